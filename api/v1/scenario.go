@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/executor"
+)
+
+// Scenario represents a scenario of the test run. Only the scenarios with an
+// externally-controlled, constant-vus or ramping-vus executor are updatable,
+// their VUs can be changed while the test is running. For the last two, the
+// VUs are a target that caps the VUs they run and the max VUs are the planned
+// ones, which can't be changed.
+type Scenario struct {
+	Name      string   `json:"-" yaml:"name"`
+	Executor  string   `json:"executor" yaml:"executor"`
+	Updatable bool     `json:"updatable" yaml:"updatable"`
+	VUs       null.Int `json:"vus" yaml:"vus"`
+	VUsMax    null.Int `json:"vus-max" yaml:"vus-max"`
+}
+
+// NewScenario returns the representation of the scenario run by the given executor.
+func NewScenario(e lib.Executor) Scenario {
+	config := e.GetConfig()
+	scenario := Scenario{
+		Name:     config.GetName(),
+		Executor: config.GetType(),
+	}
+	switch e := e.(type) {
+	case *executor.ExternallyControlled:
+		params := e.GetCurrentConfig().ExternallyControlledConfigParams
+		scenario.Updatable = true
+		scenario.VUs = params.VUs
+		scenario.VUsMax = params.MaxVUs
+	case lib.VUsTargetExecutor:
+		target, maxVUs := e.GetVUsTarget()
+		scenario.Updatable = true
+		scenario.VUs = null.IntFrom(target)
+		scenario.VUsMax = null.IntFrom(maxVUs)
+	}
+	return scenario
+}
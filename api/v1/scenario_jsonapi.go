@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"go.k6.io/k6/lib"
+)
+
+// ScenariosJSONAPI is JSON API envelop for scenarios
+type ScenariosJSONAPI struct {
+	Data []scenarioData `json:"data"`
+}
+
+// ScenarioJSONAPI is JSON API envelop for a single scenario
+type ScenarioJSONAPI struct {
+	Data scenarioData `json:"data"`
+}
+
+type scenarioData struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Attributes Scenario `json:"attributes"`
+}
+
+// NewScenarioJSONAPI creates the JSON API scenario envelop
+func NewScenarioJSONAPI(s Scenario) ScenarioJSONAPI {
+	return ScenarioJSONAPI{
+		Data: newScenarioData(s),
+	}
+}
+
+func newScenariosJSONAPI(executors []lib.Executor) ScenariosJSONAPI {
+	scenarios := make([]scenarioData, 0, len(executors))
+	for _, e := range executors {
+		scenarios = append(scenarios, newScenarioData(NewScenario(e)))
+	}
+	return ScenariosJSONAPI{
+		Data: scenarios,
+	}
+}
+
+func newScenarioData(s Scenario) scenarioData {
+	return scenarioData{
+		Type:       "scenarios",
+		ID:         s.Name,
+		Attributes: s,
+	}
+}
+
+// Scenario extract the v1.Scenario from the JSON API envelop
+func (s ScenarioJSONAPI) Scenario() Scenario {
+	scenario := s.Data.Attributes
+	scenario.Name = s.Data.ID
+	return scenario
+}
+
+// Scenarios extract the []v1.Scenario from the JSON API envelop
+func (s ScenariosJSONAPI) Scenarios() []Scenario {
+	list := make([]Scenario, 0, len(s.Data))
+	for _, scenario := range s.Data {
+		sc := scenario.Attributes
+		sc.Name = scenario.ID
+		list = append(list, sc)
+	}
+	return list
+}
@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/executor"
+)
+
+func handleGetScenarios(cs *ControlSurface, rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	data, err := json.Marshal(newScenariosJSONAPI(cs.Scheduler.GetExecutors()))
+	if err != nil {
+		apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = rw.Write(data)
+}
+
+func getExecutorByScenarioName(cs *ControlSurface, name string) lib.Executor {
+	for _, e := range cs.Scheduler.GetExecutors() {
+		if e.GetConfig().GetName() == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// updateVUsTarget updates the VUs target of the executor, its max VUs are the
+// planned ones, so they can't be changed.
+func updateVUsTarget(e lib.VUsTargetExecutor, vus, vusMax null.Int) error {
+	if _, maxVUs := e.GetVUsTarget(); vusMax.Valid && vusMax.Int64 != maxVUs {
+		return fmt.Errorf("the max VUs are the %d planned ones and can't be changed", maxVUs)
+	}
+	if !vus.Valid {
+		return nil
+	}
+	return e.SetVUsTarget(vus.Int64)
+}
+
+func handleGetScenario(cs *ControlSurface, rw http.ResponseWriter, _ *http.Request, name string) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	e := getExecutorByScenarioName(cs, name)
+	if e == nil {
+		apiError(rw, "Not Found", "No scenario with that name was found", http.StatusNotFound)
+		return
+	}
+
+	data, err := json.Marshal(NewScenarioJSONAPI(NewScenario(e)))
+	if err != nil {
+		apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = rw.Write(data)
+}
+
+func handlePatchScenario(cs *ControlSurface, rw http.ResponseWriter, r *http.Request, name string) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	e := getExecutorByScenarioName(cs, name)
+	if e == nil {
+		apiError(rw, "Not Found", "No scenario with that name was found", http.StatusNotFound)
+		return
+	}
+	if !NewScenario(e).Updatable {
+		apiError(rw, "Scenario not updatable", fmt.Sprintf(
+			"scenario %q uses the %s executor, only the VUs of externally-controlled, constant-vus and ramping-vus "+
+				"scenarios can be updated, the arrival-rate and iteration based executors can't be changed during the test",
+			name, e.GetConfig().GetType(),
+		), http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		apiError(rw, "Couldn't read request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var scenarioEnvelop ScenarioJSONAPI
+	if err = json.Unmarshal(body, &scenarioEnvelop); err != nil {
+		apiError(rw, "Invalid data", err.Error(), http.StatusBadRequest)
+		return
+	}
+	scenario := scenarioEnvelop.Scenario()
+
+	if scenario.VUs.Valid || scenario.VUsMax.Valid {
+		switch e := e.(type) {
+		case *executor.ExternallyControlled:
+			err = updateExternallyControlledVUs(r, e, scenario.VUs, scenario.VUsMax)
+		case lib.VUsTargetExecutor:
+			err = updateVUsTarget(e, scenario.VUs, scenario.VUsMax)
+		}
+		if err != nil {
+			apiError(rw, "Config update error", err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	data, err := json.Marshal(NewScenarioJSONAPI(NewScenario(e)))
+	if err != nil {
+		apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = rw.Write(data)
+}
@@ -0,0 +1,225 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/internal/lib/testutils/minirunner"
+	"go.k6.io/k6/lib"
+)
+
+func getScenariosControlSurface(t *testing.T) *ControlSurface {
+	scenarios := lib.ScenarioConfigs{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"front": {"executor": "externally-controlled", "vus": 1, "maxVUs": 10, "duration": "0"},
+		"back": {"executor": "externally-controlled", "vus": 2, "maxVUs": 5, "duration": "0"},
+		"fixed": {"executor": "constant-vus", "vus": 3, "duration": "10s"},
+		"ramp": {"executor": "ramping-vus", "stages": [{"target": 4, "duration": "10s"}]},
+		"iters": {"executor": "shared-iterations", "vus": 1, "iterations": 1},
+		"rate": {"executor": "constant-arrival-rate", "rate": 1, "duration": "10s", "preAllocatedVUs": 1, "maxVUs": 1},
+		"rates": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 1, "maxVUs": 1,
+			"stages": [{"target": 4, "duration": "10s"}]}
+	}`), &scenarios))
+
+	return getControlSurface(t, getTestRunState(t, lib.Options{Scenarios: scenarios}, &minirunner.MiniRunner{}))
+}
+
+func TestGetScenarios(t *testing.T) {
+	t.Parallel()
+
+	cs := getScenariosControlSurface(t)
+
+	rw := httptest.NewRecorder()
+	NewHandler(cs).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/v1/scenarios", nil))
+	res := rw.Result()
+	t.Cleanup(func() {
+		assert.NoError(t, res.Body.Close())
+	})
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "application/json; charset=utf-8", rw.Header().Get("Content-Type"))
+
+	var envelop ScenariosJSONAPI
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &envelop))
+	require.Len(t, envelop.Data, 7)
+	assert.Equal(t, "scenarios", envelop.Data[0].Type)
+
+	scenarios := make(map[string]Scenario)
+	for _, s := range envelop.Scenarios() {
+		scenarios[s.Name] = s
+	}
+	assert.Equal(t, map[string]Scenario{
+		"front": {Name: "front", Executor: "externally-controlled", Updatable: true, VUs: null.IntFrom(1), VUsMax: null.IntFrom(10)},
+		"back":  {Name: "back", Executor: "externally-controlled", Updatable: true, VUs: null.IntFrom(2), VUsMax: null.IntFrom(5)},
+		"fixed": {Name: "fixed", Executor: "constant-vus", Updatable: true, VUs: null.IntFrom(3), VUsMax: null.IntFrom(3)},
+		"ramp":  {Name: "ramp", Executor: "ramping-vus", Updatable: true, VUs: null.IntFrom(4), VUsMax: null.IntFrom(4)},
+		"iters": {Name: "iters", Executor: "shared-iterations"},
+		"rate":  {Name: "rate", Executor: "constant-arrival-rate"},
+		"rates": {Name: "rates", Executor: "ramping-arrival-rate"},
+	}, scenarios)
+}
+
+func TestGetScenario(t *testing.T) {
+	t.Parallel()
+
+	cs := getScenariosControlSurface(t)
+
+	t.Run("found", func(t *testing.T) {
+		t.Parallel()
+
+		rw := httptest.NewRecorder()
+		NewHandler(cs).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/v1/scenarios/back", nil))
+		require.Equal(t, http.StatusOK, rw.Result().StatusCode) //nolint:bodyclose
+
+		var envelop ScenarioJSONAPI
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &envelop))
+		assert.Equal(t, Scenario{
+			Name: "back", Executor: "externally-controlled", Updatable: true,
+			VUs: null.IntFrom(2), VUsMax: null.IntFrom(5),
+		}, envelop.Scenario())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		rw := httptest.NewRecorder()
+		NewHandler(cs).ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/v1/scenarios/unknown", nil))
+		assert.Equal(t, http.StatusNotFound, rw.Result().StatusCode) //nolint:bodyclose
+	})
+}
+
+func TestPatchScenario(t *testing.T) {
+	t.Parallel()
+
+	testData := map[string]struct {
+		scenario           string
+		payload            string
+		expectedStatusCode int
+		expectedError      string
+		expected           Scenario
+	}{
+		"vus": {
+			scenario:           "front",
+			payload:            `{"data":{"type":"scenarios","id":"front","attributes":{"vus":7,"vus-max":null}}}`,
+			expectedStatusCode: http.StatusOK,
+			expected: Scenario{
+				Name: "front", Executor: "externally-controlled", Updatable: true,
+				VUs: null.IntFrom(7), VUsMax: null.IntFrom(10),
+			},
+		},
+		"vus and max vus": {
+			scenario:           "back",
+			payload:            `{"data":{"type":"scenarios","id":"back","attributes":{"vus":8,"vus-max":8}}}`,
+			expectedStatusCode: http.StatusOK,
+			expected: Scenario{
+				Name: "back", Executor: "externally-controlled", Updatable: true,
+				VUs: null.IntFrom(8), VUsMax: null.IntFrom(8),
+			},
+		},
+		"too many vus": {
+			scenario:           "back",
+			payload:            `{"data":{"type":"scenarios","id":"back","attributes":{"vus":8}}}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "invalid configuration supplied",
+		},
+		"max vus below initial": {
+			scenario:           "front",
+			payload:            `{"data":{"type":"scenarios","id":"front","attributes":{"vus-max":5}}}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "cannot be lower than the starting number of max VUs (10)",
+		},
+		"constant vus target": {
+			scenario:           "fixed",
+			payload:            `{"data":{"type":"scenarios","id":"fixed","attributes":{"vus":2}}}`,
+			expectedStatusCode: http.StatusOK,
+			expected: Scenario{
+				Name: "fixed", Executor: "constant-vus", Updatable: true,
+				VUs: null.IntFrom(2), VUsMax: null.IntFrom(3),
+			},
+		},
+		"ramping vus target": {
+			scenario:           "ramp",
+			payload:            `{"data":{"type":"scenarios","id":"ramp","attributes":{"vus":0,"vus-max":4}}}`,
+			expectedStatusCode: http.StatusOK,
+			expected: Scenario{
+				Name: "ramp", Executor: "ramping-vus", Updatable: true,
+				VUs: null.IntFrom(0), VUsMax: null.IntFrom(4),
+			},
+		},
+		"vus target above planned": {
+			scenario:           "fixed",
+			payload:            `{"data":{"type":"scenarios","id":"fixed","attributes":{"vus":4}}}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "the VUs target should be between 0 and the 3 planned VUs, got 4",
+		},
+		"planned max vus": {
+			scenario:           "ramp",
+			payload:            `{"data":{"type":"scenarios","id":"ramp","attributes":{"vus-max":8}}}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "the max VUs are the 4 planned ones and can't be changed",
+		},
+		"not updatable": {
+			scenario:           "iters",
+			payload:            `{"data":{"type":"scenarios","id":"iters","attributes":{"vus":2}}}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      `scenario \"iters\" uses the shared-iterations executor`,
+		},
+		"constant arrival rate": {
+			scenario:           "rate",
+			payload:            `{"data":{"type":"scenarios","id":"rate","attributes":{"vus":2}}}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      `scenario \"rate\" uses the constant-arrival-rate executor`,
+		},
+		"ramping arrival rate without vus": {
+			scenario:           "rates",
+			payload:            `{"data":{"type":"scenarios","id":"rates","attributes":{}}}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "the arrival-rate and iteration based executors can't be changed during the test",
+		},
+		"unknown": {
+			scenario:           "unknown",
+			payload:            `{"data":{"type":"scenarios","id":"unknown","attributes":{"vus":2}}}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		"invalid data": {
+			scenario:           "front",
+			payload:            `{"data":`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      "Invalid data",
+		},
+	}
+
+	for name, tc := range testData {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cs := getScenariosControlSurface(t)
+
+			rw := httptest.NewRecorder()
+			NewHandler(cs).ServeHTTP(rw, httptest.NewRequest(
+				http.MethodPatch, "/v1/scenarios/"+tc.scenario, bytes.NewReader([]byte(tc.payload))))
+			res := rw.Result()
+			t.Cleanup(func() {
+				assert.NoError(t, res.Body.Close())
+			})
+			require.Equal(t, tc.expectedStatusCode, res.StatusCode, rw.Body.String())
+			if tc.expectedError != "" {
+				assert.Contains(t, rw.Body.String(), tc.expectedError)
+			}
+			if tc.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			var envelop ScenarioJSONAPI
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &envelop))
+			assert.Equal(t, tc.expected, envelop.Scenario())
+			assert.Equal(t, tc.expected, NewScenario(getExecutorByScenarioName(cs, tc.scenario)))
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 
+	"gopkg.in/guregu/null.v3"
+
 	"go.k6.io/k6/errext"
 	"go.k6.io/k6/errext/exitcodes"
 	"go.k6.io/k6/internal/execution"
@@ -34,6 +36,19 @@ func getFirstExternallyControlledExecutor(execScheduler *execution.Scheduler) (*
 	return nil, errors.New("an externally-controlled executor needs to be configured for live configuration updates")
 }
 
+// updateExternallyControlledVUs updates the VUs and max VUs of the executor,
+// if they are valid.
+func updateExternallyControlledVUs(r *http.Request, mex *executor.ExternallyControlled, vus, vusMax null.Int) error {
+	newConfig := mex.GetCurrentConfig().ExternallyControlledConfigParams
+	if vusMax.Valid {
+		newConfig.MaxVUs = vusMax
+	}
+	if vus.Valid {
+		newConfig.VUs = vus
+	}
+	return mex.UpdateConfig(r.Context(), newConfig)
+}
+
 func handlePatchStatus(cs *ControlSurface, rw http.ResponseWriter, r *http.Request) {
 	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
 
@@ -76,14 +91,7 @@ func handlePatchStatus(cs *ControlSurface, rw http.ResponseWriter, r *http.Reque
 				apiError(rw, "Execution config error", updateErr.Error(), http.StatusInternalServerError)
 				return
 			}
-			newConfig := executor.GetCurrentConfig().ExternallyControlledConfigParams
-			if status.VUsMax.Valid {
-				newConfig.MaxVUs = status.VUsMax
-			}
-			if status.VUs.Valid {
-				newConfig.VUs = status.VUs
-			}
-			if updateErr := executor.UpdateConfig(r.Context(), newConfig); updateErr != nil {
+			if updateErr := updateExternallyControlledVUs(r, executor, status.VUs, status.VUsMax); updateErr != nil {
 				apiError(rw, "Config update error", updateErr.Error(), http.StatusBadRequest)
 				return
 			}
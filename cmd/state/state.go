@@ -172,6 +172,7 @@ func NewGlobalState(ctx context.Context) *GlobalState {
 // GlobalFlags contains global config values that apply for all k6 sub-commands.
 type GlobalFlags struct {
 	ConfigFilePath   string
+	ConfigProfile    string
 	Quiet            bool
 	NoColor          bool
 	Address          string
@@ -210,6 +211,9 @@ func getFlags(defaultFlags GlobalFlags, env map[string]string, args []string) Gl
 	if val, ok := env["K6_CONFIG"]; ok {
 		result.ConfigFilePath = val
 	}
+	if val, ok := env["K6_PROFILE"]; ok {
+		result.ConfigProfile = val
+	}
 	if val, ok := env["K6_LOG_OUTPUT"]; ok {
 		result.LogOutput = val
 	}
@@ -54,4 +54,33 @@ const (
 
 	// MarkedAsFailed indicates that the test was marked as failed.
 	MarkedAsFailed ExitCode = 110
+
+	// RegressionsFound indicates that `k6 compare` found regressions between
+	// the compared test runs.
+	RegressionsFound ExitCode = 111
+
+	// SetupFailed indicates that an exception was thrown in the setup() function.
+	SetupFailed ExitCode = 112
+
+	// TeardownFailed indicates that an exception was thrown in the teardown() function.
+	TeardownFailed ExitCode = 113
+
+	// OutputFailed indicates that some output failed to flush its metrics at the end of the test.
+	OutputFailed ExitCode = 114
+
+	// ErrorBudgetExhausted indicates that the test run had more failures than its errorBudget tolerates.
+	ErrorBudgetExhausted ExitCode = 115
 )
+
+// The range of exit codes that k6 reserves for its own use. The codes above
+// are all in it, and the new ones should be added to it too, so the scripts
+// can pick their own codes without clashing with the k6 ones.
+const (
+	ReservedMin ExitCode = 97
+	ReservedMax ExitCode = 127
+)
+
+// IsReserved returns whether the exit code is in the range reserved by k6.
+func IsReserved(code ExitCode) bool {
+	return code >= ReservedMin && code <= ReservedMax
+}
@@ -0,0 +1,23 @@
+package exitcodes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReserved(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range []ExitCode{
+		CloudTestRunFailed, CloudFailedToGetProgress, ThresholdsHaveFailed, SetupTimeout,
+		TeardownTimeout, GenericTimeout, ScriptStoppedFromRESTAPI, InvalidConfig, ExternalAbort,
+		CannotStartRESTAPI, ScriptException, ScriptAborted, GoPanic, MarkedAsFailed,
+		RegressionsFound, SetupFailed, TeardownFailed, OutputFailed, ErrorBudgetExhausted,
+	} {
+		assert.True(t, IsReserved(code), code)
+	}
+	assert.False(t, IsReserved(1))
+	assert.False(t, IsReserved(96))
+	assert.False(t, IsReserved(128))
+}
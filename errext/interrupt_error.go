@@ -9,6 +9,12 @@ import (
 // InterruptError is an error that halts engine execution
 type InterruptError struct {
 	Reason string
+	// Code is the exit code of the k6 process, ScriptAborted is used if it's zero.
+	Code exitcodes.ExitCode
+	// Message is the message given by the script, without any stack trace.
+	Message string
+	// Tags are details about the interruption given by the script.
+	Tags map[string]string
 }
 
 var _ interface {
@@ -23,6 +29,9 @@ func (i *InterruptError) Error() string {
 
 // ExitCode returns the status code used when the k6 process exits.
 func (i *InterruptError) ExitCode() exitcodes.ExitCode {
+	if i.Code != 0 {
+		return i.Code
+	}
 	return exitcodes.ScriptAborted
 }
 
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/metrics"
+	"go.k6.io/k6/output"
+)
+
+const (
+	scrapeMetricPrefix = "k6_"
+	scrapeFlushRate    = 50 * time.Millisecond
+	// scrapeTrendWindow is the window of time for which the exact values of
+	// the trends are kept, the older ones are in a sketch of bounded size.
+	scrapeTrendWindow = time.Minute
+)
+
+// scrapeQuantiles are the quantiles of the trends exposed in their summaries.
+var scrapeQuantiles = []float64{0.5, 0.9, 0.95, 0.99} //nolint:gochecknoglobals
+
+var (
+	_ output.Output        = &ScrapeOutput{}
+	_ prometheus.Collector = &ScrapeOutput{}
+)
+
+// ScrapeConfig selects what is exposed on the Prometheus scrape endpoint.
+type ScrapeConfig struct {
+	// Metrics are the names of the metrics to expose, all of them if empty.
+	Metrics []string
+	// Tags are the tags to keep as labels, all of them if empty.
+	Tags []string
+}
+
+// ScrapeOutput is an output that aggregates the samples of the test run by
+// metric and tags, so they can be scraped in the Prometheus exposition format
+// from the /metrics endpoint of the REST API, along with the metrics of the
+// k6 process itself.
+type ScrapeOutput struct {
+	output.SampleBuffer
+
+	config          ScrapeConfig
+	address         string
+	logger          logrus.FieldLogger
+	periodicFlusher *output.PeriodicFlusher
+
+	mu     sync.Mutex
+	series map[string]*scrapeMetric
+}
+
+// scrapeMetric is a metric with the labels seen in its samples so far and its
+// series. The series that don't have some of the labels expose them empty, as
+// all the series of a metric must have the same labels.
+type scrapeMetric struct {
+	metric *metrics.Metric
+	labels []string
+	series map[string]*scrapeSeries
+}
+
+type scrapeSeries struct {
+	labels map[string]string
+	sink   metrics.Sink
+}
+
+// NewScrapeOutput returns the output for the Prometheus scrape endpoint of the
+// REST API listening on the given address.
+func NewScrapeOutput(config ScrapeConfig, address string, logger logrus.FieldLogger) *ScrapeOutput {
+	return &ScrapeOutput{
+		config:  config,
+		address: address,
+		logger:  logger.WithField("component", "prometheus-scrape"),
+		series:  make(map[string]*scrapeMetric),
+	}
+}
+
+// Description returns a human-readable description of the output.
+func (so *ScrapeOutput) Description() string {
+	return "prometheus scrape (http://" + so.address + "/metrics)"
+}
+
+// Start starts aggregating the buffered samples.
+func (so *ScrapeOutput) Start() error {
+	pf, err := output.NewPeriodicFlusher(scrapeFlushRate, so.flushMetrics)
+	if err != nil {
+		return err
+	}
+	so.periodicFlusher = pf
+	return nil
+}
+
+// Stop aggregates the remaining samples, they can still be scraped until the
+// REST API is shut down.
+func (so *ScrapeOutput) Stop() error {
+	so.periodicFlusher.Stop()
+	return nil
+}
+
+func (so *ScrapeOutput) flushMetrics() {
+	sampleContainers := so.GetBufferedSamples()
+	if len(sampleContainers) == 0 {
+		return
+	}
+
+	so.mu.Lock()
+	defer so.mu.Unlock()
+	for _, sc := range sampleContainers {
+		for _, sample := range sc.GetSamples() {
+			so.add(sample)
+		}
+	}
+}
+
+func (so *ScrapeOutput) add(sample metrics.Sample) {
+	name := sample.Metric.Name
+	if len(so.config.Metrics) > 0 && !slices.Contains(so.config.Metrics, name) {
+		return
+	}
+	sm, ok := so.series[name]
+	if !ok {
+		sm = &scrapeMetric{metric: sample.Metric, series: make(map[string]*scrapeSeries)}
+		so.series[name] = sm
+	}
+
+	labels := make(map[string]string)
+	for key, value := range sample.Tags.Map() {
+		if value == "" || (len(so.config.Tags) > 0 && !slices.Contains(so.config.Tags, key)) {
+			continue
+		}
+		label := sanitizeLabelName(key)
+		// the summaries of the trends have their own quantile label
+		if label == "quantile" && sample.Metric.Type == metrics.Trend {
+			continue
+		}
+		if _, exists := labels[label]; exists {
+			continue // the sanitized keys can clash, only one of them is kept
+		}
+		labels[label] = value
+		if !slices.Contains(sm.labels, label) {
+			sm.labels = append(sm.labels, label)
+			slices.Sort(sm.labels)
+		}
+	}
+
+	id := seriesID(labels)
+	series, ok := sm.series[id]
+	if !ok {
+		sink := metrics.NewSink(sample.Metric.Type)
+		if trendSink, isTrend := sink.(*metrics.TrendSink); isTrend {
+			trendSink.SetExactWindow(scrapeTrendWindow)
+		}
+		series = &scrapeSeries{labels: labels, sink: sink}
+		sm.series[id] = series
+	}
+	series.sink.Add(sample)
+}
+
+// seriesID returns a key that identifies the series with the given labels.
+func seriesID(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteByte(0)
+		b.WriteString(labels[key])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// Describe doesn't send any descriptions, since the metrics and their labels
+// are only known once their samples are seen, that makes the collector an
+// unchecked one.
+func (so *ScrapeOutput) Describe(chan<- *prometheus.Desc) {}
+
+// Collect sends the current values of all the series.
+func (so *ScrapeOutput) Collect(ch chan<- prometheus.Metric) {
+	so.mu.Lock()
+	defer so.mu.Unlock()
+
+	for _, sm := range so.series {
+		for _, series := range sm.series {
+			values := make([]string, len(sm.labels))
+			for i, label := range sm.labels {
+				values[i] = series.labels[label]
+			}
+			m, err := sm.collect(series.sink, values)
+			if err != nil {
+				so.logger.WithError(err).Debugf("Couldn't collect the %s metric", sm.metric.Name)
+				continue
+			}
+			ch <- m
+		}
+	}
+}
+
+func (sm *scrapeMetric) collect(sink metrics.Sink, values []string) (prometheus.Metric, error) {
+	name := scrapeMetricPrefix + sanitizeLabelName(sm.metric.Name)
+	help := "k6 " + sm.metric.Type.String() + " metric " + sm.metric.Name
+	if unit := sm.metric.Contains; unit != metrics.Default {
+		help += " (" + unit.String() + ")"
+	}
+
+	switch sink := sink.(type) {
+	case *metrics.CounterSink:
+		desc := prometheus.NewDesc(name+"_total", help, sm.labels, nil)
+		return prometheus.NewConstMetric(desc, prometheus.CounterValue, sink.Value, values...)
+	case *metrics.GaugeSink:
+		desc := prometheus.NewDesc(name, help, sm.labels, nil)
+		return prometheus.NewConstMetric(desc, prometheus.GaugeValue, sink.Value, values...)
+	case *metrics.RateSink:
+		var rate float64
+		if sink.Total > 0 {
+			rate = float64(sink.Trues) / float64(sink.Total)
+		}
+		desc := prometheus.NewDesc(name+"_rate", help, sm.labels, nil)
+		return prometheus.NewConstMetric(desc, prometheus.GaugeValue, rate, values...)
+	case *metrics.TrendSink:
+		quantiles := make(map[float64]float64, len(scrapeQuantiles))
+		for _, q := range scrapeQuantiles {
+			quantiles[q] = sink.P(q)
+		}
+		desc := prometheus.NewDesc(name, help, sm.labels, nil)
+		return prometheus.NewConstSummary(desc, sink.Count(), sink.Total(), quantiles, values...)
+	default:
+		return nil, fmt.Errorf("unsupported sink %T", sink)
+	}
+}
+
+// sanitizeLabelName replaces the characters that aren't allowed in the metric
+// and label names with underscores.
+func sanitizeLabelName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		isLetter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+		if !isLetter && (i == 0 || c < '0' || c > '9') {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/lib/testutils"
+	"go.k6.io/k6/metrics"
+)
+
+func TestScrapeOutput(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	reqs := registry.MustNewMetric("http_reqs", metrics.Counter)
+	duration := registry.MustNewMetric("http_req_duration", metrics.Trend, metrics.Time)
+	checks := registry.MustNewMetric("checks", metrics.Rate)
+	vus := registry.MustNewMetric("vus", metrics.Gauge)
+
+	sample := func(m *metrics.Metric, value float64, tags map[string]string) metrics.Sample {
+		return metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: m, Tags: registry.RootTagSet().WithTagsFromMap(tags)},
+			Time:       time.Now(),
+			Value:      value,
+		}
+	}
+	get := map[string]string{"method": "GET", "status": "200", "url": "http://example.com/1"}
+	failed := map[string]string{"method": "GET", "status": "0", "url": "http://example.com/2", "error_code": "1000"}
+
+	so := NewScrapeOutput(ScrapeConfig{
+		Metrics: []string{"http_reqs", "http_req_duration", "checks"},
+		Tags:    []string{"method", "status", "error_code", "check"},
+	}, "localhost:6565", testutils.NewLogger(t))
+	require.NoError(t, so.Start())
+	so.AddMetricSamples([]metrics.SampleContainer{metrics.Samples{
+		sample(reqs, 1, get),
+		sample(reqs, 1, get),
+		sample(reqs, 1, failed),
+		sample(duration, 100, get),
+		sample(duration, 200, get),
+		sample(checks, 1, map[string]string{"check": "ok"}),
+		sample(checks, 0, map[string]string{"check": "ok"}),
+		sample(vus, 10, nil),
+	}})
+	require.NoError(t, so.Stop())
+
+	mux := http.NewServeMux()
+	injectMetricsHandler(mux, so)
+	rw := httptest.NewRecorder()
+	mux.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	res := rw.Result()
+	defer func() { require.NoError(t, res.Body.Close()) }()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	text := string(body)
+	// all the series of a metric have the same labels, the missing ones empty
+	assert.Contains(t, text, `k6_http_reqs_total{error_code="",method="GET",status="200"} 2`)
+	assert.Contains(t, text, `k6_http_reqs_total{error_code="1000",method="GET",status="0"} 1`)
+	assert.Contains(t, text, `k6_http_req_duration_sum{method="GET",status="200"} 300`)
+	assert.Contains(t, text, `k6_http_req_duration_count{method="GET",status="200"} 2`)
+	assert.Contains(t, text, `k6_http_req_duration{method="GET",status="200",quantile="0.5"} 150`)
+	assert.Contains(t, text, `k6_checks_rate{check="ok"} 0.5`)
+	assert.Contains(t, text, "# HELP k6_http_req_duration k6 trend metric http_req_duration (time)")
+	assert.NotContains(t, text, "k6_vus")
+	assert.NotContains(t, text, "example.com")
+	// the metrics of the k6 process are still there
+	assert.Contains(t, text, "go_goroutines")
+}
+
+func TestScrapeOutputDescription(t *testing.T) {
+	t.Parallel()
+
+	so := NewScrapeOutput(ScrapeConfig{}, "localhost:6565", testutils.NewLogger(t))
+	assert.Equal(t, "prometheus scrape (http://localhost:6565/metrics)", so.Description())
+}
@@ -10,6 +10,7 @@ import (
 	_ "net/http/pprof" //nolint:gosec // Register pprof handlers
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 
@@ -20,17 +21,34 @@ import (
 	"go.k6.io/k6/metrics"
 )
 
-func newHandler(cs *v1.ControlSurface, profilingEnabled bool) http.Handler {
+func newHandler(cs *v1.ControlSurface, profilingEnabled bool, scrape *ScrapeOutput) http.Handler {
 	mux := http.NewServeMux()
 	mux.Handle("/v1/", v1.NewHandler(cs))
 	mux.Handle("/ping", handlePing(cs.RunState.Logger))
 	mux.Handle("/", handlePing(cs.RunState.Logger))
 
 	injectProfilerHandler(mux, profilingEnabled)
+	injectMetricsHandler(mux, scrape)
 
 	return mux
 }
 
+// injectMetricsHandler exposes the metrics of the k6 process in the Prometheus
+// exposition format and, with the scrape output, the ones of the test run.
+func injectMetricsHandler(mux *http.ServeMux, scrape *ScrapeOutput) {
+	if scrape == nil {
+		mux.Handle("/metrics", promhttp.Handler())
+		return
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(scrape)
+	mux.Handle("/metrics", promhttp.HandlerFor(
+		prometheus.Gatherers{prometheus.DefaultGatherer, registry},
+		promhttp.HandlerOpts{ErrorLog: scrape.logger, ErrorHandling: promhttp.ContinueOnError},
+	))
+}
+
 func injectProfilerHandler(mux *http.ServeMux, profilingEnabled bool) {
 	var handler http.Handler
 
@@ -45,10 +63,11 @@ func injectProfilerHandler(mux *http.ServeMux, profilingEnabled bool) {
 
 	mux.Handle("/debug/pprof/", handler)
 	mux.Handle("/debug/vars/", expvar.Handler())
-	mux.Handle("/metrics", promhttp.Handler())
 }
 
-// GetServer returns a http.Server instance that can serve k6's REST API.
+// GetServer returns a http.Server instance that can serve k6's REST API. The
+// scrape output is optional, without it only the metrics of the k6 process
+// are exposed on /metrics.
 func GetServer(
 	runCtx context.Context,
 	addr string,
@@ -57,6 +76,7 @@ func GetServer(
 	samples chan metrics.SampleContainer,
 	me *engine.MetricsEngine,
 	es *execution.Scheduler,
+	scrape *ScrapeOutput,
 ) *http.Server {
 	// TODO: reduce the control surface as much as possible? For example, if
 	// we refactor the Runner API, we won't need to send the Samples channel.
@@ -68,7 +88,7 @@ func GetServer(
 		RunState:      runState,
 	}
 
-	mux := withLoggingHandler(runState.Logger, newHandler(cs, profilingEnabled))
+	mux := withLoggingHandler(runState.Logger, newHandler(cs, profilingEnabled, scrape))
 	return &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
 }
 
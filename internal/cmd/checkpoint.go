@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/executor"
+	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
+	"go.k6.io/k6/output"
+)
+
+const checkpointVersion = 1
+
+// checkpoint is the progress of a test run, saved with --checkpoint, from
+// which an interrupted test can be continued with --resume. Only the elapsed
+// time and the completed iterations per scenario are saved, not the state of
+// the random generators or how the iterations were split between the VUs.
+type checkpoint struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	// ScriptHash and ScenariosHash identify the test the checkpoint is for, the
+	// hash of the scenarios is always the one of the original, not resumed, run.
+	ScriptHash    string         `json:"scriptHash"`
+	ScenariosHash string         `json:"scenariosHash"`
+	Elapsed       types.Duration `json:"elapsed"`
+	// Iterations is how many iterations every scenario has completed.
+	Iterations map[string]uint64 `json:"iterations"`
+}
+
+func hashSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func newCheckpoint(script []byte, scenarios lib.ScenarioConfigs) (*checkpoint, error) {
+	scenariosJSON, err := json.Marshal(scenarios)
+	if err != nil {
+		return nil, err
+	}
+	return &checkpoint{
+		Version:       checkpointVersion,
+		ScriptHash:    hashSHA256(script),
+		ScenariosHash: hashSHA256(scenariosJSON),
+		Iterations:    make(map[string]uint64),
+	}, nil
+}
+
+func readCheckpoint(fs fsext.Fs, path string) (*checkpoint, error) {
+	data, err := fsext.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the checkpoint: %w", err)
+	}
+	cp := &checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("couldn't parse the checkpoint %q: %w", path, err)
+	}
+	if cp.Version != checkpointVersion {
+		return nil, fmt.Errorf("the checkpoint %q has unsupported version %d", path, cp.Version)
+	}
+	if cp.Iterations == nil {
+		cp.Iterations = make(map[string]uint64)
+	}
+	return cp, nil
+}
+
+// resumeScenarios checks that the checkpoint is for the same test and returns
+// the scenarios with only the part of them that remains to be run, and the
+// names of the scenarios that had already finished.
+func (cp *checkpoint) resumeScenarios(
+	current *checkpoint, scenarios lib.ScenarioConfigs,
+) (lib.ScenarioConfigs, []string, error) {
+	if cp.ScriptHash != current.ScriptHash {
+		return nil, nil, errors.New("the checkpoint was saved for a different script")
+	}
+	if cp.ScenariosHash != current.ScenariosHash {
+		return nil, nil, errors.New("the checkpoint was saved for a test with different scenarios")
+	}
+
+	remaining := make(lib.ScenarioConfigs, len(scenarios))
+	var finished []string
+	for name, config := range scenarios {
+		resumed, ok, err := executor.ResumeConfig(config, time.Duration(cp.Elapsed), cp.Iterations[name])
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't resume scenario %q: %w", name, err)
+		}
+		if !ok {
+			finished = append(finished, name)
+			continue
+		}
+		remaining[name] = resumed
+	}
+	if len(remaining) == 0 {
+		return nil, nil, errors.New("all scenarios of the checkpoint have already finished")
+	}
+	sort.Strings(finished)
+	return remaining, finished, nil
+}
+
+// prepareCheckpoint returns the checkpoint that the progress of the test run
+// is added to, or nil if checkpoints aren't enabled. When resuming, the
+// scenarios of the test are replaced by what remains of them.
+func (c *cmdRun) prepareCheckpoint(test *loadedAndConfiguredTest) (*checkpoint, error) {
+	if c.checkpointPath == "" && c.resumePath == "" {
+		return nil, nil //nolint:nilnil
+	}
+	if c.checkpointInterval <= 0 {
+		return nil, fmt.Errorf("the checkpoint interval should be positive but was %s", c.checkpointInterval)
+	}
+
+	current, err := newCheckpoint(test.source.Data, test.derivedConfig.Scenarios)
+	if err != nil {
+		return nil, err
+	}
+	if c.resumePath == "" {
+		return current, nil
+	}
+
+	saved, err := readCheckpoint(c.gs.FS, c.resumePath)
+	if err != nil {
+		return nil, err
+	}
+	scenarios, finished, err := saved.resumeScenarios(current, test.derivedConfig.Scenarios)
+	if err != nil {
+		return nil, err
+	}
+	test.derivedConfig.Scenarios = scenarios
+
+	logger := c.gs.Logger.WithField("checkpoint", c.resumePath)
+	logger.Info(describeResume(saved))
+	if len(finished) > 0 {
+		logger.Infof("Scenarios %s had already finished and won't be run", strings.Join(finished, ", "))
+	}
+
+	current.Elapsed, current.Iterations = saved.Elapsed, saved.Iterations
+	if c.checkpointPath == "" {
+		c.checkpointPath = c.resumePath
+	}
+	return current, nil
+}
+
+// checkpointWriter is an output that counts the completed iterations of every
+// scenario and periodically saves the progress of the test to the checkpoint file.
+type checkpointWriter struct {
+	fs       fsext.Fs
+	path     string
+	interval time.Duration
+	logger   logrus.FieldLogger
+	elapsed  func() time.Duration
+
+	mu   sync.Mutex
+	base checkpoint
+	cp   checkpoint
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+var _ output.Output = &checkpointWriter{}
+
+func newCheckpointWriter(
+	fs fsext.Fs, path string, interval time.Duration, base *checkpoint, elapsed func() time.Duration,
+	logger logrus.FieldLogger,
+) *checkpointWriter {
+	cw := &checkpointWriter{
+		fs:       fs,
+		path:     path,
+		interval: interval,
+		logger:   logger.WithField("checkpoint", path),
+		elapsed:  elapsed,
+		base:     *base,
+		cp:       *base,
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	cw.cp.Iterations = make(map[string]uint64, len(base.Iterations))
+	for name, iters := range base.Iterations {
+		cw.cp.Iterations[name] = iters
+	}
+	return cw
+}
+
+func (cw *checkpointWriter) Description() string {
+	return fmt.Sprintf("checkpoint (%s)", cw.path)
+}
+
+func (cw *checkpointWriter) Start() error {
+	go func() {
+		defer close(cw.stopped)
+		ticker := time.NewTicker(cw.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := cw.save(); err != nil {
+					cw.logger.WithError(err).Warn("Couldn't save the checkpoint")
+				}
+			case <-cw.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (cw *checkpointWriter) AddMetricSamples(samples []metrics.SampleContainer) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	for _, sc := range samples {
+		for _, s := range sc.GetSamples() {
+			if s.Metric.Name != metrics.IterationsName {
+				continue
+			}
+			if scenario, ok := s.Tags.Get("scenario"); ok {
+				cw.cp.Iterations[scenario] += uint64(s.Value)
+			}
+		}
+	}
+}
+
+func (cw *checkpointWriter) Stop() error {
+	close(cw.stop)
+	<-cw.stopped
+	return cw.save()
+}
+
+// save writes the checkpoint to a temporary file first and then renames it,
+// so an interruption while saving doesn't corrupt the previous checkpoint.
+func (cw *checkpointWriter) save() error {
+	cw.mu.Lock()
+	cw.cp.Time = time.Now()
+	cw.cp.Elapsed = cw.base.Elapsed + types.Duration(cw.elapsed())
+	data, err := json.MarshalIndent(cw.cp, "", "  ")
+	cw.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp := cw.path + ".tmp"
+	if err := fsext.WriteFile(cw.fs, tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return cw.fs.Rename(tmp, cw.path)
+}
+
+func describeResume(cp *checkpoint) string {
+	names := make([]string, 0, len(cp.Iterations))
+	for name := range cp.Iterations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %d", name, cp.Iterations[name]))
+	}
+	return fmt.Sprintf("Resuming the test after %s, completed iterations %s",
+		time.Duration(cp.Elapsed), strings.Join(parts, ", "))
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/executor"
+	"go.k6.io/k6/lib/types"
+)
+
+func TestCheckpointResumeScenarios(t *testing.T) {
+	t.Parallel()
+
+	constant := executor.NewConstantVUsConfig("constant")
+	constant.Duration = types.NullDurationFrom(time.Minute)
+	shared := executor.NewSharedIterationsConfig("shared")
+	shared.Iterations = null.IntFrom(10)
+	scenarios := lib.ScenarioConfigs{"constant": constant, "shared": shared}
+
+	current, err := newCheckpoint([]byte("export default function() {}"), scenarios)
+	require.NoError(t, err)
+
+	saved := *current
+	saved.Elapsed = types.Duration(20 * time.Second)
+	saved.Iterations = map[string]uint64{"shared": 10, "constant": 100}
+	remaining, finished, err := saved.resumeScenarios(current, scenarios)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shared"}, finished)
+	resumed, ok := remaining["constant"].(executor.ConstantVUsConfig)
+	require.True(t, ok)
+	assert.Equal(t, types.NullDurationFrom(40*time.Second), resumed.Duration)
+
+	saved.Elapsed = types.Duration(time.Minute)
+	_, _, err = saved.resumeScenarios(current, scenarios)
+	require.ErrorContains(t, err, "all scenarios of the checkpoint have already finished")
+
+	other, err := newCheckpoint([]byte("export default function() { sleep(1) }"), scenarios)
+	require.NoError(t, err)
+	_, _, err = saved.resumeScenarios(other, scenarios)
+	require.ErrorContains(t, err, "different script")
+
+	other, err = newCheckpoint([]byte("export default function() {}"), lib.ScenarioConfigs{"constant": constant})
+	require.NoError(t, err)
+	_, _, err = saved.resumeScenarios(other, scenarios)
+	require.ErrorContains(t, err, "different scenarios")
+}
@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// cmdCompare handles the `k6 compare` sub-command
+type cmdCompare struct {
+	gs *state.GlobalState
+
+	tolerance        float64
+	metricTolerances []string
+	higherIsBetter   []string
+	metrics          []string
+	failOnRegression bool
+	json             bool
+}
+
+// comparedValue is the comparison of a single aggregated value, like the
+// p(95) of http_req_duration, between two test runs.
+type comparedValue struct {
+	Baseline   float64  `json:"baseline"`
+	Current    float64  `json:"current"`
+	Change     *float64 `json:"change_percent"` // nil if the baseline value is zero
+	Tolerance  float64  `json:"tolerance_percent"`
+	Regression bool     `json:"regression"`
+}
+
+type comparison struct {
+	Baseline       string                               `json:"baseline"`
+	Current        string                               `json:"current"`
+	Regressions    int                                  `json:"regressions"`
+	Metrics        map[string]map[string]*comparedValue `json:"metrics"`
+	OnlyInBaseline []string                             `json:"only_in_baseline,omitempty"`
+	OnlyInCurrent  []string                             `json:"only_in_current,omitempty"`
+}
+
+func (c *cmdCompare) run(_ *cobra.Command, args []string) error {
+	tolerances, err := parseMetricTolerances(c.metricTolerances)
+	if err != nil {
+		return errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
+
+	baseline, err := c.readSummary(args[0])
+	if err != nil {
+		return err
+	}
+	current, err := c.readSummary(args[1])
+	if err != nil {
+		return err
+	}
+
+	comp := &comparison{
+		Baseline: args[0],
+		Current:  args[1],
+		Metrics:  make(map[string]map[string]*comparedValue),
+	}
+	higherIsBetter := make(map[string]bool, len(c.higherIsBetter))
+	for _, name := range c.higherIsBetter {
+		higherIsBetter[name] = true
+	}
+	onlyMetrics := make(map[string]bool, len(c.metrics))
+	for _, name := range c.metrics {
+		onlyMetrics[name] = true
+	}
+
+	for name, baseValues := range baseline {
+		if len(onlyMetrics) > 0 && !onlyMetrics[name] {
+			continue
+		}
+		curValues, ok := current[name]
+		if !ok {
+			comp.OnlyInBaseline = append(comp.OnlyInBaseline, name)
+			continue
+		}
+
+		values := make(map[string]*comparedValue)
+		for stat, base := range baseValues {
+			cur, ok := curValues[stat]
+			if !ok {
+				continue
+			}
+			tolerance := tolerances.get(name, stat, c.tolerance)
+			cv := compareValue(base, cur, tolerance, higherIsBetter[metricBaseName(name)])
+			if cv.Regression {
+				comp.Regressions++
+			}
+			values[stat] = cv
+		}
+		if len(values) > 0 {
+			comp.Metrics[name] = values
+		}
+	}
+	for name := range current {
+		if _, ok := baseline[name]; !ok && (len(onlyMetrics) == 0 || onlyMetrics[name]) {
+			comp.OnlyInCurrent = append(comp.OnlyInCurrent, name)
+		}
+	}
+	sort.Strings(comp.OnlyInBaseline)
+	sort.Strings(comp.OnlyInCurrent)
+
+	if c.json {
+		data, err := json.MarshalIndent(comp, "", "  ")
+		if err != nil {
+			return err
+		}
+		printToStdout(c.gs, string(data)+"\n")
+	} else {
+		printToStdout(c.gs, renderComparison(comp, c.tolerance, c.gs.Flags.NoColor || !c.gs.Stdout.IsTTY))
+	}
+
+	if c.failOnRegression && comp.Regressions > 0 {
+		return errext.WithExitCodeIfNone(
+			fmt.Errorf("%d regressions found between '%s' and '%s'", comp.Regressions, args[0], args[1]),
+			exitcodes.RegressionsFound,
+		)
+	}
+	return nil
+}
+
+// readSummary reads the comparable values of all metrics in a summary exported
+// with --summary-export or by handleSummary(), in both the legacy and the new
+// machine-readable formats. Gauges are skipped, since their end-of-test values
+// only reflect the last samples.
+func (c *cmdCompare) readSummary(path string) (map[string]map[string]float64, error) {
+	if !filepath.IsAbs(path) {
+		pwd, err := c.gs.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(pwd, path)
+	}
+
+	data, err := fsext.ReadFile(c.gs.FS, path)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the summary: %w", err)
+	}
+
+	var summary struct {
+		Metrics map[string]map[string]any `json:"metrics"`
+	}
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("couldn't parse the summary '%s': %w", path, err)
+	}
+	if summary.Metrics == nil {
+		return nil, fmt.Errorf("the summary '%s' doesn't have any metrics", path)
+	}
+
+	metrics := make(map[string]map[string]float64, len(summary.Metrics))
+	for name, raw := range summary.Metrics {
+		metricType, _ := raw["type"].(string)
+		if values, ok := raw["values"].(map[string]any); ok {
+			raw = values
+		} else {
+			metricType = guessLegacyMetricType(raw)
+		}
+
+		values := make(map[string]float64)
+		for stat, v := range raw {
+			f, ok := v.(float64)
+			if !ok {
+				continue
+			}
+			switch metricType {
+			case "gauge":
+				continue
+			case "rate":
+				// passes and fails are just the counts behind the rate
+				if stat != "rate" && stat != "value" {
+					continue
+				}
+				stat = "rate"
+			}
+			values[stat] = f
+		}
+		if len(values) > 0 {
+			metrics[name] = values
+		}
+	}
+
+	return metrics, nil
+}
+
+// guessLegacyMetricType figures out the type of a metric from its values in the
+// legacy summary export, which doesn't include it.
+func guessLegacyMetricType(values map[string]any) string {
+	has := func(key string) bool {
+		_, ok := values[key]
+		return ok
+	}
+	switch {
+	case has("passes") && has("fails"):
+		return "rate"
+	case has("count"):
+		return "counter"
+	case has("avg"):
+		return "trend"
+	default:
+		return "gauge"
+	}
+}
+
+func compareValue(base, cur, tolerance float64, higherIsBetter bool) *comparedValue {
+	cv := &comparedValue{Baseline: base, Current: cur, Tolerance: tolerance}
+
+	diff := cur - base
+	if higherIsBetter {
+		diff = -diff
+	}
+	if base == 0 {
+		cv.Regression = diff > 0
+		if cur == 0 {
+			cv.Change = new(float64)
+		}
+		return cv
+	}
+
+	change := (cur - base) / math.Abs(base) * 100
+	cv.Change = &change
+	cv.Regression = diff > 0 && math.Abs(change) > tolerance
+	return cv
+}
+
+// metricBaseName returns the name of the parent metric of a sub-metric.
+func metricBaseName(name string) string {
+	if i := strings.IndexByte(name, '{'); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// metricTolerances are the tolerances for specific metrics, or for specific
+// values of them, keyed by "metric" or "metric:stat".
+type metricTolerances map[string]float64
+
+func parseMetricTolerances(specs []string) (metricTolerances, error) {
+	tolerances := make(metricTolerances, len(specs))
+	for _, spec := range specs {
+		i := strings.LastIndexByte(spec, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid metric tolerance '%s', it should be in the metric[:stat]=percent format", spec)
+		}
+		tolerance, err := strconv.ParseFloat(strings.TrimSuffix(spec[i+1:], "%"), 64)
+		if err != nil || tolerance < 0 {
+			return nil, fmt.Errorf("invalid tolerance in '%s', it should be a non-negative percentage", spec)
+		}
+		tolerances[spec[:i]] = tolerance
+	}
+	return tolerances, nil
+}
+
+func (mt metricTolerances) get(name, stat string, defaultTolerance float64) float64 {
+	if t, ok := mt[name+":"+stat]; ok {
+		return t
+	}
+	if t, ok := mt[name]; ok {
+		return t
+	}
+	return defaultTolerance
+}
+
+func renderComparison(comp *comparison, tolerance float64, noColor bool) string {
+	green := getColor(noColor, color.FgGreen)
+	red := getColor(noColor, color.FgRed)
+	faint := getColor(noColor, color.Faint)
+
+	names := make([]string, 0, len(comp.Metrics))
+	for name := range comp.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "\n  █ COMPARISON %s\n\n", faint.Sprintf("%s → %s (tolerance %s%%)",
+		comp.Baseline, comp.Current, formatCompareValue(tolerance)))
+	for _, name := range names {
+		fmt.Fprintf(buf, "    %s\n", name)
+		values := comp.Metrics[name]
+		stats := make([]string, 0, len(values))
+		for stat := range values {
+			stats = append(stats, stat)
+		}
+		sort.Slice(stats, func(i, j int) bool { return lessStat(stats[i], stats[j]) })
+
+		for _, stat := range stats {
+			cv := values[stat]
+			mark, change := green.Sprint("✓"), "n/a"
+			if cv.Regression {
+				mark = red.Sprint("✗")
+			}
+			if cv.Change != nil {
+				change = fmt.Sprintf("%+.2f%%", *cv.Change)
+			}
+			if cv.Regression {
+				change = red.Sprint(change)
+			} else {
+				change = faint.Sprint(change)
+			}
+			fmt.Fprintf(buf, "    %s %s: %s → %s %s\n", mark, stat+strings.Repeat(".", max(0, 10-len(stat))),
+				formatCompareValue(cv.Baseline), formatCompareValue(cv.Current), change)
+		}
+		buf.WriteString("\n")
+	}
+
+	if len(comp.OnlyInBaseline) > 0 {
+		fmt.Fprintf(buf, "    only in baseline: %s\n", strings.Join(comp.OnlyInBaseline, ", "))
+	}
+	if len(comp.OnlyInCurrent) > 0 {
+		fmt.Fprintf(buf, "    only in current: %s\n", strings.Join(comp.OnlyInCurrent, ", "))
+	}
+
+	switch comp.Regressions {
+	case 0:
+		fmt.Fprintf(buf, "    %s\n\n", green.Sprint("no regressions found"))
+	case 1:
+		fmt.Fprintf(buf, "    %s\n\n", red.Sprint("1 regression found"))
+	default:
+		fmt.Fprintf(buf, "    %s\n\n", red.Sprintf("%d regressions found", comp.Regressions))
+	}
+
+	return buf.String()
+}
+
+// lessStat sorts the stats in the same order as the end-of-test summary, with
+// the percentiles after the rest.
+func lessStat(a, b string) bool {
+	order := map[string]int{"count": 1, "rate": 2, "avg": 3, "min": 4, "med": 5, "max": 6}
+	pa, pb := strings.HasPrefix(a, "p("), strings.HasPrefix(b, "p(")
+	switch {
+	case pa && pb:
+		fa, _ := strconv.ParseFloat(strings.TrimSuffix(a[2:], ")"), 64)
+		fb, _ := strconv.ParseFloat(strings.TrimSuffix(b[2:], ")"), 64)
+		return fa < fb
+	case pa != pb:
+		return pb
+	case order[a] != order[b]:
+		return order[a] < order[b]
+	default:
+		return a < b
+	}
+}
+
+func formatCompareValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func (c *cmdCompare) flagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.SortFlags = false
+	flags.Float64Var(&c.tolerance, "tolerance", 10,
+		"the `percent` by which a value can get worse before it's considered a regression")
+	flags.StringArrayVar(&c.metricTolerances, "metric-tolerance", nil,
+		"the tolerance for a metric or one of its values, e.g. http_req_duration:p(95)=5")
+	flags.StringSliceVar(&c.higherIsBetter, "higher-is-better", []string{"checks", "iterations", "http_reqs"},
+		"metrics for which higher values are better, instead of worse")
+	flags.StringSliceVar(&c.metrics, "metrics", nil, "only compare these metrics")
+	flags.BoolVar(&c.failOnRegression, "fail-on-regression", false, "exit with a non-zero code if regressions are found")
+	flags.BoolVar(&c.json, "json", false, "output the comparison as JSON")
+	return flags
+}
+
+func getCmdCompare(gs *state.GlobalState) *cobra.Command {
+	c := &cmdCompare{gs: gs}
+
+	exampleText := getExampleText(gs, `
+  # Compare the summaries exported by two test runs.
+  {{.}} run --summary-export=baseline.json script.js
+  {{.}} run --summary-export=current.json script.js
+  {{.}} compare baseline.json current.json
+
+  # Fail if the p(95) of http_req_duration gets more than 5% worse.
+  {{.}} compare --metrics http_req_duration --metric-tolerance 'http_req_duration:p(95)=5' \
+    --fail-on-regression baseline.json current.json`[1:])
+
+	compareCmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare the summaries of two test runs",
+		Long: `Compare the summaries of two test runs.
+
+The metric values of the summaries exported with --summary-export are compared, and the ones that
+got worse by more than the tolerance are reported as regressions.`,
+		Example: exampleText,
+		Args:    exactArgsWithMsg(2, "args should be the paths to the baseline and the current summary files"),
+		RunE:    c.run,
+	}
+
+	compareCmd.Flags().SortFlags = false
+	compareCmd.Flags().AddFlagSet(c.flagSet())
+
+	return compareCmd
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/internal/cmd/tests"
+	"go.k6.io/k6/lib/fsext"
+)
+
+const (
+	compareBaselineSummary = `{
+		"metrics": {
+			"http_req_duration": {"avg": 100, "min": 10, "med": 90, "max": 300, "p(90)": 150, "p(95)": 200},
+			"http_req_duration{expected_response:true}": {"avg": 100, "p(95)": 200},
+			"http_req_failed": {"passes": 1, "fails": 99, "value": 0.01},
+			"checks": {"passes": 100, "fails": 0, "value": 1},
+			"iterations": {"count": 1000, "rate": 100},
+			"vus": {"value": 10, "min": 1, "max": 10},
+			"old_metric": {"count": 1, "rate": 1}
+		}
+	}`
+	compareCurrentSummary = `{
+		"metrics": {
+			"http_req_duration": {"type": "trend", "contains": "time", "values": {
+				"avg": 105, "min": 10, "med": 90, "max": 400, "p(90)": 150, "p(95)": 230
+			}},
+			"http_req_duration{expected_response:true}": {"type": "trend", "values": {"avg": 100, "p(95)": 220}},
+			"http_req_failed": {"type": "rate", "values": {"passes": 1, "fails": 99, "rate": 0.01}},
+			"checks": {"type": "rate", "values": {"passes": 80, "fails": 20, "rate": 0.8}},
+			"iterations": {"type": "counter", "values": {"count": 1200, "rate": 120}},
+			"vus": {"type": "gauge", "values": {"value": 20, "min": 1, "max": 20}},
+			"new_metric": {"type": "counter", "values": {"count": 1, "rate": 1}}
+		}
+	}`
+)
+
+func newCompareTestState(t *testing.T) *tests.GlobalTestState {
+	t.Helper()
+
+	ts := tests.NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "baseline.json"), []byte(compareBaselineSummary), 0o644))
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "current.json"), []byte(compareCurrentSummary), 0o644))
+	return ts
+}
+
+func TestCompareCmd(t *testing.T) {
+	t.Parallel()
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newCompareTestState(t)
+		ts.CmdArgs = []string{"k6", "compare", "baseline.json", "current.json"}
+
+		newRootCommand(ts.GlobalState).execute()
+
+		stdout := ts.Stdout.String()
+		t.Log(stdout)
+		assert.Contains(t, stdout, "█ COMPARISON baseline.json → current.json (tolerance 10%)")
+		assert.Contains(t, stdout, "    http_req_duration\n"+
+			"    ✓ avg.......: 100 → 105 +5.00%\n"+
+			"    ✓ min.......: 10 → 10 +0.00%\n"+
+			"    ✓ med.......: 90 → 90 +0.00%\n"+
+			"    ✗ max.......: 300 → 400 +33.33%\n"+
+			"    ✓ p(90).....: 150 → 150 +0.00%\n"+
+			"    ✗ p(95).....: 200 → 230 +15.00%\n")
+		assert.Contains(t, stdout, "    checks\n    ✗ rate......: 1 → 0.8 -20.00%\n")
+		assert.Contains(t, stdout, "    iterations\n    ✓ count.....: 1000 → 1200 +20.00%\n")
+		assert.NotContains(t, stdout, "vus")
+		assert.Contains(t, stdout, "only in baseline: old_metric")
+		assert.Contains(t, stdout, "only in current: new_metric")
+		assert.Contains(t, stdout, "3 regressions found")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newCompareTestState(t)
+		ts.CmdArgs = []string{
+			"k6", "compare", "--json", "--metrics", "http_req_duration,checks",
+			"--metric-tolerance", "http_req_duration=50", "--metric-tolerance", "http_req_duration:p(95)=10%",
+			"baseline.json", "current.json",
+		}
+
+		newRootCommand(ts.GlobalState).execute()
+
+		var comp comparison
+		require.NoError(t, json.Unmarshal(ts.Stdout.Bytes(), &comp))
+		assert.Equal(t, 2, comp.Regressions)
+		require.Len(t, comp.Metrics, 2)
+		assert.False(t, comp.Metrics["http_req_duration"]["max"].Regression)
+		assert.Equal(t, 50.0, comp.Metrics["http_req_duration"]["max"].Tolerance)
+		assert.True(t, comp.Metrics["http_req_duration"]["p(95)"].Regression)
+		assert.True(t, comp.Metrics["checks"]["rate"].Regression)
+		assert.Empty(t, comp.OnlyInBaseline)
+		assert.Empty(t, comp.OnlyInCurrent)
+	})
+
+	t.Run("fail on regression", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newCompareTestState(t)
+		ts.CmdArgs = []string{"k6", "compare", "--fail-on-regression", "baseline.json", "current.json"}
+		ts.ExpectedExitCode = int(exitcodes.RegressionsFound)
+
+		newRootCommand(ts.GlobalState).execute()
+
+		assert.Contains(t, ts.Stderr.String(), "3 regressions found between 'baseline.json' and 'current.json'")
+	})
+
+	t.Run("no regressions", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newCompareTestState(t)
+		ts.CmdArgs = []string{"k6", "compare", "--fail-on-regression", "--tolerance", "50", "baseline.json", "current.json"}
+
+		newRootCommand(ts.GlobalState).execute()
+
+		assert.Contains(t, ts.Stdout.String(), "no regressions found")
+	})
+
+	t.Run("invalid tolerance", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newCompareTestState(t)
+		ts.CmdArgs = []string{"k6", "compare", "--metric-tolerance", "checks", "baseline.json", "current.json"}
+		ts.ExpectedExitCode = int(exitcodes.InvalidConfig)
+
+		newRootCommand(ts.GlobalState).execute()
+
+		assert.Contains(t, ts.Stderr.String(), "invalid metric tolerance 'checks'")
+	})
+}
+
+func TestCompareValue(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(v float64) *float64 { return &v }
+
+	testCases := []struct {
+		name           string
+		base, cur      float64
+		higherIsBetter bool
+		regression     bool
+		change         *float64
+	}{
+		{name: "within tolerance", base: 100, cur: 110, regression: false, change: ptr(10.0)},
+		{name: "worse", base: 100, cur: 111, regression: true, change: ptr(11.0)},
+		{name: "better", base: 100, cur: 50, regression: false, change: ptr(-50.0)},
+		{name: "higher is better", base: 100, cur: 50, higherIsBetter: true, regression: true, change: ptr(-50.0)},
+		{name: "zero baseline", base: 0, cur: 1, regression: true},
+		{name: "zero baseline and current", base: 0, cur: 0, regression: false, change: ptr(0.0)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cv := compareValue(tc.base, tc.cur, 10, tc.higherIsBetter)
+			assert.Equal(t, tc.regression, cv.Regression)
+			assert.Equal(t, tc.change, cv.Change)
+		})
+	}
+}
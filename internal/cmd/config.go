@@ -1,18 +1,24 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/mstoykov/envconfig"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
 	"gopkg.in/guregu/null.v3"
+	"gopkg.in/yaml.v3"
 
 	"go.k6.io/k6/cmd/state"
 	"go.k6.io/k6/errext"
@@ -56,6 +62,10 @@ type Config struct {
 
 	// TODO: deprecate
 	Collectors map[string]json.RawMessage `json:"collectors"`
+
+	// Profiles are named sets of options in the config file, which are merged
+	// on top of its base options when selected with --profile.
+	Profiles map[string]json.RawMessage `json:"profiles,omitempty" ignored:"true"`
 }
 
 // Validate checks if all of the specified options make sense
@@ -88,9 +98,117 @@ func (c Config) Apply(cfg Config) Config {
 	if len(cfg.Collectors) > 0 {
 		c.Collectors = cfg.Collectors
 	}
+	if len(cfg.Profiles) > 0 {
+		profiles := make(map[string]json.RawMessage, len(c.Profiles)+len(cfg.Profiles))
+		for name, profile := range c.Profiles {
+			profiles[name] = profile
+		}
+		for name, profile := range cfg.Profiles {
+			profiles[name] = profile
+		}
+		c.Profiles = profiles
+	}
 	return c
 }
 
+// applyProfile returns the config with the given profile deep-merged on top of
+// it: objects are merged key by key, while any other values, arrays included,
+// are replaced. A profile can extend another one with "extends": "name".
+func (c Config) applyProfile(name string) (Config, error) {
+	if name == "" {
+		return c, nil
+	}
+	profile, err := c.resolveProfile(name, nil)
+	if err != nil {
+		return Config{}, err
+	}
+
+	profiles := c.Profiles
+	c.Profiles = nil
+	data, err := json.Marshal(c)
+	if err != nil {
+		return Config{}, err
+	}
+	var base map[string]any
+	if err = json.Unmarshal(data, &base); err != nil {
+		return Config{}, err
+	}
+	if data, err = json.Marshal(deepMerge(removeNulls(base), profile)); err != nil {
+		return Config{}, err
+	}
+
+	var result Config
+	if err = json.Unmarshal(data, &result); err != nil {
+		return Config{}, fmt.Errorf("invalid config profile %q: %w", name, err)
+	}
+	result.Profiles = profiles
+	return result, nil
+}
+
+// resolveProfile returns the options of the given profile, merged on top of
+// the ones of the profiles it extends.
+func (c Config) resolveProfile(name string, chain []string) (map[string]any, error) {
+	chain = append(chain, name)
+	if slices.Contains(chain[:len(chain)-1], name) {
+		return nil, fmt.Errorf("config profiles can't extend each other in a cycle: %s", strings.Join(chain, " -> "))
+	}
+	raw, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return nil, fmt.Errorf("unknown config profile %q, there are no profiles in the config file", name)
+		}
+		return nil, fmt.Errorf("unknown config profile %q, available profiles are: %s",
+			name, strings.Join(slices.Sorted(maps.Keys(c.Profiles)), ", "))
+	}
+
+	var profile map[string]any
+	if err := json.Unmarshal(raw, &profile); err != nil {
+		return nil, fmt.Errorf("config profile %q should be an object: %w", name, err)
+	}
+	extends, ok := profile["extends"]
+	if !ok {
+		return profile, nil
+	}
+	delete(profile, "extends")
+	parentName, ok := extends.(string)
+	if !ok {
+		return nil, fmt.Errorf("the extends value of config profile %q should be a profile name", name)
+	}
+	parent, err := c.resolveProfile(parentName, chain)
+	if err != nil {
+		return nil, err
+	}
+	return deepMerge(parent, profile), nil
+}
+
+// deepMerge merges src into dst recursively, src values take precedence.
+func deepMerge(dst, src map[string]any) map[string]any {
+	for k, srcVal := range src {
+		srcMap, srcIsMap := srcVal.(map[string]any)
+		dstMap, dstIsMap := dst[k].(map[string]any)
+		if srcIsMap && dstIsMap {
+			dst[k] = deepMerge(dstMap, srcMap)
+			continue
+		}
+		dst[k] = srcVal
+	}
+	return dst
+}
+
+// removeNulls recursively removes all the null values from the object, they
+// are the unset options of a marshaled config.
+func removeNulls(m map[string]any) map[string]any {
+	for k, v := range m {
+		switch v := v.(type) {
+		case nil:
+			delete(m, k)
+		case map[string]any:
+			m[k] = removeNulls(v)
+		}
+	}
+	return m
+}
+
 // getPartialConfig returns a Config but only parses the Options inside.
 func getPartialConfig(flags *pflag.FlagSet) (Config, error) {
 	opts, err := getOptions(flags)
@@ -143,14 +261,161 @@ func readDiskConfig(gs *state.GlobalState) (Config, error) {
 	if err != nil {
 		return Config{}, fmt.Errorf("couldn't load the configuration from %q: %w", gs.Flags.ConfigFilePath, err)
 	}
-	var conf Config
-	err = json.Unmarshal(data, &conf)
+	conf, err := unmarshalConfig(gs.Flags.ConfigFilePath, data)
 	if err != nil {
 		return Config{}, fmt.Errorf("couldn't parse the configuration from %q: %w", gs.Flags.ConfigFilePath, err)
 	}
 	return conf, nil
 }
 
+// isYAMLConfig returns whether the config file at the given path is a YAML one, based on its extension.
+func isYAMLConfig(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+// isTOMLConfig returns whether the config file at the given path is a TOML one, based on its extension.
+func isTOMLConfig(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
+}
+
+// unmarshalConfig parses the config file contents, as YAML, TOML or JSON depending on the file extension.
+func unmarshalConfig(path string, data []byte) (Config, error) {
+	if isYAMLConfig(path) {
+		return unmarshalYAMLConfig(data)
+	}
+	if isTOMLConfig(path) {
+		return unmarshalTOMLConfig(data)
+	}
+	var conf Config
+	err := json.Unmarshal(data, &conf)
+	return conf, err
+}
+
+// unmarshalYAMLConfig parses a YAML config, which has the same schema as the
+// JSON one. If there are multiple documents in it, each one is deep merged
+// into the previous ones, like the config profiles, so a base config can be
+// followed by overlays that only change some fields of its nested objects.
+func unmarshalYAMLConfig(data []byte) (Config, error) {
+	var conf Config
+	merged := make(map[string]any)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for i := 1; ; i++ {
+		var doc any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return conf, nil
+		}
+		if err != nil {
+			return Config{}, err
+		}
+		if doc == nil {
+			continue
+		}
+		docMap, ok := yamlToJSONValue(doc).(map[string]any)
+		if !ok {
+			return Config{}, fmt.Errorf("document %d should be a mapping", i)
+		}
+
+		// the merged documents are parsed after each one, so the errors point to it
+		merged = deepMerge(merged, docMap)
+		if conf, err = yamlJSONToConfig(merged); err != nil {
+			return Config{}, fmt.Errorf("document %d: %w", i, err)
+		}
+	}
+}
+
+// yamlJSONToConfig parses the decoded YAML through JSON, which ensures the
+// same schema and value parsing for both formats.
+func yamlJSONToConfig(doc map[string]any) (Config, error) {
+	var conf Config
+	jsonDoc, err := json.Marshal(doc)
+	if err != nil {
+		return conf, err
+	}
+	err = json.Unmarshal(jsonDoc, &conf)
+	return conf, err
+}
+
+// unmarshalTOMLConfig parses a TOML config, which has the same schema as the
+// JSON one, with the options as top-level keys and the nested objects, like
+// the scenarios, as tables.
+func unmarshalTOMLConfig(data []byte) (Config, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return Config{}, err
+	}
+
+	// going through JSON ensures the same schema and value parsing for both formats
+	jsonDoc, err := json.Marshal(doc)
+	if err != nil {
+		return Config{}, err
+	}
+	var conf Config
+	err = json.Unmarshal(jsonDoc, &conf)
+	return conf, err
+}
+
+// jsonToTOMLValue converts the decoded JSON to the values that can be encoded
+// to TOML. The null values are removed, since TOML doesn't have them and the
+// unset options are simply left out, and the numbers are kept as integers
+// when they are ones.
+func jsonToTOMLValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if e == nil {
+				delete(v, k)
+				continue
+			}
+			v[k] = jsonToTOMLValue(e)
+		}
+		return v
+	case []any:
+		for i, e := range v {
+			v[i] = jsonToTOMLValue(e)
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+// yamlToJSONValue converts the maps with non-string keys, that YAML allows, to
+// ones that can be marshaled to JSON.
+func yamlToJSONValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = yamlToJSONValue(e)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = yamlToJSONValue(e)
+		}
+		return m
+	case []any:
+		for i, e := range v {
+			v[i] = yamlToJSONValue(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
 // legacyConfigFilePath returns the path of the old location,
 // which is now deprecated and superseded by a new default.
 func legacyConfigFilePath(gs *state.GlobalState) string {
@@ -176,13 +441,36 @@ func readLegacyDiskConfig(gs *state.GlobalState) (Config, error) {
 	return conf, nil
 }
 
-// writeDiskConfig serializes the configuration to a JSON file and writes it in the supplied
+// writeDiskConfig serializes the configuration to a JSON file, or a YAML or
+// TOML one depending on the file extension, and writes it in the supplied
 // location on the supplied filesystem.
 func writeDiskConfig(gs *state.GlobalState, conf Config) error {
 	data, err := json.MarshalIndent(conf, "", "  ")
 	if err != nil {
 		return err
 	}
+	switch {
+	case isYAMLConfig(gs.Flags.ConfigFilePath):
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if data, err = yaml.Marshal(v); err != nil {
+			return err
+		}
+	case isTOMLConfig(gs.Flags.ConfigFilePath):
+		var v any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(jsonToTOMLValue(v)); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
 
 	if err := gs.FS.MkdirAll(filepath.Dir(gs.Flags.ConfigFilePath), 0o755); err != nil {
 		return err
@@ -252,6 +540,11 @@ func getConsolidatedConfig(gs *state.GlobalState, cliConf Config, runnerOpts lib
 		return Config{}, errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
 	}
 
+	fileConf, err = fileConf.applyProfile(gs.Flags.ConfigProfile)
+	if err != nil {
+		return Config{}, errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
+
 	envConf, err := readEnvConfig(gs.Env)
 	if err != nil {
 		return Config{}, errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
@@ -278,6 +571,9 @@ func getConsolidatedConfig(gs *state.GlobalState, cliConf Config, runnerOpts lib
 	if _, err = metrics.GetResolversForTrendColumns(conf.SummaryTrendStats); err != nil {
 		return Config{}, err
 	}
+	if conf.ExpectedResponses, err = conf.ExpectedResponses.Compile(); err != nil {
+		return Config{}, errext.WithExitCodeIfNone(err, exitcodes.InvalidConfig)
+	}
 
 	return conf, nil
 }
@@ -358,6 +654,16 @@ func consolidateErrorMessage(errList []error, title string) error {
 }
 
 func validateScenarioConfig(conf lib.ExecutorConfig, isExecutable func(string) bool) error {
+	// the scenarios with a mix run its functions instead of the exec one
+	if mc, ok := conf.(interface{ GetMix() lib.Mix }); ok && mc.GetMix() != nil {
+		for _, execFn := range mc.GetMix().Names() {
+			if !isExecutable(execFn) {
+				return fmt.Errorf("executor %s: mix function '%s' not found in exports", conf.GetName(), execFn)
+			}
+		}
+		return nil
+	}
+
 	execFn := conf.GetExec()
 	if !isExecutable(execFn) {
 		return fmt.Errorf("executor %s: function '%s' not found in exports", conf.GetName(), execFn)
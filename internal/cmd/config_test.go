@@ -186,6 +186,21 @@ func TestDeriveAndValidateConfig(t *testing.T) {
 			false,
 			"executor per_vu_iters: function 'nonDefaultErr' not found in exports",
 		},
+		{
+			"mixErr",
+			Config{Options: lib.Options{Scenarios: lib.ScenarioConfigs{
+				"mixed": executor.PerVUIterationsConfig{
+					BaseConfig: executor.BaseConfig{
+						Name: "mixed", Type: "per-vu-iterations", Mix: lib.Mix{"browse": 1},
+					},
+					VUs:         null.IntFrom(1),
+					Iterations:  null.IntFrom(1),
+					MaxDuration: types.NullDurationFrom(time.Second),
+				},
+			}}},
+			false,
+			"executor mixed: mix function 'browse' not found in exports",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -324,6 +339,133 @@ func TestReadDiskConfigNotFoundErrorWithCustomPath(t *testing.T) {
 	assert.Empty(t, c)
 }
 
+func TestReadDiskConfigYAML(t *testing.T) {
+	t.Parallel()
+	memfs := fsext.NewMemMapFs()
+
+	conf := []byte(`
+# the base config
+iterations: 1028
+vus: 10
+linger: true
+cloud:
+  field1: testvalue
+scenarios:
+  default:
+    executor: shared-iterations
+    iterations: 100
+    gracefulStop: 10s
+---
+# the staging overlay
+vus: 20
+out: [json=results.json]
+cloud:
+  field2: overlay
+scenarios:
+  default:
+    iterations: 200
+---
+`)
+	require.NoError(t, fsext.WriteFile(memfs, "custom-path/config.yaml", conf, 0o644))
+
+	defaultFlags := state.GetDefaultFlags(".config", ".cache")
+	gs := &state.GlobalState{
+		FS:           memfs,
+		Flags:        defaultFlags,
+		DefaultFlags: defaultFlags,
+	}
+	gs.Flags.ConfigFilePath = "custom-path/config.yaml"
+
+	c, err := readDiskConfig(gs)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1028), c.Iterations.Int64)
+	assert.Equal(t, null.IntFrom(20), c.VUs)
+	assert.Equal(t, null.BoolFrom(true), c.Linger)
+	assert.Equal(t, []string{"json=results.json"}, c.Out)
+	assert.JSONEq(t, `{"field1":"testvalue","field2":"overlay"}`, string(c.Cloud))
+	require.Contains(t, c.Scenarios, "default")
+	sc, ok := c.Scenarios["default"].(executor.SharedIterationsConfig)
+	require.True(t, ok)
+	assert.Equal(t, null.IntFrom(200), sc.Iterations)
+	assert.Equal(t, types.NullDurationFrom(10*time.Second), sc.GracefulStop)
+}
+
+func TestReadDiskConfigTOML(t *testing.T) {
+	t.Parallel()
+	memfs := fsext.NewMemMapFs()
+
+	conf := []byte(`
+# the base config
+iterations = 1028
+vus = 10
+linger = true
+out = ["json=results.json"]
+
+[cloud]
+field1 = "testvalue"
+
+[scenarios.default]
+executor = "shared-iterations"
+iterations = 100
+gracefulStop = "10s"
+`)
+	require.NoError(t, fsext.WriteFile(memfs, "custom-path/config.toml", conf, 0o644))
+
+	defaultFlags := state.GetDefaultFlags(".config", ".cache")
+	gs := &state.GlobalState{
+		FS:           memfs,
+		Flags:        defaultFlags,
+		DefaultFlags: defaultFlags,
+	}
+	gs.Flags.ConfigFilePath = "custom-path/config.toml"
+
+	c, err := readDiskConfig(gs)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1028), c.Iterations.Int64)
+	assert.Equal(t, null.IntFrom(10), c.VUs)
+	assert.Equal(t, null.BoolFrom(true), c.Linger)
+	assert.Equal(t, []string{"json=results.json"}, c.Out)
+	assert.JSONEq(t, `{"field1":"testvalue"}`, string(c.Cloud))
+	require.Contains(t, c.Scenarios, "default")
+	sc, ok := c.Scenarios["default"].(executor.SharedIterationsConfig)
+	require.True(t, ok)
+	assert.Equal(t, null.IntFrom(100), sc.Iterations)
+	assert.Equal(t, types.NullDurationFrom(10*time.Second), sc.GracefulStop)
+}
+
+func TestReadDiskConfigFormatErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		path, content, expectedError string
+	}{
+		"invalid yaml":    {"config.yml", "vus: [1", "did not find expected"},
+		"invalid overlay": {"config.yml", "vus: 1\n---\nvus: abc\n", "document 2:"},
+		"invalid toml":    {"config.toml", "vus = [1", "toml:"},
+		"invalid option":  {"config.toml", "vus = \"abc\"", "invalid syntax"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			memfs := fsext.NewMemMapFs()
+			require.NoError(t, fsext.WriteFile(memfs, tc.path, []byte(tc.content), 0o644))
+
+			defaultFlags := state.GetDefaultFlags(".config", ".cache")
+			gs := &state.GlobalState{
+				FS:           memfs,
+				Flags:        defaultFlags,
+				DefaultFlags: defaultFlags,
+			}
+			gs.Flags.ConfigFilePath = tc.path
+
+			_, err := readDiskConfig(gs)
+			require.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
 func TestWriteDiskConfigWithDefaultFlags(t *testing.T) {
 	t.Parallel()
 	memfs := fsext.NewMemMapFs()
@@ -381,6 +523,72 @@ func TestWriteDiskConfigCustomPath(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestWriteDiskConfigYAML(t *testing.T) {
+	t.Parallel()
+	memfs := fsext.NewMemMapFs()
+
+	defaultFlags := state.GetDefaultFlags(".config", ".cache")
+	gs := &state.GlobalState{
+		FS:           memfs,
+		Flags:        defaultFlags,
+		DefaultFlags: defaultFlags,
+	}
+	gs.Flags.ConfigFilePath = "custom-path/config.yaml"
+
+	c := Config{
+		WebDashboard: null.BoolFrom(true),
+		Options: lib.Options{
+			VUs:   null.IntFrom(5),
+			Cloud: []byte(`{"token":"abc"}`),
+		},
+	}
+	require.NoError(t, writeDiskConfig(gs, c))
+
+	data, err := fsext.ReadFile(memfs, "custom-path/config.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "webDashboard: true\n")
+	assert.Contains(t, string(data), "vus: 5\n")
+
+	rc, err := readDiskConfig(gs)
+	require.NoError(t, err)
+	assert.Equal(t, null.BoolFrom(true), rc.WebDashboard)
+	assert.Equal(t, null.IntFrom(5), rc.VUs)
+	assert.JSONEq(t, `{"token":"abc"}`, string(rc.Cloud))
+}
+
+func TestWriteDiskConfigTOML(t *testing.T) {
+	t.Parallel()
+	memfs := fsext.NewMemMapFs()
+
+	defaultFlags := state.GetDefaultFlags(".config", ".cache")
+	gs := &state.GlobalState{
+		FS:           memfs,
+		Flags:        defaultFlags,
+		DefaultFlags: defaultFlags,
+	}
+	gs.Flags.ConfigFilePath = "custom-path/config.toml"
+
+	c := Config{
+		WebDashboard: null.BoolFrom(true),
+		Options: lib.Options{
+			VUs:   null.IntFrom(5),
+			Cloud: []byte(`{"token":"abc"}`),
+		},
+	}
+	require.NoError(t, writeDiskConfig(gs, c))
+
+	data, err := fsext.ReadFile(memfs, "custom-path/config.toml")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "webDashboard = true\n")
+	assert.Contains(t, string(data), "vus = 5\n")
+
+	rc, err := readDiskConfig(gs)
+	require.NoError(t, err)
+	assert.Equal(t, null.BoolFrom(true), rc.WebDashboard)
+	assert.Equal(t, null.IntFrom(5), rc.VUs)
+	assert.JSONEq(t, `{"token":"abc"}`, string(rc.Cloud))
+}
+
 func TestWriteDiskConfigNoJSONContentError(t *testing.T) {
 	t.Parallel()
 	memfs := fsext.NewMemMapFs()
@@ -518,3 +726,109 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigProfiles(t *testing.T) {
+	t.Parallel()
+
+	conf, err := unmarshalYAMLConfig([]byte(`
+vus: 10
+tags:
+  team: perf
+scenarios:
+  main:
+    executor: constant-vus
+    vus: 5
+    duration: 1m
+profiles:
+  smoke:
+    vus: 1
+    scenarios:
+      main:
+        duration: 10s
+  soak:
+    extends: smoke
+    tags:
+      kind: soak
+    scenarios:
+      main:
+        vus: 50
+        duration: 2h
+  loop:
+    extends: cycle
+  cycle:
+    extends: loop
+`))
+	require.NoError(t, err)
+	require.Len(t, conf.Profiles, 4)
+
+	t.Run("no profile", func(t *testing.T) {
+		t.Parallel()
+		c, err := conf.applyProfile("")
+		require.NoError(t, err)
+		assert.Equal(t, conf, c)
+	})
+
+	t.Run("smoke", func(t *testing.T) {
+		t.Parallel()
+		c, err := conf.applyProfile("smoke")
+		require.NoError(t, err)
+		assert.Equal(t, null.IntFrom(1), c.VUs)
+		assert.Equal(t, map[string]string{"team": "perf"}, c.RunTags)
+		sc, ok := c.Scenarios["main"].(executor.ConstantVUsConfig)
+		require.True(t, ok)
+		assert.Equal(t, null.IntFrom(5), sc.VUs)
+		assert.Equal(t, types.NullDurationFrom(10*time.Second), sc.Duration)
+		assert.Equal(t, conf.Profiles, c.Profiles)
+	})
+
+	t.Run("soak extends smoke", func(t *testing.T) {
+		t.Parallel()
+		c, err := conf.applyProfile("soak")
+		require.NoError(t, err)
+		assert.Equal(t, null.IntFrom(1), c.VUs)
+		assert.Equal(t, map[string]string{"team": "perf", "kind": "soak"}, c.RunTags)
+		sc, ok := c.Scenarios["main"].(executor.ConstantVUsConfig)
+		require.True(t, ok)
+		assert.Equal(t, null.IntFrom(50), sc.VUs)
+		assert.Equal(t, types.NullDurationFrom(2*time.Hour), sc.Duration)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+		_, err := conf.applyProfile("stress")
+		require.ErrorContains(t, err,
+			`unknown config profile "stress", available profiles are: cycle, loop, smoke, soak`)
+
+		_, err = Config{}.applyProfile("stress")
+		require.ErrorContains(t, err, "there are no profiles in the config file")
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+		_, err := conf.applyProfile("loop")
+		require.ErrorContains(t, err, "can't extend each other in a cycle: loop -> cycle -> loop")
+	})
+}
+
+func TestConfigProfileFlag(t *testing.T) {
+	t.Parallel()
+
+	memfs := testutils.MakeMemMapFs(t, map[string][]byte{
+		"config.json": []byte(`{"vus":10,"profiles":{"smoke":{"vus":1,"iterations":1}}}`),
+	})
+	defaultFlags := state.GetDefaultFlags(".config", ".cache")
+	gs := &state.GlobalState{
+		FS:           memfs,
+		Flags:        defaultFlags,
+		DefaultFlags: defaultFlags,
+		Env:          map[string]string{"K6_ITERATIONS": "5"},
+		Logger:       testutils.NewLogger(t).(*logrus.Logger), //nolint:forbidigo // required by GlobalState
+	}
+	gs.Flags.ConfigFilePath = "config.json"
+	gs.Flags.ConfigProfile = "smoke"
+
+	conf, err := getConsolidatedConfig(gs, Config{}, lib.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, null.IntFrom(1), conf.VUs)
+	assert.Equal(t, null.IntFrom(5), conf.Iterations)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// httpCassetteRecorder is implemented by the runners that can record the HTTP
+// responses to a cassette, see the httpRecord option.
+type httpCassetteRecorder interface {
+	RecordedHTTPCassette() (path string, data []byte, err error)
+}
+
+// saveHTTPCassette saves the cassette with the HTTP responses that were
+// recorded during the test, if any.
+func (c *cmdRun) saveHTTPCassette(runner lib.Runner, logger logrus.FieldLogger) {
+	recorder, ok := runner.(httpCassetteRecorder)
+	if !ok {
+		return
+	}
+	path, data, err := recorder.RecordedHTTPCassette()
+	if err == nil && path != "" {
+		err = fsext.WriteFile(c.gs.FS, path, data, 0o644)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Couldn't save the HTTP cassette")
+		return
+	}
+	if path != "" {
+		logger.Infof("The HTTP responses were recorded to %s", path)
+	}
+}
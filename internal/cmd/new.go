@@ -25,7 +25,9 @@ func (c *newScriptCmd) flagSet() *pflag.FlagSet {
 	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
 	flags.SortFlags = false
 	flags.BoolVarP(&c.overwriteFiles, "force", "f", false, "overwrite existing files")
-	flags.StringVar(&c.templateType, "template", "minimal", "template type (choices: minimal, protocol, browser) or relative/absolute path to a custom template file") //nolint:lll
+	flags.StringVar(&c.templateType, "template", templates.MinimalTemplate, fmt.Sprintf(
+		"template type (choices: %s) or relative/absolute path to a custom template file",
+		strings.Join(templates.BuiltinTemplates(), ", ")))
 	flags.StringVar(&c.projectID, "project-id", "", "specify the Grafana Cloud project ID for the test")
 	return flags
 }
@@ -111,6 +113,9 @@ func getCmdNewScript(gs *state.GlobalState) *cobra.Command {
     # Create a script using a specific template
     $ {{.}} new --template protocol
 
+    # Scaffold a gRPC, WebSocket, soak or multi-scenario test
+    $ {{.}} new --template grpc grpc-test.js
+
     # Create a cloud-ready script with a specific project ID
     $ {{.}} new --project-id 12315`[1:])
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.k6.io/k6/internal/cmd/templates"
 	"go.k6.io/k6/internal/cmd/tests"
 	"go.k6.io/k6/lib/fsext"
 )
@@ -110,6 +111,40 @@ func TestNewScriptCmd_InvalidTemplateType(t *testing.T) {
 	assert.False(t, exists, "script file should not exist")
 }
 
+func TestNewScriptCmd_BuiltinTemplates(t *testing.T) {
+	t.Parallel()
+
+	withSummary := map[string]bool{
+		templates.GRPCTemplate:          true,
+		templates.WebSocketTemplate:     true,
+		templates.SoakTemplate:          true,
+		templates.MultiScenarioTemplate: true,
+	}
+	for _, name := range templates.BuiltinTemplates() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := tests.NewGlobalTestState(t)
+			ts.CmdArgs = []string{"k6", "new", "--template", name, "--project-id", "1422", "test.js"}
+
+			newRootCommand(ts.GlobalState).execute()
+			assert.Contains(t, ts.Stdout.String(), "New script created: test.js ("+name+" template).")
+
+			data, err := fsext.ReadFile(ts.FS, "test.js")
+			require.NoError(t, err)
+
+			jsData := string(data)
+			assert.Contains(t, jsData, "export const options = {")
+			assert.Contains(t, jsData, "projectID: 1422")
+			assert.Contains(t, jsData, `name: "test.js"`)
+			if withSummary[name] {
+				assert.Contains(t, jsData, "thresholds: {")
+				assert.Contains(t, jsData, "export function handleSummary(data) {")
+			}
+		})
+	}
+}
+
 func TestNewScriptCmd_ProjectID(t *testing.T) {
 	t.Parallel()
 
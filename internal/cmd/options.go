@@ -30,6 +30,8 @@ func optionFlagSet() *pflag.FlagSet {
 	flags.StringSliceP("stage", "s", nil, "add a `stage`, as `[duration]:[target]`")
 	flags.String("execution-segment", "", "limit execution to the specified segment, e.g. 10%, 1/3, 0.2:2/3")
 	flags.String("execution-segment-sequence", "", "the execution segment sequence") // TODO better description
+	flags.String("instance", "", "run as the `index/total` instance of an evenly distributed test, e.g. 3/10; "+
+		"it sets both the execution segment and the execution segment sequence")
 	flags.BoolP("paused", "p", false, "start the test in a paused state")
 	flags.Bool("no-setup", false, "don't run setup()")
 	flags.Bool("no-teardown", false, "don't run teardown()")
@@ -40,10 +42,24 @@ func optionFlagSet() *pflag.FlagSet {
 	flags.String("user-agent", fmt.Sprintf("Grafana k6/%s", build.Version), "user agent for http requests")
 	flags.String("http-debug", "", "log all HTTP requests and responses. Excludes body by default. To include body use '--http-debug=full'") //nolint:lll
 	flags.Lookup("http-debug").NoOptDefVal = "headers"
+	flags.String("http-record", "", "record all HTTP responses to a cassette `file`, relative to the script")
+	flags.String("http-replay", "", "replay the HTTP responses from a cassette `file`, relative to the script, "+
+		"instead of sending the requests")
+	flags.Int64("random-seed", 0, "seed Math.random() and the k6/random streams to replay the random values of a run")
 	flags.Bool("insecure-skip-tls-verify", false, "skip verification of TLS certificates")
 	flags.Bool("no-connection-reuse", false, "disable keep-alive connections")
 	flags.Bool("no-vu-connection-reuse", false, "don't reuse connections between iterations")
+	flags.Int64("max-concurrent-requests", 0, "limit the concurrent k6/http requests of all VUs, shared by the "+
+		"scenarios in proportion to their weights")
 	flags.Duration("min-iteration-duration", 0, "minimum amount of time k6 will take executing a single iteration")
+	flags.Duration("iteration-timeout", 0, "maximum amount of time a single iteration can take before being interrupted")
+	flags.Int64("vu-memory-limit", 0, "maximum estimated size in `bytes` of the JS values a VU can retain between "+
+		"iterations in its globals and the exports of its main module, the values that are only held by "+
+		"module-level variables, closures or imported modules aren't counted, so leaks in them aren't detected")
+	flags.String("vu-memory-limit-action", "warn", "what to do with the VUs exceeding vu-memory-limit, 'warn' or 'restart'")
+	flags.Bool("iteration-breakdown", false, "split the iteration duration into script, sleep and network time metrics")
+	flags.Bool("connection-metrics", false, "emit metrics of the connections per host, ephemeral ports in use and "+
+		"connection churn")
 	flags.BoolP("throw", "w", false, "throw warnings (like failed http requests) as errors")
 	flags.StringSlice("blacklist-ip", nil, "blacklist an `ip range` from being called")
 	flags.StringSlice("block-hostnames", nil, "block a case-insensitive hostname `pattern`,"+
@@ -56,6 +72,14 @@ func optionFlagSet() *pflag.FlagSet {
 		strings.Join(lib.DefaultSummaryTrendStats, ", "),
 	)
 	flags.StringSlice("summary-trend-stats", nil, sumTrendStatsHelp)
+	flags.StringSlice("summary-breakdown", nil, "break down the key metrics in the end-of-test summary "+
+		"by the values of these `tags`, e.g. 'scenario,group'")
+	flags.StringSlice("summary-time-series", nil, "show the values of these `metrics` in the end-of-test summary "+
+		"as a series of time buckets, e.g. 'http_req_duration,http_reqs'")
+	flags.Duration("summary-time-series-interval", 0, "the `duration` of the time buckets of summary-time-series "+
+		"(default 10s)")
+	flags.Duration("trend-exact-window", 0, "keep the exact values of trend metrics only for this `duration`, "+
+		"older values are downsampled to bound the memory usage of long tests")
 	flags.String("summary-time-unit", "", "define the time unit used to display the trend stats. Possible units are: 's', 'ms' and 'us'") //nolint:lll
 	// system-tags must have a default value, but we can't specify it here, otherwiese, it will always override others.
 	// set it to nil here, and add the default in applyDefault() instead.
@@ -65,6 +89,8 @@ func optionFlagSet() *pflag.FlagSet {
 	)
 	flags.StringSlice("system-tags", nil, systemTagsCliHelpText)
 	flags.StringArray("tag", nil, "add a `tag` to be applied to all samples, as `[name]=[value]`")
+	flags.StringArray("run-metadata", nil, "add `metadata` about the run, e.g. the git SHA, given to the outputs "+
+		"and to handleSummary(), as `[key]=[value]`")
 	flags.String("console-output", "", "redirects the console logging to the provided output file")
 	flags.Bool("discard-response-bodies", false, "Read but don't process or save HTTP response bodies")
 	flags.String("local-ips", "", "Client IP Ranges and/or CIDRs from which each VU will be making requests, "+
@@ -74,31 +100,47 @@ func optionFlagSet() *pflag.FlagSet {
 		"Milliseconds are assumed if no unit is provided. "+
 		"Possible select values to return a single IP are: 'first', 'random' or 'roundRobin'. "+
 		"Possible policy values are: 'preferIPv4', 'preferIPv6', 'onlyIPv4', 'onlyIPv6' or 'any'.")
+	flags.String("dial-family", "any", "restrict the connections to one address `family`, 'ipv4' or 'ipv6', "+
+		"or allow 'any' of them")
 	return flags
 }
 
 //nolint:funlen,gocognit,cyclop // this needs breaking up but probably should wait for croconf
 func getOptions(flags *pflag.FlagSet) (lib.Options, error) {
 	opts := lib.Options{
-		VUs:                     getNullInt64(flags, "vus"),
-		Duration:                getNullDuration(flags, "duration"),
-		Iterations:              getNullInt64(flags, "iterations"),
-		Paused:                  getNullBool(flags, "paused"),
-		NoSetup:                 getNullBool(flags, "no-setup"),
-		NoTeardown:              getNullBool(flags, "no-teardown"),
-		MaxRedirects:            getNullInt64(flags, "max-redirects"),
-		Batch:                   getNullInt64(flags, "batch"),
-		BatchPerHost:            getNullInt64(flags, "batch-per-host"),
-		RPS:                     getNullInt64(flags, "rps"),
-		UserAgent:               getNullString(flags, "user-agent"),
-		HTTPDebug:               getNullString(flags, "http-debug"),
-		InsecureSkipTLSVerify:   getNullBool(flags, "insecure-skip-tls-verify"),
-		NoConnectionReuse:       getNullBool(flags, "no-connection-reuse"),
-		NoVUConnectionReuse:     getNullBool(flags, "no-vu-connection-reuse"),
-		MinIterationDuration:    getNullDuration(flags, "min-iteration-duration"),
-		Throw:                   getNullBool(flags, "throw"),
-		DiscardResponseBodies:   getNullBool(flags, "discard-response-bodies"),
-		MetricSamplesBufferSize: null.NewInt(1000, false),
+		VUs:                       getNullInt64(flags, "vus"),
+		Duration:                  getNullDuration(flags, "duration"),
+		Iterations:                getNullInt64(flags, "iterations"),
+		Paused:                    getNullBool(flags, "paused"),
+		NoSetup:                   getNullBool(flags, "no-setup"),
+		NoTeardown:                getNullBool(flags, "no-teardown"),
+		MaxRedirects:              getNullInt64(flags, "max-redirects"),
+		Batch:                     getNullInt64(flags, "batch"),
+		BatchPerHost:              getNullInt64(flags, "batch-per-host"),
+		RPS:                       getNullInt64(flags, "rps"),
+		UserAgent:                 getNullString(flags, "user-agent"),
+		HTTPDebug:                 getNullString(flags, "http-debug"),
+		HTTPRecord:                getNullString(flags, "http-record"),
+		HTTPReplay:                getNullString(flags, "http-replay"),
+		DialFamily:                getNullString(flags, "dial-family"),
+		RandomSeed:                getNullInt64(flags, "random-seed"),
+		InsecureSkipTLSVerify:     getNullBool(flags, "insecure-skip-tls-verify"),
+		NoConnectionReuse:         getNullBool(flags, "no-connection-reuse"),
+		NoVUConnectionReuse:       getNullBool(flags, "no-vu-connection-reuse"),
+		MaxConcurrentRequests:     getNullInt64(flags, "max-concurrent-requests"),
+		MinIterationDuration:      getNullDuration(flags, "min-iteration-duration"),
+		IterationTimeout:          getNullDuration(flags, "iteration-timeout"),
+		TrendExactWindow:          getNullDuration(flags, "trend-exact-window"),
+		SummaryTimeSeriesInterval: getNullDuration(flags, "summary-time-series-interval"),
+		VUMemoryLimit:             getNullInt64(flags, "vu-memory-limit"),
+		VUMemoryLimitAction:       getNullString(flags, "vu-memory-limit-action"),
+		IterationBreakdown:        getNullBool(flags, "iteration-breakdown"),
+		ConnectionMetrics:         getNullBool(flags, "connection-metrics"),
+		Throw:                     getNullBool(flags, "throw"),
+		DiscardResponseBodies:     getNullBool(flags, "discard-response-bodies"),
+		MetricSamplesBufferSize:   null.NewInt(1000, false),
+		MetricSamplesBufferLimit:  null.NewInt(0, false),
+		MetricSamplesBufferPolicy: null.NewString("block", false),
 	}
 
 	// Using Changed() because GetStringSlice() doesn't differentiate between empty and no value
@@ -146,6 +188,39 @@ func getOptions(flags *pflag.FlagSet) (lib.Options, error) {
 		opts.ExecutionSegmentSequence = segmentSequence
 	}
 
+	if flags.Changed("instance") {
+		if flags.Changed("execution-segment") || flags.Changed("execution-segment-sequence") {
+			return opts, errors.New("the instance flag can't be used together with the " +
+				"execution-segment and execution-segment-sequence flags")
+		}
+		instanceStr, err := flags.GetString("instance")
+		if err != nil {
+			return opts, err
+		}
+		segment, sequence, err := lib.NewInstanceExecutionSegmentFromString(instanceStr)
+		if err != nil {
+			return opts, err
+		}
+		opts.ExecutionSegment = segment
+		opts.ExecutionSegmentSequence = &sequence
+	}
+
+	if flags.Changed("summary-breakdown") {
+		summaryBreakdown, err := flags.GetStringSlice("summary-breakdown")
+		if err != nil {
+			return opts, err
+		}
+		opts.SummaryBreakdown = summaryBreakdown
+	}
+
+	if flags.Changed("summary-time-series") {
+		summaryTimeSeries, err := flags.GetStringSlice("summary-time-series")
+		if err != nil {
+			return opts, err
+		}
+		opts.SummaryTimeSeries = summaryTimeSeries
+	}
+
 	if flags.Changed("system-tags") {
 		systemTagList, err := flags.GetStringSlice("system-tags")
 		if err != nil {
@@ -228,6 +303,24 @@ func getOptions(flags *pflag.FlagSet) (lib.Options, error) {
 		opts.RunTags = parsedRunTags
 	}
 
+	runMetadata, err := flags.GetStringArray("run-metadata")
+	if err != nil {
+		return opts, err
+	}
+
+	if len(runMetadata) > 0 {
+		parsedRunMetadata := make(map[string]string, len(runMetadata))
+		for _, s := range runMetadata {
+			var key, value string
+			key, value, err = parseTagNameValue(s)
+			if err != nil {
+				return opts, fmt.Errorf("error parsing run metadata '%s': %w", s, err)
+			}
+			parsedRunMetadata[key] = value
+		}
+		opts.RunMetadata = parsedRunMetadata
+	}
+
 	redirectConFile, err := flags.GetString("console-output")
 	if err != nil {
 		return opts, err
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseTagKeyValue(t *testing.T) {
@@ -62,3 +63,24 @@ func TestParseTagKeyValue(t *testing.T) {
 		})
 	}
 }
+
+func TestGetOptionsInstance(t *testing.T) {
+	t.Parallel()
+
+	flags := optionFlagSet()
+	require.NoError(t, flags.Parse([]string{"--instance", "2/4"}))
+	opts, err := getOptions(flags)
+	require.NoError(t, err)
+	assert.Equal(t, "1/4:1/2", opts.ExecutionSegment.String())
+	assert.Equal(t, "0,1/4,1/2,3/4,1", opts.ExecutionSegmentSequence.String())
+
+	flags = optionFlagSet()
+	require.NoError(t, flags.Parse([]string{"--instance", "2/4", "--execution-segment", "1/2"}))
+	_, err = getOptions(flags)
+	require.ErrorContains(t, err, "can't be used together")
+
+	flags = optionFlagSet()
+	require.NoError(t, flags.Parse([]string{"--instance", "5/4"}))
+	_, err = getOptions(flags)
+	require.Error(t, err)
+}
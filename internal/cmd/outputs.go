@@ -14,6 +14,8 @@ import (
 	"go.k6.io/k6/internal/output/json"
 	"go.k6.io/k6/internal/output/opentelemetry"
 	"go.k6.io/k6/internal/output/prometheusrw/remotewrite"
+	"go.k6.io/k6/internal/output/stream"
+	"go.k6.io/k6/internal/output/termdashboard"
 	"go.k6.io/k6/lib"
 	"go.k6.io/k6/output"
 
@@ -39,16 +41,20 @@ const (
 	builtinOutputExperimentalOpentelemetry
 	builtinOutputOpentelemetry
 	builtinOutputSummary
+	builtinOutputDashboard
+	builtinOutputStream
 )
 
 // TODO: move this to an output sub-module after we get rid of the old collectors?
 func getAllOutputConstructors() (map[string]output.Constructor, error) {
 	// Start with the built-in outputs
 	result := map[string]output.Constructor{
-		builtinOutputJSON.String():     json.New,
-		builtinOutputCloud.String():    cloud.New,
-		builtinOutputCSV.String():      csv.New,
-		builtinOutputInfluxdb.String(): influxdb.New,
+		builtinOutputJSON.String():      json.New,
+		builtinOutputCloud.String():     cloud.New,
+		builtinOutputCSV.String():       csv.New,
+		builtinOutputInfluxdb.String():  influxdb.New,
+		builtinOutputDashboard.String(): termdashboard.New,
+		builtinOutputStream.String():    stream.New,
 		builtinOutputKafka.String(): func(_ output.Params) (output.Output, error) {
 			return nil, errors.New("the kafka output was deprecated in k6 v0.32.0 and removed in k6 v0.34.0, " +
 				"please use the new xk6 kafka output extension instead - https://github.com/k6io/xk6-output-kafka")
@@ -108,6 +114,16 @@ func getPossibleIDList(constrs map[string]output.Constructor) string {
 	return strings.Join(res, ", ")
 }
 
+// hasOutput returns whether the given builtin output is among the configured ones.
+func hasOutput(outputs []string, o builtinOutput) bool {
+	for _, outputFullArg := range outputs {
+		if outputType, _, _ := strings.Cut(outputFullArg, "="); outputType == o.String() {
+			return true
+		}
+	}
+	return false
+}
+
 func createOutputs(
 	gs *state.GlobalState, test *loadedAndConfiguredTest, executionPlan []lib.ExecutionStep,
 ) ([]output.Output, error) {
@@ -169,6 +185,17 @@ func createOutputs(
 			builtinMetricOut.SetBuiltinMetrics(test.preInitState.BuiltinMetrics)
 		}
 
+		if runMetadataOut, ok := out.(output.WithRunMetadata); ok {
+			runMetadataOut.SetRunMetadata(test.preInitState.RunMetadata)
+		}
+
+		if limit := test.derivedConfig.MetricSamplesBufferLimit.Int64; limit > 0 {
+			if limitOut, ok := out.(output.WithBufferLimit); ok {
+				policy := output.BufferPolicy(test.derivedConfig.MetricSamplesBufferPolicy.String)
+				limitOut.SetBufferLimit(int(limit), policy)
+			}
+		}
+
 		// If the output is configured to support the archive, and supports it, we proceed
 		// with building an archive and setting it on the output instance.
 		if !test.derivedConfig.NoArchiveUpload.Bool {
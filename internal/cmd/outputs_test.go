@@ -12,7 +12,14 @@ func TestBuiltinOutputString(t *testing.T) {
 		"cloud", "csv", "datadog", "experimental-prometheus-rw",
 		"influxdb", "json", "kafka", "statsd",
 		"experimental-opentelemetry", "opentelemetry",
-		"summary",
+		"summary", "dashboard", "stream",
 	}
 	assert.Equal(t, exp, builtinOutputStrings())
 }
+
+func TestHasOutput(t *testing.T) {
+	t.Parallel()
+	assert.True(t, hasOutput([]string{"json=out.json", "dashboard"}, builtinOutputDashboard))
+	assert.True(t, hasOutput([]string{"dashboard=period=2s"}, builtinOutputDashboard))
+	assert.False(t, hasOutput([]string{"web-dashboard", "csv=dashboard"}, builtinOutputDashboard))
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/internal/ui/pb"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+	"go.k6.io/k6/output"
+)
+
+const (
+	progressFormatText = "text"
+	progressFormatJSON = "json"
+
+	// progressJSONTrendWindow is the window of time for which the exact values
+	// of the trends are kept, the older ones are only kept in a sketch.
+	progressJSONTrendWindow = time.Minute
+)
+
+// progressUpdate is a single line of the --progress-format json stream. The
+// durations are in seconds, so they can be used without parsing them.
+type progressUpdate struct {
+	Time    time.Time `json:"time"`
+	Status  string    `json:"status"`
+	Elapsed float64   `json:"elapsed"`
+	// ETA is the time until the end of the execution plan, it's an upper
+	// bound, since the iteration-based scenarios can finish earlier.
+	ETA                   float64                       `json:"eta"`
+	VUs                   int64                         `json:"vus"`
+	VUsMax                int64                         `json:"vusMax"`
+	Iterations            uint64                        `json:"iterations"`
+	InterruptedIterations uint64                        `json:"interruptedIterations"`
+	Scenarios             []scenarioProgress            `json:"scenarios"`
+	Metrics               map[string]map[string]float64 `json:"metrics"`
+}
+
+// scenarioProgress is the progress of a single scenario in a progressUpdate.
+// Its iterations are counted from the metrics, so they are missing if the
+// scenario system tag is disabled.
+type scenarioProgress struct {
+	Name     string  `json:"name"`
+	Executor string  `json:"executor"`
+	Status   string  `json:"status"`
+	Progress float64 `json:"progress"`
+	VUs      int64   `json:"vus"`
+	// Iterations are the completed ones and IterationRate is their rate per
+	// second since the previous update.
+	Iterations    uint64  `json:"iterations"`
+	IterationRate float64 `json:"iterationRate"`
+}
+
+// progressJSONWriter is an output that aggregates the metrics of the test and
+// periodically writes the progress of the test, instead of the progress bars,
+// as JSON lines for the wrappers of k6 and the parsers of CI logs.
+type progressJSONWriter struct {
+	out         io.Writer
+	state       *lib.ExecutionState
+	executors   []lib.Executor
+	maxDuration time.Duration
+	interval    time.Duration
+	logger      logrus.FieldLogger
+
+	mu             sync.Mutex
+	sinks          map[string]metrics.Sink
+	iterations     map[string]uint64
+	lastIterations map[string]uint64
+	lastUpdate     time.Time
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+var _ output.Output = &progressJSONWriter{}
+
+func newProgressJSONWriter(
+	out io.Writer, state *lib.ExecutionState, executors []lib.Executor, executionPlan []lib.ExecutionStep,
+	interval time.Duration, logger logrus.FieldLogger,
+) *progressJSONWriter {
+	maxDuration, _ := lib.GetEndOffset(executionPlan)
+	return &progressJSONWriter{
+		out:            out,
+		state:          state,
+		executors:      executors,
+		maxDuration:    maxDuration,
+		interval:       interval,
+		logger:         logger,
+		sinks:          make(map[string]metrics.Sink),
+		iterations:     make(map[string]uint64),
+		lastIterations: make(map[string]uint64),
+		stop:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+	}
+}
+
+func (pw *progressJSONWriter) Description() string {
+	return "progress (json)"
+}
+
+func (pw *progressJSONWriter) Start() error {
+	pw.lastUpdate = time.Now()
+	go func() {
+		defer close(pw.stopped)
+		ticker := time.NewTicker(pw.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				pw.write()
+			case <-pw.stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (pw *progressJSONWriter) AddMetricSamples(samples []metrics.SampleContainer) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	for _, sc := range samples {
+		for _, s := range sc.GetSamples() {
+			sink, ok := pw.sinks[s.Metric.Name]
+			if !ok {
+				sink = metrics.NewSink(s.Metric.Type)
+				if trendSink, isTrend := sink.(*metrics.TrendSink); isTrend {
+					trendSink.SetExactWindow(progressJSONTrendWindow)
+				}
+				pw.sinks[s.Metric.Name] = sink
+			}
+			sink.Add(s)
+
+			if s.Metric.Name != metrics.IterationsName {
+				continue
+			}
+			if scenario, ok := s.Tags.Get("scenario"); ok {
+				pw.iterations[scenario] += uint64(s.Value)
+			}
+		}
+	}
+}
+
+// Stop writes the final progress, after all of the metrics of the test were
+// added, and stops the periodic updates.
+func (pw *progressJSONWriter) Stop() error {
+	close(pw.stop)
+	<-pw.stopped
+	pw.write()
+	return nil
+}
+
+func (pw *progressJSONWriter) write() {
+	data, err := json.Marshal(pw.update())
+	if err != nil {
+		pw.logger.WithError(err).Warn("Couldn't marshal the progress")
+		return
+	}
+	if _, err := pw.out.Write(append(data, '\n')); err != nil {
+		pw.logger.WithError(err).Warn("Couldn't write the progress")
+	}
+}
+
+func (pw *progressJSONWriter) update() progressUpdate {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	now := time.Now()
+	elapsed := pw.state.GetCurrentTestRunDuration()
+	update := progressUpdate{
+		Time:                  now,
+		Status:                pw.state.GetCurrentExecutionStatus().String(),
+		Elapsed:               elapsed.Seconds(),
+		ETA:                   max(pw.maxDuration-elapsed, 0).Seconds(),
+		VUs:                   pw.state.GetCurrentlyActiveVUsCount(),
+		VUsMax:                pw.state.GetInitializedVUsCount(),
+		Iterations:            pw.state.GetFullIterationCount(),
+		InterruptedIterations: pw.state.GetPartialIterationCount(),
+		Scenarios:             make([]scenarioProgress, 0, len(pw.executors)),
+		Metrics:               make(map[string]map[string]float64, len(pw.sinks)),
+	}
+	if pw.state.HasEnded() {
+		update.ETA = 0
+	}
+
+	sinceLastUpdate := now.Sub(pw.lastUpdate).Seconds()
+	for _, executor := range pw.executors {
+		config := executor.GetConfig()
+		name := config.GetName()
+		progress, status := executor.GetProgress().Progress()
+		scenario := scenarioProgress{
+			Name:       name,
+			Executor:   config.GetType(),
+			Status:     progressStatusName(status),
+			Progress:   progress,
+			VUs:        pw.state.GetScenarioActiveVUsCount(name),
+			Iterations: pw.iterations[name],
+		}
+		if sinceLastUpdate > 0 {
+			scenario.IterationRate = float64(pw.iterations[name]-pw.lastIterations[name]) / sinceLastUpdate
+		}
+		pw.lastIterations[name] = pw.iterations[name]
+		update.Scenarios = append(update.Scenarios, scenario)
+	}
+	pw.lastUpdate = now
+
+	for name, sink := range pw.sinks {
+		update.Metrics[name] = sink.Format(elapsed)
+	}
+	return update
+}
+
+// progressStatusName returns the name of the status of the progress bar of a
+// scenario. The scenarios that haven't started yet don't have one.
+func progressStatusName(status pb.Status) string {
+	switch status {
+	case pb.Running:
+		return "running"
+	case pb.Stopping:
+		return "stopping"
+	case pb.Interrupted:
+		return "interrupted"
+	case pb.Done:
+		return "done"
+	default:
+		return "waiting"
+	}
+}
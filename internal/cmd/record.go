@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/internal/lib/recorder"
+	"go.k6.io/k6/lib/fsext"
+)
+
+const defaultRecordCAPath = "k6-record-ca.pem"
+
+// cmdRecord handles the `k6 record` sub-command
+type cmdRecord struct {
+	gs *state.GlobalState
+
+	proxyAddr      string
+	output         string
+	overwriteFile  bool
+	caCert         string
+	caKey          string
+	saveCA         string
+	includeHosts   []string
+	excludeHeaders []string
+	groupGap       time.Duration
+}
+
+func (c *cmdRecord) flagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.SortFlags = false
+	flags.StringVar(&c.proxyAddr, "proxy", ":8080", "address the recording proxy listens on")
+	flags.StringVarP(&c.output, "output", "O", defaultNewScriptName, "path of the generated script, - for stdout")
+	flags.BoolVarP(&c.overwriteFile, "force", "f", false, "overwrite the script if it exists")
+	flags.StringVar(&c.caCert, "ca-cert", "", "PEM file of the CA certificate that signs the intercepted HTTPS hosts")
+	flags.StringVar(&c.caKey, "ca-key", "", "PEM file of the private key of the CA")
+	flags.StringVar(&c.saveCA, "save-ca", defaultRecordCAPath,
+		"path where the generated CA certificate is saved, when --ca-cert isn't used")
+	flags.StringSliceVar(&c.includeHosts, "include-host", nil,
+		"only record the requests to the `hosts` matching the patterns, like *.example.com")
+	flags.StringSliceVar(&c.excludeHeaders, "exclude-header", nil,
+		"request `headers` that aren't included in the script, besides the ones that are always pruned")
+	flags.DurationVar(&c.groupGap, "group-gap", recorder.DefaultGroupGap,
+		"idle time after which the requests are put in a new group")
+	return flags
+}
+
+func (c *cmdRecord) run(_ *cobra.Command, _ []string) error {
+	if c.output != "-" {
+		exists, err := fsext.Exists(c.gs.FS, c.output)
+		if err != nil {
+			return err
+		}
+		if exists && !c.overwriteFile {
+			return fmt.Errorf("%s already exists. Use the `--force` flag to overwrite it", c.output)
+		}
+	}
+
+	ca, err := c.loadCA()
+	if err != nil {
+		return err
+	}
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return errors.New("unexpected default HTTP transport")
+	}
+	transport = transport.Clone()
+	transport.Proxy = nil
+	transport.DisableCompression = true
+	proxy, err := recorder.NewProxy(ca, transport, c.gs.Logger)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", c.proxyAddr)
+	if err != nil {
+		return fmt.Errorf("couldn't start the recording proxy: %w", err)
+	}
+	srv := &http.Server{Handler: proxy, ReadHeaderTimeout: time.Minute}
+	srvErr := make(chan error, 1)
+	go func() { srvErr <- srv.Serve(ln) }()
+
+	printToStdout(c.gs, fmt.Sprintf(
+		"Recording through the proxy on %s, configure it as the HTTP and HTTPS proxy of the browser or the app.\n"+
+			"Press Ctrl+C to stop the recording and generate the script.\n", ln.Addr()))
+
+	sigC := make(chan os.Signal, 1)
+	c.gs.SignalNotify(sigC, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer c.gs.SignalStop(sigC)
+	select {
+	case <-sigC:
+	case <-c.gs.Ctx.Done():
+	case err = <-srvErr:
+		return fmt.Errorf("the recording proxy failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = srv.Shutdown(ctx)
+
+	entries := proxy.Entries()
+	script, err := recorder.GenerateScript(entries, recorder.ScriptOptions{
+		IncludeHosts:   c.includeHosts,
+		ExcludeHeaders: c.excludeHeaders,
+		GroupGap:       c.groupGap,
+	})
+	if err != nil {
+		return err
+	}
+	if c.output == "-" {
+		printToStdout(c.gs, string(script))
+		return nil
+	}
+	if err := fsext.WriteFile(c.gs.FS, c.output, script, 0o644); err != nil {
+		return err
+	}
+	printToStdout(c.gs, fmt.Sprintf("The script was generated from %d recorded requests to %s\n", len(entries), c.output))
+	return nil
+}
+
+// loadCA loads the CA of --ca-cert and --ca-key, or generates a new one and
+// saves its certificate, so that the browser or the app can trust it.
+func (c *cmdRecord) loadCA() (ca tls.Certificate, err error) {
+	if (c.caCert == "") != (c.caKey == "") {
+		return ca, errors.New("both --ca-cert and --ca-key have to be used")
+	}
+
+	var certPEM, keyPEM []byte
+	if c.caCert != "" {
+		if certPEM, err = fsext.ReadFile(c.gs.FS, c.caCert); err != nil {
+			return ca, fmt.Errorf("couldn't read the CA certificate: %w", err)
+		}
+		if keyPEM, err = fsext.ReadFile(c.gs.FS, c.caKey); err != nil {
+			return ca, fmt.Errorf("couldn't read the CA private key: %w", err)
+		}
+		return recorder.LoadCA(certPEM, keyPEM)
+	}
+
+	if certPEM, keyPEM, err = recorder.GenerateCA(); err != nil {
+		return ca, err
+	}
+	if err = fsext.WriteFile(c.gs.FS, c.saveCA, certPEM, 0o644); err != nil {
+		return ca, fmt.Errorf("couldn't save the CA certificate: %w", err)
+	}
+	printToStdout(c.gs, fmt.Sprintf(
+		"The HTTPS requests are intercepted with the CA certificate saved to %s, "+
+			"the browser or the app has to trust it.\n", c.saveCA))
+	return recorder.LoadCA(certPEM, keyPEM)
+}
+
+func getCmdRecord(gs *state.GlobalState) *cobra.Command {
+	c := &cmdRecord{gs: gs}
+
+	exampleText := getExampleText(gs, `
+  # Record the requests of a browser, configured with the proxy localhost:8080, to script.js.
+  {{.}} record --proxy :8080 --output script.js
+
+  # Only record the requests to example.com and its subdomains.
+  {{.}} record --include-host example.com --include-host '*.example.com'`[1:])
+
+	recordCmd := &cobra.Command{
+		Use:   "record",
+		Short: "Record the requests of a browser or an app to a script",
+		Long: `Record the requests of a browser or an app to a script.
+
+A local proxy records the requests sent through it, until k6 is interrupted, then a script that
+sends them is generated. The requests are grouped by the pages they were sent for, with the think
+time between them, and the headers that k6 sets itself, like the cookies, are pruned. The URLs of
+each recorded origin start with a constant, like BASE_URL, that can be changed with an
+environment variable of the same name.
+
+The HTTPS requests are intercepted with certificates signed by a CA, that the browser or the app
+has to trust. A new CA is generated unless --ca-cert and --ca-key are used.`,
+		Example: exampleText,
+		Args:    cobra.NoArgs,
+		RunE:    c.run,
+	}
+	recordCmd.Flags().AddFlagSet(c.flagSet())
+	return recordCmd
+}
@@ -78,9 +78,9 @@ func newRootCommand(gs *state.GlobalState) *rootCommand {
 	rootCmd.SetIn(gs.Stdin)
 
 	subCommands := []func(*state.GlobalState) *cobra.Command{
-		getCmdArchive, getCmdCloud, getCmdNewScript, getCmdInspect,
-		getCmdLogin, getCmdPause, getCmdResume, getCmdScale, getCmdRun,
-		getCmdStats, getCmdStatus, getCmdVersion,
+		getCmdArchive, getCmdCloud, getCmdCompare, getCmdNewScript, getCmdInspect,
+		getCmdLogin, getCmdPause, getCmdRecord, getCmdResume, getCmdScale, getCmdRun,
+		getCmdStats, getCmdStatus, getCmdSuite, getCmdVersion,
 	}
 
 	for _, sc := range subCommands {
@@ -219,7 +219,8 @@ func rootCmdPersistentFlagSet(gs *state.GlobalState) *pflag.FlagSet {
 
 	// TODO(@mstoykov): likely needs work - no env variables and such. No config.json.
 	flags.StringArrayVar(&gs.Flags.SecretSource, "secret-source", gs.Flags.SecretSource,
-		"setting secret sources for k6 file[=./path.fileformat],")
+		"setting secret sources for k6, possible values are: "+
+			"'file[=./path.fileformat]', 'url[=urlTemplate=...]', 'vault=...', 'aws-secretsmanager=...'")
 
 	flags.StringVar(&gs.Flags.LogOutput, "log-output", gs.Flags.LogOutput,
 		"change the output for k6 logs, possible values are: "+
@@ -229,12 +230,16 @@ func rootCmdPersistentFlagSet(gs *state.GlobalState) *pflag.FlagSet {
 	flags.StringVar(&gs.Flags.LogFormat, "log-format", gs.Flags.LogFormat, "log output format")
 	flags.Lookup("log-format").DefValue = gs.DefaultFlags.LogFormat
 
-	flags.StringVarP(&gs.Flags.ConfigFilePath, "config", "c", gs.Flags.ConfigFilePath, "JSON config file")
+	flags.StringVarP(&gs.Flags.ConfigFilePath, "config", "c", gs.Flags.ConfigFilePath, "JSON, YAML or TOML config file")
 	// And we also need to explicitly set the default value for the usage message here, so things
 	// like `K6_CONFIG="blah" k6 run -h` don't produce a weird usage message
 	flags.Lookup("config").DefValue = gs.DefaultFlags.ConfigFilePath
 	must(cobra.MarkFlagFilename(flags, "config"))
 
+	flags.StringVar(&gs.Flags.ConfigProfile, "profile", gs.Flags.ConfigProfile,
+		"name of the profile from the config file to apply on top of its base options")
+	flags.Lookup("profile").DefValue = gs.DefaultFlags.ConfigProfile
+
 	flags.BoolVar(&gs.Flags.NoColor, "no-color", gs.Flags.NoColor, "disable colored output")
 	flags.Lookup("no-color").DefValue = strconv.FormatBool(gs.DefaultFlags.NoColor)
 
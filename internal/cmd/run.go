@@ -35,6 +35,7 @@ import (
 	"go.k6.io/k6/lib/fsext"
 	"go.k6.io/k6/metrics"
 	"go.k6.io/k6/output"
+	"go.k6.io/k6/secretsource"
 )
 
 // cmdRun handles the `k6 run` sub-command
@@ -43,6 +44,21 @@ type cmdRun struct {
 
 	// TODO: figure out something more elegant?
 	loadConfiguredTest func(cmd *cobra.Command, args []string) (*loadedAndConfiguredTest, execution.Controller, error)
+
+	checkpointPath     string
+	checkpointInterval time.Duration
+	resumePath         string
+	setupCachePath     string
+	setupCacheTTL      time.Duration
+	scriptProfilePath  string
+	stopReasonPath     string
+	watch              bool
+	progressFormat     string
+	progressFile       string
+
+	prometheusScrape        bool
+	prometheusScrapeMetrics []string
+	prometheusScrapeTags    []string
 }
 
 const (
@@ -68,6 +84,7 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 		} else {
 			logger.WithError(err).Debug("Everything has finished, exiting k6 with an error!")
 		}
+		c.saveStopReason(err, logger)
 	}()
 
 	globalCtx, globalCancel := context.WithCancel(c.gs.Ctx)
@@ -103,6 +120,22 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 		c.gs.Events.UnsubscribeAll()
 	}()
 
+	// The progress format is empty with the commands that don't have the flag,
+	// like the local execution of k6 cloud run.
+	if c.progressFormat != "" && c.progressFormat != progressFormatText && c.progressFormat != progressFormatJSON {
+		return errext.WithExitCodeIfNone(
+			fmt.Errorf("unsupported --progress-format %q, it should be %s or %s",
+				c.progressFormat, progressFormatText, progressFormatJSON),
+			exitcodes.InvalidConfig,
+		)
+	}
+	if c.progressFile != "" && c.progressFormat != progressFormatJSON {
+		return errext.WithExitCodeIfNone(
+			errors.New("--progress-file can only be used with --progress-format json"),
+			exitcodes.InvalidConfig,
+		)
+	}
+
 	test, controller, err := c.loadConfiguredTest(cmd, args)
 	if err != nil {
 		return err
@@ -127,6 +160,11 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 		}
 	}
 
+	checkpointBase, err := c.prepareCheckpoint(test)
+	if err != nil {
+		return err
+	}
+
 	// Write the full consolidated *and derived* options back to the Runner.
 	conf := test.derivedConfig
 	testRunState, err := test.buildTestRunState(conf.Options)
@@ -134,6 +172,18 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
+	if c.setupCachePath != "" {
+		testHash, err := hashArchive(testRunState.Runner.MakeArchive())
+		if err != nil {
+			return err
+		}
+		controller, err = newSetupCacheController(
+			controller, c.gs.FS, c.setupCachePath, c.setupCacheTTL, testHash, logger)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create a local execution scheduler wrapping the runner.
 	logger.Debug("Initializing the execution scheduler...")
 	execScheduler, err := execution.NewScheduler(testRunState, controller)
@@ -152,15 +202,20 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 	defer progressCancel()
 
 	initBar := execScheduler.GetInitProgressBar()
-	backgroundProcesses.Add(1)
-	go func() {
-		defer backgroundProcesses.Done()
-		pbs := []*pb.ProgressBar{initBar}
-		for _, s := range execScheduler.GetExecutors() {
-			pbs = append(pbs, s.GetProgress())
-		}
-		showProgress(progressCtx, c.gs, pbs, logger)
-	}()
+	// The terminal dashboard draws its own live view, which would fight with
+	// the progress bars for the same lines of the terminal. The JSON progress
+	// is written by an output instead, since it needs the metrics.
+	if c.progressFormat != progressFormatJSON && !hasOutput(conf.Out, builtinOutputDashboard) {
+		backgroundProcesses.Add(1)
+		go func() {
+			defer backgroundProcesses.Done()
+			pbs := []*pb.ProgressBar{initBar}
+			for _, s := range execScheduler.GetExecutors() {
+				pbs = append(pbs, s.GetProgress())
+			}
+			showProgress(progressCtx, c.gs, pbs, logger)
+		}()
+	}
 
 	// Create all outputs.
 	executionPlan := execScheduler.GetExecutionPlan()
@@ -170,6 +225,49 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	outputs = append(outputs, testRunState.GroupSummary)
+	var scrapeOutput *api.ScrapeOutput
+	if c.prometheusScrape {
+		if c.gs.Flags.Address == "" {
+			return errext.WithExitCodeIfNone(
+				errors.New("--prometheus-scrape requires the REST API, which was disabled with an empty --address"),
+				exitcodes.InvalidConfig,
+			)
+		}
+		scrapeOutput = api.NewScrapeOutput(api.ScrapeConfig{
+			Metrics: c.prometheusScrapeMetrics,
+			Tags:    c.prometheusScrapeTags,
+		}, c.gs.Flags.Address, logger)
+		outputs = append(outputs, scrapeOutput)
+	}
+	if c.progressFormat == progressFormatJSON {
+		// The progress is kept apart from the banner and the summary on
+		// stdout, so the JSON lines can be parsed without filtering them.
+		var progressOut io.Writer = c.gs.Stderr
+		if c.progressFile != "" {
+			f, err := c.gs.FS.OpenFile(c.progressFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return fmt.Errorf("couldn't create the progress file: %w", err)
+			}
+			// It's closed after the outputs are stopped, since the defers run
+			// in the reverse order.
+			defer func() {
+				if cerr := f.Close(); cerr != nil {
+					logger.WithError(cerr).Warn("Couldn't close the progress file")
+				}
+			}()
+			progressOut = f
+		}
+		outputs = append(outputs, newProgressJSONWriter(
+			progressOut, execScheduler.GetState(), execScheduler.GetExecutors(), executionPlan,
+			time.Second, logger,
+		))
+	}
+	if checkpointBase != nil {
+		outputs = append(outputs, newCheckpointWriter(
+			c.gs.FS, c.checkpointPath, c.checkpointInterval, checkpointBase,
+			execScheduler.GetState().GetCurrentTestRunDuration, logger,
+		))
+	}
 
 	metricsEngine, err := engine.NewMetricsEngine(testRunState.Registry, logger)
 	if err != nil {
@@ -188,13 +286,14 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 
 	// We'll need to pipe metrics to the MetricsEngine and process them if any
 	// of these are enabled: thresholds, end-of-test summary
-	shouldProcessMetrics := summaryEnabled || thresholdsEnabled
+	shouldProcessMetrics := summaryEnabled || thresholdsEnabled || conf.ErrorBudget != nil
 	var metricsIngester *engine.OutputIngester
 	if shouldProcessMetrics {
 		err = metricsEngine.InitSubMetricsAndThresholds(conf.Options, !thresholdsEnabled)
 		if err != nil {
 			return err
 		}
+		metricsEngine.InitErrorBudget(conf.Options)
 		// We'll need to pipe metrics to the MetricsEngine if either the
 		// thresholds or the end-of-test summary are enabled.
 		metricsIngester = metricsEngine.CreateIngester()
@@ -219,6 +318,8 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 					IsStdOutTTY: c.gs.Stdout.IsTTY,
 					IsStdErrTTY: c.gs.Stderr.IsTTY,
 				},
+				RunMetadata: testRunState.RunMetadata.Map(),
+				Abort:       getScriptAbort(runCtx),
 			}
 		}
 
@@ -240,6 +341,9 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 				logger.Debug("Generating the end-of-test summary...")
 
 				summaryResult, hsErr := test.initRunner.HandleSummary(globalCtx, legacySummary(), nil, summaryMeta)
+				if hsErr == nil {
+					summaryResult, hsErr = redactSummaryResult(c.gs.SecretsManager, summaryResult)
+				}
 				if hsErr == nil {
 					hsErr = handleSummaryResult(c.gs.FS, c.gs.Stdout, c.gs.Stderr, summaryResult)
 				}
@@ -251,6 +355,7 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 			// Instantiates the summary output
 			summaryOutput, err := summaryoutput.New(output.Params{
 				RuntimeOptions: testRunState.RuntimeOptions,
+				ScriptOptions:  conf.Options,
 				Logger:         c.gs.Logger,
 			})
 			if err != nil {
@@ -276,6 +381,9 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 					testRunState.RuntimeOptions.NewMachineReadableSummary.Bool
 
 				summaryResult, hsErr := test.initRunner.HandleSummary(globalCtx, legacySummary(), summary, summaryMeta)
+				if hsErr == nil {
+					summaryResult, hsErr = redactSummaryResult(c.gs.SecretsManager, summaryResult)
+				}
 				if hsErr == nil {
 					hsErr = handleSummaryResult(c.gs.FS, c.gs.Stdout, c.gs.Stderr, summaryResult)
 				}
@@ -299,6 +407,7 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 		// TODO: attach run status and exit code?
 		runAbort(err)
 	})
+	outputManager.SetDroppedSamplesMetric(testRunState.BuiltinMetrics.MetricsDropped, testRunState.RunTags)
 	samples := make(chan metrics.SampleContainer, test.derivedConfig.MetricSamplesBufferSize.Int64)
 	// Spin up the REST API server, if not disabled.
 	if c.gs.Flags.Address != "" { //nolint:nestif
@@ -321,6 +430,7 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 			samples,
 			metricsEngine,
 			execScheduler,
+			scrapeOutput,
 		)
 		go func() {
 			defer apiWG.Done()
@@ -349,7 +459,7 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 		}()
 	}
 
-	waitOutputsFlushed, stopOutputs, err := outputManager.Start(samples)
+	waitOutputsFlushed, stopOutputs, err := outputManager.StartWithStopErrors(samples)
 	if err != nil {
 		return err
 	}
@@ -359,9 +469,33 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 		// need all of the metrics to be sent to the MetricsEngine before we can
 		// calculate them one last time. We need the threshold calculated here,
 		// since they may change the run status for the outputs.
-		stopOutputs(err)
+		if oErr := stopOutputs(err); oErr != nil && err == nil {
+			err = errext.WithExitCodeIfNone(
+				fmt.Errorf("some outputs failed to flush their metrics: %w", oErr), exitcodes.OutputFailed,
+			)
+		}
 	}()
 
+	// The error budget is finalized after the thresholds, since they stop the
+	// ingester and so the last metrics are counted.
+	finalizeErrorBudget := metricsEngine.StartErrorBudgetCalculations(
+		metricsIngester, runAbort, executionState.GetFailedIterationCount,
+	)
+	if finalizeErrorBudget != nil {
+		defer func() {
+			logger.Debug("Finalizing the error budget...")
+			bErr := finalizeErrorBudget()
+			if bErr == nil {
+				return
+			}
+			if err == nil {
+				err = bErr
+			} else {
+				logger.WithError(bErr).Debug("Exhausted the error budget, but test already exited with another error")
+			}
+		}()
+	}
+
 	if thresholdsEnabled {
 		finalizeThresholds := metricsEngine.StartThresholdCalculations(
 			metricsIngester, runAbort, executionState.GetCurrentTestRunDuration,
@@ -466,9 +600,22 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 	waitTestStartDone := emitEvent(&event.Event{Type: event.TestStart})
 	waitTestStartDone()
 
+	stopScriptProfile, err := c.startScriptProfile(logger)
+	if err != nil {
+		return err
+	}
+	stopWatch, err := c.startWatch(globalCtx, test, logger)
+	if err != nil {
+		stopScriptProfile()
+		return err
+	}
+
 	// Start the test! However, we won't immediately return if there was an
 	// error, we still have things to do.
 	err = execScheduler.Run(globalCtx, runCtx, samples)
+	stopWatch()
+	stopScriptProfile()
+	c.saveHTTPCassette(test.initRunner, logger)
 
 	waitTestEndDone := emitEvent(&event.Event{Type: event.TestEnd})
 	defer waitTestEndDone()
@@ -519,6 +666,16 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 	return nil
 }
 
+// getScriptAbort returns the error of the test.abort() call that aborted the
+// test run, if it was aborted that way.
+func getScriptAbort(runCtx context.Context) *errext.InterruptError {
+	var interruptErr *errext.InterruptError
+	if errors.As(execution.GetCancelReasonIfTestAborted(runCtx), &interruptErr) {
+		return interruptErr
+	}
+	return nil
+}
+
 func getSummaryMode(runtimeOptions lib.RuntimeOptions) (summary.Mode, bool, error) {
 	if runtimeOptions.NoSummary.Bool {
 		return summary.ModeDisabled, false, nil
@@ -538,6 +695,39 @@ func (c *cmdRun) flagSet() *pflag.FlagSet {
 	flags.AddFlagSet(optionFlagSet())
 	flags.AddFlagSet(runtimeOptionFlagSet(true))
 	flags.AddFlagSet(configFlagSet())
+	flags.StringVar(&c.checkpointPath, "checkpoint", "",
+		"periodically save the progress of the test to `file`, so it can be continued with --resume if interrupted")
+	flags.DurationVar(&c.checkpointInterval, "checkpoint-interval", 30*time.Second,
+		"how often the checkpoint is saved")
+	flags.StringVar(&c.resumePath, "resume", "",
+		"continue an interrupted test from the checkpoint `file`, which is then also updated as the test continues. "+
+			"The state of the random generators isn't saved, so with randomSeed the resumed test repeats the "+
+			"random values from the start, and the completed iterations of per-vu-iterations scenarios are "+
+			"assumed to be evenly spread between their VUs")
+	flags.StringVar(&c.setupCachePath, "setup-cache", "",
+		"save the data returned by setup() to `file` and reuse it in the next runs of the same test, "+
+			"instead of running setup(), and then skip teardown()")
+	flags.DurationVar(&c.setupCacheTTL, "setup-cache-ttl", time.Hour,
+		"how long the cached setup() data is reused for")
+	flags.StringVar(&c.scriptProfilePath, "script-profile", "",
+		"sample the execution of the script in all VUs and save the time spent per function to `file`, "+
+			"as pprof or, if it ends with "+foldedProfileExt+", as folded stacks for flamegraphs")
+	flags.StringVar(&c.stopReasonPath, "stop-reason-file", "",
+		"save why the test stopped, with its exit code, to a JSON `file`")
+	flags.StringVar(&c.progressFormat, "progress-format", progressFormatText,
+		"how the progress of the test is shown, as progress bars with `text` or as JSON lines on stderr "+
+			"with json, which are written even with --quiet")
+	flags.StringVar(&c.progressFile, "progress-file", "",
+		"write the JSON lines of --progress-format json to `file` instead of stderr")
+	flags.BoolVar(&c.watch, "watch", false,
+		"reload the script when its files change, so the next iterations of the VUs run the new code, "+
+			"for developing scripts with few VUs")
+	flags.BoolVar(&c.prometheusScrape, "prometheus-scrape", false,
+		"expose the metrics of the test in the Prometheus exposition format on the /metrics endpoint of the REST API")
+	flags.StringSliceVar(&c.prometheusScrapeMetrics, "prometheus-scrape-metrics", nil,
+		"only expose these metrics on the Prometheus scrape endpoint, all of them by default")
+	flags.StringSliceVar(&c.prometheusScrapeTags, "prometheus-scrape-tags", nil,
+		"only keep these tags as labels on the Prometheus scrape endpoint, all of them by default")
 	return flags
 }
 
@@ -601,6 +791,22 @@ a commandline interface for interacting with it.`,
 	return runCmd
 }
 
+// redactSummaryResult replaces any secrets in the handleSummary() results, so
+// they aren't leaked in the end-of-test summary or the files it's saved to.
+func redactSummaryResult(
+	sm *secretsource.Manager, result map[string]io.Reader,
+) (map[string]io.Reader, error) {
+	redacted := make(map[string]io.Reader, len(result))
+	for path, value := range result {
+		data, err := io.ReadAll(value)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the summary for '%s': %w", path, err)
+		}
+		redacted[path] = strings.NewReader(sm.Redact(string(data)))
+	}
+	return redacted, nil
+}
+
 func handleSummaryResult(fs fsext.Fs, stdOut, stdErr io.Writer, result map[string]io.Reader) error {
 	var errs []error
 
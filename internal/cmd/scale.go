@@ -17,7 +17,9 @@ func getCmdScale(gs *state.GlobalState) *cobra.Command {
 		Short: "Scale a running test",
 		Long: `Scale a running test.
 
-  Use the global --address flag to specify the URL to the API server.`,
+  Use the global --address flag to specify the URL to the API server.
+  By default, the first externally-controlled scenario is scaled, use
+  --scenario to scale a specific one.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			vus := getNullInt64(cmd.Flags(), "vus")
 			maxVUs := getNullInt64(cmd.Flags(), "max")
@@ -29,6 +31,13 @@ func getCmdScale(gs *state.GlobalState) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if scenario, _ := cmd.Flags().GetString("scenario"); scenario != "" {
+				sc, err := c.SetScenario(gs.Ctx, scenario, v1.Scenario{VUs: vus, VUsMax: maxVUs})
+				if err != nil {
+					return err
+				}
+				return yamlPrint(gs.Stdout, sc)
+			}
 			status, err := c.SetStatus(gs.Ctx, v1.Status{VUs: vus, VUsMax: maxVUs})
 			if err != nil {
 				return err
@@ -40,6 +49,7 @@ func getCmdScale(gs *state.GlobalState) *cobra.Command {
 
 	scaleCmd.Flags().Int64P("vus", "u", 1, "number of virtual users")
 	scaleCmd.Flags().Int64P("max", "m", 0, "max available virtual users")
+	scaleCmd.Flags().String("scenario", "", "name of the externally-controlled scenario to scale")
 
 	return scaleCmd
 }
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/pprof/profile"
+	"github.com/grafana/sobek"
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+// foldedProfileExt is the extension of the script profiles that are saved as
+// folded stacks, which most flamegraph tools can read, instead of pprof.
+const foldedProfileExt = ".folded"
+
+// startScriptProfile starts sampling the execution of the JS code in all VUs,
+// if a script profile was requested. The returned function stops the sampling
+// and saves the merged profile.
+func (c *cmdRun) startScriptProfile(logger logrus.FieldLogger) (func(), error) {
+	if c.scriptProfilePath == "" {
+		return func() {}, nil
+	}
+
+	buf := &bytes.Buffer{}
+	if err := sobek.StartProfile(buf); err != nil {
+		return nil, fmt.Errorf("couldn't start the script profile: %w", err)
+	}
+	return func() {
+		sobek.StopProfile()
+		if err := saveScriptProfile(c.gs.FS, c.scriptProfilePath, buf.Bytes()); err != nil {
+			logger.WithError(err).Error("Couldn't save the script profile")
+			return
+		}
+		logger.Infof("The script profile was saved to %s", c.scriptProfilePath)
+	}, nil
+}
+
+func saveScriptProfile(fs fsext.Fs, path string, data []byte) error {
+	if filepath.Ext(path) == foldedProfileExt {
+		p, err := profile.ParseData(data)
+		if err != nil {
+			return err
+		}
+		folded := &bytes.Buffer{}
+		if err = writeFoldedStacks(folded, p); err != nil {
+			return err
+		}
+		data = folded.Bytes()
+	}
+	return fsext.WriteFile(fs, path, data, 0o644)
+}
+
+// writeFoldedStacks writes the profile as one line per distinct stack with the
+// function names from the root to the leaf, separated by semicolons, followed
+// by the execution time in microseconds.
+func writeFoldedStacks(w io.Writer, p *profile.Profile) error {
+	valueIdx := len(p.SampleType) - 1 // the execution time is the last value
+	totals := make(map[string]int64)
+	for _, s := range p.Sample {
+		if valueIdx < 0 || valueIdx >= len(s.Value) {
+			continue
+		}
+		names := make([]string, 0, len(s.Location))
+		for i := len(s.Location) - 1; i >= 0; i-- { // locations are from the leaf to the root
+			for _, line := range s.Location[i].Line {
+				if line.Function != nil {
+					names = append(names, strings.ReplaceAll(line.Function.Name, ";", ":"))
+				}
+			}
+		}
+		if len(names) > 0 {
+			totals[strings.Join(names, ";")] += s.Value[valueIdx]
+		}
+	}
+
+	stacks := make([]string, 0, len(totals))
+	for stack := range totals {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+	for _, stack := range stacks {
+		if _, err := fmt.Fprintf(w, "%s %d\n", stack, totals[stack]/1000); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFoldedStacks(t *testing.T) {
+	t.Parallel()
+
+	fn := func(name string) *profile.Location {
+		return &profile.Location{Line: []profile.Line{{Function: &profile.Function{Name: name}}}}
+	}
+	def, expensive, check := fn("default"), fn("expensive"), fn("check;it")
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{{Type: "samples", Unit: "count"}, {Type: "cpu", Unit: "nanoseconds"}},
+		Sample: []*profile.Sample{
+			{Location: []*profile.Location{expensive, def}, Value: []int64{1, 10000}},
+			{Location: []*profile.Location{def}, Value: []int64{1, 3000}},
+			{Location: []*profile.Location{expensive, def}, Value: []int64{1, 5000}},
+			{Location: []*profile.Location{check, def}, Value: []int64{1, 1000}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeFoldedStacks(buf, p))
+	assert.Equal(t, "default 3\ndefault;check:it 1\ndefault;expensive 15\n", buf.String())
+}
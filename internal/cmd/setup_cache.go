@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/internal/execution"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+)
+
+const (
+	setupCacheVersion = 1
+	// setupDataID and teardownDataID are the IDs of the setup() data and of
+	// the teardown() run in the execution.Controller.
+	setupDataID    = "setup"
+	teardownDataID = "teardown"
+)
+
+// setupCache is the data returned by setup(), saved with --setup-cache, so
+// that the next runs of the same test can reuse it instead of running setup()
+// again, until it's older than the --setup-cache-ttl.
+type setupCache struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	// TestHash identifies the test the cache is for, see hashArchive().
+	TestHash string `json:"testHash"`
+	// Data is the JSON of the setup() data, it's missing if setup() didn't
+	// return anything or isn't exported.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// setupCacheController is an execution.Controller that gets the setup() data
+// from the cache, if it's still valid, and saves it there after setup() is run.
+// The teardown() is skipped when the data is reused, since it would clean up
+// what the cached data refers to and the next runs would get it broken.
+type setupCacheController struct {
+	execution.Controller
+
+	fs       fsext.Fs
+	path     string
+	ttl      time.Duration
+	testHash string
+	logger   logrus.FieldLogger
+	reused   bool
+}
+
+func newSetupCacheController(
+	controller execution.Controller, fileSystem fsext.Fs, path string, ttl time.Duration, testHash string,
+	logger logrus.FieldLogger,
+) (*setupCacheController, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("the setup cache TTL should be positive but was %s", ttl)
+	}
+	return &setupCacheController{
+		Controller: controller,
+		fs:         fileSystem,
+		path:       path,
+		ttl:        ttl,
+		testHash:   testHash,
+		logger:     logger.WithField("setupCache", path),
+	}, nil
+}
+
+// hashArchive returns the hash that identifies a test for the setup cache: its
+// options, environment variables, main script and all the other files it
+// loaded, so a change in any of them invalidates the cache.
+func hashArchive(arc *lib.Archive) (string, error) {
+	metadata, err := json.Marshal(arc)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	writeEntry := func(name string, data []byte) {
+		_, _ = fmt.Fprintf(hash, "%s\x00%d\x00", name, len(data))
+		_, _ = hash.Write(data)
+	}
+	writeEntry("metadata", metadata)
+	writeEntry("data", arc.Data)
+
+	fsNames := make([]string, 0, len(arc.Filesystems))
+	for name := range arc.Filesystems {
+		fsNames = append(fsNames, name)
+	}
+	sort.Strings(fsNames)
+	for _, fsName := range fsNames {
+		filesystem := arc.Filesystems[fsName]
+		if cachedfs, ok := filesystem.(fsext.CacheLayerGetter); ok {
+			filesystem = cachedfs.GetCachingFs()
+		}
+		files := make(map[string][]byte)
+		err := fsext.Walk(filesystem, fsext.FilePathSeparator, func(path string, info fs.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			files[path], err = fsext.ReadFile(filesystem, path)
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+		paths := make([]string, 0, len(files))
+		for path := range files {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			writeEntry(fsName+"://"+path, files[path])
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GetOrCreateData returns the cached setup() data instead of calling the
+// callback, which runs setup(), if the cache is for the same test and hasn't
+// expired, and then skips the teardown(). The other data is passed through to
+// the wrapped controller.
+func (c *setupCacheController) GetOrCreateData(id string, callback func() ([]byte, error)) ([]byte, error) {
+	switch {
+	case id == teardownDataID && c.reused:
+		return c.Controller.GetOrCreateData(id, func() ([]byte, error) {
+			c.logger.Warn("Skipping teardown(), since the setup() data was reused from the cache")
+			return nil, nil
+		})
+	case id != setupDataID:
+		return c.Controller.GetOrCreateData(id, callback)
+	}
+	return c.Controller.GetOrCreateData(id, func() ([]byte, error) {
+		cache, err := c.read()
+		if err != nil {
+			c.logger.WithError(err).Warn("Couldn't read the setup cache, running setup()")
+		} else if cache != nil {
+			c.logger.Infof("Reusing the setup() data cached %s ago", time.Since(cache.Time).Round(time.Second))
+			c.reused = true
+			return cache.Data, nil
+		}
+
+		data, err := callback()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.write(data); err != nil {
+			c.logger.WithError(err).Warn("Couldn't save the setup cache")
+		}
+		return data, nil
+	})
+}
+
+// read returns the cache, or nil if there isn't one, it's for another test or
+// it has expired.
+func (c *setupCacheController) read() (*setupCache, error) {
+	data, err := fsext.ReadFile(c.fs, c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil //nolint:nilnil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cache := &setupCache{}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("couldn't parse the setup cache: %w", err)
+	}
+	switch {
+	case cache.Version != setupCacheVersion:
+		c.logger.Debugf("The setup cache has unsupported version %d", cache.Version)
+		return nil, nil //nolint:nilnil
+	case cache.TestHash != c.testHash:
+		c.logger.Debug("The setup cache was saved for a different test")
+		return nil, nil //nolint:nilnil
+	case time.Since(cache.Time) > c.ttl:
+		c.logger.Debugf("The setup cache expired, it was saved at %s", cache.Time)
+		return nil, nil //nolint:nilnil
+	}
+	return cache, nil
+}
+
+// write saves the cache to a temporary file first and then renames it, like
+// the checkpoints, so an interruption doesn't leave a corrupted cache.
+func (c *setupCacheController) write(setupData []byte) error {
+	data, err := json.MarshalIndent(setupCache{
+		Version:  setupCacheVersion,
+		Time:     time.Now(),
+		TestHash: c.testHash,
+		Data:     setupData,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := fsext.WriteFile(c.fs, tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return c.fs.Rename(tmp, c.path)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/internal/execution/local"
+	"go.k6.io/k6/internal/lib/testutils"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestSetupCacheController(t *testing.T) {
+	t.Parallel()
+
+	fs := fsext.NewMemMapFs()
+	logger := testutils.NewLogger(t)
+	newController := func(testHash string) *setupCacheController {
+		c, err := newSetupCacheController(local.NewController(), fs, "/setup.json", time.Hour, testHash, logger)
+		require.NoError(t, err)
+		return c
+	}
+	setup := func(c *setupCacheController, result string) (data []byte, ran bool) {
+		data, err := c.GetOrCreateData(setupDataID, func() ([]byte, error) {
+			ran = true
+			return []byte(result), nil
+		})
+		require.NoError(t, err)
+		return data, ran
+	}
+
+	data, ran := setup(newController("test1"), `{"a":1}`)
+	assert.True(t, ran)
+	assert.JSONEq(t, `{"a":1}`, string(data))
+
+	data, ran = setup(newController("test1"), `{"a":2}`)
+	assert.False(t, ran)
+	assert.JSONEq(t, `{"a":1}`, string(data))
+
+	// the cache is only for the test it was saved for
+	data, ran = setup(newController("test2"), `{"a":3}`)
+	assert.True(t, ran)
+	assert.JSONEq(t, `{"a":3}`, string(data))
+
+	// only the setup() data is cached
+	c := newController("test2")
+	_, err := c.GetOrCreateData(teardownDataID, func() ([]byte, error) { return nil, nil })
+	require.NoError(t, err)
+	data, ran = setup(c, `{"a":4}`)
+	assert.False(t, ran)
+	assert.JSONEq(t, `{"a":3}`, string(data))
+
+	// the teardown() is skipped only when the data was reused
+	teardown := func(c *setupCacheController) (ran bool) {
+		_, err := c.GetOrCreateData(teardownDataID, func() ([]byte, error) {
+			ran = true
+			return nil, nil
+		})
+		require.NoError(t, err)
+		return ran
+	}
+	c = newController("test2")
+	_, ran = setup(c, `{"a":5}`)
+	assert.False(t, ran)
+	assert.False(t, teardown(c))
+	c = newController("test3")
+	_, ran = setup(c, `{"a":6}`)
+	assert.True(t, ran)
+	assert.True(t, teardown(c))
+
+	_, err = newSetupCacheController(local.NewController(), fs, "/setup.json", 0, "", logger)
+	require.ErrorContains(t, err, "the setup cache TTL should be positive")
+}
+
+func TestHashArchive(t *testing.T) {
+	t.Parallel()
+
+	newArchive := func() *lib.Archive {
+		fs := fsext.NewMemMapFs()
+		require.NoError(t, fsext.WriteFile(fs, "/lib.js", []byte("export const a = 1;"), 0o644))
+		return &lib.Archive{
+			Type:        "js",
+			Data:        []byte("import { a } from './lib.js';"),
+			Filesystems: map[string]fsext.Fs{"file": fs},
+			Env:         map[string]string{"A": "1"},
+		}
+	}
+	hash := func(arc *lib.Archive) string {
+		h, err := hashArchive(arc)
+		require.NoError(t, err)
+		return h
+	}
+
+	original := hash(newArchive())
+	assert.Equal(t, original, hash(newArchive()))
+
+	arc := newArchive()
+	require.NoError(t, fsext.WriteFile(arc.Filesystems["file"], "/lib.js", []byte("export const a = 2;"), 0o644))
+	assert.NotEqual(t, original, hash(arc), "a module changed")
+
+	arc = newArchive()
+	arc.Env["A"] = "2"
+	assert.NotEqual(t, original, hash(arc), "an environment variable changed")
+
+	arc = newArchive()
+	arc.Options.VUs = null.IntFrom(2)
+	assert.NotEqual(t, original, hash(arc), "the options changed")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// stopReason is the machine-readable explanation of why a test run stopped,
+// saved with --stop-reason-file.
+type stopReason struct {
+	Reason   string `json:"reason"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"`
+}
+
+// stopReasons are the reasons reported for the different exit codes.
+var stopReasons = map[exitcodes.ExitCode]string{ //nolint:gochecknoglobals
+	exitcodes.CloudTestRunFailed:       "cloud_test_run_failed",
+	exitcodes.CloudFailedToGetProgress: "cloud_test_run_failed",
+	exitcodes.ThresholdsHaveFailed:     "thresholds_failed",
+	exitcodes.SetupTimeout:             "setup_failed",
+	exitcodes.SetupFailed:              "setup_failed",
+	exitcodes.TeardownTimeout:          "teardown_failed",
+	exitcodes.TeardownFailed:           "teardown_failed",
+	exitcodes.GenericTimeout:           "timeout",
+	exitcodes.ScriptStoppedFromRESTAPI: "interrupted",
+	exitcodes.ExternalAbort:            "interrupted",
+	exitcodes.InvalidConfig:            "invalid_config",
+	exitcodes.CannotStartRESTAPI:       "rest_api_failed",
+	exitcodes.ScriptException:          "script_error",
+	exitcodes.GoPanic:                  "script_error",
+	exitcodes.ScriptAborted:            "script_aborted",
+	exitcodes.MarkedAsFailed:           "marked_as_failed",
+	exitcodes.OutputFailed:             "output_failed",
+	exitcodes.ErrorBudgetExhausted:     "error_budget_exhausted",
+}
+
+func newStopReason(err error) stopReason {
+	if err == nil {
+		return stopReason{Reason: "finished"}
+	}
+
+	// errors without an exit code make k6 exit with -1, see rootCommand.execute()
+	sr := stopReason{Reason: "error", ExitCode: -1}
+	var ecerr errext.HasExitCode
+	if errors.As(err, &ecerr) {
+		sr.ExitCode = int(ecerr.ExitCode())
+		if reason, ok := stopReasons[ecerr.ExitCode()]; ok {
+			sr.Reason = reason
+		}
+	}
+	sr.Error, _ = errext.Format(err)
+	return sr
+}
+
+// saveStopReason saves why the test run stopped, if --stop-reason-file was used.
+func (c *cmdRun) saveStopReason(err error, logger logrus.FieldLogger) {
+	if c.stopReasonPath == "" {
+		return
+	}
+	data, mErr := json.MarshalIndent(newStopReason(err), "", "  ")
+	if mErr == nil {
+		mErr = fsext.WriteFile(c.gs.FS, c.stopReasonPath, data, 0o644)
+	}
+	if mErr != nil {
+		logger.WithError(mErr).Error("Couldn't save the stop reason")
+	}
+}
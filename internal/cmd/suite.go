@@ -0,0 +1,555 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"go.k6.io/k6/cmd/state"
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/internal/event"
+	"go.k6.io/k6/internal/ui/console"
+	"go.k6.io/k6/internal/usage"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// suiteConfig is the definition of a test suite, as read by `k6 suite` from a
+// YAML (or JSON) file.
+type suiteConfig struct {
+	// Parallel runs all tests at the same time, instead of one after another.
+	Parallel bool `yaml:"parallel"`
+	// FailFast skips the remaining tests after the first failed one.
+	FailFast bool `yaml:"failFast"`
+	// Config is the path to a config file used by all tests, like --config.
+	Config string `yaml:"config"`
+	// Env and Args are the environment variables and `k6 run` flags shared
+	// by all tests. The ones of the tests themselves take precedence.
+	Env  map[string]string `yaml:"env"`
+	Args []string          `yaml:"args"`
+
+	Tests []suiteTest `yaml:"tests"`
+}
+
+type suiteTest struct {
+	Name   string            `yaml:"name"`
+	Script string            `yaml:"script"`
+	Env    map[string]string `yaml:"env"`
+	Args   []string          `yaml:"args"`
+}
+
+const (
+	suiteTestPassed  = "passed"
+	suiteTestFailed  = "failed"
+	suiteTestSkipped = "skipped"
+)
+
+// suiteTestResult is the outcome of a single test of the suite, with the
+// metrics from its summary export.
+type suiteTestResult struct {
+	Name             string                    `json:"name"`
+	Script           string                    `json:"script"`
+	Status           string                    `json:"status"`
+	ExitCode         int                       `json:"exit_code"`
+	Error            string                    `json:"error,omitempty"`
+	Duration         float64                   `json:"duration_ms"`
+	FailedThresholds []string                  `json:"failed_thresholds,omitempty"`
+	Metrics          map[string]map[string]any `json:"metrics,omitempty"`
+	RootGroup        json.RawMessage           `json:"root_group,omitempty"`
+
+	err error
+}
+
+// suiteReport is the combined report of all the tests in the suite. Only the
+// counter and rate metrics are aggregated, since trends from different test
+// runs can't be merged without their samples.
+type suiteReport struct {
+	Passed   bool                          `json:"passed"`
+	Duration float64                       `json:"duration_ms"`
+	Metrics  map[string]map[string]float64 `json:"metrics"`
+	Tests    []*suiteTestResult            `json:"tests"`
+}
+
+// cmdSuite handles the `k6 suite` sub-command
+type cmdSuite struct {
+	gs *state.GlobalState
+
+	parallel      bool
+	failFast      bool
+	summaryExport string
+}
+
+func (c *cmdSuite) run(cmd *cobra.Command, args []string) error {
+	suitePath, suite, err := c.readSuite(args[0])
+	if err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("parallel") {
+		suite.Parallel = c.parallel
+	}
+	if cmd.Flags().Changed("fail-fast") {
+		suite.FailFast = c.failFast
+	}
+	if suite.Parallel && suite.FailFast {
+		return errext.WithExitCodeIfNone(
+			errors.New("the failFast option can't be used when running the suite in parallel"),
+			exitcodes.InvalidConfig,
+		)
+	}
+
+	exportDir, err := afero.TempDir(c.gs.FS, "", "k6-suite")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = c.gs.FS.RemoveAll(exportDir)
+	}()
+
+	suiteDir := filepath.Dir(suitePath)
+	results := make([]*suiteTestResult, len(suite.Tests))
+	for i, t := range suite.Tests {
+		results[i] = &suiteTestResult{Name: t.Name, Script: t.Script, Status: suiteTestSkipped}
+	}
+
+	start := time.Now()
+	if suite.Parallel {
+		var wg sync.WaitGroup
+		for i := range suite.Tests {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.runTest(suite, suiteDir, exportDir, i, results[i])
+			}()
+		}
+		wg.Wait()
+	} else {
+		for i := range suite.Tests {
+			c.runTest(suite, suiteDir, exportDir, i, results[i])
+			if results[i].Status != suiteTestFailed {
+				continue
+			}
+			if suite.FailFast || isExternalAbort(results[i].err) {
+				break
+			}
+		}
+	}
+
+	report := newSuiteReport(results, time.Since(start))
+	printToStdout(c.gs, renderSuiteReport(report, c.gs.Flags.NoColor || !c.gs.Stdout.IsTTY))
+
+	if c.summaryExport != "" {
+		data, err := json.MarshalIndent(report, "", "    ")
+		if err != nil {
+			return err
+		}
+		if err := fsext.WriteFile(c.gs.FS, c.summaryExport, data, 0o644); err != nil {
+			return fmt.Errorf("error saving the suite report to '%s': %w", c.summaryExport, err)
+		}
+	}
+
+	if report.Passed {
+		return nil
+	}
+
+	// The exit code of the whole suite is the one of its first failed test.
+	failed := 0
+	var firstErr error
+	for _, r := range results {
+		if r.Status != suiteTestFailed {
+			continue
+		}
+		failed++
+		if firstErr == nil {
+			firstErr = r.err
+		}
+	}
+	err = fmt.Errorf("%d of %d tests in the suite have failed", failed, len(results))
+	var ecerr errext.HasExitCode
+	if errors.As(firstErr, &ecerr) {
+		err = errext.WithExitCodeIfNone(err, ecerr.ExitCode())
+	}
+	return err
+}
+
+func (c *cmdSuite) readSuite(path string) (string, *suiteConfig, error) {
+	if !filepath.IsAbs(path) {
+		pwd, err := c.gs.Getwd()
+		if err != nil {
+			return "", nil, err
+		}
+		path = filepath.Join(pwd, path)
+	}
+
+	data, err := fsext.ReadFile(c.gs.FS, path)
+	if err != nil {
+		return "", nil, fmt.Errorf("couldn't read the suite file: %w", err)
+	}
+
+	suite := &suiteConfig{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(suite); err != nil {
+		return "", nil, errext.WithExitCodeIfNone(
+			fmt.Errorf("couldn't parse the suite file '%s': %w", path, err), exitcodes.InvalidConfig)
+	}
+
+	if len(suite.Tests) == 0 {
+		return "", nil, errext.WithExitCodeIfNone(
+			fmt.Errorf("the suite file '%s' doesn't have any tests", path), exitcodes.InvalidConfig)
+	}
+	names := make(map[string]struct{}, len(suite.Tests))
+	for i := range suite.Tests {
+		t := &suite.Tests[i]
+		if t.Script == "" {
+			return "", nil, errext.WithExitCodeIfNone(
+				fmt.Errorf("test #%d of the suite doesn't have a script", i+1), exitcodes.InvalidConfig)
+		}
+		if t.Name == "" {
+			t.Name = t.Script
+		}
+		if _, ok := names[t.Name]; ok {
+			return "", nil, errext.WithExitCodeIfNone(
+				fmt.Errorf("the suite has more than one test named '%s'", t.Name), exitcodes.InvalidConfig)
+		}
+		names[t.Name] = struct{}{}
+	}
+
+	return path, suite, nil
+}
+
+// runTest runs a single test of the suite as a `k6 run` command with its own
+// global state, so that tests don't interfere with each other.
+func (c *cmdSuite) runTest(suite *suiteConfig, suiteDir, exportDir string, i int, result *suiteTestResult) {
+	t := suite.Tests[i]
+	gs := c.testGlobalState(suite, suiteDir, t)
+
+	var stdout *bytes.Buffer
+	if suite.Parallel {
+		// The output of the tests running in parallel is buffered, so it doesn't get mixed up.
+		stdout = new(bytes.Buffer)
+		gs.OutMutex = &sync.Mutex{}
+		gs.Stdout = &console.Writer{Mutex: gs.OutMutex, Writer: stdout}
+		gs.Flags.Quiet = true
+		gs.Flags.Address = ""
+	}
+
+	exportPath := filepath.Join(exportDir, fmt.Sprintf("%d.json", i))
+	args := slices.Concat(suite.Args, t.Args, []string{"--summary-export", exportPath, resolveSuitePath(suiteDir, t.Script)})
+
+	runCmd := getCmdRun(gs)
+	runCmd.SilenceUsage = true
+	runCmd.SilenceErrors = true
+	runCmd.SetArgs(args)
+
+	gs.Logger.Debugf("Running the '%s' test of the suite...", t.Name)
+	start := time.Now()
+	err := runCmd.Execute()
+	result.Duration = float64(time.Since(start)) / float64(time.Millisecond)
+
+	if stdout != nil {
+		printToStdout(c.gs, stdout.String())
+	}
+
+	result.Status = suiteTestPassed
+	if err != nil {
+		result.err = err
+		result.Status = suiteTestFailed
+		result.ExitCode = -1
+		var ecerr errext.HasExitCode
+		if errors.As(err, &ecerr) {
+			result.ExitCode = int(ecerr.ExitCode())
+		}
+		errText, fields := errext.Format(err)
+		result.Error = errText
+		gs.Logger.WithFields(fields).Error(errText)
+	}
+
+	if err := result.readSummaryExport(c.gs.FS, exportPath); err != nil {
+		gs.Logger.WithError(err).Warn("Couldn't read the summary of the test")
+	}
+}
+
+// testGlobalState returns a copy of the global state for running a single
+// test of the suite, with its own environment, config file and test status.
+func (c *cmdSuite) testGlobalState(suite *suiteConfig, suiteDir string, t suiteTest) *state.GlobalState {
+	gs := *c.gs
+
+	gs.Env = maps.Clone(c.gs.Env)
+	maps.Copy(gs.Env, suite.Env)
+	maps.Copy(gs.Env, t.Env)
+	if suite.Config != "" {
+		gs.Flags.ConfigFilePath = resolveSuitePath(suiteDir, suite.Config)
+	}
+
+	gs.Logger = newSuiteTestLogger(c.gs.Logger, t.Name)
+	gs.Events = event.NewEventSystem(100, gs.Logger)
+	gs.Usage = usage.New()
+	gs.TestStatus = lib.NewTestStatus()
+
+	return &gs
+}
+
+// newSuiteTestLogger returns a copy of the given logger that adds the name of
+// the suite test to all of its messages.
+func newSuiteTestLogger(logger *logrus.Logger, name string) *logrus.Logger { //nolint:forbidigo
+	testLogger := &logrus.Logger{ //nolint:forbidigo
+		Out:       logger.Out,
+		Formatter: logger.Formatter,
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logger.Level,
+		ExitFunc:  logger.ExitFunc,
+	}
+	testLogger.AddHook(suiteTestHook(name))
+	for level, hooks := range logger.Hooks {
+		testLogger.Hooks[level] = append(testLogger.Hooks[level], hooks...)
+	}
+	return testLogger
+}
+
+type suiteTestHook string
+
+func (h suiteTestHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h suiteTestHook) Fire(entry *logrus.Entry) error {
+	entry.Data["test"] = string(h)
+	return nil
+}
+
+func resolveSuitePath(suiteDir, path string) string {
+	if path == "-" || filepath.IsAbs(path) || strings.Contains(path, "://") {
+		return path
+	}
+	return filepath.Join(suiteDir, path)
+}
+
+func isExternalAbort(err error) bool {
+	var ecerr errext.HasExitCode
+	return errors.As(err, &ecerr) && ecerr.ExitCode() == exitcodes.ExternalAbort
+}
+
+// readSummaryExport reads the metrics and checks from the JSON summary
+// exported by the test, if there is one.
+func (r *suiteTestResult) readSummaryExport(fs fsext.Fs, path string) error {
+	exists, err := fsext.Exists(fs, path)
+	if err != nil || !exists {
+		return err
+	}
+	data, err := fsext.ReadFile(fs, path)
+	if err != nil {
+		return err
+	}
+
+	var export struct {
+		Metrics   map[string]map[string]any `json:"metrics"`
+		RootGroup json.RawMessage           `json:"root_group"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		return err
+	}
+	r.Metrics = export.Metrics
+	r.RootGroup = export.RootGroup
+
+	for name, values := range r.Metrics {
+		thresholds, _ := values["thresholds"].(map[string]any)
+		for threshold, failed := range thresholds {
+			if failed == true {
+				r.FailedThresholds = append(r.FailedThresholds, name+": "+threshold)
+			}
+		}
+	}
+	sort.Strings(r.FailedThresholds)
+
+	return nil
+}
+
+func newSuiteReport(results []*suiteTestResult, duration time.Duration) *suiteReport {
+	report := &suiteReport{
+		Passed:   true,
+		Duration: float64(duration) / float64(time.Millisecond),
+		Metrics:  make(map[string]map[string]float64),
+		Tests:    results,
+	}
+
+	for _, r := range results {
+		if r.Status != suiteTestPassed {
+			report.Passed = false
+		}
+		for name, values := range r.Metrics {
+			aggregateSuiteMetric(report.Metrics, name, values)
+		}
+	}
+	for _, values := range report.Metrics {
+		if total := values["passes"] + values["fails"]; total > 0 {
+			values["value"] = values["passes"] / total
+		}
+	}
+
+	return report
+}
+
+// aggregateSuiteMetric sums the values of the counter and rate metrics of the
+// different tests. Counters are exported with a count and a rate, while rates
+// are exported with their passes and fails.
+func aggregateSuiteMetric(metrics map[string]map[string]float64, name string, values map[string]any) {
+	var keys []string
+	switch {
+	case hasSuiteMetricValues(values, "count", "rate"):
+		keys = []string{"count"}
+	case hasSuiteMetricValues(values, "passes", "fails"):
+		keys = []string{"passes", "fails"}
+	default:
+		return
+	}
+
+	aggregated, ok := metrics[name]
+	if !ok {
+		aggregated = make(map[string]float64, len(keys))
+		metrics[name] = aggregated
+	}
+	for _, k := range keys {
+		v, _ := values[k].(float64)
+		aggregated[k] += v
+	}
+}
+
+func hasSuiteMetricValues(values map[string]any, keys ...string) bool {
+	for _, k := range keys {
+		if _, ok := values[k].(float64); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func renderSuiteReport(report *suiteReport, noColor bool) string {
+	green := getColor(noColor, color.FgGreen)
+	red := getColor(noColor, color.FgRed)
+	faint := getColor(noColor, color.Faint)
+
+	nameWidth := 0
+	for _, r := range report.Tests {
+		nameWidth = max(nameWidth, len(r.Name))
+	}
+
+	var passed, failed, skipped int
+	buf := &strings.Builder{}
+	buf.WriteString("\n  █ SUITE\n\n")
+	for _, r := range report.Tests {
+		name := r.Name + " " + strings.Repeat(".", nameWidth-len(r.Name)+3)
+		switch r.Status {
+		case suiteTestPassed:
+			passed++
+			fmt.Fprintf(buf, "    %s %s %s %s\n", green.Sprint("✓"), name, r.Status,
+				faint.Sprint(suiteDuration(r.Duration)))
+		case suiteTestFailed:
+			failed++
+			fmt.Fprintf(buf, "    %s %s %s %s %s\n", red.Sprint("✗"), name, red.Sprint(r.Status),
+				faint.Sprint(suiteDuration(r.Duration)), faint.Sprintf("(exit code %d)", r.ExitCode))
+			for _, th := range r.FailedThresholds {
+				fmt.Fprintf(buf, "        %s %s\n", red.Sprint("✗"), th)
+			}
+		default:
+			skipped++
+			fmt.Fprintf(buf, "    %s %s %s\n", faint.Sprint("-"), name, faint.Sprint(r.Status))
+		}
+	}
+
+	buf.WriteString("\n")
+	fmt.Fprintf(buf, "    %s: %d total, %d passed, %d failed, %d skipped\n",
+		suiteLabel("tests"), len(report.Tests), passed, failed, skipped)
+	if checks, ok := report.Metrics["checks"]; ok {
+		fmt.Fprintf(buf, "    %s: %.2f%% %.0f out of %.0f\n", suiteLabel("checks"),
+			checks["value"]*100, checks["passes"], checks["passes"]+checks["fails"])
+	}
+	for _, name := range []string{"iterations", "http_reqs"} {
+		if m, ok := report.Metrics[name]; ok {
+			fmt.Fprintf(buf, "    %s: %.0f\n", suiteLabel(name), m["count"])
+		}
+	}
+	buf.WriteString("\n")
+
+	return buf.String()
+}
+
+// suiteLabel pads the label with dots, so the values of the report are aligned.
+func suiteLabel(name string) string {
+	return name + strings.Repeat(".", max(0, 11-len(name)))
+}
+
+func suiteDuration(ms float64) string {
+	return (time.Duration(ms * float64(time.Millisecond))).Round(time.Millisecond).String()
+}
+
+func (c *cmdSuite) flagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.SortFlags = false
+	flags.BoolVar(&c.parallel, "parallel", false, "run all tests of the suite at the same time")
+	flags.BoolVar(&c.failFast, "fail-fast", false, "skip the remaining tests after the first failed one")
+	flags.StringVar(&c.summaryExport, "summary-export", "", "output the combined suite report to a JSON `file`")
+	return flags
+}
+
+func getCmdSuite(gs *state.GlobalState) *cobra.Command {
+	c := &cmdSuite{gs: gs}
+
+	exampleText := getExampleText(gs, `
+  # Run all the tests of the suite, one after another.
+  {{.}} suite suite.yaml
+
+  # Run all the tests of the suite at the same time.
+  {{.}} suite --parallel suite.yaml
+
+  # Save the combined report of the suite.
+  {{.}} suite --summary-export=report.json suite.yaml`[1:])
+
+	suiteCmd := &cobra.Command{
+		Use:   "suite",
+		Short: "Run a suite of tests",
+		Long: `Run a suite of tests.
+
+The suite file lists the scripts to run, either one after another or at the same time, together
+with the config file, environment variables and "k6 run" flags shared by all of them:
+
+  parallel: false
+  failFast: false
+  config: config.json
+  env:
+    BASE_URL: https://test.k6.io
+  args: ["--vus", "10"]
+  tests:
+    - name: login
+      script: login.js
+    - name: checkout
+      script: checkout.js
+      args: ["--duration", "1m"]
+
+Paths are relative to the suite file. The summaries of all tests are combined in a single report,
+and k6 exits with the exit code of the first failed test.`,
+		Example: exampleText,
+		Args:    exactArgsWithMsg(1, "arg should be a path to a suite file"),
+		RunE:    c.run,
+	}
+
+	suiteCmd.Flags().SortFlags = false
+	suiteCmd.Flags().AddFlagSet(c.flagSet())
+
+	return suiteCmd
+}
@@ -0,0 +1,43 @@
+import grpc from "k6/net/grpc";
+import exec from "k6/execution";
+import { check, sleep } from "k6";
+import { textSummary } from "https://jslib.k6.io/k6-summary/0.0.2/index.js";
+
+const GRPC_ADDR = __ENV.GRPC_ADDR || "grpc-quickpizza.grafana.com:443";
+
+const client = new grpc.Client();
+
+export const options = {
+  vus: 5,
+  duration: "30s",
+  thresholds: {
+    grpc_req_duration: ["p(95)<500"],
+    checks: ["rate>0.99"],
+  },{{ if .ProjectID }}
+  cloud: {
+    projectID: {{ .ProjectID }},
+    name: "{{ .ScriptName }}",
+  },{{ end }}
+};
+
+export default function() {
+  // The connection is kept open and reused by the next iterations of the VU.
+  if (exec.vu.iterationInScenario === 0) {
+    client.connect(GRPC_ADDR, { reflect: true });
+  }
+
+  const res = client.invoke("quickpizza.GRPC/RatePizza", {
+    ingredients: ["Pepperoni", "Mozzarella"],
+    dough: "Stuffed",
+  });
+
+  check(res, { "status is OK": (r) => r && r.status === grpc.StatusOK });
+  sleep(1);
+}
+
+export function handleSummary(data) {
+  return {
+    stdout: textSummary(data, { indent: " ", enableColors: true }),
+    "summary.json": JSON.stringify(data, null, 2),
+  };
+}
@@ -0,0 +1,73 @@
+import http from "k6/http";
+import { check, sleep } from "k6";
+import { textSummary } from "https://jslib.k6.io/k6-summary/0.0.2/index.js";
+
+const BASE_URL = __ENV.BASE_URL || "https://quickpizza.grafana.com";
+
+export const options = {
+  scenarios: {
+    browse: {
+      executor: "constant-arrival-rate",
+      exec: "browse",
+      rate: 10,
+      timeUnit: "1s",
+      duration: "1m",
+      preAllocatedVUs: 10,
+      maxVUs: 50,
+    },
+    order: {
+      executor: "ramping-vus",
+      exec: "order",
+      startTime: "10s",
+      startVUs: 0,
+      stages: [
+        { duration: "20s", target: 5 },
+        { duration: "20s", target: 5 },
+        { duration: "10s", target: 0 },
+      ],
+    },
+  },
+  thresholds: {
+    "http_req_duration{scenario:browse}": ["p(95)<300"],
+    "http_req_duration{scenario:order}": ["p(95)<1000"],
+    http_req_failed: ["rate<0.01"],
+    checks: ["rate>0.99"],
+  },{{ if .ProjectID }}
+  cloud: {
+    projectID: {{ .ProjectID }},
+    name: "{{ .ScriptName }}",
+  },{{ end }}
+};
+
+export function browse() {
+  let res = http.get(BASE_URL);
+  check(res, { "status is 200": (r) => r.status === 200 });
+}
+
+export function order() {
+  let restrictions = {
+    maxCaloriesPerSlice: 500,
+    mustBeVegetarian: false,
+    excludedIngredients: ["pepperoni"],
+    excludedTools: ["knife"],
+    maxNumberOfToppings: 6,
+    minNumberOfToppings: 2
+  };
+
+  let res = http.post(BASE_URL + "/api/pizza", JSON.stringify(restrictions), {
+    headers: {
+      'Content-Type': 'application/json',
+      'Authorization': 'token abcdef0123456789',
+    },
+  });
+
+  check(res, { "status is 200": (r) => r.status === 200 });
+  sleep(1);
+}
+
+export function handleSummary(data) {
+  return {
+    stdout: textSummary(data, { indent: " ", enableColors: true }),
+    "summary.json": JSON.stringify(data, null, 2),
+  };
+}
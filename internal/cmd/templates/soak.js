@@ -0,0 +1,68 @@
+import http from "k6/http";
+import exec from "k6/execution";
+import { check, sleep } from "k6";
+import { textSummary } from "https://jslib.k6.io/k6-summary/0.0.2/index.js";
+
+const BASE_URL = __ENV.BASE_URL || "https://quickpizza.grafana.com";
+const SOAK_DURATION = __ENV.SOAK_DURATION || "4h";
+const SOAK_VUS = parseInt(__ENV.SOAK_VUS || "20");
+
+export const options = {
+  scenarios: {
+    soak: {
+      executor: "ramping-vus",
+      startVUs: 0,
+      stages: [
+        { duration: "5m", target: SOAK_VUS },
+        { duration: SOAK_DURATION, target: SOAK_VUS },
+        { duration: "5m", target: 0 },
+      ],
+      gracefulRampDown: "30s",
+    },
+  },
+  thresholds: {
+    // Abort long runs early if the error rate gets too high, but not during the ramp-up.
+    http_req_failed: [{ threshold: "rate<0.01", abortOnFail: true, delayAbortEval: "10m" }],
+    http_req_duration: ["p(95)<500", "p(99)<1000"],
+    checks: ["rate>0.99"],
+  },{{ if .ProjectID }}
+  cloud: {
+    projectID: {{ .ProjectID }},
+    name: "{{ .ScriptName }}",
+  },{{ end }}
+};
+
+export function setup() {
+  let res = http.get(BASE_URL);
+  if (res.status !== 200) {
+    exec.test.abort(`Got unexpected status code ${res.status} when trying to setup. Exiting.`);
+  }
+}
+
+export default function() {
+  let restrictions = {
+    maxCaloriesPerSlice: 500,
+    mustBeVegetarian: false,
+    excludedIngredients: ["pepperoni"],
+    excludedTools: ["knife"],
+    maxNumberOfToppings: 6,
+    minNumberOfToppings: 2
+  };
+
+  let res = http.post(BASE_URL + "/api/pizza", JSON.stringify(restrictions), {
+    headers: {
+      'Content-Type': 'application/json',
+      'Authorization': 'token abcdef0123456789',
+    },
+  });
+
+  check(res, { "status is 200": (r) => r.status === 200 });
+  sleep(1);
+}
+
+export function handleSummary(data) {
+  return {
+    stdout: textSummary(data, { indent: " ", enableColors: true }),
+    "summary.json": JSON.stringify(data, null, 2),
+  };
+}
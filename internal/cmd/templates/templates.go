@@ -21,57 +21,76 @@ var protocolTemplateContent string
 //go:embed browser.js
 var browserTemplateContent string
 
+//go:embed grpc.js
+var grpcTemplateContent string
+
+//go:embed websocket.js
+var websocketTemplateContent string
+
+//go:embed soak.js
+var soakTemplateContent string
+
+//go:embed multiscenario.js
+var multiScenarioTemplateContent string
+
 // Constants for template types
 // Template names should not contain path separators to not to be confused with file paths
 const (
-	MinimalTemplate  = "minimal"
-	ProtocolTemplate = "protocol"
-	BrowserTemplate  = "browser"
+	MinimalTemplate       = "minimal"
+	ProtocolTemplate      = "protocol"
+	BrowserTemplate       = "browser"
+	GRPCTemplate          = "grpc"
+	WebSocketTemplate     = "websocket"
+	SoakTemplate          = "soak"
+	MultiScenarioTemplate = "multiscenario"
 )
 
+// BuiltinTemplates lists the names of the built-in templates.
+func BuiltinTemplates() []string {
+	return []string{
+		MinimalTemplate, ProtocolTemplate, BrowserTemplate,
+		GRPCTemplate, WebSocketTemplate, SoakTemplate, MultiScenarioTemplate,
+	}
+}
+
 // TemplateManager manages the pre-parsed templates
 type TemplateManager struct {
-	minimalTemplate  *template.Template
-	protocolTemplate *template.Template
-	browserTemplate  *template.Template
-	fs               fsext.Fs
+	builtin map[string]*template.Template
+	fs      fsext.Fs
 }
 
 // NewTemplateManager initializes a new TemplateManager with parsed templates
 func NewTemplateManager(fs fsext.Fs) (*TemplateManager, error) {
-	minimalTmpl, err := template.New(MinimalTemplate).Parse(minimalTemplateContent)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse minimal template: %w", err)
-	}
-
-	protocolTmpl, err := template.New(ProtocolTemplate).Parse(protocolTemplateContent)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse protocol template: %w", err)
+	contents := map[string]string{
+		MinimalTemplate:       minimalTemplateContent,
+		ProtocolTemplate:      protocolTemplateContent,
+		BrowserTemplate:       browserTemplateContent,
+		GRPCTemplate:          grpcTemplateContent,
+		WebSocketTemplate:     websocketTemplateContent,
+		SoakTemplate:          soakTemplateContent,
+		MultiScenarioTemplate: multiScenarioTemplateContent,
 	}
 
-	browserTmpl, err := template.New(BrowserTemplate).Parse(browserTemplateContent)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse browser template: %w", err)
+	builtin := make(map[string]*template.Template, len(contents))
+	for name, content := range contents {
+		tmpl, err := template.New(name).Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", name, err)
+		}
+		builtin[name] = tmpl
 	}
 
 	return &TemplateManager{
-		minimalTemplate:  minimalTmpl,
-		protocolTemplate: protocolTmpl,
-		browserTemplate:  browserTmpl,
-		fs:               fs,
+		builtin: builtin,
+		fs:      fs,
 	}, nil
 }
 
 // GetTemplate selects the appropriate template based on the type
 func (tm *TemplateManager) GetTemplate(tpl string) (*template.Template, error) {
 	// First check built-in templates
-	switch tpl {
-	case MinimalTemplate:
-		return tm.minimalTemplate, nil
-	case ProtocolTemplate:
-		return tm.protocolTemplate, nil
-	case BrowserTemplate:
-		return tm.browserTemplate, nil
+	if tmpl, ok := tm.builtin[tpl]; ok {
+		return tmpl, nil
 	}
 
 	// Then check if it's a file path
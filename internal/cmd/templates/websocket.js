@@ -0,0 +1,52 @@
+import { WebSocket } from "k6/experimental/websockets";
+import { check } from "k6";
+import { textSummary } from "https://jslib.k6.io/k6-summary/0.0.2/index.js";
+
+const WS_URL = __ENV.WS_URL || "wss://quickpizza.grafana.com/ws";
+const SESSION_DURATION = 10000;
+
+export const options = {
+  vus: 5,
+  duration: "30s",
+  thresholds: {
+    ws_connecting: ["p(95)<1000"],
+    ws_msgs_received: ["count>0"],
+    checks: ["rate>0.99"],
+  },{{ if .ProjectID }}
+  cloud: {
+    projectID: {{ .ProjectID }},
+    name: "{{ .ScriptName }}",
+  },{{ end }}
+};
+
+export default function() {
+  const ws = new WebSocket(WS_URL, null, { pingInterval: "5s", pongTimeout: "2s" });
+
+  ws.onopen = () => {
+    ws.send(JSON.stringify({ event: "SET_NAME", new_name: `VU ${__VU}` }));
+
+    const intervalId = setInterval(() => {
+      ws.send(JSON.stringify({ event: "SAY", message: `Hello from VU ${__VU}` }));
+    }, 1000);
+
+    setTimeout(() => {
+      clearInterval(intervalId);
+      ws.close();
+    }, SESSION_DURATION);
+  };
+
+  ws.onmessage = (e) => {
+    check(e, { "message is not empty": (m) => m.data !== "" });
+  };
+
+  ws.onerror = (e) => {
+    console.error(`WebSocket error: ${e.error}`);
+  };
+}
+
+export function handleSummary(data) {
+  return {
+    stdout: textSummary(data, { indent: " ", enableColors: true }),
+    "summary.json": JSON.stringify(data, null, 2),
+  };
+}
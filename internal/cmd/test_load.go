@@ -95,6 +95,8 @@ func loadLocalTest(gs *state.GlobalState, cmd *cobra.Command, args []string) (*l
 		Usage:          gs.Usage,
 		SecretsManager: gs.SecretsManager,
 		TestStatus:     gs.TestStatus,
+		RunMetadata:    lib.NewRunMetadata(nil),
+		ConnStats:      lib.NewConnStats(),
 	}
 
 	test := &loadedTest{
@@ -451,6 +453,10 @@ func (lct *loadedAndConfiguredTest) buildTestRunState(
 	// This is done async to avoid blocking the rest of the loading process as it will not stop if it fails.
 	go loadSystemCertPool(lct.preInitState.Logger)
 
+	for key, value := range configToReinject.RunMetadata {
+		lct.preInitState.RunMetadata.Set(key, value)
+	}
+
 	return &lib.TestRunState{
 		TestPreInitState: lct.preInitState,
 		Runner:           lct.initRunner,
@@ -378,8 +378,17 @@ func TestMetricsAndThresholds(t *testing.T) {
 	teardownThresholds, ok := teardownCounter["thresholds"].(map[string]interface{})
 	require.True(t, ok)
 
-	expected := map[string]interface{}{"count == 1": map[string]interface{}{"ok": true}}
-	require.Equal(t, expected, teardownThresholds)
+	threshold, ok := teardownThresholds["count == 1"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, true, threshold["ok"])
+
+	evaluations, ok := threshold["evaluations"].([]interface{})
+	require.True(t, ok)
+	require.NotEmpty(t, evaluations)
+	lastEvaluation, ok := evaluations[len(evaluations)-1].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, true, lastEvaluation["ok"])
+	assert.Equal(t, 1.0, lastEvaluation["value"])
 }
 
 func TestThresholdsWithCustomPercentile(t *testing.T) {
@@ -513,7 +522,7 @@ func TestExecutionTestOptionsDefaultValues(t *testing.T) {
 	loglines := ts.LoggerHook.Drain()
 	require.Len(t, loglines, 1)
 
-	expected := `{"paused":null,"executionSegment":null,"executionSegmentSequence":null,"noSetup":null,"setupTimeout":null,"noTeardown":null,"teardownTimeout":null,"rps":null,"dns":{"ttl":null,"select":null,"policy":null},"maxRedirects":null,"userAgent":null,"batch":null,"batchPerHost":null,"httpDebug":null,"insecureSkipTLSVerify":null,"tlsCipherSuites":null,"tlsVersion":null,"tlsAuth":null,"throw":null,"thresholds":null,"blacklistIPs":null,"blockHostnames":null,"hosts":null,"noConnectionReuse":null,"noVUConnectionReuse":null,"minIterationDuration":null,"ext":null,"summaryTrendStats":["avg", "min", "med", "max", "p(90)", "p(95)"],"summaryTimeUnit":null,"systemTags":["check","error","error_code","expected_response","group","method","name","proto","scenario","service","status","subproto","tls_version","url"],"tags":null,"metricSamplesBufferSize":null,"noCookiesReset":null,"discardResponseBodies":null,"consoleOutput":null,"scenarios":{"default":{"vus":null,"iterations":1,"executor":"shared-iterations","maxDuration":null,"startTime":null,"env":null,"tags":null,"gracefulStop":null,"exec":null}},"localIPs":null}`
+	expected := `{"paused":null,"executionSegment":null,"executionSegmentSequence":null,"noSetup":null,"setupTimeout":null,"noTeardown":null,"teardownTimeout":null,"rps":null,"dns":{"ttl":null,"select":null,"policy":null},"maxRedirects":null,"userAgent":null,"batch":null,"batchPerHost":null,"httpDebug":null,"insecureSkipTLSVerify":null,"tlsCipherSuites":null,"tlsVersion":null,"tlsAuth":null,"throw":null,"expectedResponses":null,"thresholds":null,"errorBudget":null,"blacklistIPs":null,"blockHostnames":null,"hosts":null,"dialFamily":null,"noConnectionReuse":null,"noVUConnectionReuse":null,"maxConcurrentRequests":null,"minIterationDuration":null,"iterationTimeout":null,"vuMemoryLimit":null,"vuMemoryLimitAction":null,"iterationBreakdown":null,"connectionMetrics":null,"ext":null,"summaryTrendStats":["avg", "min", "med", "max", "p(90)", "p(95)"],"summaryTimeUnit":null,"summaryBreakdown":null,"summaryTimeSeries":null,"summaryTimeSeriesInterval":null,"trendExactWindow":null,"systemTags":["check","error","error_code","expected_response","group","journey","method","name","proto","remote_host","scenario","service","status","subproto","tls_version","url"],"tags":null,"runMetadata":null,"metricSamplesBufferSize":null,"metricSamplesBufferLimit":null,"metricSamplesBufferPolicy":null,"noCookiesReset":null,"discardResponseBodies":null,"httpRecord":null,"httpReplay":null,"randomSeed":null,"consoleOutput":null,"scenarios":{"default":{"vus":null,"iterations":1,"executor":"shared-iterations","maxDuration":null,"startTime":null,"env":null,"tags":null,"gracefulStop":null,"exec":null,"iterationTimeout":null,"warmupIterations":null,"warmupDuration":null,"weight":null,"tlsSessionTickets":null,"dialFamily":null,"pacing":null,"requestDefaults":null,"mix":null}},"localIPs":null}`
 	assert.JSONEq(t, expected, loglines[0].Message)
 }
 
@@ -790,6 +799,70 @@ func TestAbortedByThreshold(t *testing.T) {
 	assert.Contains(t, stdOut, `level=debug msg="Sending test finished" output=cloud ref=111 run_status=8 tainted=true`)
 }
 
+func TestErrorBudgetExhausted(t *testing.T) {
+	t.Parallel()
+	script := `
+		import { check } from 'k6';
+
+		export const options = {
+			iterations: 10,
+			errorBudget: {
+				failedIterations: 5,
+				checks: { 'is even': 10 },
+			},
+		};
+
+		export default function () {
+			check(__ITER, { 'is even': (i) => i % 2 == 0, 'is odd': (i) => i % 2 == 1 });
+			if (__ITER < 3) {
+				throw new Error('failed iteration');
+			}
+		};
+	`
+
+	ts := getSingleFileTestState(t, script, nil, exitcodes.ErrorBudgetExhausted)
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	stdout := ts.Stdout.String()
+	t.Log(stdout)
+	assert.Contains(t, stdout, `the error budget has been exhausted: 50.00% of the check \"is even\" failed, more than the 10% allowed`)
+	assert.NotContains(t, stdout, "iterations failed")
+	assert.NotContains(t, stdout, `check \"is odd\"`)
+}
+
+func TestAbortedByErrorBudget(t *testing.T) {
+	t.Parallel()
+	script := `
+		export const options = {
+			scenarios: {
+				sc1: {
+					executor: 'constant-arrival-rate',
+					duration: '30s',
+					rate: 10,
+					preAllocatedVUs: 2,
+				},
+			},
+			errorBudget: {
+				failedIterations: 2,
+				abortOnExhausted: true,
+			},
+		};
+
+		export default function () {
+			throw new Error('failed iteration');
+		};
+	`
+
+	ts := getSingleFileTestState(t, script, nil, exitcodes.ErrorBudgetExhausted)
+	start := time.Now()
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+	assert.Less(t, time.Since(start), 15*time.Second)
+
+	stdout := ts.Stdout.String()
+	t.Log(stdout)
+	assert.Contains(t, stdout, "the error budget is exhausted and abortOnExhausted is enabled, stopping test prematurely")
+}
+
 func TestAbortedByUserWithGoodThresholds(t *testing.T) {
 	t.Parallel()
 	script := `
@@ -1014,7 +1087,7 @@ func TestAbortedByScriptSetupErrorWithDependency(t *testing.T) {
 
 	ts.Env["K6_CLOUD_HOST"] = srv.URL
 	ts.CmdArgs = []string{"k6", "run", "-v", "--out", "cloud", "--log-output=stdout", "test.js"}
-	ts.ExpectedExitCode = int(exitcodes.ScriptException)
+	ts.ExpectedExitCode = int(exitcodes.SetupFailed)
 
 	cmd.ExecuteWithGlobalState(ts.GlobalState)
 
@@ -1152,13 +1225,13 @@ func TestAbortedByScriptSetupError(t *testing.T) {
 
 	t.Run("noLinger", func(t *testing.T) {
 		t.Parallel()
-		ts := testAbortedByScriptError(t, script, runTestWithNoLinger)
+		ts := testAbortedByScriptError(t, script, exitcodes.SetupFailed, runTestWithNoLinger)
 		doChecks(t, ts)
 	})
 
 	t.Run("withLinger", func(t *testing.T) {
 		t.Parallel()
-		ts := testAbortedByScriptError(t, script, runTestWithLinger)
+		ts := testAbortedByScriptError(t, script, exitcodes.SetupFailed, runTestWithLinger)
 		doChecks(t, ts)
 	})
 }
@@ -1191,20 +1264,91 @@ func TestAbortedByScriptTeardownError(t *testing.T) {
 
 	t.Run("noLinger", func(t *testing.T) {
 		t.Parallel()
-		ts := testAbortedByScriptError(t, script, runTestWithNoLinger)
+		ts := testAbortedByScriptError(t, script, exitcodes.TeardownFailed, runTestWithNoLinger)
 		doChecks(t, ts)
 	})
 
 	t.Run("withLinger", func(t *testing.T) {
 		t.Parallel()
-		ts := testAbortedByScriptError(t, script, runTestWithLinger)
+		ts := testAbortedByScriptError(t, script, exitcodes.TeardownFailed, runTestWithLinger)
 		doChecks(t, ts)
 	})
 }
 
-func testAbortedByScriptError(t *testing.T, script string, runTest func(*testing.T, *GlobalTestState)) *GlobalTestState {
+func TestStopReasonFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		script   string
+		exitCode exitcodes.ExitCode
+		reason   string
+	}{
+		{
+			name:   "finished",
+			script: `export default function () {};`,
+			reason: "finished",
+		},
+		{
+			name: "thresholds",
+			script: `
+				export const options = { thresholds: { iterations: ['count == 2'] } };
+				export default function () {};
+			`,
+			exitCode: exitcodes.ThresholdsHaveFailed,
+			reason:   "thresholds_failed",
+		},
+		{
+			name: "setup",
+			script: `
+				export function setup() { throw new Error('foo'); };
+				export default function () {};
+			`,
+			exitCode: exitcodes.SetupFailed,
+			reason:   "setup_failed",
+		},
+		{
+			name: "teardown",
+			script: `
+				export function teardown() { throw new Error('foo'); };
+				export default function () {};
+			`,
+			exitCode: exitcodes.TeardownFailed,
+			reason:   "teardown_failed",
+		},
+		{
+			name: "script",
+			script: `
+				throw new Error('foo');
+				export default function () {};
+			`,
+			exitCode: exitcodes.ScriptException,
+			reason:   "script_error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := getSingleFileTestState(t, tc.script, []string{"--stop-reason-file", "stop.json"}, tc.exitCode)
+			cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+			data, err := fsext.ReadFile(ts.FS, "stop.json")
+			require.NoError(t, err)
+			stopReason := gjson.ParseBytes(data)
+			assert.Equal(t, tc.reason, stopReason.Get("reason").String())
+			assert.Equal(t, int64(tc.exitCode), stopReason.Get("exit_code").Int())
+			assert.Equal(t, tc.exitCode != 0, stopReason.Get("error").Exists())
+		})
+	}
+}
+
+func testAbortedByScriptError(
+	t *testing.T, script string, expExitCode exitcodes.ExitCode, runTest func(*testing.T, *GlobalTestState),
+) *GlobalTestState {
 	ts := getSimpleCloudOutputTestState(
-		t, script, nil, cloudapi.RunStatusAbortedScriptError, cloudapi.ResultStatusPassed, exitcodes.ScriptException,
+		t, script, nil, cloudapi.RunStatusAbortedScriptError, cloudapi.ResultStatusPassed, expExitCode,
 	)
 	runTest(t, ts)
 
@@ -1236,6 +1380,34 @@ func TestAbortedByTestAbortFirstInitCode(t *testing.T) {
 	assert.NotContains(t, stdout, "bogus summary")
 }
 
+func TestAbortedByTestAbortWithStructuredReason(t *testing.T) {
+	t.Parallel()
+	script := `
+		import exec from 'k6/execution';
+		export const options = {iterations: 1};
+		export default function () {
+			exec.test.abort({code: 42, message: 'slo breached', tags: {slo: 'latency'}});
+		};
+	`
+
+	ts := getSingleFileTestState(t, script, []string{"--summary-export=summary.json", "--out=json=out.json"}, 42)
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+	assert.Contains(t, ts.Stderr.String(), "test aborted: slo breached")
+
+	summaryExport, err := fsext.ReadFile(ts.FS, "summary.json")
+	require.NoError(t, err)
+	var summary struct {
+		Abort json.RawMessage `json:"abort"`
+	}
+	require.NoError(t, json.Unmarshal(summaryExport, &summary))
+	assert.JSONEq(t, `{"code":42,"message":"slo breached","tags":{"slo":"latency"}}`, string(summary.Abort))
+
+	jsonOutput, err := fsext.ReadFile(ts.FS, "out.json")
+	require.NoError(t, err)
+	assert.Contains(t, string(jsonOutput),
+		`{"type":"Abort","data":{"code":42,"message":"slo breached","tags":{"slo":"latency"}}}`)
+}
+
 func TestAbortedByTestAbortInNonFirstInitCode(t *testing.T) {
 	t.Parallel()
 	script := `
@@ -2654,6 +2826,82 @@ func TestMultipleSecretSources(t *testing.T) {
 	assert.Contains(t, stderr, `level=info msg="trigger exception on wrong key" ***SECRET_REDACTED***=console`)
 }
 
+func TestSecretsRedactedFromSummary(t *testing.T) {
+	t.Parallel()
+	mainScript := `
+		import secrets from "k6/secrets";
+
+		export async function setup() {
+			return { token: await secrets.get("cool") };
+		}
+
+		export default () => {}
+
+		export function handleSummary(data) {
+			return {
+				stdout: "token in stdout: " + data.setup_data.token + "\n",
+				"/summary.json": JSON.stringify(data.setup_data),
+			};
+		}
+	`
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "secrets.js"), []byte(mainScript), 0o644))
+
+	ts.CmdArgs = []string{"k6", "run", "--secret-source=mock=cool=something", "secrets.js"}
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	stdout := ts.Stdout.String()
+	t.Log(stdout)
+	assert.Contains(t, stdout, "token in stdout: ***SECRET_REDACTED***")
+	assert.NotContains(t, stdout, "something")
+
+	summary, err := fsext.ReadFile(ts.FS, "/summary.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"token":"***SECRET_REDACTED***"}`, string(summary))
+}
+
+func TestRunMetadata(t *testing.T) {
+	t.Parallel()
+	mainScript := `
+		import exec from "k6/execution";
+
+		export function setup() {
+			exec.test.metadata.environment = "staging";
+		}
+
+		export default () => {}
+
+		export function handleSummary(data) {
+			return { "/summary.json": JSON.stringify(data.run_metadata) };
+		}
+	`
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "script.js"), []byte(mainScript), 0o644))
+
+	ts.CmdArgs = []string{
+		"k6", "run", "--run-metadata", "git_sha=abc123", "--out", "json=results.json", "script.js",
+	}
+
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	summary, err := fsext.ReadFile(ts.FS, "/summary.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"git_sha":"abc123","environment":"staging"}`, string(summary))
+
+	results, err := fsext.ReadFile(ts.FS, "results.json")
+	require.NoError(t, err)
+	var lastRunMetadata string
+	for _, line := range strings.Split(string(results), "\n") {
+		if strings.HasPrefix(line, `{"type":"RunMetadata"`) {
+			lastRunMetadata = line
+		}
+	}
+	assert.JSONEq(t, `{"type":"RunMetadata","data":{"git_sha":"abc123","environment":"staging"}}`, lastRunMetadata)
+}
+
 func TestSummaryExport(t *testing.T) {
 	t.Parallel()
 
@@ -2949,6 +3197,66 @@ func TestGroupsOrderInFullSummaryWithScenario(t *testing.T) {
 	assert.Regexp(t, regexp.MustCompile(expectedGroupsRegex), stdout)
 }
 
+func TestSummaryTimeSeries(t *testing.T) {
+	t.Parallel()
+
+	mainScript := `
+		import { Counter } from 'k6/metrics';
+
+		const counter = new Counter('my_counter');
+
+		export const options = {
+			iterations: 3,
+			summaryTimeSeries: ['my_counter', 'missing_metric'],
+			summaryTimeSeriesInterval: '1h',
+		};
+
+		export default function () {
+			counter.add(2);
+		}
+	`
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+
+		ts := NewGlobalTestState(t)
+		require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "script.js"), []byte(mainScript), 0o644))
+		ts.CmdArgs = []string{"k6", "run", "script.js"}
+
+		cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+		stdout := ts.Stdout.String()
+		t.Log(stdout)
+		assert.Contains(t, stdout, "TIME SERIES")
+		assert.Regexp(t, `my_counter count \[1h0m0s\]\.+: \S*▁\S* min=6 max=6`, stdout)
+		assert.Regexp(t, `missing_metric p\(95\) \[1h0m0s\]\.+: \S*no data`, stdout)
+	})
+
+	t.Run("handleSummary", func(t *testing.T) {
+		t.Parallel()
+
+		script := mainScript + `
+			export function handleSummary(data) {
+				const series = data.time_series.map(({ name, type, interval_ms, buckets }) =>
+					({ name, type, interval_ms, counts: buckets.map((b) => b && b.count) }));
+				return { 'time_series.json': JSON.stringify(series) };
+			}
+		`
+		ts := NewGlobalTestState(t)
+		require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "script.js"), []byte(script), 0o644))
+		ts.CmdArgs = []string{"k6", "run", "script.js"}
+
+		cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+		data, err := fsext.ReadFile(ts.FS, "time_series.json")
+		require.NoError(t, err)
+		assert.JSONEq(t, `[
+			{"name":"my_counter","type":"counter","interval_ms":3600000,"counts":[6]},
+			{"name":"missing_metric","type":"","interval_ms":3600000,"counts":[null]}
+		]`, string(data))
+	})
+}
+
 func TestInvalidSummaryModeAbortsTheExecution(t *testing.T) {
 	t.Parallel()
 
@@ -3199,3 +3507,355 @@ func TestSpaceInPath(t *testing.T) {
 	t.Log(stderr)
 	assert.Contains(t, stderr, `something 42`)
 }
+
+func TestCheckpointAndResume(t *testing.T) {
+	t.Parallel()
+	script := []byte(`
+		import { sleep } from "k6";
+
+		export const options = {
+			scenarios: {
+				shared: { executor: "shared-iterations", vus: 1, iterations: 10 },
+				later: { executor: "constant-vus", vus: 1, duration: "2s", startTime: "1s" },
+			},
+		};
+
+		export default function () { sleep(0.1); }
+	`)
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "test.js"), script, 0o644))
+	ts.CmdArgs = []string{"k6", "run", "--checkpoint", "/checkpoint.json", "-q", "--no-summary", "test.js"}
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	var saved map[string]any
+	data, err := fsext.ReadFile(ts.FS, "/checkpoint.json")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &saved))
+	iterations, ok := saved["iterations"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, float64(10), iterations["shared"])
+	completed, ok := iterations["later"].(float64)
+	require.True(t, ok)
+
+	// pretend that the test was interrupted in the middle of the second scenario
+	saved["elapsed"] = "2s"
+	iterations["later"] = float64(0)
+	data, err = json.Marshal(saved)
+	require.NoError(t, err)
+	require.NoError(t, fsext.WriteFile(ts.FS, "/resume.json", data, 0o644))
+
+	ts2 := NewGlobalTestState(t)
+	ts2.FS = ts.FS
+	ts2.CmdArgs = []string{"k6", "run", "--resume", "/resume.json", "--no-summary", "test.js"}
+	ts2.Cwd = ts.Cwd
+	cmd.ExecuteWithGlobalState(ts2.GlobalState)
+
+	stderr := ts2.Stderr.String()
+	t.Log(stderr)
+	assert.Contains(t, stderr, "Resuming the test after 2s, completed iterations later: 0, shared: 10")
+	assert.Contains(t, stderr, "Scenarios shared had already finished and won't be run")
+
+	data, err = fsext.ReadFile(ts.FS, "/resume.json")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &saved))
+	iterations, ok = saved["iterations"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, float64(10), iterations["shared"])
+	// only the remaining half of the second scenario was run
+	assert.Greater(t, iterations["later"], float64(0))
+	assert.Less(t, iterations["later"], completed)
+
+	// the test has now completely finished, so there's nothing left to resume
+	ts3 := NewGlobalTestState(t)
+	ts3.FS = ts.FS
+	ts3.Cwd = ts.Cwd
+	ts3.CmdArgs = []string{"k6", "run", "--resume", "/resume.json", "test.js"}
+	ts3.ExpectedExitCode = -1
+	cmd.ExecuteWithGlobalState(ts3.GlobalState)
+	assert.Contains(t, ts3.Stderr.String(), "all scenarios of the checkpoint have already finished")
+}
+
+func TestSetupCache(t *testing.T) {
+	t.Parallel()
+	script := []byte(`
+		export const options = { iterations: 1 };
+
+		export function setup() {
+			console.log("setup ran");
+			return { token: "abc" };
+		}
+
+		export default function (data) { console.log("token " + data.token); }
+
+		export function teardown() { console.log("teardown ran"); }
+	`)
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "test.js"), script, 0o644))
+	run := func(args ...string) string {
+		rts := NewGlobalTestState(t)
+		rts.FS = ts.FS
+		rts.Cwd = ts.Cwd
+		rts.CmdArgs = append([]string{"k6", "run", "--no-summary", "--setup-cache", "/setup.json"}, args...)
+		cmd.ExecuteWithGlobalState(rts.GlobalState)
+		stderr := rts.Stderr.String()
+		t.Log(stderr)
+		assert.Contains(t, stderr, "token abc")
+		return stderr
+	}
+
+	stderr := run("test.js")
+	assert.Contains(t, stderr, "setup ran")
+	assert.Contains(t, stderr, "teardown ran")
+	data, err := fsext.ReadFile(ts.FS, "/setup.json")
+	require.NoError(t, err)
+	var saved map[string]any
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, map[string]any{"token": "abc"}, saved["data"])
+
+	stderr = run("test.js")
+	assert.NotContains(t, stderr, "setup ran")
+	assert.Contains(t, stderr, "Reusing the setup() data cached")
+	assert.NotContains(t, stderr, "teardown ran")
+	assert.Contains(t, stderr, "Skipping teardown()")
+
+	// the cache is for other environment variables
+	stderr = run("-e", "A=1", "test.js")
+	assert.Contains(t, stderr, "setup ran")
+
+	// the cache has expired
+	stderr = run("--setup-cache-ttl", "1ns", "test.js")
+	assert.Contains(t, stderr, "setup ran")
+}
+
+func TestScriptProfile(t *testing.T) {
+	t.Parallel()
+	script := []byte(`
+		export const options = { iterations: 3 };
+
+		function expensive() {
+			let result = 0;
+			const end = Date.now() + 200;
+			while (Date.now() < end) {
+				result += Math.sqrt(Math.random());
+			}
+			return result;
+		}
+
+		export default function () { expensive(); }
+	`)
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "test.js"), script, 0o644))
+	ts.CmdArgs = []string{"k6", "run", "--script-profile", "/profile.folded", "--no-summary", "test.js"}
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	assert.Contains(t, ts.Stderr.String(), "The script profile was saved to /profile.folded")
+	data, err := fsext.ReadFile(ts.FS, "/profile.folded")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "default;expensive ")
+}
+
+func TestRunWatch(t *testing.T) {
+	t.Parallel()
+	script := []byte(`
+		import { sleep } from 'k6';
+		import { version } from './lib.js';
+
+		export const options = { vus: 1, duration: '3s' };
+
+		export default function () {
+			console.log('running version ' + version);
+			sleep(0.2);
+		}
+	`)
+
+	ts := NewGlobalTestState(t)
+	libPath := filepath.Join(ts.Cwd, "lib.js")
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "test.js"), script, 0o644))
+	require.NoError(t, fsext.WriteFile(ts.FS, libPath, []byte(`export const version = "one";`), 0o644))
+	ts.CmdArgs = []string{"k6", "run", "--watch", "--no-summary", "test.js"}
+
+	go func() {
+		time.Sleep(time.Second)
+		assert.NoError(t, fsext.WriteFile(ts.FS, libPath, []byte(`export const version = "second";`), 0o644))
+	}()
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	stderr := ts.Stderr.String()
+	assert.Contains(t, stderr, "running version one")
+	assert.Contains(t, stderr, "The script was reloaded, the next iterations run the new code")
+	assert.Contains(t, stderr, "running version second")
+}
+
+func TestRunWatchStdin(t *testing.T) {
+	t.Parallel()
+
+	ts := NewGlobalTestState(t)
+	ts.Stdin = bytes.NewBufferString(`export default function () {};`)
+	ts.CmdArgs = []string{"k6", "run", "--watch", "--no-summary", "-"}
+	ts.ExpectedExitCode = -1
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	assert.Contains(t, ts.Stderr.String(), "--watch can only be used with script files")
+}
+
+func TestHTTPRecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, "response %d", requests.Add(1))
+	}))
+
+	script := []byte(fmt.Sprintf(`
+		import http from 'k6/http';
+
+		export const options = { iterations: 2 };
+
+		export default function () {
+			const res = http.get('%s/');
+			console.log('got ' + res.status + ' ' + res.body);
+		}
+	`, srv.URL))
+
+	ts := NewGlobalTestState(t)
+	require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "test.js"), script, 0o644))
+	ts.CmdArgs = []string{"k6", "run", "--http-record", "cassette.json", "--no-summary", "test.js"}
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	cassettePath := filepath.Join(ts.Cwd, "cassette.json")
+	assert.Contains(t, ts.Stderr.String(), "The HTTP responses were recorded to "+cassettePath)
+	require.Equal(t, int64(2), requests.Load())
+	data, err := fsext.ReadFile(ts.FS, cassettePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"body": "response 1"`)
+
+	// the responses are replayed even though the server isn't reachable anymore
+	srv.Close()
+	replayState := NewGlobalTestState(t)
+	replayState.FS = ts.FS
+	replayState.CmdArgs = []string{"k6", "run", "--http-replay", "cassette.json", "--no-summary", "test.js"}
+	cmd.ExecuteWithGlobalState(replayState.GlobalState)
+
+	stderr := replayState.Stderr.String()
+	assert.Contains(t, stderr, "got 200 response 1")
+	assert.Contains(t, stderr, "got 200 response 2")
+	assert.Equal(t, int64(2), requests.Load())
+
+	// the cassette is included in the archives
+	archiveState := NewGlobalTestState(t)
+	archiveState.FS = ts.FS
+	archivePath := filepath.Join(ts.Cwd, "archive.tar")
+	archiveState.CmdArgs = []string{"k6", "archive", "--http-replay", "cassette.json", "-O", archivePath, "test.js"}
+	cmd.ExecuteWithGlobalState(archiveState.GlobalState)
+	require.NoError(t, ts.FS.Remove(cassettePath))
+
+	archiveReplayState := NewGlobalTestState(t)
+	archiveReplayState.FS = ts.FS
+	archiveReplayState.CmdArgs = []string{"k6", "run", "--no-summary", archivePath}
+	cmd.ExecuteWithGlobalState(archiveReplayState.GlobalState)
+	assert.Contains(t, archiveReplayState.Stderr.String(), "got 200 response 1")
+}
+
+func TestProgressFormatJSON(t *testing.T) {
+	t.Parallel()
+	script := `
+		import { check } from "k6";
+
+		export const options = {
+			scenarios: {
+				first: { executor: "per-vu-iterations", vus: 2, iterations: 3 },
+				second: { executor: "shared-iterations", vus: 1, iterations: 2, exec: "second" },
+			},
+		};
+
+		export default function () {}
+
+		export function second() { check(1, { "is one": (v) => v === 1 }); }
+	`
+
+	t.Run("stderr", func(t *testing.T) {
+		t.Parallel()
+		ts := getSingleFileTestState(t, script, []string{"--progress-format", "json"}, 0)
+		cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+		stdout := ts.Stdout.String()
+		t.Log(stdout)
+		assert.NotContains(t, stdout, "running (")
+		assert.NotContains(t, stdout, `"scenarios":`)
+		assert.Contains(t, stdout, "checks")
+
+		var lines []string
+		for _, line := range strings.Split(ts.Stderr.String(), "\n") {
+			if strings.HasPrefix(line, "{") {
+				lines = append(lines, line)
+			}
+		}
+		assertProgressFormatJSON(t, lines)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+		ts := getSingleFileTestState(t, script,
+			[]string{"--quiet", "--no-summary", "--progress-format", "json", "--progress-file", "progress.jsonl"}, 0)
+		cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+		assert.NotContains(t, ts.Stdout.String(), `"scenarios":`)
+		assert.NotContains(t, ts.Stderr.String(), `"scenarios":`)
+		data, err := fsext.ReadFile(ts.FS, "progress.jsonl")
+		require.NoError(t, err)
+		assertProgressFormatJSON(t, strings.Split(strings.TrimSpace(string(data)), "\n"))
+	})
+}
+
+func assertProgressFormatJSON(t *testing.T, lines []string) {
+	t.Helper()
+	require.NotEmpty(t, lines)
+	var last struct {
+		Status     string  `json:"status"`
+		ETA        float64 `json:"eta"`
+		Iterations uint64  `json:"iterations"`
+		Scenarios  []struct {
+			Name       string  `json:"name"`
+			Executor   string  `json:"executor"`
+			Status     string  `json:"status"`
+			Progress   float64 `json:"progress"`
+			VUs        int64   `json:"vus"`
+			Iterations uint64  `json:"iterations"`
+		} `json:"scenarios"`
+		Metrics map[string]map[string]float64 `json:"metrics"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &last))
+
+	assert.Equal(t, "Ended", last.Status)
+	assert.Equal(t, 0.0, last.ETA)
+	assert.Equal(t, uint64(8), last.Iterations)
+	require.Len(t, last.Scenarios, 2)
+	assert.Equal(t, "first", last.Scenarios[0].Name)
+	assert.Equal(t, "per-vu-iterations", last.Scenarios[0].Executor)
+	assert.Equal(t, uint64(6), last.Scenarios[0].Iterations)
+	assert.Equal(t, "second", last.Scenarios[1].Name)
+	assert.Equal(t, uint64(2), last.Scenarios[1].Iterations)
+	for _, scenario := range last.Scenarios {
+		assert.Equal(t, "done", scenario.Status)
+		assert.Equal(t, 1.0, scenario.Progress)
+		assert.Equal(t, int64(0), scenario.VUs)
+	}
+	assert.Equal(t, 8.0, last.Metrics["iterations"]["count"])
+	assert.Equal(t, 1.0, last.Metrics["checks"]["rate"])
+}
+
+func TestProgressFormatInvalid(t *testing.T) {
+	t.Parallel()
+	ts := getSingleFileTestState(t, `export default function () {}`,
+		[]string{"--progress-format", "xml"}, exitcodes.InvalidConfig)
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+	assert.Contains(t, ts.Stderr.String(), "unsupported --progress-format")
+
+	ts = getSingleFileTestState(t, `export default function () {}`,
+		[]string{"--progress-file", "progress.jsonl"}, exitcodes.InvalidConfig)
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+	assert.Contains(t, ts.Stderr.String(), "--progress-file can only be used with --progress-format json")
+}
@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/internal/cmd"
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestSuite(t *testing.T) {
+	t.Parallel()
+
+	const passingScript = `
+		import { check } from 'k6';
+		import { Counter } from 'k6/metrics';
+
+		const requests = new Counter('requests');
+
+		export const options = { iterations: 2 };
+
+		export default function () {
+			requests.add(1);
+			check(__ENV.SUITE_VAR, { 'env is shared': (v) => v === 'shared' });
+		}
+	`
+	const failingScript = `
+		import { Counter } from 'k6/metrics';
+
+		const requests = new Counter('requests');
+
+		export const options = {
+			iterations: 1,
+			thresholds: { requests: ['count>5'] },
+		};
+
+		export default function () {
+			requests.add(1);
+		}
+	`
+
+	newSuiteTestState := func(t *testing.T, suite string) *GlobalTestState {
+		ts := NewGlobalTestState(t)
+		require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "tests", "pass.js"), []byte(passingScript), 0o644))
+		require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "tests", "fail.js"), []byte(failingScript), 0o644))
+		require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "suite.yaml"), []byte(suite), 0o644))
+		return ts
+	}
+
+	readReport := func(t *testing.T, ts *GlobalTestState) map[string]any {
+		data, err := fsext.ReadFile(ts.FS, "report.json")
+		require.NoError(t, err)
+		var report map[string]any
+		require.NoError(t, json.Unmarshal(data, &report))
+		return report
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newSuiteTestState(t, `
+env:
+  SUITE_VAR: shared
+tests:
+  - name: first
+    script: tests/pass.js
+  - name: second
+    script: tests/fail.js
+  - name: third
+    script: tests/pass.js
+    args: ["--iterations", "3"]
+`)
+		ts.CmdArgs = []string{"k6", "suite", "--summary-export=report.json", "suite.yaml"}
+		ts.ExpectedExitCode = int(exitcodes.ThresholdsHaveFailed)
+
+		cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+		stdout := ts.Stdout.String()
+		t.Log(stdout)
+		assert.Contains(t, stdout, "✓ first .... passed")
+		assert.Contains(t, stdout, "✗ second ... failed")
+		assert.Contains(t, stdout, "(exit code 99)")
+		assert.Contains(t, stdout, "✗ requests: count>5")
+		assert.Contains(t, stdout, "✓ third .... passed")
+		assert.Contains(t, stdout, "tests......: 3 total, 2 passed, 1 failed, 0 skipped")
+		assert.Contains(t, stdout, "checks.....: 100.00% 5 out of 5")
+		assert.Contains(t, stdout, "iterations.: 6")
+		assert.Contains(t, ts.Stderr.String(), "1 of 3 tests in the suite have failed")
+
+		report := readReport(t, ts)
+		assert.Equal(t, false, report["passed"])
+		assert.Equal(t, map[string]any{"count": 6.0}, report["metrics"].(map[string]any)["requests"])
+
+		tests := report["tests"].([]any)
+		require.Len(t, tests, 3)
+		second := tests[1].(map[string]any)
+		assert.Equal(t, "second", second["name"])
+		assert.Equal(t, "failed", second["status"])
+		assert.Equal(t, 99.0, second["exit_code"])
+		assert.Equal(t, []any{"requests: count>5"}, second["failed_thresholds"])
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newSuiteTestState(t, `
+failFast: true
+tests:
+  - script: tests/fail.js
+  - script: tests/pass.js
+`)
+		ts.CmdArgs = []string{"k6", "suite", "--summary-export=report.json", "suite.yaml"}
+		ts.ExpectedExitCode = int(exitcodes.ThresholdsHaveFailed)
+
+		cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+		stdout := ts.Stdout.String()
+		t.Log(stdout)
+		assert.Contains(t, stdout, "✗ tests/fail.js ... failed")
+		assert.Contains(t, stdout, "- tests/pass.js ... skipped")
+		assert.Contains(t, stdout, "tests......: 2 total, 0 passed, 1 failed, 1 skipped")
+
+		tests := readReport(t, ts)["tests"].([]any)
+		assert.Equal(t, "skipped", tests[1].(map[string]any)["status"])
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newSuiteTestState(t, `
+env:
+  SUITE_VAR: shared
+tests:
+  - name: first
+    script: tests/pass.js
+  - name: second
+    script: tests/pass.js
+`)
+		ts.CmdArgs = []string{"k6", "suite", "--parallel", "suite.yaml"}
+
+		cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+		stdout := ts.Stdout.String()
+		t.Log(stdout)
+		assert.Contains(t, stdout, "✓ first .... passed")
+		assert.Contains(t, stdout, "✓ second ... passed")
+		assert.Contains(t, stdout, "tests......: 2 total, 2 passed, 0 failed, 0 skipped")
+		assert.Contains(t, stdout, "iterations.: 4")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newSuiteTestState(t, `
+tests:
+  - name: first
+`)
+		ts.CmdArgs = []string{"k6", "suite", "suite.yaml"}
+		ts.ExpectedExitCode = int(exitcodes.InvalidConfig)
+
+		cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+		assert.Contains(t, ts.Stderr.String(), "test #1 of the suite doesn't have a script")
+	})
+}
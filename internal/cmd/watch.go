@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/internal/js"
+)
+
+// watchInterval is how often the files of the script are checked for changes
+// with --watch.
+const watchInterval = 500 * time.Millisecond
+
+// fileVersion identifies the content of a watched file, without reading it.
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// startWatch reloads the script when its files change, if --watch was used,
+// so the VUs run the new code from their next iteration on. The returned
+// function stops watching.
+func (c *cmdRun) startWatch(
+	ctx context.Context, test *loadedAndConfiguredTest, logger logrus.FieldLogger,
+) (func(), error) {
+	if !c.watch {
+		return func() {}, nil
+	}
+	runner, ok := test.initRunner.(*js.Runner)
+	if !ok || test.sourceRootPath == "-" || test.source.URL.Scheme != "file" {
+		return nil, errors.New("--watch can only be used with script files")
+	}
+
+	w := &scriptWatcher{
+		c:      c,
+		test:   test,
+		runner: runner,
+		logger: logger,
+	}
+	w.files = w.versions(w.watchedFiles(test.moduleResolver.Imported()))
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.check()
+			}
+		}
+	}()
+	logger.Infof("Watching %d files of the script, their changes are applied to the next iterations", len(w.files))
+	return func() {
+		cancel()
+		<-done
+	}, nil
+}
+
+// scriptWatcher polls the local files of the script, the main one and the
+// imported modules, and reloads the script when any of them changes.
+type scriptWatcher struct {
+	c      *cmdRun
+	test   *loadedAndConfiguredTest
+	runner *js.Runner
+	logger logrus.FieldLogger
+
+	files map[string]fileVersion
+}
+
+// watchedFiles returns the paths of the main file and of the local modules
+// among the imported ones.
+func (w *scriptWatcher) watchedFiles(imported []string) []string {
+	seen := map[string]bool{w.test.source.URL.Path: true}
+	paths := []string{w.test.source.URL.Path}
+	for _, specifier := range imported {
+		u, err := url.Parse(specifier)
+		if err != nil || u.Scheme != "file" || seen[u.Path] {
+			continue
+		}
+		seen[u.Path] = true
+		paths = append(paths, u.Path)
+	}
+	sort.Strings(paths[1:])
+	return paths
+}
+
+// versions returns the current versions of the files, the ones that can't be
+// read have the zero version, so their creation is noticed as a change. The
+// files are checked on the filesystem of the global state, since the one of
+// the test caches them.
+func (w *scriptWatcher) versions(paths []string) map[string]fileVersion {
+	fs := w.c.gs.FS
+	files := make(map[string]fileVersion, len(paths))
+	for _, path := range paths {
+		fi, err := fs.Stat(filepath.FromSlash(path))
+		if err != nil {
+			files[path] = fileVersion{}
+			continue
+		}
+		files[path] = fileVersion{modTime: fi.ModTime(), size: fi.Size()}
+	}
+	return files
+}
+
+// check reloads the script if any of its files changed since the last check.
+func (w *scriptWatcher) check() {
+	paths := make([]string, 0, len(w.files))
+	for path := range w.files {
+		paths = append(paths, path)
+	}
+	current := w.versions(paths)
+	changed := false
+	for _, path := range paths {
+		if current[path] != w.files[path] {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return
+	}
+	w.files = current
+	w.reload()
+}
+
+// reload bundles the script again from its files, if that fails the VUs keep
+// running the previous code until the next change.
+func (w *scriptWatcher) reload() {
+	src, fileSystems, _, err := readSource(w.c.gs, w.test.sourceRootPath)
+	if err != nil {
+		w.logger.WithError(err).Error("Couldn't read the changed script, the previous code is still used")
+		return
+	}
+	pwd := src.URL.JoinPath("../")
+	moduleResolver := js.NewModuleResolver(pwd, w.test.preInitState, fileSystems)
+	if err = moduleResolver.LoadMainModule(pwd, src.URL.String(), src.Data); err == nil {
+		err = w.runner.Reload(src, fileSystems, moduleResolver)
+	}
+	// the imports might have changed, so the files to watch are refreshed even
+	// if the script couldn't be reloaded
+	w.files = w.versions(w.watchedFiles(moduleResolver.Imported()))
+	if err != nil {
+		w.logger.WithError(err).Error("Couldn't reload the changed script, the previous code is still used")
+		return
+	}
+	w.logger.Info("The script was reloaded, the next iterations run the new code")
+}
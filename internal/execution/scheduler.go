@@ -2,6 +2,7 @@ package execution
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"sync"
@@ -11,6 +12,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
 	"go.k6.io/k6/internal/ui/pb"
 	"go.k6.io/k6/lib"
 	"go.k6.io/k6/metrics"
@@ -226,6 +228,11 @@ func (e *Scheduler) emitVUsAndVUsMax(ctx context.Context, out chan<- metrics.Sam
 			Time: t,
 		}
 		metrics.PushIfNotDone(ctx, out, samples)
+		if e.state.Test.Options.ConnectionMetrics.Bool {
+			connSamples := e.state.Test.ConnStats.Samples(
+				t, tags, e.state.Test.Options.SystemTags, e.state.Test.BuiltinMetrics)
+			metrics.PushIfNotDone(ctx, out, metrics.Samples(connSamples))
+		}
 	}
 
 	ticker := time.NewTicker(1 * time.Second)
@@ -477,7 +484,7 @@ func (e *Scheduler) Run(globalCtx, runCtx context.Context, samplesOut chan<- met
 			actuallyRanSetup = true
 			if err := e.state.Test.Runner.Setup(withExecStateCtx, samplesOut); err != nil {
 				logger.WithField("error", err).Debug("setup() aborted by error")
-				return nil, err
+				return nil, withPhaseExitCode(err, exitcodes.SetupFailed)
 			}
 			return e.state.Test.Runner.GetSetupData(), nil
 		})
@@ -533,7 +540,7 @@ func (e *Scheduler) Run(globalCtx, runCtx context.Context, samplesOut chan<- met
 		_, err := e.controller.GetOrCreateData("teardown", func() ([]byte, error) {
 			if err := e.state.Test.Runner.Teardown(globalCtx, samplesOut); err != nil {
 				logger.WithField("error", err).Debug("teardown() aborted by error")
-				return nil, err
+				return nil, withPhaseExitCode(err, exitcodes.TeardownFailed)
 			}
 			return nil, nil
 		})
@@ -594,3 +601,29 @@ func (e *Scheduler) SetPaused(pause bool) error {
 	}
 	return e.state.Resume()
 }
+
+// withPhaseExitCode replaces the generic exit code of the script exceptions
+// thrown in setup() or teardown() with the given, more specific, one. The exit
+// codes of timeouts and test.abort() calls are kept.
+func withPhaseExitCode(err error, exitCode exitcodes.ExitCode) error {
+	var ecerr errext.HasExitCode
+	if errors.As(err, &ecerr) && ecerr.ExitCode() != exitcodes.ScriptException {
+		return err
+	}
+	return phaseError{err, exitCode}
+}
+
+type phaseError struct {
+	error
+	exitCode exitcodes.ExitCode
+}
+
+func (pe phaseError) Unwrap() error {
+	return pe.error
+}
+
+func (pe phaseError) ExitCode() exitcodes.ExitCode {
+	return pe.exitCode
+}
+
+var _ errext.HasExitCode = phaseError{}
@@ -404,6 +404,8 @@ func TestExecutionInfoAll(t *testing.T) {
 				BuiltinMetrics: builtinMetrics,
 				Registry:       registry,
 				Usage:          usage.New(),
+				RunMetadata:    lib.NewRunMetadata(nil),
+				ConnStats:      lib.NewConnStats(),
 			}
 			sourceData := &loader.SourceData{
 				URL:  &url.URL{Path: "/script.js"},
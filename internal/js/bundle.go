@@ -22,6 +22,7 @@ import (
 	"go.k6.io/k6/internal/js/compiler"
 	"go.k6.io/k6/internal/js/eventloop"
 	"go.k6.io/k6/internal/js/modules/k6/webcrypto"
+	"go.k6.io/k6/internal/js/tc55/abort"
 	"go.k6.io/k6/internal/js/tc55/timers"
 	"go.k6.io/k6/internal/lib/consts"
 	"go.k6.io/k6/internal/loader"
@@ -41,6 +42,7 @@ type Bundle struct {
 	preInitState      *lib.TestPreInitState
 
 	filesystems map[string]fsext.Fs
+	sharedFiles *sharedFiles
 	pwd         *url.URL
 
 	callableExports map[string]struct{}
@@ -102,6 +104,7 @@ func newBundle(
 		CompatibilityMode: compatMode,
 		callableExports:   make(map[string]struct{}),
 		filesystems:       filesystems,
+		sharedFiles:       &sharedFiles{},
 		pwd:               src.PWD,
 		preInitState:      piState,
 		ModuleResolver:    moduleResolver,
@@ -388,19 +391,29 @@ func (b *Bundle) instantiate(vuImpl *moduleVUImpl, vuID uint64) (*BundleInstance
 		allowOnlyOpenedFiles(b.filesystems["file"])
 	}
 
-	rt.SetRandSource(common.NewRandSource())
+	if seed := b.Options.RandomSeed; seed.Valid {
+		// every VU generates a different, but reproducible, sequence
+		rt.SetRandSource(common.NewSeededRandSource(seed.Int64, vuID))
+	} else {
+		rt.SetRandSource(common.NewRandSource())
+	}
 
 	return bi, nil
 }
 
 // registerGlobals registers the globals for the runtime.
-// e.g. timers and webcrypto.
+// e.g. timers, AbortController and webcrypto.
 func registerGlobals(vuImpl *moduleVUImpl) error {
 	err := timers.SetupGlobally(vuImpl)
 	if err != nil {
 		return err
 	}
 
+	err = abort.SetupGlobally(vuImpl)
+	if err != nil {
+		return err
+	}
+
 	return webcrypto.SetupGlobally(vuImpl)
 }
 
@@ -471,7 +484,7 @@ func (b *Bundle) setInitGlobals(rt *sobek.Runtime, vu *moduleVUImpl, modSys *mod
 
 	mustSet("require", impl.require)
 
-	mustSet("open", func(filename string, args ...string) (sobek.Value, error) {
+	mustSet("open", func(filename string, args ...sobek.Value) (sobek.Value, error) {
 		// TODO fix in stack traces
 		if vu.state != nil {
 			return nil, fmt.Errorf(cantBeUsedOutsideInitContextMsg, "open")
@@ -501,7 +514,7 @@ func (b *Bundle) setInitGlobals(rt *sobek.Runtime, vu *moduleVUImpl, modSys *mod
 			}
 		}
 
-		return openImpl(rt, b.filesystems["file"], pwd, filename, args...)
+		return openImpl(rt, b.filesystems["file"], b.sharedFiles, pwd, filename, args...)
 	})
 	warnAboutModuleMixing := func(name string) {
 		warnFunc := rt.ToValue(func() error {
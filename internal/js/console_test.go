@@ -77,6 +77,8 @@ func getSimpleRunner(tb testing.TB, filename, data string, opts ...interface{})
 		Registry:       registry,
 		LookupEnv:      func(_ string) (val string, ok bool) { return "", false },
 		Usage:          usage.New(),
+		RunMetadata:    lib.NewRunMetadata(nil),
+		ConnStats:      lib.NewConnStats(),
 	}
 	moduleResolver := NewModuleResolver(loader.Dir(filenameURL), preInitState, fsResolvers)
 	return New(
@@ -118,6 +120,8 @@ func getSimpleArchiveRunner(tb testing.TB, arc *lib.Archive, opts ...interface{}
 		BuiltinMetrics: builtinMetrics,
 		Registry:       registry,
 		Usage:          usage.New(),
+		RunMetadata:    lib.NewRunMetadata(nil),
+		ConnStats:      lib.NewConnStats(),
 	}
 	moduleResolver := NewModuleResolver(arc.PwdURL, preInitState, arc.Filesystems)
 	return NewFromArchive(preInitState, arc, moduleResolver)
@@ -0,0 +1,74 @@
+package js
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/lib/netext/httpext"
+)
+
+// cassettePath returns the absolute path of the cassette file, which is
+// relative to the script like the files that open() reads.
+func (r *Runner) cassettePath(path string) string {
+	return fsext.Abs(r.Bundle.pwd.Path, path)
+}
+
+// setHTTPCassette prepares the cassette to which the HTTP responses are
+// recorded or loads the one from which they are replayed, according to the
+// httpRecord and httpReplay options.
+func (r *Runner) setHTTPCassette(opts lib.Options) error {
+	r.httpCassette, r.httpCassetteRecording = nil, false
+
+	if opts.HTTPRecord.String != "" {
+		r.httpCassette, r.httpCassetteRecording = httpext.NewCassette(), true
+		return nil
+	}
+	if opts.HTTPReplay.String == "" {
+		return nil
+	}
+
+	// The cassette is read through the same filesystem as the files that the
+	// script opens, so it's included in the archives of the test.
+	path := r.cassettePath(opts.HTTPReplay.String)
+	fs := r.Bundle.filesystems["file"]
+	if allower, ok := fs.(fsext.PathAllower); ok {
+		allower.AllowPath(path)
+	}
+	data, err := fsext.ReadFile(fs, path)
+	if err != nil {
+		return fmt.Errorf("couldn't read the HTTP cassette: %w", err)
+	}
+	r.httpCassette, err = httpext.LoadCassette(data)
+	if err != nil {
+		return fmt.Errorf("couldn't load the HTTP cassette %q: %w", path, err)
+	}
+	return nil
+}
+
+// httpTransport returns the transport that the VUs use for HTTP requests,
+// which records or replays the responses if there is a cassette.
+func (r *Runner) httpTransport(transport *http.Transport) http.RoundTripper {
+	switch {
+	case r.httpCassette == nil:
+		return transport
+	case r.httpCassetteRecording:
+		return r.httpCassette.RecordingTransport(transport)
+	default:
+		return r.httpCassette.ReplayingTransport()
+	}
+}
+
+// RecordedHTTPCassette returns the absolute path and the contents of the
+// cassette with the recorded HTTP responses, if the httpRecord option is set.
+func (r *Runner) RecordedHTTPCassette() (string, []byte, error) {
+	if !r.httpCassetteRecording {
+		return "", nil, nil
+	}
+	data, err := r.httpCassette.Marshal()
+	if err != nil {
+		return "", nil, err
+	}
+	return r.cassettePath(r.Bundle.Options.HTTPRecord.String), data, nil
+}
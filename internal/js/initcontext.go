@@ -5,35 +5,162 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/grafana/sobek"
 	"github.com/sirupsen/logrus"
 
 	"go.k6.io/k6/internal/loader"
+	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/lib/fsext"
 )
 
 const cantBeUsedOutsideInitContextMsg = `the "%s" function is only available in the init stage ` +
 	`(i.e. the global scope), see https://grafana.com/docs/k6/latest/using-k6/test-lifecycle/ for more information`
 
+// openOptions are the options of open(), given as its third argument.
+type openOptions struct {
+	// Shared makes all the VUs use the same contents of the file, read once,
+	// instead of each one reading its own copy of it.
+	Shared bool `js:"shared"`
+}
+
 // openImpl implements openImpl() in the init context and will read and return the
 // contents of a file. If the second argument is "b" it returns an ArrayBuffer
-// instance, otherwise a string representation.
-func openImpl(rt *sobek.Runtime, fs fsext.Fs, basePWD *url.URL, filename string, args ...string) (sobek.Value, error) {
+// instance, otherwise a string representation. With the shared option the
+// contents are read from the shared files instead of the filesystem, and in
+// the binary mode a read-only view of them is returned instead of an
+// ArrayBuffer, see sharedFile.binary().
+func openImpl(
+	rt *sobek.Runtime, fs fsext.Fs, shared *sharedFiles, basePWD *url.URL, filename string, args ...sobek.Value,
+) (sobek.Value, error) {
+	var mode string
+	if len(args) > 0 && !common.IsNullish(args[0]) {
+		mode = args[0].String()
+	}
+	var opts openOptions
+	if len(args) > 1 && !common.IsNullish(args[1]) {
+		if err := rt.ExportTo(args[1], &opts); err != nil {
+			return nil, fmt.Errorf("invalid open() options: %w", err)
+		}
+	}
+
 	// Strip file scheme if available as we should support only this scheme
 	filename = strings.TrimPrefix(filename, "file://")
-	data, err := readFile(fs, fsext.Abs(basePWD.Path, filename))
+	filename = fsext.Abs(basePWD.Path, filename)
+
+	if opts.Shared {
+		file, err := shared.get(fs, filename)
+		if err != nil {
+			return nil, err
+		}
+		if mode == "b" {
+			return file.binary(rt)
+		}
+		return rt.ToValue(file.text()), nil
+	}
+
+	data, err := readFile(fs, filename)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(args) > 0 && args[0] == "b" {
+	if mode == "b" {
 		ab := rt.NewArrayBuffer(data)
 		return rt.ToValue(&ab), nil
 	}
 	return rt.ToValue(string(data)), nil
 }
 
+// sharedFiles are the files opened with the shared option, they are read once
+// and their contents are used by all the VUs of the bundle, so large files
+// don't take memory for each VU.
+type sharedFiles struct {
+	mu    sync.Mutex
+	files map[string]*sharedFile
+}
+
+// sharedFile is the contents of a shared file, which all the VUs read
+// concurrently, so it must never be changed.
+type sharedFile struct {
+	data []byte
+
+	textOnce sync.Once
+	textData string
+}
+
+// text returns the contents of the file as a string, which is converted once.
+// Note that the JS runtime keeps its own copy of the strings that aren't ASCII.
+func (f *sharedFile) text() string {
+	f.textOnce.Do(func() {
+		f.textData = string(f.data)
+	})
+	return f.textData
+}
+
+// binary returns the read-only view of the contents of the file for the binary
+// mode. An ArrayBuffer over the shared data can't be used, since writing to it
+// from one VU would race with the reads of the others. The view has the
+// byteLength of the file and, like ArrayBuffer, a slice(begin, end) method,
+// which returns an ArrayBuffer with its own copy of that part of the file.
+func (f *sharedFile) binary(rt *sobek.Runtime) (sobek.Value, error) {
+	slice := func(call sobek.FunctionCall) sobek.Value {
+		size := int64(len(f.data))
+		begin := relativeIndex(call.Argument(0), 0, size)
+		end := relativeIndex(call.Argument(1), size, size)
+		if end < begin {
+			end = begin
+		}
+		ab := rt.NewArrayBuffer(append([]byte{}, f.data[begin:end]...))
+		return rt.ToValue(&ab)
+	}
+
+	view := rt.NewObject()
+	if err := view.DefineDataProperty(
+		"byteLength", rt.ToValue(len(f.data)), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE); err != nil {
+		return nil, err
+	}
+	if err := view.DefineDataProperty(
+		"slice", rt.ToValue(slice), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// relativeIndex converts an argument of slice() to an index in [0, size], the
+// negative ones are relative to the end, like with ArrayBuffer.slice().
+func relativeIndex(arg sobek.Value, def, size int64) int64 {
+	if common.IsNullish(arg) {
+		return def
+	}
+	index := arg.ToInteger()
+	if index < 0 {
+		index += size
+	}
+	return max(0, min(index, size))
+}
+
+// get returns the shared file with the given absolute path, reading it if it
+// wasn't opened before.
+func (s *sharedFiles) get(fs fsext.Fs, filename string) (*sharedFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if file, ok := s.files[filename]; ok {
+		return file, nil
+	}
+	data, err := readFile(fs, filename)
+	if err != nil {
+		return nil, err
+	}
+	if s.files == nil {
+		s.files = make(map[string]*sharedFile)
+	}
+	file := &sharedFile{data: data}
+	s.files[filename] = file
+	return file, nil
+}
+
 func readFile(fileSystem fsext.Fs, filename string) (data []byte, err error) {
 	defer func() {
 		if errors.Is(err, fsext.ErrPathNeverRequestedBefore) {
@@ -269,6 +269,53 @@ func TestInitContextOpen(t *testing.T) {
 		})
 	}
 
+	t.Run("Shared", func(t *testing.T) {
+		t.Parallel()
+		fs := fsext.NewMemMapFs()
+		require.NoError(t, fs.MkdirAll("/path/to", 0o755))
+		require.NoError(t, fsext.WriteFile(fs, "/path/to/file.bin", []byte("hi!\x0f\xff\x01"), 0o644))
+		b, err := getSimpleBundle(t, "/path/to/script.js", `
+			const bin = open("/path/to/file.bin", "b", { shared: true });
+			export let size = bin.byteLength;
+			export let all = bin.slice();
+			export let part = bin.slice(1, -2);
+			export let changed = (() => {
+				const copy = new Uint8Array(bin.slice());
+				copy[0] = 0;
+				return new Uint8Array(bin.slice(0, 1))[0];
+			})();
+			export let text = open("./file.bin", null, { shared: true });
+			export default function() {}
+		`, fs)
+		require.NoError(t, err)
+
+		bi1, err := b.Instantiate(context.Background(), 1)
+		require.NoError(t, err)
+		bi2, err := b.Instantiate(context.Background(), 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(6), bi1.getExported("size").Export())
+		all1, ok := bi1.getExported("all").Export().(sobek.ArrayBuffer)
+		require.True(t, ok)
+		all2, ok := bi2.getExported("all").Export().(sobek.ArrayBuffer)
+		require.True(t, ok)
+		assert.Equal(t, []byte{104, 105, 33, 15, 255, 1}, all1.Bytes())
+		assert.NotSame(t, &all1.Bytes()[0], &all2.Bytes()[0], "the VUs must get their own copies")
+		part, ok := bi1.getExported("part").Export().(sobek.ArrayBuffer)
+		require.True(t, ok)
+		assert.Equal(t, []byte{105, 33, 15}, part.Bytes())
+		assert.Equal(t, int64(104), bi1.getExported("changed").Export(), "the shared contents must be read-only")
+		assert.Equal(t, "hi!\x0f\xff\x01", bi1.getExported("text").Export())
+	})
+
+	t.Run("SharedInvalidOptions", func(t *testing.T) {
+		t.Parallel()
+		fs := fsext.NewMemMapFs()
+		require.NoError(t, fsext.WriteFile(fs, "/file.txt", []byte("content"), 0o644))
+		_, err := getSimpleBundle(t, "/script.js", `open("/file.txt", "", 5); export default function() {}`, fs)
+		require.ErrorContains(t, err, "invalid open() options")
+	})
+
 	t.Run("Nonexistent", func(t *testing.T) {
 		t.Parallel()
 		path := filepath.FromSlash("/nonexistent.txt")
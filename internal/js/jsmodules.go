@@ -12,11 +12,20 @@ import (
 	"go.k6.io/k6/internal/js/modules/k6/encoding"
 	"go.k6.io/k6/internal/js/modules/k6/execution"
 	"go.k6.io/k6/internal/js/modules/k6/experimental/csv"
+	"go.k6.io/k6/internal/js/modules/k6/experimental/dns"
+	"go.k6.io/k6/internal/js/modules/k6/experimental/faker"
 	"go.k6.io/k6/internal/js/modules/k6/experimental/fs"
+	"go.k6.io/k6/internal/js/modules/k6/experimental/fuzz"
+	"go.k6.io/k6/internal/js/modules/k6/experimental/jsonschema"
+	"go.k6.io/k6/internal/js/modules/k6/experimental/oauth"
+	"go.k6.io/k6/internal/js/modules/k6/experimental/smtp"
+	"go.k6.io/k6/internal/js/modules/k6/experimental/sqlload"
 	"go.k6.io/k6/internal/js/modules/k6/experimental/streams"
+	exptls "go.k6.io/k6/internal/js/modules/k6/experimental/tls"
 	expws "go.k6.io/k6/internal/js/modules/k6/experimental/websockets"
 	"go.k6.io/k6/internal/js/modules/k6/grpc"
 	"go.k6.io/k6/internal/js/modules/k6/metrics"
+	"go.k6.io/k6/internal/js/modules/k6/random"
 	"go.k6.io/k6/internal/js/modules/k6/secrets"
 	"go.k6.io/k6/internal/js/modules/k6/timers"
 	"go.k6.io/k6/internal/js/modules/k6/ws"
@@ -42,15 +51,24 @@ func getInternalJSModules() map[string]interface{} {
 		"k6/http":        http.New(),
 		"k6/net/grpc":    grpc.New(),
 		"k6/metrics":     metrics.New(),
+		"k6/random":      random.New(),
 		"k6/secrets":     secrets.New(),
 		"k6/timers":      timers.New(),
 		"k6/ws":          ws.New(),
 
 		// Experimental modules
 		"k6/experimental/csv":        csv.New(),
+		"k6/experimental/dns":        dns.New(),
+		"k6/experimental/faker":      faker.New(),
 		"k6/experimental/fs":         fs.New(),
+		"k6/experimental/fuzz":       fuzz.New(),
+		"k6/experimental/jsonschema": jsonschema.New(),
+		"k6/experimental/oauth":      oauth.New(),
 		"k6/experimental/redis":      redis.New(),
+		"k6/experimental/smtp":       smtp.New(),
+		"k6/experimental/sql-load":   sqlload.New(),
 		"k6/experimental/streams":    streams.New(),
+		"k6/experimental/tls":        exptls.New(),
 		"k6/experimental/websockets": expws.New(),
 
 		// Removed modules
@@ -0,0 +1,39 @@
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/internal/js/modules/k6/browser/common"
+)
+
+// mapAccessibility to the JS module.
+func mapAccessibility(vu moduleVU, a *common.Accessibility) mapping {
+	return mapping{
+		"snapshot": func(opts sobek.Value) (*sobek.Promise, error) {
+			popts := common.NewAccessibilitySnapshotOptions()
+			if err := popts.Parse(vu.Context(), opts); err != nil {
+				return nil, fmt.Errorf("parsing accessibility snapshot options: %w", err)
+			}
+			return promise(vu, func() (any, error) {
+				node, err := a.Snapshot(popts)
+				if err != nil || node == nil {
+					return nil, err //nolint:wrapcheck
+				}
+				// The node is converted to a plain object so that the properties
+				// that don't apply to it are omitted, like in Playwright.
+				b, err := json.Marshal(node)
+				if err != nil {
+					return nil, fmt.Errorf("marshaling accessibility snapshot: %w", err)
+				}
+				var snapshot map[string]any
+				if err := json.Unmarshal(b, &snapshot); err != nil {
+					return nil, fmt.Errorf("unmarshaling accessibility snapshot: %w", err)
+				}
+				return snapshot, nil
+			}), nil
+		},
+	}
+}
@@ -57,6 +57,22 @@ func mapBrowser(vu moduleVU) mapping {
 				return mapBrowserContext(vu, bctx), nil
 			}), nil
 		},
+		"startTracing": func(opts sobek.Value) (*sobek.Promise, error) {
+			topts := common.NewTracingOptions()
+			if err := topts.Parse(vu.Context(), opts); err != nil {
+				return nil, fmt.Errorf("parsing browser.startTracing options: %w", err)
+			}
+			return promise(vu, func() (any, error) {
+				b, err := vu.browser()
+				if err != nil {
+					return nil, err
+				}
+				return nil, b.StartTracing(nil, topts, vu.filePersister) //nolint:wrapcheck
+			}), nil
+		},
+		"stopTracing": func() *sobek.Promise {
+			return stopTracing(vu, vu.browser)
+		},
 		"userAgent": func() (string, error) {
 			b, err := vu.browser()
 			if err != nil {
@@ -95,6 +111,32 @@ func mapBrowser(vu moduleVU) mapping {
 	}
 }
 
+// stopTracing stops the active trace of the browser and resolves with it.
+func stopTracing(vu moduleVU, browser func() (*common.Browser, error)) *sobek.Promise {
+	rt := vu.Runtime()
+	promise, res, rej := rt.NewPromise()
+	callback := vu.RegisterCallback()
+	go func() {
+		b, err := browser()
+		var trace []byte
+		if err == nil {
+			trace, err = b.StopTracing()
+		}
+		if err != nil {
+			callback(func() error {
+				return rej(err)
+			})
+			return
+		}
+
+		callback(func() error {
+			return res(rt.NewArrayBuffer(trace))
+		})
+	}()
+
+	return promise
+}
+
 func initBrowserContext(bctx *common.BrowserContext, testRunID string) error {
 	// Setting a k6 object which will contain k6 specific metadata
 	// on the current test run. This allows external applications
@@ -31,9 +31,10 @@ func customMappings() map[string]string {
 		"elementHandleAPI.query":    "$",
 		"elementHandleAPI.queryAll": "$$",
 		// getters
-		"pageAPI.getKeyboard":    "keyboard",
-		"pageAPI.getMouse":       "mouse",
-		"pageAPI.getTouchscreen": "touchscreen",
+		"pageAPI.getAccessibility": "accessibility",
+		"pageAPI.getKeyboard":      "keyboard",
+		"pageAPI.getMouse":         "mouse",
+		"pageAPI.getTouchscreen":   "touchscreen",
 		// internal methods
 		"elementHandleAPI.objectID":    "",
 		"frameAPI.id":                  "",
@@ -215,6 +216,12 @@ func TestMappings(t *testing.T) {
 				return mapTouchscreen(moduleVU{VU: vu}, &common.Touchscreen{})
 			},
 		},
+		"mapAccessibility": {
+			apiInterface: (*accessibilityAPI)(nil),
+			mapp: func() mapping {
+				return mapAccessibility(moduleVU{VU: vu}, &common.Accessibility{})
+			},
+		},
 		"mapKeyboard": {
 			apiInterface: (*keyboardAPI)(nil),
 			mapp: func() mapping {
@@ -282,6 +289,8 @@ type browserAPI interface {
 	NewContext(opts *common.BrowserContextOptions) (*common.BrowserContext, error)
 	NewPage(opts *common.BrowserContextOptions) (*common.Page, error)
 	On(string) (bool, error)
+	StartTracing(opts sobek.Value) error
+	StopTracing() (sobek.ArrayBuffer, error)
 	UserAgent() string
 	Version() string
 }
@@ -312,6 +321,7 @@ type pageAPI interface { //nolint:interfacebloat
 	Check(selector string, opts sobek.Value) error
 	Click(selector string, opts sobek.Value) error
 	Close(opts sobek.Value) error
+	CompareScreenshot(baselinePath string, opts sobek.Value) (*common.ScreenshotComparison, error)
 	Content() (string, error)
 	Context() *common.BrowserContext
 	Dblclick(selector string, opts sobek.Value) error
@@ -331,6 +341,7 @@ type pageAPI interface { //nolint:interfacebloat
 	GetByTitle(title string, opts *common.GetByBaseOptions) *common.Locator
 	GetByTestId(testID string) *common.Locator
 	GetByText(text string, opts *common.GetByBaseOptions) *common.Locator
+	GetAccessibility() *common.Accessibility
 	GetKeyboard() *common.Keyboard
 	GetMouse() *common.Mouse
 	GetTouchscreen() *common.Touchscreen
@@ -364,6 +375,8 @@ type pageAPI interface { //nolint:interfacebloat
 	SetExtraHTTPHeaders(headers map[string]string) error
 	SetInputFiles(selector string, files sobek.Value, opts sobek.Value) error
 	SetViewportSize(viewportSize sobek.Value) error
+	StartTracing(opts sobek.Value) error
+	StopTracing() (sobek.ArrayBuffer, error)
 	Tap(selector string, opts sobek.Value) error
 	TextContent(selector string, opts sobek.Value) (string, bool, error)
 	ThrottleCPU(common.CPUProfile) error
@@ -600,6 +613,11 @@ type locatorAPI interface { //nolint:interfacebloat
 	WaitFor(opts sobek.Value) error
 }
 
+// accessibilityAPI is the interface of the accessibility of a page.
+type accessibilityAPI interface {
+	Snapshot(opts sobek.Value) (map[string]any, error)
+}
+
 // keyboardAPI is the interface of a keyboard input device.
 type keyboardAPI interface {
 	Down(key string) error
@@ -21,6 +21,7 @@ import (
 func mapPage(vu moduleVU, p *common.Page) mapping { //nolint:gocognit,cyclop
 	rt := vu.Runtime()
 	maps := mapping{
+		"accessibility": mapAccessibility(vu, p.GetAccessibility()),
 		"bringToFront": func() *sobek.Promise {
 			return promise(vu, func() (any, error) {
 				return nil, p.BringToFront() //nolint:wrapcheck
@@ -57,6 +58,15 @@ func mapPage(vu moduleVU, p *common.Page) mapping { //nolint:gocognit,cyclop
 				return nil, p.Close() //nolint:wrapcheck
 			})
 		},
+		"compareScreenshot": func(baselinePath string, opts sobek.Value) (*sobek.Promise, error) {
+			popts := common.NewScreenshotComparisonOptions()
+			if err := popts.Parse(vu.Context(), opts); err != nil {
+				return nil, fmt.Errorf("parsing page compare screenshot options: %w", err)
+			}
+			return promise(vu, func() (any, error) {
+				return p.CompareScreenshot(baselinePath, popts, vu.filePersister) //nolint:wrapcheck
+			}), nil
+		},
 		"content": func() *sobek.Promise {
 			return promise(vu, func() (any, error) {
 				return p.Content() //nolint:wrapcheck
@@ -404,6 +414,20 @@ func mapPage(vu moduleVU, p *common.Page) mapping { //nolint:gocognit,cyclop
 
 			return promise, nil
 		},
+		"startTracing": func(opts sobek.Value) (*sobek.Promise, error) {
+			topts := common.NewTracingOptions()
+			if err := topts.Parse(vu.Context(), opts); err != nil {
+				return nil, fmt.Errorf("parsing page.startTracing options: %w", err)
+			}
+			return promise(vu, func() (any, error) {
+				return nil, p.Context().Browser().StartTracing(p, topts, vu.filePersister) //nolint:wrapcheck
+			}), nil
+		},
+		"stopTracing": func() *sobek.Promise {
+			return stopTracing(vu, func() (*common.Browser, error) {
+				return p.Context().Browser(), nil
+			})
+		},
 		"selectOption": func(selector string, values sobek.Value, opts sobek.Value) (*sobek.Promise, error) {
 			popts := common.NewFrameSelectOptionOptions(p.MainFrame().Timeout())
 			if err := popts.Parse(vu.Context(), opts); err != nil {
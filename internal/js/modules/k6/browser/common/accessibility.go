@@ -0,0 +1,356 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/internal/js/modules/k6/browser/k6ext"
+	k6common "go.k6.io/k6/js/common"
+)
+
+// Accessibility provides the accessibility tree of a page.
+type Accessibility struct {
+	ctx     context.Context
+	session session
+}
+
+// NewAccessibility returns a new Accessibility.
+func NewAccessibility(ctx context.Context, s session) *Accessibility {
+	return &Accessibility{
+		ctx:     ctx,
+		session: s,
+	}
+}
+
+// AccessibilitySnapshotOptions are the options of Accessibility.Snapshot.
+type AccessibilitySnapshotOptions struct {
+	// InterestingOnly prunes the nodes that aren't interesting for assistive
+	// technologies, like the generic containers, from the tree.
+	InterestingOnly bool `json:"interestingOnly"`
+}
+
+// NewAccessibilitySnapshotOptions returns the default snapshot options.
+func NewAccessibilitySnapshotOptions() *AccessibilitySnapshotOptions {
+	return &AccessibilitySnapshotOptions{
+		InterestingOnly: true,
+	}
+}
+
+// Parse parses the snapshot options.
+func (o *AccessibilitySnapshotOptions) Parse(ctx context.Context, opts sobek.Value) error {
+	if k6common.IsNullish(opts) {
+		return nil
+	}
+	obj := opts.ToObject(k6ext.Runtime(ctx))
+	for _, k := range obj.Keys() {
+		if k == "interestingOnly" {
+			o.InterestingOnly = obj.Get(k).ToBoolean()
+		}
+	}
+	return nil
+}
+
+// AXNode is a node of the accessibility tree as it's exposed to the scripts.
+// The properties that don't apply to a node are omitted.
+type AXNode struct {
+	Role            string    `json:"role"`
+	Name            string    `json:"name"`
+	Value           any       `json:"value,omitempty"`
+	Description     string    `json:"description,omitempty"`
+	KeyShortcuts    string    `json:"keyshortcuts,omitempty"`
+	RoleDescription string    `json:"roledescription,omitempty"`
+	ValueText       string    `json:"valuetext,omitempty"`
+	Disabled        bool      `json:"disabled,omitempty"`
+	Expanded        *bool     `json:"expanded,omitempty"`
+	Focused         bool      `json:"focused,omitempty"`
+	Modal           bool      `json:"modal,omitempty"`
+	Multiline       bool      `json:"multiline,omitempty"`
+	Multiselectable bool      `json:"multiselectable,omitempty"`
+	Readonly        bool      `json:"readonly,omitempty"`
+	Required        bool      `json:"required,omitempty"`
+	Selected        bool      `json:"selected,omitempty"`
+	Checked         any       `json:"checked,omitempty"` // true, false or "mixed"
+	Pressed         any       `json:"pressed,omitempty"` // true, false or "mixed"
+	Level           int64     `json:"level,omitempty"`
+	ValueMin        *float64  `json:"valuemin,omitempty"`
+	ValueMax        *float64  `json:"valuemax,omitempty"`
+	AutoComplete    string    `json:"autocomplete,omitempty"`
+	HasPopup        string    `json:"haspopup,omitempty"`
+	Invalid         string    `json:"invalid,omitempty"`
+	Orientation     string    `json:"orientation,omitempty"`
+	Children        []*AXNode `json:"children,omitempty"`
+}
+
+// Snapshot returns the accessibility tree of the page, or nil if the page has
+// no accessibility tree.
+func (a *Accessibility) Snapshot(opts *AccessibilitySnapshotOptions) (*AXNode, error) {
+	nodes, err := accessibility.GetFullAXTree().Do(cdp.WithExecutor(a.ctx, a.session))
+	if err != nil {
+		return nil, fmt.Errorf("getting the accessibility tree: %w", err)
+	}
+	return buildAXTree(nodes, opts.InterestingOnly), nil
+}
+
+// axTreeNode is a node of the accessibility tree with its children resolved.
+type axTreeNode struct {
+	node     *accessibility.Node
+	children []*axTreeNode
+}
+
+func buildAXTree(nodes []*accessibility.Node, interestingOnly bool) *AXNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	byID := make(map[accessibility.NodeID]*axTreeNode, len(nodes))
+	for _, n := range nodes {
+		byID[n.NodeID] = &axTreeNode{node: n}
+	}
+	for _, n := range nodes {
+		parent := byID[n.NodeID]
+		for _, id := range n.ChildIDs {
+			if child, ok := byID[id]; ok {
+				parent.children = append(parent.children, child)
+			}
+		}
+	}
+
+	root := byID[nodes[0].NodeID]
+	var interesting map[*axTreeNode]bool
+	if interestingOnly {
+		interesting = make(map[*axTreeNode]bool)
+		root.collectInteresting(interesting, false)
+		// the root is always kept, so there is a single tree
+		interesting[root] = true
+	}
+	return root.serialize(interesting)[0]
+}
+
+// collectInteresting marks the nodes that are interesting for assistive
+// technologies: the focusable nodes and the controls, and the leaf nodes
+// with a name that aren't inside a control.
+func (n *axTreeNode) collectInteresting(interesting map[*axTreeNode]bool, insideControl bool) {
+	if n.isInteresting(insideControl) {
+		interesting[n] = true
+	}
+	insideControl = insideControl || n.isControl()
+	for _, c := range n.children {
+		c.collectInteresting(interesting, insideControl)
+	}
+}
+
+func (n *axTreeNode) isInteresting(insideControl bool) bool {
+	if n.node.Ignored {
+		return false
+	}
+	switch n.role() {
+	case "Ignored", "none", "generic", "InlineTextBox":
+		return false
+	}
+	if n.boolProperty(accessibility.PropertyNameFocusable) || n.isControl() {
+		return true
+	}
+	if insideControl {
+		return false
+	}
+	return n.isLeaf() && n.name() != ""
+}
+
+func (n *axTreeNode) isControl() bool {
+	switch n.role() {
+	case "button", "checkbox", "ColorWell", "combobox", "DisclosureTriangle",
+		"listbox", "menu", "menubar", "menuitem", "menuitemcheckbox",
+		"menuitemradio", "radio", "scrollbar", "searchbox", "slider",
+		"spinbutton", "switch", "tab", "textbox", "tree", "treeitem":
+		return true
+	default:
+		return false
+	}
+}
+
+// isLeaf returns whether the node has no children that assistive
+// technologies would present separately from it, like a heading whose name
+// comes from its text.
+func (n *axTreeNode) isLeaf() bool {
+	if len(n.children) == 0 {
+		return true
+	}
+	switch n.role() {
+	case "doc-cover", "graphics-symbol", "img", "image", "Meter", "scrollbar",
+		"slider", "separator", "progressbar", "StaticText", "InlineTextBox", "LineBreak":
+		return true
+	}
+	if n.name() == "" {
+		return false
+	}
+	for _, c := range n.children {
+		if !c.node.Ignored && !c.isText() {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *axTreeNode) isText() bool {
+	switch n.role() {
+	case "StaticText", "InlineTextBox", "LineBreak":
+		return true
+	default:
+		return false
+	}
+}
+
+// serialize returns the node and its descendants as AXNodes. If interesting
+// isn't nil, the nodes that aren't in it are pruned and their interesting
+// descendants take their place, and the children of the leaves are omitted.
+func (n *axTreeNode) serialize(interesting map[*axTreeNode]bool) []*AXNode {
+	var children []*AXNode
+	if interesting == nil || !n.isLeaf() {
+		for _, c := range n.children {
+			children = append(children, c.serialize(interesting)...)
+		}
+	}
+	if interesting != nil && !interesting[n] {
+		return children
+	}
+	node := n.toAXNode()
+	node.Children = children
+	return []*AXNode{node}
+}
+
+func (n *axTreeNode) toAXNode() *AXNode {
+	node := &AXNode{
+		Role:        n.role(),
+		Name:        n.name(),
+		Description: axValueString(n.node.Description),
+	}
+	switch node.Role {
+	case "RootWebArea":
+		node.Role = "WebArea"
+	case "StaticText":
+		node.Role = "text"
+	}
+	if n.node.Value != nil {
+		node.Value = axValue(n.node.Value)
+	}
+
+	for _, p := range n.node.Properties {
+		v := axValue(p.Value)
+		switch p.Name { //nolint:exhaustive
+		case accessibility.PropertyNameKeyshortcuts:
+			node.KeyShortcuts, _ = v.(string)
+		case accessibility.PropertyNameRoledescription:
+			node.RoleDescription, _ = v.(string)
+		case accessibility.PropertyNameValuetext:
+			node.ValueText, _ = v.(string)
+		case accessibility.PropertyNameDisabled:
+			node.Disabled, _ = v.(bool)
+		case accessibility.PropertyNameExpanded:
+			if b, ok := v.(bool); ok {
+				node.Expanded = &b
+			}
+		case accessibility.PropertyNameFocused:
+			node.Focused, _ = v.(bool)
+		case accessibility.PropertyNameModal:
+			node.Modal, _ = v.(bool)
+		case accessibility.PropertyNameMultiline:
+			node.Multiline, _ = v.(bool)
+		case accessibility.PropertyNameMultiselectable:
+			node.Multiselectable, _ = v.(bool)
+		case accessibility.PropertyNameReadonly:
+			node.Readonly, _ = v.(bool)
+		case accessibility.PropertyNameRequired:
+			node.Required, _ = v.(bool)
+		case accessibility.PropertyNameSelected:
+			node.Selected, _ = v.(bool)
+		case accessibility.PropertyNameChecked:
+			node.Checked = axTristate(v)
+		case accessibility.PropertyNamePressed:
+			node.Pressed = axTristate(v)
+		case accessibility.PropertyNameLevel:
+			if f, ok := v.(float64); ok {
+				node.Level = int64(f)
+			}
+		case accessibility.PropertyNameValuemin:
+			if f, ok := v.(float64); ok {
+				node.ValueMin = &f
+			}
+		case accessibility.PropertyNameValuemax:
+			if f, ok := v.(float64); ok {
+				node.ValueMax = &f
+			}
+		case accessibility.PropertyNameAutocomplete:
+			node.AutoComplete = axToken(v)
+		case accessibility.PropertyNameHasPopup:
+			node.HasPopup = axToken(v)
+		case accessibility.PropertyNameInvalid:
+			node.Invalid = axToken(v)
+		case accessibility.PropertyNameOrientation:
+			node.Orientation = axToken(v)
+		}
+	}
+
+	return node
+}
+
+func (n *axTreeNode) role() string {
+	return axValueString(n.node.Role)
+}
+
+func (n *axTreeNode) name() string {
+	return axValueString(n.node.Name)
+}
+
+func (n *axTreeNode) boolProperty(name accessibility.PropertyName) bool {
+	for _, p := range n.node.Properties {
+		if p.Name == name {
+			b, _ := axValue(p.Value).(bool)
+			return b
+		}
+	}
+	return false
+}
+
+// axValue returns the decoded value of an accessibility value.
+func axValue(v *accessibility.Value) any {
+	if v == nil || len(v.Value) == 0 {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(v.Value, &value); err != nil {
+		return nil
+	}
+	return value
+}
+
+func axValueString(v *accessibility.Value) string {
+	s, _ := axValue(v).(string)
+	return s
+}
+
+// axTristate returns the value of a tristate property, like checked, which
+// is either a boolean or "mixed".
+func axTristate(v any) any {
+	switch v {
+	case "true", true:
+		return true
+	case "mixed":
+		return "mixed"
+	default:
+		return false
+	}
+}
+
+// axToken returns the value of a token property, omitting the "false" token
+// that means that the property doesn't apply.
+func axToken(v any) string {
+	s, _ := v.(string)
+	if s == "false" {
+		return ""
+	}
+	return s
+}
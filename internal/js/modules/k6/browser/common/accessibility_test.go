@@ -0,0 +1,75 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/go-json-experiment/json/jsontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAXTree(t *testing.T) {
+	t.Parallel()
+
+	value := func(v string) *accessibility.Value {
+		return &accessibility.Value{Value: jsontext.Value(v)}
+	}
+	prop := func(name accessibility.PropertyName, v string) *accessibility.Property {
+		return &accessibility.Property{Name: name, Value: value(v)}
+	}
+	// <h1>Title</h1><div><p>Some text</p><input type="checkbox" checked aria-label="Agree"></div>
+	nodes := []*accessibility.Node{
+		{NodeID: "1", Role: value(`"RootWebArea"`), Name: value(`"Page"`), ChildIDs: []accessibility.NodeID{"2", "4"}},
+		{
+			NodeID: "2", Role: value(`"heading"`), Name: value(`"Title"`), ChildIDs: []accessibility.NodeID{"3"},
+			Properties: []*accessibility.Property{prop(accessibility.PropertyNameLevel, `1`)},
+		},
+		{NodeID: "3", Role: value(`"StaticText"`), Name: value(`"Title"`)},
+		{NodeID: "4", Role: value(`"generic"`), Name: value(`""`), ChildIDs: []accessibility.NodeID{"5", "7"}},
+		{NodeID: "5", Role: value(`"paragraph"`), Name: value(`""`), ChildIDs: []accessibility.NodeID{"6"}},
+		{NodeID: "6", Role: value(`"StaticText"`), Name: value(`"Some text"`)},
+		{
+			NodeID: "7", Role: value(`"checkbox"`), Name: value(`"Agree"`),
+			Properties: []*accessibility.Property{
+				prop(accessibility.PropertyNameFocusable, `true`),
+				prop(accessibility.PropertyNameChecked, `"true"`),
+				prop(accessibility.PropertyNameInvalid, `"false"`),
+			},
+		},
+	}
+
+	t.Run("interesting_only", func(t *testing.T) {
+		t.Parallel()
+
+		want := &AXNode{
+			Role: "WebArea",
+			Name: "Page",
+			Children: []*AXNode{
+				{Role: "heading", Name: "Title", Level: 1},
+				{Role: "text", Name: "Some text"},
+				{Role: "checkbox", Name: "Agree", Checked: true},
+			},
+		}
+		assert.Equal(t, want, buildAXTree(nodes, true))
+	})
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		tree := buildAXTree(nodes, false)
+		require.Len(t, tree.Children, 2)
+		assert.Equal(t, "heading", tree.Children[0].Role)
+		require.Len(t, tree.Children[0].Children, 1)
+		assert.Equal(t, "text", tree.Children[0].Children[0].Role)
+		assert.Equal(t, "generic", tree.Children[1].Role)
+		require.Len(t, tree.Children[1].Children, 2)
+		assert.Equal(t, "paragraph", tree.Children[1].Children[0].Role)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, buildAXTree(nil, true))
+	})
+}
@@ -73,6 +73,10 @@ type Browser struct {
 	// runOnClose is a list of functions to run when the browser is closed.
 	runOnClose []func() error
 
+	// tracing is the active trace, if tracing has been started.
+	tracingMu sync.Mutex
+	tracing   *browserTracing
+
 	logger *log.Logger
 }
 
@@ -545,6 +549,7 @@ func (b *Browser) Close() {
 	}()
 
 	b.logger.Debugf("Browser:Close", "")
+	b.stopTracingOnClose(b.browserCtx)
 	atomic.CompareAndSwapInt64(&b.state, b.state, BrowserStateClosed)
 
 	// Signal to the connection and the process that we're gracefully closing.
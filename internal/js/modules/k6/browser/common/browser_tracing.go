@@ -0,0 +1,227 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto"
+	"github.com/chromedp/cdproto/cdp"
+	cdpio "github.com/chromedp/cdproto/io"
+	"github.com/chromedp/cdproto/tracing"
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/internal/js/modules/k6/browser/k6ext"
+	k6common "go.k6.io/k6/js/common"
+)
+
+const (
+	// DefaultTracingMaxSize is the default maximum size of a trace in bytes.
+	DefaultTracingMaxSize = 50 * 1024 * 1024
+
+	tracingCompleteTimeout = 30 * time.Second
+)
+
+// defaultTracingCategories are the trace categories that are recorded by
+// default, the same ones that the Performance panel of the DevTools uses.
+//
+//nolint:gochecknoglobals
+var defaultTracingCategories = []string{
+	"devtools.timeline",
+	"v8.execute",
+	"disabled-by-default-devtools.timeline",
+	"disabled-by-default-devtools.timeline.frame",
+	"toplevel",
+	"blink.console",
+	"blink.user_timing",
+	"latencyInfo",
+	"disabled-by-default-devtools.timeline.stack",
+	"disabled-by-default-v8.cpu_profiler",
+}
+
+// TracingOptions are the options of Browser.StartTracing.
+type TracingOptions struct {
+	// Path is where the trace is saved when tracing is stopped, if it's set.
+	Path        string
+	Screenshots bool
+	Categories  []string
+	// MaxSize is the maximum size of the trace in bytes. The browser stops
+	// recording new trace events when its trace buffer reaches it.
+	MaxSize int64
+}
+
+// NewTracingOptions returns the default tracing options.
+func NewTracingOptions() *TracingOptions {
+	return &TracingOptions{
+		MaxSize: DefaultTracingMaxSize,
+	}
+}
+
+// Parse parses the tracing options.
+func (o *TracingOptions) Parse(ctx context.Context, opts sobek.Value) error {
+	if k6common.IsNullish(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "path":
+			o.Path = obj.Get(k).String()
+		case "screenshots":
+			o.Screenshots = obj.Get(k).ToBoolean()
+		case "categories":
+			var categories []string
+			if err := rt.ExportTo(obj.Get(k), &categories); err != nil {
+				return fmt.Errorf("categories should be an array of strings: %w", err)
+			}
+			o.Categories = categories
+		case "maxSize":
+			o.MaxSize = obj.Get(k).ToInteger()
+			if o.MaxSize <= 0 {
+				return fmt.Errorf("maxSize should be positive but was %d", o.MaxSize)
+			}
+		}
+	}
+	return nil
+}
+
+// browserTracing is an active trace of the browser or of a page.
+type browserTracing struct {
+	executor  executorEmitter
+	opts      *TracingOptions
+	persister ScreenshotPersister
+}
+
+// StartTracing starts recording a Chrome trace, that can be opened in the
+// Performance panel of the DevTools, of the whole browser or, if the page
+// isn't nil, only of the page. Only one trace can be recorded at a time.
+func (b *Browser) StartTracing(p *Page, opts *TracingOptions, persister ScreenshotPersister) error {
+	b.tracingMu.Lock()
+	defer b.tracingMu.Unlock()
+
+	if b.tracing != nil {
+		return errors.New("tracing has already been started, it has to be stopped before it can be started again")
+	}
+
+	var executor executorEmitter = b.conn
+	if p != nil {
+		executor = p.session
+	}
+
+	categories := opts.Categories
+	if len(categories) == 0 {
+		categories = defaultTracingCategories
+	}
+	if opts.Screenshots {
+		categories = append(categories[:len(categories):len(categories)], "disabled-by-default-devtools.screenshot")
+	}
+
+	action := tracing.Start().
+		WithTransferMode(tracing.TransferModeReturnAsStream).
+		WithTraceConfig(&tracing.TraceConfig{
+			RecordMode:          tracing.RecordModeRecordUntilFull,
+			TraceBufferSizeInKb: float64(opts.MaxSize) / 1024,
+			IncludedCategories:  categories,
+		})
+	if err := action.Do(cdp.WithExecutor(b.vuCtx, executor)); err != nil {
+		return fmt.Errorf("starting tracing: %w", err)
+	}
+
+	b.tracing = &browserTracing{executor: executor, opts: opts, persister: persister}
+	return nil
+}
+
+// StopTracing stops recording the trace and returns it. If a path was set
+// when the tracing was started, the trace is also saved there.
+func (b *Browser) StopTracing() ([]byte, error) {
+	b.tracingMu.Lock()
+	defer b.tracingMu.Unlock()
+
+	if b.tracing == nil {
+		return nil, errors.New("tracing hasn't been started")
+	}
+	t := b.tracing
+	b.tracing = nil
+
+	return t.stop(b.vuCtx)
+}
+
+// stopTracingOnClose saves the trace that wasn't stopped before the browser
+// was closed, e.g. at the end of the iteration, if it has a path.
+func (b *Browser) stopTracingOnClose(ctx context.Context) {
+	b.tracingMu.Lock()
+	defer b.tracingMu.Unlock()
+
+	if b.tracing == nil {
+		return
+	}
+	t := b.tracing
+	b.tracing = nil
+	if t.opts.Path == "" {
+		return
+	}
+	if _, err := t.stop(ctx); err != nil {
+		b.logger.Errorf("Browser:Close", "saving the trace: %v", err)
+	}
+}
+
+func (t *browserTracing) stop(ctx context.Context) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, tracingCompleteTimeout)
+	defer cancel()
+
+	completeCh := make(chan Event, 1)
+	t.executor.on(ctx, []string{cdproto.EventTracingTracingComplete}, completeCh)
+
+	if err := tracing.End().Do(cdp.WithExecutor(ctx, t.executor)); err != nil {
+		return nil, fmt.Errorf("stopping tracing: %w", err)
+	}
+
+	var complete *tracing.EventTracingComplete
+	select {
+	case ev := <-completeCh:
+		var ok bool
+		if complete, ok = ev.data.(*tracing.EventTracingComplete); !ok {
+			return nil, fmt.Errorf("unexpected tracing event %T", ev.data)
+		}
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for the trace: %w", ctx.Err())
+	}
+
+	data, err := t.read(ctx, complete.Stream)
+	if err != nil {
+		return nil, err
+	}
+	if t.opts.Path != "" {
+		if err := t.persister.Persist(ctx, t.opts.Path, bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("saving the trace: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// read reads the trace from the stream, up to the maximum size.
+func (t *browserTracing) read(ctx context.Context, stream cdpio.StreamHandle) ([]byte, error) {
+	exec := cdp.WithExecutor(ctx, t.executor)
+	defer func() {
+		_ = cdpio.Close(stream).Do(exec)
+	}()
+
+	var buf strings.Builder
+	for {
+		data, eof, err := cdpio.Read(stream).Do(exec)
+		if err != nil {
+			return nil, fmt.Errorf("reading the trace: %w", err)
+		}
+		buf.WriteString(data)
+		if int64(buf.Len()) > t.opts.MaxSize {
+			return nil, fmt.Errorf("the trace is bigger than the maximum size of %d bytes", t.opts.MaxSize)
+		}
+		if eof {
+			return []byte(buf.String()), nil
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/chromedp/cdproto"
+	cdpio "github.com/chromedp/cdproto/io"
+	"github.com/chromedp/cdproto/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTracingExecutor returns the chunks as the trace when tracing is ended.
+type fakeTracingExecutor struct {
+	BaseEventEmitter
+	chunks []string
+	closed bool
+}
+
+func (e *fakeTracingExecutor) Execute(_ context.Context, method string, _, res any) error {
+	switch method {
+	case tracing.CommandEnd:
+		go e.emit(cdproto.EventTracingTracingComplete, &tracing.EventTracingComplete{Stream: "trace"})
+	case cdpio.CommandRead:
+		r, _ := res.(*cdpio.ReadReturns)
+		r.Data, e.chunks = e.chunks[0], e.chunks[1:]
+		r.EOF = len(e.chunks) == 0
+	case cdpio.CommandClose:
+		e.closed = true
+	}
+	return nil
+}
+
+type memPersister map[string]string
+
+func (p memPersister) Persist(_ context.Context, path string, data io.Reader) error {
+	b, err := io.ReadAll(data)
+	p[path] = string(b)
+	return err
+}
+
+func TestBrowserTracingStop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		exec := &fakeTracingExecutor{BaseEventEmitter: NewBaseEventEmitter(ctx), chunks: []string{`{"traceEvents":`, `[]}`}}
+		persister := memPersister{}
+		bt := &browserTracing{
+			executor:  exec,
+			opts:      &TracingOptions{Path: "trace.json", MaxSize: DefaultTracingMaxSize},
+			persister: persister,
+		}
+
+		data, err := bt.stop(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, `{"traceEvents":[]}`, string(data))
+		assert.Equal(t, `{"traceEvents":[]}`, persister["trace.json"])
+		assert.True(t, exec.closed)
+	})
+
+	t.Run("max_size", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		exec := &fakeTracingExecutor{BaseEventEmitter: NewBaseEventEmitter(ctx), chunks: []string{`{"traceEvents":`, `[]}`}}
+		bt := &browserTracing{executor: exec, opts: &TracingOptions{MaxSize: 10}}
+
+		_, err := bt.stop(ctx)
+		require.ErrorContains(t, err, "bigger than the maximum size of 10 bytes")
+		assert.True(t, exec.closed)
+	})
+}
@@ -220,9 +220,10 @@ type RouteHandlerCallback func(*Route) error
 
 // Page stores Page/tab related context.
 type Page struct {
-	Keyboard    *Keyboard
-	Mouse       *Mouse
-	Touchscreen *Touchscreen
+	Accessibility *Accessibility
+	Keyboard      *Keyboard
+	Mouse         *Mouse
+	Touchscreen   *Touchscreen
 
 	ctx context.Context
 
@@ -292,6 +293,7 @@ func NewPage(
 		extraHTTPHeaders: bctx.opts.ExtraHTTPHeaders,
 		timeoutSettings:  NewTimeoutSettings(bctx.timeoutSettings),
 		Keyboard:         NewKeyboard(ctx, s),
+		Accessibility:    NewAccessibility(ctx, s),
 		jsEnabled:        true,
 		eventCh:          make(chan Event),
 		eventHandlers:    make(map[PageEventName][]pageEventHandlerRecord),
@@ -1101,6 +1103,11 @@ func (p *Page) GetByText(text string, opts *GetByBaseOptions) *Locator {
 	return p.MainFrame().GetByText(text, opts)
 }
 
+// GetAccessibility returns the accessibility of the page.
+func (p *Page) GetAccessibility() *Accessibility {
+	return p.Accessibility
+}
+
 // GetKeyboard returns the keyboard for the page.
 func (p *Page) GetKeyboard() *Keyboard {
 	return p.Keyboard
@@ -0,0 +1,269 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // baselines can also be JPEG images
+	"image/png"
+	"io/fs"
+	"math"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/internal/js/modules/k6/browser/k6ext"
+	k6common "go.k6.io/k6/js/common"
+)
+
+const (
+	// DefaultScreenshotComparisonThreshold is the default maximum color
+	// distance, between 0 and 1, for two pixels to be considered the same.
+	DefaultScreenshotComparisonThreshold = 0.1
+
+	// maxYIQDelta is the maximum squared YIQ distance between two colors.
+	maxYIQDelta = 35215
+)
+
+// ScreenshotComparisonOptions are the options of Page.CompareScreenshot.
+type ScreenshotComparisonOptions struct {
+	*PageScreenshotOptions
+
+	// Threshold is the maximum color distance, between 0 and 1, for two
+	// pixels to be considered the same.
+	Threshold float64
+	// MaxDiffPixels and MaxDiffPixelRatio are how many pixels can differ for
+	// the screenshot to still match the baseline. If neither is set, no pixel
+	// can differ.
+	MaxDiffPixels     int64
+	MaxDiffPixelRatio float64
+	// DiffPath is where an image that highlights the different pixels is
+	// saved if the screenshot differs from the baseline.
+	DiffPath string
+}
+
+// NewScreenshotComparisonOptions returns the default screenshot comparison
+// options.
+func NewScreenshotComparisonOptions() *ScreenshotComparisonOptions {
+	return &ScreenshotComparisonOptions{
+		PageScreenshotOptions: NewPageScreenshotOptions(),
+		Threshold:             DefaultScreenshotComparisonThreshold,
+	}
+}
+
+// Parse parses the screenshot comparison options. The screenshot options,
+// except for the format, are the same as the ones of Page.Screenshot.
+func (o *ScreenshotComparisonOptions) Parse(ctx context.Context, opts sobek.Value) error {
+	if k6common.IsNullish(opts) {
+		return nil
+	}
+	if err := o.PageScreenshotOptions.Parse(ctx, opts); err != nil {
+		return err
+	}
+	// the baseline is compared to a lossless screenshot
+	o.Format = ImageFormatPNG
+
+	obj := opts.ToObject(k6ext.Runtime(ctx))
+	for _, k := range obj.Keys() {
+		switch k {
+		case "threshold":
+			o.Threshold = obj.Get(k).ToFloat()
+			if o.Threshold < 0 || o.Threshold > 1 {
+				return fmt.Errorf("threshold should be between 0 and 1 but was %v", o.Threshold)
+			}
+		case "maxDiffPixels":
+			o.MaxDiffPixels = obj.Get(k).ToInteger()
+			if o.MaxDiffPixels < 0 {
+				return fmt.Errorf("maxDiffPixels should not be negative but was %d", o.MaxDiffPixels)
+			}
+		case "maxDiffPixelRatio":
+			o.MaxDiffPixelRatio = obj.Get(k).ToFloat()
+			if o.MaxDiffPixelRatio < 0 || o.MaxDiffPixelRatio > 1 {
+				return fmt.Errorf("maxDiffPixelRatio should be between 0 and 1 but was %v", o.MaxDiffPixelRatio)
+			}
+		case "diffPath":
+			o.DiffPath = obj.Get(k).String()
+		}
+	}
+	return nil
+}
+
+// maxDiffPixels returns how many of the pixels can differ.
+func (o *ScreenshotComparisonOptions) maxDiffPixels(total int) int64 {
+	allowed := int64(math.MaxInt64)
+	if o.MaxDiffPixels > 0 {
+		allowed = o.MaxDiffPixels
+	}
+	if o.MaxDiffPixelRatio > 0 {
+		allowed = min(allowed, int64(o.MaxDiffPixelRatio*float64(total)))
+	}
+	if allowed == math.MaxInt64 {
+		return 0
+	}
+	return allowed
+}
+
+// ScreenshotComparison is the result of Page.CompareScreenshot.
+type ScreenshotComparison struct {
+	// Match is whether the screenshot matches the baseline.
+	Match bool `js:"match"`
+	// DiffPixels is how many pixels differ. If the sizes of the images differ,
+	// all the pixels do.
+	DiffPixels int64 `js:"diffPixels"`
+	// DiffRatio is the ratio of the pixels that differ.
+	DiffRatio float64 `js:"diffRatio"`
+	// BaselineCreated is whether the baseline didn't exist and the screenshot
+	// was saved as the baseline.
+	BaselineCreated bool `js:"baselineCreated"`
+}
+
+// CompareScreenshot takes a screenshot of the page and compares it with the
+// baseline image. If the baseline doesn't exist, the screenshot is saved as
+// the baseline. The baseline is read from where the persister saves it, so it
+// must be a ScreenshotReader too.
+func (p *Page) CompareScreenshot(
+	baselinePath string, opts *ScreenshotComparisonOptions, sp ScreenshotPersister,
+) (*ScreenshotComparison, error) {
+	spanCtx, span := TraceAPICall(p.ctx, p.targetID.String(), "page.compareScreenshot")
+	defer span.End()
+
+	reader, ok := sp.(ScreenshotReader)
+	if !ok {
+		err := fmt.Errorf("the screenshots can't be compared, since the baseline %q can't be read from "+
+			"where the screenshots are saved", baselinePath)
+		spanRecordError(span, err)
+		return nil, err
+	}
+
+	actual, err := p.Screenshot(opts.PageScreenshotOptions, sp)
+	if err != nil {
+		spanRecordError(span, err)
+		return nil, err
+	}
+
+	baseline, err := reader.Read(spanCtx, baselinePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := sp.Persist(spanCtx, baselinePath, bytes.NewReader(actual)); err != nil {
+			err = fmt.Errorf("saving the baseline %q: %w", baselinePath, err)
+			spanRecordError(span, err)
+			return nil, err
+		}
+		return &ScreenshotComparison{Match: true, BaselineCreated: true}, nil
+	}
+	if err != nil {
+		err = fmt.Errorf("reading the baseline %q: %w", baselinePath, err)
+		spanRecordError(span, err)
+		return nil, err
+	}
+
+	res, diff, err := compareScreenshots(baseline, actual, opts)
+	if err != nil {
+		spanRecordError(span, err)
+		return nil, err
+	}
+	if !res.Match && opts.DiffPath != "" && diff != nil {
+		buf := &bytes.Buffer{}
+		if err := png.Encode(buf, diff); err != nil {
+			return nil, fmt.Errorf("encoding the diff image: %w", err)
+		}
+		if err := sp.Persist(spanCtx, opts.DiffPath, buf); err != nil {
+			err = fmt.Errorf("saving the diff image %q: %w", opts.DiffPath, err)
+			spanRecordError(span, err)
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// compareScreenshots compares the encoded images. The returned diff image is
+// nil if the sizes of the images differ.
+func compareScreenshots(
+	baseline, actual []byte, opts *ScreenshotComparisonOptions,
+) (*ScreenshotComparison, *image.RGBA, error) {
+	baselineImg, _, err := image.Decode(bytes.NewReader(baseline))
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding the baseline: %w", err)
+	}
+	actualImg, _, err := image.Decode(bytes.NewReader(actual))
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding the screenshot: %w", err)
+	}
+
+	bb, ab := baselineImg.Bounds(), actualImg.Bounds()
+	if bb.Dx() != ab.Dx() || bb.Dy() != ab.Dy() {
+		total := max(bb.Dx()*bb.Dy(), ab.Dx()*ab.Dy())
+		return &ScreenshotComparison{DiffPixels: int64(total), DiffRatio: 1}, nil, nil
+	}
+
+	diffPixels, diff := diffImages(baselineImg, actualImg, opts.Threshold)
+	total := bb.Dx() * bb.Dy()
+	res := &ScreenshotComparison{DiffPixels: diffPixels}
+	if total > 0 {
+		res.DiffRatio = float64(diffPixels) / float64(total)
+	}
+	res.Match = diffPixels <= opts.maxDiffPixels(total)
+
+	return res, diff, nil
+}
+
+//nolint:gochecknoglobals
+var diffColor = color.RGBA{R: 255, A: 255}
+
+// diffImages returns how many pixels of the images of the same size differ by
+// more than the threshold, and an image of the baseline, faded, with those
+// pixels in red.
+func diffImages(baseline, actual image.Image, threshold float64) (int64, *image.RGBA) {
+	bb, ab := baseline.Bounds(), actual.Bounds()
+	diff := image.NewRGBA(image.Rect(0, 0, bb.Dx(), bb.Dy()))
+	maxDelta := maxYIQDelta * threshold * threshold
+
+	var diffPixels int64
+	for y := 0; y < bb.Dy(); y++ {
+		for x := 0; x < bb.Dx(); x++ {
+			c1 := baseline.At(bb.Min.X+x, bb.Min.Y+y)
+			c2 := actual.At(ab.Min.X+x, ab.Min.Y+y)
+			if colorDelta(c1, c2) > maxDelta {
+				diffPixels++
+				diff.SetRGBA(x, y, diffColor)
+				continue
+			}
+			// the pixels that are the same are shown as a faded grayscale
+			r, g, b := blendWhite(c1)
+			gray := uint8(255 + (rgb2y(r, g, b)-255)*0.1)
+			diff.SetRGBA(x, y, color.RGBA{R: gray, G: gray, B: gray, A: 255})
+		}
+	}
+	return diffPixels, diff
+}
+
+// colorDelta returns the squared distance between the colors in the YIQ
+// color space, which is closer to how different they are perceived than the
+// distance in the RGB color space.
+func colorDelta(c1, c2 color.Color) float64 {
+	r1, g1, b1 := blendWhite(c1)
+	r2, g2, b2 := blendWhite(c2)
+	if r1 == r2 && g1 == g2 && b1 == b2 {
+		return 0
+	}
+	y := rgb2y(r1, g1, b1) - rgb2y(r2, g2, b2)
+	i := rgb2i(r1, g1, b1) - rgb2i(r2, g2, b2)
+	q := rgb2q(r1, g1, b1) - rgb2q(r2, g2, b2)
+	return 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+}
+
+// blendWhite returns the 8-bit components of the color blended with white,
+// so that transparent pixels are compared as they are seen.
+func blendWhite(c color.Color) (float64, float64, float64) {
+	r, g, b, a := c.RGBA() // alpha-premultiplied, from 0 to 0xffff
+	blend := func(v uint32) float64 {
+		return (float64(v) + float64(0xffff-a)) / 0xffff * 255
+	}
+	return blend(r), blend(g), blend(b)
+}
+
+func rgb2y(r, g, b float64) float64 { return r*0.29889531 + g*0.58662247 + b*0.11448223 }
+func rgb2i(r, g, b float64) float64 { return r*0.59597799 - g*0.27417610 - b*0.32180189 }
+func rgb2q(r, g, b float64) float64 { return r*0.21147017 - g*0.52261711 + b*0.31114694 }
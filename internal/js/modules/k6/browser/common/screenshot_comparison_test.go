@@ -0,0 +1,89 @@
+package common
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareScreenshots(t *testing.T) {
+	t.Parallel()
+
+	encode := func(t *testing.T, w, h int, pixels map[image.Point]color.Color) []byte {
+		t.Helper()
+		img := image.NewRGBA(image.Rect(0, 0, w, h))
+		for y := range h {
+			for x := range w {
+				img.Set(x, y, color.White)
+			}
+		}
+		for p, c := range pixels {
+			img.Set(p.X, p.Y, c)
+		}
+		buf := &bytes.Buffer{}
+		require.NoError(t, png.Encode(buf, img))
+		return buf.Bytes()
+	}
+	baseline := encode(t, 10, 10, nil)
+	// a slightly different shade isn't a difference with the default threshold
+	actual := encode(t, 10, 10, map[image.Point]color.Color{
+		{X: 1, Y: 1}: color.Black,
+		{X: 2, Y: 2}: color.Black,
+		{X: 3, Y: 3}: color.RGBA{R: 250, G: 250, B: 250, A: 255},
+	})
+
+	tests := []struct {
+		name       string
+		opts       func(o *ScreenshotComparisonOptions)
+		actual     []byte
+		wantMatch  bool
+		wantPixels int64
+	}{
+		{name: "same", actual: baseline, wantMatch: true},
+		{name: "different", actual: actual, wantPixels: 2},
+		{
+			name:       "max_diff_pixels",
+			opts:       func(o *ScreenshotComparisonOptions) { o.MaxDiffPixels = 2 },
+			actual:     actual,
+			wantMatch:  true,
+			wantPixels: 2,
+		},
+		{
+			name:       "max_diff_pixel_ratio",
+			opts:       func(o *ScreenshotComparisonOptions) { o.MaxDiffPixelRatio = 0.01 },
+			actual:     actual,
+			wantPixels: 2,
+		},
+		{
+			name:       "threshold",
+			opts:       func(o *ScreenshotComparisonOptions) { o.Threshold = 0 },
+			actual:     actual,
+			wantPixels: 3,
+		},
+		{name: "size", actual: encode(t, 10, 20, nil), wantPixels: 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := NewScreenshotComparisonOptions()
+			if tt.opts != nil {
+				tt.opts(opts)
+			}
+			res, diff, err := compareScreenshots(baseline, tt.actual, opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMatch, res.Match)
+			assert.Equal(t, tt.wantPixels, res.DiffPixels)
+			if tt.name == "different" {
+				require.NotNil(t, diff)
+				assert.Equal(t, diffColor, diff.RGBAAt(1, 1))
+				assert.NotEqual(t, diffColor, diff.RGBAAt(0, 0))
+			}
+		})
+	}
+}
@@ -22,6 +22,13 @@ type ScreenshotPersister interface {
 	Persist(ctx context.Context, path string, data io.Reader) (err error)
 }
 
+// ScreenshotReader is implemented by the ScreenshotPersisters that can read back
+// the files from where they persist them, like the baselines of the screenshot
+// comparisons. Reading a file that doesn't exist returns an fs.ErrNotExist error.
+type ScreenshotReader interface {
+	Read(ctx context.Context, path string) ([]byte, error)
+}
+
 // ImageFormat represents an image file format.
 type ImageFormat string
 
@@ -52,6 +52,15 @@ func (l *LocalFilePersister) Persist(_ context.Context, path string, data io.Rea
 	return nil
 }
 
+// Read returns the contents of the file on the specified path of the local disk.
+func (l *LocalFilePersister) Read(_ context.Context, path string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Clean(path)) //nolint:forbidigo
+	if err != nil {
+		return nil, fmt.Errorf("reading the local file %q: %w", path, err)
+	}
+	return data, nil
+}
+
 // RemoteFilePersister is to be used when files created by the browser module need
 // to be uploaded to a remote location. This uses a presignedURLRequestURL to
 // retrieve one presigned URL. The presigned url is used to upload the file
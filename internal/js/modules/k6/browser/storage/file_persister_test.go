@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -80,6 +81,21 @@ func TestLocalFilePersister(t *testing.T) {
 	}
 }
 
+func TestLocalFilePersisterRead(t *testing.T) {
+	t.Parallel()
+
+	p := filepath.Join(t.TempDir(), "path", "test.txt")
+	var l LocalFilePersister
+	require.NoError(t, l.Persist(context.Background(), p, strings.NewReader("some data")))
+
+	bb, err := l.Read(context.Background(), p)
+	require.NoError(t, err)
+	assert.Equal(t, "some data", string(bb))
+
+	_, err = l.Read(context.Background(), p+".missing")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
 func TestRemoteFilePersister(t *testing.T) {
 	t.Parallel()
 	if runtime.GOOS == "windows" {
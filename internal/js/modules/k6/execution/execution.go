@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
 	"time"
 
 	"github.com/grafana/sobek"
 
 	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
 	"go.k6.io/k6/lib"
@@ -60,6 +65,9 @@ func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
 	defProp("scenario", mi.newScenarioInfo)
 	defProp("test", mi.newTestInfo)
 	defProp("vu", mi.newVUInfo)
+	if err := o.Set("segmentForInstance", mi.segmentForInstance); err != nil {
+		common.Throw(rt, err)
+	}
 
 	mi.obj = o
 
@@ -71,6 +79,26 @@ func (mi *ModuleInstance) Exports() modules.Exports {
 	return modules.Exports{Default: mi.obj}
 }
 
+// segmentForInstance returns the executionSegment and executionSegmentSequence
+// options for the instance with the given 1-based index, when the test is
+// evenly split between totalInstances instances. The result can be spread
+// directly in the exported script options.
+func (mi *ModuleInstance) segmentForInstance(index, totalInstances int64) (*sobek.Object, error) {
+	segment, sequence, err := lib.NewInstanceExecutionSegment(index, totalInstances)
+	if err != nil {
+		return nil, err
+	}
+	rt := mi.vu.Runtime()
+	o := rt.NewObject()
+	if err = o.Set("executionSegment", segment.String()); err != nil {
+		return nil, err
+	}
+	if err = o.Set("executionSegmentSequence", sequence.String()); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
 var errRunInInitContext = errors.New("getting scenario information outside of the VU context is not supported")
 
 // newScenarioInfo returns a sobek.Object with property accessors to retrieve
@@ -160,7 +188,10 @@ func (mi *ModuleInstance) newInstanceInfo() (*sobek.Object, error) {
 	return newInfoObj(rt, ti)
 }
 
-var errTestInfoInitContext = common.NewInitContextError("getting test options in the init context is not supported")
+var (
+	errTestInfoInitContext    = common.NewInitContextError("getting test options in the init context is not supported")
+	errRunMetadataInitContext = common.NewInitContextError("getting the run metadata in the init context is not supported")
+)
 
 // newTestInfo returns a sobek.Object with property accessors to retrieve
 // information and control execution of the overall test run.
@@ -172,12 +203,8 @@ func (mi *ModuleInstance) newTestInfo() (*sobek.Object, error) {
 	ti := map[string]func() interface{}{
 		// stop the test run
 		"abort": func() interface{} {
-			return func(msg sobek.Value) {
-				reason := errext.AbortTest
-				if !common.IsNullish(msg) {
-					reason = fmt.Sprintf("%s: %s", reason, msg.String())
-				}
-				rt.Interrupt(&errext.InterruptError{Reason: reason})
+			return func(reason sobek.Value) {
+				rt.Interrupt(newAbortError(rt, reason))
 			}
 		},
 		"fail": func() interface{} {
@@ -195,6 +222,19 @@ func (mi *ModuleInstance) newTestInfo() (*sobek.Object, error) {
 				mi.vu.State().TestStatus.MarkFailed()
 			}
 		},
+		"metadata": func() interface{} {
+			vuState := mi.vu.State()
+			if vuState == nil {
+				common.Throw(rt, errRunMetadataInitContext)
+			}
+			if vuState.RunMetadata == nil {
+				common.Throw(rt, errors.New("the run metadata isn't available"))
+			}
+			return rt.NewDynamicObject(&runMetadataDynamicObject{
+				runtime:     rt,
+				runMetadata: vuState.RunMetadata,
+			})
+		},
 		"options": func() interface{} {
 			vuState := mi.vu.State()
 			if vuState == nil {
@@ -214,6 +254,49 @@ func (mi *ModuleInstance) newTestInfo() (*sobek.Object, error) {
 	return newInfoObj(rt, ti)
 }
 
+// newAbortError returns the error that interrupts the test run for the reason
+// given to test.abort(). It's either a message or a plain object with the exit
+// code, the message and the tags of the abort.
+func newAbortError(rt *sobek.Runtime, reason sobek.Value) *errext.InterruptError {
+	err := &errext.InterruptError{Reason: errext.AbortTest}
+	if common.IsNullish(reason) {
+		return err
+	}
+	obj, isObject := reason.(*sobek.Object)
+	if !isObject || obj.ClassName() != "Object" {
+		err.Message = reason.String()
+		err.Reason = fmt.Sprintf("%s: %s", errext.AbortTest, err.Message)
+		return err
+	}
+
+	if code := obj.Get("code"); !common.IsNullish(code) {
+		c := code.ToFloat()
+		if c != math.Trunc(c) || c < 1 || c > math.MaxUint8 {
+			common.Throw(rt, fmt.Errorf("the abort code must be an integer between 1 and 255, got %s", code))
+		}
+		if exitcodes.IsReserved(exitcodes.ExitCode(c)) {
+			common.Throw(rt, fmt.Errorf("the abort code %s is reserved by k6, the codes between %d and %d can't be used",
+				code, exitcodes.ReservedMin, exitcodes.ReservedMax))
+		}
+		err.Code = exitcodes.ExitCode(c)
+	}
+	if message := obj.Get("message"); !common.IsNullish(message) {
+		err.Message = message.String()
+		err.Reason = fmt.Sprintf("%s: %s", errext.AbortTest, err.Message)
+	}
+	if tags := obj.Get("tags"); !common.IsNullish(tags) {
+		tagsObj, ok := tags.(*sobek.Object)
+		if !ok {
+			common.Throw(rt, fmt.Errorf("the abort tags must be an object, got %s", tags))
+		}
+		err.Tags = make(map[string]string)
+		for _, key := range tagsObj.Keys() {
+			err.Tags[key] = tagsObj.Get(key).String()
+		}
+	}
+	return err
+}
+
 var errVUInfoInitContex = common.NewInitContextError("getting VU information in the init context is not supported")
 
 // newVUInfo returns a sobek.Object with property accessors to retrieve
@@ -463,3 +546,53 @@ func (o *metadataDynamicObject) Keys() []string {
 	}
 	return keys
 }
+
+type runMetadataDynamicObject struct {
+	runtime     *sobek.Runtime
+	runMetadata *lib.RunMetadata
+}
+
+// Get a property value for the key. May return nil if the property does not exist.
+func (o *runMetadataDynamicObject) Get(key string) sobek.Value {
+	if value, ok := o.runMetadata.Get(key); ok {
+		return o.runtime.ToValue(value)
+	}
+	return nil
+}
+
+// Set a property value for the key. It returns true if successful. String, Boolean
+// and Number types are implicitly converted to the Sobek's relative string
+// representation. An exception is raised in case a denied type is provided.
+func (o *runMetadataDynamicObject) Set(key string, val sobek.Value) bool {
+	kind := reflect.Invalid
+	if typ := val.ExportType(); typ != nil {
+		kind = typ.Kind()
+	}
+	switch kind {
+	case reflect.String, reflect.Bool, reflect.Int64, reflect.Float64:
+		o.runMetadata.Set(key, val.String())
+		return true
+	default:
+		panic(o.runtime.NewTypeError(fmt.Sprintf("invalid value for run metadata '%s': "+
+			"only String, Boolean and Number types are accepted as a run metadata value", key)))
+	}
+}
+
+// Has returns true if the property exists.
+func (o *runMetadataDynamicObject) Has(key string) bool {
+	_, ok := o.runMetadata.Get(key)
+	return ok
+}
+
+// Delete deletes the property for the key. It returns true on success (note,
+// that includes missing property).
+func (o *runMetadataDynamicObject) Delete(key string) bool {
+	o.runMetadata.Delete(key)
+	return true
+}
+
+// Keys returns a slice with all existing property keys. The order is not
+// deterministic.
+func (o *runMetadataDynamicObject) Keys() []string {
+	return slices.Collect(maps.Keys(o.runMetadata.Map()))
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
 	"go.k6.io/k6/internal/lib/testutils"
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modulestest"
@@ -215,6 +216,35 @@ func TestAbortTest(t *testing.T) { //nolint:tparallel
 	t.Run("custom reason", func(t *testing.T) { //nolint:paralleltest
 		prove(t, `exec.test.abort("mayday")`, fmt.Sprintf("%s: mayday", errext.AbortTest))
 	})
+	t.Run("structured reason", func(t *testing.T) { //nolint:paralleltest
+		_, err := rt.RunString(`exec.test.abort({code: 42, message: "mayday", tags: {slo: "latency"}})`)
+		var x *sobek.InterruptedError
+		require.ErrorAs(t, err, &x)
+		v, ok := x.Value().(*errext.InterruptError)
+		require.True(t, ok)
+		assert.Equal(t, fmt.Sprintf("%s: mayday", errext.AbortTest), v.Reason)
+		assert.Equal(t, "mayday", v.Message)
+		assert.Equal(t, map[string]string{"slo": "latency"}, v.Tags)
+		assert.Equal(t, exitcodes.ExitCode(42), v.ExitCode())
+	})
+	t.Run("structured reason without code", func(t *testing.T) { //nolint:paralleltest
+		_, err := rt.RunString(`exec.test.abort({message: "mayday"})`)
+		var x *sobek.InterruptedError
+		require.ErrorAs(t, err, &x)
+		v, ok := x.Value().(*errext.InterruptError)
+		require.True(t, ok)
+		assert.Equal(t, exitcodes.ScriptAborted, v.ExitCode())
+	})
+	t.Run("invalid code", func(t *testing.T) { //nolint:paralleltest
+		_, err := rt.RunString(`exec.test.abort({code: 256})`)
+		require.ErrorContains(t, err, "the abort code must be an integer between 1 and 255, got 256")
+	})
+	t.Run("reserved code", func(t *testing.T) { //nolint:paralleltest
+		for _, code := range []exitcodes.ExitCode{exitcodes.ThresholdsHaveFailed, exitcodes.ScriptAborted, exitcodes.ReservedMax} {
+			_, err := rt.RunString(fmt.Sprintf(`exec.test.abort({code: %d})`, code))
+			require.ErrorContains(t, err, fmt.Sprintf("the abort code %d is reserved by k6", code))
+		}
+	})
 }
 
 func TestFailTest(t *testing.T) {
@@ -287,7 +317,7 @@ func TestFailTest(t *testing.T) {
 func TestOptionsTestFull(t *testing.T) {
 	t.Parallel()
 
-	expected := `{"paused":true,"scenarios":{"const-vus":{"executor":"constant-vus","options":{"browser":{"someOption":true}},"startTime":"10s","gracefulStop":"30s","env":{"FOO":"bar"},"exec":"default","tags":{"tagkey":"tagvalue"},"vus":50,"duration":"10m0s"}},"executionSegment":"0:1/4","executionSegmentSequence":"0,1/4,1/2,1","noSetup":true,"setupTimeout":"1m0s","noTeardown":true,"teardownTimeout":"5m0s","rps":100,"dns":{"ttl":"1m","select":"roundRobin","policy":"any"},"maxRedirects":3,"userAgent":"k6-user-agent","batch":15,"batchPerHost":5,"httpDebug":"full","insecureSkipTLSVerify":true,"tlsCipherSuites":["TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"],"tlsVersion":{"min":"tls1.2","max":"tls1.3"},"tlsAuth":[{"domains":["example.com"],"cert":"mycert.pem","key":"mycert-key.pem","password":"mypwd"}],"throw":true,"thresholds":{"http_req_duration":[{"threshold":"rate>0.01","abortOnFail":true,"delayAbortEval":"10s"}]},"blacklistIPs":["192.0.2.0/24"],"blockHostnames":["test.k6.io","*.example.com"],"hosts":{"test.k6.io":"1.2.3.4:8443"},"noConnectionReuse":true,"noVUConnectionReuse":true,"minIterationDuration":"10s","ext":{"ext-one":{"rawkey":"rawvalue"}},"summaryTrendStats":["avg","min","max"],"summaryTimeUnit":"ms","systemTags":["iter","vu"],"tags":null,"metricSamplesBufferSize":8,"noCookiesReset":true,"discardResponseBodies":true,"consoleOutput":"loadtest.log","tags":{"runtag-key":"runtag-value"},"localIPs":"192.168.20.12-192.168.20.15,192.168.10.0/27"}`
+	expected := `{"paused":true,"scenarios":{"const-vus":{"executor":"constant-vus","options":{"browser":{"someOption":true}},"startTime":"10s","gracefulStop":"30s","env":{"FOO":"bar"},"exec":"default","tags":{"tagkey":"tagvalue"},"iterationTimeout":"1m0s","warmupIterations":5,"warmupDuration":"10s","weight":2,"tlsSessionTickets":true,"dialFamily":"ipv4","pacing":null,"requestDefaults":null,"mix":null,"vus":50,"duration":"10m0s"}},"executionSegment":"0:1/4","executionSegmentSequence":"0,1/4,1/2,1","noSetup":true,"setupTimeout":"1m0s","noTeardown":true,"teardownTimeout":"5m0s","rps":100,"dns":{"ttl":"1m","select":"roundRobin","policy":"any"},"maxRedirects":3,"userAgent":"k6-user-agent","batch":15,"batchPerHost":5,"httpDebug":"full","insecureSkipTLSVerify":true,"tlsCipherSuites":["TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"],"tlsVersion":{"min":"tls1.2","max":"tls1.3"},"tlsAuth":[{"domains":["example.com"],"cert":"mycert.pem","key":"mycert-key.pem","password":"mypwd"}],"throw":true,"expectedResponses":[{"method":"DELETE","url":"/cache/.*","statuses":[404,{"min":200,"max":299}]}],"thresholds":{"http_req_duration":[{"threshold":"rate>0.01","abortOnFail":true,"delayAbortEval":"10s"}]},"errorBudget":{"failedIterations":10,"checks":{"status is 200":5},"abortOnExhausted":true},"blacklistIPs":["192.0.2.0/24"],"blockHostnames":["test.k6.io","*.example.com"],"hosts":{"test.k6.io":"1.2.3.4:8443"},"dialFamily":"ipv6","noConnectionReuse":true,"noVUConnectionReuse":true,"maxConcurrentRequests":100,"minIterationDuration":"10s","iterationTimeout":"2m0s","vuMemoryLimit":104857600,"vuMemoryLimitAction":"restart","iterationBreakdown":true,"connectionMetrics":true,"ext":{"ext-one":{"rawkey":"rawvalue"}},"summaryTrendStats":["avg","min","max"],"summaryTimeUnit":"ms","summaryBreakdown":["scenario"],"summaryTimeSeries":["http_req_duration"],"summaryTimeSeriesInterval":"5s","trendExactWindow":"1h0m0s","systemTags":["iter","vu"],"tags":null,"runMetadata":{"git_sha":"abc123"},"metricSamplesBufferSize":8,"metricSamplesBufferLimit":5000,"metricSamplesBufferPolicy":"drop","noCookiesReset":true,"discardResponseBodies":true,"httpRecord":null,"httpReplay":"cassette.json","randomSeed":42,"consoleOutput":"loadtest.log","tags":{"runtag-key":"runtag-value"},"localIPs":"192.168.20.12-192.168.20.15,192.168.10.0/27"}`
 
 	var (
 		rt    = sobek.New()
@@ -313,6 +343,12 @@ func TestOptionsTestFull(t *testing.T) {
 									"someOption": true,
 								},
 							},
+							IterationTimeout:  types.NullDurationFrom(time.Minute),
+							WarmupIterations:  null.IntFrom(5),
+							WarmupDuration:    types.NullDurationFrom(10 * time.Second),
+							Weight:            null.IntFrom(2),
+							TLSSessionTickets: null.BoolFrom(true),
+							DialFamily:        null.StringFrom("ipv4"),
 						},
 						VUs:      null.IntFrom(50),
 						Duration: types.NullDurationFrom(10 * time.Minute),
@@ -332,19 +368,39 @@ func TestOptionsTestFull(t *testing.T) {
 				NoTeardown:            null.BoolFrom(true),
 				NoConnectionReuse:     null.BoolFrom(true),
 				NoVUConnectionReuse:   null.BoolFrom(true),
+				MaxConcurrentRequests: null.IntFrom(100),
 				InsecureSkipTLSVerify: null.BoolFrom(true),
 				Throw:                 null.BoolFrom(true),
 				NoCookiesReset:        null.BoolFrom(true),
 				DiscardResponseBodies: null.BoolFrom(true),
-				RPS:                   null.IntFrom(100),
-				MaxRedirects:          null.IntFrom(3),
-				UserAgent:             null.StringFrom("k6-user-agent"),
-				Batch:                 null.IntFrom(15),
-				BatchPerHost:          null.IntFrom(5),
-				SetupTimeout:          types.NullDurationFrom(1 * time.Minute),
-				TeardownTimeout:       types.NullDurationFrom(5 * time.Minute),
-				MinIterationDuration:  types.NullDurationFrom(10 * time.Second),
-				HTTPDebug:             null.StringFrom("full"),
+				HTTPReplay:            null.StringFrom("cassette.json"),
+				RandomSeed:            null.IntFrom(42),
+				ExpectedResponses: lib.ExpectedResponseRules{{
+					ExpectedResponseFields: lib.ExpectedResponseFields{
+						Method:   "DELETE",
+						URL:      "/cache/.*",
+						Statuses: []lib.ExpectedStatus{{Min: 404, Max: 404}, {Min: 200, Max: 299}},
+					},
+				}},
+				ErrorBudget: &lib.ErrorBudget{
+					FailedIterations: null.IntFrom(10),
+					Checks:           map[string]float64{"status is 200": 5},
+					AbortOnExhausted: null.BoolFrom(true),
+				},
+				RPS:                  null.IntFrom(100),
+				MaxRedirects:         null.IntFrom(3),
+				UserAgent:            null.StringFrom("k6-user-agent"),
+				Batch:                null.IntFrom(15),
+				BatchPerHost:         null.IntFrom(5),
+				SetupTimeout:         types.NullDurationFrom(1 * time.Minute),
+				TeardownTimeout:      types.NullDurationFrom(5 * time.Minute),
+				MinIterationDuration: types.NullDurationFrom(10 * time.Second),
+				IterationTimeout:     types.NullDurationFrom(2 * time.Minute),
+				VUMemoryLimit:        null.IntFrom(100 << 20),
+				VUMemoryLimitAction:  null.StringFrom("restart"),
+				IterationBreakdown:   null.BoolFrom(true),
+				ConnectionMetrics:    null.BoolFrom(true),
+				HTTPDebug:            null.StringFrom("full"),
 				DNS: types.DNSConfig{
 					TTL:    null.StringFrom("1m"),
 					Select: types.NullDNSSelect{DNSSelect: types.DNSroundRobin, Valid: true},
@@ -404,18 +460,26 @@ func TestOptionsTestFull(t *testing.T) {
 					require.NoError(t, err)
 					return hs
 				}(),
+				DialFamily: null.StringFrom("ipv6"),
 				External: map[string]json.RawMessage{
 					"ext-one": json.RawMessage(`{"rawkey":"rawvalue"}`),
 				},
-				SummaryTrendStats: []string{"avg", "min", "max"},
-				SummaryTimeUnit:   null.StringFrom("ms"),
+				SummaryTrendStats:         []string{"avg", "min", "max"},
+				SummaryTimeUnit:           null.StringFrom("ms"),
+				SummaryBreakdown:          []string{"scenario"},
+				SummaryTimeSeries:         []string{"http_req_duration"},
+				SummaryTimeSeriesInterval: types.NullDurationFrom(5 * time.Second),
+				TrendExactWindow:          types.NullDurationFrom(time.Hour),
 				SystemTags: func() *metrics.SystemTagSet {
 					sysm := metrics.SystemTagSet(metrics.TagIter | metrics.TagVU)
 					return &sysm
 				}(),
-				RunTags:                 map[string]string{"runtag-key": "runtag-value"},
-				MetricSamplesBufferSize: null.IntFrom(8),
-				ConsoleOutput:           null.StringFrom("loadtest.log"),
+				RunTags:                   map[string]string{"runtag-key": "runtag-value"},
+				RunMetadata:               map[string]string{"git_sha": "abc123"},
+				MetricSamplesBufferSize:   null.IntFrom(8),
+				MetricSamplesBufferLimit:  null.IntFrom(5000),
+				MetricSamplesBufferPolicy: null.StringFrom("drop"),
+				ConsoleOutput:             null.StringFrom("loadtest.log"),
 				LocalIPs: func() types.NullIPPool {
 					npool := types.NullIPPool{}
 					err := npool.UnmarshalText([]byte("192.168.20.12-192.168.20.15,192.168.10.0/27"))
@@ -480,6 +544,30 @@ func TestOptionsTestSetPropertyDenied(t *testing.T) {
 	assert.Equal(t, true, rt.ToValue(paused).ToBoolean())
 }
 
+func TestTestMetadata(t *testing.T) {
+	t.Parallel()
+
+	runMetadata := lib.NewRunMetadata(map[string]string{"git_sha": "abc123"})
+	tenv := setupTagsExecEnv(t)
+	_, err := tenv.VU.Runtime().RunString(`exec.test.metadata`)
+	require.ErrorContains(t, err, "getting the run metadata in the init context is not supported")
+
+	tenv.MoveToVUContext(&lib.State{RunMetadata: runMetadata})
+	v, err := tenv.VU.Runtime().RunString(`
+		exec.test.metadata.environment = "staging";
+		exec.test.metadata.build = 42;
+		exec.test.metadata.removed = "yes";
+		delete exec.test.metadata.removed;
+		JSON.stringify([exec.test.metadata.git_sha, "removed" in exec.test.metadata, Object.keys(exec.test.metadata).sort()])
+	`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["abc123", false, ["build", "environment", "git_sha"]]`, v.String())
+	assert.Equal(t, map[string]string{"git_sha": "abc123", "environment": "staging", "build": "42"}, runMetadata.Map())
+
+	_, err = tenv.VU.Runtime().RunString(`exec.test.metadata.invalid = {}`)
+	require.ErrorContains(t, err, "TypeError: invalid value for run metadata 'invalid'")
+}
+
 func TestScenarioNoAvailableInInitContext(t *testing.T) {
 	t.Parallel()
 
@@ -525,6 +613,30 @@ func TestOptionsNoAvailableInInitContext(t *testing.T) {
 	require.ErrorContains(t, err, "getting test options in the init context is not supported")
 }
 
+func TestSegmentForInstance(t *testing.T) {
+	t.Parallel()
+
+	rt := sobek.New()
+	m, ok := New().NewModuleInstance(
+		&modulestest.VU{
+			RuntimeField: rt,
+			CtxField:     context.Background(),
+		},
+	).(*ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, rt.Set("exec", m.Exports().Default))
+
+	v, err := rt.RunString(`
+		var s = exec.segmentForInstance(3, 4);
+		s.executionSegment + " " + s.executionSegmentSequence;
+	`)
+	require.NoError(t, err)
+	assert.Equal(t, "1/2:3/4 0,1/4,1/2,3/4,1", v.String())
+
+	_, err = rt.RunString(`exec.segmentForInstance(5, 4)`)
+	require.ErrorContains(t, err, "the instance index must be between 1 and 4")
+}
+
 func TestVUDefaultDetails(t *testing.T) {
 	t.Parallel()
 
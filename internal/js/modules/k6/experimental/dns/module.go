@@ -0,0 +1,128 @@
+// Package dns provides DNS lookups of A, AAAA, CNAME, SRV and TXT records.
+package dns
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/js/promises"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/metrics"
+)
+
+type (
+	// RootModule is the global module instance that will create instances of our
+	// module for each VU.
+	RootModule struct {
+		lookuper lookuper
+	}
+
+	// ModuleInstance represents an instance of the dns module for a single VU.
+	ModuleInstance struct {
+		vu       modules.VU
+		lookuper lookuper
+
+		lookupDuration *metrics.Metric
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// lookupDurationName is the name of the metric of the durations of the lookups.
+const lookupDurationName = "dns_lookup_duration"
+
+// New returns a pointer to a new [RootModule] instance.
+func New() *RootModule {
+	return &RootModule{lookuper: net.DefaultResolver}
+}
+
+// NewModuleInstance implements the modules.Module interface and returns a new
+// instance of our module for the given VU.
+func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	lookupDuration, err := vu.InitEnv().Registry.NewMetric(lookupDurationName, metrics.Trend, metrics.Time)
+	if err != nil {
+		common.Throw(vu.Runtime(), err)
+	}
+	return &ModuleInstance{vu: vu, lookuper: rm.lookuper, lookupDuration: lookupDuration}
+}
+
+// Exports implements the modules.Module interface and returns the exports of
+// our module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"resolve": mi.Resolve,
+		},
+	}
+}
+
+// Resolve looks up the records of the type for the name, and returns a promise
+// that resolves to them. The A, AAAA and CNAME records are strings, the TXT
+// records are the strings of the record, and the SRV records are objects with
+// target, port, priority and weight properties.
+func (mi *ModuleInstance) Resolve(name sobek.Value, recordType sobek.Value) *sobek.Promise {
+	promise, resolveFn, reject := promises.New(mi.vu)
+
+	state := mi.vu.State()
+	if state == nil {
+		reject(common.NewInitContextError("resolve() can't be used in the init context"))
+		return promise
+	}
+	if common.IsNullish(name) || name.String() == "" {
+		reject(errors.New("resolve() requires a name to resolve"))
+		return promise
+	}
+	if common.IsNullish(recordType) {
+		reject(errors.New("resolve() requires a record type"))
+		return promise
+	}
+	typ, err := parseRecordType(recordType.String())
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	host := name.String()
+	ctx := mi.vu.Context()
+	tagsAndMeta := state.Tags.GetCurrentValues()
+	go func() {
+		start := time.Now()
+		records, err := resolve(ctx, mi.lookuper, vuResolver(state), state.Options, host, typ)
+		end := time.Now()
+
+		tags := tagsAndMeta.Tags.With("name", host).With("type", typ)
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: mi.lookupDuration, Tags: tags},
+			Time:       end,
+			Metadata:   tagsAndMeta.Metadata,
+			Value:      metrics.D(end.Sub(start)),
+		})
+
+		if err != nil {
+			reject(err)
+			return
+		}
+		resolveFn(records)
+	}()
+
+	return promise
+}
+
+// vuResolver returns the resolver of the connections of the VU, or nil if it
+// doesn't use the k6 dialer.
+func vuResolver(state *lib.State) netext.Resolver {
+	dialer, ok := state.Dialer.(*netext.Dialer)
+	if !ok {
+		return nil
+	}
+	return dialer.Resolver
+}
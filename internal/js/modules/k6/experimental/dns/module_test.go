@@ -0,0 +1,150 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
+)
+
+type fakeLookuper struct{}
+
+func (fakeLookuper) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	if host != "example.com" {
+		return nil, errors.New("lookup " + host + ": no such host")
+	}
+	return []net.IPAddr{{IP: net.ParseIP("192.0.2.1")}, {IP: net.ParseIP("2001:db8::1")}}, nil
+}
+
+func (fakeLookuper) LookupCNAME(_ context.Context, _ string) (string, error) {
+	return "target.example.com.", nil
+}
+
+func (fakeLookuper) LookupSRV(_ context.Context, _, _, _ string) (string, []*net.SRV, error) {
+	return "", []*net.SRV{{Target: "api.example.com.", Port: 8080, Priority: 10, Weight: 5}}, nil
+}
+
+func (fakeLookuper) LookupTXT(_ context.Context, _ string) ([]string, error) {
+	return []string{"v=spf1 -all"}, nil
+}
+
+func newTestRuntime(
+	t *testing.T, opts lib.Options, dialer lib.DialContexter,
+) (*modulestest.Runtime, chan metrics.SampleContainer) {
+	t.Helper()
+
+	rt := modulestest.NewRuntime(t)
+	m, ok := (&RootModule{lookuper: fakeLookuper{}}).NewModuleInstance(rt.VU).(*ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, rt.VU.Runtime().Set("dns", m.Exports().Named))
+
+	samples := make(chan metrics.SampleContainer, 10)
+	registry := metrics.NewRegistry()
+	rt.MoveToVUContext(&lib.State{
+		Options: opts,
+		Dialer:  dialer,
+		Samples: samples,
+		Tags:    lib.NewVUStateTags(registry.RootTagSet()),
+	})
+	return rt, samples
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	rt, samples := newTestRuntime(t, lib.Options{}, nil)
+	_, err := rt.RunOnEventLoop(`(async () => {
+		const check = (got, want) => {
+			if (JSON.stringify(got) !== JSON.stringify(want)) {
+				throw new Error("got " + JSON.stringify(got) + ", want " + JSON.stringify(want));
+			}
+		};
+		check(await dns.resolve("example.com", "A"), ["192.0.2.1"]);
+		check(await dns.resolve("example.com", "aaaa"), ["2001:db8::1"]);
+		check(await dns.resolve("example.com", "CNAME"), ["target.example.com."]);
+		check(await dns.resolve("_api._tcp.example.com", "SRV"),
+			[{ target: "api.example.com.", port: 8080, priority: 10, weight: 5 }]);
+		check(await dns.resolve("example.com", "TXT"), ["v=spf1 -all"]);
+	})()`)
+	require.NoError(t, err)
+
+	require.Len(t, samples, 5)
+	sample := (<-samples).GetSamples()[0]
+	assert.Equal(t, lookupDurationName, sample.Metric.Name)
+	assert.Equal(t, map[string]string{"name": "example.com", "type": "A"}, sample.Tags.Map())
+}
+
+func TestResolveOptions(t *testing.T) {
+	t.Parallel()
+
+	hosts, err := types.NewNullHosts(map[string]types.Host{"test.k6.io": {IP: net.ParseIP("192.0.2.2")}})
+	require.NoError(t, err)
+	blocked, err := types.NewNullHostnameTrie([]string{"*.blocked.com"})
+	require.NoError(t, err)
+
+	rt, _ := newTestRuntime(t, lib.Options{Hosts: hosts, BlockedHostnames: blocked}, nil)
+	_, err = rt.RunOnEventLoop(`(async () => {
+		const ips = await dns.resolve("test.k6.io", "A");
+		if (ips.join() !== "192.0.2.2") {
+			throw new Error("unexpected IPs " + ips.join());
+		}
+	})()`)
+	require.NoError(t, err)
+
+	_, err = rt.RunOnEventLoop(`dns.resolve("www.blocked.com", "A")`)
+	require.ErrorContains(t, err, "hostname (www.blocked.com) is in a blocked pattern (*.blocked.com)")
+}
+
+func TestResolveVUResolver(t *testing.T) {
+	t.Parallel()
+
+	var lookups int
+	resolver := netext.NewResolver(func(host string) ([]net.IP, error) {
+		lookups++
+		return []net.IP{net.ParseIP("192.0.2.3"), net.ParseIP("192.0.2.4"), net.ParseIP("2001:db8::2")}, nil
+	}, time.Minute, types.DNSfirst, types.DNSpreferIPv4)
+	dialer := netext.NewDialer(net.Dialer{}, resolver)
+
+	rt, _ := newTestRuntime(t, lib.Options{}, dialer)
+	_, err := rt.RunOnEventLoop(`(async () => {
+		const check = (got, want) => {
+			if (JSON.stringify(got) !== JSON.stringify(want)) {
+				throw new Error("got " + JSON.stringify(got) + ", want " + JSON.stringify(want));
+			}
+		};
+		check(await dns.resolve("example.com", "A"), ["192.0.2.3"]);
+		check(await dns.resolve("example.com", "AAAA"), ["2001:db8::2"]);
+	})()`)
+	require.NoError(t, err)
+	assert.Equal(t, 1, lookups, "the lookups must be cached for the dns ttl")
+}
+
+func TestResolveErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`dns.resolve("example.com", "MX")`:    `unsupported record type "MX"`,
+		`dns.resolve("", "A")`:                "resolve() requires a name to resolve",
+		`dns.resolve("example.com")`:          "resolve() requires a record type",
+		`dns.resolve("unknown.example", "A")`: "lookup unknown.example: no such host",
+	}
+	for script, want := range tests {
+		t.Run(script, func(t *testing.T) {
+			t.Parallel()
+
+			rt, _ := newTestRuntime(t, lib.Options{}, nil)
+			_, err := rt.RunOnEventLoop(script)
+			require.ErrorContains(t, err, want)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/lib/types"
+)
+
+// The supported record types.
+const (
+	TypeA     = "A"
+	TypeAAAA  = "AAAA"
+	TypeCNAME = "CNAME"
+	TypeSRV   = "SRV"
+	TypeTXT   = "TXT"
+)
+
+// lookuper performs the actual DNS lookups, it's implemented by net.Resolver.
+type lookuper interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// SRVRecord is a resolved SRV record.
+type SRVRecord struct {
+	Target   string `js:"target"`
+	Port     uint16 `js:"port"`
+	Priority uint16 `js:"priority"`
+	Weight   uint16 `js:"weight"`
+}
+
+// parseRecordType returns the record type in its canonical form, or an error
+// if it isn't supported.
+func parseRecordType(recordType string) (string, error) {
+	switch t := strings.ToUpper(recordType); t {
+	case TypeA, TypeAAAA, TypeCNAME, TypeSRV, TypeTXT:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unsupported record type %q, it must be one of A, AAAA, CNAME, SRV, TXT", recordType)
+	}
+}
+
+// resolve looks up the records of the type for the name. The A and AAAA
+// lookups respect the hosts and the blockHostnames options, and are made with
+// the resolver of the VU, if there's one, so they get the address that the
+// connections of the VU would use, according to the dns option.
+func resolve(
+	ctx context.Context, l lookuper, r netext.Resolver, opts lib.Options, name, recordType string,
+) (any, error) {
+	if opts.BlockedHostnames.Trie != nil {
+		if match, blocked := opts.BlockedHostnames.Trie.Contains(name); blocked {
+			return nil, fmt.Errorf("hostname (%s) is in a blocked pattern (%s)", name, match)
+		}
+	}
+
+	switch recordType {
+	case TypeA, TypeAAAA:
+		return resolveIP(ctx, l, r, opts, name, recordType == TypeA)
+	case TypeCNAME:
+		cname, err := l.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return []string{cname}, nil
+	case TypeSRV:
+		_, srvs, err := l.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return nil, err
+		}
+		records := make([]SRVRecord, 0, len(srvs))
+		for _, srv := range srvs {
+			records = append(records, SRVRecord{
+				Target:   srv.Target,
+				Port:     srv.Port,
+				Priority: srv.Priority,
+				Weight:   srv.Weight,
+			})
+		}
+		return records, nil
+	case TypeTXT:
+		return l.LookupTXT(ctx, name)
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+}
+
+func resolveIP(
+	ctx context.Context, l lookuper, r netext.Resolver, opts lib.Options, name string, ipv4 bool,
+) ([]string, error) {
+	var ips []net.IP
+	if host := matchHost(opts, name); host != nil {
+		ips = []net.IP{host.IP}
+	} else if r != nil {
+		ip, err := lookupVUResolver(r, name, ipv4)
+		if err != nil {
+			return nil, err
+		}
+		if ip != nil {
+			ips = []net.IP{ip}
+		}
+	} else {
+		addrs, err := l.LookupIPAddr(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	records := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		if (ip.To4() != nil) == ipv4 {
+			records = append(records, ip.String())
+		}
+	}
+	return records, nil
+}
+
+// lookupVUResolver returns the address of the family that the resolver of the
+// VU selects for name, or nil if it has none.
+func lookupVUResolver(r netext.Resolver, name string, ipv4 bool) (net.IP, error) {
+	family := types.DialFamilyIPv6
+	if ipv4 {
+		family = types.DialFamilyIPv4
+	}
+	if fr, ok := r.(netext.FamilyResolver); ok {
+		return fr.LookupIPFamily(name, family)
+	}
+	ip, err := r.LookupIP(name)
+	if err != nil || ip == nil || !types.InDialFamily(ip, family) {
+		return nil, err
+	}
+	return ip, nil
+}
+
+func matchHost(opts lib.Options, name string) *types.Host {
+	if opts.Hosts.Trie == nil {
+		return nil
+	}
+	return opts.Hosts.Trie.Match(name)
+}
@@ -0,0 +1,61 @@
+package faker
+
+//nolint:gochecknoglobals
+var (
+	firstNames = []string{
+		"Ada", "Alan", "Alice", "Amara", "Ana", "Andrei", "Anna", "Arjun", "Ben", "Carla", "Carlos", "Chen",
+		"Chloe", "Daniel", "David", "Elena", "Emma", "Ethan", "Fatima", "Felix", "Grace", "Hana", "Hugo",
+		"Ibrahim", "Ines", "Isabel", "Ivan", "James", "Jana", "Javier", "Kai", "Kenji", "Laura", "Leo", "Lina",
+		"Lucas", "Luis", "Maria", "Mateo", "Maya", "Mei", "Mohammed", "Nadia", "Noah", "Nora", "Olga", "Omar",
+		"Oscar", "Paula", "Pedro", "Priya", "Rafael", "Rosa", "Sam", "Sara", "Sofia", "Tariq", "Theo", "Yuki",
+		"Zoe",
+	}
+	lastNames = []string{
+		"Adams", "Ahmed", "Almeida", "Andersson", "Bauer", "Brown", "Chen", "Costa", "Davies", "Dubois",
+		"Fernandez", "Fischer", "Garcia", "Gonzalez", "Hansen", "Hernandez", "Ivanov", "Jensen", "Johnson",
+		"Kim", "Kowalski", "Kumar", "Lee", "Lopez", "Martin", "Martinez", "Meyer", "Miller", "Moreau", "Nakamura",
+		"Nguyen", "Nielsen", "Novak", "Okafor", "Olsen", "Patel", "Perez", "Petrov", "Popescu", "Rossi",
+		"Santos", "Schmidt", "Silva", "Singh", "Smith", "Suzuki", "Tanaka", "Taylor", "Wang", "Williams",
+		"Wilson", "Yilmaz", "Zhang",
+	}
+	emailDomains = []string{"example.com", "example.net", "example.org"}
+	streetNames  = []string{
+		"Acacia", "Ash", "Birch", "Cedar", "Cherry", "Chestnut", "Elm", "Forest", "Garden", "Hill", "Lake",
+		"Maple", "Meadow", "Mill", "Oak", "Park", "Pine", "River", "Spring", "Station", "Sunset", "Valley",
+		"Willow",
+	}
+	streetSuffixes = []string{"Street", "Avenue", "Road", "Lane", "Drive", "Way", "Court", "Boulevard"}
+	cities         = []string{
+		"Springfield", "Riverside", "Fairview", "Greenville", "Franklin", "Clinton", "Madison", "Georgetown",
+		"Salem", "Arlington", "Ashland", "Oxford", "Milton", "Newport", "Bristol", "Dover", "Burlington",
+		"Kingston", "Lakewood", "Centerville",
+	}
+	countries = []string{
+		"Argentina", "Australia", "Brazil", "Canada", "Chile", "Denmark", "Egypt", "France", "Germany",
+		"Greece", "India", "Ireland", "Italy", "Japan", "Kenya", "Mexico", "Netherlands", "Nigeria", "Norway",
+		"Poland", "Portugal", "Romania", "South Korea", "Spain", "Sweden", "Turkey", "United Kingdom",
+		"United States", "Vietnam",
+	}
+	loremWords = []string{
+		"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do", "eiusmod",
+		"tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim", "ad", "minim",
+		"veniam", "quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip", "ex", "ea",
+		"commodo", "consequat", "duis", "aute", "irure", "in", "reprehenderit", "voluptate", "velit", "esse",
+		"cillum", "fugiat", "nulla", "pariatur", "excepteur", "sint", "occaecat", "cupidatat", "non",
+		"proident", "sunt", "culpa", "qui", "officia", "deserunt", "mollit", "anim", "id", "est", "laborum",
+	}
+)
+
+// creditCardType describes the numbers of the cards of a network.
+type creditCardType struct {
+	prefixes []string
+	length   int
+}
+
+//nolint:gochecknoglobals
+var creditCardTypes = map[string]creditCardType{
+	"visa":       {prefixes: []string{"4"}, length: 16},
+	"mastercard": {prefixes: []string{"51", "52", "53", "54", "55", "2221", "2720"}, length: 16},
+	"amex":       {prefixes: []string{"34", "37"}, length: 15},
+	"discover":   {prefixes: []string{"6011", "65"}, length: 16},
+}
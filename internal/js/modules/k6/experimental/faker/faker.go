@@ -0,0 +1,202 @@
+package faker
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Faker generates fake data. The same seed always generates the same
+// sequence of values.
+type Faker struct {
+	rand *rand.Rand
+}
+
+// NewFaker returns a new Faker with the seed.
+func NewFaker(seed1, seed2 uint64) *Faker {
+	return &Faker{rand: rand.New(rand.NewPCG(seed1, seed2))} //nolint:gosec
+}
+
+func (f *Faker) pick(values []string) string {
+	return values[f.rand.IntN(len(values))]
+}
+
+func (f *Faker) digits(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('0' + f.rand.IntN(10))
+	}
+	return string(b)
+}
+
+// FirstName returns a first name.
+func (f *Faker) FirstName() string {
+	return f.pick(firstNames)
+}
+
+// LastName returns a last name.
+func (f *Faker) LastName() string {
+	return f.pick(lastNames)
+}
+
+// Name returns a full name.
+func (f *Faker) Name() string {
+	return f.FirstName() + " " + f.LastName()
+}
+
+// Username returns a user name.
+func (f *Faker) Username() string {
+	name := strings.ToLower(f.FirstName())
+	switch f.rand.IntN(3) {
+	case 0:
+		return name + strconv.Itoa(f.rand.IntN(1000))
+	case 1:
+		return name + "." + strings.ToLower(f.LastName())
+	default:
+		return name + "_" + strings.ToLower(f.LastName()) + strconv.Itoa(f.rand.IntN(100))
+	}
+}
+
+// Email returns an email address in one of the domains reserved for examples.
+func (f *Faker) Email() string {
+	return f.Username() + "@" + f.pick(emailDomains)
+}
+
+// Phone returns a phone number.
+func (f *Faker) Phone() string {
+	return fmt.Sprintf("+1-%d%s-555-%s", 2+f.rand.IntN(8), f.digits(2), f.digits(4))
+}
+
+// Address is a postal address.
+type Address struct {
+	Street  string `js:"street"`
+	City    string `js:"city"`
+	ZipCode string `js:"zipCode"`
+	Country string `js:"country"`
+}
+
+// StreetAddress returns the number and the name of a street.
+func (f *Faker) StreetAddress() string {
+	return strconv.Itoa(1+f.rand.IntN(9999)) + " " + f.pick(streetNames) + " " + f.pick(streetSuffixes)
+}
+
+// City returns the name of a city.
+func (f *Faker) City() string {
+	return f.pick(cities)
+}
+
+// Country returns the name of a country.
+func (f *Faker) Country() string {
+	return f.pick(countries)
+}
+
+// ZipCode returns a postal code.
+func (f *Faker) ZipCode() string {
+	return f.digits(5)
+}
+
+// Address returns a postal address.
+func (f *Faker) Address() Address {
+	return Address{Street: f.StreetAddress(), City: f.City(), ZipCode: f.ZipCode(), Country: f.Country()}
+}
+
+// UUID returns a version 4 UUID.
+func (f *Faker) UUID() string {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(f.rand.IntN(256))
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// CreditCardTypes returns the types of the credit cards that CreditCard can
+// generate.
+func CreditCardTypes() []string {
+	types := make([]string, 0, len(creditCardTypes))
+	for t := range creditCardTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// CreditCard returns a credit card number of the type, or of a random type
+// if it's empty, with a valid Luhn check digit. The numbers aren't real, but
+// they pass the validations of their format.
+func (f *Faker) CreditCard(cardType string) (string, error) {
+	if cardType == "" {
+		types := CreditCardTypes()
+		cardType = types[f.rand.IntN(len(types))]
+	}
+	card, ok := creditCardTypes[strings.ToLower(cardType)]
+	if !ok {
+		return "", fmt.Errorf("unknown credit card type %q, it must be one of %s",
+			cardType, strings.Join(CreditCardTypes(), ", "))
+	}
+	prefix := f.pick(card.prefixes)
+	number := prefix + f.digits(card.length-len(prefix)-1)
+	return number + strconv.Itoa(luhnCheckDigit(number)), nil
+}
+
+// luhnCheckDigit returns the digit that makes the number valid according to
+// the Luhn algorithm.
+func luhnCheckDigit(number string) int {
+	sum := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if (len(number)-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// Word returns a lorem ipsum word.
+func (f *Faker) Word() string {
+	return f.pick(loremWords)
+}
+
+// Words returns the number of lorem ipsum words, or 3 if it isn't positive.
+func (f *Faker) Words(n int) []string {
+	if n <= 0 {
+		n = 3
+	}
+	words := make([]string, n)
+	for i := range words {
+		words[i] = f.Word()
+	}
+	return words
+}
+
+// Sentence returns a lorem ipsum sentence with the number of words, or
+// between 4 and 12 if it isn't positive.
+func (f *Faker) Sentence(words int) string {
+	if words <= 0 {
+		words = 4 + f.rand.IntN(9)
+	}
+	s := []rune(strings.Join(f.Words(words), " "))
+	s[0] = unicode.ToUpper(s[0])
+	return string(s) + "."
+}
+
+// Paragraph returns a lorem ipsum paragraph with the number of sentences, or
+// between 3 and 6 if it isn't positive.
+func (f *Faker) Paragraph(sentences int) string {
+	if sentences <= 0 {
+		sentences = 3 + f.rand.IntN(4)
+	}
+	s := make([]string, sentences)
+	for i := range s {
+		s[i] = f.Sentence(0)
+	}
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,129 @@
+// Package faker provides generators of fake data, like names, addresses or
+// credit card numbers.
+package faker
+
+import (
+	"math/rand/v2"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+)
+
+type (
+	// RootModule is the global module instance that will create instances of our
+	// module for each VU.
+	RootModule struct{}
+
+	// ModuleInstance represents an instance of the faker module for a single VU.
+	ModuleInstance struct {
+		vu modules.VU
+
+		// faker is used by the functions exported by the module, and it isn't
+		// seeded.
+		faker *Faker
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// New returns a pointer to a new [RootModule] instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface and returns a new
+// instance of our module for the given VU.
+func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	return &ModuleInstance{vu: vu, faker: NewFaker(rand.Uint64(), rand.Uint64())} //nolint:gosec
+}
+
+// Exports implements the modules.Module interface and returns the exports of
+// our module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	named := map[string]any{
+		"Faker": mi.NewFaker,
+	}
+	for name, method := range fakerMethods(mi.vu.Runtime(), func() *Faker { return mi.faker }) {
+		named[name] = method
+	}
+	return modules.Exports{Named: named}
+}
+
+// options are the options of the Faker constructor.
+type options struct {
+	// Seed makes the generated values reproducible. Each VU generates a
+	// different sequence of values from the same seed.
+	Seed *int64 `js:"seed"`
+}
+
+// NewFaker is the JS constructor of a Faker.
+func (mi *ModuleInstance) NewFaker(call sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+	var opts options
+	if !common.IsNullish(call.Argument(0)) {
+		if err := rt.ExportTo(call.Argument(0), &opts); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+
+	// The seeded faker is created when it's first used, because the VU isn't
+	// known yet if the constructor is called in the init context.
+	var f *Faker
+	faker := func() *Faker {
+		if f != nil {
+			return f
+		}
+		if opts.Seed == nil {
+			f = NewFaker(rand.Uint64(), rand.Uint64()) //nolint:gosec
+			return f
+		}
+		var vuID uint64
+		if state := mi.vu.State(); state != nil {
+			vuID = state.VUIDGlobal
+		}
+		f = NewFaker(uint64(*opts.Seed), vuID) //nolint:gosec
+		return f
+	}
+
+	obj := rt.NewObject()
+	for name, method := range fakerMethods(rt, faker) {
+		if err := obj.Set(name, method); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+	return obj
+}
+
+// fakerMethods returns the JS methods that generate values with the faker.
+func fakerMethods(rt *sobek.Runtime, faker func() *Faker) map[string]any {
+	return map[string]any{
+		"firstName":     func() string { return faker().FirstName() },
+		"lastName":      func() string { return faker().LastName() },
+		"name":          func() string { return faker().Name() },
+		"username":      func() string { return faker().Username() },
+		"email":         func() string { return faker().Email() },
+		"phone":         func() string { return faker().Phone() },
+		"streetAddress": func() string { return faker().StreetAddress() },
+		"city":          func() string { return faker().City() },
+		"country":       func() string { return faker().Country() },
+		"zipCode":       func() string { return faker().ZipCode() },
+		"address":       func() Address { return faker().Address() },
+		"uuid":          func() string { return faker().UUID() },
+		"creditCard": func(cardType string) string {
+			number, err := faker().CreditCard(cardType)
+			if err != nil {
+				common.Throw(rt, err)
+			}
+			return number
+		},
+		"word":      func() string { return faker().Word() },
+		"words":     func(n int) []string { return faker().Words(n) },
+		"sentence":  func(words int) string { return faker().Sentence(words) },
+		"paragraph": func(sentences int) string { return faker().Paragraph(sentences) },
+	}
+}
@@ -0,0 +1,110 @@
+package faker
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+)
+
+func newTestRuntime(t *testing.T) *modulestest.Runtime {
+	t.Helper()
+
+	rt := modulestest.NewRuntime(t)
+	m, ok := New().NewModuleInstance(rt.VU).(*ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, rt.VU.Runtime().Set("faker", m.Exports().Named))
+	return rt
+}
+
+func TestFaker(t *testing.T) {
+	t.Parallel()
+
+	f := NewFaker(1, 2)
+	for range 100 {
+		assert.Regexp(t, `^[A-Z][a-z]+ [A-Z][a-z]+$`, f.Name())
+		assert.Regexp(t, `^[a-z0-9._]+@example\.(com|net|org)$`, f.Email())
+		assert.Regexp(t, `^\+1-\d{3}-555-\d{4}$`, f.Phone())
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, f.UUID())
+		assert.Regexp(t, `^\d{5}$`, f.ZipCode())
+		assert.Regexp(t, `^[A-Z][a-z ]+\.$`, f.Sentence(0))
+		assert.Len(t, f.Words(5), 5)
+
+		address := f.Address()
+		assert.NotEmpty(t, address.Street)
+		assert.NotEmpty(t, address.City)
+		assert.NotEmpty(t, address.Country)
+	}
+	assert.Equal(t, NewFaker(1, 2).Paragraph(0), NewFaker(1, 2).Paragraph(0))
+	assert.NotEqual(t, NewFaker(1, 2).Paragraph(0), NewFaker(1, 3).Paragraph(0))
+}
+
+func TestFakerCreditCard(t *testing.T) {
+	t.Parallel()
+
+	valid := func(number string) bool {
+		return luhnCheckDigit(number[:len(number)-1]) == int(number[len(number)-1]-'0')
+	}
+	assert.True(t, valid("4111111111111111"))
+	assert.False(t, valid("4111111111111112"))
+
+	formats := map[string]*regexp.Regexp{
+		"visa":       regexp.MustCompile(`^4\d{15}$`),
+		"mastercard": regexp.MustCompile(`^(5[1-5]|2221|2720)\d+$`),
+		"amex":       regexp.MustCompile(`^3[47]\d{13}$`),
+		"discover":   regexp.MustCompile(`^(6011|65)\d+$`),
+	}
+	f := NewFaker(1, 2)
+	for cardType, format := range formats {
+		for range 20 {
+			number, err := f.CreditCard(cardType)
+			require.NoError(t, err)
+			assert.Regexp(t, format, number)
+			assert.True(t, valid(number), number)
+		}
+	}
+	number, err := f.CreditCard("")
+	require.NoError(t, err)
+	assert.True(t, valid(number), number)
+
+	_, err = f.CreditCard("unknown")
+	require.ErrorContains(t, err, `unknown credit card type "unknown", it must be one of amex, discover, mastercard, visa`)
+}
+
+func TestModuleSeed(t *testing.T) {
+	t.Parallel()
+
+	generate := func(script string, vuID uint64) string {
+		rt := newTestRuntime(t)
+		_, err := rt.VU.Runtime().RunString(`var f = ` + script)
+		require.NoError(t, err)
+		rt.MoveToVUContext(&lib.State{VUIDGlobal: vuID})
+		v, err := rt.VU.Runtime().RunString(`
+			const a = f.address();
+			[f.name(), f.email(), f.uuid(), f.creditCard("visa"), a.street, a.city, f.paragraph()].join("|")
+		`)
+		require.NoError(t, err)
+		return v.String()
+	}
+
+	seeded := `new faker.Faker({ seed: 42 })`
+	assert.Equal(t, generate(seeded, 1), generate(seeded, 1))
+	assert.NotEqual(t, generate(seeded, 1), generate(seeded, 2))
+	assert.NotEqual(t, generate(seeded, 1), generate(`new faker.Faker({ seed: 43 })`, 1))
+	assert.NotEqual(t, generate(`new faker.Faker()`, 1), generate(`new faker.Faker()`, 1))
+	assert.NotEqual(t, generate(`faker`, 1), generate(`faker`, 1))
+	assert.Equal(t, 7, strings.Count(generate(`faker`, 1), "|")+1)
+}
+
+func TestModuleCreditCardError(t *testing.T) {
+	t.Parallel()
+
+	rt := newTestRuntime(t)
+	_, err := rt.VU.Runtime().RunString(`faker.creditCard("unknown")`)
+	require.ErrorContains(t, err, `unknown credit card type "unknown"`)
+}
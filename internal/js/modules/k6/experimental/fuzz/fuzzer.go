@@ -0,0 +1,330 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultMaxLength = 64
+	// maxOutputLength bounds the size of the mutated values, so the repetitions
+	// can't make them grow without limit.
+	maxOutputLength = 64 * 1024
+)
+
+// interestingStrings are inserted into strings because they often expose
+// problems with escaping, encoding, parsing or with the lengths of buffers.
+//
+//nolint:gochecknoglobals
+var interestingStrings = []string{
+	"", " ", "\x00", "\t", "\r\n", "\n", "'", "\"", "`", "\\", "/", "../", "..\\",
+	"<", ">", "&", "%", "%s", "%n", "%x", "%%", "{}", "[]", "${", "{{", "}}",
+	"null", "undefined", "NaN", "true", "-1", "0", "1e309",
+	"\u202e", "\ufeff", "\u200b", "\uffff", "\U0001f600", "é", "\xff\xfe",
+}
+
+// interestingInts are the boundaries of the common integer types.
+//
+//nolint:gochecknoglobals
+var interestingInts = []float64{
+	0, 1, -1, 2, -2, 7, 8, 15, 16, 31, 32, 63, 64, 100, 127, -128, 128, 255, 256,
+	1023, 1024, 4095, 4096, 32767, -32768, 32768, 65535, 65536,
+	math.MaxInt32, math.MinInt32, math.MaxInt32 + 1, math.MinInt32 - 1, math.MaxUint32, math.MaxUint32 + 1,
+	1<<53 - 1, -(1<<53 - 1), 1 << 53, math.MaxInt64, math.MinInt64, math.MaxUint64,
+}
+
+// interestingFloats are the special and boundary floating point values.
+//
+//nolint:gochecknoglobals
+var interestingFloats = []float64{
+	math.NaN(), math.Inf(1), math.Inf(-1), math.Copysign(0, -1), 0.1, -0.1, 0.5,
+	math.MaxFloat64, -math.MaxFloat64, math.SmallestNonzeroFloat64, -math.SmallestNonzeroFloat64,
+	math.MaxFloat32, math.SmallestNonzeroFloat32, 1e-7, 1e21, 2.2250738585072014e-308,
+}
+
+// Fuzzer generates values for fuzzing. The same seed always generates the same
+// sequence of values.
+type Fuzzer struct {
+	rand *rand.Rand
+}
+
+// NewFuzzer returns a new Fuzzer with the seed.
+func NewFuzzer(seed1, seed2 uint64) *Fuzzer {
+	return &Fuzzer{rand: rand.New(rand.NewPCG(seed1, seed2))} //nolint:gosec
+}
+
+// StringOptions are the options of Fuzzer.String.
+type StringOptions struct {
+	MinLength int `js:"minLength"`
+	MaxLength int `js:"maxLength"`
+	// Charset is "ascii" for printable ASCII characters, "unicode" for any
+	// Unicode characters or "mixed", the default, for both and the
+	// interesting strings.
+	Charset string `js:"charset"`
+}
+
+// String returns a random string.
+func (f *Fuzzer) String(opts StringOptions) string {
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = max(opts.MinLength, defaultMaxLength)
+	}
+	opts.MaxLength = min(opts.MaxLength, maxOutputLength)
+	opts.MinLength = min(max(opts.MinLength, 0), opts.MaxLength)
+	length := opts.MinLength + f.rand.IntN(opts.MaxLength-opts.MinLength+1)
+
+	var sb strings.Builder
+	for n := 0; n < length; {
+		var s string
+		switch opts.Charset {
+		case "ascii":
+			s = f.asciiChar()
+		case "unicode":
+			s = f.unicodeChar()
+		default:
+			switch f.rand.IntN(4) {
+			case 0:
+				s = f.unicodeChar()
+			case 1:
+				s = f.pick(interestingStrings)
+			default:
+				s = f.asciiChar()
+			}
+		}
+		if s == "" {
+			continue
+		}
+		sb.WriteString(s)
+		n += utf8.RuneCountInString(s)
+	}
+	return sb.String()
+}
+
+func (f *Fuzzer) asciiChar() string {
+	return string(rune(' ' + f.rand.IntN('~'-' '+1)))
+}
+
+func (f *Fuzzer) unicodeChar() string {
+	for {
+		r := rune(f.rand.IntN(utf8.MaxRune + 1))
+		if f.rand.IntN(2) == 0 { // prefer the basic multilingual plane
+			r = rune(f.rand.IntN(0x10000))
+		}
+		if utf8.ValidRune(r) {
+			return string(r)
+		}
+	}
+}
+
+func (f *Fuzzer) pick(values []string) string {
+	return values[f.rand.IntN(len(values))]
+}
+
+// Number returns a boundary number. The kind is "int" for integers, "float"
+// for the special floating point values, or "any", the default, for both and
+// random numbers.
+func (f *Fuzzer) Number(kind string) float64 {
+	switch kind {
+	case "int":
+		return f.int()
+	case "float":
+		return interestingFloats[f.rand.IntN(len(interestingFloats))]
+	default:
+		switch f.rand.IntN(4) {
+		case 0:
+			return interestingFloats[f.rand.IntN(len(interestingFloats))]
+		case 1:
+			return (f.rand.Float64() - 0.5) * math.Pow(10, float64(f.rand.IntN(40)))
+		default:
+			return f.int()
+		}
+	}
+}
+
+func (f *Fuzzer) int() float64 {
+	n := interestingInts[f.rand.IntN(len(interestingInts))]
+	if f.rand.IntN(4) == 0 { // off by one
+		n += float64(f.rand.IntN(3) - 1)
+	}
+	return n
+}
+
+// MutateString returns the input with the number of random mutations, or
+// between 1 and 4 if it's not positive.
+func (f *Fuzzer) MutateString(input string, mutations int) string {
+	return string(f.mutate([]byte(input), nil, mutations, f.mutateStringOnce))
+}
+
+func (f *Fuzzer) mutateStringOnce(b []byte, _ [][]byte) []byte {
+	runes := []rune(string(b))
+	switch f.rand.IntN(6) {
+	case 0: // insert an interesting string
+		i := f.rand.IntN(len(runes) + 1)
+		return []byte(string(runes[:i]) + f.pick(interestingStrings) + string(runes[i:]))
+	case 1: // replace with an interesting string
+		if len(runes) == 0 {
+			return []byte(f.pick(interestingStrings))
+		}
+		i, j := f.span(len(runes))
+		return []byte(string(runes[:i]) + f.pick(interestingStrings) + string(runes[j:]))
+	case 2: // change the case or the code point of a character
+		if len(runes) == 0 {
+			return b
+		}
+		i := f.rand.IntN(len(runes))
+		if upper := strings.ToUpper(string(runes[i])); upper != string(runes[i]) {
+			runes[i] = []rune(upper)[0]
+		} else {
+			runes[i] ^= 1 << f.rand.IntN(7)
+		}
+		return []byte(string(runes))
+	case 3: // insert a random string
+		i := f.rand.IntN(len(runes) + 1)
+		return []byte(string(runes[:i]) + f.String(StringOptions{MaxLength: 8}) + string(runes[i:]))
+	default:
+		return f.mutateBytesOnce(b, nil)
+	}
+}
+
+// Mutate returns one of the inputs of the seed corpus with the number of
+// random byte-level mutations, or between 1 and 4 if it's not positive.
+// The result isn't necessarily valid UTF-8.
+func (f *Fuzzer) Mutate(corpus []string, mutations int) string {
+	if len(corpus) == 0 {
+		corpus = []string{""}
+	}
+	others := make([][]byte, len(corpus))
+	for i, c := range corpus {
+		others[i] = []byte(c)
+	}
+	input := []byte(corpus[f.rand.IntN(len(corpus))])
+	return string(f.mutate(input, others, mutations, f.mutateBytesOnce))
+}
+
+func (f *Fuzzer) mutate(
+	b []byte, corpus [][]byte, mutations int, mutateOnce func([]byte, [][]byte) []byte,
+) []byte {
+	if mutations <= 0 {
+		mutations = 1 + f.rand.IntN(4)
+	}
+	b = append([]byte(nil), b...)
+	for range mutations {
+		b = mutateOnce(b, corpus)
+		if len(b) > maxOutputLength {
+			b = b[:maxOutputLength]
+		}
+	}
+	return b
+}
+
+// span returns a random range of the indexes up to n, which isn't 0.
+func (f *Fuzzer) span(n int) (int, int) {
+	i := f.rand.IntN(n)
+	return i, i + 1 + f.rand.IntN(min(n-i, 16))
+}
+
+//nolint:cyclop
+func (f *Fuzzer) mutateBytesOnce(b []byte, corpus [][]byte) []byte {
+	if len(b) == 0 {
+		return []byte(f.pick(interestingStrings))
+	}
+	switch f.rand.IntN(9) {
+	case 0: // flip a bit
+		b[f.rand.IntN(len(b))] ^= 1 << f.rand.IntN(8)
+	case 1: // set a random byte
+		b[f.rand.IntN(len(b))] = byte(f.rand.IntN(256))
+	case 2: // set an interesting byte
+		interesting := []byte{0x00, 0x01, 0x7f, 0x80, 0xff, '\n', '"', '\\', '%'}
+		b[f.rand.IntN(len(b))] = interesting[f.rand.IntN(len(interesting))]
+	case 3: // delete a range
+		i, j := f.span(len(b))
+		b = append(b[:i], b[j:]...)
+	case 4: // duplicate a range
+		i, j := f.span(len(b))
+		b = append(b[:j], append(append([]byte(nil), b[i:j]...), b[j:]...)...)
+	case 5: // repeat a range many times
+		i, j := f.span(len(b))
+		repeated := strings.Repeat(string(b[i:j]), 2+f.rand.IntN(256))
+		b = append(b[:i], append([]byte(repeated), b[j:]...)...)
+	case 6: // insert an interesting string
+		i := f.rand.IntN(len(b) + 1)
+		b = append(b[:i], append([]byte(f.pick(interestingStrings)), b[i:]...)...)
+	case 7: // splice with another input of the corpus
+		if len(corpus) == 0 {
+			return f.mutateBytesOnce(b, nil)
+		}
+		other := corpus[f.rand.IntN(len(corpus))]
+		i := f.rand.IntN(len(b) + 1)
+		j := f.rand.IntN(len(other) + 1)
+		b = append(b[:i:i], other[j:]...)
+	default: // truncate
+		b = b[:f.rand.IntN(len(b))]
+	}
+	return b
+}
+
+// MalformedJSON returns the JSON of the value, or of a random object if it's
+// nil, with a random corruption that makes it invalid, or that is valid but
+// unusual, like deeply nested arrays or duplicated keys.
+func (f *Fuzzer) MalformedJSON(value any) string {
+	if value == nil {
+		value = map[string]any{
+			"id":    f.Number("int"),
+			"name":  f.String(StringOptions{Charset: "ascii", MaxLength: 16}),
+			"tags":  []any{f.String(StringOptions{MaxLength: 8}), f.Number("int")},
+			"valid": f.rand.IntN(2) == 0,
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		data = []byte("{}")
+	}
+	s := string(data)
+
+	switch f.rand.IntN(10) {
+	case 0: // truncated
+		return s[:f.rand.IntN(len(s))]
+	case 1: // an unbalanced bracket
+		return s + f.pick([]string{"}", "]", "{", "["})
+	case 2: // a trailing comma
+		if i := strings.LastIndexAny(s, "}]"); i > 0 {
+			return s[:i] + "," + s[i:]
+		}
+		return s + ","
+	case 3: // single quotes
+		return strings.ReplaceAll(s, `"`, `'`)
+	case 4: // invalid literals
+		return replaceValue(s, f.pick([]string{"NaN", "Infinity", "-Infinity", "undefined", "True", "nul", "0x10", "01"}))
+	case 5: // an invalid escape or control character
+		return replaceValue(s, f.pick([]string{`"\x41"`, `"\u12"`, "\"\x00\"", "\"\n\"", `"\"`, "\"\xff\""}))
+	case 6: // deeply nested arrays
+		depth := 1000 + f.rand.IntN(9000)
+		return strings.Repeat("[", depth) + s + strings.Repeat("]", depth)
+	case 7: // duplicated keys
+		if strings.HasPrefix(s, "{") && len(s) > 2 {
+			return s[:len(s)-1] + "," + s[1:]
+		}
+		return `{"a":1,"a":` + s + `}`
+	case 8: // a huge number
+		return replaceValue(s, "1"+strings.Repeat("0", 400)+"e"+strconv.Itoa(f.rand.IntN(100000)))
+	default: // garbage after the value
+		return s + f.pick([]string{" x", "\x00", "{}", " // comment", "/*", ";"})
+	}
+}
+
+// replaceValue replaces the first value after a colon or in an array, or the
+// whole JSON if there isn't one.
+func replaceValue(s, value string) string {
+	i := strings.IndexAny(s, ":[")
+	if i < 0 {
+		return value
+	}
+	end := strings.IndexAny(s[i+1:], ",}]")
+	if end < 0 {
+		return s[:i+1] + value
+	}
+	return s[:i+1] + value + s[i+1+end:]
+}
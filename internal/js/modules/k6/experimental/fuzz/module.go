@@ -0,0 +1,141 @@
+// Package fuzz provides generators of payloads for fuzzing.
+package fuzz
+
+import (
+	"errors"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+)
+
+type (
+	// RootModule is the global module instance that will create instances of our
+	// module for each VU.
+	RootModule struct{}
+
+	// ModuleInstance represents an instance of the fuzz module for a single VU.
+	ModuleInstance struct {
+		vu modules.VU
+
+		// seed is the seed set by the script, from which the seeds of the
+		// iterations are derived.
+		seed uint64
+		// fuzzer generates the values of the current iteration, as identified
+		// by fuzzerIteration.
+		fuzzer          *Fuzzer
+		fuzzerIteration int64
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// New returns a pointer to a new [RootModule] instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface and returns a new
+// instance of our module for the given VU.
+func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	return &ModuleInstance{vu: vu}
+}
+
+// Exports implements the modules.Module interface and returns the exports of
+// our module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	named := map[string]any{
+		"seed":   mi.Seed,
+		"Fuzzer": mi.NewFuzzer,
+	}
+	for name, method := range fuzzerMethods(mi.vu.Runtime(), mi.iterationFuzzer) {
+		named[name] = method
+	}
+	return modules.Exports{Named: named}
+}
+
+// Seed sets the seed from which the values of each iteration are generated.
+func (mi *ModuleInstance) Seed(seed sobek.Value) {
+	if common.IsNullish(seed) {
+		common.Throw(mi.vu.Runtime(), errors.New("the seed is required"))
+	}
+	mi.seed = uint64(seed.ToInteger()) //nolint:gosec
+	mi.fuzzer = nil
+}
+
+// iterationFuzzer returns the fuzzer of the current iteration of the VU. Its
+// seed depends on the VU and the iteration, so a failure can be reproduced by
+// running the same iteration of the same VU with the same seed.
+func (mi *ModuleInstance) iterationFuzzer() *Fuzzer {
+	var vuID uint64
+	iteration := int64(-1)
+	if state := mi.vu.State(); state != nil {
+		vuID, iteration = state.VUIDGlobal, state.Iteration
+	}
+	if mi.fuzzer == nil || mi.fuzzerIteration != iteration {
+		mi.fuzzer = NewFuzzer(mi.seed, vuID<<32|uint64(uint32(iteration+1))) //nolint:gosec
+		mi.fuzzerIteration = iteration
+	}
+	return mi.fuzzer
+}
+
+// NewFuzzer is the JS constructor of a Fuzzer with its own seed, independent
+// of the VU and the iteration.
+func (mi *ModuleInstance) NewFuzzer(call sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+	var seed uint64
+	if !common.IsNullish(call.Argument(0)) {
+		seed = uint64(call.Argument(0).ToInteger()) //nolint:gosec
+	}
+	f := NewFuzzer(seed, 0)
+
+	obj := rt.NewObject()
+	for name, method := range fuzzerMethods(rt, func() *Fuzzer { return f }) {
+		if err := obj.Set(name, method); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+	return obj
+}
+
+// fuzzerMethods returns the JS methods that generate values with the fuzzer.
+func fuzzerMethods(rt *sobek.Runtime, fuzzer func() *Fuzzer) map[string]any {
+	return map[string]any{
+		"string": func(opts sobek.Value) string {
+			var o StringOptions
+			if !common.IsNullish(opts) {
+				if err := rt.ExportTo(opts, &o); err != nil {
+					common.Throw(rt, err)
+				}
+			}
+			return fuzzer().String(o)
+		},
+		"mutateString": func(input string, mutations int) string {
+			return fuzzer().MutateString(input, mutations)
+		},
+		"number": func(kind string) float64 {
+			return fuzzer().Number(kind)
+		},
+		"json": func(value sobek.Value) string {
+			var v any
+			if !common.IsNullish(value) {
+				v = value.Export()
+			}
+			return fuzzer().MalformedJSON(v)
+		},
+		"mutate": func(corpus sobek.Value, mutations int) string {
+			var c []string
+			if common.IsNullish(corpus) {
+				common.Throw(rt, errors.New("the seed corpus is required"))
+			}
+			if err := rt.ExportTo(corpus, &c); err != nil {
+				common.Throw(rt, err)
+			}
+			return fuzzer().Mutate(c, mutations)
+		},
+	}
+}
@@ -0,0 +1,152 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+)
+
+func newTestRuntime(t *testing.T) *modulestest.Runtime {
+	t.Helper()
+
+	rt := modulestest.NewRuntime(t)
+	m, ok := New().NewModuleInstance(rt.VU).(*ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, rt.VU.Runtime().Set("fuzz", m.Exports().Named))
+	return rt
+}
+
+func TestFuzzerDeterministic(t *testing.T) {
+	t.Parallel()
+
+	generate := func(f *Fuzzer) []string {
+		var values []string
+		for range 50 {
+			values = append(values,
+				f.String(StringOptions{}),
+				f.MutateString("hello world", 0),
+				f.Mutate([]string{`{"a":1}`, "b=2&c=3"}, 0),
+				f.MalformedJSON(nil),
+			)
+		}
+		return values
+	}
+	assert.Equal(t, generate(NewFuzzer(1, 2)), generate(NewFuzzer(1, 2)))
+	assert.NotEqual(t, generate(NewFuzzer(1, 2)), generate(NewFuzzer(1, 3)))
+}
+
+func TestFuzzerString(t *testing.T) {
+	t.Parallel()
+
+	f := NewFuzzer(1, 0)
+	for range 100 {
+		s := f.String(StringOptions{MinLength: 3, MaxLength: 10, Charset: "ascii"})
+		assert.GreaterOrEqual(t, len(s), 3)
+		assert.LessOrEqual(t, len(s), 10)
+		for _, r := range s {
+			assert.True(t, r >= ' ' && r <= '~', "%q isn't printable ASCII", r)
+		}
+
+		s = f.String(StringOptions{MaxLength: 10, Charset: "unicode"})
+		assert.True(t, utf8.ValidString(s))
+		assert.LessOrEqual(t, utf8.RuneCountInString(s), 10)
+	}
+}
+
+func TestFuzzerNumber(t *testing.T) {
+	t.Parallel()
+
+	f := NewFuzzer(1, 0)
+	var nan, inf bool
+	for range 1000 {
+		n := f.Number("int")
+		assert.Equal(t, math.Trunc(n), n)
+
+		n = f.Number("float")
+		nan = nan || math.IsNaN(n)
+		inf = inf || math.IsInf(n, 0)
+	}
+	assert.True(t, nan)
+	assert.True(t, inf)
+}
+
+func TestFuzzerMutate(t *testing.T) {
+	t.Parallel()
+
+	f := NewFuzzer(1, 0)
+	corpus := []string{"GET /index.html", "POST /login"}
+	var changed int
+	for range 100 {
+		s := f.Mutate(corpus, 0)
+		assert.LessOrEqual(t, len(s), maxOutputLength)
+		if s != corpus[0] && s != corpus[1] {
+			changed++
+		}
+		assert.NotEqual(t, "hello", f.MutateString("hello", 10))
+	}
+	assert.Greater(t, changed, 90)
+}
+
+func TestFuzzerMalformedJSON(t *testing.T) {
+	t.Parallel()
+
+	f := NewFuzzer(1, 0)
+	var invalid int
+	for range 100 {
+		s := f.MalformedJSON(map[string]any{"name": "k6", "values": []any{1, 2}})
+		assert.NotEqual(t, `{"name":"k6","values":[1,2]}`, s)
+		if !json.Valid([]byte(s)) {
+			invalid++
+		}
+	}
+	assert.Greater(t, invalid, 50)
+}
+
+func TestModuleIterationSeed(t *testing.T) {
+	t.Parallel()
+
+	generate := func(seed, vuID uint64, iteration int64) string {
+		rt := newTestRuntime(t)
+		rt.MoveToVUContext(&lib.State{VUIDGlobal: vuID, Iteration: iteration})
+		v, err := rt.VU.Runtime().RunString(fmt.Sprintf(`
+			fuzz.seed(%d);
+			[fuzz.string(), fuzz.mutateString("hello"), fuzz.number(), fuzz.json(), fuzz.mutate(["a", "b"])].join("|")
+		`, seed))
+		require.NoError(t, err)
+		return v.String()
+	}
+
+	assert.Equal(t, generate(1, 1, 0), generate(1, 1, 0))
+	assert.NotEqual(t, generate(1, 1, 0), generate(1, 1, 1))
+	assert.NotEqual(t, generate(1, 1, 0), generate(1, 2, 0))
+	assert.NotEqual(t, generate(1, 1, 0), generate(2, 1, 0))
+}
+
+func TestModuleFuzzer(t *testing.T) {
+	t.Parallel()
+
+	rt := newTestRuntime(t)
+	v, err := rt.VU.Runtime().RunString(`
+		const a = new fuzz.Fuzzer(42), b = new fuzz.Fuzzer(42);
+		const s = a.string({ minLength: 5, maxLength: 5, charset: "ascii" });
+		if (s.length !== 5) {
+			throw new Error("unexpected length of " + JSON.stringify(s));
+		}
+		s === b.string({ minLength: 5, maxLength: 5, charset: "ascii" }) &&
+			a.json({ a: 1 }) === b.json({ a: 1 }) &&
+			a.mutate(["abc"], 3) === b.mutate(["abc"], 3)
+	`)
+	require.NoError(t, err)
+	assert.True(t, v.ToBoolean())
+
+	_, err = rt.VU.Runtime().RunString(`fuzz.mutate()`)
+	require.ErrorContains(t, err, "the seed corpus is required")
+}
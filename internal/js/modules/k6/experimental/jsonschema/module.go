@@ -0,0 +1,163 @@
+// Package jsonschema validates values, like the JSON bodies of the responses,
+// with JSON Schemas.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/metrics"
+)
+
+type (
+	// RootModule is the global module instance that will create instances of our
+	// module for each VU.
+	RootModule struct{}
+
+	// ModuleInstance represents an instance of the jsonschema module for a single VU.
+	ModuleInstance struct {
+		vu               modules.VU
+		validationFailed *metrics.Metric
+
+		// the compiled schemas, so that the same schema is compiled once per VU
+		objectSchemas map[*sobek.Object]*Schema
+		stringSchemas map[string]*Schema
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+const validationFailedName = "json_schema_validation_failed"
+
+// maxCachedSchemas limits the compiled schemas cached by each VU, for the
+// scripts that create a new schema object for every validation.
+const maxCachedSchemas = 1000
+
+// New returns a pointer to a new [RootModule] instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface and returns a new
+// instance of our module for the given VU.
+func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	validationFailed, err := vu.InitEnv().Registry.NewMetric(validationFailedName, metrics.Rate)
+	if err != nil {
+		common.Throw(vu.Runtime(), err)
+	}
+	return &ModuleInstance{
+		vu:               vu,
+		validationFailed: validationFailed,
+		objectSchemas:    make(map[*sobek.Object]*Schema),
+		stringSchemas:    make(map[string]*Schema),
+	}
+}
+
+// Exports implements the modules.Module interface and returns the exports of
+// our module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"validate": mi.validate,
+			"compile":  mi.compile,
+		},
+	}
+}
+
+// Result is the result of a validation.
+type Result struct {
+	Valid  bool              `js:"valid"`
+	Errors []ValidationError `js:"errors"`
+}
+
+// Validator validates values with a compiled schema.
+type Validator struct {
+	mi     *ModuleInstance
+	schema *Schema
+}
+
+// Validate validates the value.
+func (v *Validator) Validate(value sobek.Value) *Result {
+	return v.mi.run(v.schema, value)
+}
+
+// validate validates the value with the schema, which is an object or its
+// JSON. The schema is compiled the first time it's used.
+func (mi *ModuleInstance) validate(value sobek.Value, schema sobek.Value) *Result {
+	return mi.run(mi.schemaFor(schema), value)
+}
+
+// compile compiles the schema, for validating many values with it.
+func (mi *ModuleInstance) compile(schema sobek.Value) *Validator {
+	return &Validator{mi: mi, schema: mi.schemaFor(schema)}
+}
+
+func (mi *ModuleInstance) run(schema *Schema, value sobek.Value) *Result {
+	var v any
+	if !common.IsNullish(value) {
+		v = value.Export()
+	}
+	errs := schema.Validate(v)
+	if errs == nil {
+		errs = []ValidationError{}
+	}
+
+	if state := mi.vu.State(); state != nil {
+		tagsAndMeta := state.Tags.GetCurrentValues()
+		metrics.PushIfNotDone(mi.vu.Context(), state.Samples, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: mi.validationFailed, Tags: tagsAndMeta.Tags},
+			Time:       time.Now(),
+			Metadata:   tagsAndMeta.Metadata,
+			Value:      metrics.B(len(errs) > 0),
+		})
+	}
+	return &Result{Valid: len(errs) == 0, Errors: errs}
+}
+
+func (mi *ModuleInstance) schemaFor(v sobek.Value) *Schema {
+	rt := mi.vu.Runtime()
+	if common.IsNullish(v) {
+		common.Throw(rt, fmt.Errorf("the schema is required"))
+	}
+
+	if obj, ok := v.(*sobek.Object); ok {
+		if s, ok := mi.objectSchemas[obj]; ok {
+			return s
+		}
+		s, err := Compile(obj.Export())
+		if err != nil {
+			common.Throw(rt, fmt.Errorf("invalid schema: %w", err))
+		}
+		if len(mi.objectSchemas) >= maxCachedSchemas {
+			clear(mi.objectSchemas)
+		}
+		mi.objectSchemas[obj] = s
+		return s
+	}
+
+	source := v.String()
+	if s, ok := mi.stringSchemas[source]; ok {
+		return s
+	}
+	var raw any
+	if err := json.Unmarshal([]byte(source), &raw); err != nil {
+		common.Throw(rt, fmt.Errorf("the schema must be an object or its JSON: %w", err))
+	}
+	s, err := Compile(raw)
+	if err != nil {
+		common.Throw(rt, fmt.Errorf("invalid schema: %w", err))
+	}
+	if len(mi.stringSchemas) >= maxCachedSchemas {
+		clear(mi.stringSchemas)
+	}
+	mi.stringSchemas[source] = s
+	return s
+}
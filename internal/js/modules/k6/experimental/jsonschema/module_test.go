@@ -0,0 +1,101 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+func newTestRuntime(t *testing.T) (*modulestest.Runtime, *ModuleInstance, chan metrics.SampleContainer) {
+	t.Helper()
+
+	rt := modulestest.NewRuntime(t)
+	m, ok := New().NewModuleInstance(rt.VU).(*ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, rt.VU.Runtime().Set("jsonschema", m.Exports().Named))
+
+	registry := metrics.NewRegistry()
+	samples := make(chan metrics.SampleContainer, 100)
+	rt.MoveToVUContext(&lib.State{
+		Samples: samples,
+		Tags:    lib.NewVUStateTags(registry.RootTagSet()),
+	})
+	return rt, m, samples
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	rt, m, samples := newTestRuntime(t)
+	_, err := rt.RunOnEventLoop(`
+		const schema = {
+			type: "object",
+			required: ["id"],
+			properties: { id: { type: "integer" }, name: { type: "string" } },
+		};
+		for (let i = 0; i < 3; i++) {
+			const res = jsonschema.validate({ id: i, name: "k6" }, schema);
+			if (!res.valid || res.errors.length !== 0) {
+				throw new Error("unexpected invalid result: " + JSON.stringify(res));
+			}
+		}
+
+		const res = jsonschema.validate(JSON.parse('{"id": 1.5}'), schema);
+		if (res.valid) {
+			throw new Error("unexpected valid result");
+		}
+		const got = JSON.stringify(res.errors);
+		const want = JSON.stringify([{ instancePath: "/id", keyword: "type", message: "expected integer, but it's number" }]);
+		if (got !== want) {
+			throw new Error("unexpected errors: " + got);
+		}
+
+		if (!jsonschema.validate("k6", '{"type": "string"}').valid || jsonschema.validate(null, true).valid !== true) {
+			throw new Error("unexpected result of the JSON schemas");
+		}
+
+		const validator = jsonschema.compile({ items: { minimum: 0 } });
+		if (!validator.validate([0, 1]).valid || validator.validate([-1]).valid) {
+			throw new Error("unexpected result of the compiled schema");
+		}
+	`)
+	require.NoError(t, err)
+
+	// the schemas are compiled once, even if they are used many times
+	assert.Len(t, m.objectSchemas, 2)
+	assert.Len(t, m.stringSchemas, 2)
+
+	var values []float64
+	for _, sc := range metrics.GetBufferedSamples(samples) {
+		for _, s := range sc.GetSamples() {
+			assert.Equal(t, validationFailedName, s.Metric.Name)
+			values = append(values, s.Value)
+		}
+	}
+	assert.Equal(t, []float64{0, 0, 0, 1, 0, 0, 0, 1}, values)
+}
+
+func TestValidateErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`jsonschema.validate({}, { type: "int" })`: `invalid schema: #/type has an unknown type "int"`,
+		`jsonschema.validate({}, "{")`:             "the schema must be an object or its JSON",
+		`jsonschema.validate({})`:                  "the schema is required",
+		`jsonschema.compile({ minimum: "1" })`:     "invalid schema: #/minimum must be a number",
+	}
+	for script, want := range tests {
+		t.Run(script, func(t *testing.T) {
+			t.Parallel()
+
+			rt, _, _ := newTestRuntime(t)
+			_, err := rt.RunOnEventLoop(script)
+			require.ErrorContains(t, err, want)
+		})
+	}
+}
@@ -0,0 +1,851 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Schema is a compiled JSON Schema. It supports the validation keywords of
+// the 2020-12 draft, with the items and additionalItems of the older drafts,
+// and the $ref to the schemas in the same document, like the ones in $defs.
+// The schemas with the keywords in unsupportedKeywords aren't compiled, instead
+// of being validated as if those keywords weren't there.
+type Schema struct {
+	// always is the result of the boolean schemas, true and false.
+	always *bool
+
+	ref *Schema
+
+	types    []string
+	enum     []any
+	constVal any
+	hasConst bool
+
+	properties           map[string]*Schema
+	patternProperties    []patternSchema
+	additionalProperties *Schema
+	propertyNames        *Schema
+	required             []string
+	dependentRequired    map[string][]string
+	minProperties        *int
+	maxProperties        *int
+
+	prefixItems []*Schema
+	items       *Schema
+	contains    *Schema
+	minContains *int
+	maxContains *int
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+	format    string
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+	not   *Schema
+	ifS   *Schema
+	thenS *Schema
+	elseS *Schema
+}
+
+type patternSchema struct {
+	re     *regexp.Regexp
+	schema *Schema
+}
+
+// ValidationError is a reason for which a value isn't valid.
+type ValidationError struct {
+	// InstancePath is the JSON pointer to the invalid part of the value.
+	InstancePath string `js:"instancePath" json:"instancePath"`
+	Keyword      string `js:"keyword" json:"keyword"`
+	Message      string `js:"message" json:"message"`
+}
+
+// unsupportedKeywords are the keywords of the 2020-12 draft, and of the older
+// ones, that affect the validation but aren't supported.
+//
+//nolint:gochecknoglobals
+var unsupportedKeywords = []string{
+	"unevaluatedProperties", "unevaluatedItems", "dependentSchemas", "dependencies",
+	"$anchor", "$dynamicRef", "$dynamicAnchor", "$recursiveRef", "$recursiveAnchor",
+}
+
+// Compile compiles the schema, as decoded from JSON or exported from JS.
+func Compile(schema any) (*Schema, error) {
+	c := &compiler{root: schema, refs: make(map[string]*Schema)}
+	return c.compile(schema, "#")
+}
+
+type compiler struct {
+	root any
+	refs map[string]*Schema
+}
+
+//nolint:funlen,gocognit,cyclop
+func (c *compiler) compile(raw any, location string) (*Schema, error) {
+	if s, ok := c.refs[location]; ok {
+		return s, nil
+	}
+	s := &Schema{}
+	// registered before compiling the subschemas, for the recursive $refs
+	c.refs[location] = s
+
+	if b, ok := raw.(bool); ok {
+		s.always = &b
+		return s, nil
+	}
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: a schema must be an object or a boolean, but it's %s", location, jsonType(raw))
+	}
+	for _, keyword := range unsupportedKeywords {
+		if _, ok := obj[keyword]; ok {
+			return nil, fmt.Errorf("%s/%s: the keyword isn't supported", location, keyword)
+		}
+	}
+
+	var err error
+	sub := func(keyword string) (*Schema, error) {
+		v, ok := obj[keyword]
+		if !ok {
+			return nil, nil //nolint:nilnil
+		}
+		return c.compile(v, location+"/"+escapePointer(keyword))
+	}
+	subList := func(keyword string) ([]*Schema, error) {
+		v, ok := obj[keyword]
+		if !ok {
+			return nil, nil
+		}
+		list, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/%s must be an array", location, keyword)
+		}
+		schemas := make([]*Schema, len(list))
+		for i, item := range list {
+			if schemas[i], err = c.compile(item, location+"/"+keyword+"/"+strconv.Itoa(i)); err != nil {
+				return nil, err
+			}
+		}
+		return schemas, nil
+	}
+	subMap := func(keyword string) (map[string]*Schema, error) {
+		v, ok := obj[keyword]
+		if !ok {
+			return nil, nil
+		}
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/%s must be an object", location, keyword)
+		}
+		schemas := make(map[string]*Schema, len(m))
+		for name, item := range m {
+			if schemas[name], err = c.compile(item, location+"/"+keyword+"/"+escapePointer(name)); err != nil {
+				return nil, err
+			}
+		}
+		return schemas, nil
+	}
+	integer := func(keyword string) (*int, error) {
+		v, ok := obj[keyword]
+		if !ok {
+			return nil, nil
+		}
+		n, ok := toNumber(v)
+		if !ok || n < 0 || n != math.Trunc(n) {
+			return nil, fmt.Errorf("%s/%s must be a non-negative integer", location, keyword)
+		}
+		i := int(n)
+		return &i, nil
+	}
+	number := func(keyword string) (*float64, error) {
+		v, ok := obj[keyword]
+		if !ok {
+			return nil, nil
+		}
+		n, ok := toNumber(v)
+		if !ok {
+			return nil, fmt.Errorf("%s/%s must be a number", location, keyword)
+		}
+		return &n, nil
+	}
+
+	if ref, ok := obj["$ref"].(string); ok {
+		if s.ref, err = c.resolve(ref); err != nil {
+			return nil, fmt.Errorf("%s/$ref: %w", location, err)
+		}
+	}
+
+	switch t := obj["type"].(type) {
+	case nil:
+	case string:
+		s.types = []string{t}
+	case []any:
+		for _, item := range t {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s/type must be a string or an array of strings", location)
+			}
+			s.types = append(s.types, name)
+		}
+	default:
+		return nil, fmt.Errorf("%s/type must be a string or an array of strings", location)
+	}
+	for _, t := range s.types {
+		switch t {
+		case "null", "boolean", "object", "array", "number", "integer", "string":
+		default:
+			return nil, fmt.Errorf("%s/type has an unknown type %q", location, t)
+		}
+	}
+
+	if v, ok := obj["enum"]; ok {
+		if s.enum, ok = v.([]any); !ok {
+			return nil, fmt.Errorf("%s/enum must be an array", location)
+		}
+	}
+	s.constVal, s.hasConst = obj["const"]
+
+	if s.properties, err = subMap("properties"); err != nil {
+		return nil, err
+	}
+	patterns, err := subMap("patternProperties")
+	if err != nil {
+		return nil, err
+	}
+	for pattern, schema := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s/patternProperties: %w", location, err)
+		}
+		s.patternProperties = append(s.patternProperties, patternSchema{re: re, schema: schema})
+	}
+	if s.additionalProperties, err = sub("additionalProperties"); err != nil {
+		return nil, err
+	}
+	if s.propertyNames, err = sub("propertyNames"); err != nil {
+		return nil, err
+	}
+	if v, ok := obj["required"]; ok {
+		if s.required, err = stringList(v); err != nil {
+			return nil, fmt.Errorf("%s/required %w", location, err)
+		}
+	}
+	if v, ok := obj["dependentRequired"]; ok {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/dependentRequired must be an object", location)
+		}
+		s.dependentRequired = make(map[string][]string, len(m))
+		for name, deps := range m {
+			if s.dependentRequired[name], err = stringList(deps); err != nil {
+				return nil, fmt.Errorf("%s/dependentRequired/%s %w", location, name, err)
+			}
+		}
+	}
+	if s.minProperties, err = integer("minProperties"); err != nil {
+		return nil, err
+	}
+	if s.maxProperties, err = integer("maxProperties"); err != nil {
+		return nil, err
+	}
+
+	if s.prefixItems, err = subList("prefixItems"); err != nil {
+		return nil, err
+	}
+	if _, isList := obj["items"].([]any); isList {
+		// the tuples of the drafts before 2020-12
+		if s.prefixItems, err = subList("items"); err != nil {
+			return nil, err
+		}
+		if s.items, err = sub("additionalItems"); err != nil {
+			return nil, err
+		}
+	} else if s.items, err = sub("items"); err != nil {
+		return nil, err
+	}
+	if s.contains, err = sub("contains"); err != nil {
+		return nil, err
+	}
+	if s.minContains, err = integer("minContains"); err != nil {
+		return nil, err
+	}
+	if s.maxContains, err = integer("maxContains"); err != nil {
+		return nil, err
+	}
+	if s.minItems, err = integer("minItems"); err != nil {
+		return nil, err
+	}
+	if s.maxItems, err = integer("maxItems"); err != nil {
+		return nil, err
+	}
+	s.uniqueItems, _ = obj["uniqueItems"].(bool)
+
+	if s.minLength, err = integer("minLength"); err != nil {
+		return nil, err
+	}
+	if s.maxLength, err = integer("maxLength"); err != nil {
+		return nil, err
+	}
+	if v, ok := obj["pattern"]; ok {
+		pattern, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s/pattern must be a string", location)
+		}
+		if s.pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("%s/pattern: %w", location, err)
+		}
+	}
+	s.format, _ = obj["format"].(string)
+
+	if s.minimum, err = number("minimum"); err != nil {
+		return nil, err
+	}
+	if s.maximum, err = number("maximum"); err != nil {
+		return nil, err
+	}
+	// draft-04 has boolean exclusiveMinimum and exclusiveMaximum
+	if exclusive, _ := obj["exclusiveMinimum"].(bool); exclusive {
+		s.exclusiveMinimum, s.minimum = s.minimum, nil
+	} else if s.exclusiveMinimum, err = number("exclusiveMinimum"); err != nil && !isBool(obj["exclusiveMinimum"]) {
+		return nil, err
+	}
+	if exclusive, _ := obj["exclusiveMaximum"].(bool); exclusive {
+		s.exclusiveMaximum, s.maximum = s.maximum, nil
+	} else if s.exclusiveMaximum, err = number("exclusiveMaximum"); err != nil && !isBool(obj["exclusiveMaximum"]) {
+		return nil, err
+	}
+	if s.multipleOf, err = number("multipleOf"); err != nil {
+		return nil, err
+	}
+	if s.multipleOf != nil && *s.multipleOf <= 0 {
+		return nil, fmt.Errorf("%s/multipleOf must be greater than 0", location)
+	}
+
+	if s.allOf, err = subList("allOf"); err != nil {
+		return nil, err
+	}
+	if s.anyOf, err = subList("anyOf"); err != nil {
+		return nil, err
+	}
+	if s.oneOf, err = subList("oneOf"); err != nil {
+		return nil, err
+	}
+	if s.not, err = sub("not"); err != nil {
+		return nil, err
+	}
+	if s.ifS, err = sub("if"); err != nil {
+		return nil, err
+	}
+	if s.thenS, err = sub("then"); err != nil {
+		return nil, err
+	}
+	if s.elseS, err = sub("else"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// resolve compiles the schema that the $ref points to, which has to be in the
+// same document, like "#/$defs/name".
+func (c *compiler) resolve(ref string) (*Schema, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("only the references within the schema are supported, but it's %q", ref)
+	}
+	pointer, err := url.PathUnescape(ref[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %q: %w", ref, err)
+	}
+	location := "#"
+	raw := c.root
+	if pointer != "" {
+		for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			location += "/" + escapePointer(token)
+			switch v := raw.(type) {
+			case map[string]any:
+				raw = v[token]
+			case []any:
+				i, err := strconv.Atoi(token)
+				if err != nil || i < 0 || i >= len(v) {
+					return nil, fmt.Errorf("the reference %q doesn't exist", ref)
+				}
+				raw = v[i]
+			default:
+				raw = nil
+			}
+			if raw == nil {
+				return nil, fmt.Errorf("the reference %q doesn't exist", ref)
+			}
+		}
+	}
+	return c.compile(raw, location)
+}
+
+// Validate returns the reasons why the value isn't valid, if any. The value
+// has to be decoded from JSON or exported from JS.
+func (s *Schema) Validate(v any) []ValidationError {
+	var errs []ValidationError
+	s.validate(v, "", &errs)
+	return errs
+}
+
+// IsValid returns whether the value is valid, faster than Validate.
+func (s *Schema) IsValid(v any) bool {
+	return s.validate(v, "", nil)
+}
+
+// validate validates the value, with the JSON pointer path. If errs is nil,
+// it returns as soon as the value is found invalid, without describing why.
+//
+//nolint:funlen,gocognit,cyclop
+func (s *Schema) validate(v any, path string, errs *[]ValidationError) bool {
+	if s.always != nil {
+		if !*s.always {
+			return fail(errs, path, "false", "no value is allowed")
+		}
+		return true
+	}
+
+	valid := true
+	check := func(ok bool) bool {
+		valid = valid && ok
+		return errs == nil && !valid
+	}
+
+	if s.ref != nil && check(s.ref.validate(v, path, errs)) {
+		return false
+	}
+
+	if len(s.types) > 0 && !s.hasType(v) && check(fail(errs, path, "type",
+		fmt.Sprintf("expected %s, but it's %s", strings.Join(s.types, " or "), jsonType(v)))) {
+		return false
+	}
+	if s.enum != nil {
+		found := false
+		for _, e := range s.enum {
+			if jsonEqual(v, e) {
+				found = true
+				break
+			}
+		}
+		if !found && check(fail(errs, path, "enum", "must be one of the values in enum")) {
+			return false
+		}
+	}
+	if s.hasConst && !jsonEqual(v, s.constVal) && check(fail(errs, path, "const", "must be equal to const")) {
+		return false
+	}
+
+	switch value := v.(type) {
+	case map[string]any:
+		if !s.validateObject(value, path, errs, check) {
+			return false
+		}
+	case []any:
+		if !s.validateArray(value, path, errs, check) {
+			return false
+		}
+	case string:
+		if !s.validateString(value, path, errs, check) {
+			return false
+		}
+	default:
+		if n, ok := toNumber(v); ok && !s.validateNumber(n, path, errs, check) {
+			return false
+		}
+	}
+
+	for _, sub := range s.allOf {
+		if check(sub.validate(v, path, errs)) {
+			return false
+		}
+	}
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if sub.validate(v, path, nil) {
+				matched = true
+				break
+			}
+		}
+		if !matched && check(fail(errs, path, "anyOf", "must match at least one of the schemas in anyOf")) {
+			return false
+		}
+	}
+	if len(s.oneOf) > 0 {
+		matched := 0
+		for _, sub := range s.oneOf {
+			if sub.validate(v, path, nil) {
+				matched++
+			}
+		}
+		if matched != 1 && check(fail(errs, path, "oneOf",
+			fmt.Sprintf("must match exactly one of the schemas in oneOf, but it matches %d", matched))) {
+			return false
+		}
+	}
+	if s.not != nil && s.not.validate(v, path, nil) && check(fail(errs, path, "not", "must not match the schema in not")) {
+		return false
+	}
+	if s.ifS != nil {
+		if s.ifS.validate(v, path, nil) {
+			if s.thenS != nil && check(s.thenS.validate(v, path, errs)) {
+				return false
+			}
+		} else if s.elseS != nil && check(s.elseS.validate(v, path, errs)) {
+			return false
+		}
+	}
+	return valid
+}
+
+//nolint:gocognit,cyclop
+func (s *Schema) validateObject(
+	obj map[string]any, path string, errs *[]ValidationError, check func(bool) bool,
+) bool {
+	if s.minProperties != nil && len(obj) < *s.minProperties && check(fail(errs, path, "minProperties",
+		fmt.Sprintf("must have at least %d properties", *s.minProperties))) {
+		return false
+	}
+	if s.maxProperties != nil && len(obj) > *s.maxProperties && check(fail(errs, path, "maxProperties",
+		fmt.Sprintf("must have at most %d properties", *s.maxProperties))) {
+		return false
+	}
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok && check(fail(errs, path, "required",
+			fmt.Sprintf("missing required property %q", name))) {
+			return false
+		}
+	}
+	for name, deps := range s.dependentRequired {
+		if _, ok := obj[name]; !ok {
+			continue
+		}
+		for _, dep := range deps {
+			if _, ok := obj[dep]; !ok && check(fail(errs, path, "dependentRequired",
+				fmt.Sprintf("property %q is required when %q is present", dep, name))) {
+				return false
+			}
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	if errs != nil {
+		sort.Strings(names) // for a stable order of the errors
+	}
+	for _, name := range names {
+		value := obj[name]
+		propPath := path + "/" + escapePointer(name)
+		if s.propertyNames != nil && !s.propertyNames.validate(name, propPath, nil) &&
+			check(fail(errs, propPath, "propertyNames", fmt.Sprintf("invalid property name %q", name))) {
+			return false
+		}
+
+		evaluated := false
+		if sub, ok := s.properties[name]; ok {
+			evaluated = true
+			if check(sub.validate(value, propPath, errs)) {
+				return false
+			}
+		}
+		for _, ps := range s.patternProperties {
+			if ps.re.MatchString(name) {
+				evaluated = true
+				if check(ps.schema.validate(value, propPath, errs)) {
+					return false
+				}
+			}
+		}
+		if !evaluated && s.additionalProperties != nil {
+			if s.additionalProperties.always != nil && !*s.additionalProperties.always {
+				if check(fail(errs, propPath, "additionalProperties",
+					fmt.Sprintf("additional property %q isn't allowed", name))) {
+					return false
+				}
+			} else if check(s.additionalProperties.validate(value, propPath, errs)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//nolint:gocognit,cyclop
+func (s *Schema) validateArray(arr []any, path string, errs *[]ValidationError, check func(bool) bool) bool {
+	if s.minItems != nil && len(arr) < *s.minItems && check(fail(errs, path, "minItems",
+		fmt.Sprintf("must have at least %d items", *s.minItems))) {
+		return false
+	}
+	if s.maxItems != nil && len(arr) > *s.maxItems && check(fail(errs, path, "maxItems",
+		fmt.Sprintf("must have at most %d items", *s.maxItems))) {
+		return false
+	}
+	for i, item := range arr {
+		itemPath := path + "/" + strconv.Itoa(i)
+		var sub *Schema
+		if i < len(s.prefixItems) {
+			sub = s.prefixItems[i]
+		} else {
+			sub = s.items
+		}
+		if sub != nil && check(sub.validate(item, itemPath, errs)) {
+			return false
+		}
+	}
+	if s.contains != nil {
+		matched := 0
+		for _, item := range arr {
+			if s.contains.validate(item, path, nil) {
+				matched++
+			}
+		}
+		minContains := 1
+		if s.minContains != nil {
+			minContains = *s.minContains
+		}
+		if matched < minContains && check(fail(errs, path, "contains",
+			fmt.Sprintf("must contain at least %d items matching the schema in contains", minContains))) {
+			return false
+		}
+		if s.maxContains != nil && matched > *s.maxContains && check(fail(errs, path, "maxContains",
+			fmt.Sprintf("must contain at most %d items matching the schema in contains", *s.maxContains))) {
+			return false
+		}
+	}
+	if s.uniqueItems {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if jsonEqual(arr[i], arr[j]) && check(fail(errs, path, "uniqueItems",
+					fmt.Sprintf("items %d and %d are equal", i, j))) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (s *Schema) validateString(str string, path string, errs *[]ValidationError, check func(bool) bool) bool {
+	if s.minLength != nil || s.maxLength != nil {
+		length := utf8.RuneCountInString(str)
+		if s.minLength != nil && length < *s.minLength && check(fail(errs, path, "minLength",
+			fmt.Sprintf("must be at least %d characters long", *s.minLength))) {
+			return false
+		}
+		if s.maxLength != nil && length > *s.maxLength && check(fail(errs, path, "maxLength",
+			fmt.Sprintf("must be at most %d characters long", *s.maxLength))) {
+			return false
+		}
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) && check(fail(errs, path, "pattern",
+		fmt.Sprintf("must match the pattern %q", s.pattern.String()))) {
+		return false
+	}
+	if s.format != "" && !validFormat(s.format, str) && check(fail(errs, path, "format",
+		fmt.Sprintf("must be a valid %s", s.format))) {
+		return false
+	}
+	return true
+}
+
+func (s *Schema) validateNumber(n float64, path string, errs *[]ValidationError, check func(bool) bool) bool {
+	format := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+	if s.minimum != nil && n < *s.minimum && check(fail(errs, path, "minimum",
+		"must be greater than or equal to "+format(*s.minimum))) {
+		return false
+	}
+	if s.maximum != nil && n > *s.maximum && check(fail(errs, path, "maximum",
+		"must be less than or equal to "+format(*s.maximum))) {
+		return false
+	}
+	if s.exclusiveMinimum != nil && n <= *s.exclusiveMinimum && check(fail(errs, path, "exclusiveMinimum",
+		"must be greater than "+format(*s.exclusiveMinimum))) {
+		return false
+	}
+	if s.exclusiveMaximum != nil && n >= *s.exclusiveMaximum && check(fail(errs, path, "exclusiveMaximum",
+		"must be less than "+format(*s.exclusiveMaximum))) {
+		return false
+	}
+	if s.multipleOf != nil {
+		q := n / *s.multipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 && check(fail(errs, path, "multipleOf",
+			"must be a multiple of "+format(*s.multipleOf))) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Schema) hasType(v any) bool {
+	actual := jsonType(v)
+	for _, t := range s.types {
+		switch {
+		case t == actual:
+			return true
+		case t == "number" && actual == "integer":
+			return true
+		}
+	}
+	return false
+}
+
+func fail(errs *[]ValidationError, path, keyword, message string) bool {
+	if errs != nil {
+		*errs = append(*errs, ValidationError{InstancePath: path, Keyword: keyword, Message: message})
+	}
+	return false
+}
+
+// jsonType returns the JSON Schema type of the value, with integer for the
+// numbers without a fractional part.
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	}
+	if n, ok := toNumber(v); ok {
+		if n == math.Trunc(n) && !math.IsInf(n, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func isBool(v any) bool {
+	_, ok := v.(bool)
+	return ok
+}
+
+func jsonEqual(a, b any) bool {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			if other, ok := bv[k]; !ok || !jsonEqual(v, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !jsonEqual(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if an, ok := toNumber(a); ok {
+		bn, ok := toNumber(b)
+		return ok && an == bn
+	}
+	return a == b
+}
+
+func stringList(v any) ([]string, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, errors.New("must be an array of strings")
+	}
+	result := make([]string, len(list))
+	for i, item := range list {
+		if result[i], ok = item.(string); !ok {
+			return nil, errors.New("must be an array of strings")
+		}
+	}
+	return result, nil
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+var uuidPattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// validFormat validates the most common formats. The unknown formats are only
+// annotations, so any value is valid for them.
+func validFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, strings.ToUpper(s))
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	case "ipv6":
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	default:
+		return true
+	}
+}
@@ -0,0 +1,145 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
+
+func TestSchemaKeywords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		schema  string
+		valid   []string
+		invalid []string
+	}{
+		{"boolean", `false`, nil, []string{`1`, `null`}},
+		{"type", `{"type": "integer"}`, []string{`1`, `2.0`}, []string{`1.5`, `"1"`}},
+		{"types", `{"type": ["string", "null"]}`, []string{`"a"`, `null`}, []string{`1`, `{}`}},
+		{"number", `{"type": "number"}`, []string{`1`, `1.5`}, []string{`true`}},
+		{"enum", `{"enum": [1, "a", {"b": [2]}]}`, []string{`1.0`, `"a"`, `{"b": [2]}`}, []string{`2`, `{"b": []}`}},
+		{"const", `{"const": "a"}`, []string{`"a"`}, []string{`"b"`}},
+		{
+			"object",
+			`{"properties": {"a": {"type": "string"}}, "required": ["a"], "additionalProperties": false}`,
+			[]string{`{"a": "x"}`, `1`},
+			[]string{`{}`, `{"a": 1}`, `{"a": "x", "b": 1}`},
+		},
+		{
+			"patternProperties",
+			`{"patternProperties": {"^x-": {"type": "integer"}}, "additionalProperties": {"type": "string"}}`,
+			[]string{`{"x-a": 1, "b": "c"}`},
+			[]string{`{"x-a": "1"}`, `{"b": 1}`},
+		},
+		{"propertyNames", `{"propertyNames": {"maxLength": 2}}`, []string{`{"ab": 1}`}, []string{`{"abc": 1}`}},
+		{"properties count", `{"minProperties": 1, "maxProperties": 2}`, []string{`{"a": 1}`}, []string{`{}`, `{"a": 1, "b": 2, "c": 3}`}},
+		{"dependentRequired", `{"dependentRequired": {"a": ["b"]}}`, []string{`{"a": 1, "b": 2}`, `{"b": 2}`}, []string{`{"a": 1}`}},
+		{"items", `{"items": {"type": "integer"}, "minItems": 1, "maxItems": 2}`, []string{`[1]`, `[1, 2]`}, []string{`[]`, `[1, "a"]`, `[1, 2, 3]`}},
+		{"prefixItems", `{"prefixItems": [{"type": "string"}], "items": false}`, []string{`["a"]`}, []string{`[1]`, `["a", 1]`}},
+		{"draft-07 tuples", `{"items": [{"type": "string"}], "additionalItems": false}`, []string{`["a"]`}, []string{`[1]`, `["a", 1]`}},
+		{"contains", `{"contains": {"const": 1}, "maxContains": 1}`, []string{`[1, 2]`}, []string{`[2]`, `[1, 1]`}},
+		{"uniqueItems", `{"uniqueItems": true}`, []string{`[1, 2, {"a": 1}]`}, []string{`[1, 1.0]`, `[{"a": 1}, {"a": 1}]`}},
+		{"string", `{"minLength": 2, "maxLength": 3, "pattern": "^a"}`, []string{`"ab"`, `"aéé"`}, []string{`"a"`, `"abcd"`, `"ba"`}},
+		{"format", `{"format": "uuid"}`, []string{`"123e4567-e89b-12d3-a456-426614174000"`, `1`}, []string{`"nope"`}},
+		{"date-time", `{"format": "date-time"}`, []string{`"2024-01-02T03:04:05Z"`, `"2024-01-02t03:04:05.123+01:00"`}, []string{`"2024-01-02"`}},
+		{"unknown format", `{"format": "custom"}`, []string{`"anything"`}, nil},
+		{"range", `{"minimum": 1, "exclusiveMaximum": 3}`, []string{`1`, `2.5`}, []string{`0.5`, `3`}},
+		{"draft-04 exclusive", `{"maximum": 3, "exclusiveMaximum": true}`, []string{`2`}, []string{`3`}},
+		{"multipleOf", `{"multipleOf": 0.1}`, []string{`0.3`, `2`}, []string{`0.35`}},
+		{"allOf", `{"allOf": [{"minimum": 1}, {"maximum": 2}]}`, []string{`1.5`}, []string{`3`}},
+		{"anyOf", `{"anyOf": [{"type": "string"}, {"minimum": 2}]}`, []string{`"a"`, `3`}, []string{`1`}},
+		{"oneOf", `{"oneOf": [{"type": "integer"}, {"minimum": 2}]}`, []string{`1`, `2.5`}, []string{`3`, `1.5`}},
+		{"not", `{"not": {"type": "string"}}`, []string{`1`}, []string{`"a"`}},
+		{"if", `{"if": {"minimum": 10}, "then": {"multipleOf": 10}, "else": {"maximum": 5}}`, []string{`20`, `3`}, []string{`15`, `7`}},
+		{
+			"$ref",
+			`{"$defs": {"positive": {"exclusiveMinimum": 0}}, "properties": {"a": {"$ref": "#/$defs/positive"}}}`,
+			[]string{`{"a": 1}`},
+			[]string{`{"a": 0}`},
+		},
+		{
+			"recursive $ref",
+			`{"type": "object", "properties": {"name": {"type": "string"}, "children": {"type": "array", "items": {"$ref": "#"}}}}`,
+			[]string{`{"name": "a", "children": [{"name": "b", "children": []}]}`},
+			[]string{`{"name": "a", "children": [{"name": 1}]}`},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := Compile(decode(t, tc.schema))
+			require.NoError(t, err)
+			for _, v := range tc.valid {
+				assert.Empty(t, s.Validate(decode(t, v)), v)
+				assert.True(t, s.IsValid(decode(t, v)), v)
+			}
+			for _, v := range tc.invalid {
+				assert.NotEmpty(t, s.Validate(decode(t, v)), v)
+				assert.False(t, s.IsValid(decode(t, v)), v)
+			}
+		})
+	}
+}
+
+func TestSchemaErrors(t *testing.T) {
+	t.Parallel()
+
+	s, err := Compile(decode(t, `{
+		"type": "object",
+		"required": ["id", "tags"],
+		"properties": {
+			"id": {"type": "integer"},
+			"items": {"type": "array", "items": {"properties": {"name/full": {"type": "string"}}}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	errs := s.Validate(decode(t, `{"id": "1", "items": [{"name/full": "a"}, {"name/full": 2}]}`))
+	assert.Equal(t, []ValidationError{
+		{InstancePath: "", Keyword: "required", Message: `missing required property "tags"`},
+		{InstancePath: "/id", Keyword: "type", Message: "expected integer, but it's string"},
+		{InstancePath: "/items/1/name~1full", Keyword: "type", Message: "expected string, but it's integer"},
+	}, errs)
+}
+
+func TestCompileErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`1`:                                                  "#: a schema must be an object or a boolean, but it's integer",
+		`{"type": "int"}`:                                    `#/type has an unknown type "int"`,
+		`{"properties": {"a": 1}}`:                           "#/properties/a: a schema must be an object or a boolean",
+		`{"pattern": "("}`:                                   "#/pattern: error parsing regexp",
+		`{"minLength": -1}`:                                  "#/minLength must be a non-negative integer",
+		`{"required": [1]}`:                                  "#/required must be an array of strings",
+		`{"$ref": "#/$defs/missing"}`:                        `#/$ref: the reference "#/$defs/missing" doesn't exist`,
+		`{"$ref": "https://example.com/s"}`:                  "only the references within the schema are supported",
+		`{"multipleOf": 0}`:                                  "#/multipleOf must be greater than 0",
+		`{"allOf": {"type": "string"}}`:                      "#/allOf must be an array",
+		`{"items": {"minimum": "1"}}`:                        "#/items/minimum must be a number",
+		`{"dependentRequired": {"a": "b"}}`:                  "#/dependentRequired/a must be an array of strings",
+		`{"exclusiveMinimum": "1"}`:                          "#/exclusiveMinimum must be a number",
+		`{"$defs": {"a": {"type": 1}}, "$ref": "#/$defs/a"}`: "#/$defs/a/type must be a string or an array of strings",
+		`{"unevaluatedProperties": false}`:                   "#/unevaluatedProperties: the keyword isn't supported",
+		`{"items": {"unevaluatedItems": false}}`:             "#/items/unevaluatedItems: the keyword isn't supported",
+		`{"dependentSchemas": {"a": {"required": ["b"]}}}`:   "#/dependentSchemas: the keyword isn't supported",
+		`{"properties": {"a": {"$anchor": "a"}}}`:            "#/properties/a/$anchor: the keyword isn't supported",
+		`{"$dynamicRef": "#node"}`:                           "#/$dynamicRef: the keyword isn't supported",
+	}
+	for schema, want := range tests {
+		_, err := Compile(decode(t, schema))
+		assert.ErrorContains(t, err, want, schema)
+	}
+}
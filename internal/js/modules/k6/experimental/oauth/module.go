@@ -0,0 +1,296 @@
+// Package oauth provides a token manager that requests OAuth 2.0 access tokens
+// with the client credentials or refresh token grants, caches them per VU or
+// across VUs and refreshes them before they expire.
+package oauth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/lib/types"
+)
+
+type (
+	// RootModule is the global module instance that will create instances of our
+	// module for each VU. It holds the token sources shared by the VUs.
+	RootModule struct {
+		mu      sync.Mutex
+		sources map[string]*tokenSource
+	}
+
+	// ModuleInstance represents an instance of the oauth module for a single VU.
+	ModuleInstance struct {
+		vu   modules.VU
+		root *RootModule
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+const (
+	defaultRefreshBefore = 30 * time.Second
+	defaultTimeout       = time.Minute
+)
+
+// New returns a pointer to a new [RootModule] instance.
+func New() *RootModule {
+	return &RootModule{sources: make(map[string]*tokenSource)}
+}
+
+// NewModuleInstance implements the modules.Module interface and returns a new
+// instance of our module for the given VU.
+func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	return &ModuleInstance{vu: vu, root: rm}
+}
+
+// sharedSource returns the token source shared by the VUs for the config,
+// creating it the first time.
+func (rm *RootModule) sharedSource(config Config) *tokenSource {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	key := config.key()
+	source, ok := rm.sources[key]
+	if !ok {
+		source = newTokenSource(config)
+		rm.sources[key] = source
+	}
+	return source
+}
+
+// Exports implements the modules.Module interface and returns the exports of
+// our module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"TokenManager": mi.NewTokenManager,
+		},
+	}
+}
+
+func parseConfig(rt *sobek.Runtime, v sobek.Value) (Config, error) {
+	config := Config{
+		GrantType:     grantClientCredentials,
+		ClientAuth:    clientAuthBasic,
+		RefreshBefore: defaultRefreshBefore,
+		Timeout:       defaultTimeout,
+	}
+	if common.IsNullish(v) {
+		return config, errors.New("the token manager requires options with the URL of the token endpoint")
+	}
+	var raw struct {
+		TokenURL      string `js:"tokenURL"`
+		GrantType     string `js:"grantType"`
+		ClientID      string `js:"clientId"`
+		ClientSecret  string `js:"clientSecret"`
+		ClientAuth    string `js:"clientAuth"`
+		Scope         any    `js:"scope"`
+		Audience      string `js:"audience"`
+		RefreshToken  string `js:"refreshToken"`
+		RefreshBefore any    `js:"refreshBefore"`
+		Timeout       any    `js:"timeout"`
+		Shared        bool   `js:"shared"`
+	}
+	if err := rt.ExportTo(v, &raw); err != nil {
+		return config, err
+	}
+
+	if raw.TokenURL == "" {
+		return config, errors.New("the token manager requires the tokenURL option")
+	}
+	config.TokenURL = raw.TokenURL
+	switch raw.GrantType {
+	case "":
+	case grantClientCredentials, grantRefreshToken:
+		config.GrantType = raw.GrantType
+	default:
+		return config, fmt.Errorf("invalid grantType option %q, it must be %s or %s",
+			raw.GrantType, grantClientCredentials, grantRefreshToken)
+	}
+	switch raw.ClientAuth {
+	case "":
+	case clientAuthBasic, clientAuthPost:
+		config.ClientAuth = raw.ClientAuth
+	default:
+		return config, fmt.Errorf("invalid clientAuth option %q, it must be %s or %s",
+			raw.ClientAuth, clientAuthBasic, clientAuthPost)
+	}
+	config.ClientID, config.ClientSecret = raw.ClientID, raw.ClientSecret
+	if config.GrantType == grantClientCredentials && config.ClientID == "" {
+		return config, errors.New("the client_credentials grant requires the clientId option")
+	}
+	config.RefreshToken = raw.RefreshToken
+	if config.GrantType == grantRefreshToken && config.RefreshToken == "" {
+		return config, errors.New("the refresh_token grant requires the refreshToken option")
+	}
+	switch scope := raw.Scope.(type) {
+	case nil:
+	case string:
+		config.Scope = scope
+	case []any:
+		scopes := make([]string, len(scope))
+		for i, s := range scope {
+			scopes[i] = fmt.Sprint(s)
+		}
+		config.Scope = strings.Join(scopes, " ")
+	default:
+		return config, errors.New("invalid scope option, it must be a string or an array of strings")
+	}
+	config.Audience = raw.Audience
+	if raw.RefreshBefore != nil {
+		refreshBefore, err := types.GetDurationValue(raw.RefreshBefore)
+		if err != nil {
+			return config, fmt.Errorf("invalid refreshBefore option: %w", err)
+		}
+		if refreshBefore < 0 {
+			return config, errors.New("invalid refreshBefore option: it can't be negative")
+		}
+		config.RefreshBefore = refreshBefore
+	}
+	if raw.Timeout != nil {
+		timeout, err := types.GetDurationValue(raw.Timeout)
+		if err != nil {
+			return config, fmt.Errorf("invalid timeout option: %w", err)
+		}
+		config.Timeout = timeout
+	}
+	config.Shared = raw.Shared
+	return config, nil
+}
+
+// TokenManager requests the access tokens of a client and attaches them to
+// the params of the requests.
+type TokenManager struct {
+	mi     *ModuleInstance
+	source *tokenSource
+}
+
+// NewTokenManager is the JS constructor of a TokenManager.
+func (mi *ModuleInstance) NewTokenManager(call sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+	config, err := parseConfig(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	m := &TokenManager{mi: mi}
+	if config.Shared {
+		m.source = mi.root.sharedSource(config)
+	} else {
+		m.source = newTokenSource(config)
+	}
+
+	obj := rt.NewObject()
+	for name, method := range map[string]any{
+		"token":      m.Token,
+		"authorize":  m.Authorize,
+		"invalidate": m.Invalidate,
+	} {
+		if err := obj.Set(name, method); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+	return obj
+}
+
+// Token returns a promise that resolves to the current access token.
+func (m *TokenManager) Token() *sobek.Promise {
+	return m.withToken("token()", func(token *Token) (any, error) {
+		return token.AccessToken, nil
+	})
+}
+
+// Authorize returns a promise that resolves to a copy of the params of a
+// request, or to new ones, with the Authorization header of the current
+// access token. It's meant to wrap the params of the k6/http requests.
+func (m *TokenManager) Authorize(params sobek.Value) *sobek.Promise {
+	return m.withToken("authorize()", func(token *Token) (any, error) {
+		return authorizedParams(m.mi.vu.Runtime(), params, token)
+	})
+}
+
+// Invalidate discards the cached access token, so the next one is requested,
+// e.g. after the server rejected it.
+func (m *TokenManager) Invalidate() {
+	m.source.Invalidate()
+}
+
+// withToken returns a promise that resolves to the result of fn with the
+// current access token. The token is requested outside of the event loop,
+// while fn runs on it.
+func (m *TokenManager) withToken(method string, fn func(*Token) (any, error)) *sobek.Promise {
+	vu := m.mi.vu
+	promise, resolve, reject := vu.Runtime().NewPromise()
+
+	state := vu.State()
+	if state == nil {
+		_ = reject(common.NewInitContextError(method + " can't be used in the init context"))
+		return promise
+	}
+
+	ctx := vu.Context()
+	client := &http.Client{Transport: state.Transport}
+	callback := vu.RegisterCallback()
+	go func() {
+		token, err := m.source.Token(ctx, client)
+		if err != nil && token != nil {
+			state.Logger.WithError(err).Warn("Couldn't refresh the OAuth token, the current one is used until it expires")
+			err = nil
+		}
+		callback(func() error {
+			if err != nil {
+				return reject(err)
+			}
+			result, err := fn(token)
+			if err != nil {
+				return reject(err)
+			}
+			return resolve(result)
+		})
+	}()
+	return promise
+}
+
+// authorizedParams copies the params of a request, with the Authorization
+// header of the token added to their headers.
+func authorizedParams(rt *sobek.Runtime, params sobek.Value, token *Token) (*sobek.Object, error) {
+	result := rt.NewObject()
+	headers := rt.NewObject()
+	if !common.IsNullish(params) {
+		obj := params.ToObject(rt)
+		for _, key := range obj.Keys() {
+			if err := result.Set(key, obj.Get(key)); err != nil {
+				return nil, err
+			}
+		}
+		if h := obj.Get("headers"); !common.IsNullish(h) {
+			hobj := h.ToObject(rt)
+			for _, key := range hobj.Keys() {
+				// the header of the token replaces any other, whatever its case
+				if strings.EqualFold(key, "Authorization") {
+					continue
+				}
+				if err := headers.Set(key, hobj.Get(key)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if err := headers.Set("Authorization", token.TokenType+" "+token.AccessToken); err != nil {
+		return nil, err
+	}
+	if err := result.Set("headers", headers); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
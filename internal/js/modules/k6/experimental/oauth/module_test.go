@@ -0,0 +1,131 @@
+package oauth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+func newTestRuntime(t *testing.T, root *RootModule, tokenURL string) *modulestest.Runtime {
+	t.Helper()
+
+	rt := modulestest.NewRuntime(t)
+	m, ok := root.NewModuleInstance(rt.VU).(*ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, rt.VU.Runtime().Set("oauth", m.Exports().Named))
+	require.NoError(t, rt.VU.Runtime().Set("tokenURL", tokenURL))
+	return rt
+}
+
+func moveToVUContext(rt *modulestest.Runtime, transport http.RoundTripper) {
+	registry := metrics.NewRegistry()
+	rt.MoveToVUContext(&lib.State{
+		Transport: transport,
+		Logger:    logrus.New(),
+		Samples:   make(chan metrics.SampleContainer, 10),
+		Tags:      lib.NewVUStateTags(registry.RootTagSet()),
+	})
+}
+
+func TestTokenManager(t *testing.T) {
+	t.Parallel()
+
+	server := newTokenServer(t, 3600)
+	rt := newTestRuntime(t, New(), server.URL)
+	_, err := rt.VU.Runtime().RunString(`
+		var tokens = new oauth.TokenManager({
+			tokenURL: tokenURL,
+			clientId: "client",
+			clientSecret: "secret",
+			scope: ["read", "write"],
+		});
+	`)
+	require.NoError(t, err)
+	moveToVUContext(rt, server.Client().Transport)
+
+	_, err = rt.RunOnEventLoop(`(async () => {
+		const token = await tokens.token();
+		if (token !== "token-1") {
+			throw new Error("unexpected token " + token);
+		}
+		const params = await tokens.authorize({ tags: { name: "api" }, headers: { "X-Test": "yes", authorization: "old" } });
+		if (params.headers.Authorization !== "Bearer token-1" || params.headers["X-Test"] !== "yes" ||
+			params.headers.authorization !== undefined || params.tags.name !== "api") {
+			throw new Error("unexpected params " + JSON.stringify(params));
+		}
+		const empty = await tokens.authorize();
+		if (empty.headers.Authorization !== "Bearer token-1") {
+			throw new Error("unexpected params " + JSON.stringify(empty));
+		}
+		tokens.invalidate();
+		if (await tokens.token() !== "token-2") {
+			throw new Error("the token wasn't requested again");
+		}
+	})()`)
+	require.NoError(t, err)
+
+	requests := server.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "read write", requests[0]["scope"])
+}
+
+func TestTokenManagerShared(t *testing.T) {
+	t.Parallel()
+
+	server := newTokenServer(t, 3600)
+	root := New()
+	script := `(async () => {
+		const tokens = new oauth.TokenManager({ tokenURL: tokenURL, clientId: "client", shared: SHARED });
+		return await tokens.token();
+	})()`
+	for _, shared := range []string{"true", "true", "false"} {
+		rt := newTestRuntime(t, root, server.URL)
+		require.NoError(t, rt.VU.Runtime().Set("SHARED", shared == "true"))
+		moveToVUContext(rt, server.Client().Transport)
+		_, err := rt.RunOnEventLoop(script)
+		require.NoError(t, err)
+	}
+	// the second VU used the token of the first one
+	assert.Len(t, server.Requests(), 2)
+}
+
+func TestTokenManagerErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`new oauth.TokenManager()`:                                                            "requires options",
+		`new oauth.TokenManager({ clientId: "c" })`:                                           "requires the tokenURL option",
+		`new oauth.TokenManager({ tokenURL: tokenURL })`:                                      "requires the clientId option",
+		`new oauth.TokenManager({ tokenURL: tokenURL, grantType: "refresh_token" })`:          "requires the refreshToken option",
+		`new oauth.TokenManager({ tokenURL: tokenURL, clientId: "c", grantType: "code" })`:    `invalid grantType option "code"`,
+		`new oauth.TokenManager({ tokenURL: tokenURL, clientId: "c", clientAuth: "jwt" })`:    `invalid clientAuth option "jwt"`,
+		`new oauth.TokenManager({ tokenURL: tokenURL, clientId: "c", refreshBefore: "-1s" })`: "it can't be negative",
+		`new oauth.TokenManager({ tokenURL: tokenURL, clientId: "c", scope: 1 })`:             "invalid scope option",
+	}
+	for script, expected := range tests {
+		t.Run(script, func(t *testing.T) {
+			t.Parallel()
+
+			rt := newTestRuntime(t, New(), "http://127.0.0.1:1")
+			_, err := rt.VU.Runtime().RunString(script)
+			require.ErrorContains(t, err, expected)
+		})
+	}
+
+	t.Run("init context", func(t *testing.T) {
+		t.Parallel()
+
+		rt := newTestRuntime(t, New(), "http://127.0.0.1:1")
+		_, err := rt.RunOnEventLoop(`
+			new oauth.TokenManager({ tokenURL: tokenURL, clientId: "c" }).token()
+		`)
+		require.ErrorContains(t, err, "token() can't be used in the init context")
+	})
+}
@@ -0,0 +1,233 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The grant types of the token requests.
+const (
+	grantClientCredentials = "client_credentials"
+	grantRefreshToken      = "refresh_token"
+)
+
+// The ways the client authenticates to the token endpoint.
+const (
+	clientAuthBasic = "basic"
+	clientAuthPost  = "post"
+)
+
+// maxTokenResponseSize limits the responses of the token endpoint that are read.
+const maxTokenResponseSize = 1 << 20
+
+// Config is the configuration of a token manager.
+type Config struct {
+	TokenURL     string
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	// ClientAuth is whether the client credentials are sent with basic
+	// authentication or in the body of the token requests.
+	ClientAuth   string
+	Scope        string
+	Audience     string
+	RefreshToken string
+	// RefreshBefore is how long before the expiry of a token it's refreshed,
+	// at most half of its lifetime.
+	RefreshBefore time.Duration
+	Timeout       time.Duration
+	// Shared is whether all the VUs use the same tokens.
+	Shared bool
+}
+
+// key identifies the shared token sources.
+func (c Config) key() string {
+	return strings.Join([]string{
+		c.TokenURL, c.GrantType, c.ClientID, c.ClientSecret, c.ClientAuth, c.Scope, c.Audience, c.RefreshToken,
+	}, "\x00")
+}
+
+// Token is an access token, with the time when it's refreshed.
+type Token struct {
+	AccessToken string
+	TokenType   string
+	// RefreshAt is when the token is proactively refreshed, it's zero if the
+	// token doesn't expire.
+	RefreshAt time.Time
+	ExpiresAt time.Time
+}
+
+// valid returns whether the token can still be used at now.
+func (t *Token) valid(now time.Time) bool {
+	return t != nil && (t.ExpiresAt.IsZero() || now.Before(t.ExpiresAt))
+}
+
+// fresh returns whether the token doesn't have to be refreshed at now.
+func (t *Token) fresh(now time.Time) bool {
+	return t != nil && (t.RefreshAt.IsZero() || now.Before(t.RefreshAt))
+}
+
+// TokenError is the error response of the token endpoint.
+type TokenError struct {
+	Status      int
+	Code        string
+	Description string
+}
+
+func (e *TokenError) Error() string {
+	msg := fmt.Sprintf("the token request failed with status %d", e.Status)
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+	if e.Description != "" {
+		msg += " (" + e.Description + ")"
+	}
+	return msg
+}
+
+// tokenSource requests the tokens and caches them until they have to be
+// refreshed. A single token request is made at a time, the concurrent callers
+// wait for its token instead of requesting their own.
+type tokenSource struct {
+	config Config
+	now    func() time.Time
+
+	mu           sync.Mutex
+	token        *Token
+	refreshToken string
+}
+
+func newTokenSource(config Config) *tokenSource {
+	return &tokenSource{config: config, now: time.Now, refreshToken: config.RefreshToken}
+}
+
+// Token returns the cached token, or requests a new one if it's missing or
+// about to expire. If refreshing a token that is still valid fails, it's
+// returned with the error, so the callers can keep using it.
+func (s *tokenSource) Token(ctx context.Context, client *http.Client) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.fresh(s.now()) {
+		return s.token, nil
+	}
+	token, err := s.request(ctx, client)
+	if err != nil {
+		if s.token.valid(s.now()) {
+			return s.token, err
+		}
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// Invalidate discards the cached token, e.g. after it was rejected, so the
+// next one is requested.
+func (s *tokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = nil
+}
+
+// request requests a token, with the refresh token if there is one, falling
+// back to the client credentials if the refresh token is rejected.
+func (s *tokenSource) request(ctx context.Context, client *http.Client) (*Token, error) {
+	if s.refreshToken != "" {
+		form := url.Values{"grant_type": {grantRefreshToken}, "refresh_token": {s.refreshToken}}
+		token, err := s.post(ctx, client, form)
+		var tokenErr *TokenError
+		if err == nil || s.config.GrantType != grantClientCredentials || !errors.As(err, &tokenErr) {
+			return token, err
+		}
+		s.refreshToken = ""
+	}
+	if s.config.GrantType != grantClientCredentials {
+		return nil, errors.New("there is no refresh token to request a token with")
+	}
+	return s.post(ctx, client, url.Values{"grant_type": {grantClientCredentials}})
+}
+
+func (s *tokenSource) post(ctx context.Context, client *http.Client, form url.Values) (*Token, error) {
+	if s.config.Scope != "" {
+		form.Set("scope", s.config.Scope)
+	}
+	if s.config.Audience != "" {
+		form.Set("audience", s.config.Audience)
+	}
+	if s.config.ClientAuth == clientAuthPost {
+		form.Set("client_id", s.config.ClientID)
+		if s.config.ClientSecret != "" {
+			form.Set("client_secret", s.config.ClientSecret)
+		}
+	}
+
+	if s.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.config.ClientAuth != clientAuthPost {
+		req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+	}
+
+	start := s.now()
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxTokenResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		AccessToken      string `json:"access_token"`
+		TokenType        string `json:"token_type"`
+		ExpiresIn        int64  `json:"expires_in"`
+		RefreshToken     string `json:"refresh_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	jsonErr := json.Unmarshal(body, &raw)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, &TokenError{Status: res.StatusCode, Code: raw.Error, Description: raw.ErrorDescription}
+	}
+	if jsonErr != nil {
+		return nil, fmt.Errorf("invalid token response: %w", jsonErr)
+	}
+	if raw.AccessToken == "" {
+		return nil, errors.New("invalid token response: it has no access_token")
+	}
+
+	if raw.RefreshToken != "" {
+		s.refreshToken = raw.RefreshToken
+	}
+	token := &Token{AccessToken: raw.AccessToken, TokenType: raw.TokenType}
+	if token.TokenType == "" || strings.EqualFold(token.TokenType, "bearer") {
+		token.TokenType = "Bearer"
+	}
+	if raw.ExpiresIn > 0 {
+		// the lifetime is counted from the request, since the response might
+		// take a while to arrive
+		lifetime := time.Duration(raw.ExpiresIn) * time.Second
+		token.ExpiresAt = start.Add(lifetime)
+		token.RefreshAt = token.ExpiresAt.Add(-min(s.config.RefreshBefore, lifetime/2))
+	}
+	return token, nil
+}
@@ -0,0 +1,186 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenServer is a token endpoint that issues numbered tokens, which expire
+// after expiresIn seconds, and records the forms of the requests.
+type tokenServer struct {
+	*httptest.Server
+	expiresIn int
+
+	mu       sync.Mutex
+	requests []map[string]string
+	issued   atomic.Int64
+	fail     atomic.Bool
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	t.Helper()
+
+	s := &tokenServer{expiresIn: expiresIn}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req := map[string]string{}
+		for key := range r.PostForm {
+			req[key] = r.PostForm.Get(key)
+		}
+		if user, pass, ok := r.BasicAuth(); ok {
+			req["basic"] = user + ":" + pass
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if s.fail.Load() || req["refresh_token"] == "revoked" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"the grant was revoked"}`))
+			return
+		}
+		n := s.issued.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "token-" + string(rune('0'+n)),
+			"token_type":    "bearer",
+			"expires_in":    s.expiresIn,
+			"refresh_token": "refresh-" + string(rune('0'+n)),
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *tokenServer) Requests() []map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]string{}, s.requests...)
+}
+
+func TestTokenSourceRefresh(t *testing.T) {
+	t.Parallel()
+
+	server := newTokenServer(t, 100)
+	source := newTokenSource(Config{
+		TokenURL:      server.URL,
+		GrantType:     grantClientCredentials,
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		ClientAuth:    clientAuthBasic,
+		Scope:         "read write",
+		RefreshBefore: 10 * time.Second,
+	})
+	now := time.Now()
+	source.now = func() time.Time { return now }
+
+	token, err := source.Token(context.Background(), server.Client())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.AccessToken)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, now.Add(90*time.Second), token.RefreshAt)
+
+	// the cached token is used until it has to be refreshed
+	now = now.Add(89 * time.Second)
+	token, err = source.Token(context.Background(), server.Client())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.AccessToken)
+
+	now = now.Add(time.Second)
+	token, err = source.Token(context.Background(), server.Client())
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token.AccessToken)
+
+	// a failed refresh keeps the token that is still valid
+	server.fail.Store(true)
+	now = now.Add(95 * time.Second)
+	token, err = source.Token(context.Background(), server.Client())
+	require.ErrorContains(t, err, "failed with status 400: invalid_grant (the grant was revoked)")
+	assert.Equal(t, "token-2", token.AccessToken)
+
+	now = now.Add(10 * time.Second)
+	token, err = source.Token(context.Background(), server.Client())
+	require.Error(t, err)
+	assert.Nil(t, token)
+
+	// the rejected refresh token is dropped for the client credentials
+	requests := server.Requests()
+	require.Len(t, requests, 5)
+	assert.Equal(t, map[string]string{
+		"grant_type": "client_credentials", "scope": "read write", "basic": "client:secret",
+	}, requests[0])
+	// the returned refresh token is used for the next tokens
+	assert.Equal(t, map[string]string{
+		"grant_type": "refresh_token", "refresh_token": "refresh-1", "scope": "read write", "basic": "client:secret",
+	}, requests[1])
+	assert.Equal(t, "client_credentials", requests[3]["grant_type"])
+	assert.Equal(t, "client_credentials", requests[4]["grant_type"])
+}
+
+func TestTokenSourceRevokedRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	server := newTokenServer(t, 0)
+	config := Config{
+		TokenURL:     server.URL,
+		GrantType:    grantClientCredentials,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		ClientAuth:   clientAuthPost,
+		RefreshToken: "revoked",
+	}
+
+	// the client credentials are used when the refresh token is rejected
+	token, err := newTokenSource(config).Token(context.Background(), server.Client())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token.AccessToken)
+	assert.True(t, token.RefreshAt.IsZero())
+
+	requests := server.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, map[string]string{
+		"grant_type": "client_credentials", "client_id": "client", "client_secret": "secret",
+	}, requests[1])
+
+	// but not with the refresh_token grant
+	config.GrantType = grantRefreshToken
+	_, err = newTokenSource(config).Token(context.Background(), server.Client())
+	require.ErrorContains(t, err, "invalid_grant")
+}
+
+func TestTokenSourceConcurrent(t *testing.T) {
+	t.Parallel()
+
+	server := newTokenServer(t, 3600)
+	source := newTokenSource(Config{TokenURL: server.URL, GrantType: grantClientCredentials, ClientID: "client"})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := source.Token(context.Background(), server.Client())
+			assert.NoError(t, err)
+			assert.Equal(t, "token-1", token.AccessToken)
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, server.Requests(), 1)
+
+	source.Invalidate()
+	token, err := source.Token(context.Background(), server.Client())
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token.AccessToken)
+}
@@ -0,0 +1,327 @@
+package smtp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec // CRAM-MD5 is defined with MD5
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"go.k6.io/k6/lib"
+)
+
+// The TLS modes of the connections.
+const (
+	tlsNone     = "none"
+	tlsStartTLS = "starttls"
+	tlsImplicit = "implicit"
+)
+
+// The supported authentication mechanisms.
+const (
+	authPlain   = "PLAIN"
+	authLogin   = "LOGIN"
+	authCRAMMD5 = "CRAM-MD5"
+)
+
+// Auth are the credentials with which the client authenticates.
+type Auth struct {
+	Username  string `js:"username"`
+	Password  string `js:"password"`
+	Mechanism string `js:"mechanism"`
+}
+
+// conn is a connection to an SMTP server, after the greeting, the TLS
+// negotiation and the authentication. It isn't safe for concurrent use.
+type conn struct {
+	netConn net.Conn
+	text    *textproto.Conn
+	// extensions are the extensions that the server supports, with their
+	// parameters, as advertised in its reply to EHLO.
+	extensions map[string]string
+	timeout    time.Duration
+}
+
+// dialConn connects to the server of the options and gets the connection
+// ready to send messages.
+func dialConn(ctx context.Context, state *lib.State, opts clientOptions) (*conn, error) {
+	netConn, err := state.Dialer.DialContext(ctx, "tcp", opts.Host)
+	if err != nil {
+		return nil, err
+	}
+	c := &conn{netConn: netConn, timeout: opts.Timeout}
+	if err := c.handshake(ctx, state, opts); err != nil {
+		_ = c.netConn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *conn) handshake(ctx context.Context, state *lib.State, opts clientOptions) error {
+	serverName, _, err := net.SplitHostPort(opts.Host)
+	if err != nil {
+		return err
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12} //nolint:gosec // the state's config applies the options
+	if state.TLSConfig != nil {
+		tlsConfig = state.TLSConfig.Clone()
+	}
+	tlsConfig.ServerName = serverName
+
+	if opts.TLS == tlsImplicit {
+		if err := c.startTLS(ctx, tlsConfig); err != nil {
+			return err
+		}
+	} else {
+		c.text = textproto.NewConn(c.netConn)
+	}
+
+	c.extendDeadline()
+	if _, _, err := c.text.ReadResponse(220); err != nil {
+		return err
+	}
+	if err := c.hello(opts.HeloName); err != nil {
+		return err
+	}
+
+	if opts.TLS == tlsStartTLS {
+		if _, ok := c.extensions["STARTTLS"]; !ok {
+			return errors.New("the server doesn't support STARTTLS")
+		}
+		if _, _, err := c.cmd(220, "STARTTLS"); err != nil {
+			return err
+		}
+		if err := c.startTLS(ctx, tlsConfig); err != nil {
+			return err
+		}
+		if err := c.hello(opts.HeloName); err != nil {
+			return err
+		}
+	}
+
+	if opts.Auth != nil {
+		return c.auth(*opts.Auth)
+	}
+	return nil
+}
+
+func (c *conn) startTLS(ctx context.Context, config *tls.Config) error {
+	tlsConn := tls.Client(c.netConn, config)
+	c.extendDeadline()
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return err
+	}
+	c.netConn = tlsConn
+	c.text = textproto.NewConn(tlsConn)
+	return nil
+}
+
+// hello sends EHLO and records the extensions that the server supports.
+func (c *conn) hello(name string) error {
+	_, msg, err := c.cmd(250, "EHLO %s", name)
+	if err != nil {
+		return err
+	}
+	c.extensions = make(map[string]string)
+	lines := strings.Split(msg, "\n")
+	for _, line := range lines[1:] {
+		keyword, params, _ := strings.Cut(line, " ")
+		c.extensions[strings.ToUpper(keyword)] = params
+	}
+	return nil
+}
+
+func (c *conn) auth(auth Auth) error {
+	mechanism := strings.ToUpper(auth.Mechanism)
+	if mechanism == "" {
+		mechanism = authPlain
+	}
+	if !strings.Contains(" "+strings.ToUpper(c.extensions["AUTH"])+" ", " "+mechanism+" ") {
+		return fmt.Errorf("the server doesn't support the %s authentication", mechanism)
+	}
+
+	encode := base64.StdEncoding.EncodeToString
+	switch mechanism {
+	case authPlain:
+		_, _, err := c.cmd(235, "AUTH PLAIN %s", encode([]byte("\x00"+auth.Username+"\x00"+auth.Password)))
+		return err
+	case authLogin:
+		if _, _, err := c.cmd(334, "AUTH LOGIN"); err != nil {
+			return err
+		}
+		if _, _, err := c.cmd(334, "%s", encode([]byte(auth.Username))); err != nil {
+			return err
+		}
+		_, _, err := c.cmd(235, "%s", encode([]byte(auth.Password)))
+		return err
+	case authCRAMMD5:
+		_, msg, err := c.cmd(334, "AUTH CRAM-MD5")
+		if err != nil {
+			return err
+		}
+		challenge, err := base64.StdEncoding.DecodeString(msg)
+		if err != nil {
+			return fmt.Errorf("invalid CRAM-MD5 challenge: %w", err)
+		}
+		mac := hmac.New(md5.New, []byte(auth.Password))
+		mac.Write(challenge)
+		_, _, err = c.cmd(235, "%s", encode([]byte(auth.Username+" "+hex.EncodeToString(mac.Sum(nil)))))
+		return err
+	default:
+		return fmt.Errorf("unsupported authentication mechanism %q, it must be one of %s, %s or %s",
+			auth.Mechanism, authPlain, authLogin, authCRAMMD5)
+	}
+}
+
+// sendResult is the outcome of a transaction that the server accepted.
+type sendResult struct {
+	code     int
+	message  string
+	rejected []string
+}
+
+// send sends the message to the recipients in a single transaction. The
+// recipients that the server rejects are reported in the result, the message
+// is sent as long as it accepts one of them. With pipelining, the commands
+// before the message are sent at once if the server supports it.
+func (c *conn) send(from string, recipients []string, data []byte, pipelining bool) (sendResult, error) {
+	c.extendDeadline()
+	if _, ok := c.extensions["PIPELINING"]; ok && pipelining {
+		return c.sendPipelined(from, recipients, data)
+	}
+
+	var result sendResult
+	if _, _, err := c.cmd(250, "MAIL FROM:<%s>", from); err != nil {
+		return result, c.abort(err)
+	}
+	for _, rcpt := range recipients {
+		if _, _, err := c.cmd(25, "RCPT TO:<%s>", rcpt); err != nil {
+			if !isReply(err) {
+				return result, err
+			}
+			result.rejected = append(result.rejected, rcpt)
+		}
+	}
+	if len(result.rejected) == len(recipients) {
+		return result, c.abort(errors.New("the server rejected all the recipients"))
+	}
+	if _, _, err := c.cmd(354, "DATA"); err != nil {
+		return result, c.abort(err)
+	}
+	return c.writeData(data, result)
+}
+
+func (c *conn) sendPipelined(from string, recipients []string, data []byte) (sendResult, error) {
+	ids := make([]uint, 0, len(recipients)+2)
+	for _, cmd := range append(append([]string{"MAIL FROM:<" + from + ">"}, rcptCommands(recipients)...), "DATA") {
+		id, err := c.text.Cmd("%s", cmd)
+		if err != nil {
+			return sendResult{}, err
+		}
+		ids = append(ids, id)
+	}
+
+	var result sendResult
+	var failure error
+	for i, id := range ids {
+		expectCode := 25
+		switch i {
+		case 0:
+			expectCode = 250
+		case len(ids) - 1:
+			expectCode = 354
+		}
+		c.text.StartResponse(id)
+		_, _, err := c.text.ReadResponse(expectCode)
+		c.text.EndResponse(id)
+		switch {
+		case err == nil:
+		case !isReply(err):
+			return result, err
+		case i > 0 && i < len(ids)-1:
+			result.rejected = append(result.rejected, recipients[i-1])
+		case failure == nil:
+			failure = err
+		}
+	}
+	if failure == nil && len(result.rejected) == len(recipients) {
+		failure = errors.New("the server rejected all the recipients")
+	}
+	if failure != nil {
+		return result, c.abort(failure)
+	}
+	return c.writeData(data, result)
+}
+
+func (c *conn) writeData(data []byte, result sendResult) (sendResult, error) {
+	w := c.text.DotWriter()
+	if _, err := w.Write(data); err != nil {
+		return result, err
+	}
+	if err := w.Close(); err != nil {
+		return result, err
+	}
+	code, msg, err := c.text.ReadResponse(250)
+	if err != nil {
+		return result, c.abort(err)
+	}
+	result.code, result.message = code, msg
+	return result, nil
+}
+
+// abort resets the transaction after the server rejected a command, so the
+// connection can be used for the next messages.
+func (c *conn) abort(err error) error {
+	if !isReply(err) {
+		return err
+	}
+	if _, _, rsetErr := c.cmd(250, "RSET"); rsetErr != nil && !isReply(rsetErr) {
+		return errors.Join(err, rsetErr)
+	}
+	return err
+}
+
+// close says goodbye to the server and closes the connection.
+func (c *conn) close() error {
+	c.extendDeadline()
+	_, _, err := c.cmd(221, "QUIT")
+	return errors.Join(err, c.netConn.Close())
+}
+
+func (c *conn) cmd(expectCode int, format string, args ...any) (int, string, error) {
+	id, err := c.text.Cmd(format, args...)
+	if err != nil {
+		return 0, "", err
+	}
+	c.text.StartResponse(id)
+	defer c.text.EndResponse(id)
+	return c.text.ReadResponse(expectCode)
+}
+
+func (c *conn) extendDeadline() {
+	if c.timeout > 0 {
+		_ = c.netConn.SetDeadline(time.Now().Add(c.timeout))
+	}
+}
+
+func rcptCommands(recipients []string) []string {
+	cmds := make([]string, len(recipients))
+	for i, rcpt := range recipients {
+		cmds[i] = "RCPT TO:<" + rcpt + ">"
+	}
+	return cmds
+}
+
+// isReply returns whether the error is a reply of the server, after which the
+// connection can still be used.
+func isReply(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr)
+}
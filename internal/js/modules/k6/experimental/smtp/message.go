@@ -0,0 +1,146 @@
+package smtp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Message is a message to send. Unless the raw data of the message is set,
+// it's built from the addresses, the subject, the headers and the text.
+type Message struct {
+	From    string            `js:"from"`
+	To      any               `js:"to"`
+	Cc      any               `js:"cc"`
+	Bcc     any               `js:"bcc"`
+	Subject string            `js:"subject"`
+	Text    string            `js:"text"`
+	Headers map[string]string `js:"headers"`
+	// Data is the raw RFC 5322 message, with its headers.
+	Data string `js:"data"`
+}
+
+// envelope is what a message is sent as.
+type envelope struct {
+	from       string
+	recipients []string
+	data       []byte
+}
+
+// envelope returns the addresses of the sender and the recipients of the
+// message, and its data.
+func (m Message) envelope(now time.Time) (envelope, error) {
+	if m.From == "" {
+		return envelope{}, errors.New("the message requires a from address")
+	}
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return envelope{}, fmt.Errorf("invalid from address %q: %w", m.From, err)
+	}
+
+	var recipients []string
+	headers := make(map[string]string, len(m.Headers)+8)
+	for _, field := range []struct {
+		name  string
+		value any
+	}{{"To", m.To}, {"Cc", m.Cc}, {"Bcc", m.Bcc}} {
+		addresses, err := parseAddresses(field.value)
+		if err != nil {
+			return envelope{}, fmt.Errorf("invalid %s addresses: %w", strings.ToLower(field.name), err)
+		}
+		formatted := make([]string, len(addresses))
+		for i, address := range addresses {
+			recipients = append(recipients, address.Address)
+			formatted[i] = address.String()
+		}
+		if len(addresses) > 0 && field.name != "Bcc" {
+			headers[field.name] = strings.Join(formatted, ", ")
+		}
+	}
+	if len(recipients) == 0 {
+		return envelope{}, errors.New("the message requires at least one recipient")
+	}
+
+	if m.Data != "" {
+		return envelope{from: from.Address, recipients: recipients, data: []byte(m.Data)}, nil
+	}
+
+	headers["From"] = from.String()
+	headers["Subject"] = mime.QEncoding.Encode("utf-8", m.Subject)
+	headers["Date"] = now.Format(time.RFC1123Z)
+	headers["Message-Id"] = messageID(from.Address)
+	headers["Mime-Version"] = "1.0"
+	headers["Content-Type"] = "text/plain; charset=utf-8"
+	headers["Content-Transfer-Encoding"] = "quoted-printable"
+	for name, value := range m.Headers {
+		headers[textproto.CanonicalMIMEHeaderKey(name)] = value
+	}
+
+	var buf bytes.Buffer
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, headers[name])
+	}
+	buf.WriteString("\r\n")
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(m.Text)); err != nil {
+		return envelope{}, err
+	}
+	if err := w.Close(); err != nil {
+		return envelope{}, err
+	}
+	return envelope{from: from.Address, recipients: recipients, data: buf.Bytes()}, nil
+}
+
+// parseAddresses parses an address, a list of addresses separated by commas
+// or an array of addresses.
+func parseAddresses(v any) ([]*mail.Address, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		return mail.ParseAddressList(v)
+	case []any:
+		addresses := make([]*mail.Address, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%v isn't a string", item)
+			}
+			address, err := mail.ParseAddress(s)
+			if err != nil {
+				return nil, err
+			}
+			addresses = append(addresses, address)
+		}
+		return addresses, nil
+	default:
+		return nil, fmt.Errorf("%v isn't a string or an array of strings", v)
+	}
+}
+
+func messageID(from string) string {
+	var id [12]byte
+	_, _ = rand.Read(id[:])
+	domain := "localhost"
+	if _, d, ok := strings.Cut(from, "@"); ok {
+		domain = d
+	}
+	return "<" + hex.EncodeToString(id[:]) + "@" + domain + ">"
+}
@@ -0,0 +1,293 @@
+// Package smtp provides a client to send messages to SMTP servers, with
+// STARTTLS, authentication and pipelining, and metrics of each message.
+package smtp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/js/promises"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
+)
+
+type (
+	// RootModule is the global module instance that will create instances of our
+	// module for each VU.
+	RootModule struct{}
+
+	// ModuleInstance represents an instance of the smtp module for a single VU.
+	ModuleInstance struct {
+		vu modules.VU
+
+		messageDuration *metrics.Metric
+		messageFailed   *metrics.Metric
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// The names of the metrics of the messages.
+const (
+	messageDurationName = "smtp_message_duration"
+	messageFailedName   = "smtp_message_failed"
+)
+
+const defaultTimeout = time.Minute
+
+// New returns a pointer to a new [RootModule] instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface and returns a new
+// instance of our module for the given VU.
+func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	registry := vu.InitEnv().Registry
+	messageDuration, err := registry.NewMetric(messageDurationName, metrics.Trend, metrics.Time)
+	if err != nil {
+		common.Throw(vu.Runtime(), err)
+	}
+	messageFailed, err := registry.NewMetric(messageFailedName, metrics.Rate)
+	if err != nil {
+		common.Throw(vu.Runtime(), err)
+	}
+	return &ModuleInstance{vu: vu, messageDuration: messageDuration, messageFailed: messageFailed}
+}
+
+// Exports implements the modules.Module interface and returns the exports of
+// our module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"Client": mi.NewClient,
+		},
+	}
+}
+
+// clientOptions are the options of a Client.
+type clientOptions struct {
+	// Host is the address of the server, with its port.
+	Host string
+	// TLS is how the connection is secured: none, starttls or implicit.
+	TLS  string
+	Auth *Auth
+	// Pipelining is whether the commands of a message are sent at once when
+	// the server supports it.
+	Pipelining bool
+	HeloName   string
+	Timeout    time.Duration
+}
+
+func parseClientOptions(rt *sobek.Runtime, v sobek.Value) (clientOptions, error) {
+	opts := clientOptions{TLS: tlsNone, Pipelining: true, HeloName: "localhost", Timeout: defaultTimeout}
+	if common.IsNullish(v) {
+		return opts, errors.New("the client requires options with the host of the server")
+	}
+	var raw struct {
+		Host       string `js:"host"`
+		TLS        string `js:"tls"`
+		Auth       *Auth  `js:"auth"`
+		Pipelining *bool  `js:"pipelining"`
+		HeloName   string `js:"heloName"`
+		Timeout    any    `js:"timeout"`
+	}
+	if err := rt.ExportTo(v, &raw); err != nil {
+		return opts, err
+	}
+
+	if raw.Host == "" {
+		return opts, errors.New("the client requires the host of the server")
+	}
+	opts.Host = raw.Host
+	switch tlsMode := strings.ToLower(raw.TLS); tlsMode {
+	case "":
+	case tlsNone, tlsStartTLS, tlsImplicit:
+		opts.TLS = tlsMode
+	default:
+		return opts, fmt.Errorf("invalid tls option %q, it must be one of %s, %s or %s",
+			raw.TLS, tlsNone, tlsStartTLS, tlsImplicit)
+	}
+	opts.Auth = raw.Auth
+	if raw.Pipelining != nil {
+		opts.Pipelining = *raw.Pipelining
+	}
+	if raw.HeloName != "" {
+		opts.HeloName = raw.HeloName
+	}
+	if raw.Timeout != nil {
+		timeout, err := types.GetDurationValue(raw.Timeout)
+		if err != nil {
+			return opts, fmt.Errorf("invalid timeout option: %w", err)
+		}
+		opts.Timeout = timeout
+	}
+	return opts, nil
+}
+
+// Client sends messages to an SMTP server over a connection that it keeps
+// open between them. It connects on the first message and reconnects after
+// the errors that break the connection.
+type Client struct {
+	mi   *ModuleInstance
+	opts clientOptions
+
+	// mu serializes the use of the connection.
+	mu   sync.Mutex
+	conn *conn
+	// connCtx is the context of the VU that the connection was opened in, it's
+	// closed once it's done.
+	connCtx context.Context //nolint:containedctx
+	stop    func() bool
+}
+
+// NewClient is the JS constructor of a Client.
+func (mi *ModuleInstance) NewClient(call sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+	opts, err := parseClientOptions(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	c := &Client{mi: mi, opts: opts}
+
+	obj := rt.NewObject()
+	for name, method := range map[string]any{
+		"send":  c.Send,
+		"close": c.Close,
+	} {
+		if err := obj.Set(name, method); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+	return obj
+}
+
+// Send sends a message, and returns a promise that resolves to an object
+// with the code and the message of the reply of the server, the recipients
+// that it rejected and the duration of the transaction in milliseconds.
+func (c *Client) Send(message sobek.Value) *sobek.Promise {
+	promise, resolve, reject := promises.New(c.mi.vu)
+
+	state := c.mi.vu.State()
+	if state == nil {
+		reject(common.NewInitContextError("send() can't be used in the init context"))
+		return promise
+	}
+	if common.IsNullish(message) {
+		reject(errors.New("send() requires a message"))
+		return promise
+	}
+	var msg Message
+	if err := c.mi.vu.Runtime().ExportTo(message, &msg); err != nil {
+		reject(err)
+		return promise
+	}
+	env, err := msg.envelope(time.Now())
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	ctx := c.mi.vu.Context()
+	tagsAndMeta := state.Tags.GetCurrentValues()
+	go func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.conn != nil && c.connCtx != ctx {
+			c.disconnect()
+		}
+		if c.conn == nil {
+			conn, err := dialConn(ctx, state, c.opts)
+			if err != nil {
+				reject(err)
+				return
+			}
+			c.conn, c.connCtx = conn, ctx
+			c.stop = context.AfterFunc(ctx, func() { _ = conn.netConn.Close() })
+		}
+
+		start := time.Now()
+		result, err := c.conn.send(env.from, env.recipients, env.data, c.opts.Pipelining)
+		end := time.Now()
+		if err != nil && !isReply(err) {
+			c.disconnect()
+		}
+
+		tags := tagsAndMeta.Tags.With("host", c.opts.Host)
+		samples := []metrics.Sample{{
+			TimeSeries: metrics.TimeSeries{Metric: c.mi.messageFailed, Tags: tags},
+			Time:       end,
+			Metadata:   tagsAndMeta.Metadata,
+			Value:      metrics.B(err != nil),
+		}}
+		if err == nil {
+			samples = append(samples, metrics.Sample{
+				TimeSeries: metrics.TimeSeries{Metric: c.mi.messageDuration, Tags: tags},
+				Time:       end,
+				Metadata:   tagsAndMeta.Metadata,
+				Value:      metrics.D(end.Sub(start)),
+			})
+		}
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.ConnectedSamples{Samples: samples, Time: end})
+
+		if err != nil {
+			reject(err)
+			return
+		}
+		rejected := result.rejected
+		if rejected == nil {
+			rejected = []string{}
+		}
+		resolve(map[string]any{
+			"code":     result.code,
+			"message":  result.message,
+			"rejected": rejected,
+			"duration": metrics.D(end.Sub(start)),
+		})
+	}()
+
+	return promise
+}
+
+// Close says goodbye to the server and closes the connection, if the client
+// is connected. It returns a promise that resolves once it's closed.
+func (c *Client) Close() *sobek.Promise {
+	promise, resolve, reject := promises.New(c.mi.vu)
+	go func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.conn == nil {
+			resolve(nil)
+			return
+		}
+		err := c.conn.close()
+		c.conn, c.connCtx = nil, nil
+		c.stop()
+		if err != nil {
+			reject(err)
+			return
+		}
+		resolve(nil)
+	}()
+	return promise
+}
+
+// disconnect closes the connection after an error, without saying goodbye.
+func (c *Client) disconnect() {
+	_ = c.conn.netConn.Close()
+	c.conn, c.connCtx = nil, nil
+	c.stop()
+}
@@ -0,0 +1,259 @@
+package smtp
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+// fakeServer is an SMTP server that accepts the messages to the recipients
+// that don't start with "nobody", and records the commands and the messages.
+type fakeServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+
+	mu       sync.Mutex
+	commands []string
+	messages []string
+}
+
+func newFakeServer(t *testing.T, tlsConfig *tls.Config) *fakeServer {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeServer{listener: l, tlsConfig: tlsConfig}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	text := textproto.NewConn(conn)
+	reply := func(lines ...string) {
+		for _, line := range lines {
+			_ = text.PrintfLine("%s", line)
+		}
+	}
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, line)
+		s.mu.Unlock()
+
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			lines := []string{"250-fake", "250-PIPELINING", "250-AUTH PLAIN LOGIN"}
+			if s.tlsConfig != nil {
+				lines = append(lines, "250-STARTTLS")
+			}
+			reply(append(lines, "250 8BITMIME")...)
+		case "STARTTLS":
+			reply("220 ready")
+			conn = tls.Server(conn, s.tlsConfig)
+			text = textproto.NewConn(conn)
+		case "AUTH":
+			plain := "PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00user\x00secret"))
+			if arg == plain {
+				reply("235 authenticated")
+			} else {
+				reply("535 invalid credentials")
+			}
+		case "MAIL", "RSET":
+			reply("250 ok")
+		case "RCPT":
+			if strings.HasPrefix(arg, "TO:<nobody") {
+				reply("550 no such user")
+			} else {
+				reply("250 ok")
+			}
+		case "DATA":
+			reply("354 go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			reply("250 queued as 42")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 unknown command")
+		}
+	}
+}
+
+func newTestRuntime(t *testing.T, tlsConfig *tls.Config) (*modulestest.Runtime, chan metrics.SampleContainer) {
+	t.Helper()
+
+	rt := modulestest.NewRuntime(t)
+	m, ok := New().NewModuleInstance(rt.VU).(*ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, rt.VU.Runtime().Set("smtp", m.Exports().Named))
+
+	samples := make(chan metrics.SampleContainer, 10)
+	registry := metrics.NewRegistry()
+	rt.MoveToVUContext(&lib.State{
+		Dialer:    &net.Dialer{},
+		TLSConfig: tlsConfig,
+		Samples:   samples,
+		Tags:      lib.NewVUStateTags(registry.RootTagSet()),
+	})
+	return rt, samples
+}
+
+func TestSend(t *testing.T) {
+	t.Parallel()
+
+	for _, pipelining := range []string{"true", "false"} {
+		t.Run("pipelining="+pipelining, func(t *testing.T) {
+			t.Parallel()
+
+			server := newFakeServer(t, nil)
+			rt, samples := newTestRuntime(t, nil)
+			_, err := rt.RunOnEventLoop(`(async () => {
+				const client = new smtp.Client({
+					host: "` + server.listener.Addr().String() + `",
+					auth: { username: "user", password: "secret" },
+					pipelining: ` + pipelining + `,
+				});
+				const res = await client.send({
+					from: "k6 <k6@example.com>",
+					to: ["a@example.com", "nobody@example.com"],
+					bcc: "hidden@example.com",
+					subject: "Hello",
+					text: "Hi!\n.\nBye",
+					headers: { "x-test": "yes" },
+				});
+				if (res.code !== 250 || res.message !== "queued as 42") {
+					throw new Error("unexpected reply " + res.code + " " + res.message);
+				}
+				if (res.rejected.join() !== "nobody@example.com") {
+					throw new Error("unexpected rejected recipients " + res.rejected.join());
+				}
+				await client.send({ from: "k6@example.com", to: "a@example.com", data: "Subject: raw\r\n\r\nraw" });
+				await client.close();
+			})()`)
+			require.NoError(t, err)
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			require.Len(t, server.messages, 2)
+			msg := server.messages[0]
+			assert.Contains(t, msg, "From: \"k6\" <k6@example.com>\n")
+			assert.Contains(t, msg, "To: <a@example.com>, <nobody@example.com>\n")
+			assert.Contains(t, msg, "Subject: Hello\n")
+			assert.Contains(t, msg, "X-Test: yes\n")
+			assert.Contains(t, msg, "\nHi!\n.\nBye")
+			assert.NotContains(t, msg, "hidden@example.com")
+			assert.Equal(t, "Subject: raw\n\nraw\n", server.messages[1])
+
+			assert.Equal(t, 1, countPrefix(server.commands, "EHLO"), "the connection wasn't reused")
+			assert.Equal(t, 4, countPrefix(server.commands, "RCPT"))
+			assert.Equal(t, "QUIT", server.commands[len(server.commands)-1])
+
+			require.Len(t, samples, 2)
+			sample := (<-samples).GetSamples()
+			require.Len(t, sample, 2)
+			assert.Equal(t, messageFailedName, sample[0].Metric.Name)
+			assert.Equal(t, 0.0, sample[0].Value)
+			assert.Equal(t, messageDurationName, sample[1].Metric.Name)
+			assert.Equal(t, map[string]string{"host": server.listener.Addr().String()}, sample[1].Tags.Map())
+		})
+	}
+}
+
+func TestSendStartTLS(t *testing.T) {
+	t.Parallel()
+
+	// the certificate of the test server of httptest is valid for 127.0.0.1
+	tlsServer := httptest.NewTLSServer(nil)
+	defer tlsServer.Close()
+	transport, ok := tlsServer.Client().Transport.(*http.Transport)
+	require.True(t, ok)
+
+	server := newFakeServer(t, tlsServer.TLS)
+	rt, _ := newTestRuntime(t, transport.TLSClientConfig)
+	_, err := rt.RunOnEventLoop(`(async () => {
+		const client = new smtp.Client({ host: "` + server.listener.Addr().String() + `", tls: "starttls" });
+		await client.send({ from: "k6@example.com", to: "a@example.com", text: "secret" });
+		await client.close();
+	})()`)
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Equal(t, 2, countPrefix(server.commands, "EHLO"))
+	assert.Equal(t, 1, countPrefix(server.commands, "STARTTLS"))
+	require.Len(t, server.messages, 1)
+}
+
+func TestSendErrors(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeServer(t, nil)
+	host := server.listener.Addr().String()
+	tests := map[string]string{
+		`new smtp.Client()`:                                                                                                "the client requires options with the host of the server",
+		`new smtp.Client({ tls: "starttls" })`:                                                                             "the client requires the host of the server",
+		`new smtp.Client({ host: "h:25", tls: "ssl" })`:                                                                    `invalid tls option "ssl"`,
+		`new smtp.Client({ host: "` + host + `" }).send({ to: "a@example.com" })`:                                          "the message requires a from address",
+		`new smtp.Client({ host: "` + host + `" }).send({ from: "k6@example.com" })`:                                       "the message requires at least one recipient",
+		`new smtp.Client({ host: "` + host + `", tls: "starttls" }).send({ from: "k6@example.com", to: "a@example.com" })`: "the server doesn't support STARTTLS",
+		`new smtp.Client({ host: "` + host + `", auth: { username: "user", password: "wrong" } })
+			.send({ from: "k6@example.com", to: "a@example.com" })`: "invalid credentials",
+		`new smtp.Client({ host: "` + host + `", auth: { username: "user", mechanism: "CRAM-MD5" } })
+			.send({ from: "k6@example.com", to: "a@example.com" })`: "the server doesn't support the CRAM-MD5 authentication",
+		`new smtp.Client({ host: "` + host + `" }).send({ from: "k6@example.com", to: "nobody@example.com" })`: "the server rejected all the recipients",
+	}
+	for script, want := range tests {
+		t.Run(script, func(t *testing.T) {
+			t.Parallel()
+
+			rt, _ := newTestRuntime(t, nil)
+			_, err := rt.RunOnEventLoop(script)
+			require.ErrorContains(t, err, want)
+		})
+	}
+}
+
+func countPrefix(lines []string, prefix string) int {
+	n := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,164 @@
+package sqlload
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"go.k6.io/k6/lib"
+)
+
+// The supported drivers.
+const (
+	driverPostgres = "postgres"
+	driverMySQL    = "mysql"
+)
+
+// maxMessageSize limits the size of the messages of the servers.
+const maxMessageSize = 1 << 30
+
+// valueKind is how the values of a column are converted to JS values.
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindNumber
+	kindBool
+)
+
+// column is a column of the rows of a result.
+type column struct {
+	name string
+	kind valueKind
+}
+
+// result is the result of a query, with the values of the rows in the text
+// format of the protocol, nil for NULL.
+type result struct {
+	columns      []column
+	rows         [][][]byte
+	rowsAffected int64
+	lastInsertID int64
+}
+
+// value converts a value of the column to the one returned to the script.
+func (c column) value(v []byte) any {
+	if v == nil {
+		return nil
+	}
+	s := string(v)
+	switch c.kind {
+	case kindNumber:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case kindBool:
+		switch s {
+		case "t", "true", "1":
+			return true
+		case "f", "false", "0":
+			return false
+		}
+	case kindString:
+	}
+	return s
+}
+
+// ServerError is an error returned by the database for a query, after which
+// the connection can still be used.
+type ServerError struct {
+	// Code is the SQLSTATE of Postgres, or the error number of MySQL.
+	Code    string
+	Message string
+}
+
+func (e *ServerError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// protocol is the wire protocol of a driver, on an authenticated connection.
+type protocol interface {
+	query(query string, args []any) (*result, error)
+	// terminate says goodbye to the server, before the connection is closed.
+	terminate() error
+}
+
+// conn is a connection of a pool. It isn't safe for concurrent use.
+type conn struct {
+	netConn  net.Conn
+	proto    protocol
+	openedAt time.Time
+	// ctx is the context of the VU that the connection was opened in, it's
+	// closed once it's done.
+	ctx  context.Context //nolint:containedctx
+	stop func() bool
+}
+
+// dialConn connects to the database of the options and authenticates.
+func dialConn(ctx context.Context, state *lib.State, opts poolOptions) (*conn, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, opts.ConnectTimeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port))
+	netConn, err := state.Dialer.DialContext(dialCtx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	_ = netConn.SetDeadline(time.Now().Add(opts.ConnectTimeout))
+
+	var tlsConfig *tls.Config
+	if opts.TLS {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12} //nolint:gosec // the state's config applies the options
+		if state.TLSConfig != nil {
+			tlsConfig = state.TLSConfig.Clone()
+		}
+		tlsConfig.ServerName = opts.Host
+	}
+
+	c := &conn{netConn: netConn, openedAt: time.Now(), ctx: ctx}
+	switch opts.Driver {
+	case driverPostgres:
+		c.proto, c.netConn, err = startPostgres(dialCtx, netConn, tlsConfig, opts)
+	case driverMySQL:
+		c.proto, c.netConn, err = startMySQL(dialCtx, netConn, tlsConfig, opts)
+	default:
+		err = fmt.Errorf("unsupported driver %q", opts.Driver)
+	}
+	if err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+	_ = c.netConn.SetDeadline(time.Time{})
+	c.stop = context.AfterFunc(ctx, func() { _ = c.netConn.Close() })
+	return c, nil
+}
+
+// query runs a query, which fails if it takes longer than the timeout.
+func (c *conn) query(query string, args []any, timeout time.Duration) (*result, error) {
+	if timeout > 0 {
+		_ = c.netConn.SetDeadline(time.Now().Add(timeout))
+		defer func() { _ = c.netConn.SetDeadline(time.Time{}) }()
+	}
+	return c.proto.query(query, args)
+}
+
+// close says goodbye to the server, if it can, and closes the connection.
+func (c *conn) close(graceful bool) {
+	c.stop()
+	if graceful && c.ctx.Err() == nil {
+		_ = c.netConn.SetDeadline(time.Now().Add(time.Second))
+		_ = c.proto.terminate()
+	}
+	_ = c.netConn.Close()
+}
+
+// usable returns whether the connection can be reused at now.
+func (c *conn) usable(now time.Time, maxLifetime time.Duration) bool {
+	return c.ctx.Err() == nil && (maxLifetime <= 0 || now.Sub(c.openedAt) < maxLifetime)
+}
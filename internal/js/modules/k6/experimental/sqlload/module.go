@@ -0,0 +1,380 @@
+// Package sqlload provides connection pools that speak the Postgres and MySQL
+// wire protocols, to load test databases with parameterized queries, with
+// metrics of the latency, the rows and the errors of each query.
+package sqlload
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
+)
+
+type (
+	// RootModule is the global module instance that will create instances of our
+	// module for each VU.
+	RootModule struct{}
+
+	// ModuleInstance represents an instance of the sql-load module for a single VU.
+	ModuleInstance struct {
+		vu modules.VU
+
+		queryDuration *metrics.Metric
+		queryRows     *metrics.Metric
+		queryFailed   *metrics.Metric
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// The names of the metrics of the queries.
+const (
+	queryDurationName = "sql_query_duration"
+	queryRowsName     = "sql_query_rows"
+	queryFailedName   = "sql_query_failed"
+)
+
+const (
+	defaultMaxConnections = 10
+	defaultConnectTimeout = 10 * time.Second
+	defaultQueryTimeout   = time.Minute
+)
+
+// New returns a pointer to a new [RootModule] instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface and returns a new
+// instance of our module for the given VU.
+func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	registry := vu.InitEnv().Registry
+	mi := &ModuleInstance{vu: vu}
+	for _, m := range []struct {
+		dst       **metrics.Metric
+		name      string
+		typ       metrics.MetricType
+		valueType []metrics.ValueType
+	}{
+		{&mi.queryDuration, queryDurationName, metrics.Trend, []metrics.ValueType{metrics.Time}},
+		{&mi.queryRows, queryRowsName, metrics.Counter, nil},
+		{&mi.queryFailed, queryFailedName, metrics.Rate, nil},
+	} {
+		metric, err := registry.NewMetric(m.name, m.typ, m.valueType...)
+		if err != nil {
+			common.Throw(vu.Runtime(), err)
+		}
+		*m.dst = metric
+	}
+	return mi
+}
+
+// Exports implements the modules.Module interface and returns the exports of
+// our module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"Pool": mi.NewPool,
+		},
+	}
+}
+
+// poolOptions are the options of a Pool.
+type poolOptions struct {
+	Driver   string
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+	// TLS is whether the connections are secured with TLS.
+	TLS bool
+	// MaxConnections is how many connections are open at most, the queries
+	// wait for one when they are all busy.
+	MaxConnections int
+	// MaxIdleConnections is how many connections are kept open between the
+	// queries.
+	MaxIdleConnections int
+	// MaxLifetime is how long a connection is reused, it's unlimited if 0.
+	MaxLifetime    time.Duration
+	ConnectTimeout time.Duration
+	QueryTimeout   time.Duration
+}
+
+//nolint:cyclop
+func parsePoolOptions(rt *sobek.Runtime, v sobek.Value) (poolOptions, error) {
+	opts := poolOptions{
+		Host:           "localhost",
+		MaxConnections: defaultMaxConnections,
+		ConnectTimeout: defaultConnectTimeout,
+		QueryTimeout:   defaultQueryTimeout,
+	}
+	if common.IsNullish(v) {
+		return opts, errors.New("the pool requires options with the driver and the database")
+	}
+	var raw struct {
+		Driver             string `js:"driver"`
+		Host               string `js:"host"`
+		Port               int    `js:"port"`
+		User               string `js:"user"`
+		Password           string `js:"password"`
+		Database           string `js:"database"`
+		TLS                bool   `js:"tls"`
+		MaxConnections     *int   `js:"maxConnections"`
+		MaxIdleConnections *int   `js:"maxIdleConnections"`
+		MaxLifetime        any    `js:"maxLifetime"`
+		ConnectTimeout     any    `js:"connectTimeout"`
+		QueryTimeout       any    `js:"queryTimeout"`
+	}
+	if err := rt.ExportTo(v, &raw); err != nil {
+		return opts, err
+	}
+
+	switch driver := strings.ToLower(raw.Driver); driver {
+	case driverPostgres:
+		opts.Driver, opts.Port = driver, 5432
+	case driverMySQL:
+		opts.Driver, opts.Port = driver, 3306
+	default:
+		return opts, fmt.Errorf("invalid driver option %q, it must be %s or %s", raw.Driver, driverPostgres, driverMySQL)
+	}
+	if raw.Host != "" {
+		opts.Host = raw.Host
+	}
+	if raw.Port < 0 || raw.Port > 65535 {
+		return opts, fmt.Errorf("invalid port option %d", raw.Port)
+	}
+	if raw.Port != 0 {
+		opts.Port = raw.Port
+	}
+	if raw.User == "" {
+		return opts, errors.New("the pool requires the user option")
+	}
+	opts.User, opts.Password, opts.Database, opts.TLS = raw.User, raw.Password, raw.Database, raw.TLS
+
+	if raw.MaxConnections != nil {
+		if *raw.MaxConnections < 1 {
+			return opts, errors.New("invalid maxConnections option: it must be at least 1")
+		}
+		opts.MaxConnections = *raw.MaxConnections
+	}
+	opts.MaxIdleConnections = opts.MaxConnections
+	if raw.MaxIdleConnections != nil {
+		if *raw.MaxIdleConnections < 0 {
+			return opts, errors.New("invalid maxIdleConnections option: it can't be negative")
+		}
+		opts.MaxIdleConnections = min(*raw.MaxIdleConnections, opts.MaxConnections)
+	}
+	for name, d := range map[string]struct {
+		raw any
+		dst *time.Duration
+	}{
+		"maxLifetime":    {raw.MaxLifetime, &opts.MaxLifetime},
+		"connectTimeout": {raw.ConnectTimeout, &opts.ConnectTimeout},
+		"queryTimeout":   {raw.QueryTimeout, &opts.QueryTimeout},
+	} {
+		if d.raw == nil {
+			continue
+		}
+		duration, err := types.GetDurationValue(d.raw)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s option: %w", name, err)
+		}
+		if duration < 0 {
+			return opts, fmt.Errorf("invalid %s option: it can't be negative", name)
+		}
+		*d.dst = duration
+	}
+	if opts.ConnectTimeout == 0 {
+		opts.ConnectTimeout = defaultConnectTimeout
+	}
+	return opts, nil
+}
+
+// NewPool is the JS constructor of a Pool.
+func (mi *ModuleInstance) NewPool(call sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+	opts, err := parsePoolOptions(rt, call.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	p := &Pool{mi: mi, pool: newPool(opts)}
+
+	obj := rt.NewObject()
+	for name, method := range map[string]any{
+		"query": p.Query,
+		"close": p.Close,
+	} {
+		if err := obj.Set(name, method); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+	return obj
+}
+
+// Pool runs the queries of a VU over a pool of connections to the database.
+type Pool struct {
+	mi   *ModuleInstance
+	pool *pool
+}
+
+// queryOptions are the options of a query.
+type queryOptions struct {
+	// Name is the name tag of the metrics of the query, the query itself by
+	// default.
+	Name    string
+	Timeout time.Duration
+}
+
+func (p *Pool) parseQueryOptions(v sobek.Value, query string, tagsAndMeta *metrics.TagsAndMeta) (queryOptions, error) {
+	opts := queryOptions{Name: query, Timeout: p.pool.opts.QueryTimeout}
+	if common.IsNullish(v) {
+		return opts, nil
+	}
+	rt := p.mi.vu.Runtime()
+	obj := v.ToObject(rt)
+	for _, key := range obj.Keys() {
+		value := obj.Get(key)
+		switch key {
+		case "name":
+			opts.Name = value.String()
+		case "timeout":
+			timeout, err := types.GetDurationValue(value.Export())
+			if err != nil {
+				return opts, fmt.Errorf("invalid timeout option: %w", err)
+			}
+			opts.Timeout = timeout
+		case "tags":
+			if err := common.ApplyCustomUserTags(rt, tagsAndMeta, value); err != nil {
+				return opts, fmt.Errorf("invalid query metric tags: %w", err)
+			}
+		}
+	}
+	return opts, nil
+}
+
+// Query runs a query with its arguments, which replace the $1, $2... or ?
+// placeholders of the query, for Postgres and MySQL respectively. It returns
+// a promise that resolves to an object with the columns and the rows of the
+// result, the number of rows affected, the last inserted ID for MySQL and
+// the duration of the query in milliseconds.
+func (p *Pool) Query(query string, args sobek.Value, options sobek.Value) *sobek.Promise {
+	vu := p.mi.vu
+	rt := vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	state := vu.State()
+	if state == nil {
+		_ = reject(common.NewInitContextError("query() can't be used in the init context"))
+		return promise
+	}
+	var queryArgs []any
+	if !common.IsNullish(args) {
+		if err := rt.ExportTo(args, &queryArgs); err != nil {
+			_ = reject(fmt.Errorf("the arguments of the query must be an array: %w", err))
+			return promise
+		}
+		for i, arg := range queryArgs {
+			if ab, ok := arg.(sobek.ArrayBuffer); ok {
+				queryArgs[i] = ab.Bytes()
+			}
+		}
+	}
+	tagsAndMeta := state.Tags.GetCurrentValues()
+	opts, err := p.parseQueryOptions(options, query, &tagsAndMeta)
+	if err != nil {
+		_ = reject(err)
+		return promise
+	}
+
+	ctx := vu.Context()
+	callback := vu.RegisterCallback()
+	go func() {
+		c, err := p.pool.acquire(ctx, state)
+		if err != nil {
+			callback(func() error { return reject(err) })
+			return
+		}
+		start := time.Now()
+		res, err := c.query(query, queryArgs, opts.Timeout)
+		end := time.Now()
+		var serverErr *ServerError
+		p.pool.release(c, err != nil && !errors.As(err, &serverErr))
+
+		tags := tagsAndMeta.Tags.With("driver", p.pool.opts.Driver).With("name", opts.Name)
+		samples := []metrics.Sample{{
+			TimeSeries: metrics.TimeSeries{Metric: p.mi.queryFailed, Tags: tags},
+			Time:       end,
+			Metadata:   tagsAndMeta.Metadata,
+			Value:      metrics.B(err != nil),
+		}}
+		if err == nil {
+			rows := len(res.rows)
+			if res.columns == nil {
+				rows = int(res.rowsAffected)
+			}
+			samples = append(samples, metrics.Sample{
+				TimeSeries: metrics.TimeSeries{Metric: p.mi.queryDuration, Tags: tags},
+				Time:       end,
+				Metadata:   tagsAndMeta.Metadata,
+				Value:      metrics.D(end.Sub(start)),
+			}, metrics.Sample{
+				TimeSeries: metrics.TimeSeries{Metric: p.mi.queryRows, Tags: tags},
+				Time:       end,
+				Metadata:   tagsAndMeta.Metadata,
+				Value:      float64(rows),
+			})
+		}
+		metrics.PushIfNotDone(ctx, state.Samples, metrics.ConnectedSamples{Samples: samples, Time: end})
+
+		callback(func() error {
+			if err != nil {
+				return reject(err)
+			}
+			return resolve(resultObject(rt, res, end.Sub(start)))
+		})
+	}()
+	return promise
+}
+
+// Close closes the connections of the pool, after which it can't be used.
+func (p *Pool) Close() {
+	p.pool.close()
+}
+
+// resultObject returns the object of the result of a query, with the rows as
+// objects with the values of their columns, in their order.
+func resultObject(rt *sobek.Runtime, res *result, duration time.Duration) *sobek.Object {
+	columns := make([]any, len(res.columns))
+	for i, c := range res.columns {
+		columns[i] = c.name
+	}
+	rows := make([]any, len(res.rows))
+	for i, row := range res.rows {
+		obj := rt.NewObject()
+		for j, c := range res.columns {
+			if j < len(row) {
+				_ = obj.Set(c.name, c.value(row[j]))
+			}
+		}
+		rows[i] = obj
+	}
+
+	obj := rt.NewObject()
+	_ = obj.Set("columns", rt.NewArray(columns...))
+	_ = obj.Set("rows", rt.NewArray(rows...))
+	_ = obj.Set("rowsAffected", res.rowsAffected)
+	_ = obj.Set("lastInsertId", res.lastInsertID)
+	_ = obj.Set("duration", metrics.D(duration))
+	return obj
+}
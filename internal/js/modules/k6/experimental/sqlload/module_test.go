@@ -0,0 +1,213 @@
+package sqlload
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+func newTestRuntime(t *testing.T, port int) (*modulestest.Runtime, chan metrics.SampleContainer) {
+	t.Helper()
+
+	rt := modulestest.NewRuntime(t)
+	m, ok := New().NewModuleInstance(rt.VU).(*ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, rt.VU.Runtime().Set("sql", m.Exports().Named))
+	require.NoError(t, rt.VU.Runtime().Set("PORT", port))
+
+	samples := make(chan metrics.SampleContainer, 100)
+	registry := metrics.NewRegistry()
+	rt.MoveToVUContext(&lib.State{
+		Dialer:  &net.Dialer{},
+		Samples: samples,
+		Tags:    lib.NewVUStateTags(registry.RootTagSet()),
+	})
+	return rt, samples
+}
+
+// sampleValues returns the values of the samples of the metric by their name tag.
+func sampleValues(samples chan metrics.SampleContainer, metric string) map[string][]float64 {
+	values := make(map[string][]float64)
+	for _, container := range metrics.GetBufferedSamples(samples) {
+		for _, s := range container.GetSamples() {
+			if s.Metric.Name == metric {
+				name, _ := s.Tags.Get("name")
+				values[name] = append(values[name], s.Value)
+			}
+		}
+	}
+	return values
+}
+
+func TestPostgres(t *testing.T) {
+	t.Parallel()
+
+	server := newFakePostgres(t)
+	rt, samples := newTestRuntime(t, server.port())
+	_, err := rt.RunOnEventLoop(`(async () => {
+		const db = new sql.Pool({ driver: "postgres", host: "127.0.0.1", port: PORT, user: "k6", password: "secret" });
+		const res = await db.query("SELECT id, name, ok FROM users WHERE name = $1 AND age > $2", ["ann", 30]);
+		if (res.columns.join() !== "id,name,ok" || res.rows.length !== 2 || res.rowsAffected !== 2) {
+			throw new Error("unexpected result " + JSON.stringify(res));
+		}
+		if (JSON.stringify(res.rows) !== '[{"id":42,"name":"ann","ok":true},{"id":7,"name":null,"ok":false}]') {
+			throw new Error("unexpected rows " + JSON.stringify(res.rows));
+		}
+		const insert = await db.query("INSERT INTO users VALUES ($1, $2)", [null, true], { name: "insert" });
+		if (insert.rowsAffected !== 1 || insert.rows.length !== 0) {
+			throw new Error("unexpected result " + JSON.stringify(insert));
+		}
+		try {
+			await db.query("FAIL");
+			throw new Error("the query didn't fail");
+		} catch (e) {
+			if (e.toString().indexOf("42601: syntax error") < 0) {
+				throw e;
+			}
+		}
+		await db.query("SELECT 1", ["again"]);
+		db.close();
+	})()`)
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	assert.Equal(t, 1, server.conns)
+	assert.Equal(t, [][]string{{"ann", "30"}, {"NULL", "true"}, nil, {"again"}}, server.args)
+	server.mu.Unlock()
+
+	assert.Equal(t, map[string][]float64{
+		"SELECT id, name, ok FROM users WHERE name = $1 AND age > $2": {0},
+		"insert":   {0},
+		"FAIL":     {1},
+		"SELECT 1": {0},
+	}, sampleValues(samples, queryFailedName))
+}
+
+func TestMySQL(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeMySQL(t)
+	rt, samples := newTestRuntime(t, server.port())
+	_, err := rt.RunOnEventLoop(`(async () => {
+		const db = new sql.Pool({ driver: "mysql", host: "127.0.0.1", port: PORT, user: "k6", password: "secret", database: "db" });
+		const res = await db.query("SELECT id, name FROM users WHERE name = ?", ["ann"]);
+		if (JSON.stringify(res.rows) !== '[{"id":1,"name":"a\'b"},{"id":2,"name":null}]') {
+			throw new Error("unexpected rows " + JSON.stringify(res.rows));
+		}
+		const insert = await db.query("INSERT INTO users (name) VALUES (?)", ["bob"], { name: "insert" });
+		if (insert.rowsAffected !== 2 || insert.lastInsertId !== 7) {
+			throw new Error("unexpected result " + JSON.stringify(insert));
+		}
+		try {
+			await db.query("FAIL");
+			throw new Error("the query didn't fail");
+		} catch (e) {
+			if (e.toString().indexOf("1064: You have an error") < 0) {
+				throw e;
+			}
+		}
+		try {
+			await db.query("SELECT ?", [], { name: "placeholders" });
+			throw new Error("the query didn't fail");
+		} catch (e) {
+			if (e.toString().indexOf("the query has 1 placeholders for the 0 arguments") < 0) {
+				throw e;
+			}
+		}
+		await db.query("INSERT INTO users (name, age) VALUES (?, ?)", ["it's\\ -- ?", null], { name: "quoted" });
+	})()`)
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	assert.Equal(t, []string{
+		"SELECT id, name FROM users WHERE name = ?", "INSERT INTO users (name) VALUES (?)", "FAIL", "SELECT ?",
+		"INSERT INTO users (name, age) VALUES (?, ?)",
+	}, server.queries)
+	assert.Equal(t, [][]string{{"ann"}, {"bob"}, {`it's\ -- ?`, "NULL"}}, server.args)
+	server.mu.Unlock()
+
+	assert.Equal(t, map[string][]float64{
+		"SELECT id, name FROM users WHERE name = ?": {2},
+		"insert": {2},
+		"quoted": {2},
+	}, sampleValues(samples, queryRowsName))
+}
+
+func TestPoolMaxConnections(t *testing.T) {
+	t.Parallel()
+
+	server := newFakePostgres(t)
+	rt, _ := newTestRuntime(t, server.port())
+	_, err := rt.RunOnEventLoop(`(async () => {
+		const db = new sql.Pool({
+			driver: "postgres", host: "127.0.0.1", port: PORT, user: "k6", password: "secret",
+			maxConnections: 3, maxIdleConnections: 1,
+		});
+		const queries = [];
+		for (let i = 0; i < 20; i++) {
+			queries.push(db.query("SELECT $1", [String(i)]));
+		}
+		const results = await Promise.all(queries);
+		for (let i = 0; i < 20; i++) {
+			if (results[i].rows[0].name !== String(i)) {
+				throw new Error("unexpected result " + JSON.stringify(results[i]));
+			}
+		}
+	})()`)
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.LessOrEqual(t, server.maxBusy, 3)
+	assert.Len(t, server.queries, 20)
+}
+
+func TestPoolErrors(t *testing.T) {
+	t.Parallel()
+
+	server := newFakePostgres(t)
+	port := strconv.Itoa(server.port())
+	tests := map[string]string{
+		`new sql.Pool()`: "requires options",
+		`new sql.Pool({ driver: "oracle", user: "k6" })`:                     `invalid driver option "oracle"`,
+		`new sql.Pool({ driver: "mysql" })`:                                  "requires the user option",
+		`new sql.Pool({ driver: "mysql", user: "k6", port: -1 })`:            "invalid port option -1",
+		`new sql.Pool({ driver: "mysql", user: "k6", maxConnections: 0 })`:   "it must be at least 1",
+		`new sql.Pool({ driver: "mysql", user: "k6", queryTimeout: "-1s" })`: "invalid queryTimeout option",
+	}
+	for script, expected := range tests {
+		t.Run(script, func(t *testing.T) {
+			t.Parallel()
+			rt, _ := newTestRuntime(t, 0)
+			_, err := rt.VU.Runtime().RunString(script)
+			require.ErrorContains(t, err, expected)
+		})
+	}
+
+	t.Run("authentication", func(t *testing.T) {
+		t.Parallel()
+		rt, _ := newTestRuntime(t, 0)
+		_, err := rt.RunOnEventLoop(`
+			new sql.Pool({ driver: "postgres", host: "127.0.0.1", port: ` + port + `, user: "k6", password: "wrong" }).query("SELECT 1")
+		`)
+		require.ErrorContains(t, err, "28P01: password authentication failed")
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		t.Parallel()
+		rt, _ := newTestRuntime(t, 0)
+		_, err := rt.RunOnEventLoop(`
+			const db = new sql.Pool({ driver: "postgres", host: "127.0.0.1", port: ` + port + `, user: "k6", password: "secret" });
+			db.close();
+			db.query("SELECT 1")
+		`)
+		require.ErrorContains(t, err, "the pool is closed")
+	})
+}
@@ -0,0 +1,798 @@
+package sqlload
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // mysql_native_password and the RSA padding are defined with SHA1
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"time"
+)
+
+// The capabilities of the MySQL clients that are used.
+const (
+	myClientLongPassword     = 1 << 0
+	myClientLongFlag         = 1 << 2
+	myClientConnectWithDB    = 1 << 3
+	myClientProtocol41       = 1 << 9
+	myClientSSL              = 1 << 11
+	myClientTransactions     = 1 << 13
+	myClientSecureConnection = 1 << 15
+	myClientMultiResults     = 1 << 17
+	myClientPluginAuth       = 1 << 19
+)
+
+// The commands, packets and authentication plugins of the protocol.
+const (
+	myComQuit        = 0x01
+	myComStmtPrepare = 0x16
+	myComStmtExecute = 0x17
+	myComStmtClose   = 0x19
+
+	myOK          = 0x00
+	myAuthMore    = 0x01
+	myLocalInfile = 0xfb
+	myEOF         = 0xfe
+	myErr         = 0xff
+
+	myNativePassword = "mysql_native_password"
+	myCachingSHA2    = "caching_sha2_password"
+
+	myCharsetUTF8MB4   = 45
+	myMaxPacketSize    = 1<<24 - 1
+	myMoreResultsFlag  = 0x0008
+	myUnsignedFlag     = 0x0020
+	myFastAuthSuccess  = 3
+	myFullAuthRequired = 4
+	myRequestPublicKey = 2
+)
+
+// The types of the columns and the parameters that are used.
+const (
+	myTypeDecimal    = 0x00
+	myTypeTiny       = 0x01
+	myTypeShort      = 0x02
+	myTypeLong       = 0x03
+	myTypeFloat      = 0x04
+	myTypeDouble     = 0x05
+	myTypeNull       = 0x06
+	myTypeTimestamp  = 0x07
+	myTypeLongLong   = 0x08
+	myTypeInt24      = 0x09
+	myTypeDate       = 0x0a
+	myTypeTime       = 0x0b
+	myTypeDateTime   = 0x0c
+	myTypeYear       = 0x0d
+	myTypeNewDecimal = 0xf6
+	myTypeBlob       = 0xfc
+	myTypeString     = 0xfe
+)
+
+// The column types that are converted to numbers.
+var myNumberTypes = map[byte]bool{ //nolint:gochecknoglobals
+	myTypeDecimal:    true,
+	myTypeTiny:       true,
+	myTypeShort:      true,
+	myTypeLong:       true,
+	myTypeFloat:      true,
+	myTypeDouble:     true,
+	myTypeLongLong:   true,
+	myTypeInt24:      true,
+	myTypeNewDecimal: true,
+}
+
+// mysql speaks the client/server protocol of MySQL. The queries are prepared
+// as statements and executed with the binary protocol, so their arguments are
+// sent apart from them, like with the extended query protocol of Postgres.
+// The values of the binary rows are converted to the text format of the
+// results.
+type mysql struct {
+	r   *bufio.Reader
+	w   io.Writer
+	seq byte
+	tls bool
+}
+
+// myField is the type of a column of the binary rows.
+type myField struct {
+	typ   byte
+	flags uint16
+}
+
+// startMySQL reads the handshake of the server, upgrades the connection to
+// TLS if there is a config and authenticates the user. It returns the
+// connection that the protocol uses.
+func startMySQL(
+	ctx context.Context, netConn net.Conn, tlsConfig *tls.Config, opts poolOptions,
+) (*mysql, net.Conn, error) {
+	m := &mysql{r: bufio.NewReader(netConn), w: netConn}
+	handshake, err := m.readPacket()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(handshake) > 0 && handshake[0] == myErr {
+		return nil, nil, myError(handshake)
+	}
+	serverCaps, scramble, plugin, err := parseMyHandshake(handshake)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	caps := uint32(myClientLongPassword | myClientLongFlag | myClientProtocol41 | myClientTransactions |
+		myClientSecureConnection | myClientMultiResults | myClientPluginAuth)
+	if opts.Database != "" {
+		caps |= myClientConnectWithDB
+	}
+	header := make([]byte, 0, 32)
+	if tlsConfig != nil {
+		if serverCaps&myClientSSL == 0 {
+			return nil, nil, errors.New("the server doesn't support TLS")
+		}
+		caps |= myClientSSL
+		header = myHandshakeHeader(header, caps)
+		if err := m.writePacket(header); err != nil {
+			return nil, nil, err
+		}
+		tlsConn := tls.Client(netConn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, nil, err
+		}
+		netConn = tlsConn
+		m.r, m.w, m.tls = bufio.NewReader(tlsConn), tlsConn, true
+	}
+
+	if plugin != myCachingSHA2 {
+		plugin = myNativePassword
+	}
+	auth := myScramble(plugin, opts.Password, scramble)
+	packet := myHandshakeHeader(header[:0], caps)
+	packet = append(append(packet, opts.User...), 0)
+	packet = append(packet, byte(len(auth)))
+	packet = append(packet, auth...)
+	if opts.Database != "" {
+		packet = append(append(packet, opts.Database...), 0)
+	}
+	packet = append(append(packet, plugin...), 0)
+	if err := m.writePacket(packet); err != nil {
+		return nil, nil, err
+	}
+	if err := m.authenticate(plugin, scramble, opts.Password); err != nil {
+		return nil, nil, err
+	}
+	return m, netConn, nil
+}
+
+// authenticate handles the replies of the server to the authentication,
+// until it's accepted.
+func (m *mysql) authenticate(plugin string, scramble []byte, password string) error {
+	for {
+		packet, err := m.readPacket()
+		if err != nil {
+			return err
+		}
+		if len(packet) == 0 {
+			return errors.New("invalid authentication reply")
+		}
+		switch packet[0] {
+		case myOK:
+			return nil
+		case myErr:
+			return myError(packet)
+		case myEOF:
+			// the server switches to another plugin, with a new scramble
+			end := indexZero(packet[1:])
+			if end < 0 {
+				return errors.New("invalid authentication switch request")
+			}
+			plugin = string(packet[1 : end+1])
+			scramble = trimZero(packet[end+2:])
+			if plugin != myNativePassword && plugin != myCachingSHA2 {
+				return fmt.Errorf("unsupported authentication plugin %q", plugin)
+			}
+			if err := m.writePacket(myScramble(plugin, password, scramble)); err != nil {
+				return err
+			}
+		case myAuthMore:
+			if plugin != myCachingSHA2 || len(packet) < 2 {
+				return errors.New("unexpected authentication data")
+			}
+			if err := m.cachingSHA2More(packet[1:], scramble, password); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unexpected authentication reply %#x", packet[0])
+		}
+	}
+}
+
+// cachingSHA2More answers the additional data of caching_sha2_password. When
+// the server doesn't have the password cached, it's sent in cleartext over
+// TLS, or encrypted with the public key of the server otherwise.
+func (m *mysql) cachingSHA2More(data, scramble []byte, password string) error {
+	switch {
+	case data[0] == myFastAuthSuccess && len(data) == 1:
+		return nil
+	case data[0] == myFullAuthRequired && len(data) == 1:
+		if m.tls {
+			return m.writePacket(append([]byte(password), 0))
+		}
+		return m.writePacket([]byte{myRequestPublicKey})
+	default:
+		// the public key of the server
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return errors.New("invalid public key of the server")
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("invalid public key of the server: %w", err)
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("the public key of the server isn't an RSA key")
+		}
+		plain := append([]byte(password), 0)
+		for i := range plain {
+			plain[i] ^= scramble[i%len(scramble)]
+		}
+		encrypted, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, rsaKey, plain, nil) //nolint:gosec
+		if err != nil {
+			return err
+		}
+		return m.writePacket(encrypted)
+	}
+}
+
+// query prepares the query, executes it with the arguments and closes the
+// statement.
+func (m *mysql) query(query string, args []any) (*result, error) {
+	id, params, err := m.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = m.closeStatement(id) }()
+	if params != len(args) {
+		return nil, fmt.Errorf("the query has %d placeholders for the %d arguments", params, len(args))
+	}
+	execute, err := myExecute(id, args)
+	if err != nil {
+		return nil, err
+	}
+	m.seq = 0
+	if err := m.writePacket(execute); err != nil {
+		return nil, err
+	}
+
+	// only the first result is returned, the others are skipped
+	var res *result
+	var queryErr error
+	for {
+		r, more, err := m.readResult()
+		if err != nil {
+			var serverErr *ServerError
+			if !errors.As(err, &serverErr) {
+				return nil, err
+			}
+			queryErr = err
+		}
+		if res == nil && err == nil {
+			res = r
+		}
+		if !more {
+			break
+		}
+	}
+	if queryErr != nil && res == nil {
+		return nil, queryErr
+	}
+	return res, nil
+}
+
+// prepare prepares the query as a statement, and returns its ID and the
+// number of its parameters.
+func (m *mysql) prepare(query string) (uint32, int, error) {
+	m.seq = 0
+	if err := m.writePacket(append([]byte{myComStmtPrepare}, query...)); err != nil {
+		return 0, 0, err
+	}
+	packet, err := m.readPacket()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(packet) > 0 && packet[0] == myErr {
+		return 0, 0, myError(packet)
+	}
+	if len(packet) < 12 || packet[0] != myOK {
+		return 0, 0, errors.New("invalid prepare reply")
+	}
+	id := binary.LittleEndian.Uint32(packet[1:])
+	columns := int(binary.LittleEndian.Uint16(packet[5:]))
+	params := int(binary.LittleEndian.Uint16(packet[7:]))
+	// the definitions of the parameters and the columns, followed by an EOF
+	// each, aren't used since the ones of the result are sent again
+	for _, count := range []int{params, columns} {
+		if count == 0 {
+			continue
+		}
+		for range count + 1 {
+			if _, err := m.readPacket(); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	return id, params, nil
+}
+
+// closeStatement deallocates the statement, the server doesn't reply.
+func (m *mysql) closeStatement(id uint32) error {
+	m.seq = 0
+	return m.writePacket(binary.LittleEndian.AppendUint32([]byte{myComStmtClose}, id))
+}
+
+// readResult reads a result, and returns whether more results follow.
+func (m *mysql) readResult() (*result, bool, error) {
+	packet, err := m.readPacket()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(packet) == 0 {
+		return nil, false, errors.New("invalid query reply")
+	}
+	switch packet[0] {
+	case myOK:
+		res := &result{}
+		affected, rest := readLenEnc(packet[1:])
+		insertID, _ := readLenEnc(rest)
+		res.rowsAffected, res.lastInsertID = int64(affected), int64(insertID) //nolint:gosec
+		return res, myOKStatus(packet)&myMoreResultsFlag != 0, nil
+	case myErr:
+		return nil, false, myError(packet)
+	case myLocalInfile:
+		return nil, false, errors.New("LOAD DATA LOCAL INFILE isn't supported")
+	}
+
+	count, _ := readLenEnc(packet)
+	res := &result{columns: make([]column, count)}
+	fields := make([]myField, count)
+	for i := range res.columns {
+		def, err := m.readPacket()
+		if err != nil {
+			return nil, false, err
+		}
+		if res.columns[i], fields[i], err = parseMyColumn(def); err != nil {
+			return nil, false, err
+		}
+	}
+	if _, err := m.readPacket(); err != nil { // the EOF after the columns
+		return nil, false, err
+	}
+	for {
+		packet, err := m.readPacket()
+		if err != nil {
+			return nil, false, err
+		}
+		if len(packet) > 0 && packet[0] == myErr {
+			return nil, false, myError(packet)
+		}
+		if len(packet) > 0 && packet[0] == myEOF && len(packet) < 9 {
+			var status uint16
+			if len(packet) >= 5 {
+				status = binary.LittleEndian.Uint16(packet[3:])
+			}
+			return res, status&myMoreResultsFlag != 0, nil
+		}
+		row, err := myBinaryRow(packet, fields)
+		if err != nil {
+			return nil, false, err
+		}
+		res.rows = append(res.rows, row)
+	}
+}
+
+// myOKStatus returns the status flags of an OK packet.
+func myOKStatus(packet []byte) uint16 {
+	_, rest := readLenEnc(packet[1:]) // affected rows
+	_, rest = readLenEnc(rest)        // last insert id
+	if len(rest) < 2 {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(rest)
+}
+
+func (m *mysql) terminate() error {
+	m.seq = 0
+	return m.writePacket([]byte{myComQuit})
+}
+
+// readPacket reads a packet, joining the ones split because of their size.
+func (m *mysql) readPacket() ([]byte, error) {
+	var packet []byte
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(m.r, header[:]); err != nil {
+			return nil, err
+		}
+		size := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+		m.seq = header[3] + 1
+		if len(packet)+size > maxMessageSize {
+			return nil, fmt.Errorf("the packet is larger than %d bytes", maxMessageSize)
+		}
+		start := len(packet)
+		packet = append(packet, make([]byte, size)...)
+		if _, err := io.ReadFull(m.r, packet[start:]); err != nil {
+			return nil, err
+		}
+		if size < myMaxPacketSize {
+			return packet, nil
+		}
+	}
+}
+
+// writePacket writes a packet, split if it's too large for one.
+func (m *mysql) writePacket(payload []byte) error {
+	for {
+		size := min(len(payload), myMaxPacketSize)
+		header := []byte{byte(size), byte(size >> 8), byte(size >> 16), m.seq}
+		m.seq++
+		if _, err := m.w.Write(append(header, payload[:size]...)); err != nil {
+			return err
+		}
+		payload = payload[size:]
+		if size < myMaxPacketSize {
+			return nil
+		}
+	}
+}
+
+// parseMyHandshake returns the capabilities, the scramble and the
+// authentication plugin of the initial handshake of the server.
+func parseMyHandshake(packet []byte) (uint32, []byte, string, error) {
+	invalid := errors.New("invalid handshake of the server")
+	if len(packet) < 1 || packet[0] != 10 {
+		return 0, nil, "", invalid
+	}
+	end := indexZero(packet[1:])
+	if end < 0 {
+		return 0, nil, "", invalid
+	}
+	rest := packet[end+2:]
+	if len(rest) < 4+8+1+2 {
+		return 0, nil, "", invalid
+	}
+	scramble := append([]byte{}, rest[4:12]...)
+	caps := uint32(binary.LittleEndian.Uint16(rest[13:]))
+	rest = rest[15:]
+	if len(rest) < 1+2+2+1+10 {
+		return caps, scramble, "", nil
+	}
+	caps |= uint32(binary.LittleEndian.Uint16(rest[3:])) << 16
+	authLen := int(rest[5])
+	rest = rest[16:]
+	part2 := max(13, authLen-8)
+	if len(rest) < part2 {
+		return 0, nil, "", invalid
+	}
+	scramble = append(scramble, trimZero(rest[:part2])...)
+	rest = rest[part2:]
+	return caps, scramble, string(trimZero(rest)), nil
+}
+
+// myHandshakeHeader appends the fixed part of the handshake response, which
+// is also the TLS request.
+func myHandshakeHeader(b []byte, caps uint32) []byte {
+	b = binary.LittleEndian.AppendUint32(b, caps)
+	b = binary.LittleEndian.AppendUint32(b, myMaxPacketSize)
+	b = append(b, myCharsetUTF8MB4)
+	return append(b, make([]byte, 23)...)
+}
+
+// myScramble returns the authentication data of the password for the plugin.
+func myScramble(plugin, password string, scramble []byte) []byte {
+	if password == "" {
+		return nil
+	}
+	if plugin == myCachingSHA2 {
+		// SHA256(password) XOR SHA256(SHA256(SHA256(password)), scramble)
+		h1 := sha256.Sum256([]byte(password))
+		h2 := sha256.Sum256(h1[:])
+		h3 := sha256.Sum256(append(h2[:], scramble...))
+		for i := range h1 {
+			h1[i] ^= h3[i]
+		}
+		return h1[:]
+	}
+	// SHA1(password) XOR SHA1(scramble, SHA1(SHA1(password)))
+	h1 := sha1.Sum([]byte(password))                                //nolint:gosec
+	h2 := sha1.Sum(h1[:])                                           //nolint:gosec
+	h3 := sha1.Sum(append(append([]byte{}, scramble...), h2[:]...)) //nolint:gosec
+	for i := range h1 {
+		h1[i] ^= h3[i]
+	}
+	return h1[:]
+}
+
+func parseMyColumn(def []byte) (column, myField, error) {
+	// catalog, schema, table, org_table, name, org_name
+	var name []byte
+	for i := range 6 {
+		size, rest := readLenEnc(def)
+		if uint64(len(rest)) < size {
+			return column{}, myField{}, errors.New("invalid column definition")
+		}
+		if i == 4 {
+			name = rest[:size]
+		}
+		def = rest[size:]
+	}
+	// length of the fixed fields, charset, column length, type and flags
+	if len(def) < 1+2+4+1+2 {
+		return column{}, myField{}, errors.New("invalid column definition")
+	}
+	c := column{name: string(name)}
+	f := myField{typ: def[7], flags: binary.LittleEndian.Uint16(def[8:])}
+	if myNumberTypes[f.typ] {
+		c.kind = kindNumber
+	}
+	return c, f, nil
+}
+
+// myExecute returns the packet that executes the statement with the
+// arguments, in the binary format.
+func myExecute(id uint32, args []any) ([]byte, error) {
+	packet := binary.LittleEndian.AppendUint32([]byte{myComStmtExecute}, id)
+	packet = append(packet, 0)                           // no cursor
+	packet = binary.LittleEndian.AppendUint32(packet, 1) // iteration count
+	if len(args) == 0 {
+		return packet, nil
+	}
+	nulls := make([]byte, (len(args)+7)/8)
+	types := make([]byte, 0, 2*len(args))
+	var values []byte
+	for i, arg := range args {
+		typ, v, err := myAppendArg(values, arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d: %w", i+1, err)
+		}
+		if typ == myTypeNull {
+			nulls[i/8] |= 1 << (i % 8)
+		}
+		types = append(types, typ, 0)
+		values = v
+	}
+	packet = append(packet, nulls...)
+	packet = append(packet, 1) // the types of the parameters follow
+	packet = append(packet, types...)
+	return append(packet, values...), nil
+}
+
+// myAppendArg appends an argument in the binary format, and returns its type.
+func myAppendArg(b []byte, arg any) (byte, []byte, error) {
+	switch v := arg.(type) {
+	case nil:
+		return myTypeNull, b, nil
+	case string:
+		return myTypeString, appendLenEnc(b, []byte(v)), nil
+	case bool:
+		if v {
+			return myTypeTiny, append(b, 1), nil
+		}
+		return myTypeTiny, append(b, 0), nil
+	case int64:
+		return myTypeLongLong, binary.LittleEndian.AppendUint64(b, uint64(v)), nil //nolint:gosec
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return 0, nil, fmt.Errorf("%v can't be stored", v)
+		}
+		return myTypeDouble, binary.LittleEndian.AppendUint64(b, math.Float64bits(v)), nil
+	case time.Time:
+		v = v.UTC()
+		b = append(b, 11)
+		b = binary.LittleEndian.AppendUint16(b, uint16(v.Year())) //nolint:gosec
+		b = append(b, byte(v.Month()), byte(v.Day()), byte(v.Hour()), byte(v.Minute()), byte(v.Second()))
+		return myTypeDateTime, binary.LittleEndian.AppendUint32(b, uint32(v.Nanosecond()/1000)), nil //nolint:gosec
+	case []byte:
+		return myTypeBlob, appendLenEnc(b, v), nil
+	case map[string]any, []any:
+		j, err := json.Marshal(v)
+		if err != nil {
+			return 0, nil, err
+		}
+		return myTypeString, appendLenEnc(b, j), nil
+	default:
+		return 0, nil, fmt.Errorf("unsupported type %T", arg)
+	}
+}
+
+// myBinaryRow converts a row of the binary protocol to the text format, nil
+// for NULL.
+func myBinaryRow(packet []byte, fields []myField) ([][]byte, error) {
+	// the NULL bitmap of the binary rows starts at its third bit
+	nullsLen := (len(fields) + 7 + 2) / 8
+	if len(packet) < 1+nullsLen || packet[0] != myOK {
+		return nil, errors.New("invalid row")
+	}
+	nulls, values := packet[1:1+nullsLen], packet[1+nullsLen:]
+	row := make([][]byte, len(fields))
+	for i, f := range fields {
+		if nulls[(i+2)/8]&(1<<((i+2)%8)) != 0 {
+			continue
+		}
+		var err error
+		if row[i], values, err = f.text(values); err != nil {
+			return nil, err
+		}
+	}
+	return row, nil
+}
+
+// text converts the binary value of the field at the start of b to the text
+// format, and returns it with the rest of b.
+func (f myField) text(b []byte) ([]byte, []byte, error) {
+	invalid := errors.New("invalid row")
+	var size int
+	switch f.typ {
+	case myTypeTiny:
+		size = 1
+	case myTypeShort, myTypeYear:
+		size = 2
+	case myTypeLong, myTypeInt24, myTypeFloat:
+		size = 4
+	case myTypeLongLong, myTypeDouble:
+		size = 8
+	case myTypeDate, myTypeDateTime, myTypeTimestamp, myTypeTime:
+		if len(b) == 0 {
+			return nil, nil, invalid
+		}
+		size = 1 + int(b[0])
+	default:
+		// the strings, and the decimals in their text format
+		n, rest := readLenEnc(b)
+		if len(b) == 0 || rest == nil || uint64(len(rest)) < n {
+			return nil, nil, invalid
+		}
+		return rest[:n:n], rest[n:], nil
+	}
+	if len(b) < size {
+		return nil, nil, invalid
+	}
+	v, rest := b[:size], b[size:]
+	switch f.typ {
+	case myTypeTiny:
+		return f.integer(int64(int8(v[0])), uint64(v[0])), rest, nil //nolint:gosec
+	case myTypeShort, myTypeYear:
+		u := binary.LittleEndian.Uint16(v)
+		return f.integer(int64(int16(u)), uint64(u)), rest, nil //nolint:gosec
+	case myTypeLong, myTypeInt24:
+		u := binary.LittleEndian.Uint32(v)
+		return f.integer(int64(int32(u)), uint64(u)), rest, nil //nolint:gosec
+	case myTypeLongLong:
+		u := binary.LittleEndian.Uint64(v)
+		return f.integer(int64(u), u), rest, nil //nolint:gosec
+	case myTypeFloat:
+		return strconv.AppendFloat(nil, float64(math.Float32frombits(binary.LittleEndian.Uint32(v))), 'g', -1, 32), rest, nil
+	case myTypeDouble:
+		return strconv.AppendFloat(nil, math.Float64frombits(binary.LittleEndian.Uint64(v)), 'g', -1, 64), rest, nil
+	case myTypeTime:
+		return myTimeText(v[1:]), rest, nil
+	default:
+		return myDateTimeText(v[1:], f.typ == myTypeDate), rest, nil
+	}
+}
+
+func (f myField) integer(signed int64, unsigned uint64) []byte {
+	if f.flags&myUnsignedFlag != 0 {
+		return strconv.AppendUint(nil, unsigned, 10)
+	}
+	return strconv.AppendInt(nil, signed, 10)
+}
+
+// myDateTimeText formats a binary date, datetime or timestamp, which has 0, 4,
+// 7 or 11 bytes, as the text protocol does.
+func myDateTimeText(v []byte, dateOnly bool) []byte {
+	var year, month, day, hour, minute, second, micro int
+	if len(v) >= 4 {
+		year, month, day = int(binary.LittleEndian.Uint16(v)), int(v[2]), int(v[3])
+	}
+	if len(v) >= 7 {
+		hour, minute, second = int(v[4]), int(v[5]), int(v[6])
+	}
+	if len(v) >= 11 {
+		micro = int(binary.LittleEndian.Uint32(v[7:]))
+	}
+	s := fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+	if !dateOnly {
+		s += fmt.Sprintf(" %02d:%02d:%02d", hour, minute, second)
+		if micro != 0 {
+			s += fmt.Sprintf(".%06d", micro)
+		}
+	}
+	return []byte(s)
+}
+
+// myTimeText formats a binary time, which has 0, 8 or 12 bytes, as the text
+// protocol does.
+func myTimeText(v []byte) []byte {
+	var sign string
+	var hours, minute, second, micro int
+	if len(v) >= 8 {
+		if v[0] == 1 {
+			sign = "-"
+		}
+		hours = int(binary.LittleEndian.Uint32(v[1:]))*24 + int(v[5])
+		minute, second = int(v[6]), int(v[7])
+	}
+	if len(v) >= 12 {
+		micro = int(binary.LittleEndian.Uint32(v[8:]))
+	}
+	s := fmt.Sprintf("%s%02d:%02d:%02d", sign, hours, minute, second)
+	if micro != 0 {
+		s += fmt.Sprintf(".%06d", micro)
+	}
+	return []byte(s)
+}
+
+// myError returns the error of an ERR packet, with its error number.
+func myError(packet []byte) error {
+	if len(packet) < 3 {
+		return &ServerError{Message: "unknown error"}
+	}
+	err := &ServerError{Code: strconv.Itoa(int(binary.LittleEndian.Uint16(packet[1:])))}
+	msg := packet[3:]
+	if len(msg) >= 6 && msg[0] == '#' {
+		msg = msg[6:]
+	}
+	err.Message = string(msg)
+	return err
+}
+
+// readLenEnc reads a length-encoded integer.
+func readLenEnc(b []byte) (uint64, []byte) {
+	if len(b) == 0 {
+		return 0, b
+	}
+	switch {
+	case b[0] < 0xfb:
+		return uint64(b[0]), b[1:]
+	case b[0] == 0xfc && len(b) >= 3:
+		return uint64(binary.LittleEndian.Uint16(b[1:])), b[3:]
+	case b[0] == 0xfd && len(b) >= 4:
+		return uint64(b[1]) | uint64(b[2])<<8 | uint64(b[3])<<16, b[4:]
+	case b[0] == 0xfe && len(b) >= 9:
+		return binary.LittleEndian.Uint64(b[1:]), b[9:]
+	}
+	return 0, nil
+}
+
+// appendLenEnc appends v as a length-encoded string.
+func appendLenEnc(b, v []byte) []byte {
+	switch n := uint64(len(v)); {
+	case n < 0xfb:
+		b = append(b, byte(n))
+	case n < 1<<16:
+		b = binary.LittleEndian.AppendUint16(append(b, 0xfc), uint16(n))
+	case n < 1<<24:
+		b = append(b, 0xfd, byte(n), byte(n>>8), byte(n>>16))
+	default:
+		b = binary.LittleEndian.AppendUint64(append(b, 0xfe), n)
+	}
+	return append(b, v...)
+}
+
+func trimZero(b []byte) []byte {
+	if i := indexZero(b); i >= 0 {
+		return b[:i]
+	}
+	return b
+}
@@ -0,0 +1,269 @@
+package sqlload
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMySQL is a MySQL server that authenticates with mysql_native_password
+// and answers the statements starting with SELECT with two binary rows, the
+// ones starting with FAIL with an error when they are prepared and the others
+// with an OK.
+type fakeMySQL struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	queries []string
+	args    [][]string
+}
+
+func newFakeMySQL(t *testing.T) *fakeMySQL {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeMySQL{listener: l}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeMySQL) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port //nolint:forcetypeassert
+}
+
+func (s *fakeMySQL) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	m := &mysql{r: bufio.NewReader(conn), w: conn}
+
+	scramble := []byte("abcdefghij0123456789")
+	handshake := append([]byte{10}, "8.0.0-fake\x00"...)
+	handshake = append(handshake, 1, 0, 0, 0)
+	handshake = append(append(handshake, scramble[:8]...), 0)
+	handshake = binary.LittleEndian.AppendUint16(handshake, 0xffff)
+	handshake = append(handshake, myCharsetUTF8MB4, 2, 0)
+	handshake = binary.LittleEndian.AppendUint16(handshake, 0xffff)
+	handshake = append(append(handshake, 21), make([]byte, 10)...)
+	handshake = append(append(handshake, scramble[8:]...), 0)
+	handshake = append(handshake, myNativePassword+"\x00"...)
+	if err := m.writePacket(handshake); err != nil {
+		return
+	}
+
+	response, err := m.readPacket()
+	if err != nil {
+		return
+	}
+	user, rest, _ := bytes.Cut(response[32:], []byte{0})
+	auth := rest[1 : 1+rest[0]]
+	if string(user) != "k6" || !bytes.Equal(auth, myScramble(myNativePassword, "secret", scramble)) {
+		_ = m.writePacket(append([]byte{myErr, 0x15, 0x04}, "#28000Access denied"...))
+		return
+	}
+	if err := m.writePacket([]byte{myOK, 0, 0, 2, 0, 0, 0}); err != nil {
+		return
+	}
+
+	lenenc := func(b []byte, s string) []byte { return append(append(b, byte(len(s))), s...) }
+	columnDef := func(name string, typ byte) []byte {
+		def := lenenc(nil, "def")
+		for _, s := range []string{"db", "t", "t", name, name} {
+			def = lenenc(def, s)
+		}
+		return append(def, 0x0c, 45, 0, 0, 0, 0, 0, typ, 0, 0, 0, 0, 0)
+	}
+	type columnType struct {
+		name string
+		typ  byte
+	}
+	selectColumns := []columnType{{"id", myTypeLong}, {"name", 0xfd}}
+
+	statements := make(map[uint32]string)
+	var nextID uint32
+	for {
+		packet, err := m.readPacket()
+		if err != nil || packet[0] == myComQuit {
+			return
+		}
+		switch packet[0] {
+		case myComStmtPrepare:
+			query := string(packet[1:])
+			s.mu.Lock()
+			s.queries = append(s.queries, query)
+			s.mu.Unlock()
+			if strings.HasPrefix(query, "FAIL") {
+				_ = m.writePacket(append([]byte{myErr, 0x28, 0x04}, "#42000You have an error in your SQL syntax"...))
+				continue
+			}
+			nextID++
+			statements[nextID] = query
+			params := []columnType{}
+			for range strings.Count(query, "?") {
+				params = append(params, columnType{"?", myTypeString})
+			}
+			var columns []columnType
+			if strings.HasPrefix(query, "SELECT") {
+				columns = selectColumns
+			}
+			reply := binary.LittleEndian.AppendUint32([]byte{myOK}, nextID)
+			reply = binary.LittleEndian.AppendUint16(reply, uint16(len(columns))) //nolint:gosec
+			reply = binary.LittleEndian.AppendUint16(reply, uint16(len(params)))  //nolint:gosec
+			_ = m.writePacket(append(reply, 0, 0, 0))
+			for _, defs := range [][]columnType{params, columns} {
+				if len(defs) == 0 {
+					continue
+				}
+				for _, c := range defs {
+					_ = m.writePacket(columnDef(c.name, c.typ))
+				}
+				_ = m.writePacket([]byte{myEOF, 0, 0, 2, 0})
+			}
+		case myComStmtClose:
+			delete(statements, binary.LittleEndian.Uint32(packet[1:]))
+		case myComStmtExecute:
+			query := statements[binary.LittleEndian.Uint32(packet[1:])]
+			args := fakeMyArgs(packet[10:], strings.Count(query, "?"))
+			s.mu.Lock()
+			s.args = append(s.args, args)
+			s.mu.Unlock()
+
+			if !strings.HasPrefix(query, "SELECT") {
+				_ = m.writePacket([]byte{myOK, 2, 7, 2, 0, 0, 0})
+				continue
+			}
+			_ = m.writePacket([]byte{byte(len(selectColumns))})
+			for _, c := range selectColumns {
+				_ = m.writePacket(columnDef(c.name, c.typ))
+			}
+			_ = m.writePacket([]byte{myEOF, 0, 0, 2, 0})
+			_ = m.writePacket(append([]byte{myOK, 0, 1, 0, 0, 0}, lenenc(nil, "a'b")...))
+			_ = m.writePacket([]byte{myOK, 1 << 3, 2, 0, 0, 0})
+			_ = m.writePacket([]byte{myEOF, 0, 0, 2, 0})
+		}
+	}
+}
+
+// fakeMyArgs returns the arguments of a COM_STMT_EXECUTE packet, after its
+// header, in the text format.
+func fakeMyArgs(b []byte, count int) []string {
+	if count == 0 {
+		return nil
+	}
+	nulls := b[:(count+7)/8]
+	types := b[len(nulls)+1 : len(nulls)+1+2*count]
+	values := b[len(nulls)+1+2*count:]
+	args := make([]string, count)
+	for i := range args {
+		if nulls[i/8]&(1<<(i%8)) != 0 {
+			args[i] = "NULL"
+			continue
+		}
+		var v []byte
+		v, values, _ = myField{typ: types[2*i]}.text(values)
+		args[i] = string(v)
+	}
+	return args
+}
+
+func TestMyScramble(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, myScramble(myNativePassword, "", []byte("scramble")))
+	assert.Len(t, myScramble(myNativePassword, "secret", []byte("scramble")), 20)
+	assert.Len(t, myScramble(myCachingSHA2, "secret", []byte("scramble")), 32)
+	assert.NotEqual(t,
+		myScramble(myCachingSHA2, "secret", []byte("scramble")),
+		myScramble(myCachingSHA2, "secret", []byte("other")))
+}
+
+func TestMySQLPackets(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	m := &mysql{w: &buf}
+	large := bytes.Repeat([]byte{'x'}, myMaxPacketSize+10)
+	require.NoError(t, m.writePacket(large))
+	assert.Equal(t, byte(2), m.seq)
+
+	m.r = bufio.NewReader(io.MultiReader(&buf))
+	packet, err := m.readPacket()
+	require.NoError(t, err)
+	assert.Equal(t, large, packet)
+}
+
+func TestMyExecute(t *testing.T) {
+	t.Parallel()
+
+	packet, err := myExecute(7, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{myComStmtExecute, 7, 0, 0, 0, 0, 1, 0, 0, 0}, packet)
+
+	packet, err = myExecute(7, []any{
+		"it's\\", nil, true, int64(-2), 1.5, time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC),
+		[]byte{0xca, 0xfe}, map[string]any{"a": "b"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{myComStmtExecute, 7, 0, 0, 0, 0, 1, 0, 0, 0}, packet[:10])
+	assert.Equal(t, []string{
+		"it's\\", "NULL", "1", "-2", "1.5", "2026-01-02 03:04:05.000006", "\xca\xfe", `{"a":"b"}`,
+	}, fakeMyArgs(packet[10:], 8))
+
+	_, err = myExecute(7, []any{math.NaN()})
+	require.ErrorContains(t, err, "invalid argument 1: NaN can't be stored")
+	_, err = myExecute(7, []any{struct{}{}})
+	require.ErrorContains(t, err, "invalid argument 1: unsupported type struct {}")
+}
+
+func TestMyBinaryRow(t *testing.T) {
+	t.Parallel()
+
+	fields := []myField{
+		{typ: myTypeTiny}, {typ: myTypeTiny, flags: myUnsignedFlag}, {typ: myTypeLongLong},
+		{typ: myTypeFloat}, {typ: myTypeDouble}, {typ: myTypeDate}, {typ: myTypeDateTime},
+		{typ: myTypeTime}, {typ: myTypeNewDecimal}, {typ: 0xfd},
+	}
+	packet := []byte{myOK, 0, 1 << 3} // the tenth value is NULL
+	packet = append(packet, 0xff, 0xff)
+	packet = binary.LittleEndian.AppendUint64(packet, 1<<40)
+	packet = binary.LittleEndian.AppendUint32(packet, math.Float32bits(0.5))
+	packet = binary.LittleEndian.AppendUint64(packet, math.Float64bits(-2.25))
+	packet = append(packet, 4, 0xea, 0x07, 1, 2)
+	packet = append(packet, 11, 0xea, 0x07, 1, 2, 3, 4, 5, 6, 0, 0, 0)
+	packet = append(packet, 8, 1, 1, 0, 0, 0, 2, 3, 4)
+	packet = append(packet, 4, '1', '.', '5', '0')
+
+	row, err := myBinaryRow(packet, fields)
+	require.NoError(t, err)
+	values := make([]any, len(row))
+	for i, v := range row {
+		if v != nil {
+			values[i] = string(v)
+		}
+	}
+	assert.Equal(t, []any{
+		"-1", "255", "1099511627776", "0.5", "-2.25", "2026-01-02", "2026-01-02 03:04:05.000006",
+		"-26:03:04", "1.50", nil,
+	}, values)
+
+	_, err = myBinaryRow(packet[:len(packet)-2], fields)
+	require.ErrorContains(t, err, "invalid row")
+}
@@ -0,0 +1,93 @@
+package sqlload
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.k6.io/k6/lib"
+)
+
+// errPoolClosed is returned for the queries of a closed pool.
+var errPoolClosed = errors.New("the pool is closed")
+
+// pool keeps the connections of a VU to the database, up to
+// MaxConnections at a time. The idle ones are reused by the next queries.
+type pool struct {
+	opts poolOptions
+	// slots has a value for each open or opening connection, the queries wait
+	// for one when the pool is full.
+	slots chan struct{}
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+func newPool(opts poolOptions) *pool {
+	return &pool{opts: opts, slots: make(chan struct{}, opts.MaxConnections)}
+}
+
+// acquire returns an idle connection, or opens a new one, waiting for the
+// pool to have room for it.
+func (p *pool) acquire(ctx context.Context, state *lib.State) (*conn, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		<-p.slots
+		return nil, errPoolClosed
+	}
+	now := time.Now()
+	for len(p.idle) > 0 {
+		c := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		// the connections of a previous run of the VU are closed
+		if c.ctx == ctx && c.usable(now, p.opts.MaxLifetime) {
+			p.mu.Unlock()
+			return c, nil
+		}
+		c.close(c.ctx == ctx)
+	}
+	p.mu.Unlock()
+
+	c, err := dialConn(ctx, state, p.opts)
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+	return c, nil
+}
+
+// release returns the connection to the pool after a query, or closes it if
+// it's broken or there are enough idle ones.
+func (p *pool) release(c *conn, broken bool) {
+	defer func() { <-p.slots }()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if broken || p.closed || len(p.idle) >= p.opts.MaxIdleConnections {
+		c.close(!broken)
+		return
+	}
+	p.idle = append(p.idle, c)
+}
+
+// close closes the idle connections, the busy ones are closed once their
+// queries are done.
+func (p *pool) close() {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle, p.closed = nil, true
+	p.mu.Unlock()
+
+	for _, c := range idle {
+		c.close(true)
+	}
+}
@@ -0,0 +1,535 @@
+package sqlload
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec // the md5 authentication of Postgres is defined with MD5
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The codes of the messages of the Postgres protocol, version 3.
+const (
+	pgProtocolVersion = 3 << 16
+	pgSSLRequestCode  = 80877103
+
+	pgAuthOK           = 0
+	pgAuthCleartext    = 3
+	pgAuthMD5          = 5
+	pgAuthSASL         = 10
+	pgAuthSASLContinue = 11
+	pgAuthSASLFinal    = 12
+
+	pgScramSHA256 = "SCRAM-SHA-256"
+)
+
+// The type OIDs of the columns that are converted to numbers and booleans.
+var pgTypeKinds = map[uint32]valueKind{ //nolint:gochecknoglobals
+	16:   kindBool,   // bool
+	20:   kindNumber, // int8
+	21:   kindNumber, // int2
+	23:   kindNumber, // int4
+	26:   kindNumber, // oid
+	700:  kindNumber, // float4
+	701:  kindNumber, // float8
+	1700: kindNumber, // numeric
+}
+
+// postgres speaks the version 3 of the Postgres protocol. The queries use the
+// extended protocol, with the arguments sent separately from the query in the
+// text format.
+type postgres struct {
+	r *bufio.Reader
+	w *bufio.Writer
+	// buf is the message that is being written.
+	buf []byte
+}
+
+// startPostgres starts a session on the connection, with TLS if there is a
+// config, and authenticates the user. It returns the connection that the
+// protocol uses.
+func startPostgres(
+	ctx context.Context, netConn net.Conn, tlsConfig *tls.Config, opts poolOptions,
+) (*postgres, net.Conn, error) {
+	if tlsConfig != nil {
+		var req [8]byte
+		binary.BigEndian.PutUint32(req[0:], 8)
+		binary.BigEndian.PutUint32(req[4:], pgSSLRequestCode)
+		if _, err := netConn.Write(req[:]); err != nil {
+			return nil, nil, err
+		}
+		var reply [1]byte
+		if _, err := io.ReadFull(netConn, reply[:]); err != nil {
+			return nil, nil, err
+		}
+		if reply[0] != 'S' {
+			return nil, nil, errors.New("the server doesn't support TLS")
+		}
+		tlsConn := tls.Client(netConn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, nil, err
+		}
+		netConn = tlsConn
+	}
+
+	p := &postgres{r: bufio.NewReader(netConn), w: bufio.NewWriter(netConn)}
+	p.startup(opts)
+	if err := p.flush(); err != nil {
+		return nil, nil, err
+	}
+	if err := p.authenticate(opts); err != nil {
+		return nil, nil, err
+	}
+	// the parameters of the server and the key of the session aren't needed
+	for {
+		typ, msg, err := p.receive()
+		if err != nil {
+			return nil, nil, err
+		}
+		switch typ {
+		case 'Z':
+			return p, netConn, nil
+		case 'E':
+			return nil, nil, pgError(msg)
+		}
+	}
+}
+
+func (p *postgres) startup(opts poolOptions) {
+	p.buf = append(p.buf[:0], 0, 0, 0, 0)
+	p.buf = binary.BigEndian.AppendUint32(p.buf, pgProtocolVersion)
+	for _, param := range [][2]string{
+		{"user", opts.User}, {"database", opts.Database}, {"application_name", "k6"}, {"client_encoding", "UTF8"},
+	} {
+		if param[1] == "" {
+			continue
+		}
+		p.buf = append(append(p.buf, param[0]...), 0)
+		p.buf = append(append(p.buf, param[1]...), 0)
+	}
+	p.buf = append(p.buf, 0)
+	binary.BigEndian.PutUint32(p.buf, uint32(len(p.buf))) //nolint:gosec
+	_, _ = p.w.Write(p.buf)
+}
+
+// authenticate answers the authentication requests of the server, with the
+// cleartext, md5 or SCRAM-SHA-256 methods.
+func (p *postgres) authenticate(opts poolOptions) error {
+	var scram *scramClient
+	for {
+		typ, msg, err := p.receive()
+		if err != nil {
+			return err
+		}
+		if typ == 'E' {
+			return pgError(msg)
+		}
+		if typ != 'R' || len(msg) < 4 {
+			return fmt.Errorf("unexpected message %q during the authentication", typ)
+		}
+		method, data := binary.BigEndian.Uint32(msg), msg[4:]
+		switch method {
+		case pgAuthOK:
+			return nil
+		case pgAuthCleartext:
+			p.begin('p')
+			p.cstring(opts.Password)
+		case pgAuthMD5:
+			if len(data) < 4 {
+				return errors.New("invalid md5 authentication request")
+			}
+			p.begin('p')
+			p.cstring("md5" + md5Hex(md5Hex(opts.Password+opts.User)+string(data[:4])))
+		case pgAuthSASL:
+			if !containsCString(data, pgScramSHA256) {
+				return errors.New("the server doesn't support the SCRAM-SHA-256 authentication")
+			}
+			scram = newScramClient("", opts.Password)
+			first := scram.first()
+			p.begin('p')
+			p.cstring(pgScramSHA256)
+			p.int32(int32(len(first))) //nolint:gosec
+			p.buf = append(p.buf, first...)
+		case pgAuthSASLContinue:
+			if scram == nil {
+				return errors.New("unexpected SASL continuation")
+			}
+			final, err := scram.final(string(data))
+			if err != nil {
+				return err
+			}
+			p.begin('p')
+			p.buf = append(p.buf, final...)
+		case pgAuthSASLFinal:
+			if scram == nil {
+				return errors.New("unexpected SASL completion")
+			}
+			if err := scram.verify(string(data)); err != nil {
+				return err
+			}
+			continue
+		default:
+			return fmt.Errorf("unsupported authentication method %d", method)
+		}
+		if err := p.send(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *postgres) query(query string, args []any) (*result, error) {
+	p.begin('P')
+	p.cstring("")
+	p.cstring(query)
+	p.int16(0)
+	p.end()
+
+	p.begin('B')
+	p.cstring("")
+	p.cstring("")
+	p.int16(0)
+	p.int16(int16(len(args))) //nolint:gosec
+	for i, arg := range args {
+		v, ok, err := pgArg(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %d: %w", i+1, err)
+		}
+		if !ok {
+			p.int32(-1)
+			continue
+		}
+		p.int32(int32(len(v))) //nolint:gosec
+		p.buf = append(p.buf, v...)
+	}
+	p.int16(0)
+	p.end()
+
+	p.begin('D')
+	p.buf = append(p.buf, 'P')
+	p.cstring("")
+	p.end()
+
+	p.begin('E')
+	p.cstring("")
+	p.int32(0)
+	p.end()
+
+	p.begin('S')
+	if err := p.send(); err != nil {
+		return nil, err
+	}
+
+	res := &result{}
+	var queryErr error
+	for {
+		typ, msg, err := p.receive()
+		if err != nil {
+			return nil, err
+		}
+		switch typ {
+		case 'T':
+			if res.columns, err = pgColumns(msg); err != nil {
+				return nil, err
+			}
+		case 'D':
+			row, err := pgRow(msg)
+			if err != nil {
+				return nil, err
+			}
+			res.rows = append(res.rows, row)
+		case 'C':
+			tag := strings.TrimRight(string(msg), "\x00")
+			if i := strings.LastIndexByte(tag, ' '); i >= 0 {
+				res.rowsAffected, _ = strconv.ParseInt(tag[i+1:], 10, 64)
+			}
+		case 'E':
+			queryErr = pgError(msg)
+		case 'Z':
+			if queryErr != nil {
+				return nil, queryErr
+			}
+			return res, nil
+		}
+	}
+}
+
+func (p *postgres) terminate() error {
+	p.begin('X')
+	return p.send()
+}
+
+// begin starts a message of the type, its length is set by end.
+func (p *postgres) begin(typ byte) {
+	p.buf = append(p.buf[:0], typ, 0, 0, 0, 0)
+}
+
+// end sets the length of the message and buffers it.
+func (p *postgres) end() {
+	binary.BigEndian.PutUint32(p.buf[1:], uint32(len(p.buf)-1)) //nolint:gosec
+	_, _ = p.w.Write(p.buf)
+}
+
+// send ends the message and sends the buffered ones.
+func (p *postgres) send() error {
+	p.end()
+	return p.flush()
+}
+
+func (p *postgres) flush() error {
+	return p.w.Flush()
+}
+
+func (p *postgres) cstring(s string) {
+	p.buf = append(append(p.buf, s...), 0)
+}
+
+func (p *postgres) int16(v int16) {
+	p.buf = binary.BigEndian.AppendUint16(p.buf, uint16(v)) //nolint:gosec
+}
+
+func (p *postgres) int32(v int32) {
+	p.buf = binary.BigEndian.AppendUint32(p.buf, uint32(v)) //nolint:gosec
+}
+
+// receive reads a message, skipping the notices and notifications.
+func (p *postgres) receive() (byte, []byte, error) {
+	for {
+		var header [5]byte
+		if _, err := io.ReadFull(p.r, header[:]); err != nil {
+			return 0, nil, err
+		}
+		size := int(binary.BigEndian.Uint32(header[1:]))
+		if size < 4 || size > maxMessageSize {
+			return 0, nil, fmt.Errorf("invalid message length %d", size)
+		}
+		msg := make([]byte, size-4)
+		if _, err := io.ReadFull(p.r, msg); err != nil {
+			return 0, nil, err
+		}
+		if header[0] == 'N' || header[0] == 'A' {
+			continue
+		}
+		return header[0], msg, nil
+	}
+}
+
+// pgArg formats an argument in the text format. It returns false for NULL.
+func pgArg(arg any) (string, bool, error) {
+	switch v := arg.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return v, true, nil
+	case bool:
+		return strconv.FormatBool(v), true, nil
+	case int64:
+		return strconv.FormatInt(v, 10), true, nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), true, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true, nil
+	case []byte:
+		return `\x` + hex.EncodeToString(v), true, nil
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		return string(b), err == nil, err
+	default:
+		return "", false, fmt.Errorf("unsupported type %T", arg)
+	}
+}
+
+func pgColumns(msg []byte) ([]column, error) {
+	if len(msg) < 2 {
+		return nil, errors.New("invalid row description")
+	}
+	count := int(binary.BigEndian.Uint16(msg))
+	msg = msg[2:]
+	columns := make([]column, count)
+	for i := range columns {
+		end := indexZero(msg)
+		if end < 0 || len(msg) < end+19 {
+			return nil, errors.New("invalid row description")
+		}
+		columns[i].name = string(msg[:end])
+		columns[i].kind = pgTypeKinds[binary.BigEndian.Uint32(msg[end+7:])]
+		msg = msg[end+19:]
+	}
+	return columns, nil
+}
+
+func pgRow(msg []byte) ([][]byte, error) {
+	if len(msg) < 2 {
+		return nil, errors.New("invalid data row")
+	}
+	count := int(binary.BigEndian.Uint16(msg))
+	msg = msg[2:]
+	row := make([][]byte, count)
+	for i := range row {
+		if len(msg) < 4 {
+			return nil, errors.New("invalid data row")
+		}
+		size := int32(binary.BigEndian.Uint32(msg)) //nolint:gosec
+		msg = msg[4:]
+		if size < 0 {
+			continue
+		}
+		if len(msg) < int(size) {
+			return nil, errors.New("invalid data row")
+		}
+		row[i] = msg[:size:size]
+		msg = msg[size:]
+	}
+	return row, nil
+}
+
+// pgError returns the error of an ErrorResponse, with its SQLSTATE and message.
+func pgError(msg []byte) error {
+	err := &ServerError{}
+	for len(msg) > 1 {
+		field := msg[0]
+		end := indexZero(msg[1:])
+		if end < 0 {
+			break
+		}
+		value := string(msg[1 : end+1])
+		switch field {
+		case 'C':
+			err.Code = value
+		case 'M':
+			err.Message = value
+		}
+		msg = msg[end+2:]
+	}
+	return err
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s)) //nolint:gosec
+	return hex.EncodeToString(sum[:])
+}
+
+func indexZero(b []byte) int {
+	for i, c := range b {
+		if c == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// containsCString returns whether the list of null-terminated strings has s.
+func containsCString(list []byte, s string) bool {
+	for len(list) > 0 {
+		end := indexZero(list)
+		if end <= 0 {
+			return false
+		}
+		if string(list[:end]) == s {
+			return true
+		}
+		list = list[end+1:]
+	}
+	return false
+}
+
+// scramClient is the client side of the SCRAM-SHA-256 authentication, as
+// defined in RFC 5802 and RFC 7677, without channel binding.
+type scramClient struct {
+	user     string
+	password string
+	nonce    string
+
+	clientFirstBare string
+	authMessage     string
+	saltedPassword  []byte
+}
+
+// newScramClient returns a client for the credentials. Postgres ignores the
+// user of SCRAM, it uses the one of the startup message.
+func newScramClient(user, password string) *scramClient {
+	nonce := make([]byte, 18)
+	_, _ = rand.Read(nonce)
+	return &scramClient{user: user, password: password, nonce: base64.StdEncoding.EncodeToString(nonce)}
+}
+
+// first returns the first message of the client.
+func (s *scramClient) first() string {
+	s.clientFirstBare = "n=" + s.user + ",r=" + s.nonce
+	return "n,," + s.clientFirstBare
+}
+
+// final returns the final message of the client, with its proof, in reply
+// to the first message of the server.
+func (s *scramClient) final(serverFirst string) (string, error) {
+	attrs := scramAttributes(serverFirst)
+	nonce, salt := attrs["r"], attrs["s"]
+	iterations, err := strconv.Atoi(attrs["i"])
+	if err != nil || iterations <= 0 || !strings.HasPrefix(nonce, s.nonce) || salt == "" {
+		return "", errors.New("invalid SCRAM message of the server")
+	}
+	saltBytes, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return "", fmt.Errorf("invalid SCRAM salt: %w", err)
+	}
+	s.saltedPassword, err = pbkdf2.Key(sha256.New, s.password, saltBytes, iterations, sha256.Size)
+	if err != nil {
+		return "", err
+	}
+
+	withoutProof := "c=biws,r=" + nonce
+	s.authMessage = s.clientFirstBare + "," + serverFirst + "," + withoutProof
+	clientKey := hmacSHA256(s.saltedPassword, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	proof := hmacSHA256(storedKey[:], s.authMessage)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+	return withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+// verify checks the signature of the server in its final message.
+func (s *scramClient) verify(serverFinal string) error {
+	attrs := scramAttributes(serverFinal)
+	if e, ok := attrs["e"]; ok {
+		return fmt.Errorf("the SCRAM authentication failed: %s", e)
+	}
+	signature, err := base64.StdEncoding.DecodeString(attrs["v"])
+	if err != nil {
+		return fmt.Errorf("invalid SCRAM signature: %w", err)
+	}
+	serverKey := hmacSHA256(s.saltedPassword, "Server Key")
+	if !hmac.Equal(signature, hmacSHA256(serverKey, s.authMessage)) {
+		return errors.New("the SCRAM signature of the server is invalid")
+	}
+	return nil
+}
+
+func scramAttributes(msg string) map[string]string {
+	attrs := make(map[string]string)
+	for _, attr := range strings.Split(msg, ",") {
+		if k, v, ok := strings.Cut(attr, "="); ok {
+			attrs[k] = v
+		}
+	}
+	return attrs
+}
+
+func hmacSHA256(key []byte, msg string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(msg))
+	return mac.Sum(nil)
+}
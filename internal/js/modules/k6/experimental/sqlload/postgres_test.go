@@ -0,0 +1,227 @@
+package sqlload
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePostgres is a Postgres server that authenticates with md5 and answers
+// the queries starting with SELECT with a row of their arguments, the ones
+// starting with FAIL with an error and the others with INSERT 0 1.
+type fakePostgres struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	conns   int
+	busy    int
+	maxBusy int
+	queries []string
+	args    [][]string
+}
+
+func newFakePostgres(t *testing.T) *fakePostgres {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakePostgres{listener: l}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakePostgres) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port //nolint:forcetypeassert
+}
+
+func (s *fakePostgres) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	s.mu.Lock()
+	s.conns++
+	s.mu.Unlock()
+
+	r := bufio.NewReader(conn)
+	send := func(typ byte, payload ...[]byte) {
+		var body []byte
+		for _, p := range payload {
+			body = append(body, p...)
+		}
+		msg := append([]byte{typ}, binary.BigEndian.AppendUint32(nil, uint32(len(body)+4))...) //nolint:gosec
+		_, _ = conn.Write(append(msg, body...))
+	}
+	u32 := func(v uint32) []byte { return binary.BigEndian.AppendUint32(nil, v) }
+	u16 := func(v uint16) []byte { return binary.BigEndian.AppendUint16(nil, v) }
+
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return
+	}
+	startup := make([]byte, binary.BigEndian.Uint32(size[:])-4)
+	if _, err := io.ReadFull(r, startup); err != nil {
+		return
+	}
+	params := strings.Split(string(startup[4:]), "\x00")
+	user := ""
+	for i := 0; i+1 < len(params); i += 2 {
+		if params[i] == "user" {
+			user = params[i+1]
+		}
+	}
+	send('R', u32(pgAuthMD5), []byte("salt"))
+
+	receive := func() (byte, []byte, bool) {
+		var header [5]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return 0, nil, false
+		}
+		msg := make([]byte, binary.BigEndian.Uint32(header[1:])-4)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			return 0, nil, false
+		}
+		return header[0], msg, true
+	}
+	_, password, ok := receive()
+	if !ok {
+		return
+	}
+	if string(password) != "md5"+md5Hex(md5Hex("secret"+user)+"salt")+"\x00" {
+		send('E', []byte("SFATAL\x00C28P01\x00Mpassword authentication failed\x00\x00"))
+		return
+	}
+	send('R', u32(pgAuthOK))
+	send('S', []byte("server_version\x0016\x00"))
+	send('Z', []byte("I"))
+
+	var query string
+	var args []string
+	for {
+		typ, msg, ok := receive()
+		if !ok {
+			return
+		}
+		switch typ {
+		case 'P':
+			query = strings.Split(string(msg), "\x00")[1]
+		case 'B':
+			args = nil
+			msg = msg[1+1+2:] // the names of the portal and the statement, and the formats
+			count := int(binary.BigEndian.Uint16(msg))
+			msg = msg[2:]
+			for range count {
+				n := int32(binary.BigEndian.Uint32(msg)) //nolint:gosec
+				msg = msg[4:]
+				if n < 0 {
+					args = append(args, "NULL")
+					continue
+				}
+				args = append(args, string(msg[:n]))
+				msg = msg[n:]
+			}
+			s.mu.Lock()
+			s.queries = append(s.queries, query)
+			s.args = append(s.args, args)
+			s.busy++
+			s.maxBusy = max(s.maxBusy, s.busy)
+			s.mu.Unlock()
+		case 'S':
+			switch {
+			case strings.HasPrefix(query, "FAIL"):
+				send('E', []byte("SERROR\x00C42601\x00Msyntax error\x00\x00"))
+			case strings.HasPrefix(query, "SELECT"):
+				send('1')
+				send('2')
+				desc := u16(3)
+				for _, c := range []struct {
+					name string
+					oid  uint32
+				}{{"id", 23}, {"name", 25}, {"ok", 16}} {
+					desc = append(desc, c.name+"\x00"...)
+					desc = append(append(append(desc, u32(0)...), u16(0)...), u32(c.oid)...)
+					desc = append(append(append(desc, u16(4)...), u32(0)...), u16(0)...)
+				}
+				send('T', desc)
+				row := u16(3)
+				for _, v := range []string{"42", args[0], "t"} {
+					row = append(append(row, u32(uint32(len(v)))...), v...) //nolint:gosec
+				}
+				send('D', row)
+				send('D', append(append(append(u16(3), u32(1)...), '7'), append(u32(0xffffffff), append(u32(1), 'f')...)...))
+				send('C', []byte("SELECT 2\x00"))
+			default:
+				send('1')
+				send('2')
+				send('n')
+				send('C', []byte("INSERT 0 1\x00"))
+			}
+			// the connection is released by the client once it's ready for
+			// the next query, so it isn't counted as busy from then on
+			s.mu.Lock()
+			s.busy--
+			s.mu.Unlock()
+			send('Z', []byte("I"))
+		case 'X':
+			return
+		}
+	}
+}
+
+func TestScram(t *testing.T) {
+	t.Parallel()
+
+	// the example of RFC 7677
+	s := &scramClient{user: "user", password: "pencil", nonce: "rOprNGfwEbeRWgbNEkqO"}
+	assert.Equal(t, "n,,n=user,r=rOprNGfwEbeRWgbNEkqO", s.first())
+	final, err := s.final("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")
+	require.NoError(t, err)
+	assert.Equal(t, "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,"+
+		"p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=", final)
+	require.NoError(t, s.verify("v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4="))
+	require.ErrorContains(t, s.verify("v=AAAA"), "signature of the server is invalid")
+
+	_, err = s.final("r=other,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")
+	require.ErrorContains(t, err, "invalid SCRAM message")
+}
+
+func TestPgArg(t *testing.T) {
+	t.Parallel()
+
+	for arg, expected := range map[any]string{
+		"text":    "text",
+		int64(-3): "-3",
+		1.5:       "1.5",
+		true:      "true",
+		"":        "",
+	} {
+		v, ok, err := pgArg(arg)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, expected, v)
+	}
+	v, _, err := pgArg([]byte{1, 0xab})
+	require.NoError(t, err)
+	assert.Equal(t, `\x01ab`, v)
+	v, _, err = pgArg(map[string]any{"a": []any{int64(1)}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":[1]}`, v)
+	_, ok, err := pgArg(nil)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, _, err = pgArg(struct{}{})
+	require.ErrorContains(t, err, "unsupported type")
+}
@@ -0,0 +1,186 @@
+// Package tls provides helpers to assert on the TLS details of HTTP responses,
+// like the expiry of the certificates or the stapled OCSP response.
+package tls
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/lib/netext/httpext"
+)
+
+type (
+	// RootModule is the global module instance that will create instances of our
+	// module for each VU.
+	RootModule struct{}
+
+	// ModuleInstance represents an instance of the tls module for a single VU.
+	ModuleInstance struct {
+		vu modules.VU
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// New returns a pointer to a new [RootModule] instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface and returns a new
+// instance of our module for the given VU.
+func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	return &ModuleInstance{vu: vu}
+}
+
+// Exports implements the modules.Module interface and returns the exports of
+// our module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"daysToExpiry": mi.DaysToExpiry,
+			"verify":       mi.Verify,
+		},
+	}
+}
+
+// Expectations are the expectations that Verify checks, the ones that aren't
+// set aren't checked.
+type Expectations struct {
+	// MinDaysToExpiry is the minimum number of days until the first of the
+	// certificates of the chain expires.
+	MinDaysToExpiry *float64 `js:"minDaysToExpiry"`
+	// Version is the TLS version, e.g. http.TLS_1_3.
+	Version string `js:"version"`
+	// OCSPStapled is whether the server stapled an OCSP response.
+	OCSPStapled *bool `js:"ocspStapled"`
+	// OCSPStatus is the status of the stapled OCSP response, e.g.
+	// http.OCSP_STATUS_GOOD.
+	OCSPStatus string `js:"ocspStatus"`
+	// Subject and Issuer are contained by the subject and the issuer of the
+	// certificate of the server.
+	Subject string `js:"subject"`
+	Issuer  string `js:"issuer"`
+	// DNSName is one of the DNS names of the certificate of the server.
+	DNSName string `js:"dnsName"`
+}
+
+// DaysToExpiry returns the days until the first of the certificates of the
+// chain of the response expires, or null if the response wasn't over TLS.
+func (mi *ModuleInstance) DaysToExpiry(v sobek.Value) sobek.Value {
+	rt := mi.vu.Runtime()
+	details, err := tlsDetails(rt, v)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	if details == nil || len(details.PeerCertificates) == 0 {
+		return sobek.Null()
+	}
+	days := details.PeerCertificates[0].DaysToExpiry
+	for _, cert := range details.PeerCertificates[1:] {
+		days = min(days, cert.DaysToExpiry)
+	}
+	return rt.ToValue(days)
+}
+
+// Verify returns whether the TLS details of the response meet the
+// expectations. It accepts either a response or its tls property, and it
+// always returns false for the responses that weren't over TLS.
+func (mi *ModuleInstance) Verify(v sobek.Value, expectations sobek.Value) bool {
+	rt := mi.vu.Runtime()
+	details, err := tlsDetails(rt, v)
+	if err != nil {
+		common.Throw(rt, err)
+	}
+	var exp Expectations
+	if !common.IsNullish(expectations) {
+		if err := rt.ExportTo(expectations, &exp); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+	if details == nil {
+		return false
+	}
+	return verify(details, exp)
+}
+
+func verify(details *httpext.ResponseTLS, exp Expectations) bool {
+	if exp.Version != "" && details.Version != exp.Version {
+		return false
+	}
+	if exp.OCSPStapled != nil && details.OCSP.Stapled != *exp.OCSPStapled {
+		return false
+	}
+	if exp.OCSPStatus != "" && details.OCSP.Status != exp.OCSPStatus {
+		return false
+	}
+
+	if exp.MinDaysToExpiry == nil && exp.Subject == "" && exp.Issuer == "" && exp.DNSName == "" {
+		return true
+	}
+	if len(details.PeerCertificates) == 0 {
+		return false
+	}
+	if exp.MinDaysToExpiry != nil {
+		for _, cert := range details.PeerCertificates {
+			if cert.DaysToExpiry < *exp.MinDaysToExpiry {
+				return false
+			}
+		}
+	}
+	leaf := details.PeerCertificates[0]
+	if !strings.Contains(leaf.Subject, exp.Subject) || !strings.Contains(leaf.Issuer, exp.Issuer) {
+		return false
+	}
+	if exp.DNSName != "" && !matchesDNSName(leaf.DNSNames, exp.DNSName) {
+		return false
+	}
+	return true
+}
+
+// matchesDNSName returns whether the name matches one of the DNS names of a
+// certificate, including the wildcard ones.
+func matchesDNSName(dnsNames []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, dnsName := range dnsNames {
+		dnsName = strings.ToLower(dnsName)
+		if dnsName == name {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(dnsName, "*."); ok {
+			if _, rest, found := strings.Cut(name, "."); found && rest == suffix {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tlsDetails returns the TLS details of a response, or of its tls property.
+// It returns nil if the response wasn't over TLS.
+func tlsDetails(rt *sobek.Runtime, v sobek.Value) (*httpext.ResponseTLS, error) {
+	if common.IsNullish(v) {
+		return nil, errors.New("a response or its tls property is required")
+	}
+	obj := v.ToObject(rt)
+	if details := obj.Get("tls"); details != nil {
+		if common.IsNullish(details) {
+			return nil, nil //nolint:nilnil
+		}
+		v = details
+	}
+	if details, ok := v.Export().(httpext.ResponseTLS); ok {
+		if details.Version == "" {
+			return nil, nil //nolint:nilnil
+		}
+		return &details, nil
+	}
+	return nil, errors.New("the value isn't a response or its tls property")
+}
@@ -0,0 +1,98 @@
+package tls
+
+import (
+	"testing"
+
+	"github.com/grafana/sobek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/lib/netext/httpext"
+)
+
+func newTestRuntime(t *testing.T) *modulestest.Runtime {
+	t.Helper()
+
+	rt := modulestest.NewRuntime(t)
+	m, ok := New().NewModuleInstance(rt.VU).(*ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, rt.VU.Runtime().Set("tls", m.Exports().Named))
+	require.NoError(t, rt.VU.Runtime().Set("res", map[string]any{
+		"tls": httpext.ResponseTLS{
+			Version:     netext.TLS_1_3,
+			CipherSuite: "TLS_AES_128_GCM_SHA256",
+			OCSP:        netext.OCSP{Status: netext.OCSP_STATUS_GOOD, Stapled: true},
+			PeerCertificates: []netext.Certificate{
+				{
+					Subject:      "CN=www.example.com,O=Example",
+					Issuer:       "CN=Example CA",
+					DNSNames:     []string{"example.com", "*.example.com"},
+					DaysToExpiry: 45.5,
+				},
+				{Subject: "CN=Example CA", Issuer: "CN=Example Root", DaysToExpiry: 30.25},
+			},
+		},
+	}))
+	require.NoError(t, rt.VU.Runtime().Set("plainRes", map[string]any{
+		"tls": httpext.ResponseTLS{PeerCertificates: []netext.Certificate{}},
+	}))
+	return rt
+}
+
+func TestDaysToExpiry(t *testing.T) {
+	t.Parallel()
+
+	rt := newTestRuntime(t)
+	v, err := rt.VU.Runtime().RunString(`tls.daysToExpiry(res)`)
+	require.NoError(t, err)
+	assert.Equal(t, 30.25, v.ToFloat())
+
+	v, err = rt.VU.Runtime().RunString(`tls.daysToExpiry(res.tls)`)
+	require.NoError(t, err)
+	assert.Equal(t, 30.25, v.ToFloat())
+
+	v, err = rt.VU.Runtime().RunString(`tls.daysToExpiry(plainRes)`)
+	require.NoError(t, err)
+	assert.True(t, sobek.IsNull(v))
+
+	_, err = rt.VU.Runtime().RunString(`tls.daysToExpiry({})`)
+	require.ErrorContains(t, err, "the value isn't a response or its tls property")
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]bool{
+		`{}`:                      true,
+		`{ minDaysToExpiry: 30 }`: true,
+		`{ minDaysToExpiry: 31 }`: false,
+		`{ version: "tls1.3", ocspStapled: true }`:      true,
+		`{ version: "tls1.2" }`:                         false,
+		`{ ocspStatus: "good" }`:                        true,
+		`{ ocspStatus: "revoked" }`:                     false,
+		`{ ocspStapled: false }`:                        false,
+		`{ subject: "www.example.com" }`:                true,
+		`{ subject: "other.example.com" }`:              false,
+		`{ issuer: "Example CA" }`:                      true,
+		`{ dnsName: "API.example.com" }`:                true,
+		`{ dnsName: "example.com" }`:                    true,
+		`{ dnsName: "a.b.example.com" }`:                false,
+		`{ dnsName: "example.org", version: "tls1.3" }`: false,
+	}
+	for expectations, want := range tests {
+		t.Run(expectations, func(t *testing.T) {
+			t.Parallel()
+
+			rt := newTestRuntime(t)
+			v, err := rt.VU.Runtime().RunString(`tls.verify(res, ` + expectations + `)`)
+			require.NoError(t, err)
+			assert.Equal(t, want, v.ToBoolean())
+
+			v, err = rt.VU.Runtime().RunString(`tls.verify(plainRes, ` + expectations + `)`)
+			require.NoError(t, err)
+			assert.False(t, v.ToBoolean())
+		})
+	}
+}
@@ -1,16 +1,21 @@
 package websockets
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/http/cookiejar"
 	"strings"
+	"time"
 
 	"github.com/grafana/sobek"
 
+	"go.k6.io/k6/internal/js/tc55/abort"
 	"go.k6.io/k6/js/common"
 	httpModule "go.k6.io/k6/js/modules/k6/http"
 	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
 
@@ -21,6 +26,43 @@ type wsParams struct {
 	tagsAndMeta       *metrics.TagsAndMeta
 	enableCompression bool
 	subprocotols      []string
+
+	// pingInterval enables automatic pings, a pong not received within
+	// pongTimeout after a ping is counted as a miss.
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+
+	// reconnect is nil if dropped connections shouldn't be re-established.
+	reconnect *reconnectPolicy
+
+	// signal, if set, cancels the connection or closes it when it's aborted.
+	signal *abort.Signal
+}
+
+// reconnectPolicy configures how a dropped connection is re-established.
+type reconnectPolicy struct {
+	maxRetries   int64
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	multiplier   float64
+}
+
+func defaultReconnectPolicy() *reconnectPolicy {
+	return &reconnectPolicy{
+		maxRetries:   3,
+		initialDelay: time.Second,
+		maxDelay:     30 * time.Second,
+		multiplier:   2,
+	}
+}
+
+// delay returns how long to wait before the given (zero-based) reconnect attempt.
+func (p *reconnectPolicy) delay(attempt int64) time.Duration {
+	d := float64(p.initialDelay) * math.Pow(p.multiplier, float64(attempt))
+	if d > float64(p.maxDelay) {
+		return p.maxDelay
+	}
+	return time.Duration(d)
 }
 
 // buildParams builds WebSocket params and configure some of them
@@ -78,10 +120,90 @@ func buildParams(state *lib.State, rt *sobek.Runtime, raw sobek.Value) (*wsParam
 			}
 
 			parsed.enableCompression = true
+		case "pingInterval", "pongTimeout":
+			d, err := types.GetDurationValue(params.Get(k).Export())
+			if err != nil {
+				return nil, fmt.Errorf("invalid WebSocket's %s option: %w", k, err)
+			}
+			if d <= 0 {
+				return nil, fmt.Errorf("the WebSocket's %s option should be positive", k)
+			}
+			if k == "pingInterval" {
+				parsed.pingInterval = d
+			} else {
+				parsed.pongTimeout = d
+			}
+		case "reconnect":
+			policy, err := parseReconnectPolicy(rt, params.Get(k))
+			if err != nil {
+				return nil, fmt.Errorf("invalid WebSocket's reconnect option: %w", err)
+			}
+			parsed.reconnect = policy
+		case "signal":
+			if common.IsNullish(params.Get(k)) {
+				continue
+			}
+			signal, err := abort.FromValue(rt, params.Get(k))
+			if err != nil {
+				return nil, fmt.Errorf("invalid WebSocket's signal option: %w", err)
+			}
+			parsed.signal = signal
 		default:
 			return nil, fmt.Errorf("unknown WebSocket's option %s", k)
 		}
 	}
 
+	if parsed.pongTimeout > 0 && parsed.pingInterval == 0 {
+		return nil, errors.New("the WebSocket's pongTimeout option requires pingInterval to be set")
+	}
+
 	return parsed, nil
 }
+
+// parseReconnectPolicy parses the reconnect option, which can be either a
+// boolean enabling the default policy or an object overriding parts of it.
+func parseReconnectPolicy(rt *sobek.Runtime, v sobek.Value) (*reconnectPolicy, error) {
+	if common.IsNullish(v) {
+		return nil, nil //nolint:nilnil
+	}
+	if b, ok := v.Export().(bool); ok {
+		if !b {
+			return nil, nil //nolint:nilnil
+		}
+		return defaultReconnectPolicy(), nil
+	}
+
+	policy := defaultReconnectPolicy()
+	obj := v.ToObject(rt)
+	for _, k := range obj.Keys() {
+		val := obj.Get(k)
+		switch k {
+		case "maxRetries":
+			policy.maxRetries = val.ToInteger()
+			if policy.maxRetries < 1 {
+				return nil, errors.New("maxRetries should be at least 1")
+			}
+		case "initialDelay", "maxDelay":
+			d, err := types.GetDurationValue(val.Export())
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", k, err)
+			}
+			if d < 0 {
+				return nil, fmt.Errorf("%s can't be negative", k)
+			}
+			if k == "initialDelay" {
+				policy.initialDelay = d
+			} else {
+				policy.maxDelay = d
+			}
+		case "multiplier":
+			policy.multiplier = val.ToFloat()
+			if policy.multiplier < 1 {
+				return nil, errors.New("multiplier should be at least 1")
+			}
+		default:
+			return nil, fmt.Errorf("unknown option %s", k)
+		}
+	}
+	return policy, nil
+}
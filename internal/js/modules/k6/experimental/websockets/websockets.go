@@ -21,6 +21,7 @@ import (
 
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
 
@@ -77,6 +78,7 @@ type webSocket struct {
 	blobConstructor sobek.Value
 
 	url            *url.URL
+	params         *wsParams
 	conn           *websocket.Conn
 	tagsAndMeta    *metrics.TagsAndMeta
 	tq             *taskqueue.TaskQueue
@@ -91,6 +93,17 @@ type webSocket struct {
 
 	sendPings ping
 
+	// reconnecting is true while a dropped connection is being re-established,
+	// stopReconnect is closed if the WebSocket is closed in the meantime.
+	reconnecting  bool
+	stopReconnect chan struct{}
+
+	// dialCtx is canceled with errConnectionCanceled when the signal param
+	// is aborted, and unsubscribeSignal stops listening for it.
+	dialCtx           context.Context
+	cancelDial        context.CancelCauseFunc
+	unsubscribeSignal func()
+
 	// fields that should be seen by js only be updated on the event loop
 	readyState     ReadyState
 	bufferedAmount int
@@ -134,6 +147,7 @@ func (r *WebSocketsAPI) websocket(c sobek.ConstructorCall) *sobek.Object {
 		vu:              r.vu,
 		blobConstructor: r.blobConstructor,
 		url:             url,
+		params:          params,
 		tq:              taskqueue.New(r.vu.RegisterCallback),
 		readyState:      CONNECTING,
 		builtinMetrics:  r.vu.State().BuiltinMetrics,
@@ -146,10 +160,16 @@ func (r *WebSocketsAPI) websocket(c sobek.ConstructorCall) *sobek.Object {
 		binaryType:      blobBinaryType,
 	}
 
+	w.dialCtx, w.cancelDial = context.WithCancelCause(r.vu.Context())
+	w.unsubscribeSignal = func() {}
+	if params.signal != nil {
+		w.unsubscribeSignal = params.signal.Subscribe(w.abort)
+	}
+
 	// Maybe have this after the goroutine below ?!?
 	defineWebsocket(rt, w)
 
-	go w.establishConnection(params)
+	go w.establishConnection()
 	return w.obj
 }
 
@@ -261,9 +281,32 @@ type message struct {
 }
 
 // documented https://websockets.spec.whatwg.org/#concept-websocket-establish
-func (w *webSocket) establishConnection(params *wsParams) {
+func (w *webSocket) establishConnection() {
+	var connErr error
+	w.conn, w.started, connErr = w.connect(false)
+	if connErr != nil {
+		// Pass the error to the user script before exiting immediately
+		w.tq.Queue(func() error {
+			return w.connectionClosedWithError(connErr)
+		})
+		w.tq.Close()
+		return
+	}
+	go w.loop()
+	w.tq.Queue(func() error {
+		return w.connectionConnected()
+	})
+}
+
+// connect dials the WebSocket server and emits the connection metrics. It
+// returns the connection and when it was started, which are only set by the
+// caller, since a reconnect happens concurrently with the event loop. For the
+// same reason, the metric tags and the negotiated protocol and extensions are
+// only updated on the initial connection.
+func (w *webSocket) connect(reconnect bool) (*websocket.Conn, time.Time, error) {
 	state := w.vu.State()
-	w.started = time.Now()
+	params := w.params
+	started := time.Now()
 	var tlsConfig *tls.Config
 	if state.TLSConfig != nil {
 		tlsConfig = state.TLSConfig.Clone()
@@ -271,10 +314,7 @@ func (w *webSocket) establishConnection(params *wsParams) {
 	}
 	// technically we have to do a fetch request here, so ... uh do normal one ;)
 	wsd := websocket.Dialer{
-		HandshakeTimeout: time.Second * 60, // TODO configurable
-		// Pass a custom net.DialContext function to websocket.Dialer that will substitute
-		// the underlying net.Conn with our own tracked netext.Conn
-		NetDialContext:    state.Dialer.DialContext,
+		HandshakeTimeout:  time.Second * 60, // TODO configurable
 		Proxy:             http.ProxyFromEnvironment,
 		TLSClientConfig:   tlsConfig,
 		EnableCompression: params.enableCompression,
@@ -286,25 +326,54 @@ func (w *webSocket) establishConnection(params *wsParams) {
 		wsd.Jar = params.cookieJar
 	}
 
+	// Pass a custom net.DialContext function to websocket.Dialer that will substitute
+	// the underlying net.Conn with our own tracked netext.Conn.
+	// The handshake isn't canceled with the context, only the dial is, so the
+	// connection is closed if it's canceled before the handshake is done.
+	var stopClosing func() bool
+	wsd.NetDialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := state.Dialer.DialContext(ctx, network, addr)
+		if err == nil {
+			stopClosing = context.AfterFunc(w.dialCtx, func() { _ = conn.Close() })
+		}
+		return conn, err
+	}
+
 	ctx := w.vu.Context()
 	start := time.Now()
-	conn, httpResponse, connErr := wsd.DialContext(ctx, w.url.String(), params.headers)
+	conn, httpResponse, connErr := wsd.DialContext(w.dialCtx, w.url.String(), params.headers)
+	if stopClosing != nil {
+		stopClosing()
+	}
+	if connErr != nil && errors.Is(context.Cause(w.dialCtx), errConnectionCanceled) {
+		connErr = fmt.Errorf("%w: %w", errConnectionCanceled, connErr)
+	}
 	connectionEnd := time.Now()
 	connectionDuration := metrics.D(connectionEnd.Sub(start))
+	if httpResponse != nil {
+		defer func() {
+			_ = httpResponse.Body.Close()
+		}()
+	}
 
 	systemTags := state.Options.SystemTags
+	if reconnect {
+		if connErr == nil {
+			w.emitConnectionMetrics(ctx, start, connectionDuration)
+		}
+		return conn, started, connErr
+	}
 
 	if conn != nil && conn.RemoteAddr() != nil {
 		if ip, _, err := net.SplitHostPort(conn.RemoteAddr().String()); err == nil {
 			w.tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagIP, ip)
 		}
+		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+			w.tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagIPFamily, types.IPFamily(addr.IP))
+		}
 	}
 
 	if httpResponse != nil {
-		defer func() {
-			_ = httpResponse.Body.Close()
-		}()
-
 		w.tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagStatus, strconv.Itoa(httpResponse.StatusCode))
 		if conn != nil {
 			w.protocol = conn.Subprotocol()
@@ -312,7 +381,6 @@ func (w *webSocket) establishConnection(params *wsParams) {
 		w.extensions = httpResponse.Header.Values("Sec-WebSocket-Extensions")
 		w.tagsAndMeta.SetSystemTagOrMetaIfEnabled(systemTags, metrics.TagSubproto, w.protocol)
 	}
-	w.conn = conn
 
 	nameTagValue, nameTagManuallySet := params.tagsAndMeta.Tags.Get(metrics.TagName.String())
 	// After k6 v0.41.0, the `name` and `url` tags have the exact same values:
@@ -324,18 +392,7 @@ func (w *webSocket) establishConnection(params *wsParams) {
 	}
 
 	w.emitConnectionMetrics(ctx, start, connectionDuration)
-	if connErr != nil {
-		// Pass the error to the user script before exiting immediately
-		w.tq.Queue(func() error {
-			return w.connectionClosedWithError(connErr)
-		})
-		w.tq.Close()
-		return
-	}
-	go w.loop()
-	w.tq.Queue(func() error {
-		return w.connectionConnected()
-	})
+	return conn, started, connErr
 }
 
 // emitConnectionMetrics emits the metrics for a websocket connection.
@@ -372,8 +429,16 @@ func (w *webSocket) loop() {
 	w.conn.SetPongHandler(func(pingID string) error { pongChan <- pingID; return nil })
 
 	ctx := w.vu.Context()
+	conn := w.conn
 	wg := new(sync.WaitGroup)
 
+	var pingTicker <-chan time.Time
+	if w.params.pingInterval > 0 {
+		ticker := time.NewTicker(w.params.pingInterval)
+		defer ticker.Stop()
+		pingTicker = ticker.C
+	}
+
 	defer func() {
 		metrics.PushIfNotDone(ctx, w.vu.State().Samples, metrics.Sample{
 			TimeSeries: metrics.TimeSeries{
@@ -384,13 +449,18 @@ func (w *webSocket) loop() {
 			Metadata: w.tagsAndMeta.Metadata,
 			Value:    metrics.D(time.Since(w.started)),
 		})
-		_ = w.conn.Close()
+		_ = conn.Close()
 		wg.Wait()
+		// w.done is closed only after reconnecting is set on the event loop
+		if w.reconnecting {
+			w.reconnect()
+			return
+		}
 		w.tq.Close()
 	}()
 	wg.Add(2)
-	go w.readPump(wg)
-	go w.writePump(wg)
+	go w.readPump(conn, wg)
+	go w.writePump(conn, wg)
 
 	ctxDone := ctx.Done()
 	for {
@@ -408,7 +478,7 @@ func (w *webSocket) loop() {
 			// - trigger the `ping` event
 			// - reply with pong (needed when `SetPingHandler` is overwritten)
 			// WriteControl is okay to be concurrent so we don't need to gsend this over writeChannel
-			err := w.conn.WriteControl(websocket.PongMessage, []byte(pingData), time.Now().Add(writeWait))
+			err := conn.WriteControl(websocket.PongMessage, []byte(pingData), time.Now().Add(writeWait))
 			w.tq.Queue(func() error {
 				if err != nil {
 					return w.callErrorListeners(err)
@@ -424,8 +494,59 @@ func (w *webSocket) loop() {
 
 				return w.callEventListeners(events.PONG)
 			})
+
+		case <-pingTicker:
+			w.tq.Queue(w.autoPing)
+		}
+	}
+}
+
+// reconnect re-establishes a dropped connection according to the reconnect
+// policy. It's called after all the goroutines of the dropped connection are
+// done and the new connection is handed over to the event loop.
+func (w *webSocket) reconnect() {
+	ctx := w.vu.Context()
+	policy := w.params.reconnect
+
+	var err error
+	for attempt := int64(0); attempt < policy.maxRetries; attempt++ {
+		timer := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			w.stopReconnecting(nil)
+			return
+		case <-w.stopReconnect:
+			timer.Stop()
+			w.stopReconnecting(nil)
+			return
+		}
+
+		var (
+			conn    *websocket.Conn
+			started time.Time
+		)
+		if conn, started, err = w.connect(true); err == nil {
+			w.tq.Queue(func() error {
+				return w.connectionReconnected(conn, started)
+			})
+			return
 		}
+		w.vu.State().Logger.WithError(err).Debugf("WebSocket reconnect attempt %d to %s failed", attempt+1, w.url)
 	}
+	w.stopReconnecting(err)
+}
+
+// stopReconnecting closes the WebSocket after it couldn't be reconnected or was
+// closed while reconnecting.
+func (w *webSocket) stopReconnecting(err error) {
+	w.tq.Queue(func() error {
+		w.reconnecting = false
+		w.done = make(chan struct{}) // the one of the lost connection is already closed
+		return w.connectionClosedWithError(err)
+	})
+	w.tq.Close()
 }
 
 func (w *webSocket) queueMessage(msg *message) {
@@ -484,10 +605,10 @@ func (w *webSocket) queueMessage(msg *message) {
 	})
 }
 
-func (w *webSocket) readPump(wg *sync.WaitGroup) {
+func (w *webSocket) readPump(conn *websocket.Conn, wg *sync.WaitGroup) {
 	defer wg.Done()
 	for {
-		messageType, data, err := w.conn.ReadMessage()
+		messageType, data, err := conn.ReadMessage()
 		if err == nil {
 			w.queueMessage(&message{
 				mtype: messageType,
@@ -498,6 +619,13 @@ func (w *webSocket) readPump(wg *sync.WaitGroup) {
 			continue
 		}
 
+		// the connection was lost if it was closed without a closing handshake
+		var closeErr *websocket.CloseError
+		lostErr := err
+		if errors.As(err, &closeErr) && closeErr.Code != websocket.CloseAbnormalClosure {
+			lostErr = nil
+		}
+
 		if !websocket.IsUnexpectedCloseError(
 			err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
 			// maybe still log it with debug level?
@@ -506,12 +634,15 @@ func (w *webSocket) readPump(wg *sync.WaitGroup) {
 
 		if err != nil {
 			w.tq.Queue(func() error {
-				_ = w.conn.Close() // TODO fix this
+				_ = conn.Close() // TODO fix this
 				return nil
 			})
 		}
 
 		w.tq.Queue(func() error {
+			if lostErr != nil && w.shouldReconnect() {
+				return w.connectionLost(lostErr)
+			}
 			return w.connectionClosedWithError(err)
 		})
 
@@ -519,7 +650,7 @@ func (w *webSocket) readPump(wg *sync.WaitGroup) {
 	}
 }
 
-func (w *webSocket) writePump(wg *sync.WaitGroup) {
+func (w *webSocket) writePump(conn *websocket.Conn, wg *sync.WaitGroup) {
 	defer wg.Done()
 	wg.Add(1)
 	samplesOutput := w.vu.State().Samples
@@ -537,15 +668,18 @@ func (w *webSocket) writePump(wg *sync.WaitGroup) {
 
 				err := func() error {
 					if msg.mtype != websocket.PingMessage {
-						return w.conn.WriteMessage(msg.mtype, msg.data)
+						return conn.WriteMessage(msg.mtype, msg.data)
 					}
 
 					// WriteControl is concurrently okay
-					return w.conn.WriteControl(msg.mtype, msg.data, msg.t.Add(writeWait))
+					return conn.WriteControl(msg.mtype, msg.data, msg.t.Add(writeWait))
 				}()
 				if err != nil {
 					w.tq.Queue(func() error {
-						_ = w.conn.Close() // TODO fix
+						_ = conn.Close() // TODO fix
+						if w.shouldReconnect() {
+							return w.connectionLost(err)
+						}
 						closeErr := w.connectionClosedWithError(err)
 						return closeErr
 					})
@@ -684,7 +818,11 @@ func isArrayBufferView(rt *sobek.Runtime, v sobek.Value) (bool, error) {
 // Ping sends a ping message over the websocket.
 func (w *webSocket) ping() {
 	w.assertStateOpen()
+	w.sendPing()
+}
 
+// sendPing sends a ping message and returns its ID.
+func (w *webSocket) sendPing() string {
 	pingID := strconv.Itoa(w.sendPings.counter)
 
 	w.writeQueueCh <- message{
@@ -695,6 +833,51 @@ func (w *webSocket) ping() {
 
 	w.sendPings.timestamps[pingID] = time.Now()
 	w.sendPings.counter++
+	return pingID
+}
+
+// autoPing sends one of the pings enabled by the pingInterval param and checks
+// that it's answered within the pongTimeout, if one is set.
+// To be run only on the event loop.
+func (w *webSocket) autoPing() error {
+	if w.readyState != OPEN {
+		return nil
+	}
+	pingID := w.sendPing()
+	if timeout := w.params.pongTimeout; timeout > 0 {
+		conn := w.conn
+		time.AfterFunc(timeout, func() {
+			w.tq.Queue(func() error {
+				w.checkPong(conn, pingID)
+				return nil
+			})
+		})
+	}
+	return nil
+}
+
+// checkPong emits a ws_pong_timeouts sample if the ping with the given ID
+// still wasn't answered. The connection is dropped, in order to be
+// re-established, when a reconnect policy is configured.
+func (w *webSocket) checkPong(conn *websocket.Conn, pingID string) {
+	if _, pending := w.sendPings.timestamps[pingID]; !pending {
+		return
+	}
+	delete(w.sendPings.timestamps, pingID)
+
+	metrics.PushIfNotDone(w.vu.Context(), w.vu.State().Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: w.builtinMetrics.WSPongTimeouts,
+			Tags:   w.tagsAndMeta.Tags,
+		},
+		Time:     time.Now(),
+		Metadata: w.tagsAndMeta.Metadata,
+		Value:    1,
+	})
+
+	if w.shouldReconnect() {
+		_ = conn.Close() // the read pump will handle it as a lost connection
+	}
 }
 
 func (w *webSocket) trackPong(pingID string) {
@@ -708,6 +891,7 @@ func (w *webSocket) trackPong(pingID string) {
 
 		return
 	}
+	delete(w.sendPings.timestamps, pingID)
 
 	metrics.PushIfNotDone(w.vu.Context(), w.vu.State().Samples, metrics.Sample{
 		TimeSeries: metrics.TimeSeries{
@@ -736,6 +920,11 @@ func (w *webSocket) close(code int, reason string) {
 	if w.readyState == CLOSED || w.readyState == CLOSING {
 		return
 	}
+	if w.reconnecting {
+		w.readyState = CLOSING
+		close(w.stopReconnect)
+		return
+	}
 	w.readyState = CLOSING
 	if code == 0 {
 		code = websocket.CloseNormalClosure
@@ -757,20 +946,78 @@ func (w *webSocket) queueClose() {
 // to be run only on the eventloop
 // from https://websockets.spec.whatwg.org/#feedback-from-the-protocol
 func (w *webSocket) connectionConnected() error {
+	if w.reconnecting {
+		w.reconnecting = false
+		if w.readyState == CLOSING {
+			// closed while reconnecting, after the new connection was established
+			w.readyState = OPEN
+			w.close(websocket.CloseNormalClosure, "")
+			return nil
+		}
+	}
 	if w.readyState != CONNECTING {
 		return nil
 	}
 	w.readyState = OPEN
+	if w.params.signal != nil && w.params.signal.Aborted() {
+		// aborted after the connection was established, but before it was open
+		w.close(websocket.CloseNormalClosure, "")
+		return nil
+	}
 	return w.callOpenListeners(time.Now()) // TODO fix time
 }
 
+// errConnectionCanceled is the cause of the cancellation of the connection
+// when the signal param is aborted.
+var errConnectionCanceled = errors.New("WebSocket connection canceled")
+
+// abort cancels the connection if it's being established, or closes it.
+// to be run only on the eventloop
+func (w *webSocket) abort() {
+	w.cancelDial(errConnectionCanceled)
+	if w.readyState == OPEN || w.reconnecting {
+		w.close(websocket.CloseNormalClosure, "")
+	}
+}
+
+// connectionReconnected replaces the dropped connection with the new one and
+// starts its goroutines.
+// to be run only on the eventloop
+func (w *webSocket) connectionReconnected(conn *websocket.Conn, started time.Time) error {
+	w.conn = conn
+	w.started = started
+	w.done = make(chan struct{})
+	go w.loop()
+	return w.connectionConnected()
+}
+
+// to be run only on the eventloop
+func (w *webSocket) shouldReconnect() bool {
+	return w.params.reconnect != nil && w.readyState == OPEN
+}
+
+// connectionLost stops the goroutines of a connection that was dropped, after
+// which it's re-established according to the reconnect policy.
+// to be run only on the eventloop
+func (w *webSocket) connectionLost(err error) error {
+	w.vu.State().Logger.WithError(err).Debugf("WebSocket connection to %s was lost, reconnecting", w.url)
+	w.reconnecting = true
+	w.readyState = CONNECTING
+	w.stopReconnect = make(chan struct{})
+	close(w.done)
+	return nil
+}
+
 // to be run only on the eventloop
 func (w *webSocket) connectionClosedWithError(err error) error {
-	if w.readyState == CLOSED {
+	if w.readyState == CLOSED || w.reconnecting {
+		// events of a lost connection are ignored while it's being re-established
 		return nil
 	}
 	w.readyState = CLOSED
 	close(w.done)
+	w.unsubscribeSignal()
+	w.cancelDial(nil)
 
 	if err != nil {
 		if errList := w.callErrorListeners(err); errList != nil {
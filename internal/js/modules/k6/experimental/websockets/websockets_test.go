@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
@@ -146,6 +147,7 @@ func newTestState(t testing.TB) testState {
 		TLSConfig:      tb.TLSClientConfig,
 		BuiltinMetrics: runtime.BuiltinMetrics,
 		Tags:           lib.NewVUStateTags(runtime.VU.InitEnvField.Registry.RootTagSet()),
+		Logger:         testutils.NewLogger(t),
 	}
 
 	recorder := &callRecorder{
@@ -1420,6 +1422,266 @@ func TestSessionPingAdd(t *testing.T) {
 	assert.Equal(t, []string{"from onpong"}, ts.callRecorder.Recorded())
 }
 
+func countSamples(sampleContainers []metrics.SampleContainer, metricName string) int {
+	var count int
+	for _, sc := range sampleContainers {
+		for _, sample := range sc.GetSamples() {
+			if sample.Metric.Name == metricName {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestSessionAutoPing(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+	sr := ts.tb.Replacer.Replace
+
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		var pongs = 0;
+		var ws = new WebSocket("WSBIN_URL/ws-echo", null, { pingInterval: "10ms", pongTimeout: "5s" })
+		ws.onpong = () => {
+			pongs++;
+			if (pongs == 3) {
+				call("got 3 pongs")
+				ws.close()
+			}
+		}
+		ws.onerror = (e) => { throw JSON.stringify(e) }
+	`))
+	require.NoError(t, err)
+
+	samplesBuf := metrics.GetBufferedSamples(ts.samples)
+	assertSessionMetricsEmitted(t, samplesBuf, "", sr("WSBIN_URL/ws-echo"), http.StatusSwitchingProtocols, "")
+	assert.Equal(t, []string{"got 3 pongs"}, ts.callRecorder.Recorded())
+	assert.GreaterOrEqual(t, countSamples(samplesBuf, metrics.WSPingName), 3)
+	assert.Equal(t, 0, countSamples(samplesBuf, metrics.WSPongTimeoutsName))
+}
+
+func TestSessionPongTimeout(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+	sr := ts.tb.Replacer.Replace
+
+	ts.tb.Mux.HandleFunc("/ws-no-pong", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, req, w.Header())
+		if err != nil {
+			ts.errors <- err
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		// pings aren't answered since nothing is read until the connection is closed
+		time.Sleep(200 * time.Millisecond)
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		var ws = new WebSocket("WSBIN_URL/ws-no-pong", null, { pingInterval: "20ms", pongTimeout: "10ms" })
+		ws.onpong = () => { throw "unexpected pong" }
+		ws.onclose = () => { call("closed") }
+	`))
+	require.NoError(t, err)
+
+	samplesBuf := metrics.GetBufferedSamples(ts.samples)
+	assert.Equal(t, []string{"closed"}, ts.callRecorder.Recorded())
+	assert.Greater(t, countSamples(samplesBuf, metrics.WSPongTimeoutsName), 0)
+}
+
+func TestReconnect(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+	sr := ts.tb.Replacer.Replace
+
+	var connections int
+	var mu sync.Mutex
+	ts.tb.Mux.HandleFunc("/ws-drop-once", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, req, w.Header())
+		if err != nil {
+			ts.errors <- err
+			return
+		}
+		mu.Lock()
+		connections++
+		first := connections == 1
+		mu.Unlock()
+		if first {
+			_ = conn.Close() // without a closing handshake
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_ = conn.WriteMessage(websocket.TextMessage, []byte("reconnected"))
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		var ws = new WebSocket("WSBIN_URL/ws-drop-once", null, { reconnect: { initialDelay: "10ms" } })
+		ws.onopen = () => { call("open") }
+		ws.onmessage = (e) => {
+			call(e.data)
+			ws.close()
+		}
+		ws.onerror = (e) => { throw JSON.stringify(e) }
+		ws.onclose = () => { call("closed") }
+	`))
+	require.NoError(t, err)
+
+	samplesBuf := metrics.GetBufferedSamples(ts.samples)
+	assert.Equal(t, []string{"open", "open", "reconnected", "closed"}, ts.callRecorder.Recorded())
+	assert.Equal(t, 2, countSamples(samplesBuf, metrics.WSSessionsName))
+	assert.Equal(t, 2, countSamples(samplesBuf, metrics.WSSessionDurationName))
+}
+
+func TestReconnectGivesUp(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+	sr := ts.tb.Replacer.Replace
+
+	var connections int
+	var mu sync.Mutex
+	ts.tb.Mux.HandleFunc("/ws-drop", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		connections++
+		first := connections == 1
+		mu.Unlock()
+		if !first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, req, w.Header())
+		if err != nil {
+			ts.errors <- err
+			return
+		}
+		_ = conn.Close() // without a closing handshake
+	}))
+
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		var ws = new WebSocket("WSBIN_URL/ws-drop", null, {
+			reconnect: { maxRetries: 2, initialDelay: "5ms", multiplier: 1.5 },
+		})
+		ws.onopen = () => { call("open") }
+		ws.onerror = () => { call("error") }
+		ws.onclose = () => { call("closed") }
+	`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"open", "error", "closed"}, ts.callRecorder.Recorded())
+	mu.Lock()
+	assert.Equal(t, 3, connections)
+	mu.Unlock()
+}
+
+func TestKeepaliveParamsErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		`{ pongTimeout: "1s" }`:                    "requires pingInterval",
+		`{ pingInterval: "-1s" }`:                  "should be positive",
+		`{ pingInterval: "foo" }`:                  "invalid WebSocket's pingInterval option",
+		`{ reconnect: { maxRetries: 0 } }`:         "maxRetries should be at least 1",
+		`{ reconnect: { multiplier: 0.5 } }`:       "multiplier should be at least 1",
+		`{ reconnect: { initialDelay: "-1s" } }`:   "initialDelay can't be negative",
+		`{ reconnect: { backoff: "exponential" }}`: "unknown option backoff",
+	}
+	for params, expectedErr := range cases {
+		t.Run(params, func(t *testing.T) {
+			t.Parallel()
+			ts := newTestState(t)
+			sr := ts.tb.Replacer.Replace
+
+			_, err := ts.runtime.RunOnEventLoop(sr(`new WebSocket("WSBIN_URL/ws-echo", null, ` + params + `)`))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), expectedErr)
+		})
+	}
+}
+
+func TestReconnectPolicyDelay(t *testing.T) {
+	t.Parallel()
+	policy := &reconnectPolicy{
+		maxRetries:   5,
+		initialDelay: time.Second,
+		maxDelay:     5 * time.Second,
+		multiplier:   2,
+	}
+	assert.Equal(t, time.Second, policy.delay(0))
+	assert.Equal(t, 2*time.Second, policy.delay(1))
+	assert.Equal(t, 4*time.Second, policy.delay(2))
+	assert.Equal(t, 5*time.Second, policy.delay(3))
+}
+
+func TestAbortSignal(t *testing.T) {
+	t.Parallel()
+	t.Run("open", func(t *testing.T) {
+		t.Parallel()
+		ts := newTestState(t)
+		sr := ts.tb.Replacer.Replace
+
+		_, err := ts.runtime.RunOnEventLoop(sr(`
+			var controller = new AbortController()
+			var ws = new WebSocket("WSBIN_URL/ws-echo", null, { signal: controller.signal })
+			ws.onopen = () => {
+				call("open")
+				controller.abort()
+			}
+			ws.onerror = (e) => { call("error") }
+			ws.onclose = () => { call("closed") }
+		`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"open", "closed"}, ts.callRecorder.Recorded())
+	})
+	t.Run("connecting", func(t *testing.T) {
+		t.Parallel()
+		ts := newTestState(t)
+		sr := ts.tb.Replacer.Replace
+
+		unblock := make(chan struct{})
+		defer close(unblock)
+		ts.tb.Mux.HandleFunc("/ws-slow", http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			select {
+			case <-unblock:
+			case <-req.Context().Done():
+			}
+		}))
+
+		_, err := ts.runtime.RunOnEventLoop(sr(`
+			var controller = new AbortController()
+			var ws = new WebSocket("WSBIN_URL/ws-slow", null, { signal: controller.signal })
+			ws.onopen = () => { call("open") }
+			ws.onerror = (e) => { call(e.error) }
+			ws.onclose = () => { call("closed") }
+			setTimeout(() => controller.abort(), 50)
+		`))
+		require.NoError(t, err)
+		recorded := ts.callRecorder.Recorded()
+		require.Len(t, recorded, 2)
+		assert.Contains(t, recorded[0], "WebSocket connection canceled")
+		assert.Equal(t, "closed", recorded[1])
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		ts := newTestState(t)
+		sr := ts.tb.Replacer.Replace
+
+		_, err := ts.runtime.RunOnEventLoop(sr(`new WebSocket("WSBIN_URL/ws-echo", null, { signal: {} })`))
+		require.ErrorContains(t, err, "invalid WebSocket's signal option")
+	})
+}
+
 func TestLockingUpWithAThrow(t *testing.T) {
 	t.Parallel()
 	tb := httpmultibin.NewHTTPMultiBin(t)
@@ -227,6 +227,10 @@ func (c *Client) Connect(addr string, params sobek.Value) (bool, error) {
 		return false, fmt.Errorf("invalid grpc.connect() parameters: %w", err)
 	}
 
+	if p.Protocol != protocolGRPC {
+		return c.connectWeb(addr, p)
+	}
+
 	opts := grpcext.DefaultOptions(c.vu.State)
 
 	var tcred credentials.TransportCredentials
@@ -288,6 +292,41 @@ func (c *Client) Connect(addr string, params sobek.Value) (bool, error) {
 	return true, err
 }
 
+// connectWeb connects to a server that uses the gRPC-Web or the Connect
+// protocol, which only support the unary calls and not the reflection.
+func (c *Client) connectWeb(addr string, p *connectParams) (bool, error) {
+	if p.UseReflectionProtocol {
+		return false, fmt.Errorf("reflection isn't supported with the %s protocol", p.Protocol)
+	}
+
+	state := c.vu.State()
+	opts := grpcext.WebOptions{
+		Protocol:       grpcext.WebProtocol(p.Protocol),
+		GetState:       c.vu.State,
+		Authority:      p.Authority,
+		UserAgent:      state.Options.UserAgent.ValueOrZero(),
+		MaxReceiveSize: p.MaxReceiveSize,
+		MaxSendSize:    p.MaxSendSize,
+	}
+	if !p.IsPlaintext {
+		opts.TLSConfig = state.TLSConfig.Clone()
+		if len(p.TLS) > 0 {
+			var err error
+			if opts.TLSConfig, err = buildTLSConfigFromMap(opts.TLSConfig, p.TLS); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	var err error
+	c.addr = addr
+	c.conn, err = grpcext.DialWeb(addr, c.types, opts)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // HealthCheck checks if the server side is up and ready to serve responses
 func (c *Client) HealthCheck(svc *string) (*grpcext.HealthCheckResponse, error) {
 	var service string
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -758,6 +759,42 @@ func TestClient(t *testing.T) {
 				err:  "rpc error: code = Unimplemented desc = unknown service grpc.reflection.v1alpha.ServerReflection",
 			},
 		},
+		{
+			name: "InvokeGRPCWeb",
+			setup: func(tb *httpmultibin.HTTPMultiBin) {
+				tb.Mux.HandleFunc("/grpc.testing.TestService/EmptyCall", func(w http.ResponseWriter, r *http.Request) {
+					if r.Header.Get("Content-Type") != "application/grpc-web+proto" {
+						w.WriteHeader(http.StatusUnsupportedMediaType)
+						return
+					}
+					trailer := "grpc-status: 0\r\nx-trailer: trailer-value\r\n"
+					_, _ = w.Write([]byte{0, 0, 0, 0, 0})
+					_, _ = w.Write(append([]byte{0x80, 0, 0, 0, byte(len(trailer))}, trailer...))
+				})
+			},
+			initString: codeBlock{code: `
+				var client = new grpc.Client();
+				client.load([], "../../../../lib/testutils/httpmultibin/grpc_testing/test.proto");`},
+			vuString: codeBlock{code: `
+				client.connect("HTTPBIN_URL", {protocol: "grpc-web", plaintext: true});
+				var resp = client.invoke("grpc.testing.TestService/EmptyCall", {})
+				if (resp.status !== grpc.StatusOK) {
+					throw new Error("unexpected status: " + resp.status)
+				}
+				if (resp.trailers["x-trailer"][0] !== "trailer-value") {
+					throw new Error("unexpected trailers: " + JSON.stringify(resp.trailers))
+				}`},
+		},
+		{
+			name: "ConnectWebReflect",
+			initString: codeBlock{
+				code: `var client = new grpc.Client();`,
+			},
+			vuString: codeBlock{
+				code: `client.connect("HTTPBIN_URL", {protocol: "connect", reflect: true})`,
+				err:  "reflection isn't supported with the connect protocol",
+			},
+		},
 		{
 			name: "Reflect",
 			setup: func(tb *httpmultibin.HTTPMultiBin) {
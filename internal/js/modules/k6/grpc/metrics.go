@@ -4,9 +4,11 @@ import "go.k6.io/k6/metrics"
 
 // instanceMetrics contains the metrics for the grpc extension.
 type instanceMetrics struct {
-	Streams                 *metrics.Metric
-	StreamsMessagesSent     *metrics.Metric
-	StreamsMessagesReceived *metrics.Metric
+	Streams                   *metrics.Metric
+	StreamsMessagesSent       *metrics.Metric
+	StreamsMessagesReceived   *metrics.Metric
+	StreamsDuration           *metrics.Metric
+	StreamsTimeToFirstMessage *metrics.Metric
 }
 
 // registerMetrics registers and returns the metrics in the provided registry
@@ -26,5 +28,14 @@ func registerMetrics(registry *metrics.Registry) (*instanceMetrics, error) {
 		return nil, err
 	}
 
+	if m.StreamsDuration, err = registry.NewMetric("grpc_stream_duration", metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+
+	m.StreamsTimeToFirstMessage, err = registry.NewMetric("grpc_stream_time_to_first_msg", metrics.Trend, metrics.Time)
+	if err != nil {
+		return nil, err
+	}
+
 	return m, nil
 }
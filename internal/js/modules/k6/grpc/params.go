@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/sobek"
+	"go.k6.io/k6/internal/lib/netext/grpcext"
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
 	"go.k6.io/k6/lib"
@@ -122,6 +123,10 @@ func (p *callParams) SetSystemTags(state *lib.State, addr string, methodName str
 	}
 }
 
+// protocolGRPC is the default protocol of connect, which uses the native gRPC
+// framing over HTTP/2, unlike the web protocols of grpcext.
+const protocolGRPC = "grpc"
+
 // connectParams is the parameters that can be passed to a gRPC connect call.
 type connectParams struct {
 	IsPlaintext           bool
@@ -132,6 +137,7 @@ type connectParams struct {
 	MaxSendSize           int64
 	TLS                   map[string]interface{}
 	Authority             string
+	Protocol              string
 }
 
 func newConnectParams(vu modules.VU, input sobek.Value) (*connectParams, error) { //nolint:gocognit
@@ -143,6 +149,7 @@ func newConnectParams(vu modules.VU, input sobek.Value) (*connectParams, error)
 		MaxSendSize:           0,
 		Authority:             "",
 		ReflectionMetadata:    metadata.New(nil),
+		Protocol:              protocolGRPC,
 	}
 
 	if common.IsNullish(input) {
@@ -209,6 +216,17 @@ func newConnectParams(vu modules.VU, input sobek.Value) (*connectParams, error)
 			if !ok {
 				return result, fmt.Errorf("invalid authority value: '%#v', it needs to be a string", v)
 			}
+		case "protocol":
+			protocol, ok := v.(string)
+			switch {
+			case !ok:
+				return result, fmt.Errorf("invalid protocol value: '%#v', it needs to be a string", v)
+			case protocol != protocolGRPC &&
+				protocol != string(grpcext.ProtocolGRPCWeb) && protocol != string(grpcext.ProtocolConnect):
+				return result, fmt.Errorf("invalid protocol value: %q, it needs to be one of %q, %q or %q",
+					protocol, protocolGRPC, grpcext.ProtocolGRPCWeb, grpcext.ProtocolConnect)
+			}
+			result.Protocol = protocol
 		default:
 			return result, fmt.Errorf("unknown connect param: %q", k)
 		}
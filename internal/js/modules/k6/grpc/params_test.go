@@ -206,6 +206,59 @@ func TestConnectParamsAuthority(t *testing.T) {
 	}
 }
 
+func TestConnectParamsProtocol(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name             string
+		JSON             string
+		ExpectedProtocol string
+		ErrContains      string
+	}{
+		{
+			Name:             "DefaultProtocol",
+			JSON:             `{}`,
+			ExpectedProtocol: "grpc",
+		},
+		{
+			Name:             "GRPCWeb",
+			JSON:             `{protocol:"grpc-web"}`,
+			ExpectedProtocol: "grpc-web",
+		},
+		{
+			Name:             "Connect",
+			JSON:             `{protocol:"connect"}`,
+			ExpectedProtocol: "connect",
+		},
+		{
+			Name:        "InvalidProtocol",
+			JSON:        `{protocol:"grpc-web-text"}`,
+			ErrContains: `invalid protocol value: "grpc-web-text"`,
+		},
+		{
+			Name:        "InvalidProtocolType",
+			JSON:        `{protocol:true}`,
+			ErrContains: `invalid protocol value: 'true', it needs to be a string`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			testRuntime, params := newParamsTestRuntime(t, tc.JSON)
+
+			p, err := newConnectParams(testRuntime.VU, params)
+			if tc.ErrContains != "" {
+				assert.ErrorContains(t, err, tc.ErrContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.ExpectedProtocol, p.Protocol)
+		})
+	}
+}
+
 // newParamsTestRuntime creates a new test runtime
 // that could be used to test the params
 // it also moves to the VU context and creates the params
@@ -7,8 +7,10 @@ import (
 	"io"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.k6.io/k6/internal/js/modules/k6"
 	"go.k6.io/k6/internal/lib/netext/grpcext"
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
@@ -64,6 +66,12 @@ type stream struct {
 	eventListeners *eventListeners
 
 	timeoutCancel context.CancelFunc
+
+	// startTime is when the stream began, the messages sent and received are
+	// counted from the goroutines reading and writing the stream
+	startTime        time.Time
+	messagesSent     atomic.Int64
+	messagesReceived atomic.Int64
 }
 
 // defineStream defines the sobek.Object that is given to js to interact with the Stream
@@ -76,6 +84,9 @@ func defineStream(rt *sobek.Runtime, s *stream) {
 
 	must(rt, s.obj.DefineDataProperty(
 		"end", rt.ToValue(s.end), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+
+	must(rt, s.obj.DefineDataProperty(
+		"check", rt.ToValue(s.check), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
 }
 
 func (s *stream) beginStream(p *callParams) error {
@@ -101,12 +112,13 @@ func (s *stream) beginStream(p *callParams) error {
 		return fmt.Errorf("failed to create a new stream: %w", err)
 	}
 	s.stream = stream
+	s.startTime = time.Now()
 	metrics.PushIfNotDone(s.vu.Context(), s.vu.State().Samples, metrics.Sample{
 		TimeSeries: metrics.TimeSeries{
 			Metric: s.instanceMetrics.Streams,
 			Tags:   s.tagsAndMeta.Tags,
 		},
-		Time:     time.Now(),
+		Time:     s.startTime,
 		Metadata: s.tagsAndMeta.Metadata,
 		Value:    1,
 	})
@@ -148,7 +160,7 @@ func (s *stream) loop() {
 
 func (s *stream) queueMessage(msg interface{}) {
 	now := time.Now()
-	metrics.PushIfNotDone(s.vu.Context(), s.vu.State().Samples, metrics.Sample{
+	samples := []metrics.Sample{{
 		TimeSeries: metrics.TimeSeries{
 			Metric: s.instanceMetrics.StreamsMessagesReceived,
 			Tags:   s.tagsAndMeta.Tags,
@@ -156,7 +168,19 @@ func (s *stream) queueMessage(msg interface{}) {
 		Time:     now,
 		Metadata: s.tagsAndMeta.Metadata,
 		Value:    1,
-	})
+	}}
+	if s.messagesReceived.Add(1) == 1 {
+		samples = append(samples, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: s.instanceMetrics.StreamsTimeToFirstMessage,
+				Tags:   s.tagsAndMeta.Tags,
+			},
+			Time:     now,
+			Metadata: s.tagsAndMeta.Metadata,
+			Value:    metrics.D(now.Sub(s.startTime)),
+		})
+	}
+	metrics.PushIfNotDone(s.vu.Context(), s.vu.State().Samples, metrics.Samples(samples))
 
 	s.tq.Queue(func() error {
 		rt := s.vu.Runtime()
@@ -252,6 +276,7 @@ func (s *stream) writeData(wg *sync.WaitGroup) {
 					return
 				}
 
+				s.messagesSent.Add(1)
 				metrics.PushIfNotDone(s.vu.Context(), s.vu.State().Samples, metrics.Sample{
 					TimeSeries: metrics.TimeSeries{
 						Metric: s.instanceMetrics.StreamsMessagesSent,
@@ -371,8 +396,20 @@ func (s *stream) close(err error) {
 	s.logger.Debugf("stream %s is closing", s.method)
 	close(s.done)
 
+	now := time.Now()
+	duration := now.Sub(s.startTime)
+	metrics.PushIfNotDone(s.vu.Context(), s.vu.State().Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: s.instanceMetrics.StreamsDuration,
+			Tags:   s.tagsAndMeta.Tags,
+		},
+		Time:     now,
+		Metadata: s.tagsAndMeta.Metadata,
+		Value:    metrics.D(duration),
+	})
+
 	s.tq.Queue(func() error {
-		return s.callEventListeners(eventEnd)
+		return s.callEndListeners(duration)
 	})
 
 	if s.timeoutCancel != nil {
@@ -380,6 +417,27 @@ func (s *stream) close(err error) {
 	}
 }
 
+// check runs the checks against a message of the stream, like the check() of
+// the k6 module, with the current tags of the VU and the tags of the stream,
+// including its method.
+func (s *stream) check(arg0, checks sobek.Value, extras ...sobek.Value) (bool, error) {
+	state := s.vu.State()
+	if state == nil {
+		return false, k6.ErrCheckInInitContext
+	}
+	tagsAndMeta := state.Tags.GetCurrentValues()
+	for key, value := range s.tagsAndMeta.Tags.Map() {
+		if key == metrics.TagGroup.String() {
+			continue // the check is in the group it's called from
+		}
+		tagsAndMeta.SetTag(key, value)
+	}
+	for key, value := range s.tagsAndMeta.Metadata {
+		tagsAndMeta.SetMetadata(key, value)
+	}
+	return k6.RunChecks(s.vu, tagsAndMeta, arg0, checks, extras...)
+}
+
 func (s *stream) callErrorListeners(e error) error {
 	if e == nil || errors.Is(e, io.EOF) {
 		return nil
@@ -443,7 +501,8 @@ func extractError(e error) grpcError {
 	return w
 }
 
-func (s *stream) callEventListeners(eventType string) error {
+// callEndListeners calls the end event listeners with the stats of the stream.
+func (s *stream) callEndListeners(duration time.Duration) error {
 	now := time.Now()
 	rt := s.vu.Runtime()
 
@@ -452,8 +511,20 @@ func (s *stream) callEventListeners(eventType string) error {
 	if err != nil {
 		return err
 	}
-	for _, listener := range s.eventListeners.all(eventType) {
-		if _, err := listener(rt.ToValue(struct{}{}), metadataObj); err != nil {
+
+	statsObj := rt.NewObject()
+	for name, value := range map[string]interface{}{
+		"messagesSent":     s.messagesSent.Load(),
+		"messagesReceived": s.messagesReceived.Load(),
+		"duration":         metrics.D(duration),
+	} {
+		if err := statsObj.Set(name, value); err != nil {
+			return err
+		}
+	}
+
+	for _, listener := range s.eventListeners.all(eventEnd) {
+		if _, err := listener(statsObj, metadataObj); err != nil {
 			return err
 		}
 	}
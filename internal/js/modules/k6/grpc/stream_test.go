@@ -644,7 +644,7 @@ func TestStream_MetricsTagsMetadata(t *testing.T) {
 
 	samplesBuf := metrics.GetBufferedSamples(ts.samples)
 
-	assert.Len(t, samplesBuf, 4)
+	assert.Len(t, samplesBuf, 5)
 	for _, samples := range samplesBuf {
 		for _, sample := range samples.GetSamples() {
 			assertTags(t, sample, expTags)
@@ -652,6 +652,88 @@ func TestStream_MetricsTagsMetadata(t *testing.T) {
 	}
 }
 
+// TestStream_StatsAndChecks tests that the per-stream metrics are emitted, that
+// the end event gets the stats of the stream and that the checks of the stream's
+// messages are tagged with its method.
+func TestStream_StatsAndChecks(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestState(t)
+
+	stub := &featureExplorerStub{}
+
+	stub.listFeatures = func(_ *grpcservice.Rectangle, stream grpcservice.FeatureExplorer_ListFeaturesServer) error {
+		for _, name := range []string{"foo", "bar"} {
+			if err := stream.Send(&grpcservice.Feature{Name: name}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	grpcservice.RegisterFeatureExplorerServer(ts.httpBin.ServerGRPC, stub)
+
+	initString := codeBlock{
+		code: `
+		var client = new grpc.Client();
+		client.load([], "../../../../lib/testutils/grpcservice/route_guide.proto");`,
+	}
+	vuString := codeBlock{
+		code: `
+		client.connect("GRPCBIN_ADDR");
+
+		let stream = new grpc.Stream(client, "main.FeatureExplorer/ListFeatures")
+		stream.on('data', function (data) {
+			let ok = stream.check(data, {
+				"is foo": (f) => f.name === "foo",
+			}, { "msg": data.name });
+			call('Feature:' + data.name + ':' + ok);
+		});
+		stream.on('end', function (stats) {
+			if (stats.duration <= 0) {
+				throw new Error("unexpected duration " + stats.duration);
+			}
+			call('End:' + stats.messagesSent + ':' + stats.messagesReceived);
+		});
+
+		stream.write({lo: {latitude: 1, longitude: 2}, hi: {latitude: 1, longitude: 2}});
+		stream.end();
+		`,
+	}
+
+	val, err := ts.Run(initString.code)
+	assertResponse(t, initString, err, val, ts)
+
+	ts.ToVUContext()
+	ts.VU.State().Tags.Modify(func(tagsAndMeta *metrics.TagsAndMeta) {
+		tagsAndMeta.SetTag("vu_tag", "vu")
+	})
+
+	val, err = ts.RunOnEventLoop(vuString.code)
+	assertResponse(t, vuString, err, val, ts)
+
+	assert.Equal(t, []string{"Feature:foo:true", "Feature:bar:false", "End:1:2"}, ts.callRecorder.Recorded())
+
+	counts := make(map[string]int)
+	checks := make(map[string]float64)
+	for _, container := range metrics.GetBufferedSamples(ts.samples) {
+		for _, sample := range container.GetSamples() {
+			counts[sample.Metric.Name]++
+			if sample.Metric.Name != metrics.ChecksName {
+				continue
+			}
+			assertTags(t, sample, map[string]string{"name": "/main.FeatureExplorer/ListFeatures", "vu_tag": "vu"})
+			msg, _ := sample.Tags.Get("msg")
+			checks[msg] = sample.Value
+		}
+	}
+
+	assert.Equal(t, 1, counts["grpc_stream_duration"])
+	assert.Equal(t, 1, counts["grpc_stream_time_to_first_msg"])
+	assert.Equal(t, 2, counts["grpc_streams_msgs_received"])
+	assert.Equal(t, map[string]float64{"foo": 1, "bar": 0}, checks)
+}
+
 func assertTags(t *testing.T, sample metrics.Sample, tags map[string]string) {
 	for k, v := range tags {
 		tag, ok := sample.Tags.Get(k)
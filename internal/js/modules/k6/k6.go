@@ -2,7 +2,9 @@
 package k6
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"math/rand" // nosemgrep: math-random-used // used to seed the Marh.random of the JS VM that is pseudo random by specification
 	"strings"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/js/modules"
 	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
 
@@ -31,9 +34,24 @@ type (
 	// K6 represents an instance of the k6 module.
 	K6 struct {
 		vu modules.VU
+
+		// groupCtx is done when the deadline of the innermost group with a
+		// timeout is reached, it's nil outside of such groups.
+		groupCtx context.Context
+	}
+
+	// groupTimeoutError is used to interrupt the JS runtime when a group
+	// exceeds its timeout.
+	groupTimeoutError struct {
+		group   string
+		timeout time.Duration
 	}
 )
 
+func (e *groupTimeoutError) Error() string {
+	return fmt.Sprintf("group %q timed out after %s", e.group, e.timeout)
+}
+
 var (
 	_ modules.Module   = &RootModule{}
 	_ modules.Instance = &K6{}
@@ -71,11 +89,24 @@ func (*K6) Fail(msg string) (sobek.Value, error) {
 // Sleep waits the provided seconds before continuing the execution.
 func (mi *K6) Sleep(secs float64) {
 	ctx := mi.vu.Context()
+	if mi.groupCtx != nil {
+		ctx = mi.groupCtx
+	}
+	start := time.Now()
 	timer := time.NewTimer(time.Duration(secs * float64(time.Second)))
 	select {
 	case <-timer.C:
 	case <-ctx.Done():
 		timer.Stop()
+		// the interrupt of the group body could still be pending, so it's done
+		// here to not let the body continue after the sleep
+		var timeoutErr *groupTimeoutError
+		if errors.As(context.Cause(ctx), &timeoutErr) && mi.vu.Context().Err() == nil {
+			mi.vu.Runtime().Interrupt(timeoutErr)
+		}
+	}
+	if state := mi.vu.State(); state != nil {
+		state.IterationBreakdown.AddSleep(time.Since(start))
 	}
 }
 
@@ -86,23 +117,44 @@ func (mi *K6) RandomSeed(seed int64) {
 }
 
 // Group wraps a function call and executes it within the provided group name.
-func (mi *K6) Group(name string, val sobek.Value) (sobek.Value, error) {
+//
+// An optional params object can be passed between the name and the callback.
+// If it has a timeout, the group body is aborted once the timeout is reached,
+// the execution continues after the group and its group_duration sample is
+// tagged with timed_out=true. So are the samples of the checks whose callables
+// were aborted; the checks done before the timeout was reached are not tagged,
+// as they weren't affected by it. Operations blocking in Go code, except sleep(),
+// are not interrupted and the group body is aborted only once they return.
+func (mi *K6) Group(name string, args ...sobek.Value) (sobek.Value, error) {
 	state := mi.vu.State()
 	if state == nil {
 		return nil, ErrGroupInInitContext
 	}
 
+	var val, params sobek.Value
+	switch len(args) {
+	case 0:
+	case 1:
+		val = args[0]
+	default:
+		params, val = args[0], args[1]
+	}
+
 	if common.IsNullish(val) {
-		return nil, errors.New("group() requires a callback as a second argument")
+		return nil, errors.New("group() requires a callback as the last argument")
 	}
 	fn, ok := sobek.AssertFunction(val)
 	if !ok {
-		return nil, errors.New("group() requires a callback as a second argument")
+		return nil, errors.New("group() requires a callback as the last argument")
 	}
 	if common.IsAsyncFunction(mi.vu.Runtime(), val) {
 		return sobek.Undefined(), errors.New("group() does not support async functions as arguments, " +
 			"please see https://grafana.com/docs/k6/latest/javascript-api/k6/group/ for more info")
 	}
+	timeout, err := mi.parseGroupTimeout(params)
+	if err != nil {
+		return sobek.Undefined(), err
+	}
 	oldGroupName, _ := state.Tags.GetCurrentValues().Tags.Get(metrics.TagGroup.String())
 	// TODO: what are we doing if group is not tagged
 	newGroupName, err := lib.NewGroupPath(oldGroupName, name)
@@ -125,15 +177,26 @@ func (mi *K6) Group(name string, val sobek.Value) (sobek.Value, error) {
 	}()
 
 	startTime := time.Now()
-	ret, err := fn(sobek.Undefined())
+	var ret sobek.Value
+	timedOut := false
+	if timeout > 0 {
+		ret, timedOut, err = mi.runGroupWithTimeout(name, timeout, fn)
+	} else {
+		ret, err = fn(sobek.Undefined())
+	}
 	t := time.Now()
 
 	ctx := mi.vu.Context()
 	ctm := state.Tags.GetCurrentValues()
+	tags := ctm.Tags
+	if timedOut {
+		tags = tags.With("timed_out", "true")
+		state.Logger.Warnf("group %q timed out after %s", name, timeout)
+	}
 	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
 		TimeSeries: metrics.TimeSeries{
 			Metric: state.BuiltinMetrics.GroupDuration,
-			Tags:   ctm.Tags,
+			Tags:   tags,
 		},
 		Time:     t,
 		Value:    metrics.D(t.Sub(startTime)),
@@ -143,21 +206,112 @@ func (mi *K6) Group(name string, val sobek.Value) (sobek.Value, error) {
 	return ret, err
 }
 
+func (mi *K6) parseGroupTimeout(params sobek.Value) (time.Duration, error) {
+	if common.IsNullish(params) {
+		return 0, nil
+	}
+	rt := mi.vu.Runtime()
+	timeoutV := params.ToObject(rt).Get("timeout")
+	if common.IsNullish(timeoutV) {
+		return 0, nil
+	}
+	timeout, err := types.GetDurationValue(timeoutV.Export())
+	if err != nil {
+		return 0, fmt.Errorf("invalid group timeout: %w", err)
+	}
+	if timeout < 0 {
+		return 0, errors.New("the group timeout can't be negative")
+	}
+	return timeout, nil
+}
+
+// runGroupWithTimeout calls fn and interrupts it if it doesn't finish within
+// the given timeout. It returns whether the group body was aborted.
+func (mi *K6) runGroupWithTimeout(
+	name string, timeout time.Duration, fn sobek.Callable,
+) (sobek.Value, bool, error) {
+	rt := mi.vu.Runtime()
+	vuCtx := mi.vu.Context()
+	timeoutErr := &groupTimeoutError{group: name, timeout: timeout}
+
+	prevGroupCtx := mi.groupCtx
+	parentCtx := prevGroupCtx
+	if parentCtx == nil {
+		parentCtx = vuCtx
+	}
+	groupCtx, cancel := context.WithTimeoutCause(parentCtx, timeout, timeoutErr)
+	defer cancel()
+	mi.groupCtx = groupCtx
+	defer func() { mi.groupCtx = prevGroupCtx }()
+
+	interrupted := make(chan struct{})
+	stop := context.AfterFunc(groupCtx, func() {
+		defer close(interrupted)
+		if context.Cause(groupCtx) == timeoutErr && vuCtx.Err() == nil {
+			rt.Interrupt(timeoutErr)
+		}
+	})
+
+	ret, err := fn(sobek.Undefined())
+	if stop() {
+		return ret, false, err
+	}
+	<-interrupted
+	if context.Cause(groupCtx) != timeoutErr || vuCtx.Err() != nil {
+		return ret, false, err
+	}
+
+	// the interrupt could have happened after the group body was done
+	rt.ClearInterrupt()
+	if err == nil {
+		return ret, true, nil
+	}
+	var interruptErr *sobek.InterruptedError
+	if !errors.As(err, &interruptErr) || interruptErr.Value() != timeoutErr {
+		return ret, false, err
+	}
+	return sobek.Undefined(), true, nil
+}
+
+// isGroupTimeout returns whether err is the interruption of a group body
+// because its timeout was reached.
+func isGroupTimeout(err error) bool {
+	var interruptErr *sobek.InterruptedError
+	if !errors.As(err, &interruptErr) {
+		return false
+	}
+	_, ok := interruptErr.Value().(*groupTimeoutError)
+	return ok
+}
+
 // Check will emit check metrics for the provided checks.
 func (mi *K6) Check(arg0, checks sobek.Value, extras ...sobek.Value) (bool, error) {
 	state := mi.vu.State()
 	if state == nil {
 		return false, ErrCheckInInitContext
 	}
+	return RunChecks(mi.vu, state.Tags.GetCurrentValues(), arg0, checks, extras...)
+}
+
+// RunChecks runs the checks against arg0 and emits their metrics, tagged with
+// the given tags and metadata and the custom tags in extras. It's what check()
+// does, for the modules which provide their own check() with different tags.
+func RunChecks(
+	vu modules.VU, tagsAndMeta metrics.TagsAndMeta, arg0, checks sobek.Value, extras ...sobek.Value,
+) (bool, error) {
+	state := vu.State()
+	if state == nil {
+		return false, ErrCheckInInitContext
+	}
 	if checks == nil {
 		return false, errors.New("no checks provided to `check`")
 	}
-	ctx := mi.vu.Context()
-	rt := mi.vu.Runtime()
+	ctx := vu.Context()
+	rt := vu.Runtime()
 	t := time.Now()
 
 	// Prepare the metric tags
-	commonTagsAndMeta := state.Tags.GetCurrentValues()
+	commonTagsAndMeta := tagsAndMeta
 	if len(extras) > 0 {
 		if err := common.ApplyCustomUserTags(rt, &commonTagsAndMeta, extras[0]); err != nil {
 			return false, err
@@ -192,6 +346,9 @@ func (mi *K6) Check(arg0, checks sobek.Value, extras ...sobek.Value) (bool, erro
 			if err != nil {
 				val = rt.ToValue(false)
 				exc = err
+				if isGroupTimeout(err) {
+					tags = tags.With("timed_out", "true")
+				}
 			}
 		}
 		booleanVal := val.ToBoolean()
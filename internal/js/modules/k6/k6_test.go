@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.k6.io/k6/internal/lib/testutils"
 	"go.k6.io/k6/js/modulestest"
 	"go.k6.io/k6/lib"
 	"go.k6.io/k6/metrics"
@@ -117,6 +118,41 @@ func TestGroup(t *testing.T) {
 		assert.ErrorContains(t, err, "group() does not support async functions as arguments")
 	})
 
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		tc := testCaseRuntime(t)
+		startTime := time.Now()
+		_, err := tc.testRuntime.RunOnEventLoop(`
+			var reached = false;
+			k6.group("slow", {timeout: "200ms"}, function() { while(true) {} });
+			k6.group("sleepy", {timeout: 200}, function() { k6.sleep(10); reached = true; });
+			if (reached) { throw new Error("the group body wasn't aborted"); }
+		`)
+		require.NoError(t, err)
+		assert.Less(t, time.Since(startTime), 2*time.Second)
+
+		groupSamples := bufferedSamplesOf(tc, metrics.GroupDurationName)
+		assert.Len(t, groupSamples, 2)
+		assert.Equal(t, 2, countTimedOut(groupSamples))
+	})
+
+	t.Run("timeout not reached", func(t *testing.T) {
+		t.Parallel()
+		tc := testCaseRuntime(t)
+		v, err := tc.testRuntime.RunOnEventLoop(`k6.group("fast", {timeout: "10s"}, function() { return 42 })`)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), v.Export())
+
+		assert.Zero(t, countTimedOut(bufferedSamplesOf(tc, "")))
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		t.Parallel()
+		tc := testCaseRuntime(t)
+		_, err := tc.testRuntime.RunOnEventLoop(`k6.group("bad", {timeout: "nope"}, function() {})`)
+		assert.ErrorContains(t, err, "invalid group timeout")
+	})
+
 	t.Run("async lambda", func(t *testing.T) {
 		t.Parallel()
 		tc := testCaseRuntime(t)
@@ -192,6 +228,32 @@ func TestCheckObject(t *testing.T) {
 		assert.ErrorContains(t, err, "check() does not support async functions as arguments")
 	})
 
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+		tc := testCaseRuntime(t)
+		_, err := tc.testRuntime.RunOnEventLoop(`
+			k6.group("slow", {timeout: "200ms"}, function() {
+				k6.check(null, { "before": true });
+				k6.check(null, { "slow": function() { while(true) {} } });
+			});
+		`)
+		require.NoError(t, err)
+
+		checks := make(map[string]bool)
+		var groupSamples []metrics.Sample
+		for _, s := range bufferedSamplesOf(tc, "") {
+			if s.Metric.Name == metrics.GroupDurationName {
+				groupSamples = append(groupSamples, s)
+				continue
+			}
+			name, ok := s.Tags.Get("check")
+			require.True(t, ok)
+			checks[name] = countTimedOut([]metrics.Sample{s}) == 1
+		}
+		assert.Equal(t, map[string]bool{"before": false, "slow": true}, checks)
+		assert.Equal(t, 1, countTimedOut(groupSamples))
+	})
+
 	t.Run("async lambda", func(t *testing.T) {
 		t.Parallel()
 		tc := testCaseRuntime(t)
@@ -384,6 +446,7 @@ func testCaseRuntime(t testing.TB) *testCase {
 			SystemTags: &metrics.DefaultSystemTagSet,
 		},
 		Samples:        samples,
+		Logger:         testutils.NewLogger(t),
 		Tags:           lib.NewVUStateTags(registry.RootTagSet().WithTagsFromMap(map[string]string{"group": lib.RootGroupPath})),
 		BuiltinMetrics: metrics.RegisterBuiltinMetrics(registry),
 	}
@@ -394,3 +457,27 @@ func testCaseRuntime(t testing.TB) *testCase {
 		testRuntime: testRuntime,
 	}
 }
+
+// bufferedSamplesOf drains the samples of the test case and returns the ones
+// of the given metric, or all of them if the metric name is empty.
+func bufferedSamplesOf(tc *testCase, metricName string) []metrics.Sample {
+	var samples []metrics.Sample
+	for _, container := range metrics.GetBufferedSamples(tc.samples) {
+		for _, s := range container.GetSamples() {
+			if metricName == "" || s.Metric.Name == metricName {
+				samples = append(samples, s)
+			}
+		}
+	}
+	return samples
+}
+
+func countTimedOut(samples []metrics.Sample) int {
+	var timedOut int
+	for _, s := range samples {
+		if v, ok := s.Tags.Get("timed_out"); ok && v == "true" {
+			timedOut++
+		}
+	}
+	return timedOut
+}
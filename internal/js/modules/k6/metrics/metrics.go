@@ -74,7 +74,10 @@ func limitValue(v string) string {
 	}, string(omitMsg))
 }
 
-func (m Metric) add(v sobek.Value, addTags sobek.Value) (bool, error) {
+// add adds a value to the metric, with the tags and the metadata of the VU and
+// the ones of the sample, if any. The metadata, like trace or order IDs, is
+// attached to the sample without adding to the cardinality of its time series.
+func (m Metric) add(v sobek.Value, addTags sobek.Value, addMetadata sobek.Value) (bool, error) {
 	state := m.vu.State()
 	if state == nil {
 		return false, ErrMetricsAddInInitContext
@@ -114,6 +117,14 @@ func (m Metric) add(v sobek.Value, addTags sobek.Value) (bool, error) {
 	if err := common.ApplyCustomUserTags(m.vu.Runtime(), &ctm, addTags); err != nil {
 		return false, fmt.Errorf("cannot add tags for the '%s' custom metric: %w", m.metric.Name, err)
 	}
+	if !common.IsNullish(addMetadata) {
+		metadata := addMetadata.ToObject(m.vu.Runtime())
+		for _, key := range metadata.Keys() {
+			if err := common.ApplyCustomUserMetadata(&ctm, key, metadata.Get(key)); err != nil {
+				return false, fmt.Errorf("cannot add metadata for the '%s' custom metric: %w", m.metric.Name, err)
+			}
+		}
+	}
 
 	sample := metrics.Sample{
 		TimeSeries: metrics.TimeSeries{
@@ -10,6 +10,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
 
 	"go.k6.io/k6/internal/lib/testutils"
 	"go.k6.io/k6/js/common"
@@ -233,3 +234,49 @@ func TestMetricDuplicates(t *testing.T) {
 
 	require.True(t, v.ToBoolean())
 }
+
+func TestMetricAddMetadata(t *testing.T) {
+	t.Parallel()
+	rt := sobek.New()
+	rt.SetFieldNameMapper(common.FieldNameMapper{})
+
+	registry := metrics.NewRegistry()
+	mii := &modulestest.VU{
+		RuntimeField: rt,
+		InitEnvField: &common.InitEnvironment{TestPreInitState: &lib.TestPreInitState{Registry: registry}},
+		CtxField:     context.Background(),
+	}
+	m, ok := New().NewModuleInstance(mii).(*ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, rt.Set("metrics", m.Exports().Named))
+	_, err := rt.RunString(`var m = new metrics.Counter("my_metric")`)
+	require.NoError(t, err)
+
+	samples := make(chan metrics.SampleContainer, 10)
+	tags := lib.NewVUStateTags(registry.RootTagSet().WithTagsFromMap(map[string]string{"key": "value"}))
+	tags.Modify(func(tagsAndMeta *metrics.TagsAndMeta) {
+		tagsAndMeta.SetMetadata("vu_meta", "1")
+	})
+	mii.InitEnvField = nil
+	mii.StateField = &lib.State{
+		Options: lib.Options{Throw: null.BoolFrom(true)},
+		Samples: samples,
+		Tags:    tags,
+	}
+
+	_, err = rt.RunString(`m.add(1, { status: "ok" }, { trace_id: "abcd", order_id: 42 })`)
+	require.NoError(t, err)
+	sample, ok := (<-samples).(metrics.Sample)
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"key": "value", "status": "ok"}, sample.Tags.Map())
+	assert.Equal(t, map[string]string{"vu_meta": "1", "trace_id": "abcd", "order_id": "42"}, sample.Metadata)
+
+	_, err = rt.RunString(`m.add(1, null, { order_id: 43 })`)
+	require.NoError(t, err)
+	sample, ok = (<-samples).(metrics.Sample)
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"vu_meta": "1", "order_id": "43"}, sample.Metadata)
+
+	_, err = rt.RunString(`m.add(1, null, { order: { id: 44 } })`)
+	require.ErrorContains(t, err, "cannot add metadata for the 'my_metric' custom metric")
+}
@@ -0,0 +1,179 @@
+// Package random provides named streams of pseudo-random values that can be
+// seeded, so that the random values of a test run can be replayed exactly.
+package random
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+)
+
+type (
+	// RootModule is the global module instance that will create instances of our
+	// module for each VU.
+	RootModule struct{}
+
+	// ModuleInstance represents an instance of the random module for a single VU.
+	ModuleInstance struct {
+		vu modules.VU
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// New returns a pointer to a new [RootModule] instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface and returns a new
+// instance of our module for the given VU.
+func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	return &ModuleInstance{vu: vu}
+}
+
+// Exports implements the modules.Module interface and returns the exports of
+// our module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"new": mi.NewStream,
+		},
+	}
+}
+
+// NewStream returns a new named stream. It's seeded with the seed if it's
+// given, or else with the randomSeed option if it's set. Each VU generates a
+// different sequence of values from the same seed, and so does each name.
+func (mi *ModuleInstance) NewStream(name string, seedArg sobek.Value) *sobek.Object {
+	rt := mi.vu.Runtime()
+	if name == "" {
+		common.Throw(rt, errors.New("new() requires the name of the stream"))
+	}
+	var seed *int64
+	if !common.IsNullish(seedArg) {
+		var s int64
+		if err := rt.ExportTo(seedArg, &s); err != nil {
+			common.Throw(rt, err)
+		}
+		seed = &s
+	}
+
+	// The options and the VU aren't known yet if the stream is created in the
+	// init context, so the seed is resolved when the stream is first used.
+	resolveSeed := func() *int64 {
+		if seed != nil {
+			return seed
+		}
+		if state := mi.vu.State(); state != nil && state.Options.RandomSeed.Valid {
+			return &state.Options.RandomSeed.Int64
+		}
+		return nil
+	}
+	var s *Stream
+	stream := func() *Stream {
+		if s != nil {
+			return s
+		}
+		seed := resolveSeed()
+		if seed == nil {
+			s = newUnseededStream()
+			return s
+		}
+		var vuID uint64
+		if state := mi.vu.State(); state != nil {
+			vuID = state.VUIDGlobal
+		}
+		s = NewStream(*seed, name, vuID)
+		return s
+	}
+
+	obj := rt.NewObject()
+	for method, fn := range streamMethods(rt, stream) {
+		if err := obj.Set(method, fn); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+	if err := obj.Set("name", name); err != nil {
+		common.Throw(rt, err)
+	}
+	getSeed := rt.ToValue(func() sobek.Value {
+		if seed := resolveSeed(); seed != nil {
+			return rt.ToValue(*seed)
+		}
+		return sobek.Null()
+	})
+	if err := obj.DefineAccessorProperty("seed", getSeed, nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE); err != nil {
+		common.Throw(rt, err)
+	}
+	return obj
+}
+
+// streamMethods returns the JS methods that generate values with the stream.
+func streamMethods(rt *sobek.Runtime, stream func() *Stream) map[string]any {
+	return map[string]any{
+		"float": func() float64 { return stream().Float() },
+		"int": func(low, high int64) int64 {
+			n, err := stream().Int(low, high)
+			if err != nil {
+				common.Throw(rt, err)
+			}
+			return n
+		},
+		"bool": func(probability sobek.Value) bool {
+			p := 0.5
+			if !common.IsNullish(probability) {
+				p = probability.ToFloat()
+			}
+			return stream().Bool(p)
+		},
+		"normal": func(mean, stddev sobek.Value) float64 {
+			m, sd := 0.0, 1.0
+			if !common.IsNullish(mean) {
+				m = mean.ToFloat()
+			}
+			if !common.IsNullish(stddev) {
+				sd = stddev.ToFloat()
+			}
+			return stream().Normal(m, sd)
+		},
+		"pick": func(v sobek.Value) any {
+			values := arrayValues(rt, v)
+			if len(values) == 0 {
+				common.Throw(rt, errors.New("pick() requires a non-empty array"))
+			}
+			return values[stream().Index(len(values))]
+		},
+		"shuffle": func(v sobek.Value) *sobek.Object {
+			values := arrayValues(rt, v)
+			stream().Shuffle(len(values), func(i, j int) {
+				values[i], values[j] = values[j], values[i]
+			})
+			return rt.NewArray(values...)
+		},
+	}
+}
+
+// arrayValues returns the elements of a JS array.
+func arrayValues(rt *sobek.Runtime, v sobek.Value) []any {
+	if common.IsNullish(v) {
+		common.Throw(rt, errors.New("an array is required"))
+	}
+	obj := v.ToObject(rt)
+	if obj.ClassName() != "Array" {
+		common.Throw(rt, errors.New("an array is required"))
+	}
+	length := obj.Get("length").ToInteger()
+	values := make([]any, length)
+	for i := range values {
+		values[i] = obj.Get(strconv.Itoa(i))
+	}
+	return values
+}
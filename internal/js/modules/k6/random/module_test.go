@@ -0,0 +1,131 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/js/modulestest"
+	"go.k6.io/k6/lib"
+)
+
+func newTestRuntime(t *testing.T) *modulestest.Runtime {
+	t.Helper()
+
+	rt := modulestest.NewRuntime(t)
+	m, ok := New().NewModuleInstance(rt.VU).(*ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, rt.VU.Runtime().Set("random", m.Exports().Named))
+	return rt
+}
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	s := NewStream(1, "stream", 1)
+	for range 100 {
+		f := s.Float()
+		assert.True(t, f >= 0 && f < 1, f)
+		n, err := s.Int(3, 5)
+		require.NoError(t, err)
+		assert.True(t, n >= 3 && n <= 5, n)
+	}
+	_, err := s.Int(5, 3)
+	require.ErrorContains(t, err, "the max can't be lower than the min")
+
+	assert.Equal(t, NewStream(1, "stream", 1).Float(), NewStream(1, "stream", 1).Float())
+	assert.NotEqual(t, NewStream(1, "stream", 1).Float(), NewStream(2, "stream", 1).Float())
+	assert.NotEqual(t, NewStream(1, "stream", 1).Float(), NewStream(1, "other", 1).Float())
+	assert.NotEqual(t, NewStream(1, "stream", 1).Float(), NewStream(1, "stream", 2).Float())
+}
+
+func TestNewStream(t *testing.T) {
+	t.Parallel()
+
+	rt := newTestRuntime(t)
+	_, err := rt.VU.Runtime().RunString(`
+		var seeded = random.new("user-think", 42);
+		var again = random.new("user-think", 42);
+		var unseeded = random.new("user-think");
+	`)
+	require.NoError(t, err)
+	rt.MoveToVUContext(&lib.State{VUIDGlobal: 1})
+
+	_, err = rt.VU.Runtime().RunString(`
+		if (seeded.name !== "user-think" || seeded.seed !== 42 || unseeded.seed !== null) {
+			throw new Error("unexpected properties");
+		}
+		for (var i = 0; i < 10; i++) {
+			if (seeded.float() !== again.float()) {
+				throw new Error("the seeded streams differ");
+			}
+		}
+		for (var i = 0; i < 100; i++) {
+			var n = seeded.int(1, 6);
+			if (n < 1 || n > 6 || n !== Math.floor(n)) {
+				throw new Error("unexpected int " + n);
+			}
+			var v = seeded.pick(["a", "b", "c"]);
+			if (["a", "b", "c"].indexOf(v) < 0) {
+				throw new Error("unexpected pick " + v);
+			}
+		}
+		if (seeded.bool(1) !== true || seeded.bool(0) !== false) {
+			throw new Error("unexpected bool");
+		}
+		if (seeded.shuffle([3, 1, 2]).sort().join() !== "1,2,3") {
+			throw new Error("unexpected shuffle");
+		}
+		if (typeof seeded.normal(10, 2) !== "number") {
+			throw new Error("unexpected normal");
+		}
+	`)
+	require.NoError(t, err)
+}
+
+func TestNewStreamRandomSeed(t *testing.T) {
+	t.Parallel()
+
+	floats := func(vuID uint64) []float64 {
+		rt := newTestRuntime(t)
+		_, err := rt.VU.Runtime().RunString(`var stream = random.new("user-think");`)
+		require.NoError(t, err)
+		rt.MoveToVUContext(&lib.State{VUIDGlobal: vuID, Options: lib.Options{RandomSeed: null.IntFrom(7)}})
+
+		v, err := rt.VU.Runtime().RunString(`
+			if (stream.seed !== 7) {
+				throw new Error("unexpected seed " + stream.seed);
+			}
+			[stream.float(), stream.float()]
+		`)
+		require.NoError(t, err)
+		var got []float64
+		require.NoError(t, rt.VU.Runtime().ExportTo(v, &got))
+		return got
+	}
+	assert.Equal(t, floats(1), floats(1))
+	assert.NotEqual(t, floats(1), floats(2))
+}
+
+func TestNewStreamErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`random.new("")`:                    "new() requires the name of the stream",
+		`random.new("s", 1).int(5, 1)`:      "the max can't be lower than the min",
+		`random.new("s", 1).pick([])`:       "pick() requires a non-empty array",
+		`random.new("s", 1).shuffle("abc")`: "an array is required",
+		`random.new("s", 1).pick(null)`:     "an array is required",
+	}
+	for script, want := range tests {
+		t.Run(script, func(t *testing.T) {
+			t.Parallel()
+
+			rt := newTestRuntime(t)
+			_, err := rt.VU.Runtime().RunString(script)
+			require.ErrorContains(t, err, want)
+		})
+	}
+}
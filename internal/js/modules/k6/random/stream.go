@@ -0,0 +1,61 @@
+package random
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math/rand/v2"
+)
+
+// Stream is a named stream of pseudo-random values. The same seed, name and
+// VU always generate the same sequence of values.
+type Stream struct {
+	rand *rand.Rand
+}
+
+// NewStream returns a new Stream seeded with the seed, the name and the VU.
+func NewStream(seed int64, name string, vuID uint64) *Stream {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	_ = binary.Write(h, binary.LittleEndian, vuID)
+	return &Stream{rand: rand.New(rand.NewPCG(uint64(seed), h.Sum64()))} //nolint:gosec
+}
+
+// newUnseededStream returns a new Stream that generates a different sequence
+// of values in every run.
+func newUnseededStream() *Stream {
+	return &Stream{rand: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))} //nolint:gosec
+}
+
+// Float returns a value in [0, 1).
+func (s *Stream) Float() float64 {
+	return s.rand.Float64()
+}
+
+// Int returns a value in [low, high].
+func (s *Stream) Int(low, high int64) (int64, error) {
+	if high < low {
+		return 0, errors.New("the max can't be lower than the min")
+	}
+	return low + s.rand.Int64N(high-low+1), nil
+}
+
+// Bool returns true with the probability.
+func (s *Stream) Bool(probability float64) bool {
+	return s.rand.Float64() < probability
+}
+
+// Normal returns a normally distributed value.
+func (s *Stream) Normal(mean, stddev float64) float64 {
+	return mean + s.rand.NormFloat64()*stddev
+}
+
+// Index returns a value in [0, n).
+func (s *Stream) Index(n int) int {
+	return s.rand.IntN(n)
+}
+
+// Shuffle shuffles n elements with the swap function.
+func (s *Stream) Shuffle(n int, swap func(i, j int)) {
+	s.rand.Shuffle(n, swap)
+}
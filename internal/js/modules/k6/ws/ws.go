@@ -23,6 +23,7 @@ import (
 	"go.k6.io/k6/js/modules"
 	httpModule "go.k6.io/k6/js/modules/k6/http"
 	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
 
@@ -277,6 +278,11 @@ func (mi *WS) dial(
 			args.tagsAndMeta.SetSystemTagOrMeta(metrics.TagIP, ip)
 		}
 	}
+	if state.Options.SystemTags.Has(metrics.TagIPFamily) && conn.RemoteAddr() != nil {
+		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+			args.tagsAndMeta.SetSystemTagOrMeta(metrics.TagIPFamily, types.IPFamily(addr.IP))
+		}
+	}
 
 	if httpResponse != nil {
 		if state.Options.SystemTags.Has(metrics.TagStatus) {
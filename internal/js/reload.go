@@ -0,0 +1,49 @@
+package js
+
+import (
+	"fmt"
+
+	"go.k6.io/k6/internal/loader"
+	"go.k6.io/k6/js/modules"
+	"go.k6.io/k6/lib/fsext"
+)
+
+// Reload bundles the changed source of the script, which the VUs use from
+// their next iteration on, after their JS runtime is replaced with one
+// initialized from it. The options of the running test are kept, so the
+// changes to the exported options have no effect, and the exported functions
+// that the scenarios execute have to still be exported.
+func (r *Runner) Reload(src *loader.SourceData, filesystems map[string]fsext.Fs, mr *modules.ModuleResolver) error {
+	bundle, err := newBundle(r.preInitState, src, filesystems, r.Bundle.Options, false, mr)
+	if err != nil {
+		return err
+	}
+	for name := range r.Bundle.callableExports {
+		if _, ok := bundle.callableExports[name]; !ok {
+			return fmt.Errorf("the reloaded script doesn't export the function %q anymore", name)
+		}
+	}
+	r.reloadedBundle.Store(bundle)
+	return nil
+}
+
+// currentBundle returns the bundle that the VUs are initialized from, the
+// last reloaded one, if the script was reloaded.
+func (r *Runner) currentBundle() *Bundle {
+	if bundle := r.reloadedBundle.Load(); bundle != nil {
+		return bundle
+	}
+	return r.Bundle
+}
+
+// reloadIfChanged replaces the JS runtime of the VU with one initialized from
+// the reloaded script, if it was reloaded since the VU was initialized.
+func (u *ActiveVU) reloadIfChanged() {
+	if u.Runner.currentBundle() == u.bundle {
+		return
+	}
+	if err := u.restart(); err != nil {
+		// the VU keeps running the previous code, and tries again on its next iteration
+		u.state.Logger.WithError(err).Error("Couldn't initialize the VU with the reloaded script")
+	}
+}
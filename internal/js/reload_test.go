@@ -0,0 +1,68 @@
+package js
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/loader"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/metrics"
+)
+
+func TestRunnerReload(t *testing.T) {
+	t.Parallel()
+
+	fs := fsext.NewMemMapFs()
+	r, err := getSimpleRunner(t, "/script.js", `
+		exports.default = function() { globalThis.version = 1; };
+		exports.other = function() {};
+	`, fs)
+	require.NoError(t, err)
+
+	reload := func(data string) error {
+		filesystems := map[string]fsext.Fs{"file": fs}
+		src := &loader.SourceData{URL: &url.URL{Path: "/script.js", Scheme: "file"}, Data: []byte(data)}
+		pwd := src.URL.JoinPath("../")
+		mr := NewModuleResolver(pwd, r.preInitState, filesystems)
+		if err := mr.LoadMainModule(pwd, src.URL.String(), src.Data); err != nil {
+			return err
+		}
+		return r.Reload(src, filesystems, mr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	initVU, err := r.NewVU(ctx, 1, 1, make(chan metrics.SampleContainer, 100))
+	require.NoError(t, err)
+	vu := initVU.Activate(&lib.VUActivationParams{RunContext: ctx})
+	version := func() int64 {
+		return initVU.(*VU).Runtime.Get("version").ToInteger()
+	}
+
+	require.NoError(t, vu.RunOnce())
+	assert.Equal(t, int64(1), version())
+
+	require.NoError(t, reload(`
+		exports.default = function() { globalThis.version = 2; };
+		exports.other = function() {};
+	`))
+	require.NoError(t, vu.RunOnce())
+	assert.Equal(t, int64(2), version())
+
+	// the functions that the scenarios might execute have to be kept
+	err = reload(`exports.default = function() { globalThis.version = 3; };`)
+	require.ErrorContains(t, err, `the reloaded script doesn't export the function "other" anymore`)
+	require.NoError(t, vu.RunOnce())
+	assert.Equal(t, int64(2), version())
+
+	// the new VUs are initialized from the reloaded script
+	newVU, err := r.NewVU(ctx, 2, 2, make(chan metrics.SampleContainer, 100))
+	require.NoError(t, err)
+	require.NoError(t, newVU.Activate(&lib.VUActivationParams{RunContext: ctx}).RunOnce())
+	assert.Equal(t, int64(2), newVU.(*VU).Runtime.Get("version").ToInteger())
+}
@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/grafana/sobek"
@@ -33,6 +34,7 @@ import (
 	"go.k6.io/k6/lib"
 	"go.k6.io/k6/lib/fsext"
 	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/lib/netext/httpext"
 	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
@@ -56,11 +58,18 @@ type Runner struct {
 	// TODO: Remove ActualResolver, it's a hack to simplify mocking in tests.
 	ActualResolver netext.MultiResolver
 	RPSLimit       *rate.Limiter
+	RequestLimiter *lib.RequestLimiter
 	RunTags        *metrics.TagSet
 
 	console    *console
 	setupData  []byte
 	BufferPool *lib.BufferPool
+
+	httpCassette          *httpext.Cassette
+	httpCassetteRecording bool
+
+	// reloadedBundle is the last bundle of the script reloaded with Reload.
+	reloadedBundle atomic.Pointer[Bundle]
 }
 
 // New returns a new Runner for the provided source
@@ -129,7 +138,8 @@ func (r *Runner) newVU(
 	ctx context.Context, idLocal, idGlobal uint64, samplesOut chan<- metrics.SampleContainer,
 ) (*VU, error) {
 	// Instantiate a new bundle, make a VU out of it.
-	bi, err := r.Bundle.Instantiate(ctx, idLocal)
+	bundle := r.currentBundle()
+	bi, err := bundle.Instantiate(ctx, idLocal)
 	if err != nil {
 		return nil, err
 	}
@@ -165,6 +175,10 @@ func (r *Runner) newVU(
 		BlockedHostnames: r.Bundle.Options.BlockedHostnames.Trie,
 		Hosts:            r.Bundle.Options.Hosts.Trie,
 	}
+	dialer.SetFamily(r.Bundle.Options.DialFamily.String)
+	if r.Bundle.Options.ConnectionMetrics.Bool {
+		dialer.ConnStats = r.preInitState.ConnStats
+	}
 	if r.Bundle.Options.LocalIPs.Valid {
 		var ipIndex uint64
 		if idLocal > 0 {
@@ -220,6 +234,7 @@ func (r *Runner) newVU(
 		IDGlobal:       idGlobal,
 		iteration:      int64(-1),
 		BundleInstance: *bi,
+		bundle:         bundle,
 		Runner:         r,
 		Transport:      transport,
 		Dialer:         dialer,
@@ -234,7 +249,7 @@ func (r *Runner) newVU(
 	vu.state = &lib.State{
 		Logger:         vu.Runner.preInitState.Logger,
 		Options:        vu.Runner.Bundle.Options,
-		Transport:      vu.Transport,
+		Transport:      vu.Runner.httpTransport(vu.Transport),
 		Dialer:         vu.Dialer,
 		TLSConfig:      vu.TLSConfig,
 		CookieJar:      cookieJar,
@@ -248,6 +263,12 @@ func (r *Runner) newVU(
 		TracerProvider: r.preInitState.TracerProvider,
 		Usage:          r.preInitState.Usage,
 		TestStatus:     r.preInitState.TestStatus,
+		RunMetadata:    r.preInitState.RunMetadata,
+
+		IsolatedTransports: lib.NewIsolatedTransports(),
+	}
+	if vu.Runner.Bundle.Options.IterationBreakdown.Bool {
+		vu.state.IterationBreakdown = &lib.IterationBreakdown{}
 	}
 	vu.moduleVUImpl.state = vu.state
 	_ = vu.Runtime.Set("console", vu.Console)
@@ -452,7 +473,9 @@ func prepareHandleSummaryCall(
 			handleSummaryData = rt.ToValue(mrSummary)
 			err = errors.Join(srErr, mrsErr)
 		} else {
-			handleSummaryData = rt.ToValue(summarizeMetricsToObject(legacy, r.Bundle.Options, r.setupData))
+			data := summarizeMetricsToObject(legacy, r.Bundle.Options, r.setupData)
+			data["time_series"] = summarizeTimeSeries(s.TimeSeries)
+			handleSummaryData = rt.ToValue(data)
 		}
 	} else {
 		noColor = legacy.NoColor
@@ -539,6 +562,10 @@ func (r *Runner) SetOptions(opts lib.Options) error {
 	if rps := opts.RPS; rps.Valid && rps.Int64 > 0 {
 		r.RPSLimit = rate.NewLimiter(rate.Limit(rps.Int64), 1)
 	}
+	r.RequestLimiter = nil
+	if limit := opts.MaxConcurrentRequests; limit.Valid && limit.Int64 > 0 {
+		r.RequestLimiter = lib.NewRequestLimiter(limit.Int64)
+	}
 
 	// TODO: validate that all exec values are either nil or valid exported methods (or HTTP requests in the future)
 
@@ -573,6 +600,10 @@ func (r *Runner) SetOptions(opts lib.Options) error {
 		return err
 	}
 
+	if err := r.setHTTPCassette(opts); err != nil {
+		return err
+	}
+
 	// FIXME: add tests
 	r.RunTags = r.preInitState.Registry.RootTagSet().WithTagsFromMap(r.Bundle.Options.RunTags)
 
@@ -719,6 +750,20 @@ type VU struct {
 	state *lib.State
 	// count of iterations executed by this VU in each scenario
 	scenarioIter map[string]uint64
+
+	// tlsSessionCache holds the TLS sessions of the VU, for the scenarios
+	// that resume them with session tickets.
+	tlsSessionCache tls.ClientSessionCache
+
+	// runtimeLock guards replacing the runtime when the VU is restarted
+	// because of the memory limit
+	runtimeLock     sync.Mutex
+	lastMemoryCheck time.Time
+	memoryWarned    bool
+
+	// bundle is the bundle that the runtime was initialized from, which
+	// changes when the script is reloaded.
+	bundle *Bundle
 }
 
 // Verify that interfaces are implemented
@@ -727,6 +772,8 @@ var (
 	_ lib.InitializedVU = &VU{}
 )
 
+var _ lib.ActiveVUWithTeardown = &ActiveVU{}
+
 // ActiveVU holds a VU and its activation parameters
 type ActiveVU struct {
 	*VU
@@ -736,6 +783,14 @@ type ActiveVU struct {
 	scenarioName              string
 	getNextIterationCounters  func() (uint64, uint64)
 	scIterLocal, scIterGlobal uint64
+	activeEnv                 map[string]string
+
+	// pacer draws the think times after the iterations, if the scenario has
+	// pacing.
+	pacer *lib.Pacer
+
+	// mixer picks the function of every iteration, if the scenario has a mix.
+	mixer *lib.Mixer
 }
 
 // GetID returns the unique VU ID.
@@ -743,6 +798,38 @@ func (u *VU) GetID() uint64 {
 	return u.ID
 }
 
+// setTLSSessionTickets enables or disables the resumption of the TLS sessions
+// with session tickets, for the new connections of the VU.
+func (u *VU) setTLSSessionTickets(enabled bool) {
+	if !enabled {
+		u.TLSConfig.ClientSessionCache = nil
+		return
+	}
+	if u.tlsSessionCache == nil {
+		u.tlsSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+	u.TLSConfig.ClientSessionCache = u.tlsSessionCache
+}
+
+// setDialFamily restricts the connections of the VU to the address family of
+// the scenario, or to the one of the global dialFamily option if the scenario
+// doesn't set it. The idle connections are closed when the family changes,
+// since they might be of the previous one.
+func (u *VU) setDialFamily(family string) {
+	if family == "" {
+		family = u.Runner.Bundle.Options.DialFamily.String
+	}
+	if family == types.DialFamilyAny {
+		family = ""
+	}
+	if family == u.Dialer.Family() {
+		return
+	}
+	u.Dialer.SetFamily(family)
+	u.Transport.CloseIdleConnections()
+	u.state.IsolatedTransports.CloseIdleConnections()
+}
+
 // Activate the VU so it will be able to run code.
 func (u *VU) Activate(params *lib.VUActivationParams) lib.ActiveVU {
 	u.Runtime.ClearInterrupt()
@@ -788,6 +875,15 @@ func (u *VU) Activate(params *lib.VUActivationParams) lib.ActiveVU {
 		return u.scenarioIter[params.Scenario]
 	}
 
+	u.state.RequestLimiter = nil
+	if u.Runner.RequestLimiter != nil {
+		u.state.RequestLimiter = u.Runner.RequestLimiter.Scenario(params.Scenario, params.Weight)
+	}
+
+	u.setTLSSessionTickets(params.TLSSessionTickets)
+	u.setDialFamily(params.DialFamily)
+	u.state.RequestDefaults = params.RequestDefaults
+
 	avu := &ActiveVU{
 		VU:                       u,
 		VUActivationParams:       params,
@@ -796,6 +892,13 @@ func (u *VU) Activate(params *lib.VUActivationParams) lib.ActiveVU {
 		scIterLocal:              ^uint64(0),
 		scIterGlobal:             ^uint64(0),
 		getNextIterationCounters: params.GetNextIterationCounters,
+		activeEnv:                env,
+	}
+	if params.Pacing != nil {
+		avu.pacer = params.Pacing.NewPacer(u.IDGlobal)
+	}
+	if params.Mix != nil {
+		avu.mixer = params.Mix.NewMixer(opts.RandomSeed, u.IDGlobal)
 	}
 
 	u.state.GetScenarioLocalVUIter = func() uint64 {
@@ -808,7 +911,9 @@ func (u *VU) Activate(params *lib.VUActivationParams) lib.ActiveVU {
 	// Wait for the run context to be over
 	context.AfterFunc(ctx, func() {
 		// Interrupt the JS runtime
+		u.runtimeLock.Lock()
 		u.Runtime.Interrupt(context.Canceled)
+		u.runtimeLock.Unlock()
 		// Wait for the VU to stop running, if it was, and prevent it from
 		// running again for this activation
 		avu.busy <- struct{}{}
@@ -833,6 +938,8 @@ func (u *ActiveVU) RunOnce() error {
 		<-u.busy // unlock deactivation again
 	}()
 
+	u.reloadIfChanged()
+
 	// Unmarshall the setupData only the first time for each VU so that VUs are isolated but we
 	// still don't use too much CPU in the middle test
 	if u.setupData == nil {
@@ -847,10 +954,32 @@ func (u *ActiveVU) RunOnce() error {
 		}
 	}
 
-	fn := u.getCallableExport(u.Exec)
+	exec := u.Exec
+	if u.mixer != nil {
+		exec = u.mixer.Next()
+		if u.Runner.Bundle.Options.SystemTags.Has(metrics.TagJourney) {
+			// the system tag takes precedence over a journey tag of the user,
+			// which is restored after the iteration
+			var prevJourney string
+			var hadJourney bool
+			u.state.Tags.Modify(func(tagsAndMeta *metrics.TagsAndMeta) {
+				prevJourney, hadJourney = tagsAndMeta.Tags.Get(metrics.TagJourney.String())
+				tagsAndMeta.SetSystemTagOrMeta(metrics.TagJourney, exec)
+			})
+			defer u.state.Tags.Modify(func(tagsAndMeta *metrics.TagsAndMeta) {
+				if hadJourney {
+					tagsAndMeta.SetTag(metrics.TagJourney.String(), prevJourney)
+				} else {
+					tagsAndMeta.DeleteTag(metrics.TagJourney.String())
+				}
+			})
+		}
+	}
+
+	fn := u.getCallableExport(exec)
 	if fn == nil {
 		// Shouldn't happen; this is validated in cmd.validateScenarioConfig()
-		panic(fmt.Sprintf("function '%s' not found in exports", u.Exec))
+		panic(fmt.Sprintf("function '%s' not found in exports", exec))
 	}
 
 	u.incrIteration()
@@ -858,9 +987,36 @@ func (u *ActiveVU) RunOnce() error {
 		panic(fmt.Errorf("error setting __ITER in Sobek runtime: %w", err))
 	}
 
-	ctx, cancel := context.WithCancel(u.RunContext)
+	if u.isWarmupIteration() {
+		// A phase tag of the user is overridden only during the warm-up iterations
+		var userPhase string
+		var hasUserPhase bool
+		u.state.Tags.Modify(func(tagsAndMeta *metrics.TagsAndMeta) {
+			userPhase, hasUserPhase = tagsAndMeta.Tags.Get(metrics.PhaseTagName)
+			tagsAndMeta.SetTag(metrics.PhaseTagName, metrics.WarmupPhaseTagVal)
+		})
+		defer u.state.Tags.Modify(func(tagsAndMeta *metrics.TagsAndMeta) {
+			if hasUserPhase {
+				tagsAndMeta.SetTag(metrics.PhaseTagName, userPhase)
+			} else {
+				tagsAndMeta.DeleteTag(metrics.PhaseTagName)
+			}
+		})
+	}
+
+	var (
+		ctx              context.Context
+		cancel           context.CancelFunc
+		iterationTimeout = u.getIterationTimeout()
+	)
+	if iterationTimeout > 0 {
+		ctx, cancel = context.WithTimeoutCause(u.RunContext, iterationTimeout, lib.ErrIterationTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(u.RunContext)
+	}
 	defer cancel()
 	u.moduleVUImpl.ctx = ctx
+	stopTimeoutInterrupt := u.interruptOnIterationTimeout(ctx)
 
 	eventIterData := event.IterData{
 		Iteration:    u.iteration,
@@ -880,11 +1036,21 @@ func (u *ActiveVU) RunOnce() error {
 				err = v
 			}
 		}
+	}
+	if stopTimeoutInterrupt() {
+		err = fmt.Errorf("%w after %s", lib.ErrIterationTimeout, iterationTimeout)
+		u.emitIterationTimedOut()
+	}
+	if err != nil {
 		eventIterData.Error = err
 	}
 
 	u.emitAndWaitEvent(&event.Event{Type: event.IterEnd, Data: eventIterData})
 
+	if limit := u.Runner.Bundle.Options.VUMemoryLimit.Int64; limit > 0 {
+		u.checkMemoryLimit(uint64(limit))
+	}
+
 	// If MinIterationDuration is specified and the iteration wasn't canceled
 	// and was less than it, sleep for the remainder
 	if isFullIteration && u.Runner.Bundle.Options.MinIterationDuration.Valid {
@@ -897,9 +1063,119 @@ func (u *ActiveVU) RunOnce() error {
 		}
 	}
 
+	// If the scenario has pacing and the iteration wasn't canceled, think
+	// before the next iteration
+	if isFullIteration && u.pacer != nil {
+		select {
+		case <-time.After(u.pacer.Next()):
+		case <-u.RunContext.Done():
+		}
+	}
+
+	return err
+}
+
+// RunVUTeardown runs the vuTeardown() function of the script, if it's exported,
+// when the VU is stopped by a scale-down. It gets the setup data and sees the
+// cookies of the last iteration, so it can log out its sessions.
+func (u *ActiveVU) RunVUTeardown() error {
+	if !u.Runner.IsExecutable(consts.VUTeardownFn) {
+		return nil
+	}
+	select {
+	case <-u.RunContext.Done():
+		return u.RunContext.Err() // we are done, return
+	case u.busy <- struct{}{}:
+		// nothing else can run now, and the VU cannot be deactivated
+	}
+	defer func() {
+		<-u.busy // unlock deactivation again
+	}()
+
+	fn := u.getCallableExport(consts.VUTeardownFn)
+	if fn == nil {
+		return nil // the VU still runs a version of the script without it
+	}
+	setupData := u.setupData
+	if setupData == nil {
+		setupData = sobek.Undefined()
+	}
+
+	ctx, cancel := context.WithCancel(u.RunContext)
+	defer cancel()
+	u.moduleVUImpl.ctx = ctx
+	_, _, _, err := u.runFn(ctx, false, fn, cancel, setupData)
 	return err
 }
 
+// getIterationTimeout returns the scenario-specific iteration timeout, falling
+// back to the global iterationTimeout option.
+func (u *ActiveVU) getIterationTimeout() time.Duration {
+	if u.IterationTimeout.Valid {
+		return u.IterationTimeout.TimeDuration()
+	}
+	return u.Runner.Bundle.Options.IterationTimeout.TimeDuration()
+}
+
+// isWarmupIteration returns whether the current iteration is among the first
+// warmupIterations ones of the scenario in this instance or if it was started
+// during the warmupDuration period after the start of the scenario.
+func (u *ActiveVU) isWarmupIteration() bool {
+	if u.WarmupIterations > 0 && u.getNextIterationCounters != nil &&
+		u.scIterLocal < uint64(u.WarmupIterations) {
+		return true
+	}
+	if u.WarmupDuration > 0 {
+		if ss := lib.GetScenarioState(u.RunContext); ss != nil {
+			return time.Since(ss.StartTime) < u.WarmupDuration
+		}
+	}
+	return false
+}
+
+// interruptOnIterationTimeout interrupts the JS runtime if the given iteration
+// context is done because the iteration timeout was reached. The returned
+// function has to be called after the iteration is over, it returns whether
+// the iteration was interrupted because of the timeout and, if that's the
+// case, it also clears the runtime interrupt so the VU can continue with the
+// next iteration.
+func (u *ActiveVU) interruptOnIterationTimeout(ctx context.Context) func() bool {
+	interrupted := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		defer close(interrupted)
+		if errors.Is(context.Cause(ctx), lib.ErrIterationTimeout) && u.RunContext.Err() == nil {
+			u.runtimeLock.Lock()
+			u.Runtime.Interrupt(lib.ErrIterationTimeout)
+			u.runtimeLock.Unlock()
+		}
+	})
+
+	return func() bool {
+		if stop() {
+			return false // the iteration finished before the context was done
+		}
+		<-interrupted
+		if !errors.Is(context.Cause(ctx), lib.ErrIterationTimeout) || u.RunContext.Err() != nil {
+			return false
+		}
+		u.Runtime.ClearInterrupt()
+		return true
+	}
+}
+
+func (u *ActiveVU) emitIterationTimedOut() {
+	ctm := u.state.Tags.GetCurrentValues()
+	u.state.Samples <- metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: u.Runner.preInitState.BuiltinMetrics.IterationsTimedOut,
+			Tags:   ctm.Tags,
+		},
+		Time:     time.Now(),
+		Metadata: ctm.Metadata,
+		Value:    1,
+	}
+}
+
 func (u *ActiveVU) emitAndWaitEvent(evt *event.Event) {
 	waitDone := u.moduleVUImpl.events.local.Emit(evt)
 	waitCtx, waitCancel := context.WithTimeout(u.RunContext, 30*time.Minute)
@@ -918,7 +1194,9 @@ func (u *VU) getExported(name string) sobek.Value {
 func (u *VU) runFn(
 	ctx context.Context, isDefault bool, fn sobek.Callable, cancel func(), args ...sobek.Value,
 ) (v sobek.Value, isFullIteration bool, t time.Duration, err error) {
-	if !u.Runner.Bundle.Options.NoCookiesReset.ValueOrZero() {
+	// the cookies are reset only for the iterations, the other functions run
+	// either in a new VU or, like vuTeardown(), with the last iteration ones
+	if isDefault && !u.Runner.Bundle.Options.NoCookiesReset.ValueOrZero() {
 		u.state.CookieJar, err = cookiejar.New(nil)
 		if err != nil {
 			return sobek.Undefined(), false, time.Duration(0), err
@@ -933,6 +1211,7 @@ func (u *VU) runFn(
 		})
 	}
 
+	u.state.IterationBreakdown.Reset()
 	startTime := time.Now()
 
 	if u.moduleVUImpl.eventLoop == nil {
@@ -962,6 +1241,7 @@ func (u *VU) runFn(
 
 	if u.Runner.Bundle.Options.NoVUConnectionReuse.Bool {
 		u.Transport.CloseIdleConnections()
+		u.state.IsolatedTransports.CloseIdleConnections()
 	}
 
 	builtinMetrics := u.Runner.preInitState.BuiltinMetrics
@@ -970,6 +1250,10 @@ func (u *VU) runFn(
 
 	if isFullIteration && isDefault {
 		u.state.Samples <- iterationSamples(startTime, endTime, ctm, builtinMetrics)
+		if u.state.IterationBreakdown != nil {
+			u.state.Samples <- iterationBreakdownSamples(
+				u.state.IterationBreakdown, startTime, endTime, ctm, builtinMetrics)
+		}
 	}
 
 	v = unPromisify(v)
@@ -1002,6 +1286,42 @@ func iterationSamples(
 	})
 }
 
+// iterationBreakdownSamples splits the duration of the iteration into the time
+// spent sleeping, waiting for the network and running the script, which is
+// whatever remains. Concurrent asynchronous requests can overlap, so the
+// network time is capped to the time that the iteration didn't sleep.
+func iterationBreakdownSamples(
+	breakdown *lib.IterationBreakdown, startTime, endTime time.Time,
+	ctm metrics.TagsAndMeta, builtinMetrics *metrics.BuiltinMetrics,
+) metrics.Samples {
+	total := endTime.Sub(startTime)
+	sleep, network := breakdown.Reset()
+	sleep = min(sleep, total)
+	network = min(network, total-sleep)
+	script := total - sleep - network
+
+	samples := make([]metrics.Sample, 0, 3)
+	for _, part := range []struct {
+		metric *metrics.Metric
+		value  time.Duration
+	}{
+		{builtinMetrics.IterationDurationScript, script},
+		{builtinMetrics.IterationDurationSleep, sleep},
+		{builtinMetrics.IterationDurationNetwork, network},
+	} {
+		samples = append(samples, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: part.metric,
+				Tags:   ctm.Tags,
+			},
+			Time:     endTime,
+			Metadata: ctm.Metadata,
+			Value:    metrics.D(part.value),
+		})
+	}
+	return metrics.Samples(samples)
+}
+
 func (u *ActiveVU) incrIteration() {
 	u.iteration++
 	u.state.Iteration = u.iteration
@@ -762,6 +762,98 @@ func TestVUIntegrationMetrics(t *testing.T) {
 	}
 }
 
+func TestVUIntegrationIterationBreakdown(t *testing.T) {
+	t.Parallel()
+	tb := httpmultibin.NewHTTPMultiBin(t)
+
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+			t.Parallel()
+
+			r, err := getSimpleRunner(t, "/script.js", tb.Replacer.Replace(`
+				var http = require("k6/http");
+				var sleep = require("k6").sleep;
+				exports.default = function() {
+					http.get("HTTPBIN_URL/get");
+					sleep(0.1);
+				}
+			`))
+			require.NoError(t, err)
+			r.Bundle.Options.Hosts = types.NullHosts{Trie: tb.Dialer.Hosts}
+			r.Bundle.Options.IterationBreakdown = null.BoolFrom(enabled)
+
+			samples := make(chan metrics.SampleContainer, 100)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			vu, err := r.newVU(ctx, 1, 1, samples)
+			require.NoError(t, err)
+			require.NoError(t, vu.Activate(&lib.VUActivationParams{RunContext: ctx}).RunOnce())
+
+			values := make(map[string]float64)
+			for _, sc := range metrics.GetBufferedSamples(samples) {
+				for _, s := range sc.GetSamples() {
+					values[s.Metric.Name] = s.Value
+				}
+			}
+			if !enabled {
+				assert.NotContains(t, values, metrics.IterationDurationScriptName)
+				assert.NotContains(t, values, metrics.IterationDurationSleepName)
+				assert.NotContains(t, values, metrics.IterationDurationNetworkName)
+				return
+			}
+			assert.GreaterOrEqual(t, values[metrics.IterationDurationSleepName], 100.0)
+			assert.Greater(t, values[metrics.IterationDurationNetworkName], 0.0)
+			assert.GreaterOrEqual(t, values[metrics.IterationDurationScriptName], 0.0)
+			assert.InDelta(t, values[metrics.IterationDurationName],
+				values[metrics.IterationDurationScriptName]+values[metrics.IterationDurationSleepName]+
+					values[metrics.IterationDurationNetworkName], 0.001)
+		})
+	}
+}
+
+func TestVUIntegrationConnectionMetrics(t *testing.T) {
+	t.Parallel()
+	tb := httpmultibin.NewHTTPMultiBin(t)
+
+	r, err := getSimpleRunner(t, "/script.js", tb.Replacer.Replace(`
+		var http = require("k6/http");
+		exports.default = function() {
+			http.get("HTTPBIN_URL/get");
+			http.get("HTTPBIN_URL/get");
+		}
+	`))
+	require.NoError(t, err)
+	r.Bundle.Options.Hosts = types.NullHosts{Trie: tb.Dialer.Hosts}
+	r.Bundle.Options.ConnectionMetrics = null.BoolFrom(true)
+
+	samples := make(chan metrics.SampleContainer, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	vu, err := r.newVU(ctx, 1, 1, samples)
+	require.NoError(t, err)
+	require.NoError(t, vu.Activate(&lib.VUActivationParams{RunContext: ctx}).RunOnce())
+
+	connStats := r.preInitState.ConnStats
+	builtinMetrics := r.preInitState.BuiltinMetrics
+	var values map[string]float64
+	var opened float64
+	// the connections are returned to the idle pool in the background
+	assert.Eventually(t, func() bool {
+		values = make(map[string]float64)
+		for _, s := range connStats.Samples(time.Now(), r.RunTags, &metrics.DefaultSystemTagSet, builtinMetrics) {
+			values[s.Metric.Name] = s.Value
+			if host, ok := s.Tags.Get(metrics.TagRemoteHost.String()); ok {
+				assert.Equal(t, "httpbin.local", host)
+			}
+		}
+		opened += values[metrics.NetConnsOpenedName]
+		return values[metrics.NetConnsIdleName] == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0.0, values[metrics.NetConnsActiveName])
+	assert.Equal(t, 1.0, values[metrics.NetEphemeralPortsName])
+	assert.Equal(t, 1.0, opened)
+}
+
 func generateTLSCertificate(t *testing.T, host string, notBefore time.Time, validFor time.Duration) ([]byte, []byte) {
 	return generateTLSCertificateWithCA(t, host, notBefore, validFor, nil, nil)
 }
@@ -1502,6 +1594,48 @@ func TestVUIntegrationCookiesReset(t *testing.T) {
 	}
 }
 
+func TestVUIntegrationVUTeardown(t *testing.T) {
+	t.Parallel()
+	tb := httpmultibin.NewHTTPMultiBin(t)
+
+	r, err := getSimpleRunner(t, "/script.js", tb.Replacer.Replace(`
+			var http = require("k6/http");
+			var url = "HTTPBIN_URL";
+			var iterations = 0;
+			exports.default = function() {
+				iterations++;
+				var res = http.get(url + "/cookies/set?session=" + __ITER);
+				if (res.status != 200) { throw new Error("wrong status: " + res.status) }
+			}
+			exports.vuTeardown = function() {
+				var res = http.get(url + "/cookies");
+				if (res.json().session != "1") {
+					throw new Error("wrong cookies: " + res.body);
+				}
+				throw new Error("logged out after " + iterations + " iterations");
+			}
+		`))
+	require.NoError(t, err)
+	require.NoError(t, r.SetOptions(lib.Options{
+		Throw:        null.BoolFrom(true),
+		MaxRedirects: null.IntFrom(10),
+		Hosts:        types.NullHosts{Trie: tb.Dialer.Hosts},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	initVU, err := r.NewVU(ctx, 1, 1, make(chan metrics.SampleContainer, 100))
+	require.NoError(t, err)
+	vu := initVU.Activate(&lib.VUActivationParams{RunContext: ctx})
+	for i := 0; i < 2; i++ {
+		require.NoError(t, vu.RunOnce())
+	}
+
+	tvu, ok := vu.(lib.ActiveVUWithTeardown)
+	require.True(t, ok)
+	require.ErrorContains(t, tvu.RunVUTeardown(), "logged out after 2 iterations")
+}
+
 func TestVUIntegrationCookiesNoReset(t *testing.T) {
 	t.Parallel()
 	tb := httpmultibin.NewHTTPMultiBin(t)
@@ -1998,6 +2132,7 @@ func TestSystemTags(t *testing.T) {
 		{"url", "http_get", tb.ServerHTTP.URL},
 		{"url", "https_get", tb.ServerHTTPS.URL},
 		{"ip", "http_get", httpURL.Hostname()},
+		{"ip_family", "http_get", "ipv4"},
 		{"name", "http_get", tb.ServerHTTP.URL},
 		{"group", "http_get", ""},
 		{"vu", "http_get", "8"},
@@ -2006,6 +2141,7 @@ func TestSystemTags(t *testing.T) {
 		{"iter", "noop", "0"},
 		{"tls_version", "https_get", "tls1.3"},
 		{"ocsp_status", "https_get", "unknown"},
+		{"tls_resumed", "https_get", "false"},
 		{"error", "bad_url_get", `dial: connection refused`},
 		{"error_code", "bad_url_get", "1212"},
 		{"scenario", "http_get", "default"},
@@ -2063,6 +2199,88 @@ func TestSystemTags(t *testing.T) {
 	}
 }
 
+func TestVUTLSSessionTickets(t *testing.T) {
+	t.Parallel()
+	tb := httpmultibin.NewHTTPMultiBin(t)
+
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+			t.Parallel()
+			r, err := getSimpleRunner(t, "/script.js", tb.Replacer.Replace(fmt.Sprintf(`
+				var http = require("k6/http");
+				exports.default = function() {
+					var resumed = [];
+					for (var i = 0; i < 3; i++) {
+						resumed.push(http.get("HTTPSBIN_IP_URL").tls.resumed);
+					}
+					var expected = [false, %[1]t, %[1]t];
+					if (JSON.stringify(resumed) !== JSON.stringify(expected)) {
+						throw new Error("unexpected resumptions " + JSON.stringify(resumed));
+					}
+				};
+			`, enabled)), lib.RuntimeOptions{CompatibilityMode: null.StringFrom("base")})
+			require.NoError(t, err)
+			require.NoError(t, r.SetOptions(r.GetOptions().Apply(lib.Options{
+				Throw:                 null.BoolFrom(true),
+				NoConnectionReuse:     null.BoolFrom(true),
+				InsecureSkipTLSVerify: null.BoolFrom(true),
+			})))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			vu, err := r.NewVU(ctx, 1, 1, make(chan metrics.SampleContainer, 100))
+			require.NoError(t, err)
+			activeVU := vu.Activate(&lib.VUActivationParams{
+				RunContext:        ctx,
+				Scenario:          "default",
+				TLSSessionTickets: enabled,
+			})
+			require.NoError(t, activeVU.RunOnce())
+		})
+	}
+}
+
+func TestVUDialFamily(t *testing.T) {
+	t.Parallel()
+	tb := httpmultibin.NewHTTPMultiBin(t)
+
+	r, err := getSimpleRunner(t, "/script.js", tb.Replacer.Replace(`
+		var http = require("k6/http");
+		exports.default = function() {
+			var res = http.get("HTTPBIN_IP_URL/");
+			if (res.error !== "" && __ENV.FAMILY === "ipv4") {
+				throw new Error("unexpected error " + res.error);
+			}
+			if (res.error.indexOf("isn't an ipv6 address") < 0 && __ENV.FAMILY === "ipv6") {
+				throw new Error("the IPv4 address was dialed: " + res.error);
+			}
+		};
+	`))
+	require.NoError(t, err)
+	require.NoError(t, r.SetOptions(r.GetOptions().Apply(lib.Options{DialFamily: null.StringFrom("ipv6")})))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	initVU, err := r.NewVU(ctx, 1, 1, make(chan metrics.SampleContainer, 100))
+	require.NoError(t, err)
+	vu := initVU.(*VU)
+	assert.Equal(t, "ipv6", vu.Dialer.Family())
+
+	// the scenario overrides the global option
+	activeVU := initVU.Activate(&lib.VUActivationParams{
+		RunContext: ctx, DialFamily: "ipv4", Env: map[string]string{"FAMILY": "ipv4"},
+	})
+	require.NoError(t, activeVU.RunOnce())
+	assert.Equal(t, "ipv4", vu.Dialer.Family())
+
+	activeVU = initVU.Activate(&lib.VUActivationParams{
+		RunContext: ctx, Env: map[string]string{"FAMILY": "ipv6"},
+	})
+	require.NoError(t, activeVU.RunOnce())
+	assert.Equal(t, "ipv6", vu.Dialer.Family())
+}
+
 type multiFileTestCase struct {
 	fses       map[string]fsext.Fs
 	rtOpts     lib.RuntimeOptions
@@ -2263,6 +2481,291 @@ func TestMinIterationDurationIsCancellable(t *testing.T) {
 	}
 }
 
+func TestPacing(t *testing.T) {
+	t.Parallel()
+
+	r, err := getSimpleRunner(t, "/script.js", `exports.default = function() {};`)
+	require.NoError(t, err)
+
+	ch := make(chan metrics.SampleContainer, 1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	initVU, err := r.NewVU(ctx, 1, 1, ch)
+	require.NoError(t, err)
+
+	pacing := &lib.Pacing{Model: lib.PacingConstant, Mean: types.NullDurationFrom(100 * time.Millisecond)}
+	vu := initVU.Activate(&lib.VUActivationParams{RunContext: ctx, Pacing: pacing})
+	start := time.Now()
+	require.NoError(t, vu.RunOnce())
+	require.NoError(t, vu.RunOnce())
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+
+	// the think time is cancellable
+	pacing = &lib.Pacing{Model: lib.PacingConstant, Mean: types.NullDurationFrom(time.Minute)}
+	vu = initVU.Activate(&lib.VUActivationParams{RunContext: ctx, Pacing: pacing})
+	errC := make(chan error)
+	go func() { errC <- vu.RunOnce() }()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case <-time.After(3 * time.Second):
+		t.Fatal("the think time wasn't cancelled")
+	case err := <-errC:
+		require.NoError(t, err)
+	}
+}
+
+func TestMix(t *testing.T) {
+	t.Parallel()
+
+	r, err := getSimpleRunner(t, "/script.js", `
+		var counts = {};
+		function count(name) {
+			counts[name] = (counts[name] || 0) + 1;
+		}
+		exports.browse = function() { count("browse"); };
+		exports.checkout = function() { count("checkout"); };
+		exports.default = function() { throw new Error("the default function shouldn't run"); };
+	`)
+	require.NoError(t, err)
+
+	journeys := func(seed null.Int) []string {
+		require.NoError(t, r.SetOptions(r.GetOptions().Apply(lib.Options{
+			RandomSeed: seed,
+			SystemTags: &metrics.DefaultSystemTagSet,
+		})))
+
+		ch := make(chan metrics.SampleContainer, 1000)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		initVU, err := r.NewVU(ctx, 1, 1, ch)
+		require.NoError(t, err)
+
+		mix := lib.Mix{"browse": 3, "checkout": 1}
+		vu := initVU.Activate(&lib.VUActivationParams{RunContext: ctx, Mix: mix})
+		for range 20 {
+			require.NoError(t, vu.RunOnce())
+		}
+
+		var names []string
+		for _, container := range metrics.GetBufferedSamples(ch) {
+			for _, sample := range container.GetSamples() {
+				if sample.Metric.Name != metrics.IterationsName {
+					continue
+				}
+				journey, ok := sample.Tags.Get(metrics.TagJourney.String())
+				require.True(t, ok)
+				names = append(names, journey)
+			}
+		}
+		require.Len(t, names, 20)
+		return names
+	}
+
+	names := journeys(null.IntFrom(7))
+	assert.Subset(t, []string{"browse", "checkout"}, names)
+	assert.Contains(t, names, "browse")
+	assert.Equal(t, names, journeys(null.IntFrom(7)))
+}
+
+func TestMixJourneyTag(t *testing.T) {
+	t.Parallel()
+
+	journeys := func(systemTags *metrics.SystemTagSet) []string {
+		r, err := getSimpleRunner(t, "/script.js", `exports.browse = function() {};`)
+		require.NoError(t, err)
+		require.NoError(t, r.SetOptions(r.GetOptions().Apply(lib.Options{SystemTags: systemTags})))
+
+		ch := make(chan metrics.SampleContainer, 100)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		initVU, err := r.NewVU(ctx, 1, 1, ch)
+		require.NoError(t, err)
+
+		vu := initVU.Activate(&lib.VUActivationParams{
+			RunContext: ctx,
+			Mix:        lib.Mix{"browse": 1},
+			Tags:       map[string]string{"journey": "signup"},
+		})
+		require.NoError(t, vu.RunOnce())
+
+		var names []string
+		for _, container := range metrics.GetBufferedSamples(ch) {
+			for _, sample := range container.GetSamples() {
+				if sample.Metric.Name == metrics.IterationsName {
+					journey, _ := sample.Tags.Get(metrics.TagJourney.String())
+					names = append(names, journey)
+				}
+			}
+		}
+		activeVU, ok := vu.(*ActiveVU)
+		require.True(t, ok)
+		journey, _ := activeVU.state.Tags.GetCurrentValues().Tags.Get(metrics.TagJourney.String())
+		return append(names, journey)
+	}
+
+	t.Run("system tag", func(t *testing.T) {
+		t.Parallel()
+		// the user's tag is restored after the iteration
+		assert.Equal(t, []string{"browse", "signup"}, journeys(&metrics.DefaultSystemTagSet))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"signup", "signup"}, journeys(metrics.ToSystemTagSet([]string{"scenario"})))
+	})
+}
+
+func TestRandomSeed(t *testing.T) {
+	t.Parallel()
+
+	values := func(seed null.Int, vuID uint64) []float64 {
+		r, err := getSimpleRunner(t, "/script.js", `
+			var random = require("k6/random");
+			var metrics = require("k6/metrics");
+			var stream = random.new("user-think");
+			var values = new metrics.Trend("values");
+			exports.default = function() {
+				values.add(Math.random());
+				values.add(stream.float());
+			};
+		`)
+		require.NoError(t, err)
+		require.NoError(t, r.SetOptions(r.GetOptions().Apply(lib.Options{RandomSeed: seed})))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch := make(chan metrics.SampleContainer, 100)
+		initVU, err := r.NewVU(ctx, vuID, vuID, ch)
+		require.NoError(t, err)
+		vu := initVU.Activate(&lib.VUActivationParams{RunContext: ctx})
+		require.NoError(t, vu.RunOnce())
+
+		var got []float64
+		for _, container := range metrics.GetBufferedSamples(ch) {
+			for _, sample := range container.GetSamples() {
+				if sample.Metric.Name == "values" {
+					got = append(got, sample.Value)
+				}
+			}
+		}
+		require.Len(t, got, 2)
+		return got
+	}
+
+	seed := null.IntFrom(42)
+	assert.Equal(t, values(seed, 1), values(seed, 1))
+	assert.NotEqual(t, values(seed, 1), values(seed, 2))
+	assert.NotEqual(t, values(seed, 1), values(null.IntFrom(43), 1))
+	assert.NotEqual(t, values(null.Int{}, 1), values(null.Int{}, 1))
+}
+
+func TestIterationTimeout(t *testing.T) {
+	t.Parallel()
+
+	r, err := getSimpleRunner(t, "/script.js", `
+			exports.options = { iterationTimeout: '100ms' };
+
+			exports.default = function() { if (__ITER == 0) { while(true) {} } };
+		`)
+	require.NoError(t, err)
+
+	ch := make(chan metrics.SampleContainer, 1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	initVU, err := r.NewVU(ctx, 1, 1, ch)
+	require.NoError(t, err)
+
+	vu := initVU.Activate(&lib.VUActivationParams{RunContext: ctx})
+	err = vu.RunOnce()
+	require.ErrorIs(t, err, lib.ErrIterationTimeout)
+
+	// the VU should be able to continue with the next iteration
+	require.NoError(t, vu.RunOnce())
+
+	var timedOut, iterations float64
+	for _, sampleCont := range metrics.GetBufferedSamples(ch) {
+		for _, sample := range sampleCont.GetSamples() {
+			switch sample.Metric.Name {
+			case metrics.IterationsTimedOutName:
+				timedOut += sample.Value
+			case metrics.IterationsName:
+				iterations += sample.Value
+			}
+		}
+	}
+	assert.Equal(t, 1.0, timedOut)
+	assert.Equal(t, 1.0, iterations)
+}
+
+func TestIterationTimeoutScenarioOverride(t *testing.T) {
+	t.Parallel()
+
+	r, err := getSimpleRunner(t, "/script.js", `
+			exports.options = { iterationTimeout: '1h' };
+
+			exports.default = function() { while(true) {} };
+		`)
+	require.NoError(t, err)
+
+	ch := make(chan metrics.SampleContainer, 1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	initVU, err := r.NewVU(ctx, 1, 1, ch)
+	require.NoError(t, err)
+
+	vu := initVU.Activate(&lib.VUActivationParams{
+		RunContext:       ctx,
+		IterationTimeout: types.NullDurationFrom(100 * time.Millisecond),
+	})
+	require.ErrorIs(t, vu.RunOnce(), lib.ErrIterationTimeout)
+}
+
+func TestWarmupIterations(t *testing.T) {
+	t.Parallel()
+
+	r, err := getSimpleRunner(t, "/script.js", `exports.default = function() {};`)
+	require.NoError(t, err)
+
+	ch := make(chan metrics.SampleContainer, 1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	initVU, err := r.NewVU(ctx, 1, 1, ch)
+	require.NoError(t, err)
+
+	var iter uint64
+	vu := initVU.Activate(&lib.VUActivationParams{
+		RunContext:       ctx,
+		Tags:             map[string]string{"phase": "load"},
+		WarmupIterations: 2,
+		GetNextIterationCounters: func() (uint64, uint64) {
+			iter++
+			return iter - 1, iter - 1
+		},
+	})
+	for range 5 {
+		require.NoError(t, vu.RunOnce())
+	}
+
+	var warmup, regular int
+	for _, sampleCont := range metrics.GetBufferedSamples(ch) {
+		for _, sample := range sampleCont.GetSamples() {
+			if sample.Metric.Name != metrics.IterationsName {
+				continue
+			}
+			phase, _ := sample.Tags.Get(metrics.PhaseTagName)
+			if sample.IsWarmup() {
+				warmup++
+			} else {
+				assert.Equal(t, "load", phase)
+				regular++
+			}
+		}
+	}
+	assert.Equal(t, 2, warmup)
+	assert.Equal(t, 3, regular)
+}
+
 func TestForceHTTP1Feature(t *testing.T) {
 	t.Parallel()
 	cases := map[string]struct {
@@ -46,6 +46,10 @@
                 oldFormatMetric.thresholds = {};
                 forEach(newFormatThresholds, function (thresholdName, threshold) {
                     oldFormatMetric.thresholds[thresholdName] = !threshold.ok;
+                    if (threshold.evaluations) {
+                        oldFormatMetric.threshold_evaluations = oldFormatMetric.threshold_evaluations || {};
+                        oldFormatMetric.threshold_evaluations[thresholdName] = threshold.evaluations;
+                    }
                 });
             }
             if (metric.type == 'rate' && oldFormatMetric.hasOwnProperty('rate')) {
@@ -90,9 +90,32 @@ func summarizeReportToObject(rt *sobek.Runtime, s *summary.Summary) (map[string]
 	m["metrics"] = s.Metrics
 	m["groups"] = groups
 	m["scenarios"] = scenarios
+	m["time_series"] = summarizeTimeSeries(s.TimeSeries)
 	return m, nil
 }
 
+// summarizeTimeSeries transforms the time series of the metrics selected with the summaryTimeSeries option,
+// with null for the buckets without samples, so the shape of the test run can be rendered.
+func summarizeTimeSeries(timeSeries []summary.TimeSeries) []map[string]any {
+	result := make([]map[string]any, 0, len(timeSeries))
+	for _, ts := range timeSeries {
+		buckets := make([]any, len(ts.Buckets))
+		for i, values := range ts.Buckets {
+			if values != nil {
+				buckets[i] = values
+			}
+		}
+		result = append(result, map[string]any{
+			"name":        ts.Name,
+			"type":        ts.Type,
+			"contains":    ts.Contains,
+			"interval_ms": float64(ts.Interval) / float64(time.Millisecond),
+			"buckets":     buckets,
+		})
+	}
+	return result
+}
+
 // TODO: figure out something saner... refactor the sinks and how we deal with
 // metrics in general... so much pain and misery... :sob:
 func metricValueGetter(summaryTrendStats []string) func(metrics.Sink, time.Duration) map[string]float64 {
@@ -130,12 +153,34 @@ func metricValueGetter(summaryTrendStats []string) func(metrics.Sink, time.Durat
 func summarizeMetricsToObject(data *lib.LegacySummary, options lib.Options, setupData []byte) map[string]interface{} {
 	m := make(map[string]interface{})
 	m["root_group"] = exportGroup(data.RootGroup)
+	// the seed is recorded, so that the random values of the run can be replayed
+	var randomSeed interface{}
+	if options.RandomSeed.Valid {
+		randomSeed = options.RandomSeed.Int64
+	}
 	m["options"] = map[string]interface{}{
 		// TODO: improve when we can easily export all option values, including defaults?
 		"summaryTrendStats": options.SummaryTrendStats,
 		"summaryTimeUnit":   options.SummaryTimeUnit.String,
+		"randomSeed":        randomSeed,
 		"noColor":           data.NoColor, // TODO: move to the (runtime) options
 	}
+	runMetadata := data.RunMetadata
+	if runMetadata == nil {
+		runMetadata = map[string]string{}
+	}
+	m["run_metadata"] = runMetadata
+	if data.Abort != nil {
+		tags := data.Abort.Tags
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		m["abort"] = map[string]interface{}{
+			"code":    int(data.Abort.ExitCode()),
+			"message": data.Abort.Message,
+			"tags":    tags,
+		}
+	}
 	m["state"] = map[string]interface{}{
 		"isStdOutTTY":       data.UIState.IsStdOutTTY,
 		"isStdErrTTY":       data.UIState.IsStdErrTTY,
@@ -155,9 +200,13 @@ func summarizeMetricsToObject(data *lib.LegacySummary, options lib.Options, setu
 		if len(m.Thresholds.Thresholds) > 0 {
 			thresholds := make(map[string]interface{})
 			for _, threshold := range m.Thresholds.Thresholds {
-				thresholds[threshold.Source] = map[string]interface{}{
+				thresholdData := map[string]interface{}{
 					"ok": !threshold.LastFailed,
 				}
+				if timeline := threshold.Timeline(); len(timeline) > 0 {
+					thresholdData["evaluations"] = thresholdEvaluations(timeline)
+				}
+				thresholds[threshold.Source] = thresholdData
 			}
 			metricData["thresholds"] = thresholds
 		}
@@ -180,6 +229,21 @@ func summarizeMetricsToObject(data *lib.LegacySummary, options lib.Options, setu
 	return m
 }
 
+// thresholdEvaluations returns the timeline of a threshold in the format of the
+// summary data.
+func thresholdEvaluations(timeline []metrics.ThresholdEvaluation) []interface{} {
+	evaluations := make([]interface{}, 0, len(timeline))
+	for _, eval := range timeline {
+		evaluations = append(evaluations, map[string]interface{}{
+			"time":              eval.Time.Format(time.RFC3339Nano),
+			"testRunDurationMs": float64(eval.TestRunDuration) / float64(time.Millisecond),
+			"value":             eval.Value,
+			"ok":                eval.Ok,
+		})
+	}
+	return evaluations
+}
+
 func exportGroup(group *lib.Group) map[string]interface{} {
 	subGroups := make([]map[string]interface{}, len(group.OrderedGroups))
 	for i, subGroup := range group.OrderedGroups {
@@ -66,6 +66,16 @@ function humanizeValue(val, metric, timeUnit) {
  * @property {ReportMetrics} metrics - The metrics report.
  * @property {Record<string, ReportGroup>} groups - The groups report.
  * @property {Record<string, ReportGroup>} scenarios - The scenarios report.
+ * @property {ReportTimeSeries[]} [time_series] - The time series of the metrics selected with summaryTimeSeries.
+ */
+
+/**
+ * @typedef {Object} ReportTimeSeries
+ * @property {string} name - The name of the metric, or sub-metric.
+ * @property {"counter"|"gauge"|"rate"|"trend"} type - The type of the metric.
+ * @property {"time"|"data"|"default"} contains - The type of data contained in the metric.
+ * @property {number} interval_ms - The length of each time bucket, in milliseconds.
+ * @property {(Record<string, number>|null)[]} buckets - The values of each time bucket, null if it has no samples.
  */
 
 /**
@@ -177,6 +187,7 @@ class TestReportGenerator {
 		return reportBuilder
 			.addThresholds(report.thresholds)
 			.addTotalResults(report)
+			.addTimeSeries(report.time_series)
 			.addGroups(report.groups)
 			.addScenarios(report.scenarios)
 			.build();
@@ -235,6 +246,27 @@ class ReportBuilder {
 		return this;
 	}
 
+	/**
+	 * Adds a time series section to the report, with a line showing the shape of each selected metric.
+	 *
+	 * @param {ReportTimeSeries[]} timeSeries - The time series to add to the report.
+	 * @returns {ReportBuilder}
+	 */
+	addTimeSeries(timeSeries) {
+		if (!timeSeries || timeSeries.length === 0) return this;
+
+		this.sections.push({
+			title: 'TIME SERIES',
+			content: renderTimeSeries(
+				timeSeries,
+				this.formatter,
+				this.renderContext.indentedContext(1),
+				this.options,
+			),
+		});
+		return this;
+	}
+
 	/**
 	 * Adds groups sections to the report.
 	 *
@@ -810,6 +842,74 @@ function renderMetrics(
 	});
 }
 
+/**
+ * Renders each time series as a line with a sparkline of its buckets, as well as the lowest and highest
+ * values, e.g. "http_req_duration p(95) [10s]: ▁▂▂▃▅█·▂ min=12ms max=340ms".
+ *
+ * @param {ReportTimeSeries[]} timeSeries - The time series to render.
+ * @param {ANSIFormatter} formatter - ANSI formatter used for decorating text.
+ * @param {RenderContext} renderContext - The render context to use for text rendering.
+ * @param {Options} options - Display options merged with defaultOptions.
+ * @returns {string[]}
+ */
+function renderTimeSeries(timeSeries, formatter, renderContext, options) {
+	const rendered = timeSeries.map((series) => {
+		const stat = timeSeriesStat(series);
+		const values = series.buckets.map((bucket) =>
+			bucket && bucket[stat] !== undefined ? bucket[stat] : null,
+		);
+		const present = values.filter((value) => value !== null);
+		const label = `${series.name} ${stat} [${humanizeGenericDuration(series.interval_ms)}]`;
+		if (present.length === 0) {
+			return {label, data: formatter.decorate('no data', 'white', 'faint')};
+		}
+
+		const min = Math.min(...present);
+		const max = Math.max(...present);
+		const sparkline = values
+			.map((value) => {
+				if (value === null) return sparklineEmpty;
+				const level = max === min ? 0 : Math.round(((value - min) / (max - min)) * (sparklineLevels.length - 1));
+				return sparklineLevels[level];
+			})
+			.join('');
+		const humanize = (value) => humanizeValue(value, series, options.summaryTimeUnit);
+		return {
+			label,
+			data: `${formatter.decorate(sparkline, 'cyan')} min=${humanize(min)} max=${humanize(max)}`,
+		};
+	});
+
+	const maxLabelWidth = Math.max(...rendered.map(({label}) => strWidth(label)));
+	return rendered.map(({label, data}) => {
+		const dots = formatter.decorate('.'.repeat(maxLabelWidth - strWidth(label) + 3) + ':', 'white', 'faint');
+		return renderContext.indent(label + dots + ' ' + data);
+	});
+}
+
+/**
+ * Picks the value of the time series buckets that is rendered: the 95th percentile (or the first trend stat) for
+ * trends, the count for counters, the rate for rates and the value for gauges.
+ *
+ * @param {ReportTimeSeries} series - The time series.
+ * @returns {string}
+ */
+function timeSeriesStat(series) {
+	switch (series.type) {
+		case 'counter':
+			return 'count';
+		case 'rate':
+			return 'rate';
+		case 'gauge':
+			return 'value';
+		default: {
+			const bucket = series.buckets.find((b) => b);
+			if (!bucket || bucket['p(95)'] !== undefined) return 'p(95)';
+			return Object.keys(bucket)[0];
+		}
+	}
+}
+
 /**
  * Renders each thresholds results into a formatted set of lines ready for display in the terminal.
  *
@@ -1252,6 +1352,8 @@ function forEach(obj, callback) {
 const titlePrefix = '█';
 const subtitlePrefix = '↳';
 const successMark = '✓';
+const sparklineLevels = ['▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'];
+const sparklineEmpty = '·';
 const failMark = '✗';
 const defaultOptions = {
 	indent: ' ',
@@ -214,6 +214,7 @@ func createTestLegacySummary(t *testing.T) *lib.LegacySummary {
 }
 
 const expectedOldJSONExportResult = `{
+    "run_metadata": {},
     "root_group": {
         "name": "",
         "path": "",
@@ -320,6 +321,54 @@ func TestOldJSONExport(t *testing.T) {
 	assert.JSONEq(t, expectedOldJSONExportResult, string(jsonExport))
 }
 
+func TestOldJSONExportThresholdEvaluations(t *testing.T) {
+	t.Parallel()
+	runner, err := getSimpleRunner(
+		t, "/script.js",
+		`exports.default = function() {/* we don't run this, metrics are mocked */};`,
+		lib.RuntimeOptions{
+			CompatibilityMode: null.NewString("base", true),
+			SummaryExport:     null.StringFrom("result.json"),
+		},
+	)
+	require.NoError(t, err)
+
+	legacySummary := createTestLegacySummary(t)
+	countMetric := legacySummary.Metrics["http_reqs"]
+	countMetric.Thresholds = metrics.NewThresholds([]string{"count<2"})
+	require.NoError(t, countMetric.Thresholds.Parse())
+	_, err = countMetric.Thresholds.Run(countMetric.Sink, 1500*time.Millisecond)
+	require.NoError(t, err)
+
+	result, err := runner.HandleSummary(t.Context(), legacySummary, nil, summary.Meta{})
+	require.NoError(t, err)
+	require.NotNil(t, result["result.json"])
+	jsonExport, err := io.ReadAll(result["result.json"])
+	require.NoError(t, err)
+
+	var export struct {
+		Metrics map[string]struct {
+			Thresholds           map[string]bool `json:"thresholds"`
+			ThresholdEvaluations map[string][]struct {
+				Time              time.Time `json:"time"`
+				TestRunDurationMs float64   `json:"testRunDurationMs"`
+				Value             float64   `json:"value"`
+				Ok                bool      `json:"ok"`
+			} `json:"threshold_evaluations"`
+		} `json:"metrics"`
+	}
+	require.NoError(t, json.Unmarshal(jsonExport, &export))
+
+	assert.Equal(t, map[string]bool{"count<2": true}, export.Metrics["http_reqs"].Thresholds)
+	evaluations := export.Metrics["http_reqs"].ThresholdEvaluations["count<2"]
+	require.Len(t, evaluations, 1)
+	assert.False(t, evaluations[0].Time.IsZero())
+	assert.Equal(t, 1500.0, evaluations[0].TestRunDurationMs)
+	assert.Equal(t, 3.0, evaluations[0].Value)
+	assert.False(t, evaluations[0].Ok)
+	assert.Empty(t, export.Metrics["checks"].ThresholdEvaluations)
+}
+
 const expectedHandleSummaryRawData = `
 {
     "root_group": {
@@ -371,8 +420,10 @@ const expectedHandleSummaryRawData = `
             "count"
         ],
         "summaryTimeUnit": "",
+        "randomSeed": null,
         "noColor": false
     },
+    "run_metadata": {},
     "state": {
         "isStdErrTTY": false,
         "isStdOutTTY": false,
@@ -488,8 +539,10 @@ const expectedHandleSummaryDataWithSetup = `
             "count"
             ],
             "summaryTimeUnit": "",
+            "randomSeed": null,
             "noColor": false
         },
+        "run_metadata": {},
         "state": {
             "isStdErrTTY": false,
             "isStdOutTTY": false,
@@ -0,0 +1,232 @@
+// Package abort is implementing AbortController and AbortSignal.
+package abort
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/js/modules"
+)
+
+// signalSymbol is the key of the hidden property of the AbortSignal objects
+// that holds their Signal, so the modules can get it back with FromValue.
+//
+//nolint:gochecknoglobals
+var signalSymbol = sobek.NewSymbol("k6.AbortSignal")
+
+// SetupGlobally setups implementations of AbortController and AbortSignal
+// to be accessible globally by setting them on globalThis.
+func SetupGlobally(vu modules.VU) error {
+	rt := vu.Runtime()
+
+	abortSignal := rt.NewObject()
+	if err := abortSignal.Set("abort", func(reason sobek.Value) *sobek.Object {
+		s := newSignal(vu)
+		if err := s.abort(reason); err != nil {
+			common.Throw(rt, err)
+		}
+		return s.obj
+	}); err != nil {
+		return err
+	}
+
+	mapping := map[string]any{
+		"AbortController": func(sobek.ConstructorCall) *sobek.Object {
+			return newController(vu)
+		},
+		"AbortSignal": abortSignal,
+	}
+	for k, v := range mapping {
+		if err := rt.Set(k, v); err != nil {
+			return fmt.Errorf("error setting up %q globally: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// Signal is the Go side of an AbortSignal. Its methods must only be called
+// on the event loop.
+type Signal struct {
+	vu  modules.VU
+	obj *sobek.Object
+
+	aborted   bool
+	reason    sobek.Value
+	onabort   sobek.Value
+	listeners []sobek.Value
+
+	subscribers   map[uint64]func()
+	subscriberIDs uint64
+}
+
+// FromValue returns the Signal of the AbortSignal value.
+func FromValue(rt *sobek.Runtime, v sobek.Value) (*Signal, error) {
+	if common.IsNullish(v) {
+		return nil, errors.New("an AbortSignal is required")
+	}
+	if obj, ok := v.(*sobek.Object); ok {
+		if v := obj.GetSymbol(signalSymbol); v != nil {
+			if s, ok := v.Export().(*Signal); ok {
+				return s, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s isn't an AbortSignal", v.ToString())
+}
+
+// Aborted returns whether the signal was aborted.
+func (s *Signal) Aborted() bool {
+	return s.aborted
+}
+
+// Reason returns the reason with which the signal was aborted.
+func (s *Signal) Reason() sobek.Value {
+	return s.reason
+}
+
+// Subscribe calls the function when the signal is aborted, or right away if
+// it's already aborted. The returned function unsubscribes it.
+func (s *Signal) Subscribe(fn func()) (unsubscribe func()) {
+	if s.aborted {
+		fn()
+		return func() {}
+	}
+	s.subscriberIDs++
+	id := s.subscriberIDs
+	s.subscribers[id] = fn
+	return func() { delete(s.subscribers, id) }
+}
+
+func newSignal(vu modules.VU) *Signal {
+	rt := vu.Runtime()
+	s := &Signal{
+		vu:          vu,
+		obj:         rt.NewObject(),
+		reason:      sobek.Undefined(),
+		onabort:     sobek.Null(),
+		subscribers: make(map[uint64]func()),
+	}
+
+	must(rt, s.obj.DefineDataPropertySymbol(
+		signalSymbol, rt.ToValue(s), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_FALSE))
+	must(rt, s.obj.DefineAccessorProperty(
+		"aborted", rt.ToValue(func() bool { return s.aborted }), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, s.obj.DefineAccessorProperty(
+		"reason", rt.ToValue(func() sobek.Value { return s.reason }), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, s.obj.DefineAccessorProperty(
+		"onabort", rt.ToValue(func() sobek.Value { return s.onabort }), rt.ToValue(func(v sobek.Value) {
+			s.onabort = v
+		}), sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, s.obj.DefineDataProperty(
+		"addEventListener", rt.ToValue(s.addEventListener), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, s.obj.DefineDataProperty(
+		"removeEventListener", rt.ToValue(s.removeEventListener), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, s.obj.DefineDataProperty(
+		"throwIfAborted", rt.ToValue(func() {
+			if s.aborted {
+				panic(rt.ToValue(s.reason))
+			}
+		}), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+
+	return s
+}
+
+func (s *Signal) addEventListener(event string, listener sobek.Value) error {
+	if event != "abort" {
+		return fmt.Errorf("unknown AbortSignal event %q, the only supported one is \"abort\"", event)
+	}
+	if _, ok := sobek.AssertFunction(listener); !ok {
+		return errors.New("the AbortSignal's event listener should be callable")
+	}
+	for _, l := range s.listeners {
+		if l.SameAs(listener) {
+			return nil
+		}
+	}
+	s.listeners = append(s.listeners, listener)
+	return nil
+}
+
+func (s *Signal) removeEventListener(event string, listener sobek.Value) {
+	if event != "abort" {
+		return
+	}
+	for i, l := range s.listeners {
+		if l.SameAs(listener) {
+			s.listeners = append(s.listeners[:i], s.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// abort aborts the signal, notifies the subscribers and dispatches the abort
+// event to the listeners.
+func (s *Signal) abort(reason sobek.Value) error {
+	if s.aborted {
+		return nil
+	}
+	rt := s.vu.Runtime()
+	if common.IsNullish(reason) {
+		reason = newAbortError(rt)
+	}
+	s.aborted, s.reason = true, reason
+
+	subscribers := s.subscribers
+	s.subscribers = nil
+	for _, fn := range subscribers {
+		fn()
+	}
+
+	event := rt.NewObject()
+	must(rt, event.Set("type", "abort"))
+	must(rt, event.Set("target", s.obj))
+	listeners := s.listeners
+	if fn, ok := sobek.AssertFunction(s.onabort); ok {
+		if _, err := fn(s.obj, event); err != nil {
+			return err
+		}
+	}
+	for _, l := range listeners {
+		fn, _ := sobek.AssertFunction(l)
+		if _, err := fn(s.obj, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newAbortError returns the default reason of an abort, an error named
+// AbortError like the DOMException of the browsers.
+func newAbortError(rt *sobek.Runtime) sobek.Value {
+	errorCtor, _ := sobek.AssertConstructor(rt.Get("Error"))
+	err, _ := errorCtor(nil, rt.ToValue("This operation was aborted"))
+	must(rt, err.Set("name", "AbortError"))
+	return err
+}
+
+func newController(vu modules.VU) *sobek.Object {
+	rt := vu.Runtime()
+	s := newSignal(vu)
+	obj := rt.NewObject()
+
+	must(rt, obj.DefineDataProperty(
+		"signal", s.obj, sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, obj.DefineDataProperty(
+		"abort", rt.ToValue(func(reason sobek.Value) {
+			if err := s.abort(reason); err != nil {
+				common.Throw(rt, err)
+			}
+		}), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+
+	return obj
+}
+
+// must is a small helper that will panic if err is not nil.
+func must(rt *sobek.Runtime, err error) {
+	if err != nil {
+		common.Throw(rt, err)
+	}
+}
@@ -0,0 +1,91 @@
+package abort_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/js/tc55/abort"
+	"go.k6.io/k6/js/modulestest"
+)
+
+func TestAbortController(t *testing.T) {
+	t.Parallel()
+
+	runtime := modulestest.NewRuntime(t)
+	rt := runtime.VU.Runtime()
+	var log []string
+	require.NoError(t, rt.Set("print", func(s string) { log = append(log, s) }))
+
+	_, err := runtime.RunOnEventLoop(`
+		const controller = new AbortController();
+		const signal = controller.signal;
+		const removed = () => print("removed");
+		signal.onabort = (e) => print("onabort " + e.type + " " + (e.target === signal));
+		signal.addEventListener("abort", () => print("listener " + signal.aborted));
+		signal.addEventListener("abort", removed);
+		signal.removeEventListener("abort", removed);
+		print(String(signal.aborted));
+		signal.throwIfAborted();
+		controller.abort();
+		controller.abort(); // only the first abort dispatches the event
+		print(signal.reason.name + ": " + signal.reason.message);
+		try {
+			signal.throwIfAborted();
+		} catch (e) {
+			print("thrown " + (e === signal.reason));
+		}
+	`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"false",
+		"onabort abort true",
+		"listener true",
+		"AbortError: This operation was aborted",
+		"thrown true",
+	}, log)
+}
+
+func TestAbortSignalAbort(t *testing.T) {
+	t.Parallel()
+
+	runtime := modulestest.NewRuntime(t)
+	v, err := runtime.RunOnEventLoop(`
+		const signal = AbortSignal.abort("custom reason");
+		signal.aborted && signal.reason === "custom reason"
+	`)
+	require.NoError(t, err)
+	assert.True(t, v.ToBoolean())
+}
+
+func TestFromValue(t *testing.T) {
+	t.Parallel()
+
+	runtime := modulestest.NewRuntime(t)
+	rt := runtime.VU.Runtime()
+
+	v, err := rt.RunString(`var controller = new AbortController(); controller.signal`)
+	require.NoError(t, err)
+	signal, err := abort.FromValue(rt, v)
+	require.NoError(t, err)
+	assert.False(t, signal.Aborted())
+
+	var calls int
+	unsubscribe := signal.Subscribe(func() { calls++ })
+	unsubscribed := signal.Subscribe(func() { calls += 10 })
+	unsubscribed()
+	_, err = rt.RunString(`controller.abort("reason")`)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.True(t, signal.Aborted())
+	assert.Equal(t, "reason", signal.Reason().String())
+	unsubscribe()
+
+	// subscribing to an aborted signal calls the function right away
+	signal.Subscribe(func() { calls++ })
+	assert.Equal(t, 2, calls)
+
+	_, err = abort.FromValue(rt, rt.ToValue(map[string]any{"aborted": false}))
+	require.ErrorContains(t, err, "isn't an AbortSignal")
+}
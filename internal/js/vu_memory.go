@@ -0,0 +1,177 @@
+package js
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/internal/event"
+	"go.k6.io/k6/metrics"
+)
+
+// vuMemoryCheckInterval is how often, at most, the memory that a VU retains
+// is estimated, since it requires walking through all of its values.
+const vuMemoryCheckInterval = time.Second
+
+// Rough sizes in bytes of the JS values, used to estimate the memory of a VU.
+const (
+	memObjectSize   = 64
+	memPropertySize = 32
+	memValueSize    = 16
+)
+
+//nolint:gochecknoglobals
+var arrayBufferType = reflect.TypeOf(sobek.ArrayBuffer{})
+
+// estimateMemory returns an estimate of the memory retained by the JS values
+// that are reachable from the global object and from the exports of the main
+// module. It isn't the size of the whole heap of the runtime, which Sobek
+// doesn't track: the values that are only reachable from closures, from the
+// bindings in the module scope that aren't exported, or from the modules that
+// the main one imports, can't be inspected, so they aren't counted.
+func (bi *BundleInstance) estimateMemory() uint64 {
+	e := &memoryEstimator{rt: bi.Runtime, seen: make(map[*sobek.Object]struct{})}
+	e.add(bi.Runtime.GlobalObject())
+	bi.mainModule.GetExportedNames(func(names []string) {
+		for _, name := range names {
+			e.add(bi.getExported(name))
+		}
+	})
+	return e.size
+}
+
+type memoryEstimator struct {
+	rt   *sobek.Runtime
+	seen map[*sobek.Object]struct{}
+	size uint64
+}
+
+func (e *memoryEstimator) add(v sobek.Value) {
+	if v == nil || sobek.IsUndefined(v) || sobek.IsNull(v) {
+		return
+	}
+	obj, ok := v.(*sobek.Object)
+	if !ok {
+		e.size += memValueSize
+		if v.ExportType().Kind() == reflect.String {
+			e.size += uint64(len(v.String()))
+		}
+		return
+	}
+
+	if _, ok := e.seen[obj]; ok {
+		return
+	}
+	e.seen[obj] = struct{}{}
+	e.size += memObjectSize
+
+	switch obj.ClassName() {
+	case "Function", "AsyncFunction", "GeneratorFunction":
+		return
+	case "Map", "Set":
+		e.rt.ForOf(obj, func(entry sobek.Value) bool {
+			e.add(entry)
+			return true
+		})
+	case "Object":
+		exportType := obj.ExportType()
+		if exportType == arrayBufferType {
+			if ab, ok := obj.Export().(sobek.ArrayBuffer); ok {
+				e.size += uint64(len(ab.Bytes()))
+			}
+			return
+		}
+		if exportType != nil && exportType.Kind() == reflect.Slice {
+			// typed arrays are views of their buffers
+			e.add(obj.Get("buffer"))
+			return
+		}
+	}
+
+	for _, key := range obj.Keys() {
+		e.size += memPropertySize + uint64(len(key))
+		e.add(obj.Get(key))
+	}
+}
+
+// checkMemoryLimit estimates the memory that the VU retains, at most once per
+// vuMemoryCheckInterval, and emits it as the vu_memory_bytes metric. If it's
+// over the limit, the VU logs a warning about it or is restarted, depending on
+// the vuMemoryLimitAction option.
+func (u *ActiveVU) checkMemoryLimit(limit uint64) {
+	now := time.Now()
+	if now.Sub(u.lastMemoryCheck) < vuMemoryCheckInterval {
+		return
+	}
+	u.lastMemoryCheck = now
+
+	size := u.estimateMemory()
+	ctm := u.state.Tags.GetCurrentValues()
+	metrics.PushIfNotDone(u.RunContext, u.state.Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: u.Runner.preInitState.BuiltinMetrics.VUMemoryBytes,
+			Tags:   ctm.Tags,
+		},
+		Time:     now,
+		Metadata: ctm.Metadata,
+		Value:    float64(size),
+	})
+	if size <= limit {
+		return
+	}
+
+	logger := u.state.Logger.WithFields(logrus.Fields{"vu": u.ID, "memory": size, "limit": limit})
+	if u.Runner.Bundle.Options.VUMemoryLimitAction.String != "restart" {
+		if !u.memoryWarned {
+			u.memoryWarned = true
+			logger.Warn("The VU has exceeded the memory limit, the script might be leaking memory")
+		}
+		return
+	}
+	if u.RunContext.Err() != nil {
+		return
+	}
+	logger.Warn("The VU has exceeded the memory limit and is restarted")
+	if err := u.restart(); err != nil {
+		logger.WithError(err).Error("Couldn't restart the VU")
+	}
+}
+
+// restart replaces the JS runtime of the VU with a newly initialized one, so
+// everything that the previous iterations have retained is released. The
+// other VU state, like the cookies and the connections, is kept. The modules of
+// the previous runtime get the Exit event on its own event system, so they can
+// release what they hold, and are then unsubscribed from it.
+func (u *ActiveVU) restart() error {
+	bundle := u.Runner.currentBundle()
+	bi, err := bundle.Instantiate(u.RunContext, u.ID)
+	if err != nil {
+		return err
+	}
+	bi.moduleVUImpl.state = u.state
+	if err = bi.Runtime.Set("console", u.Console); err != nil {
+		return err
+	}
+	if err = bi.Runtime.Set("__ENV", u.activeEnv); err != nil {
+		return err
+	}
+
+	u.runtimeLock.Lock()
+	oldEvents := u.moduleVUImpl.events.local
+	u.BundleInstance = *bi
+	u.runtimeLock.Unlock()
+	u.bundle = bundle
+	u.setupData = nil
+
+	waitDone := oldEvents.Emit(&event.Event{Type: event.Exit, Data: &event.ExitData{}})
+	waitCtx, waitCancel := context.WithTimeout(u.RunContext, 30*time.Minute)
+	defer waitCancel()
+	if err := waitDone(waitCtx); err != nil {
+		u.state.Logger.WithError(err).Warn()
+	}
+	oldEvents.UnsubscribeAll()
+	return nil
+}
@@ -0,0 +1,131 @@
+package js
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/internal/event"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+func TestEstimateMemory(t *testing.T) {
+	t.Parallel()
+
+	r, err := getSimpleRunner(t, "/script.js", `
+		export const cache = [];
+		globalThis.buffer = new Uint8Array(100000);
+		export default function() {
+			for (let i = 0; i < 1000; i++) {
+				cache.push("x".repeat(1000));
+			}
+		}
+	`)
+	require.NoError(t, err)
+
+	vu, err := r.newVU(context.Background(), 1, 1, make(chan metrics.SampleContainer, 100))
+	require.NoError(t, err)
+	before := vu.estimateMemory()
+	assert.Greater(t, before, uint64(100000))
+	assert.Less(t, before, uint64(200000))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, vu.Activate(&lib.VUActivationParams{RunContext: ctx}).RunOnce())
+	assert.Greater(t, vu.estimateMemory(), before+1000*1000)
+}
+
+func TestVUMemoryLimit(t *testing.T) {
+	t.Parallel()
+
+	script := `
+		exports.cache = [];
+		exports.default = function() {
+			for (var i = 0; i < 100; i++) {
+				exports.cache.push("x".repeat(1000));
+			}
+		}
+	`
+	for _, action := range []string{"warn", "restart"} {
+		t.Run(action, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := getSimpleRunner(t, "/script.js", script)
+			require.NoError(t, err)
+			require.NoError(t, r.SetOptions(r.GetOptions().Apply(lib.Options{
+				VUMemoryLimit:       null.IntFrom(50000),
+				VUMemoryLimitAction: null.StringFrom(action),
+			})))
+
+			samples := make(chan metrics.SampleContainer, 100)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			vu, err := r.newVU(ctx, 1, 1, samples)
+			require.NoError(t, err)
+			activeVU := vu.Activate(&lib.VUActivationParams{RunContext: ctx})
+			require.NoError(t, activeVU.RunOnce())
+
+			var memory []float64
+			for _, sc := range metrics.GetBufferedSamples(samples) {
+				for _, s := range sc.GetSamples() {
+					if s.Metric.Name == metrics.VUMemoryBytesName {
+						memory = append(memory, s.Value)
+					}
+				}
+			}
+			require.Len(t, memory, 1)
+			assert.Greater(t, memory[0], 100000.0)
+
+			cached := vu.getExported("cache").ToObject(vu.Runtime).Get("length").ToInteger()
+			if action == "restart" {
+				assert.Equal(t, int64(0), cached)
+				// the restarted VU continues with the next iteration
+				require.NoError(t, activeVU.RunOnce())
+				cached = vu.getExported("cache").ToObject(vu.Runtime).Get("length").ToInteger()
+				assert.Equal(t, int64(100), cached)
+			} else {
+				assert.Equal(t, int64(100), cached)
+			}
+		})
+	}
+}
+
+func TestVURestartExitsModules(t *testing.T) {
+	t.Parallel()
+
+	r, err := getSimpleRunner(t, "/script.js", `export default function() {}`)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	vu, err := r.newVU(ctx, 1, 1, make(chan metrics.SampleContainer, 100))
+	require.NoError(t, err)
+	activeVU, ok := vu.Activate(&lib.VUActivationParams{RunContext: ctx}).(*ActiveVU)
+	require.True(t, ok)
+
+	oldEvents := vu.moduleVUImpl.events.local
+	_, eventsCh := oldEvents.Subscribe(event.Exit)
+	exited := make(chan int)
+	go func() {
+		var received int
+		for evt := range eventsCh {
+			assert.Equal(t, event.Exit, evt.Type)
+			received++
+			evt.Done()
+		}
+		exited <- received
+	}()
+
+	require.NoError(t, activeVU.restart())
+	assert.NotSame(t, oldEvents, vu.moduleVUImpl.events.local)
+	select {
+	case received := <-exited:
+		assert.Equal(t, 1, received)
+	case <-time.After(time.Second):
+		t.Fatal("the modules of the previous runtime weren't unsubscribed")
+	}
+}
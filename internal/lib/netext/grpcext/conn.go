@@ -13,6 +13,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 
 	protov1 "github.com/golang/protobuf/proto" //nolint:staticcheck,nolintlint // this is the old v1 version
@@ -281,6 +282,11 @@ func (h statsHandler) HandleRPC(ctx context.Context, stat grpcstats.RPCStats) {
 				stateRPC.tagsAndMeta.SetSystemTagOrMeta(metrics.TagIP, ip)
 			}
 		}
+		if state.Options.SystemTags.Has(metrics.TagIPFamily) {
+			if addr, ok := s.RemoteAddr.(*net.TCPAddr); ok {
+				stateRPC.tagsAndMeta.SetSystemTagOrMeta(metrics.TagIPFamily, types.IPFamily(addr.IP))
+			}
+		}
 	case *grpcstats.End:
 		if state.Options.SystemTags.Has(metrics.TagStatus) {
 			stateRPC.tagsAndMeta.SetSystemTagOrMeta(metrics.TagStatus, strconv.Itoa(int(status.Code(s.Error))))
@@ -0,0 +1,475 @@
+package grpcext
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+// WebProtocol is a protocol for making gRPC calls over plain HTTP requests,
+// as an alternative to the native gRPC framing.
+type WebProtocol string
+
+// The supported web protocols.
+const (
+	ProtocolGRPCWeb WebProtocol = "grpc-web"
+	ProtocolConnect WebProtocol = "connect"
+)
+
+const (
+	grpcWebContentType = "application/grpc-web+proto"
+	connectContentType = "application/proto"
+
+	grpcWebTrailerFlag    = 0x80
+	grpcWebCompressedFlag = 0x01
+	grpcWebFrameHeaderLen = 5
+)
+
+// WebOptions are the options of a connection that uses a web protocol.
+type WebOptions struct {
+	Protocol WebProtocol
+	GetState func() *lib.State
+	// TLSConfig is nil for plaintext connections.
+	TLSConfig      *tls.Config
+	Authority      string
+	UserAgent      string
+	MaxReceiveSize int64
+	MaxSendSize    int64
+}
+
+// DialWeb returns a connection that makes the unary calls with the gRPC-Web or
+// the Connect protocol, over HTTP/1.1 or HTTP/2, so that the gateways that
+// reject the native gRPC framing, like the ones for browsers, can be tested.
+// Streams aren't supported. The address can be a host:port or a base URL.
+// Unlike Dial, it doesn't connect to the server until the first call.
+func DialWeb(addr string, types *protoregistry.Types, opts WebOptions) (*Conn, error) {
+	baseURL := addr
+	if !strings.Contains(addr, "://") {
+		scheme := "https"
+		if opts.TLSConfig == nil {
+			scheme = "http"
+		}
+		baseURL = scheme + "://" + addr
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return opts.GetState().Dialer.DialContext(ctx, network, addr)
+		},
+		TLSClientConfig:   opts.TLSConfig,
+		ForceAttemptHTTP2: true,
+	}
+
+	return &Conn{
+		raw: &webConn{
+			opts:    opts,
+			baseURL: strings.TrimSuffix(baseURL, "/"),
+			client:  &http.Client{Transport: transport},
+		},
+		types: types,
+	}, nil
+}
+
+// webConn implements the unary calls of grpc.ClientConnInterface with HTTP
+// requests, using either the gRPC-Web or the Connect protocol.
+type webConn struct {
+	opts    WebOptions
+	baseURL string
+	client  *http.Client
+}
+
+// webCall is the result of a single call, before it's converted to the reply
+// and the error that grpc.ClientConnInterface.Invoke returns.
+type webCall struct {
+	message []byte
+	header  metadata.MD
+	trailer metadata.MD
+	status  *status.Status
+}
+
+func (c *webConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	start := time.Now()
+	call, err := c.invoke(ctx, method, args, reply)
+	c.emitMetrics(ctx, start, call.status)
+
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			*o.HeaderAddr = call.header
+		case grpc.TrailerCallOption:
+			*o.TrailerAddr = call.trailer
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return call.status.Err()
+}
+
+func (c *webConn) invoke(ctx context.Context, method string, args, reply any) (webCall, error) {
+	call := webCall{header: metadata.MD{}, trailer: metadata.MD{}}
+
+	reqMsg, ok := args.(proto.Message)
+	if !ok {
+		return call, failCall(&call, codes.Internal, fmt.Errorf("unexpected request message type %T", args))
+	}
+	replyMsg, ok := reply.(proto.Message)
+	if !ok {
+		return call, failCall(&call, codes.Internal, fmt.Errorf("unexpected reply message type %T", reply))
+	}
+	payload, err := proto.Marshal(reqMsg)
+	if err != nil {
+		return call, failCall(&call, codes.Internal, fmt.Errorf("unable to marshal the request message: %w", err))
+	}
+	if c.opts.MaxSendSize > 0 && int64(len(payload)) > c.opts.MaxSendSize {
+		return call, failCall(&call, codes.ResourceExhausted, fmt.Errorf(
+			"trying to send message larger than max (%d vs. %d)", len(payload), c.opts.MaxSendSize))
+	}
+
+	req, err := c.newRequest(ctx, method, payload)
+	if err != nil {
+		return call, failCall(&call, codes.Internal, err)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return call, failCall(&call, status.FromContextError(ctxErr).Code(), err)
+		}
+		return call, failCall(&call, codes.Unavailable, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if c.opts.Protocol == ProtocolConnect {
+		err = c.readConnectResponse(res, &call)
+	} else {
+		err = c.readGRPCWebResponse(res, &call)
+	}
+	if err != nil {
+		return call, failCall(&call, codes.Internal, err)
+	}
+
+	if call.status.Code() == codes.OK && call.message != nil {
+		if err := proto.Unmarshal(call.message, replyMsg); err != nil {
+			return call, failCall(&call, codes.Internal, fmt.Errorf("unable to unmarshal the response message: %w", err))
+		}
+	}
+	return call, nil
+}
+
+func failCall(call *webCall, code codes.Code, err error) error {
+	call.status = status.New(code, err.Error())
+	return call.status.Err()
+}
+
+func (c *webConn) newRequest(ctx context.Context, method string, payload []byte) (*http.Request, error) {
+	var body []byte
+	if c.opts.Protocol == ProtocolConnect {
+		body = payload
+	} else {
+		body = make([]byte, grpcWebFrameHeaderLen, grpcWebFrameHeaderLen+len(payload))
+		binary.BigEndian.PutUint32(body[1:], uint32(len(payload))) //nolint:gosec
+		body = append(body, payload...)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			if strings.HasSuffix(key, "-bin") {
+				value = base64.StdEncoding.EncodeToString([]byte(value))
+			}
+			req.Header.Add(key, value)
+		}
+	}
+
+	deadline, hasDeadline := ctx.Deadline()
+	timeout := time.Until(deadline).Milliseconds()
+	if c.opts.Protocol == ProtocolConnect {
+		req.Header.Set("Content-Type", connectContentType)
+		req.Header.Set("Connect-Protocol-Version", "1")
+		if hasDeadline {
+			req.Header.Set("Connect-Timeout-Ms", strconv.FormatInt(max(timeout, 1), 10))
+		}
+	} else {
+		req.Header.Set("Content-Type", grpcWebContentType)
+		req.Header.Set("X-Grpc-Web", "1")
+		if hasDeadline {
+			req.Header.Set("Grpc-Timeout", strconv.FormatInt(max(timeout, 1), 10)+"m")
+		}
+	}
+	if c.opts.UserAgent != "" {
+		req.Header.Set("User-Agent", c.opts.UserAgent)
+	}
+	if c.opts.Authority != "" {
+		req.Host = c.opts.Authority
+	}
+	return req, nil
+}
+
+// readGRPCWebResponse reads the data frame with the message and the trailers,
+// which gRPC-Web sends at the end of the body, in a frame with the trailer flag.
+// Responses without a message can also have the trailers in the HTTP headers.
+func (c *webConn) readGRPCWebResponse(res *http.Response, call *webCall) error {
+	call.header = headerToMetadata(res.Header, "")
+	if res.StatusCode != http.StatusOK {
+		call.status = statusFromHeaders(res.Header, res.StatusCode)
+		return nil
+	}
+
+	body := bufio.NewReader(res.Body)
+	header := make([]byte, grpcWebFrameHeaderLen)
+	for {
+		if _, err := io.ReadFull(body, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("unable to read the response frame: %w", err)
+		}
+		length := binary.BigEndian.Uint32(header[1:])
+		if c.opts.MaxReceiveSize > 0 && int64(length) > c.opts.MaxReceiveSize {
+			return fmt.Errorf("received message larger than max (%d vs. %d)", length, c.opts.MaxReceiveSize)
+		}
+		if header[0]&grpcWebCompressedFlag != 0 {
+			return errors.New("compressed response frames aren't supported")
+		}
+		frame := make([]byte, length)
+		if _, err := io.ReadFull(body, frame); err != nil {
+			return fmt.Errorf("unable to read the response frame: %w", err)
+		}
+
+		if header[0]&grpcWebTrailerFlag == 0 {
+			call.message = frame
+			continue
+		}
+		trailer, err := textproto.NewReader(bufio.NewReader(io.MultiReader(
+			bytes.NewReader(frame), strings.NewReader("\r\n")))).ReadMIMEHeader()
+		if err != nil {
+			return fmt.Errorf("unable to read the response trailers: %w", err)
+		}
+		call.trailer = headerToMetadata(http.Header(trailer), "")
+		call.status = statusFromHeaders(http.Header(trailer), res.StatusCode)
+		break
+	}
+
+	if call.status == nil {
+		// trailers-only responses have the status in the headers
+		call.status = statusFromHeaders(res.Header, res.StatusCode)
+	}
+	for _, key := range []string{"grpc-status", "grpc-message", "grpc-status-details-bin"} {
+		delete(call.header, key)
+		delete(call.trailer, key)
+	}
+	return nil
+}
+
+// readConnectResponse reads a unary Connect response, which has the message as
+// the whole body and the trailers as headers with the trailer- prefix. Errors
+// have a non-200 status code and are described by a JSON body.
+func (c *webConn) readConnectResponse(res *http.Response, call *webCall) error {
+	call.header = headerToMetadata(res.Header, "")
+	call.trailer = headerToMetadata(res.Header, "trailer-")
+	for key := range call.header {
+		if strings.HasPrefix(key, "trailer-") {
+			delete(call.header, key)
+		}
+	}
+
+	reader := io.Reader(res.Body)
+	if c.opts.MaxReceiveSize > 0 {
+		reader = io.LimitReader(res.Body, c.opts.MaxReceiveSize+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("unable to read the response body: %w", err)
+	}
+	if c.opts.MaxReceiveSize > 0 && int64(len(body)) > c.opts.MaxReceiveSize {
+		return fmt.Errorf("received message larger than max (%d)", c.opts.MaxReceiveSize)
+	}
+
+	if res.StatusCode == http.StatusOK {
+		call.message = body
+		call.status = status.New(codes.OK, "")
+		return nil
+	}
+
+	var connectErr struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &connectErr) != nil || connectErr.Code == "" {
+		call.status = status.New(codeFromHTTPStatus(res.StatusCode), http.StatusText(res.StatusCode))
+		return nil
+	}
+	code, ok := connectCodes[connectErr.Code]
+	if !ok {
+		code = codes.Unknown
+	}
+	call.status = status.New(code, connectErr.Message)
+	return nil
+}
+
+func (c *webConn) emitMetrics(ctx context.Context, start time.Time, st *status.Status) {
+	state := c.opts.GetState()
+	if state == nil {
+		return
+	}
+	var tagsAndMeta metrics.TagsAndMeta
+	if stateRPC := getRPCState(ctx); stateRPC != nil && stateRPC.tagsAndMeta != nil {
+		tagsAndMeta = *stateRPC.tagsAndMeta
+	} else {
+		tagsAndMeta = state.Tags.GetCurrentValues()
+	}
+	if state.Options.SystemTags.Has(metrics.TagStatus) {
+		tagsAndMeta.SetSystemTagOrMeta(metrics.TagStatus, strconv.Itoa(int(st.Code())))
+	}
+
+	end := time.Now()
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: state.BuiltinMetrics.GRPCReqDuration,
+			Tags:   tagsAndMeta.Tags,
+		},
+		Time:     end,
+		Metadata: tagsAndMeta.Metadata,
+		Value:    metrics.D(end.Sub(start)),
+	})
+}
+
+func (c *webConn) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "streams aren't supported with the %s protocol", c.opts.Protocol)
+}
+
+func (c *webConn) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// headerToMetadata converts the HTTP headers with the prefix to metadata,
+// without the prefix and with the binary values decoded.
+func headerToMetadata(header http.Header, prefix string) metadata.MD {
+	md := metadata.MD{}
+	for key, values := range header {
+		key = strings.ToLower(key)
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		key = strings.TrimPrefix(key, prefix)
+		for _, value := range values {
+			if strings.HasSuffix(key, "-bin") {
+				if decoded, err := decodeBinaryHeader(value); err == nil {
+					value = string(decoded)
+				}
+			}
+			md.Append(key, value)
+		}
+	}
+	return md
+}
+
+func decodeBinaryHeader(value string) ([]byte, error) {
+	if len(value)%4 == 0 {
+		return base64.StdEncoding.DecodeString(value)
+	}
+	return base64.RawStdEncoding.DecodeString(value)
+}
+
+// statusFromHeaders returns the status from the grpc-status and grpc-message
+// headers or, if they are missing, from the HTTP status code.
+func statusFromHeaders(header http.Header, httpCode int) *status.Status {
+	rawCode := header.Get("Grpc-Status")
+	if rawCode == "" {
+		if httpCode == http.StatusOK {
+			return status.New(codes.Internal, "the server didn't send a grpc-status")
+		}
+		return status.New(codeFromHTTPStatus(httpCode), http.StatusText(httpCode))
+	}
+	code, err := strconv.ParseUint(rawCode, 10, 32)
+	if err != nil {
+		return status.New(codes.Internal, fmt.Sprintf("invalid grpc-status %q", rawCode))
+	}
+	return status.New(codes.Code(code), decodeGRPCMessage(header.Get("Grpc-Message")))
+}
+
+// decodeGRPCMessage decodes the percent-encoding of the grpc-message header.
+func decodeGRPCMessage(msg string) string {
+	var sb strings.Builder
+	for i := 0; i < len(msg); i++ {
+		if msg[i] == '%' && i+2 < len(msg) {
+			if b, err := strconv.ParseUint(msg[i+1:i+3], 16, 8); err == nil {
+				sb.WriteByte(byte(b))
+				i += 2
+				continue
+			}
+		}
+		sb.WriteByte(msg[i])
+	}
+	return sb.String()
+}
+
+// codeFromHTTPStatus maps the HTTP status codes of responses without a gRPC
+// status, as described in
+// https://github.com/grpc/grpc/blob/master/doc/http-grpc-status-mapping.md
+func codeFromHTTPStatus(httpCode int) codes.Code {
+	switch httpCode {
+	case http.StatusBadRequest:
+		return codes.Internal
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
+
+//nolint:gochecknoglobals
+var connectCodes = map[string]codes.Code{
+	"canceled":            codes.Canceled,
+	"unknown":             codes.Unknown,
+	"invalid_argument":    codes.InvalidArgument,
+	"deadline_exceeded":   codes.DeadlineExceeded,
+	"not_found":           codes.NotFound,
+	"already_exists":      codes.AlreadyExists,
+	"permission_denied":   codes.PermissionDenied,
+	"resource_exhausted":  codes.ResourceExhausted,
+	"failed_precondition": codes.FailedPrecondition,
+	"aborted":             codes.Aborted,
+	"out_of_range":        codes.OutOfRange,
+	"unimplemented":       codes.Unimplemented,
+	"internal":            codes.Internal,
+	"unavailable":         codes.Unavailable,
+	"data_loss":           codes.DataLoss,
+	"unauthenticated":     codes.Unauthenticated,
+}
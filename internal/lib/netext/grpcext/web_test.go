@@ -0,0 +1,186 @@
+package grpcext
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+// helloWebHandler returns a handler for /hello.HelloService/SayHello that
+// replies to the greeting, or fails with NotFound if the greeting is "fail".
+func helloWebHandler(t *testing.T, protocol WebProtocol) http.Handler {
+	t.Helper()
+
+	md := methodFromProto("SayHello")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/hello.HelloService/SayHello", r.URL.Path)
+		assert.Equal(t, "k6-test", r.Header.Get("User-Agent"))
+		assert.Equal(t, "value", r.Header.Get("X-Custom"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if protocol == ProtocolGRPCWeb {
+			assert.Equal(t, "application/grpc-web+proto", r.Header.Get("Content-Type"))
+			assert.NotEmpty(t, r.Header.Get("Grpc-Timeout"))
+			require.Equal(t, int(binary.BigEndian.Uint32(body[1:5])), len(body)-5)
+			body = body[5:]
+		} else {
+			assert.Equal(t, "application/proto", r.Header.Get("Content-Type"))
+			assert.Equal(t, "1", r.Header.Get("Connect-Protocol-Version"))
+			assert.NotEmpty(t, r.Header.Get("Connect-Timeout-Ms"))
+		}
+		req := dynamicpb.NewMessage(md.Input())
+		require.NoError(t, proto.Unmarshal(body, req))
+		greeting := req.Get(md.Input().Fields().ByName("greeting")).String()
+
+		w.Header().Set("X-Header", "header-value")
+		if greeting == "fail" {
+			if protocol == ProtocolGRPCWeb {
+				w.Header().Set("Grpc-Status", "5")
+				w.Header().Set("Grpc-Message", "no greeting %22fail%22")
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"not_found","message":"no greeting \"fail\""}`))
+			return
+		}
+
+		res := dynamicpb.NewMessage(md.Output())
+		res.Set(md.Output().Fields().ByName("reply"), protoreflect.ValueOfString("hello, "+greeting))
+		payload, err := proto.Marshal(res)
+		require.NoError(t, err)
+
+		if protocol == ProtocolConnect {
+			w.Header().Set("Trailer-X-Trailer", "trailer-value")
+			_, _ = w.Write(payload)
+			return
+		}
+		trailer := "grpc-status: 0\r\nx-trailer: trailer-value\r\n"
+		_, _ = w.Write(grpcWebFrame(0, payload))
+		_, _ = w.Write(grpcWebFrame(grpcWebTrailerFlag, []byte(trailer)))
+	})
+}
+
+func grpcWebFrame(flag byte, payload []byte) []byte {
+	frame := make([]byte, 5, 5+len(payload))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload))) //nolint:gosec
+	return append(frame, payload...)
+}
+
+func TestWebInvoke(t *testing.T) {
+	t.Parallel()
+
+	for _, protocol := range []WebProtocol{ProtocolGRPCWeb, ProtocolConnect} {
+		t.Run(string(protocol), func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(helloWebHandler(t, protocol))
+			t.Cleanup(srv.Close)
+
+			registry := metrics.NewRegistry()
+			samples := make(chan metrics.SampleContainer, 10)
+			state := &lib.State{
+				Dialer:         &net.Dialer{},
+				Samples:        samples,
+				BuiltinMetrics: metrics.RegisterBuiltinMetrics(registry),
+				Tags:           lib.NewVUStateTags(registry.RootTagSet()),
+				Options:        lib.Options{SystemTags: metrics.NewSystemTagSet(metrics.TagStatus)},
+			}
+			c, err := DialWeb(strings.TrimPrefix(srv.URL, "http://"), &protoregistry.Types{}, WebOptions{
+				Protocol:  protocol,
+				GetState:  func() *lib.State { return state },
+				UserAgent: "k6-test",
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = c.Close() })
+
+			invoke := func(greeting string) *InvokeResponse {
+				res, err := c.Invoke(context.Background(), InvokeRequest{
+					Method:           "/hello.HelloService/SayHello",
+					MethodDescriptor: methodFromProto("SayHello"),
+					Timeout:          10 * time.Second,
+					Message:          fmt.Appendf(nil, `{"greeting":%q}`, greeting),
+					Metadata:         metadata.New(map[string]string{"x-custom": "value"}),
+				})
+				require.NoError(t, err)
+				return res
+			}
+
+			res := invoke("k6")
+			assert.Equal(t, codes.OK, res.Status)
+			assert.Equal(t, map[string]any{"reply": "hello, k6"}, res.Message)
+			assert.Empty(t, res.Error)
+			assert.Equal(t, []string{"header-value"}, res.Headers["x-header"])
+			assert.Equal(t, []string{"trailer-value"}, res.Trailers["x-trailer"])
+			assert.NotContains(t, res.Trailers, "grpc-status")
+
+			res = invoke("fail")
+			assert.Equal(t, codes.NotFound, res.Status)
+			assert.Equal(t, map[string]any{"code": 5.0, "message": `no greeting "fail"`, "details": []any{}}, res.Error)
+
+			var statuses []string
+			for _, sc := range metrics.GetBufferedSamples(samples) {
+				for _, s := range sc.GetSamples() {
+					assert.Equal(t, metrics.GRPCReqDurationName, s.Metric.Name)
+					status, _ := s.Tags.Get("status")
+					statuses = append(statuses, status)
+				}
+			}
+			assert.Equal(t, []string{"0", "5"}, statuses)
+		})
+	}
+}
+
+func TestWebInvokeHTTPError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	registry := metrics.NewRegistry()
+	state := &lib.State{
+		Dialer:         &net.Dialer{},
+		Samples:        make(chan metrics.SampleContainer, 10),
+		BuiltinMetrics: metrics.RegisterBuiltinMetrics(registry),
+		Tags:           lib.NewVUStateTags(registry.RootTagSet()),
+	}
+	for _, protocol := range []WebProtocol{ProtocolGRPCWeb, ProtocolConnect} {
+		c, err := DialWeb(srv.URL, &protoregistry.Types{}, WebOptions{
+			Protocol: protocol,
+			GetState: func() *lib.State { return state },
+		})
+		require.NoError(t, err)
+
+		_, err = c.HealthCheck(context.Background(), "")
+		assert.ErrorContains(t, err, "code = Unavailable", protocol)
+
+		_, err = c.NewStream(context.Background(), StreamRequest{
+			Method:           "/hello.HelloService/LotsOfReplies",
+			MethodDescriptor: methodFromProto("LotsOfReplies"),
+		})
+		assert.ErrorContains(t, err, fmt.Sprintf("streams aren't supported with the %s protocol", protocol))
+	}
+}
@@ -0,0 +1,305 @@
+// Package recorder records the HTTP traffic of a browser or an app through a
+// proxy, and generates a k6 script that replays it.
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxBodySize is the size of the biggest request body that is recorded, the
+// bigger ones are sent but not included in the script.
+const maxBodySize = 1 << 20
+
+// Entry is a recorded request.
+type Entry struct {
+	Time     time.Time
+	Duration time.Duration
+	Method   string
+	URL      *url.URL
+	Header   http.Header
+	Body     []byte
+	// BodyOmitted is set if the body was bigger than maxBodySize.
+	BodyOmitted bool
+	Status      int
+}
+
+// Proxy is an HTTP proxy that records the requests. The HTTPS requests are
+// intercepted with certificates signed by the CA, which the browser or the
+// app have to trust.
+type Proxy struct {
+	ca        tls.Certificate
+	caCert    *x509.Certificate
+	leafKey   *ecdsa.PrivateKey
+	transport http.RoundTripper
+	logger    logrus.FieldLogger
+
+	mu      sync.Mutex
+	entries []Entry
+	certs   map[string]*tls.Certificate
+}
+
+// NewProxy returns a new proxy that intercepts the HTTPS requests with
+// certificates signed by the CA.
+func NewProxy(ca tls.Certificate, transport http.RoundTripper, logger logrus.FieldLogger) (*Proxy, error) {
+	caCert, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA certificate: %w", err)
+	}
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Proxy{
+		ca:        ca,
+		caCert:    caCert,
+		leafKey:   leafKey,
+		transport: transport,
+		logger:    logger,
+		certs:     make(map[string]*tls.Certificate),
+	}, nil
+}
+
+// Entries returns the requests recorded so far, in the order they were sent.
+func (p *Proxy) Entries() []Entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Entry(nil), p.entries...)
+}
+
+// ServeHTTP implements http.Handler.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	if !r.URL.IsAbs() {
+		http.Error(w, "this is a recording proxy, configure it as the HTTP proxy of the browser or the app",
+			http.StatusBadRequest)
+		return
+	}
+
+	resp, err := p.roundTrip(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+	for k, vs := range resp.Header {
+		w.Header()[k] = vs
+	}
+	w.Header().Del("Connection")
+	w.Header().Del("Keep-Alive")
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// tunnel intercepts the HTTPS requests of a CONNECT tunnel, they are decrypted
+// with a certificate for the host signed by the CA.
+func (p *Proxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "tunnels aren't supported", http.StatusInternalServerError)
+		return
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		p.logger.WithError(err).Warn("Couldn't open the tunnel")
+		return
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err = io.WriteString(conn, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		return
+	}
+
+	host := r.URL.Host
+	tlsConn := tls.Server(conn, &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"http/1.1"},
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			name := hello.ServerName
+			if name == "" {
+				name, _, _ = net.SplitHostPort(host)
+			}
+			return p.certificate(name)
+		},
+	})
+	if err = tlsConn.Handshake(); err != nil {
+		p.logger.WithError(err).Warnf("The TLS handshake for %s failed, does the browser or the app trust the CA?", host)
+		return
+	}
+
+	br := bufio.NewReader(tlsConn)
+	for {
+		req, err := http.ReadRequest(br)
+		if err != nil {
+			return
+		}
+		req.URL.Scheme = "https"
+		req.URL.Host = req.Host
+		if req.URL.Host == "" {
+			req.URL.Host = host
+		}
+
+		resp, err := p.roundTrip(req)
+		if err != nil {
+			resp = &http.Response{
+				StatusCode: http.StatusBadGateway,
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+				Body:       io.NopCloser(bytes.NewBufferString(err.Error())),
+				Close:      true,
+			}
+		}
+		err = resp.Write(tlsConn)
+		_ = resp.Body.Close()
+		if err != nil || req.Close || resp.Close {
+			return
+		}
+	}
+}
+
+// roundTrip sends the request and records it, the response body has to be
+// read and closed by the caller.
+func (p *Proxy) roundTrip(r *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	entry := Entry{
+		Time:        time.Now(),
+		Method:      r.Method,
+		URL:         r.URL,
+		Header:      r.Header.Clone(),
+		Body:        body,
+		BodyOmitted: len(body) > maxBodySize,
+	}
+	if entry.BodyOmitted {
+		entry.Body = nil
+	}
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	out.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	for _, h := range []string{"Proxy-Connection", "Proxy-Authorization", "Connection", "Keep-Alive"} {
+		out.Header.Del(h)
+	}
+
+	resp, err := p.transport.RoundTrip(out)
+	entry.Duration = time.Since(entry.Time)
+	if err == nil {
+		entry.Status = resp.StatusCode
+	}
+	p.mu.Lock()
+	p.entries = append(p.entries, entry)
+	p.mu.Unlock()
+
+	if err != nil {
+		p.logger.WithError(err).Warnf("The request to %s failed", r.URL)
+		return nil, err
+	}
+	p.logger.Debugf("Recorded %s %s: %d", r.Method, r.URL, resp.StatusCode)
+	return resp, nil
+}
+
+// certificate returns the certificate for the host, signed by the CA.
+func (p *Proxy) certificate(host string) (*tls.Certificate, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cert, ok := p.certs[host]; ok {
+		return cert, nil
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(30 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, p.caCert, &p.leafKey.PublicKey, p.ca.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create the certificate for %s: %w", host, err)
+	}
+	cert := &tls.Certificate{Certificate: [][]byte{der, p.ca.Certificate[0]}, PrivateKey: p.leafKey}
+	p.certs[host] = cert
+	return cert, nil
+}
+
+// GenerateCA generates a CA for intercepting the HTTPS requests, it returns
+// the PEM encoded certificate and private key.
+func GenerateCA() (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "k6 record CA", Organization: []string{"k6"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+// LoadCA loads the PEM encoded certificate and private key of a CA.
+func LoadCA(certPEM, keyPEM []byte) (tls.Certificate, error) {
+	ca, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid CA: %w", err)
+	}
+	cert, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("invalid CA certificate: %w", err)
+	}
+	if !cert.IsCA {
+		return tls.Certificate{}, errors.New("the certificate isn't a CA")
+	}
+	return ca, nil
+}
@@ -0,0 +1,113 @@
+package recorder
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/lib/testutils"
+)
+
+func TestProxy(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Empty(t, r.Header.Get("Proxy-Connection"))
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.Path + " " + string(body)))
+	})
+	plainSrv := httptest.NewServer(handler)
+	t.Cleanup(plainSrv.Close)
+	tlsSrv := httptest.NewTLSServer(handler)
+	t.Cleanup(tlsSrv.Close)
+
+	certPEM, keyPEM, err := GenerateCA()
+	require.NoError(t, err)
+	ca, err := LoadCA(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	proxy, err := NewProxy(ca, tlsSrv.Client().Transport, testutils.NewLogger(t))
+	require.NoError(t, err)
+	proxySrv := httptest.NewServer(proxy)
+	t.Cleanup(proxySrv.Close)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(certPEM))
+	proxyURL, err := url.Parse(proxySrv.URL)
+	require.NoError(t, err)
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+	}}
+
+	for _, srv := range []*httptest.Server{plainSrv, tlsSrv} {
+		res, err := client.Post(srv.URL+"/post?a=1", "text/plain", strings.NewReader("data"))
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, "POST /post data", string(body))
+
+		res, err = client.Get(srv.URL + "/get")
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+
+	entries := proxy.Entries()
+	require.Len(t, entries, 4)
+	for i, e := range entries {
+		srv := plainSrv
+		if i >= 2 {
+			srv = tlsSrv
+		}
+		assert.Equal(t, http.StatusOK, e.Status)
+		assert.Equal(t, srv.URL, e.URL.Scheme+"://"+e.URL.Host)
+		if i%2 == 0 {
+			assert.Equal(t, http.MethodPost, e.Method)
+			assert.Equal(t, "/post?a=1", e.URL.RequestURI())
+			assert.Equal(t, "data", string(e.Body))
+			assert.Equal(t, "text/plain", e.Header.Get("Content-Type"))
+		} else {
+			assert.Equal(t, http.MethodGet, e.Method)
+			assert.Empty(t, e.Body)
+		}
+	}
+
+	res, err := http.Get(proxySrv.URL + "/direct")
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestLoadCA(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM, err := GenerateCA()
+	require.NoError(t, err)
+	_, err = LoadCA(certPEM, []byte("invalid"))
+	assert.ErrorContains(t, err, "invalid CA")
+
+	ca, err := LoadCA(certPEM, keyPEM)
+	require.NoError(t, err)
+	proxy, err := NewProxy(ca, http.DefaultTransport, testutils.NewLogger(t))
+	require.NoError(t, err)
+	leaf, err := proxy.certificate("example.com")
+	require.NoError(t, err)
+	leafKey, err := x509.MarshalECPrivateKey(proxy.leafKey)
+	require.NoError(t, err)
+	_, err = LoadCA(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Certificate[0]}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: leafKey}),
+	)
+	assert.ErrorContains(t, err, "the certificate isn't a CA")
+}
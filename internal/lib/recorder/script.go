@@ -0,0 +1,317 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultGroupGap is the default idle time after which the requests are put in
+// a new group.
+const DefaultGroupGap = 2 * time.Second
+
+// prunedHeaders are the request headers that aren't included in the scripts,
+// since k6 sets them itself, or they are specific to the recorded session.
+var prunedHeaders = map[string]bool{ //nolint:gochecknoglobals
+	"Host":                      true,
+	"Content-Length":            true,
+	"Connection":                true,
+	"Keep-Alive":                true,
+	"Proxy-Connection":          true,
+	"Proxy-Authorization":       true,
+	"Te":                        true,
+	"Trailer":                   true,
+	"Transfer-Encoding":         true,
+	"Upgrade":                   true,
+	"Accept-Encoding":           true, // k6 handles the compression
+	"Cookie":                    true, // the cookies are set by the responses, in the VU's cookie jar
+	"If-None-Match":             true,
+	"If-Modified-Since":         true,
+	"Priority":                  true,
+	"Upgrade-Insecure-Requests": true,
+}
+
+// prunedHeaderPrefixes are the prefixes of the browsers' headers that aren't
+// included in the scripts.
+var prunedHeaderPrefixes = []string{"Sec-Ch-", "Sec-Fetch-"} //nolint:gochecknoglobals
+
+// ScriptOptions are the options of the generated scripts.
+type ScriptOptions struct {
+	// IncludeHosts are the patterns of the hosts whose requests are included,
+	// like *.example.com. All of them are included if it's empty.
+	IncludeHosts []string
+	// ExcludeHeaders are the headers that are removed, besides the ones that
+	// are always removed.
+	ExcludeHeaders []string
+	// GroupGap is the idle time after which the requests are put in a new
+	// group, the navigations of the browsers always start a new group.
+	GroupGap time.Duration
+}
+
+// GenerateScript generates a k6 script that sends the recorded requests. The
+// requests are grouped by the pages they were sent for, with the think time
+// between them, and the URLs of each origin start with a constant that can be
+// changed with an environment variable, like BASE_URL.
+func GenerateScript(entries []Entry, opts ScriptOptions) ([]byte, error) {
+	if opts.GroupGap <= 0 {
+		opts.GroupGap = DefaultGroupGap
+	}
+	excluded := make(map[string]bool, len(opts.ExcludeHeaders))
+	for _, h := range opts.ExcludeHeaders {
+		excluded[http.CanonicalHeaderKey(h)] = true
+	}
+
+	var included []Entry
+	for _, e := range entries {
+		ok, err := matchHost(opts.IncludeHosts, e.URL.Hostname())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			included = append(included, e)
+		}
+	}
+	if len(included) == 0 {
+		return nil, errors.New("no requests were recorded")
+	}
+
+	g := &scriptGenerator{origins: make(map[string]string)}
+	headers := make([]map[string]string, len(included))
+	for i, e := range included {
+		headers[i] = pruneHeaders(e.Header, excluded)
+		g.originVar(e.URL.Scheme + "://" + e.URL.Host)
+	}
+	g.common = commonHeaders(headers)
+
+	g.line(0, `import http from "k6/http";`)
+	g.line(0, `import { group, sleep } from "k6";`)
+	g.line(0, "")
+	for _, origin := range g.originOrder {
+		name := g.origins[origin]
+		g.line(0, fmt.Sprintf("const %s = __ENV.%s || %s;", name, name, quote(origin)))
+	}
+	if len(g.common) > 0 {
+		g.line(0, "")
+		g.line(0, "const headers = "+headersLiteral(g.common, 0)+";")
+	}
+	g.line(0, "")
+	g.line(0, "export default function () {")
+
+	var groupEnd time.Time
+	for i, e := range included {
+		if i == 0 || isNavigation(e) || e.Time.Sub(groupEnd) > opts.GroupGap {
+			if i > 0 {
+				g.line(1, "});")
+				if thinkTime := math.Round(e.Time.Sub(groupEnd).Seconds()*10) / 10; thinkTime > 0 {
+					g.line(1, "sleep("+strconv.FormatFloat(thinkTime, 'f', -1, 64)+");")
+				}
+				g.line(0, "")
+			}
+			g.line(1, "group("+quote(groupName(e))+", function () {")
+		}
+		g.request(e, headers[i])
+		if end := e.Time.Add(e.Duration); end.After(groupEnd) {
+			groupEnd = end
+		}
+	}
+	g.line(1, "});")
+	g.line(0, "}")
+	return g.buf.Bytes(), nil
+}
+
+type scriptGenerator struct {
+	buf         bytes.Buffer
+	origins     map[string]string
+	originOrder []string
+	common      map[string]string
+}
+
+func (g *scriptGenerator) line(indent int, s string) {
+	if s != "" {
+		g.buf.WriteString(strings.Repeat("  ", indent))
+	}
+	g.buf.WriteString(s)
+	g.buf.WriteByte('\n')
+}
+
+// originVar returns the name of the constant of the origin, the first origin
+// is BASE_URL and the next ones are BASE_URL_2, BASE_URL_3 and so on.
+func (g *scriptGenerator) originVar(origin string) string {
+	if name, ok := g.origins[origin]; ok {
+		return name
+	}
+	name := "BASE_URL"
+	if len(g.originOrder) > 0 {
+		name += "_" + strconv.Itoa(len(g.originOrder)+1)
+	}
+	g.origins[origin] = name
+	g.originOrder = append(g.originOrder, origin)
+	return name
+}
+
+func (g *scriptGenerator) request(e Entry, headers map[string]string) {
+	url := g.originVar(e.URL.Scheme+"://"+e.URL.Host) + " + " + quote(e.URL.RequestURI())
+
+	own := make(map[string]string)
+	for k, v := range headers {
+		if g.common[k] != v {
+			own[k] = v
+		}
+	}
+	var params string
+	switch {
+	case len(g.common) > 0 && len(own) > 0:
+		own["...headers"] = ""
+		params = "{ headers: " + headersLiteral(own, 2) + " }"
+	case len(g.common) > 0:
+		params = "{ headers: headers }"
+	case len(own) > 0:
+		params = "{ headers: " + headersLiteral(own, 2) + " }"
+	}
+
+	body := "null"
+	switch {
+	case e.BodyOmitted:
+		g.line(2, "// the body was too big to be recorded")
+	case len(e.Body) > 0 && utf8.Valid(e.Body):
+		body = quote(string(e.Body))
+	case len(e.Body) > 0:
+		g.line(2, fmt.Sprintf("// the binary body of %d bytes wasn't recorded", len(e.Body)))
+	}
+
+	var args []string
+	switch e.Method {
+	case http.MethodGet, http.MethodHead:
+		args = []string{url}
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		args = []string{url, body}
+	default:
+		args = []string{quote(e.Method), url, body}
+	}
+	if params != "" {
+		args = append(args, params)
+	}
+	g.line(2, fmt.Sprintf("http.%s(%s);", functionName(e.Method), strings.Join(args, ", ")))
+}
+
+func functionName(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodOptions:
+		return strings.ToLower(method)
+	case http.MethodDelete:
+		return "del"
+	default:
+		return "request"
+	}
+}
+
+// headersLiteral returns the object literal of the headers, with the
+// "...headers" key for spreading the common headers.
+func headersLiteral(headers map[string]string, indent int) string {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, k := range keys {
+		b.WriteString(strings.Repeat("  ", indent+1))
+		if k == "...headers" {
+			b.WriteString(k)
+		} else {
+			b.WriteString(quote(k) + ": " + quote(headers[k]))
+		}
+		b.WriteString(",\n")
+	}
+	b.WriteString(strings.Repeat("  ", indent) + "}")
+	return b.String()
+}
+
+func pruneHeaders(header http.Header, excluded map[string]bool) map[string]string {
+	result := make(map[string]string, len(header))
+	for k, vs := range header {
+		k = http.CanonicalHeaderKey(k)
+		if prunedHeaders[k] || excluded[k] {
+			continue
+		}
+		pruned := false
+		for _, prefix := range prunedHeaderPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				pruned = true
+				break
+			}
+		}
+		if !pruned {
+			result[k] = strings.Join(vs, ", ")
+		}
+	}
+	return result
+}
+
+// commonHeaders returns the headers that all the requests have, with the same
+// values.
+func commonHeaders(headers []map[string]string) map[string]string {
+	common := make(map[string]string)
+	for k, v := range headers[0] {
+		common[k] = v
+	}
+	for _, h := range headers[1:] {
+		for k, v := range common {
+			if h[k] != v {
+				delete(common, k)
+			}
+		}
+	}
+	return common
+}
+
+// isNavigation returns whether the request was a navigation of a browser,
+// which loads a new page.
+func isNavigation(e Entry) bool {
+	return e.Header.Get("Sec-Fetch-Mode") == "navigate" || e.Header.Get("Sec-Fetch-Dest") == "document"
+}
+
+// groupName returns the name of the group that starts with the request, the
+// path of its URL.
+func groupName(e Entry) string {
+	if e.URL.Path == "" {
+		return "/"
+	}
+	// the group names can't contain the separator of the nested groups
+	return strings.ReplaceAll(e.URL.Path, "::", ":")
+}
+
+func matchHost(patterns []string, host string) (bool, error) {
+	if len(patterns) == 0 {
+		return true, nil
+	}
+	for _, pattern := range patterns {
+		ok, err := path.Match(pattern, host)
+		if err != nil {
+			return false, fmt.Errorf("invalid host pattern %q: %w", pattern, err)
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func quote(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,111 @@
+package recorder
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateScript(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	entry := func(offset time.Duration, method, rawURL string, header http.Header, body string) Entry {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		h := http.Header{
+			"User-Agent":      {"Mozilla/5.0"},
+			"Accept-Encoding": {"gzip"},
+			"Cookie":          {"session=1"},
+			"Sec-Ch-Ua":       {`"Chromium"`},
+		}
+		for k, vs := range header {
+			h[k] = vs
+		}
+		return Entry{
+			Time:     start.Add(offset),
+			Duration: 100 * time.Millisecond,
+			Method:   method,
+			URL:      u,
+			Header:   h,
+			Body:     []byte(body),
+			Status:   http.StatusOK,
+		}
+	}
+	navigation := http.Header{"Sec-Fetch-Mode": {"navigate"}, "Accept": {"text/html"}}
+	entries := []Entry{
+		entry(0, http.MethodGet, "https://example.com/", navigation, ""),
+		entry(50*time.Millisecond, http.MethodGet, "https://cdn.example.com/app.js", nil, ""),
+		entry(200*time.Millisecond, http.MethodGet, "https://tracker.test/pixel", nil, ""),
+		entry(time.Second, http.MethodGet, "https://example.com/login", navigation, ""),
+		entry(5*time.Second, http.MethodPost, "https://example.com/login?next=%2F", http.Header{
+			"Content-Type": {"application/x-www-form-urlencoded"},
+			"X-Trace":      {"abc"},
+		}, `user=k6&password="secret"`),
+		entry(5200*time.Millisecond, http.MethodDelete, "https://example.com/session", nil, ""),
+	}
+
+	script, err := GenerateScript(entries, ScriptOptions{
+		IncludeHosts:   []string{"example.com", "*.example.com"},
+		ExcludeHeaders: []string{"x-trace"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `import http from "k6/http";
+import { group, sleep } from "k6";
+
+const BASE_URL = __ENV.BASE_URL || "https://example.com";
+const BASE_URL_2 = __ENV.BASE_URL_2 || "https://cdn.example.com";
+
+const headers = {
+  "User-Agent": "Mozilla/5.0",
+};
+
+export default function () {
+  group("/", function () {
+    http.get(BASE_URL + "/", { headers: {
+      ...headers,
+      "Accept": "text/html",
+    } });
+    http.get(BASE_URL_2 + "/app.js", { headers: headers });
+  });
+  sleep(0.9);
+
+  group("/login", function () {
+    http.get(BASE_URL + "/login", { headers: {
+      ...headers,
+      "Accept": "text/html",
+    } });
+  });
+  sleep(3.9);
+
+  group("/login", function () {
+    http.post(BASE_URL + "/login?next=%2F", "user=k6&password=\"secret\"", { headers: {
+      ...headers,
+      "Content-Type": "application/x-www-form-urlencoded",
+    } });
+    http.del(BASE_URL + "/session", null, { headers: headers });
+  });
+}
+`, string(script))
+}
+
+func TestGenerateScriptErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := GenerateScript(nil, ScriptOptions{})
+	assert.ErrorContains(t, err, "no requests were recorded")
+
+	u, err := url.Parse("https://example.com/")
+	require.NoError(t, err)
+	entries := []Entry{{Method: http.MethodGet, URL: u, Header: http.Header{}}}
+	_, err = GenerateScript(entries, ScriptOptions{IncludeHosts: []string{"["}})
+	assert.ErrorContains(t, err, `invalid host pattern "["`)
+
+	script, err := GenerateScript(entries, ScriptOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, string(script), `http.get(BASE_URL + "/");`)
+}
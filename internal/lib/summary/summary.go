@@ -106,6 +106,7 @@ type Summary struct {
 	Thresholds `js:"thresholds"`
 	Group      `js:"root_group"`
 	Scenarios  map[string]Group
+	TimeSeries []TimeSeries
 
 	TestRunDuration           time.Duration
 	NoColor                   bool // TODO: drop this when noColor is part of the (runtime) options
@@ -125,6 +126,15 @@ func New() *Summary {
 	}
 }
 
+// TimeSeries holds the values of a metric in consecutive time buckets of the same length,
+// to render the shape of the test run in the summary.
+type TimeSeries struct {
+	MetricInfo
+	Interval time.Duration
+	// Buckets holds the values of each bucket, nil for those without samples.
+	Buckets []map[string]float64
+}
+
 // MetricInfo holds the definition of a metric that will be rendered in the summary,
 // including the name of the metric, its type (Counter, Trend, etc.) and what contains (data amounts, times, etc.).
 type MetricInfo struct {
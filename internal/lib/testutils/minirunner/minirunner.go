@@ -25,6 +25,7 @@ type MiniRunner struct {
 	Fn              func(ctx context.Context, state *lib.State, out chan<- metrics.SampleContainer) error
 	SetupFn         func(ctx context.Context, out chan<- metrics.SampleContainer) ([]byte, error)
 	TeardownFn      func(ctx context.Context, out chan<- metrics.SampleContainer) error
+	VUTeardownFn    func(ctx context.Context, state *lib.State, out chan<- metrics.SampleContainer) error
 	HandleSummaryFn func(context.Context, *lib.LegacySummary, *summary.Summary) (map[string]io.Reader, error)
 
 	SetupData []byte
@@ -222,3 +223,22 @@ func (vu *ActiveVU) RunOnce() error {
 	vu.incrIteration()
 	return vu.R.Fn(vu.RunContext, vu.State(), vu.Out)
 }
+
+// RunVUTeardown runs the mock vuTeardown function, if there is one.
+func (vu *ActiveVU) RunVUTeardown() error {
+	if vu.R.VUTeardownFn == nil {
+		return nil
+	}
+
+	select {
+	case <-vu.RunContext.Done():
+		return vu.RunContext.Err() // we are done, return
+	case vu.busy <- struct{}{}:
+		// nothing else can run now, and the VU cannot be deactivated
+	}
+	defer func() {
+		<-vu.busy // unlock deactivation again
+	}()
+
+	return vu.R.VUTeardownFn(vu.RunContext, vu.State(), vu.Out)
+}
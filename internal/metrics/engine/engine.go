@@ -4,6 +4,7 @@ package engine
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -31,6 +32,21 @@ type MetricsEngine struct {
 	metricsWithThresholds   []*metrics.Metric
 	breachedThresholdsCount uint32
 
+	// The tags by which the summaryBreakdownMetrics are broken down, the
+	// names of the sub-metrics that were already considered and the ones
+	// created for them, by their parent metric. They aren't added to the
+	// Submetrics of the metrics, which the outputs read without the lock.
+	summaryBreakdownTags       []string
+	summaryBreakdownNames      map[string]struct{}
+	summaryBreakdownSubmetrics map[*metrics.Metric][]*metrics.Submetric
+
+	// If positive, the trend metrics keep their exact values only for this
+	// long, the older ones are downsampled.
+	trendExactWindow time.Duration
+
+	// The failures tolerated by the errorBudget option, if it's set
+	errorBudget *errorBudget
+
 	// TODO: completely refactor:
 	//   - make these private, add a method to export the raw data
 	//   - do not use an unnecessary map for the observed metrics
@@ -109,13 +125,70 @@ func (me *MetricsEngine) markObserved(metric *metrics.Metric) {
 	if !metric.Observed {
 		metric.Observed = true
 		me.ObservedMetrics[metric.Name] = metric
+		// metrics are marked as observed before any samples are added to them
+		if trendSink, ok := metric.Sink.(*metrics.TrendSink); ok && me.trendExactWindow > 0 {
+			trendSink.SetExactWindow(me.trendExactWindow)
+		}
+	}
+}
+
+// summaryBreakdownMetrics are the metrics that are broken down in the
+// end-of-test summary by the tags specified in the summaryBreakdown option.
+//
+//nolint:gochecknoglobals
+var summaryBreakdownMetrics = map[string]struct{}{
+	metrics.IterationsName:        {},
+	metrics.IterationDurationName: {},
+	metrics.ChecksName:            {},
+	metrics.HTTPReqsName:          {},
+	metrics.HTTPReqFailedName:     {},
+	metrics.HTTPReqDurationName:   {},
+	metrics.WSSessionDurationName: {},
+	metrics.GRPCReqDurationName:   {},
+}
+
+// summaryBreakdownSubmetricsOf returns the sub-metrics of the summaryBreakdown
+// tags for the metric of the given sample, after creating the ones for the
+// values of the tags in the sample, so they are shown in the end-of-test
+// summary. It needs to be called with the MetricsLock held.
+func (me *MetricsEngine) summaryBreakdownSubmetricsOf(sample metrics.Sample) []*metrics.Submetric {
+	m := sample.Metric
+	if len(me.summaryBreakdownTags) == 0 || m.Sub != nil {
+		return nil
 	}
+	if _, ok := summaryBreakdownMetrics[m.Name]; !ok {
+		return nil
+	}
+	for _, tag := range me.summaryBreakdownTags {
+		value, ok := sample.Tags.Get(tag)
+		// sub-metric definitions can't contain commas and there is no point in
+		// creating a sub-metric for empty values, e.g. for the root group
+		if !ok || value == "" || strings.Contains(value, ",") {
+			continue
+		}
+		name := m.Name + "{" + tag + ":" + value + "}"
+		if _, ok := me.summaryBreakdownNames[name]; ok {
+			continue
+		}
+		me.summaryBreakdownNames[name] = struct{}{}
+
+		tags := me.registry.RootTagSet().With(tag, value)
+		if slices.ContainsFunc(m.Submetrics, func(sm *metrics.Submetric) bool { return sm.Tags == tags }) {
+			continue // a threshold already added it before the test started
+		}
+		sm := &metrics.Submetric{Name: name, Suffix: tag + ":" + value, Tags: tags, Parent: m}
+		sm.Metric = &metrics.Metric{Name: name, Type: m.Type, Contains: m.Contains, Sink: metrics.NewSink(m.Type), Sub: sm}
+		me.summaryBreakdownSubmetrics[m] = append(me.summaryBreakdownSubmetrics[m], sm)
+	}
+	return me.summaryBreakdownSubmetrics[m]
 }
 
 // InitSubMetricsAndThresholds parses the thresholds from the test Options and
 // initializes both the thresholds themselves, as well as any submetrics that
 // were referenced in them.
 func (me *MetricsEngine) InitSubMetricsAndThresholds(options lib.Options, onlyLogErrors bool) error {
+	me.trendExactWindow = options.TrendExactWindow.TimeDuration()
+
 	for metricName, thresholds := range options.Thresholds {
 		metric, err := me.getThresholdMetricOrSubmetric(metricName)
 
@@ -142,6 +215,10 @@ func (me *MetricsEngine) InitSubMetricsAndThresholds(options lib.Options, onlyLo
 		}
 	}
 
+	me.summaryBreakdownTags = options.SummaryBreakdown
+	me.summaryBreakdownNames = make(map[string]struct{})
+	me.summaryBreakdownSubmetrics = make(map[*metrics.Metric][]*metrics.Submetric)
+
 	// TODO: refactor out of here when https://github.com/grafana/k6/issues/1321
 	// lands and there is a better way to enable a metric with tag
 	if options.SystemTags.Has(metrics.TagExpectedResponse) {
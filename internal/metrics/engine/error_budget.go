@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+// errorBudget keeps track of the failures of the checks that are tolerated by
+// the errorBudget option. The checks are counted by the engine itself, instead
+// of with sub-metrics, so they aren't added to the end-of-test summary.
+type errorBudget struct {
+	budget lib.ErrorBudget
+	checks map[string]*metrics.RateSink
+}
+
+// InitErrorBudget sets up the evaluation of the error budget of the test run,
+// if it has one.
+func (me *MetricsEngine) InitErrorBudget(options lib.Options) {
+	if options.ErrorBudget == nil {
+		return
+	}
+	if len(options.ErrorBudget.Checks) > 0 && !options.SystemTags.Has(metrics.TagCheck) {
+		me.logger.Warn("The errorBudget has checks, but the check system tag is disabled, so their failures aren't counted")
+	}
+
+	eb := &errorBudget{
+		budget: *options.ErrorBudget,
+		checks: make(map[string]*metrics.RateSink, len(options.ErrorBudget.Checks)),
+	}
+	for name := range eb.budget.Checks {
+		eb.checks[name] = &metrics.RateSink{}
+	}
+	me.errorBudget = eb
+}
+
+// addErrorBudgetSample counts the sample if it's of a check of the error
+// budget. It needs to be called with the MetricsLock held.
+func (me *MetricsEngine) addErrorBudgetSample(sample metrics.Sample) {
+	if me.errorBudget == nil || sample.Metric.Name != metrics.ChecksName {
+		return
+	}
+	name, _ := sample.Tags.Get(metrics.TagCheck.String())
+	if sink, ok := me.errorBudget.checks[name]; ok {
+		sink.Add(sample)
+	}
+}
+
+// StartErrorBudgetCalculations spins up a new goroutine that periodically
+// evaluates the error budget, it aborts the test run when the budget is
+// exhausted if abortOnExhausted is enabled. It returns a callback that stops
+// the goroutine and returns an error if the budget was exhausted in the end.
+func (me *MetricsEngine) StartErrorBudgetCalculations(
+	ingester *OutputIngester,
+	abortRun func(error),
+	getFailedIterations func() uint64,
+) (finalize func() error) {
+	if me.errorBudget == nil {
+		return nil
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(thresholdsRate)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				exhausted := me.evaluateErrorBudget(getFailedIterations())
+				if len(exhausted) == 0 {
+					continue
+				}
+				if !me.errorBudget.budget.AbortOnExhausted.Bool {
+					me.logger.Warnf("The error budget is exhausted: %s", strings.Join(exhausted, "; "))
+					return // the test run is marked as failed at the end
+				}
+				err := fmt.Errorf(
+					"the error budget is exhausted and abortOnExhausted is enabled, stopping test prematurely: %s",
+					strings.Join(exhausted, "; "),
+				)
+				me.logger.Debug(err.Error())
+				abortRun(errext.WithAbortReasonIfNone(
+					errext.WithExitCodeIfNone(err, exitcodes.ErrorBudgetExhausted), errext.AbortedByErrorBudget,
+				))
+				return
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return func() error {
+		if ingester != nil {
+			// Stop the ingester so we don't get any more metrics
+			if err := ingester.Stop(); err != nil {
+				me.logger.WithError(err).Warnf("There was a problem stopping the output ingester.")
+			}
+		}
+		close(stop)
+		<-done
+
+		exhausted := me.evaluateErrorBudget(getFailedIterations())
+		if len(exhausted) == 0 {
+			return nil
+		}
+		// like the thresholds, the test run finished but it's marked as failed
+		return errext.WithAbortReasonIfNone(
+			errext.WithExitCodeIfNone(
+				fmt.Errorf("the error budget has been exhausted: %s", strings.Join(exhausted, "; ")),
+				exitcodes.ErrorBudgetExhausted,
+			), errext.AbortedByThresholdsAfterTestEnd)
+	}
+}
+
+// evaluateErrorBudget returns how the error budget was exhausted, if it was.
+func (me *MetricsEngine) evaluateErrorBudget(failedIterations uint64) (exhausted []string) {
+	me.MetricsLock.Lock()
+	defer me.MetricsLock.Unlock()
+
+	budget := me.errorBudget.budget
+	if budget.FailedIterations.Valid && failedIterations > uint64(budget.FailedIterations.Int64) { //nolint:gosec
+		exhausted = append(exhausted, fmt.Sprintf(
+			"%d iterations failed, more than the %d allowed", failedIterations, budget.FailedIterations.Int64))
+	}
+	for _, name := range budget.CheckNames() {
+		sink := me.errorBudget.checks[name]
+		if sink.Total == 0 {
+			continue
+		}
+		failed := float64(sink.Total-sink.Trues) / float64(sink.Total) * 100
+		if failed > budget.Checks[name] {
+			exhausted = append(exhausted, fmt.Sprintf(
+				"%.2f%% of the check %q failed, more than the %g%% allowed", failed, name, budget.Checks[name]))
+		}
+	}
+	return exhausted
+}
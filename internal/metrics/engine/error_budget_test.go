@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/errext/exitcodes"
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+func TestErrorBudget(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, budget *lib.ErrorBudget, failedIterations uint64, checks map[string][]float64) error {
+		piState := newTestPreInitState(t)
+		builtinMetrics := metrics.RegisterBuiltinMetrics(piState.Registry)
+		me := &MetricsEngine{
+			logger:          piState.Logger,
+			registry:        piState.Registry,
+			ObservedMetrics: make(map[string]*metrics.Metric),
+		}
+		me.InitErrorBudget(lib.Options{ErrorBudget: budget, SystemTags: &metrics.DefaultSystemTagSet})
+
+		ingester := me.CreateIngester()
+		require.NoError(t, ingester.Start())
+		for name, values := range checks {
+			for _, value := range values {
+				ingester.AddMetricSamples([]metrics.SampleContainer{metrics.Sample{
+					TimeSeries: metrics.TimeSeries{
+						Metric: builtinMetrics.Checks,
+						Tags:   piState.Registry.RootTagSet().With("check", name),
+					},
+					Value: value,
+				}})
+			}
+		}
+
+		finalize := me.StartErrorBudgetCalculations(ingester, func(error) {}, func() uint64 { return failedIterations })
+		require.NotNil(t, finalize)
+		return finalize()
+	}
+
+	budget := &lib.ErrorBudget{
+		FailedIterations: null.IntFrom(2),
+		Checks:           map[string]float64{"status is 200": 25},
+	}
+	checks := map[string][]float64{
+		"status is 200": {1, 1, 1, 0},
+		"other":         {0, 0},
+	}
+	require.NoError(t, run(t, budget, 2, checks))
+
+	err := run(t, budget, 3, checks)
+	require.ErrorContains(t, err, "3 iterations failed, more than the 2 allowed")
+	var ecerr errext.HasExitCode
+	require.ErrorAs(t, err, &ecerr)
+	assert.Equal(t, exitcodes.ErrorBudgetExhausted, ecerr.ExitCode())
+
+	checks["status is 200"] = append(checks["status is 200"], 0)
+	err = run(t, budget, 0, checks)
+	require.ErrorContains(t, err, `40.00% of the check "status is 200" failed, more than the 25% allowed`)
+	assert.NotContains(t, err.Error(), "iterations failed")
+}
+
+func TestErrorBudgetNone(t *testing.T) {
+	t.Parallel()
+
+	me := newTestMetricsEngine(t)
+	me.InitErrorBudget(lib.Options{})
+	assert.Nil(t, me.StartErrorBudgetCalculations(nil, func(error) {}, func() uint64 { return 0 }))
+}
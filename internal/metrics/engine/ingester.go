@@ -85,17 +85,27 @@ func (oi *OutputIngester) flushMetrics() {
 		}
 
 		for _, sample := range samples {
+			if sample.IsWarmup() {
+				continue // warm-up samples are excluded from thresholds and the summary
+			}
+
+			oi.metricsEngine.addErrorBudgetSample(sample)
+
 			m := sample.Metric               // this should have come from the Registry, no need to look it up
 			oi.metricsEngine.markObserved(m) // mark it as observed so it shows in the end-of-test summary
 			m.Sink.Add(sample)               // finally, add its value to its own sink
 
 			// and also to the same for any submetrics that match the metric sample
-			for _, sm := range m.Submetrics {
-				if !sample.Tags.Contains(sm.Tags) {
-					continue
+			for _, submetrics := range [][]*metrics.Submetric{
+				m.Submetrics, oi.metricsEngine.summaryBreakdownSubmetricsOf(sample),
+			} {
+				for _, sm := range submetrics {
+					if !sample.Tags.Contains(sm.Tags) {
+						continue
+					}
+					oi.metricsEngine.markObserved(sm.Metric)
+					sm.Metric.Sink.Add(sample)
 				}
-				oi.metricsEngine.markObserved(sm.Metric)
-				sm.Metric.Sink.Add(sample)
 			}
 
 			oi.cardinality.Add(sample.TimeSeries)
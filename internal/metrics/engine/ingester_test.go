@@ -3,12 +3,14 @@ package engine
 import (
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.k6.io/k6/internal/lib/testutils"
 	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
 
@@ -47,6 +49,157 @@ func TestIngesterOutputFlushMetrics(t *testing.T) {
 	assert.Equal(t, 42.0, sink.Total())
 }
 
+func TestIngesterOutputSkipsWarmupSamples(t *testing.T) {
+	t.Parallel()
+
+	piState := newTestPreInitState(t)
+	testMetric, err := piState.Registry.NewMetric("test_metric", metrics.Trend)
+	require.NoError(t, err)
+
+	ingester := OutputIngester{
+		logger: piState.Logger,
+		metricsEngine: &MetricsEngine{
+			ObservedMetrics: make(map[string]*metrics.Metric),
+		},
+		cardinality: newCardinalityControl(),
+	}
+	require.NoError(t, ingester.Start())
+	ingester.AddMetricSamples([]metrics.SampleContainer{metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: testMetric,
+			Tags:   piState.Registry.RootTagSet().With(metrics.PhaseTagName, metrics.WarmupPhaseTagVal),
+		},
+		Value: 1000,
+	}})
+	ingester.AddMetricSamples([]metrics.SampleContainer{metrics.Sample{
+		TimeSeries: metrics.TimeSeries{Metric: testMetric, Tags: piState.Registry.RootTagSet()},
+		Value:      21,
+	}})
+	require.NoError(t, ingester.Stop())
+
+	metric := ingester.metricsEngine.ObservedMetrics["test_metric"]
+	require.NotNil(t, metric)
+	sink := metric.Sink.(*metrics.TrendSink)
+	assert.Equal(t, 21.0, sink.Total())
+	assert.Equal(t, uint64(1), sink.Count())
+}
+
+func TestIngesterOutputSummaryBreakdown(t *testing.T) {
+	t.Parallel()
+
+	piState := newTestPreInitState(t)
+	builtinMetrics := metrics.RegisterBuiltinMetrics(piState.Registry)
+	me := &MetricsEngine{
+		logger:          piState.Logger,
+		registry:        piState.Registry,
+		ObservedMetrics: make(map[string]*metrics.Metric),
+	}
+	thresholds := metrics.NewThresholds([]string{"count>0"})
+	require.NoError(t, me.InitSubMetricsAndThresholds(lib.Options{
+		SummaryBreakdown: []string{"scenario", "group"},
+		Thresholds:       map[string]metrics.Thresholds{"http_req_duration{scenario:b}": thresholds},
+	}, false))
+
+	ingester := OutputIngester{
+		logger:        piState.Logger,
+		metricsEngine: me,
+		cardinality:   newCardinalityControl(),
+	}
+	require.NoError(t, ingester.Start())
+	for _, tags := range []map[string]string{
+		{"scenario": "a", "group": ""},
+		{"scenario": "a", "group": "::g1"},
+		{"scenario": "b", "group": "::g1"},
+	} {
+		ingester.AddMetricSamples([]metrics.SampleContainer{
+			metrics.Sample{
+				TimeSeries: metrics.TimeSeries{
+					Metric: builtinMetrics.HTTPReqDuration,
+					Tags:   piState.Registry.RootTagSet().WithTagsFromMap(tags),
+				},
+				Value: 10,
+			},
+			metrics.Sample{
+				TimeSeries: metrics.TimeSeries{
+					Metric: builtinMetrics.DataSent,
+					Tags:   piState.Registry.RootTagSet().WithTagsFromMap(tags),
+				},
+				Value: 10,
+			},
+		})
+	}
+	require.NoError(t, ingester.Stop())
+
+	observed := make([]string, 0, len(me.ObservedMetrics))
+	for name := range me.ObservedMetrics {
+		observed = append(observed, name)
+	}
+	assert.ElementsMatch(t, []string{
+		"http_req_duration",
+		"http_req_duration{scenario:a}",
+		"http_req_duration{scenario:b}",
+		"http_req_duration{group:::g1}",
+		"data_sent",
+	}, observed)
+
+	sink := me.ObservedMetrics["http_req_duration{scenario:a}"].Sink.(*metrics.TrendSink)
+	assert.Equal(t, uint64(2), sink.Count())
+	sink = me.ObservedMetrics["http_req_duration{group:::g1}"].Sink.(*metrics.TrendSink)
+	assert.Equal(t, uint64(2), sink.Count())
+	sink = me.ObservedMetrics["http_req_duration{scenario:b}"].Sink.(*metrics.TrendSink)
+	assert.Equal(t, uint64(1), sink.Count())
+
+	// the outputs read the sub-metrics of the metrics without the lock, so
+	// only the ones of the thresholds are added to them
+	require.Len(t, builtinMetrics.HTTPReqDuration.Submetrics, 1)
+	assert.Equal(t, "http_req_duration{scenario:b}", builtinMetrics.HTTPReqDuration.Submetrics[0].Name)
+}
+
+func TestIngesterOutputTrendExactWindow(t *testing.T) {
+	t.Parallel()
+
+	piState := newTestPreInitState(t)
+	testMetric, err := piState.Registry.NewMetric("test_metric", metrics.Trend)
+	require.NoError(t, err)
+	me := &MetricsEngine{
+		logger:          piState.Logger,
+		registry:        piState.Registry,
+		ObservedMetrics: make(map[string]*metrics.Metric),
+	}
+	require.NoError(t, me.InitSubMetricsAndThresholds(lib.Options{
+		TrendExactWindow: types.NullDurationFrom(time.Second),
+		Thresholds: map[string]metrics.Thresholds{
+			"test_metric{a:1}": {},
+		},
+	}, false))
+
+	ingester := OutputIngester{
+		logger:        piState.Logger,
+		metricsEngine: me,
+		cardinality:   newCardinalityControl(),
+	}
+	require.NoError(t, ingester.Start())
+	start := time.Now()
+	for i := range 100 {
+		ingester.AddMetricSamples([]metrics.SampleContainer{metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: testMetric,
+				Tags:   piState.Registry.RootTagSet().With("a", "1"),
+			},
+			Time:  start.Add(time.Duration(i) * time.Second),
+			Value: float64(i),
+		}})
+	}
+	require.NoError(t, ingester.Stop())
+
+	for _, name := range []string{"test_metric", "test_metric{a:1}"} {
+		sink := me.ObservedMetrics[name].Sink.(*metrics.TrendSink) //nolint:forcetypeassert
+		assert.Equal(t, uint64(100), sink.Count())
+		assert.InDelta(t, 49.5, sink.P(0.5), 0.5)
+		assert.InDelta(t, 95, sink.P(0.96), 1)
+	}
+}
+
 func TestIngesterOutputFlushSubmetrics(t *testing.T) {
 	t.Parallel()
 
@@ -336,6 +336,8 @@ func (out *Output) getRunStatus(testErr error) cloudapi.RunStatus {
 			return cloudapi.RunStatusAbortedLimit
 		case errext.AbortedByOutput:
 			return cloudapi.RunStatusAbortedSystem
+		case errext.AbortedByErrorBudget:
+			return cloudapi.RunStatusAbortedThreshold
 		case errext.AbortedByThresholdsAfterTestEnd:
 			// The test run finished normally, it wasn't prematurely aborted by
 			// anything while running, but the thresholds failed at the end and
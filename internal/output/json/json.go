@@ -4,6 +4,7 @@ package json
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -13,6 +14,8 @@ import (
 	"github.com/mailru/easyjson/jwriter"
 	"github.com/sirupsen/logrus"
 
+	"go.k6.io/k6/errext"
+	"go.k6.io/k6/lib"
 	"go.k6.io/k6/metrics"
 	"go.k6.io/k6/output"
 )
@@ -33,6 +36,10 @@ type Output struct {
 	closeFn     func() error
 	seenMetrics map[string]struct{}
 	thresholds  map[string]metrics.Thresholds
+
+	runMetadata        *lib.RunMetadata
+	runMetadataVersion uint64
+	runMetadataWritten bool
 }
 
 // New returns a new JSON output.
@@ -104,9 +111,25 @@ func (o *Output) Start() error {
 
 // Stop flushes any remaining metrics and stops the goroutine.
 func (o *Output) Stop() error {
+	return o.StopWithTestError(nil)
+}
+
+// StopWithTestError flushes any remaining metrics, writes the details of the
+// abort if the test run was aborted by the script and stops the goroutine.
+func (o *Output) StopWithTestError(testRunErr error) error {
 	o.logger.Debug("Stopping...")
 	defer o.logger.Debug("Stopped!")
 	o.periodicFlusher.Stop()
+
+	var interruptErr *errext.InterruptError
+	if errors.As(testRunErr, &interruptErr) {
+		jw := new(jwriter.Writer)
+		wrapAbort(interruptErr).MarshalEasyJSON(jw)
+		jw.RawByte('\n')
+		if _, err := jw.DumpTo(o.out); err != nil {
+			o.logger.WithError(err).Error("Abort details couldn't be marshalled to JSON")
+		}
+	}
 	return o.closeFn()
 }
 
@@ -121,11 +144,18 @@ func (o *Output) SetThresholds(thresholds map[string]metrics.Thresholds) {
 	}
 }
 
+// SetRunMetadata receives the metadata of the test run, which is written
+// whenever it changes.
+func (o *Output) SetRunMetadata(runMetadata *lib.RunMetadata) {
+	o.runMetadata = runMetadata
+}
+
 func (o *Output) flushMetrics() {
 	samples := o.GetBufferedSamples()
 	start := time.Now()
 	var count int
 	jw := new(jwriter.Writer)
+	o.handleRunMetadata(jw)
 	for _, sc := range samples {
 		samples := sc.GetSamples()
 		count += len(samples)
@@ -167,3 +197,23 @@ func (o *Output) handleMetric(m *metrics.Metric, jw *jwriter.Writer) {
 	wrapped.MarshalEasyJSON(jw)
 	jw.RawByte('\n')
 }
+
+func (o *Output) handleRunMetadata(jw *jwriter.Writer) {
+	if o.runMetadata == nil {
+		return
+	}
+	version := o.runMetadata.Version()
+	if o.runMetadataWritten && version == o.runMetadataVersion {
+		return
+	}
+	values := o.runMetadata.Map()
+	if !o.runMetadataWritten && len(values) == 0 {
+		return
+	}
+	o.runMetadataVersion = version
+	o.runMetadataWritten = true
+
+	wrapped := runMetadataEnvelope{Type: "RunMetadata", Data: values}
+	wrapped.MarshalEasyJSON(jw)
+	jw.RawByte('\n')
+}
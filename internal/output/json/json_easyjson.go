@@ -204,7 +204,249 @@ func easyjson42239ddeEncode(out *jwriter.Writer, in struct {
 	}
 	out.RawByte('}')
 }
-func easyjson42239ddeDecodeGoK6IoK6InternalOutputJson1(in *jlexer.Lexer, out *metricEnvelope) {
+func easyjson42239ddeDecodeGoK6IoK6InternalOutputJson1(in *jlexer.Lexer, out *runMetadataEnvelope) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "data":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.Data = make(map[string]string)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v3 string
+					v3 = string(in.String())
+					(out.Data)[key] = v3
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGoK6IoK6InternalOutputJson1(out *jwriter.Writer, in runMetadataEnvelope) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"data\":"
+		out.RawString(prefix)
+		if in.Data == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v4First := true
+			for v4Name, v4Value := range in.Data {
+				if v4First {
+					v4First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v4Name))
+				out.RawByte(':')
+				out.String(string(v4Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v runMetadataEnvelope) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGoK6IoK6InternalOutputJson1(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *runMetadataEnvelope) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGoK6IoK6InternalOutputJson1(l, v)
+}
+func easyjson42239ddeDecodeGoK6IoK6InternalOutputJson3(in *jlexer.Lexer, out *abortEnvelope) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "data":
+			easyjson42239ddeDecode2(in, &out.Data)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncodeGoK6IoK6InternalOutputJson3(out *jwriter.Writer, in abortEnvelope) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"data\":"
+		out.RawString(prefix)
+		easyjson42239ddeEncode2(out, in.Data)
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v abortEnvelope) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson42239ddeEncodeGoK6IoK6InternalOutputJson3(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *abortEnvelope) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson42239ddeDecodeGoK6IoK6InternalOutputJson3(l, v)
+}
+func easyjson42239ddeDecode2(in *jlexer.Lexer, out *struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Tags    map[string]string `json:"tags"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "code":
+			out.Code = int(in.Int())
+		case "message":
+			out.Message = string(in.String())
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.Tags = make(map[string]string)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v8 string
+					v8 = string(in.String())
+					(out.Tags)[key] = v8
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson42239ddeEncode2(out *jwriter.Writer, in struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Tags    map[string]string `json:"tags"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Code))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	{
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		if in.Tags == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v9First := true
+			for v9Name, v9Value := range in.Tags {
+				if v9First {
+					v9First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v9Name))
+				out.RawByte(':')
+				out.String(string(v9Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson42239ddeDecodeGoK6IoK6InternalOutputJson2(in *jlexer.Lexer, out *metricEnvelope) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -239,7 +481,7 @@ func easyjson42239ddeDecodeGoK6IoK6InternalOutputJson1(in *jlexer.Lexer, out *me
 		in.Consumed()
 	}
 }
-func easyjson42239ddeEncodeGoK6IoK6InternalOutputJson1(out *jwriter.Writer, in metricEnvelope) {
+func easyjson42239ddeEncodeGoK6IoK6InternalOutputJson2(out *jwriter.Writer, in metricEnvelope) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -263,12 +505,12 @@ func easyjson42239ddeEncodeGoK6IoK6InternalOutputJson1(out *jwriter.Writer, in m
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
 func (v metricEnvelope) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjson42239ddeEncodeGoK6IoK6InternalOutputJson1(w, v)
+	easyjson42239ddeEncodeGoK6IoK6InternalOutputJson2(w, v)
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
 func (v *metricEnvelope) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjson42239ddeDecodeGoK6IoK6InternalOutputJson1(l, v)
+	easyjson42239ddeDecodeGoK6IoK6InternalOutputJson2(l, v)
 }
 func easyjson42239ddeDecode1(in *jlexer.Lexer, out *struct {
 	Name       string               `json:"name"`
@@ -325,17 +567,17 @@ func easyjson42239ddeDecode1(in *jlexer.Lexer, out *struct {
 					out.Submetrics = (out.Submetrics)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v3 *metrics.Submetric
+					var v5 *metrics.Submetric
 					if in.IsNull() {
 						in.Skip()
-						v3 = nil
+						v5 = nil
 					} else {
-						if v3 == nil {
-							v3 = new(metrics.Submetric)
+						if v5 == nil {
+							v5 = new(metrics.Submetric)
 						}
-						easyjson42239ddeDecodeGoK6IoK6Metrics(in, v3)
+						easyjson42239ddeDecodeGoK6IoK6Metrics(in, v5)
 					}
-					out.Submetrics = append(out.Submetrics, v3)
+					out.Submetrics = append(out.Submetrics, v5)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -387,14 +629,14 @@ func easyjson42239ddeEncode1(out *jwriter.Writer, in struct {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v4, v5 := range in.Submetrics {
-				if v4 > 0 {
+			for v6, v7 := range in.Submetrics {
+				if v6 > 0 {
 					out.RawByte(',')
 				}
-				if v5 == nil {
+				if v7 == nil {
 					out.RawString("null")
 				} else {
-					easyjson42239ddeEncodeGoK6IoK6Metrics(out, *v5)
+					easyjson42239ddeEncodeGoK6IoK6Metrics(out, *v7)
 				}
 			}
 			out.RawByte(']')
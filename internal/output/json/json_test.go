@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"testing"
 	"time"
@@ -11,7 +12,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.k6.io/k6/errext"
 	"go.k6.io/k6/internal/lib/testutils"
+	"go.k6.io/k6/lib"
 	"go.k6.io/k6/lib/fsext"
 	"go.k6.io/k6/metrics"
 	"go.k6.io/k6/output"
@@ -206,6 +209,55 @@ func TestJsonOutputFileGzipped(t *testing.T) {
 	assert.NoError(t, file.Close())
 }
 
+func TestJsonOutputRunMetadata(t *testing.T) {
+	t.Parallel()
+
+	stdout := new(bytes.Buffer)
+	out, err := New(output.Params{Logger: testutils.NewLogger(t)})
+	require.NoError(t, err)
+	jout, ok := out.(*Output)
+	require.True(t, ok)
+
+	// the flushes are triggered manually, without the periodic flusher
+	jout.out = stdout
+	runMetadata := lib.NewRunMetadata(map[string]string{"git_sha": "abc123"})
+	jout.SetRunMetadata(runMetadata)
+	jout.flushMetrics()
+	jout.flushMetrics()
+	runMetadata.Set("build", "42")
+	jout.flushMetrics()
+
+	getValidator(t, []string{
+		`{"type":"RunMetadata","data":{"git_sha":"abc123"}}`,
+		`{"type":"RunMetadata","data":{"git_sha":"abc123","build":"42"}}`,
+	})(stdout)
+}
+
+func TestJsonOutputAbort(t *testing.T) {
+	t.Parallel()
+
+	stdout := new(bytes.Buffer)
+	out, err := New(output.Params{
+		Logger: testutils.NewLogger(t),
+		StdOut: stdout,
+	})
+	require.NoError(t, err)
+	jout, ok := out.(*Output)
+	require.True(t, ok)
+
+	require.NoError(t, jout.Start())
+	require.NoError(t, jout.StopWithTestError(fmt.Errorf("wrapped: %w", &errext.InterruptError{
+		Reason:  "test aborted: slo breached",
+		Code:    42,
+		Message: "slo breached",
+		Tags:    map[string]string{"slo": "latency"},
+	})))
+
+	getValidator(t, []string{
+		`{"type":"Abort","data":{"code":42,"message":"slo breached","tags":{"slo":"latency"}}}`,
+	})(stdout)
+}
+
 func TestWrapSampleWithSamplePointer(t *testing.T) {
 	t.Parallel()
 	out := wrapSample(metrics.Sample{
@@ -3,6 +3,7 @@ package json
 import (
 	"time"
 
+	"go.k6.io/k6/errext"
 	"go.k6.io/k6/metrics"
 )
 
@@ -46,3 +47,28 @@ type metricEnvelope struct {
 	} `json:"data"`
 	Metric string `json:"metric"`
 }
+
+//easyjson:json
+type runMetadataEnvelope struct {
+	Type string            `json:"type"`
+	Data map[string]string `json:"data"`
+}
+
+//easyjson:json
+type abortEnvelope struct {
+	Type string `json:"type"`
+	Data struct {
+		Code    int               `json:"code"`
+		Message string            `json:"message"`
+		Tags    map[string]string `json:"tags"`
+	} `json:"data"`
+}
+
+// wrapAbort is used to package the details of a test.abort() call.
+func wrapAbort(abort *errext.InterruptError) abortEnvelope {
+	a := abortEnvelope{Type: "Abort"}
+	a.Data.Code = int(abort.ExitCode())
+	a.Data.Message = abort.Message
+	a.Data.Tags = abort.Tags
+	return a
+}
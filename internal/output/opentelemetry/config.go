@@ -83,6 +83,11 @@ type Config struct {
 	// When it is set to true, metrics are exported as a single counter, using an attribute as discriminator.
 	// When the opposite, the old method is used generating two different counters.
 	SingleCounterForRate null.Bool `json:"singleCounterForRate" envconfig:"K6_OTEL_SINGLE_COUNTER_FOR_RATE"`
+
+	// MetadataExemplars is a comma-separated list of the metadata keys of the samples, like trace_id or order_id,
+	// that are exported as exemplars. The listed keys are dropped from the attributes of all the metrics, even
+	// where they are tags. No metadata is exported if it's empty.
+	MetadataExemplars null.String `json:"metadataExemplars" envconfig:"K6_OTEL_METADATA_EXEMPLARS"`
 }
 
 // GetConsolidatedConfig combines the options' values from the different sources
@@ -212,6 +217,10 @@ func (cfg Config) Apply(v Config) Config {
 		cfg.SingleCounterForRate = v.SingleCounterForRate
 	}
 
+	if v.MetadataExemplars.Valid {
+		cfg.MetadataExemplars = v.MetadataExemplars
+	}
+
 	return cfg
 }
 
@@ -73,6 +73,7 @@ func TestConfig(t *testing.T) {
 				"K6_OTEL_TLS_CLIENT_KEY":           "client_key_path",
 				"K6_OTEL_HEADERS":                  "key1=value1,key2=value2",
 				"K6_OTEL_SINGLE_COUNTER_FOR_RATE":  "false",
+				"K6_OTEL_METADATA_EXEMPLARS":       "trace_id,order_id",
 			},
 			expectedConfig: Config{
 				ServiceName:           null.NewString("foo", true),
@@ -92,6 +93,7 @@ func TestConfig(t *testing.T) {
 				TLSClientKey:          null.NewString("client_key_path", true),
 				Headers:               null.NewString("key1=value1,key2=value2", true),
 				SingleCounterForRate:  null.NewBool(false, true),
+				MetadataExemplars:     null.NewString("trace_id,order_id", true),
 			},
 		},
 
@@ -133,7 +135,8 @@ func TestConfig(t *testing.T) {
 					`"tlsClientCertificate":"client_cert_path",` +
 					`"tlsClientKey":"client_key_path",` +
 					`"headers":"key1=value1,key2=value2",` +
-					`"singleCounterForRate":false` +
+					`"singleCounterForRate":false,` +
+					`"metadataExemplars":"trace_id,order_id"` +
 					`}`,
 			),
 			expectedConfig: Config{
@@ -154,6 +157,7 @@ func TestConfig(t *testing.T) {
 				TLSClientKey:          null.NewString("client_key_path", true),
 				Headers:               null.NewString("key1=value1,key2=value2", true),
 				SingleCounterForRate:  null.NewBool(false, true),
+				MetadataExemplars:     null.NewString("trace_id,order_id", true),
 			},
 		},
 
@@ -0,0 +1,95 @@
+package opentelemetry
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceIDMetadata is the metadata key whose value, when it is a valid W3C trace
+// ID, is exported as the trace ID of the exemplar instead of as an attribute.
+const traceIDMetadata = "trace_id"
+
+type metadataCtxKey struct{}
+
+// metadataExemplars exports the configured keys of the metadata of the samples
+// as exemplars. The metadata is recorded as extra attributes of the
+// measurements, that a view drops from the time series, so they end up only as
+// the filtered attributes of the exemplars, without adding to the cardinality
+// of the metrics.
+type metadataExemplars struct {
+	// keys are the configured ones, they are dropped from the attributes of
+	// the time series by the view.
+	keys map[attribute.Key]struct{}
+}
+
+// newMetadataExemplars returns the exporter of the metadata keys of the
+// comma-separated list, or nil if it's empty.
+func newMetadataExemplars(list string) *metadataExemplars {
+	keys := make(map[attribute.Key]struct{})
+	for _, key := range strings.Split(list, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys[attribute.Key(key)] = struct{}{}
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return &metadataExemplars{keys: keys}
+}
+
+// options returns the options of the meter provider for exporting the metadata
+// as exemplars.
+func (me *metadataExemplars) options() []metric.Option {
+	return []metric.Option{
+		metric.WithExemplarFilter(func(ctx context.Context) bool {
+			return ctx.Value(metadataCtxKey{}) != nil
+		}),
+		metric.WithView(metric.NewView(
+			metric.Instrument{Name: "*"},
+			metric.Stream{AttributeFilter: func(kv attribute.KeyValue) bool {
+				_, isMetadata := me.keys[kv.Key]
+				return !isMetadata
+			}},
+		)),
+	}
+}
+
+// record returns the context and the extra attributes to record a measurement
+// of a sample with the given metadata, so they are offered as an exemplar. The
+// metadata keys that aren't configured, or that are already used by the tags
+// of the sample, are skipped.
+func (me *metadataExemplars) record(
+	ctx context.Context, metadata map[string]string, tags attribute.Set,
+) (context.Context, []attribute.KeyValue) {
+	var attrs []attribute.KeyValue
+	exemplar := false
+	for key, value := range metadata {
+		k := attribute.Key(key)
+		if _, ok := me.keys[k]; !ok {
+			continue
+		}
+		if key == traceIDMetadata {
+			if traceID, err := trace.TraceIDFromHex(value); err == nil {
+				ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+					TraceID:    traceID,
+					TraceFlags: trace.FlagsSampled,
+				}))
+				exemplar = true
+				continue
+			}
+		}
+		if tags.HasValue(k) {
+			continue
+		}
+		attrs = append(attrs, k.String(value))
+		exemplar = true
+	}
+	if !exemplar {
+		return ctx, nil
+	}
+	return context.WithValue(ctx, metadataCtxKey{}, true), attrs
+}
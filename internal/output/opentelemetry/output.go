@@ -27,6 +27,7 @@ type Output struct {
 
 	meterProvider   *metric.MeterProvider
 	metricsRegistry *registry
+	exemplars       *metadataExemplars
 }
 
 var _ output.WithStopWithTestError = new(Output)
@@ -95,7 +96,7 @@ func (o *Output) Start() error {
 		return fmt.Errorf("failed to create OpenTelemetry resource: %w", err)
 	}
 
-	meterProvider := metric.NewMeterProvider(
+	opts := []metric.Option{
 		metric.WithResource(res),
 		metric.WithReader(
 			metric.NewPeriodicReader(
@@ -103,7 +104,11 @@ func (o *Output) Start() error {
 				metric.WithInterval(o.config.ExportInterval.TimeDuration()),
 			),
 		),
-	)
+	}
+	if o.exemplars = newMetadataExemplars(o.config.MetadataExemplars.String); o.exemplars != nil {
+		opts = append(opts, o.exemplars.options()...)
+	}
+	meterProvider := metric.NewMeterProvider(opts...)
 
 	pf, err := output.NewPeriodicFlusher(o.config.FlushInterval.TimeDuration(), o.flushMetrics)
 	if err != nil {
@@ -157,6 +162,13 @@ func (o *Output) dispatch(entry metrics.Sample) error {
 
 	attributeSet := newAttributeSet(entry.Tags)
 	attributeSetOpt := otelMetric.WithAttributeSet(attributeSet)
+	if o.exemplars != nil {
+		var metadataAttrs []attribute.KeyValue
+		ctx, metadataAttrs = o.exemplars.record(ctx, entry.Metadata, attributeSet)
+		if len(metadataAttrs) > 0 {
+			attributeSetOpt = otelMetric.WithAttributeSet(attribute.NewSet(append(attributeSet.ToSlice(), metadataAttrs...)...))
+		}
+	}
 
 	unit := normalizeUnit(entry.Metric.Contains)
 
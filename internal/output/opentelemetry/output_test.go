@@ -2,6 +2,7 @@ package opentelemetry
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net"
@@ -348,3 +349,69 @@ func assertHasAttribute(t *testing.T, attrs []*commonpb.KeyValue, key, value str
 	}
 	t.Errorf("Attribute %s not found", key)
 }
+
+func TestOutputMetadataExemplars(t *testing.T) {
+	t.Parallel()
+
+	server := createServer(t, "grpc")
+	defer server.Stop()
+
+	config := createTestConfig("grpc", server.Endpoint())
+	config["K6_OTEL_METADATA_EXEMPLARS"] = "trace_id, order_id"
+	output := setupOutput(t, config)
+
+	registry := metrics.NewRegistry()
+	sample := createTestSample(t, registry, metrics.Counter, 1.0)
+	sample.Metadata = map[string]string{
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"order_id": "42",
+		"tag1":     "collides",
+		"user_id":  "not listed",
+	}
+	output.AddMetricSamples([]metrics.SampleContainer{metrics.Samples([]metrics.Sample{sample})})
+
+	require.NoError(t, output.Stop())
+
+	time.Sleep(300 * time.Millisecond)
+	validateMetrics(t, server.LastMetrics(), func(t *testing.T, mr *collectormetrics.ExportMetricsServiceRequest) {
+		metric := findMetric(mr, "test.counter_metric")
+		require.NotNil(t, metric, "counter metric not found")
+		sum := metric.GetSum()
+		require.NotNil(t, sum)
+		require.Len(t, sum.DataPoints, 1)
+
+		dp := sum.DataPoints[0]
+		require.Len(t, dp.Attributes, 1, "metadata must not be added to the attributes of the time series")
+		assertHasAttribute(t, dp.Attributes, "tag1", "value1")
+
+		require.Len(t, dp.Exemplars, 1)
+		exemplar := dp.Exemplars[0]
+		assert.Equal(t, 1.0, exemplar.GetAsDouble())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", hex.EncodeToString(exemplar.TraceId))
+		require.Len(t, exemplar.FilteredAttributes, 1)
+		assertHasAttribute(t, exemplar.FilteredAttributes, "order_id", "42")
+	})
+}
+
+func TestOutputMetadataExemplarsDisabled(t *testing.T) {
+	t.Parallel()
+
+	server := createServer(t, "http")
+	defer server.Stop()
+
+	output := setupOutput(t, createTestConfig("http", server.Endpoint()))
+
+	registry := metrics.NewRegistry()
+	sample := createTestSample(t, registry, metrics.Trend, 25.0)
+	sample.Metadata = map[string]string{"order_id": "42"}
+	output.AddMetricSamples([]metrics.SampleContainer{metrics.Samples([]metrics.Sample{sample})})
+
+	require.NoError(t, output.Stop())
+
+	time.Sleep(300 * time.Millisecond)
+	validateMetrics(t, server.LastMetrics(), func(t *testing.T, mr *collectormetrics.ExportMetricsServiceRequest) {
+		validateTrendMetric(t, mr)
+		histogram := findMetric(mr, "test.trend_metric").GetHistogram()
+		assert.Empty(t, histogram.DataPoints[0].Exemplars)
+	})
+}
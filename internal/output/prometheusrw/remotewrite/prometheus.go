@@ -9,7 +9,13 @@ import (
 	"go.k6.io/k6/metrics"
 )
 
-const namelbl = "__name__"
+const (
+	namelbl = "__name__"
+
+	// runMetadataInfoName is the name of the info series with the metadata of
+	// the test run as labels.
+	runMetadataInfoName = "run_metadata_info"
+)
 
 // MapTagSet converts a k6 tag set into
 // the equivalent set of Labels as expected from the
@@ -51,3 +57,37 @@ func MapSeries(series metrics.TimeSeries, suffix string) []*prompb.Label {
 	})
 	return lbls
 }
+
+// MapRunMetadata converts the metadata of a test run into the labels of the
+// info series that describes it. The keys that aren't valid label names are
+// sanitized, and the labels are lexicographic sorted.
+func MapRunMetadata(metadata map[string]string) []*prompb.Label {
+	lbls := make([]*prompb.Label, 0, len(metadata)+1)
+	lbls = append(lbls, &prompb.Label{Name: namelbl, Value: defaultMetricPrefix + runMetadataInfoName})
+	for key, value := range metadata {
+		if key == "" || value == "" {
+			continue
+		}
+		lbls = append(lbls, &prompb.Label{Name: sanitizeLabelName(key), Value: value})
+	}
+	slices.SortStableFunc(lbls, func(i, j *prompb.Label) int {
+		return cmp.Compare(i.Name, j.Name)
+	})
+	// the sanitized keys can clash, only the first of them is kept
+	return slices.CompactFunc(lbls, func(i, j *prompb.Label) bool {
+		return i.Name == j.Name
+	})
+}
+
+// sanitizeLabelName replaces the characters that aren't allowed in the label
+// names with underscores.
+func sanitizeLabelName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		isLetter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+		if !isLetter && (i == 0 || c < '0' || c > '9') {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
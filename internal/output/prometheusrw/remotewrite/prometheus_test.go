@@ -44,6 +44,24 @@ func TestMapSeries(t *testing.T) {
 	assert.Equal(t, exp, lbls)
 }
 
+func TestMapRunMetadata(t *testing.T) {
+	t.Parallel()
+
+	lbls := MapRunMetadata(map[string]string{
+		"git-sha":     "abc123",
+		"environment": "staging",
+		"1build":      "42",
+		"empty":       "",
+	})
+	exp := []*prompb.Label{
+		{Name: "__name__", Value: "k6_run_metadata_info"},
+		{Name: "_build", Value: "42"},
+		{Name: "environment", Value: "staging"},
+		{Name: "git_sha", Value: "abc123"},
+	}
+	assert.Equal(t, exp, lbls)
+}
+
 // buildTimeSeries creates a TimSeries with the given name, value and timestamp
 func buildTimeSeries(name string, value float64, timestamp time.Time) *prompb.TimeSeries { //nolint:unparam
 	return &prompb.TimeSeries{
@@ -10,6 +10,7 @@ import (
 	"go.k6.io/k6/internal/output/prometheusrw/remote"
 	"go.k6.io/k6/internal/output/prometheusrw/stale"
 
+	"go.k6.io/k6/lib"
 	"go.k6.io/k6/metrics"
 	"go.k6.io/k6/output"
 
@@ -29,6 +30,7 @@ type Output struct {
 	periodicFlusher    *output.PeriodicFlusher
 	tsdb               map[metrics.TimeSeries]*seriesWithMeasure
 	trendStatsResolver map[string]func(*metrics.TrendSink) float64
+	runMetadata        *lib.RunMetadata
 
 	// TODO: copy the prometheus/remote.WriteClient interface and depend on it
 	client *remote.WriteClient
@@ -89,6 +91,12 @@ func (o *Output) Start() error {
 	return nil
 }
 
+// SetRunMetadata receives the metadata of the test run, which is sent as the
+// labels of an info series on every flush.
+func (o *Output) SetRunMetadata(runMetadata *lib.RunMetadata) {
+	o.runMetadata = runMetadata
+}
+
 // Stop stops the output.
 func (o *Output) Stop() error {
 	o.logger.Debug("Stopping the output")
@@ -144,6 +152,11 @@ func (o *Output) staleMarkers() []*prompb.TimeSeries {
 		}
 		staleMarkers = append(staleMarkers, series...)
 	}
+	if series := o.runMetadataSeries(); series != nil {
+		series.Samples[0].Value = stale.Marker
+		series.Samples[0].Timestamp = timestamp
+		staleMarkers = append(staleMarkers, series)
+	}
 	return staleMarkers
 }
 
@@ -224,6 +237,9 @@ func (o *Output) flush() {
 	// Prometheus write handler processes only some fields as of now, so here we'll add only them.
 
 	promTimeSeries := o.convertToPbSeries(samplesContainers)
+	if series := o.runMetadataSeries(); series != nil {
+		promTimeSeries = append(promTimeSeries, series)
+	}
 	nts = len(promTimeSeries)
 	o.logger.WithField("nts", nts).Debug("Converted samples to Prometheus TimeSeries")
 
@@ -298,6 +314,24 @@ func (o *Output) convertToPbSeries(samplesContainers []metrics.SampleContainer)
 	return pbseries
 }
 
+// runMetadataSeries returns the info series with the current metadata of the
+// test run, or nil if there isn't any.
+func (o *Output) runMetadataSeries() *prompb.TimeSeries {
+	if o.runMetadata == nil {
+		return nil
+	}
+	metadata := o.runMetadata.Map()
+	if len(metadata) == 0 {
+		return nil
+	}
+	return &prompb.TimeSeries{
+		Labels: MapRunMetadata(metadata),
+		Samples: []*prompb.Sample{
+			{Value: 1, Timestamp: o.now().Truncate(time.Millisecond).UnixMilli()},
+		},
+	}
+}
+
 type seriesWithMeasure struct {
 	metrics.TimeSeries
 	Measure metrics.Sink
@@ -0,0 +1,125 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/mstoykov/envconfig"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib/types"
+)
+
+const (
+	formatJSON     = "json"
+	formatProtobuf = "protobuf"
+)
+
+// Config is the config for the stream output.
+type Config struct {
+	// URL is the ws://, wss://, http:// or https:// address the frames are sent to.
+	URL     null.String       `json:"url" envconfig:"K6_STREAM_URL"`
+	Headers map[string]string `json:"headers,omitempty" envconfig:"K6_STREAM_HEADERS"`
+	// Format of the frames, either json or protobuf.
+	Format       null.String        `json:"format,omitempty" envconfig:"K6_STREAM_FORMAT"`
+	PushInterval types.NullDuration `json:"pushInterval,omitempty" envconfig:"K6_STREAM_PUSH_INTERVAL"`
+	Timeout      types.NullDuration `json:"timeout,omitempty" envconfig:"K6_STREAM_TIMEOUT"`
+	// BufferSize is how many frames are kept while the collector can't be reached.
+	BufferSize null.Int `json:"bufferSize,omitempty" envconfig:"K6_STREAM_BUFFER_SIZE"`
+}
+
+// NewConfig creates a new Config instance with default values for some fields.
+func NewConfig() Config {
+	return Config{
+		Format:       null.NewString(formatJSON, false),
+		PushInterval: types.NewNullDuration(time.Second, false),
+		Timeout:      types.NewNullDuration(5*time.Second, false),
+		BufferSize:   null.NewInt(100, false),
+	}
+}
+
+// Apply merges two configs by overwriting properties in the old config
+func (c Config) Apply(cfg Config) Config {
+	if cfg.URL.Valid {
+		c.URL = cfg.URL
+	}
+	if len(cfg.Headers) > 0 {
+		c.Headers = cfg.Headers
+	}
+	if cfg.Format.Valid {
+		c.Format = cfg.Format
+	}
+	if cfg.PushInterval.Valid {
+		c.PushInterval = cfg.PushInterval
+	}
+	if cfg.Timeout.Valid {
+		c.Timeout = cfg.Timeout
+	}
+	if cfg.BufferSize.Valid {
+		c.BufferSize = cfg.BufferSize
+	}
+	return c
+}
+
+// Validate checks that the config is usable.
+func (c Config) Validate() error {
+	if !c.URL.Valid || c.URL.String == "" {
+		return fmt.Errorf("a URL is required, e.g. --out stream=ws://localhost:9000")
+	}
+	u, err := url.Parse(c.URL.String)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", c.URL.String, err)
+	}
+	switch u.Scheme {
+	case "ws", "wss", "http", "https":
+	default:
+		return fmt.Errorf("unsupported URL scheme %q, only ws, wss, http and https are supported", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("the URL %q has no host", c.URL.String)
+	}
+	if c.Format.String != formatJSON && c.Format.String != formatProtobuf {
+		return fmt.Errorf("invalid format %q, it should be either %s or %s", c.Format.String, formatJSON, formatProtobuf)
+	}
+	if c.PushInterval.Duration <= 0 {
+		return fmt.Errorf("pushInterval should be positive but was %s", c.PushInterval.Duration)
+	}
+	if c.Timeout.Duration <= 0 {
+		return fmt.Errorf("timeout should be positive but was %s", c.Timeout.Duration)
+	}
+	if c.BufferSize.Int64 <= 0 {
+		return fmt.Errorf("bufferSize should be positive but was %d", c.BufferSize.Int64)
+	}
+	return nil
+}
+
+// GetConsolidatedConfig combines {default config values + JSON config +
+// environment vars + URL argument}, and returns the final result.
+func GetConsolidatedConfig(jsonRawConf json.RawMessage, env map[string]string, arg string) (Config, error) {
+	result := NewConfig()
+	if jsonRawConf != nil {
+		jsonConf := Config{}
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, err
+		}
+		result = result.Apply(jsonConf)
+	}
+
+	envConfig := Config{}
+	if err := envconfig.Process("", &envConfig, func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}); err != nil {
+		// TODO: get rid of envconfig and actually use the env parameter...
+		return result, err
+	}
+	result = result.Apply(envConfig)
+
+	if arg != "" {
+		result.URL = null.StringFrom(arg)
+	}
+
+	return result, result.Validate()
+}
@@ -0,0 +1,86 @@
+package stream
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib/types"
+)
+
+func TestGetConsolidatedConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		jsonRaw       json.RawMessage
+		env           map[string]string
+		arg           string
+		expected      Config
+		expectedError string
+	}{
+		"defaults": {
+			arg: "ws://localhost:9000",
+			expected: Config{
+				URL:          null.StringFrom("ws://localhost:9000"),
+				Format:       null.NewString("json", false),
+				PushInterval: types.NewNullDuration(time.Second, false),
+				Timeout:      types.NewNullDuration(5*time.Second, false),
+				BufferSize:   null.NewInt(100, false),
+			},
+		},
+		"json, env and arg": {
+			jsonRaw: json.RawMessage(`{"url":"http://json:9000","format":"protobuf","bufferSize":5}`),
+			env: map[string]string{
+				"K6_STREAM_PUSH_INTERVAL": "250ms",
+				"K6_STREAM_HEADERS":       "Authorization:Bearer token",
+			},
+			arg: "https://collector/frames",
+			expected: Config{
+				URL:          null.StringFrom("https://collector/frames"),
+				Headers:      map[string]string{"Authorization": "Bearer token"},
+				Format:       null.StringFrom("protobuf"),
+				PushInterval: types.NullDurationFrom(250 * time.Millisecond),
+				Timeout:      types.NewNullDuration(5*time.Second, false),
+				BufferSize:   null.IntFrom(5),
+			},
+		},
+		"no url": {
+			expectedError: "a URL is required",
+		},
+		"unsupported scheme": {
+			arg:           "tcp://localhost:9000",
+			expectedError: `unsupported URL scheme "tcp"`,
+		},
+		"no host": {
+			arg:           "ws:///frames",
+			expectedError: "has no host",
+		},
+		"bad format": {
+			arg:           "ws://localhost:9000",
+			env:           map[string]string{"K6_STREAM_FORMAT": "msgpack"},
+			expectedError: `invalid format "msgpack"`,
+		},
+		"bad buffer size": {
+			arg:           "ws://localhost:9000",
+			env:           map[string]string{"K6_STREAM_BUFFER_SIZE": "0"},
+			expectedError: "bufferSize should be positive",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c, err := GetConsolidatedConfig(tc.jsonRaw, tc.env, tc.arg)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, c)
+		})
+	}
+}
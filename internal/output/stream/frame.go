@@ -0,0 +1,164 @@
+package stream
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"go.k6.io/k6/metrics"
+)
+
+// frame is a batch of the metrics of a single push interval, pre-aggregated
+// for every time series. Its protobuf encoding is described in stream.proto.
+type frame struct {
+	Seq      uint64    `json:"seq"`
+	Time     time.Time `json:"time"`
+	Interval float64   `json:"interval"`
+	Series   []series  `json:"series"`
+}
+
+type series struct {
+	Metric string             `json:"metric"`
+	Type   string             `json:"type"`
+	Tags   map[string]string  `json:"tags,omitempty"`
+	Values map[string]float64 `json:"values"`
+}
+
+// aggregator aggregates the samples of a push interval per time series.
+type aggregator struct {
+	sinks map[metrics.TimeSeries]metrics.Sink
+}
+
+func newAggregator() *aggregator {
+	return &aggregator{sinks: make(map[metrics.TimeSeries]metrics.Sink)}
+}
+
+func (a *aggregator) add(s metrics.Sample) {
+	sink, ok := a.sinks[s.TimeSeries]
+	if !ok {
+		sink = metrics.NewSink(s.Metric.Type)
+		a.sinks[s.TimeSeries] = sink
+	}
+	sink.Add(s)
+}
+
+// series returns the aggregated values of every time series seen since the
+// last call, sorted by metric name, and resets the aggregator.
+func (a *aggregator) series(interval time.Duration) []series {
+	result := make([]series, 0, len(a.sinks))
+	for ts, sink := range a.sinks {
+		result = append(result, series{
+			Metric: ts.Metric.Name,
+			Type:   ts.Metric.Type.String(),
+			Tags:   ts.Tags.Map(),
+			Values: sinkValues(sink, interval),
+		})
+	}
+	a.sinks = make(map[metrics.TimeSeries]metrics.Sink)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Metric != result[j].Metric {
+			return result[i].Metric < result[j].Metric
+		}
+		return tagsKey(result[i].Tags) < tagsKey(result[j].Tags)
+	})
+	return result
+}
+
+func tagsKey(tags map[string]string) string {
+	b, _ := json.Marshal(tags) //nolint:errchkjson // maps are marshaled with sorted keys
+	return string(b)
+}
+
+func sinkValues(sink metrics.Sink, interval time.Duration) map[string]float64 {
+	switch s := sink.(type) {
+	case *metrics.CounterSink:
+		values := map[string]float64{"count": s.Value}
+		if interval > 0 {
+			values["rate"] = s.Value / interval.Seconds()
+		}
+		return values
+	case *metrics.GaugeSink:
+		return map[string]float64{"value": s.Value, "min": s.Min, "max": s.Max}
+	case *metrics.RateSink:
+		values := map[string]float64{"passes": float64(s.Trues), "fails": float64(s.Total - s.Trues)}
+		if s.Total > 0 {
+			values["rate"] = float64(s.Trues) / float64(s.Total)
+		}
+		return values
+	case *metrics.TrendSink:
+		return map[string]float64{
+			"count": float64(s.Count()),
+			"min":   s.Min(),
+			"max":   s.Max(),
+			"avg":   s.Avg(),
+			"med":   s.P(0.5),
+			"p(90)": s.P(0.90),
+			"p(95)": s.P(0.95),
+			"p(99)": s.P(0.99),
+		}
+	default:
+		return map[string]float64{}
+	}
+}
+
+func encodeJSON(f frame) ([]byte, error) {
+	return json.Marshal(f)
+}
+
+// encodeProtobuf encodes the frame as the Frame message in stream.proto.
+func encodeProtobuf(f frame) ([]byte, error) {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, f.Seq)
+	b = protowire.AppendTag(b, 2, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(f.Time.UnixNano())) //nolint:gosec
+	b = protowire.AppendTag(b, 3, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, math.Float64bits(f.Interval))
+	for _, s := range f.Series {
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendBytes(b, encodeSeries(s))
+	}
+	return b, nil
+}
+
+func encodeSeries(s series) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, s.Metric)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, s.Type)
+
+	// map fields are repeated key/value entry messages, in a stable order
+	for _, k := range sortedKeys(s.Tags) {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, k)
+		entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+		entry = protowire.AppendString(entry, s.Tags[k])
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+	for _, k := range sortedKeys(s.Values) {
+		var entry []byte
+		entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+		entry = protowire.AppendString(entry, k)
+		entry = protowire.AppendTag(entry, 2, protowire.Fixed64Type)
+		entry = protowire.AppendFixed64(entry, math.Float64bits(s.Values[k]))
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+	return b
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
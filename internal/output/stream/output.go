@@ -0,0 +1,214 @@
+// Package stream implements an output that pushes batches of pre-aggregated
+// metrics to a WebSocket or HTTP collector, e.g. for custom live dashboards.
+package stream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/output"
+)
+
+const (
+	minRetryDelay = 100 * time.Millisecond
+	maxRetryDelay = 10 * time.Second
+)
+
+// Output aggregates the samples of every push interval into a frame and
+// sends it to the collector. Frames are buffered while the collector can't be
+// reached, dropping the oldest ones once the buffer is full.
+type Output struct {
+	output.SampleBuffer
+
+	config          Config
+	logger          logrus.FieldLogger
+	periodicFlusher *output.PeriodicFlusher
+	transport       transport
+	encode          func(frame) ([]byte, error)
+
+	aggregator *aggregator
+	seq        uint64
+	lastFlush  time.Time
+
+	mu      sync.Mutex
+	queue   []queuedFrame
+	dropped int
+	notify  chan struct{}
+
+	ctx    context.Context //nolint:containedctx
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type queuedFrame struct {
+	seq  uint64
+	data []byte
+}
+
+// New returns a new stream output.
+func New(params output.Params) (output.Output, error) {
+	config, err := GetConsolidatedConfig(params.JSONConfig, params.Environment, params.ConfigArgument)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &Output{
+		config:     config,
+		logger:     params.Logger.WithFields(logrus.Fields{"output": "stream", "url": config.URL.String}),
+		transport:  newTransport(config),
+		encode:     encodeJSON,
+		aggregator: newAggregator(),
+		notify:     make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	if config.Format.String == formatProtobuf {
+		o.encode = encodeProtobuf
+	}
+	return o, nil
+}
+
+// Description returns a human-readable description of the output.
+func (o *Output) Description() string {
+	return fmt.Sprintf("stream (%s, %s)", o.config.URL.String, o.config.Format.String)
+}
+
+// Start starts the goroutines that aggregate the samples and send the frames.
+func (o *Output) Start() error {
+	o.ctx, o.cancel = context.WithCancel(context.Background())
+	o.lastFlush = time.Now()
+	go o.sendFrames()
+
+	pf, err := output.NewPeriodicFlusher(o.config.PushInterval.TimeDuration(), o.flush)
+	if err != nil {
+		o.cancel()
+		return err
+	}
+	o.periodicFlusher = pf
+	return nil
+}
+
+// Stop flushes the last frame and waits for the buffered frames to be sent,
+// for at most the configured timeout.
+func (o *Output) Stop() error {
+	o.periodicFlusher.Stop()
+
+	o.mu.Lock()
+	close(o.notify)
+	o.mu.Unlock()
+
+	select {
+	case <-o.done:
+	case <-time.After(o.config.Timeout.TimeDuration()):
+		o.cancel()
+		<-o.done
+	}
+	o.cancel()
+
+	o.mu.Lock()
+	if len(o.queue) > 0 {
+		o.logger.Warnf("%d frames couldn't be sent before the output stopped", len(o.queue))
+	}
+	o.mu.Unlock()
+	return o.transport.close()
+}
+
+func (o *Output) flush() {
+	for _, sc := range o.GetBufferedSamples() {
+		for _, s := range sc.GetSamples() {
+			o.aggregator.add(s)
+		}
+	}
+
+	now := time.Now()
+	interval := now.Sub(o.lastFlush)
+	o.lastFlush = now
+
+	o.seq++
+	f := frame{Seq: o.seq, Time: now, Interval: interval.Seconds(), Series: o.aggregator.series(interval)}
+	data, err := o.encode(f)
+	if err != nil {
+		o.logger.WithError(err).Error("Couldn't encode a frame")
+		return
+	}
+	o.enqueue(queuedFrame{seq: f.Seq, data: data})
+}
+
+func (o *Output) enqueue(f queuedFrame) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.queue = append(o.queue, f)
+	if overflow := len(o.queue) - int(o.config.BufferSize.Int64); overflow > 0 {
+		o.queue = o.queue[overflow:]
+		if o.dropped == 0 {
+			o.logger.Warn("The stream buffer is full, the oldest frames are being dropped")
+		}
+		o.dropped += overflow
+	}
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the oldest buffered frame, if there is one.
+func (o *Output) next() (queuedFrame, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.queue) == 0 {
+		return queuedFrame{}, false
+	}
+	return o.queue[0], true
+}
+
+// sent removes the frame from the buffer, unless it was already dropped
+// because the buffer overflowed while it was being sent.
+func (o *Output) sent(seq uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.queue) > 0 && o.queue[0].seq == seq {
+		o.queue = o.queue[1:]
+	}
+}
+
+// sendFrames sends the buffered frames in order, retrying with an exponential
+// backoff when the collector can't be reached, until the output is stopped
+// and the buffer is empty.
+func (o *Output) sendFrames() {
+	defer close(o.done)
+
+	delay := minRetryDelay
+	for {
+		f, ok := o.next()
+		if !ok {
+			select {
+			case _, open := <-o.notify:
+				if !open {
+					if _, ok := o.next(); !ok {
+						return
+					}
+				}
+				continue
+			case <-o.ctx.Done():
+				return
+			}
+		}
+
+		if err := o.transport.send(o.ctx, f.data); err != nil {
+			o.logger.WithError(err).Debug("Couldn't send a frame, retrying")
+			select {
+			case <-time.After(delay):
+			case <-o.ctx.Done():
+				return
+			}
+			delay = min(2*delay, maxRetryDelay)
+			continue
+		}
+		delay = minRetryDelay
+		o.sent(f.seq)
+	}
+}
@@ -0,0 +1,257 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"go.k6.io/k6/internal/lib/testutils"
+	"go.k6.io/k6/metrics"
+	"go.k6.io/k6/output"
+)
+
+func testSamples(t *testing.T) []metrics.SampleContainer {
+	t.Helper()
+
+	registry := metrics.NewRegistry()
+	reqs := registry.MustNewMetric("http_reqs", metrics.Counter)
+	duration := registry.MustNewMetric("http_req_duration", metrics.Trend)
+	tags := registry.RootTagSet().WithTagsFromMap(map[string]string{"scenario": "default"})
+	now := time.Now()
+
+	samples := metrics.Samples{}
+	for _, v := range []float64{100, 200, 300} {
+		samples = append(samples,
+			metrics.Sample{TimeSeries: metrics.TimeSeries{Metric: reqs, Tags: tags}, Time: now, Value: 1},
+			metrics.Sample{TimeSeries: metrics.TimeSeries{Metric: duration, Tags: tags}, Time: now, Value: v},
+		)
+	}
+	return []metrics.SampleContainer{samples}
+}
+
+func newTestOutput(t *testing.T, arg string, env map[string]string) *Output {
+	t.Helper()
+
+	out, err := New(output.Params{
+		Logger:         testutils.NewLogger(t),
+		ConfigArgument: arg,
+		Environment:    env,
+	})
+	require.NoError(t, err)
+	o, ok := out.(*Output)
+	require.True(t, ok)
+	return o
+}
+
+func assertFrame(t *testing.T, f frame) {
+	t.Helper()
+
+	assert.Equal(t, uint64(1), f.Seq)
+	require.Len(t, f.Series, 2)
+
+	assert.Equal(t, "http_req_duration", f.Series[0].Metric)
+	assert.Equal(t, "trend", f.Series[0].Type)
+	assert.Equal(t, map[string]string{"scenario": "default"}, f.Series[0].Tags)
+	assert.Equal(t, 3.0, f.Series[0].Values["count"])
+	assert.Equal(t, 100.0, f.Series[0].Values["min"])
+	assert.Equal(t, 300.0, f.Series[0].Values["max"])
+	assert.Equal(t, 200.0, f.Series[0].Values["med"])
+
+	assert.Equal(t, "http_reqs", f.Series[1].Metric)
+	assert.Equal(t, "counter", f.Series[1].Type)
+	assert.Equal(t, 3.0, f.Series[1].Values["count"])
+	assert.Contains(t, f.Series[1].Values, "rate")
+}
+
+func TestOutputHTTP(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		attempts int
+		frames   []frame
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		// the collector is unavailable at first, the frame should be retried
+		if attempts == 1 || r.Header.Get("Authorization") != "Bearer token" ||
+			r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var f frame
+		if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		frames = append(frames, f)
+	}))
+	t.Cleanup(srv.Close)
+
+	o := newTestOutput(t, srv.URL, map[string]string{
+		"K6_STREAM_PUSH_INTERVAL": "1h",
+		"K6_STREAM_HEADERS":       "Authorization:Bearer token",
+	})
+	require.NoError(t, o.Start())
+	o.AddMetricSamples(testSamples(t))
+	require.NoError(t, o.Stop())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, attempts)
+	require.Len(t, frames, 1)
+	assertFrame(t, frames[0])
+}
+
+func TestOutputWebSocketProtobuf(t *testing.T) {
+	t.Parallel()
+
+	messages := make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		for {
+			mt, data, err := conn.ReadMessage()
+			if err != nil {
+				close(messages)
+				return
+			}
+			if mt == websocket.BinaryMessage {
+				messages <- data
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	o := newTestOutput(t, "ws"+srv.URL[len("http"):], map[string]string{
+		"K6_STREAM_PUSH_INTERVAL": "1h",
+		"K6_STREAM_FORMAT":        "protobuf",
+	})
+	require.NoError(t, o.Start())
+	o.AddMetricSamples(testSamples(t))
+	require.NoError(t, o.Stop())
+
+	data, ok := <-messages
+	require.True(t, ok)
+	assertFrame(t, decodeProtobuf(t, data))
+
+	_, ok = <-messages
+	assert.False(t, ok, "the connection should be closed after the last frame")
+}
+
+type failingTransport struct{}
+
+func (failingTransport) send(_ context.Context, _ []byte) error { return errors.New("unreachable") }
+func (failingTransport) close() error                           { return nil }
+
+func TestOutputBufferDropsOldestFrames(t *testing.T) {
+	t.Parallel()
+
+	o := newTestOutput(t, "http://localhost:9000", map[string]string{"K6_STREAM_BUFFER_SIZE": "2"})
+	o.transport = failingTransport{}
+	for range 3 {
+		o.flush()
+	}
+
+	require.Len(t, o.queue, 2)
+	assert.Equal(t, uint64(2), o.queue[0].seq)
+	assert.Equal(t, uint64(3), o.queue[1].seq)
+	assert.Equal(t, 1, o.dropped)
+
+	// a frame that was dropped while it was being sent doesn't remove the next one
+	o.sent(1)
+	assert.Len(t, o.queue, 2)
+	o.sent(2)
+	assert.Len(t, o.queue, 1)
+}
+
+// decodeProtobuf decodes a Frame message of stream.proto.
+func decodeProtobuf(t *testing.T, b []byte) frame {
+	t.Helper()
+
+	var f frame
+	forEachField(t, b, func(num protowire.Number, v []byte, n uint64) {
+		switch num {
+		case 1:
+			f.Seq = n
+		case 2:
+			f.Time = time.Unix(0, int64(n)) //nolint:gosec
+		case 3:
+			f.Interval = math.Float64frombits(n)
+		case 4:
+			s := series{Tags: map[string]string{}, Values: map[string]float64{}}
+			forEachField(t, v, func(num protowire.Number, v []byte, _ uint64) {
+				var key string
+				switch num {
+				case 1:
+					s.Metric = string(v)
+				case 2:
+					s.Type = string(v)
+				case 3:
+					forEachField(t, v, func(num protowire.Number, v []byte, _ uint64) {
+						if num == 1 {
+							key = string(v)
+						} else {
+							s.Tags[key] = string(v)
+						}
+					})
+				case 4:
+					forEachField(t, v, func(num protowire.Number, v []byte, n uint64) {
+						if num == 1 {
+							key = string(v)
+						} else {
+							s.Values[key] = math.Float64frombits(n)
+						}
+					})
+				}
+			})
+			f.Series = append(f.Series, s)
+		}
+	})
+	return f
+}
+
+func forEachField(t *testing.T, b []byte, fn func(num protowire.Number, v []byte, n uint64)) {
+	t.Helper()
+
+	for len(b) > 0 {
+		num, typ, l := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, l, 0)
+		b = b[l:]
+		switch typ {
+		case protowire.VarintType:
+			n, l := protowire.ConsumeVarint(b)
+			require.GreaterOrEqual(t, l, 0)
+			fn(num, nil, n)
+			b = b[l:]
+		case protowire.Fixed64Type:
+			n, l := protowire.ConsumeFixed64(b)
+			require.GreaterOrEqual(t, l, 0)
+			fn(num, nil, n)
+			b = b[l:]
+		case protowire.BytesType:
+			v, l := protowire.ConsumeBytes(b)
+			require.GreaterOrEqual(t, l, 0)
+			fn(num, v, 0)
+			b = b[l:]
+		default:
+			t.Fatalf("unexpected wire type %d", typ)
+		}
+	}
+}
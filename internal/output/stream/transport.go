@@ -0,0 +1,145 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// transport sends the encoded frames to the collector.
+type transport interface {
+	send(ctx context.Context, data []byte) error
+	close() error
+}
+
+func newTransport(config Config) transport {
+	header := make(http.Header, len(config.Headers))
+	for k, v := range config.Headers {
+		header.Set(k, v)
+	}
+	contentType, messageType := "application/json", websocket.TextMessage
+	if config.Format.String == formatProtobuf {
+		contentType, messageType = "application/x-protobuf", websocket.BinaryMessage
+	}
+
+	if strings.HasPrefix(config.URL.String, "ws") {
+		return &wsTransport{
+			url:         config.URL.String,
+			header:      header,
+			messageType: messageType,
+			timeout:     config.Timeout.TimeDuration(),
+		}
+	}
+	header.Set("Content-Type", contentType)
+	return &httpTransport{
+		url:    config.URL.String,
+		header: header,
+		client: &http.Client{Timeout: config.Timeout.TimeDuration()},
+	}
+}
+
+// wsTransport sends every frame as a message over a WebSocket connection,
+// which is (re)established on demand.
+type wsTransport struct {
+	url         string
+	header      http.Header
+	messageType int
+	timeout     time.Duration
+
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (t *wsTransport) send(ctx context.Context, data []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		dialer := websocket.Dialer{HandshakeTimeout: t.timeout, Proxy: http.ProxyFromEnvironment}
+		conn, resp, err := dialer.DialContext(ctx, t.url, t.header)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't connect to %s: %w", t.url, err)
+		}
+		// The collector isn't expected to send anything, but the connection
+		// has to be read for the control messages like ping and close to be handled.
+		go func() {
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+		t.conn = conn
+	}
+
+	if err := t.conn.SetWriteDeadline(time.Now().Add(t.timeout)); err != nil {
+		return t.reset(err)
+	}
+	if err := t.conn.WriteMessage(t.messageType, data); err != nil {
+		return t.reset(err)
+	}
+	return nil
+}
+
+// reset drops the broken connection, so the next send reconnects.
+func (t *wsTransport) reset(err error) error {
+	_ = t.conn.Close()
+	t.conn = nil
+	return fmt.Errorf("couldn't send a frame to %s: %w", t.url, err)
+}
+
+func (t *wsTransport) close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		return nil
+	}
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "test finished")
+	_ = t.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(t.timeout))
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
+
+// httpTransport sends every frame as the body of a POST request.
+type httpTransport struct {
+	url    string
+	header http.Header
+	client *http.Client
+}
+
+func (t *httpTransport) send(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header = t.header.Clone()
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("couldn't send a frame to %s: %w", t.url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("couldn't send a frame to %s: status code %d", t.url, resp.StatusCode)
+	}
+	return nil
+}
+
+func (t *httpTransport) close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
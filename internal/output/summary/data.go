@@ -17,10 +17,12 @@ type dataModel struct {
 	scenarios map[string]*aggregatedGroupData
 }
 
-func newDataModel() dataModel {
+// newDataModel creates a new dataModel, whose group and scenario trend sinks
+// keep their exact values only for the given window, if it's positive.
+func newDataModel(trendExactWindow time.Duration) dataModel {
 	return dataModel{
 		thresholds:          make(map[string]metricThresholds),
-		aggregatedGroupData: newAggregatedGroupData(),
+		aggregatedGroupData: newAggregatedGroupData(trendExactWindow),
 		scenarios:           make(map[string]*aggregatedGroupData),
 	}
 }
@@ -29,7 +31,7 @@ func (d *dataModel) groupDataFor(scenario string) *aggregatedGroupData {
 	if groupData, exists := d.scenarios[scenario]; exists {
 		return groupData
 	}
-	d.scenarios[scenario] = newAggregatedGroupData()
+	d.scenarios[scenario] = newAggregatedGroupData(d.trendExactWindow)
 	return d.scenarios[scenario]
 }
 
@@ -58,14 +60,16 @@ type aggregatedGroupData struct {
 	aggregatedMetrics aggregatedMetricData
 	groupsData        map[string]*aggregatedGroupData
 	groupsOrder       []string
+	trendExactWindow  time.Duration
 }
 
-func newAggregatedGroupData() *aggregatedGroupData {
+func newAggregatedGroupData(trendExactWindow time.Duration) *aggregatedGroupData {
 	return &aggregatedGroupData{
 		checks:            newAggregatedChecksData(),
 		aggregatedMetrics: make(map[string]aggregatedMetric),
 		groupsData:        make(map[string]*aggregatedGroupData),
 		groupsOrder:       make([]string, 0),
+		trendExactWindow:  trendExactWindow,
 	}
 }
 
@@ -73,7 +77,7 @@ func (a *aggregatedGroupData) groupDataFor(group string) *aggregatedGroupData {
 	if groupData, exists := a.groupsData[group]; exists {
 		return groupData
 	}
-	newGroupData := newAggregatedGroupData()
+	newGroupData := newAggregatedGroupData(a.trendExactWindow)
 	a.groupsData[group] = newGroupData
 	a.groupsOrder = append(a.groupsOrder, group)
 	return a.groupsData[group]
@@ -83,7 +87,7 @@ func (a *aggregatedGroupData) groupDataFor(group string) *aggregatedGroupData {
 // sample, which differs from the original metric sink, while relayAggregatedMetricFrom stores the metric and the
 // metric sink from the sample's metric.
 func (a *aggregatedGroupData) addSample(sample metrics.Sample) {
-	a.aggregatedMetrics.addSample(sample)
+	a.aggregatedMetrics.addSample(sample, a.trendExactWindow)
 
 	checkName, hasCheckTag := sample.Tags.Get(metrics.TagCheck.String())
 	if hasCheckTag && sample.Metric.Name == metrics.ChecksName {
@@ -121,9 +125,9 @@ func summaryMetricInfoFrom(m *metrics.Metric) summary.MetricInfo {
 
 // addSample stores the value of the sample in a separate internal sink completely detached from the underlying metrics.
 // This allows to keep an aggregated view of the values specific to a group or scenario.
-func (a aggregatedMetricData) addSample(sample metrics.Sample) {
+func (a aggregatedMetricData) addSample(sample metrics.Sample, trendExactWindow time.Duration) {
 	if _, exists := a[sample.Metric.Name]; !exists {
-		a[sample.Metric.Name] = newAggregatedMetric(sample.Metric)
+		a[sample.Metric.Name] = newAggregatedMetric(sample.Metric, trendExactWindow)
 	}
 
 	a[sample.Metric.Name].Sink.Add(sample)
@@ -134,10 +138,14 @@ type aggregatedMetric struct {
 	Sink metrics.Sink
 }
 
-func newAggregatedMetric(m *metrics.Metric) aggregatedMetric {
+func newAggregatedMetric(m *metrics.Metric, trendExactWindow time.Duration) aggregatedMetric {
+	sink := metrics.NewSink(m.Type)
+	if trendSink, ok := sink.(*metrics.TrendSink); ok && trendExactWindow > 0 {
+		trendSink.SetExactWindow(trendExactWindow)
+	}
 	return aggregatedMetric{
 		MetricInfo: summaryMetricInfoFrom(m),
-		Sink:       metrics.NewSink(m.Type),
+		Sink:       sink,
 	}
 }
 
@@ -326,6 +334,7 @@ func isHTTPMetric(metricName string) bool {
 		metrics.HTTPReqSendingName,
 		metrics.HTTPReqWaitingName,
 		metrics.HTTPReqReceivingName,
+		metrics.HTTPReqTLSCertDaysToExpiryName,
 	)
 }
 
@@ -334,7 +343,13 @@ func isExecutionMetric(metricName string) bool {
 		metrics.VUsMaxName,
 		metrics.IterationsName,
 		metrics.IterationDurationName,
+		metrics.IterationDurationScriptName,
+		metrics.IterationDurationSleepName,
+		metrics.IterationDurationNetworkName,
 		metrics.DroppedIterationsName,
+		metrics.ArrivalRateVUsAllocatedName,
+		metrics.ArrivalRateVUsBusyName,
+		metrics.ArrivalRateVUsPendingName,
 	)
 }
 
@@ -27,6 +27,10 @@ type Output struct {
 
 	dataModel   dataModel
 	summaryMode summary.Mode
+
+	startTime        time.Time
+	timeSeries       []*timeSeries
+	trendExactWindow time.Duration
 }
 
 // New returns a new summary output.
@@ -36,12 +40,23 @@ func New(params output.Params) (*Output, error) {
 		return nil, err
 	}
 
+	timeSeries, err := newTimeSeries(
+		params.ScriptOptions.SummaryTimeSeries,
+		params.ScriptOptions.SummaryTimeSeriesInterval.TimeDuration(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	trendExactWindow := params.ScriptOptions.TrendExactWindow.TimeDuration()
 	return &Output{
 		logger: params.Logger.WithFields(logrus.Fields{
 			"output": "summary",
 		}),
-		dataModel:   newDataModel(),
-		summaryMode: sm,
+		dataModel:        newDataModel(trendExactWindow),
+		summaryMode:      sm,
+		timeSeries:       timeSeries,
+		trendExactWindow: trendExactWindow,
 	}, nil
 }
 
@@ -62,6 +77,7 @@ func (o *Output) Start() error {
 	}
 	o.logger.Debug("Started!")
 	o.periodicFlusher = pf
+	o.startTime = time.Now()
 	return nil
 }
 
@@ -82,9 +98,20 @@ func (o *Output) flushMetrics() {
 }
 
 func (o *Output) flushSample(sample metrics.Sample) {
+	if sample.IsWarmup() {
+		return // warm-up samples are excluded from the end-of-test summary
+	}
+
 	// First, the sample data is stored into the metrics stored at the k6 metrics registry level.
 	o.storeSample(sample)
 
+	// The samples of the metrics selected with summaryTimeSeries are also stored by time bucket.
+	for _, ts := range o.timeSeries {
+		if ts.matches(sample) {
+			ts.addSample(sample, o.startTime, o.trendExactWindow)
+		}
+	}
+
 	skipGroupSamples := o.summaryMode == summary.ModeCompact || o.summaryMode == summary.ModeLegacy
 	if skipGroupSamples {
 		return
@@ -156,6 +183,14 @@ func (o *Output) Summary(
 		s.Scenarios[scenarioName] = scenarioSummaryGroup
 	}
 
+	// Populate the time series of the selected metrics, in the order they were selected.
+	for _, ts := range o.timeSeries {
+		if m, observed := observedMetrics[ts.metric]; observed && ts.buckets == nil {
+			ts.info = summaryMetricInfoFrom(m)
+		}
+		s.TimeSeries = append(s.TimeSeries, ts.summary(testRunDuration, summaryTrendStats))
+	}
+
 	return s
 }
 
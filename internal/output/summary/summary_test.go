@@ -12,6 +12,7 @@ import (
 	"go.k6.io/k6/internal/lib/summary"
 	"go.k6.io/k6/internal/lib/testutils"
 	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 	"go.k6.io/k6/output"
 )
@@ -343,3 +344,88 @@ func TestOutput_AddMetricSamples(t *testing.T) {
 		assert.Equal(t, []string{"something", "auth"}, o.dataModel.groupsOrder)
 	})
 }
+
+func TestOutput_TimeSeries(t *testing.T) {
+	t.Parallel()
+
+	reg := metrics.NewRegistry()
+	httpReqs, err := reg.NewMetric("http_reqs", metrics.Counter)
+	require.NoError(t, err)
+	httpReqDuration, err := reg.NewMetric("http_req_duration", metrics.Trend, metrics.Time)
+	require.NoError(t, err)
+	vus, err := reg.NewMetric("vus", metrics.Gauge)
+	require.NoError(t, err)
+
+	o, err := New(output.Params{
+		ScriptOptions: lib.Options{
+			SummaryTimeSeries:         []string{"http_req_duration", "http_reqs{status:500}", "vus"},
+			SummaryTimeSeriesInterval: types.NullDurationFrom(10 * time.Second),
+		},
+		Logger: testutils.NewLogger(t),
+	})
+	require.NoError(t, err)
+	require.NoError(t, o.Start())
+	require.NoError(t, o.Stop())
+
+	sample := func(m *metrics.Metric, offset time.Duration, status string, value float64) metrics.Sample {
+		return metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: m, Tags: reg.RootTagSet().With("status", status)},
+			Time:       o.startTime.Add(offset),
+			Value:      value,
+		}
+	}
+	for _, s := range []metrics.Sample{
+		sample(httpReqDuration, time.Second, "200", 100),
+		sample(httpReqDuration, 9*time.Second, "500", 300),
+		sample(httpReqDuration, 25*time.Second, "200", 50),
+		sample(httpReqs, 2*time.Second, "500", 1),
+		sample(httpReqs, 3*time.Second, "200", 1),
+		sample(httpReqs, 21*time.Second, "500", 1),
+		sample(httpReqs, 22*time.Second, "500", 1),
+	} {
+		o.flushSample(s)
+	}
+
+	s := o.Summary(25*time.Second, map[string]*metrics.Metric{"vus": vus}, lib.Options{
+		SummaryTrendStats: []string{"avg", "max"},
+	})
+	require.Len(t, s.TimeSeries, 3)
+
+	assert.Equal(t, summary.TimeSeries{
+		MetricInfo: summary.MetricInfo{Name: "http_req_duration", Type: "trend", Contains: "time"},
+		Interval:   10 * time.Second,
+		Buckets: []map[string]float64{
+			{"avg": 200, "max": 300},
+			nil,
+			{"avg": 50, "max": 50},
+		},
+	}, s.TimeSeries[0])
+
+	assert.Equal(t, summary.TimeSeries{
+		MetricInfo: summary.MetricInfo{Name: "http_reqs{status:500}", Type: "counter", Contains: "default"},
+		Interval:   10 * time.Second,
+		Buckets: []map[string]float64{
+			{"count": 1, "rate": 0.1},
+			nil,
+			{"count": 2, "rate": 0.4},
+		},
+	}, s.TimeSeries[1])
+
+	assert.Equal(t, summary.TimeSeries{
+		MetricInfo: summary.MetricInfo{Name: "vus", Type: "gauge", Contains: "default"},
+		Interval:   10 * time.Second,
+		Buckets:    []map[string]float64{nil, nil, nil},
+	}, s.TimeSeries[2])
+}
+
+func TestNewTimeSeriesInvalidName(t *testing.T) {
+	t.Parallel()
+
+	_, err := newTimeSeries([]string{"http_reqs{status:500"}, 0)
+	assert.ErrorIs(t, err, metrics.ErrMetricNameParsing)
+
+	series, err := newTimeSeries([]string{` http_reqs{ status: "500" } `}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, defaultTimeSeriesInterval, series[0].interval)
+	assert.Equal(t, map[string]string{"status": "500"}, series[0].tags)
+}
@@ -0,0 +1,115 @@
+package summary
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.k6.io/k6/internal/lib/summary"
+	"go.k6.io/k6/metrics"
+)
+
+// defaultTimeSeriesInterval is the length of the time buckets, if the
+// summaryTimeSeriesInterval option isn't set.
+const defaultTimeSeriesInterval = 10 * time.Second
+
+// timeSeries holds the values of a metric, or of a sub-metric, in consecutive
+// time buckets since the start of the test run.
+type timeSeries struct {
+	name     string
+	metric   string
+	tags     map[string]string
+	interval time.Duration
+
+	info    summary.MetricInfo
+	buckets []metrics.Sink
+}
+
+// newTimeSeries parses the metric name expressions of the summaryTimeSeries
+// option, like http_reqs{status:500}.
+func newTimeSeries(names []string, interval time.Duration) ([]*timeSeries, error) {
+	if interval <= 0 {
+		interval = defaultTimeSeriesInterval
+	}
+
+	series := make([]*timeSeries, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		metricName, tagKVs, err := metrics.ParseMetricName(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid summaryTimeSeries metric: %w", err)
+		}
+		tags := make(map[string]string, len(tagKVs))
+		for _, kv := range tagKVs {
+			k, v, _ := strings.Cut(kv, ":")
+			tags[strings.Trim(strings.TrimSpace(k), `"'`)] = strings.Trim(strings.TrimSpace(v), `"'`)
+		}
+		series = append(series, &timeSeries{
+			name:     name,
+			metric:   metricName,
+			tags:     tags,
+			interval: interval,
+		})
+	}
+	return series, nil
+}
+
+// matches returns whether the sample belongs to the metric, or sub-metric, of
+// the series.
+func (ts *timeSeries) matches(sample metrics.Sample) bool {
+	if sample.Metric.Name != ts.metric {
+		return false
+	}
+	for k, v := range ts.tags {
+		if tv, ok := sample.Tags.Get(k); !ok || tv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// addSample adds the sample to the bucket of its time, the samples from
+// before the start of the test run are added to the first one.
+func (ts *timeSeries) addSample(sample metrics.Sample, start time.Time, trendExactWindow time.Duration) {
+	if ts.buckets == nil {
+		ts.info = summaryMetricInfoFrom(sample.Metric)
+	}
+
+	i := 0
+	if elapsed := sample.Time.Sub(start); elapsed > 0 {
+		i = int(elapsed / ts.interval)
+	}
+	for len(ts.buckets) <= i {
+		ts.buckets = append(ts.buckets, nil)
+	}
+	if ts.buckets[i] == nil {
+		ts.buckets[i] = newAggregatedMetric(sample.Metric, trendExactWindow).Sink
+	}
+	ts.buckets[i].Add(sample)
+}
+
+// summary returns the values of the buckets, up to the end of the test run.
+// The rates of the counters are calculated for the time of each bucket, which
+// for the last one might be shorter than the interval.
+func (ts *timeSeries) summary(testRunDuration time.Duration, summaryTrendStats []string) summary.TimeSeries {
+	getMetricValues := metricValueGetter(summaryTrendStats)
+
+	n := max(int((testRunDuration+ts.interval-1)/ts.interval), len(ts.buckets))
+	result := summary.TimeSeries{
+		MetricInfo: ts.info,
+		Interval:   ts.interval,
+		Buckets:    make([]map[string]float64, n),
+	}
+	result.Name = ts.name
+	for i, sink := range ts.buckets {
+		if sink == nil {
+			continue
+		}
+		duration := ts.interval
+		if remaining := testRunDuration - time.Duration(i)*ts.interval; remaining > 0 && remaining < duration {
+			duration = remaining
+		}
+		result.Buckets[i] = getMetricValues(sink, duration)
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package termdashboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPeriod  = time.Second
+	defaultTop     = 5
+	defaultHistory = 60
+)
+
+// Config is the config for the terminal dashboard output.
+type Config struct {
+	// Period is how often the dashboard is refreshed.
+	Period time.Duration
+	// Top is how many of the most frequent errors are shown.
+	Top int
+	// History is how many refresh periods the latency sparkline spans.
+	History int
+}
+
+// NewConfig creates a new Config instance with the default values.
+func NewConfig() Config {
+	return Config{
+		Period:  defaultPeriod,
+		Top:     defaultTop,
+		History: defaultHistory,
+	}
+}
+
+// ParseArg parses the output argument, a comma separated list of key=value
+// pairs, e.g. --out dashboard=period=2s,top=10.
+func ParseArg(arg string) (Config, error) {
+	c := NewConfig()
+	if arg == "" {
+		return c, nil
+	}
+
+	for _, pair := range strings.Split(arg, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || v == "" {
+			return c, fmt.Errorf("couldn't parse %q as argument for dashboard output", arg)
+		}
+		switch k {
+		case "period":
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return c, fmt.Errorf("invalid period: %w", err)
+			}
+			if d <= 0 {
+				return c, fmt.Errorf("period should be positive but was %s", d)
+			}
+			c.Period = d
+		case "top", "history":
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return c, fmt.Errorf("%s should be a positive integer but was %q", k, v)
+			}
+			if k == "top" {
+				c.Top = n
+			} else {
+				c.History = n
+			}
+		default:
+			return c, fmt.Errorf("unknown key %q as argument for dashboard output", k)
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,3 @@
+// Package termdashboard implements an output that renders a live dashboard
+// in the terminal while the test is running.
+package termdashboard
@@ -0,0 +1,125 @@
+package termdashboard
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/internal/ui/console"
+	"go.k6.io/k6/metrics"
+	"go.k6.io/k6/output"
+)
+
+// OutputName is the name the output is registered with.
+const OutputName = "dashboard"
+
+// Output renders a dashboard with the live test results to stdout. In a
+// terminal the dashboard is redrawn in place on every period, otherwise only
+// the final dashboard is printed when the test finishes.
+type Output struct {
+	output.SampleBuffer
+
+	config          Config
+	logger          logrus.FieldLogger
+	out             io.Writer
+	tty             bool
+	periodicFlusher *output.PeriodicFlusher
+
+	stats     *stats
+	startTime time.Time
+	now       func() time.Time
+	lastLines int
+}
+
+var _ output.WithThresholds = &Output{}
+
+// New returns a new terminal dashboard output.
+func New(params output.Params) (output.Output, error) {
+	config, err := ParseArg(params.ConfigArgument)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &Output{
+		config: config,
+		logger: params.Logger.WithField("output", OutputName),
+		out:    params.StdOut,
+		stats:  newStats(config.History),
+		now:    time.Now,
+	}
+	if w, ok := params.StdOut.(*console.Writer); ok {
+		o.tty = w.IsTTY
+	}
+	return o, nil
+}
+
+// Description returns a human-readable description of the output.
+func (o *Output) Description() string {
+	return fmt.Sprintf("%s (refresh %s)", OutputName, o.config.Period)
+}
+
+// SetThresholds receives the thresholds before the output is Start()-ed.
+func (o *Output) SetThresholds(thresholds map[string]metrics.Thresholds) {
+	if err := o.stats.setThresholds(thresholds); err != nil {
+		o.logger.WithError(err).Warn("Thresholds won't be shown on the dashboard")
+		o.stats.thresholds = nil
+	}
+}
+
+// Start starts the goroutine that periodically aggregates the samples and
+// redraws the dashboard.
+func (o *Output) Start() error {
+	o.startTime = o.now()
+	pf, err := output.NewPeriodicFlusher(o.config.Period, o.flush)
+	if err != nil {
+		return err
+	}
+	o.periodicFlusher = pf
+	return nil
+}
+
+// Stop flushes the remaining samples and draws the final dashboard.
+func (o *Output) Stop() error {
+	o.periodicFlusher.Stop()
+	if o.tty {
+		return nil
+	}
+	return o.draw()
+}
+
+func (o *Output) flush() {
+	for _, sc := range o.GetBufferedSamples() {
+		for _, s := range sc.GetSamples() {
+			o.stats.add(s)
+		}
+	}
+	o.stats.endPeriod()
+
+	if !o.tty {
+		return
+	}
+	if err := o.draw(); err != nil {
+		o.logger.WithError(err).Error("Couldn't draw the dashboard")
+	}
+}
+
+func (o *Output) draw() error {
+	frame, err := o.stats.render(o.now().Sub(o.startTime), o.config.Top)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if o.tty && o.lastLines > 0 {
+		// move the cursor to the start of the previous frame and clear everything below it
+		fmt.Fprintf(&b, "\x1b[%dA\x1b[J", o.lastLines)
+	}
+	b.WriteString(frame)
+	o.lastLines = strings.Count(frame, "\n")
+
+	_, err = io.WriteString(o.out, b.String())
+	return err
+}
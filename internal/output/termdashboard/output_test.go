@@ -0,0 +1,180 @@
+package termdashboard
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/lib/testutils"
+	"go.k6.io/k6/metrics"
+	"go.k6.io/k6/output"
+)
+
+func TestParseArg(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		arg           string
+		expected      Config
+		expectedError string
+	}{
+		"empty": {
+			expected: NewConfig(),
+		},
+		"all": {
+			arg:      "period=2s,top=10,history=30",
+			expected: Config{Period: 2 * time.Second, Top: 10, History: 30},
+		},
+		"bad period": {
+			arg:           "period=0s",
+			expectedError: "period should be positive",
+		},
+		"bad top": {
+			arg:           "top=-1",
+			expectedError: `top should be a positive integer but was "-1"`,
+		},
+		"unknown key": {
+			arg:           "refresh=1s",
+			expectedError: `unknown key "refresh"`,
+		},
+		"not key value": {
+			arg:           "period",
+			expectedError: "couldn't parse",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c, err := ParseArg(tc.arg)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, c)
+		})
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "▁▅ █", sparkline([]float64{0, 50, math.NaN(), 100}))
+	assert.Equal(t, "▁▁", sparkline([]float64{0, 0}))
+	assert.Equal(t, "", sparkline(nil))
+}
+
+type testSamples struct {
+	registry *metrics.Registry
+}
+
+func (ts testSamples) sample(name string, typ metrics.MetricType, value float64, tags map[string]string) metrics.Sample {
+	return metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: ts.registry.MustNewMetric(name, typ),
+			Tags:   ts.registry.RootTagSet().WithTagsFromMap(tags),
+		},
+		Time:  time.Now(),
+		Value: value,
+	}
+}
+
+func (ts testSamples) request(scenario string, duration float64, status string) metrics.Samples {
+	tags := map[string]string{
+		"scenario": scenario, "method": "GET", "name": "https://test.k6.io/" + scenario, "status": status,
+	}
+	failed := 0.0
+	if status != "200" {
+		failed = 1
+	}
+	return metrics.Samples{
+		ts.sample(metrics.HTTPReqsName, metrics.Counter, 1, tags),
+		ts.sample(metrics.HTTPReqDurationName, metrics.Trend, duration, tags),
+		ts.sample(metrics.HTTPReqFailedName, metrics.Rate, failed, tags),
+	}
+}
+
+func TestOutput(t *testing.T) {
+	t.Parallel()
+
+	ts := testSamples{registry: metrics.NewRegistry()}
+	stdout := &bytes.Buffer{}
+	out, err := New(output.Params{
+		Logger:         testutils.NewLogger(t),
+		StdOut:         stdout,
+		ConfigArgument: "period=1h,top=1",
+	})
+	require.NoError(t, err)
+	o, ok := out.(*Output)
+	require.True(t, ok)
+
+	thresholds := map[string]metrics.Thresholds{
+		"http_req_duration":                  metrics.NewThresholds([]string{"p(95)<300"}),
+		"http_req_failed{scenario:checkout}": metrics.NewThresholds([]string{"rate<0.1"}),
+		"checks":                             metrics.NewThresholds([]string{"rate>0.9"}),
+	}
+	o.SetThresholds(thresholds)
+
+	now := time.Now()
+	o.now = func() time.Time { return now }
+	require.NoError(t, o.Start())
+
+	o.AddMetricSamples([]metrics.SampleContainer{
+		ts.sample(metrics.VUsName, metrics.Gauge, 5, nil),
+		ts.sample(metrics.VUsMaxName, metrics.Gauge, 10, nil),
+		ts.request("browse", 100, "200"),
+		ts.request("browse", 200, "200"),
+		ts.request("checkout", 400, "500"),
+		ts.request("checkout", 50, "200"),
+		ts.sample(metrics.IterationsName, metrics.Counter, 1, map[string]string{"scenario": "browse"}),
+		ts.sample(metrics.IterationsName, metrics.Counter, 1, map[string]string{"scenario": "checkout"}),
+	})
+	now = now.Add(2 * time.Second)
+	require.NoError(t, o.Stop())
+
+	// stdout isn't a terminal, so only the final dashboard is printed
+	assert.Equal(t, ""+
+		"  k6 dashboard   running 2s   vus 5/10   iterations 2 (1.0/s)\n"+
+		"\n"+
+		"  http_req_duration p(95)   now 400.00ms   peak 400.00ms\n"+
+		"  █\n"+
+		"  requests 4 (2.0/s)   failed 25.00%\n"+
+		"\n"+
+		"  SCENARIO   ITERATIONS   REQUESTS   FAILED   P(95)\n"+
+		"  browse     1            2          0.00%    195.00ms\n"+
+		"  checkout   1            2          50.00%   382.50ms\n"+
+		"\n"+
+		"  TOP ERRORS\n"+
+		"       1  GET https://test.k6.io/checkout status 500\n"+
+		"\n"+
+		"  THRESHOLDS\n"+
+		"  ? checks  rate>0.9\n"+
+		"  ✗ http_req_duration  p(95)<300\n"+
+		"  ✗ http_req_failed{scenario:checkout}  rate<0.1\n",
+		stdout.String())
+
+	// the thresholds the engine uses are left untouched
+	for _, th := range thresholds {
+		assert.False(t, th.Thresholds[0].LastFailed)
+	}
+}
+
+func TestOutputRedrawsInPlace(t *testing.T) {
+	t.Parallel()
+
+	stdout := &bytes.Buffer{}
+	o := &Output{config: NewConfig(), out: stdout, tty: true, stats: newStats(3), now: time.Now}
+
+	require.NoError(t, o.draw())
+	first := stdout.String()
+	assert.NotContains(t, first, "\x1b[")
+
+	stdout.Reset()
+	require.NoError(t, o.draw())
+	assert.Equal(t, "\x1b[5A\x1b[J"+first, stdout.String())
+}
@@ -0,0 +1,138 @@
+package termdashboard
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders the values as a unicode sparkline scaled to their maximum,
+// with a space for every NaN value.
+func sparkline(values []float64) string {
+	maxV := 0.0
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			maxV = math.Max(maxV, v)
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case maxV == 0:
+			b.WriteRune(sparks[0])
+		default:
+			b.WriteRune(sparks[int(math.Round(v/maxV*float64(len(sparks)-1)))])
+		}
+	}
+	return b.String()
+}
+
+func formatMillis(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+	if v >= 1000 {
+		return fmt.Sprintf("%.2fs", v/1000)
+	}
+	return fmt.Sprintf("%.2fms", v)
+}
+
+func formatRate(count float64, elapsed time.Duration) string {
+	if elapsed <= 0 {
+		return "0.0/s"
+	}
+	return fmt.Sprintf("%.1f/s", count/elapsed.Seconds())
+}
+
+func formatPercent(part, total float64) string {
+	if total == 0 {
+		return "0.00%"
+	}
+	return fmt.Sprintf("%.2f%%", part/total*100)
+}
+
+// render returns a frame of the dashboard, ending with a new line.
+//
+//nolint:funlen
+func (st *stats) render(elapsed time.Duration, top int) (string, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "  k6 dashboard   running %s   vus %.0f/%.0f   iterations %.0f (%s)\n\n",
+		elapsed.Truncate(time.Second), st.vus, st.vusMax, st.iterations, formatRate(st.iterations, elapsed))
+
+	current, peak := math.NaN(), math.NaN()
+	for _, v := range st.latency {
+		if math.IsNaN(v) {
+			continue
+		}
+		current = v
+		if math.IsNaN(peak) || v > peak {
+			peak = v
+		}
+	}
+	fmt.Fprintf(&b, "  http_req_duration p(95)   now %s   peak %s\n", formatMillis(current), formatMillis(peak))
+	fmt.Fprintf(&b, "  %s\n", sparkline(st.latency))
+	fmt.Fprintf(&b, "  requests %.0f (%s)   failed %s\n",
+		st.requests, formatRate(st.requests, elapsed), formatPercent(st.failedRequests, st.requests))
+
+	if len(st.scenarios) > 0 {
+		names := make([]string, 0, len(st.scenarios))
+		for name := range st.scenarios {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		b.WriteString("\n")
+		tw := tabwriter.NewWriter(&b, 0, 0, 3, ' ', 0)
+		fmt.Fprint(tw, "  SCENARIO\tITERATIONS\tREQUESTS\tFAILED\tP(95)\n")
+		for _, name := range names {
+			s := st.scenarios[name]
+			p95 := math.NaN()
+			if !s.duration.IsEmpty() {
+				p95 = s.duration.P(0.95)
+			}
+			fmt.Fprintf(tw, "  %s\t%.0f\t%.0f\t%s\t%s\n",
+				name, s.iterations, s.requests, formatPercent(s.failed, s.requests), formatMillis(p95))
+		}
+		if err := tw.Flush(); err != nil {
+			return "", err
+		}
+	}
+
+	if errs := st.topErrors(top); len(errs) > 0 {
+		b.WriteString("\n  TOP ERRORS\n")
+		for _, e := range errs {
+			fmt.Fprintf(&b, "  %6d  %s\n", e.count, e.key)
+		}
+	}
+
+	if len(st.thresholds) > 0 {
+		results, err := st.thresholdResults(elapsed)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("\n  THRESHOLDS\n")
+		for _, ts := range st.thresholds {
+			for _, r := range results[ts.name] {
+				mark := "?"
+				if r.passed != nil {
+					mark = "✓"
+					if !*r.passed {
+						mark = "✗"
+					}
+				}
+				fmt.Fprintf(&b, "  %s %s  %s\n", mark, ts.name, r.source)
+			}
+		}
+	}
+
+	return b.String(), nil
+}
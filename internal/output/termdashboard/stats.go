@@ -0,0 +1,243 @@
+package termdashboard
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.k6.io/k6/metrics"
+)
+
+type scenarioStats struct {
+	iterations float64
+	requests   float64
+	failed     float64
+	duration   *metrics.TrendSink
+}
+
+type errorCount struct {
+	key   string
+	count int
+}
+
+type thresholdStatus struct {
+	name       string
+	metric     string
+	tags       map[string]string
+	sink       metrics.Sink
+	thresholds metrics.Thresholds
+}
+
+// matches returns whether the sample belongs to the (sub)metric the thresholds are defined on.
+func (ts *thresholdStatus) matches(s metrics.Sample) bool {
+	if s.Metric.Name != ts.metric {
+		return false
+	}
+	for k, v := range ts.tags {
+		if tv, ok := s.Tags.Get(k); !ok || tv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// stats holds everything the dashboard shows, aggregated from the samples
+// since the start of the test.
+type stats struct {
+	historySize int
+
+	vus, vusMax    float64
+	iterations     float64
+	requests       float64
+	failedRequests float64
+
+	// latency has the p(95) of http_req_duration for each of the last
+	// historySize periods, NaN for periods without any requests.
+	latency   []float64
+	periodDur []float64
+
+	scenarios  map[string]*scenarioStats
+	errors     map[string]int
+	thresholds []*thresholdStatus
+}
+
+func newStats(historySize int) *stats {
+	return &stats{
+		historySize: historySize,
+		scenarios:   make(map[string]*scenarioStats),
+		errors:      make(map[string]int),
+	}
+}
+
+func (st *stats) scenario(name string) *scenarioStats {
+	s, ok := st.scenarios[name]
+	if !ok {
+		s = &scenarioStats{duration: metrics.NewSink(metrics.Trend).(*metrics.TrendSink)} //nolint:forcetypeassert
+		st.scenarios[name] = s
+	}
+	return s
+}
+
+func (st *stats) add(s metrics.Sample) {
+	for _, ts := range st.thresholds {
+		if !ts.matches(s) {
+			continue
+		}
+		if ts.sink == nil {
+			ts.sink = metrics.NewSink(s.Metric.Type)
+		}
+		ts.sink.Add(s)
+	}
+
+	var scenario *scenarioStats
+	if name, ok := s.Tags.Get("scenario"); ok {
+		scenario = st.scenario(name)
+	}
+
+	switch s.Metric.Name {
+	case metrics.VUsName:
+		st.vus = s.Value
+	case metrics.VUsMaxName:
+		st.vusMax = s.Value
+	case metrics.IterationsName:
+		st.iterations += s.Value
+		if scenario != nil {
+			scenario.iterations += s.Value
+		}
+	case metrics.HTTPReqsName:
+		st.requests += s.Value
+		if scenario != nil {
+			scenario.requests += s.Value
+		}
+	case metrics.HTTPReqFailedName:
+		if s.Value == 0 {
+			return
+		}
+		st.failedRequests++
+		if scenario != nil {
+			scenario.failed++
+		}
+		st.errors[requestErrorKey(s.Tags)]++
+	case metrics.HTTPReqDurationName:
+		st.periodDur = append(st.periodDur, s.Value)
+		if scenario != nil {
+			scenario.duration.Add(s)
+		}
+	case metrics.ChecksName:
+		if s.Value == 0 {
+			name, _ := s.Tags.Get("check")
+			st.errors["check failed: "+name]++
+		}
+	}
+}
+
+// endPeriod closes the current refresh period and adds its latency to the history.
+func (st *stats) endPeriod() {
+	p95 := math.NaN()
+	if len(st.periodDur) > 0 {
+		sort.Float64s(st.periodDur)
+		p95 = st.periodDur[int(math.Ceil(0.95*float64(len(st.periodDur))))-1]
+		st.periodDur = st.periodDur[:0]
+	}
+	st.latency = append(st.latency, p95)
+	if len(st.latency) > st.historySize {
+		st.latency = st.latency[len(st.latency)-st.historySize:]
+	}
+}
+
+// topErrors returns the n most frequent errors, the most frequent first.
+func (st *stats) topErrors(n int) []errorCount {
+	result := make([]errorCount, 0, len(st.errors))
+	for k, c := range st.errors {
+		result = append(result, errorCount{key: k, count: c})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].count != result[j].count {
+			return result[i].count > result[j].count
+		}
+		return result[i].key < result[j].key
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+func requestErrorKey(tags *metrics.TagSet) string {
+	var b strings.Builder
+	if method, ok := tags.Get("method"); ok {
+		b.WriteString(method + " ")
+	}
+	name, _ := tags.Get("name")
+	b.WriteString(name)
+	if code, ok := tags.Get("error_code"); ok && code != "" {
+		b.WriteString(" error_code " + code)
+	} else if status, ok := tags.Get("status"); ok {
+		b.WriteString(" status " + status)
+	}
+	return b.String()
+}
+
+// setThresholds creates copies of the test's thresholds, which are evaluated
+// against the samples the dashboard sees, without touching the ones the
+// metrics engine uses.
+func (st *stats) setThresholds(thresholds map[string]metrics.Thresholds) error {
+	names := make([]string, 0, len(thresholds))
+	for name := range thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	st.thresholds = make([]*thresholdStatus, 0, len(names))
+	for _, name := range names {
+		metric, tags, err := metrics.ParseMetricName(name)
+		if err != nil {
+			return err
+		}
+		ts := &thresholdStatus{name: name, metric: metric, tags: make(map[string]string, len(tags))}
+		for _, t := range tags {
+			k, v, _ := strings.Cut(t, ":")
+			ts.tags[strings.TrimSpace(k)] = strings.Trim(strings.TrimSpace(v), `"'`)
+		}
+
+		sources := make([]string, 0, len(thresholds[name].Thresholds))
+		for _, t := range thresholds[name].Thresholds {
+			sources = append(sources, t.Source)
+		}
+		ts.thresholds = metrics.NewThresholds(sources)
+		if err := ts.thresholds.Parse(); err != nil {
+			return err
+		}
+		st.thresholds = append(st.thresholds, ts)
+	}
+	return nil
+}
+
+// thresholdResult is the state of a single threshold expression.
+type thresholdResult struct {
+	source string
+	// passed is nil while there is no data for the metric yet.
+	passed *bool
+}
+
+func (st *stats) thresholdResults(elapsed time.Duration) (map[string][]thresholdResult, error) {
+	results := make(map[string][]thresholdResult, len(st.thresholds))
+	for _, ts := range st.thresholds {
+		rs := make([]thresholdResult, len(ts.thresholds.Thresholds))
+		if ts.sink != nil {
+			if _, err := ts.thresholds.Run(ts.sink, elapsed); err != nil {
+				return nil, err
+			}
+		}
+		for i, t := range ts.thresholds.Thresholds {
+			rs[i].source = t.Source
+			if ts.sink != nil {
+				passed := !t.LastFailed
+				rs[i].passed = &passed
+			}
+		}
+		results[ts.name] = rs
+	}
+	return results, nil
+}
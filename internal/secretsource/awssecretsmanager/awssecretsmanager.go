@@ -0,0 +1,179 @@
+// Package awssecretsmanager implements a secret source that reads secrets from AWS Secrets Manager.
+package awssecretsmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"go.k6.io/k6/secretsource"
+)
+
+const (
+	service         = "secretsmanager"
+	maxResponseSize = 128 * 1024
+)
+
+var errFailedToGetSecret = errors.New("failed to get secret")
+
+//nolint:gochecknoinits // This is how k6 secret source registration works.
+func init() {
+	secretsource.RegisterExtension("aws-secretsmanager", func(params secretsource.Params) (secretsource.Source, error) {
+		s, err := newSecretsManager(params.ConfigArgument, params.Environment)
+		if err != nil {
+			return nil, fmt.Errorf("missing or invalid config: %w", err)
+		}
+		return s, nil
+	})
+}
+
+type secretsManager struct {
+	region     string
+	endpoint   string
+	creds      credentials
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// newSecretsManager parses the configuration, which is a comma separated list
+// of key=value pairs. Credentials are only read from the standard AWS
+// environment variables, since the command line is visible in the process listing.
+func newSecretsManager(arg string, env map[string]string) (*secretsManager, error) {
+	s := &secretsManager{
+		region: env["AWS_REGION"],
+		creds: credentials{
+			accessKeyID:     env["AWS_ACCESS_KEY_ID"],
+			secretAccessKey: env["AWS_SECRET_ACCESS_KEY"],
+			sessionToken:    env["AWS_SESSION_TOKEN"],
+		},
+		now: time.Now,
+	}
+	if s.region == "" {
+		s.region = env["AWS_DEFAULT_REGION"]
+	}
+	timeout := 30 * time.Second
+
+	if arg != "" {
+		for _, kv := range strings.Split(arg, ",") {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid config format %q, expected key=value", kv)
+			}
+			switch k {
+			case "region":
+				s.region = v
+			case "endpoint":
+				s.endpoint = v
+			case "timeout":
+				d, err := time.ParseDuration(v)
+				if err != nil {
+					return nil, fmt.Errorf("invalid timeout: %w", err)
+				}
+				if d <= 0 {
+					return nil, errors.New("timeout must be greater than 0")
+				}
+				timeout = d
+			default:
+				return nil, fmt.Errorf("unknown configuration key for aws-secretsmanager secret source %q", k)
+			}
+		}
+	}
+
+	if s.region == "" {
+		return nil, errors.New("region is required, either as an option or with the AWS_REGION environment variable")
+	}
+	if s.creds.accessKeyID == "" || s.creds.secretAccessKey == "" {
+		return nil, errors.New("the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables are required")
+	}
+	if s.endpoint == "" {
+		s.endpoint = "https://" + service + "." + s.region + ".amazonaws.com"
+	}
+	u, err := url.Parse(s.endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("endpoint %q is not an absolute URL", s.endpoint)
+	}
+
+	s.httpClient = &http.Client{Timeout: timeout}
+	return s, nil
+}
+
+func (s *secretsManager) Description() string {
+	return fmt.Sprintf("AWS Secrets Manager secret source in %s", s.region)
+}
+
+// Get returns the value of a secret. The key is either the secret ID (name or
+// ARN) or "secretID#field" to get a field of a secret stored as a JSON object.
+func (s *secretsManager) Get(key string) (string, error) {
+	secretID, field, hasField := strings.Cut(key, "#")
+	if secretID == "" {
+		return "", fmt.Errorf("key %q has an empty secret ID", key)
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signV4(req, body, s.creds, s.region, service, s.now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errFailedToGetSecret, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(data) > maxResponseSize {
+		return "", fmt.Errorf("secret response exceeds maximum size of %d bytes", maxResponseSize)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errType := gjson.GetBytes(data, "__type").String()
+		if i := strings.LastIndex(errType, "#"); i >= 0 {
+			errType = errType[i+1:]
+		}
+		msg := gjson.GetBytes(data, "message").String()
+		if msg == "" {
+			msg = gjson.GetBytes(data, "Message").String()
+		}
+		return "", fmt.Errorf("%w %q: status code %d: %s %s",
+			errFailedToGetSecret, secretID, resp.StatusCode, errType, msg)
+	}
+
+	secretString := gjson.GetBytes(data, "SecretString")
+	if secretString.Type != gjson.String {
+		return "", fmt.Errorf("secret %q has no string value", secretID)
+	}
+	if !hasField {
+		return secretString.String(), nil
+	}
+
+	if !gjson.Valid(secretString.String()) {
+		return "", fmt.Errorf("secret %q is not a JSON object, can't get field %q from it", secretID, field)
+	}
+	result := gjson.Get(secretString.String(), gjson.Escape(field))
+	if !result.Exists() {
+		return "", fmt.Errorf("field %q not found in secret %q", field, secretID)
+	}
+	if result.Type != gjson.String {
+		return "", fmt.Errorf("field %q of secret %q is not a string (got %s)", field, secretID, result.Type)
+	}
+	return result.String(), nil
+}
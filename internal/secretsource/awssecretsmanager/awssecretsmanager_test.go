@@ -0,0 +1,168 @@
+package awssecretsmanager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignV4(t *testing.T) {
+	t.Parallel()
+
+	// the example from the AWS documentation
+	req, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	creds := credentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signV4(req, nil, creds, "us-east-1", "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, "+
+			"SignedHeaders=content-type;host;x-amz-date, "+
+			"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7",
+		req.Header.Get("Authorization"))
+}
+
+func TestNewSecretsManager(t *testing.T) {
+	t.Parallel()
+
+	creds := map[string]string{"AWS_ACCESS_KEY_ID": "id", "AWS_SECRET_ACCESS_KEY": "secret"}
+	withEnv := func(kv ...string) map[string]string {
+		env := map[string]string{}
+		for k, v := range creds {
+			env[k] = v
+		}
+		for i := 0; i < len(kv); i += 2 {
+			env[kv[i]] = kv[i+1]
+		}
+		return env
+	}
+
+	testCases := map[string]struct {
+		arg              string
+		env              map[string]string
+		expectedRegion   string
+		expectedEndpoint string
+		expectedError    string
+	}{
+		"region from env": {
+			env:              withEnv("AWS_REGION", "eu-west-1", "AWS_DEFAULT_REGION", "us-east-1"),
+			expectedRegion:   "eu-west-1",
+			expectedEndpoint: "https://secretsmanager.eu-west-1.amazonaws.com",
+		},
+		"default region from env": {
+			env:              withEnv("AWS_DEFAULT_REGION", "us-east-1"),
+			expectedRegion:   "us-east-1",
+			expectedEndpoint: "https://secretsmanager.us-east-1.amazonaws.com",
+		},
+		"inline": {
+			arg:              "region=us-east-2,endpoint=http://localhost:4566,timeout=5s",
+			env:              withEnv("AWS_REGION", "eu-west-1"),
+			expectedRegion:   "us-east-2",
+			expectedEndpoint: "http://localhost:4566",
+		},
+		"no region": {
+			env:           withEnv(),
+			expectedError: "region is required",
+		},
+		"no credentials": {
+			arg:           "region=us-east-1",
+			env:           map[string]string{"AWS_ACCESS_KEY_ID": "id"},
+			expectedError: "AWS_SECRET_ACCESS_KEY environment variables are required",
+		},
+		"relative endpoint": {
+			arg:           "region=us-east-1,endpoint=localhost",
+			env:           withEnv(),
+			expectedError: "is not an absolute URL",
+		},
+		"unknown key": {
+			arg:           "region=us-east-1,secretAccessKey=secret",
+			env:           withEnv(),
+			expectedError: `unknown configuration key for aws-secretsmanager secret source "secretAccessKey"`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := newSecretsManager(tc.arg, tc.env)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRegion, s.region)
+			assert.Equal(t, tc.expectedEndpoint, s.endpoint)
+		})
+	}
+}
+
+func TestSecretsManagerGet(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=id/") ||
+			r.Header.Get("X-Amz-Security-Token") != "session" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var body struct {
+			SecretID string `json:"SecretId"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch body.SecretID {
+		case "plain":
+			_, _ = w.Write([]byte(`{"Name":"plain","SecretString":"hunter2"}`))
+		case "db":
+			_, _ = w.Write([]byte(`{"Name":"db","SecretString":"{\"password\":\"hunter3\",\"port\":5432}"}`))
+		case "binary":
+			_, _ = w.Write([]byte(`{"Name":"binary","SecretBinary":"aHVudGVyMg=="}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"com.amazonaws.secretsmanager#ResourceNotFoundException",` +
+				`"message":"Secrets Manager can't find the specified secret."}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	s, err := newSecretsManager("region=us-east-1,endpoint="+srv.URL, map[string]string{
+		"AWS_ACCESS_KEY_ID": "id", "AWS_SECRET_ACCESS_KEY": "secret", "AWS_SESSION_TOKEN": "session",
+	})
+	require.NoError(t, err)
+
+	v, err := s.Get("plain")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", v)
+
+	v, err = s.Get("db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter3", v)
+
+	_, err = s.Get("db#port")
+	require.ErrorContains(t, err, `field "port" of secret "db" is not a string`)
+
+	_, err = s.Get("db#user")
+	require.ErrorContains(t, err, `field "user" not found in secret "db"`)
+
+	_, err = s.Get("plain#password")
+	require.ErrorContains(t, err, `secret "plain" is not a JSON object`)
+
+	_, err = s.Get("binary")
+	require.ErrorContains(t, err, `secret "binary" has no string value`)
+
+	_, err = s.Get("missing")
+	require.ErrorIs(t, err, errFailedToGetSecret)
+	require.ErrorContains(t, err, "status code 400: ResourceNotFoundException Secrets Manager can't find")
+}
@@ -2,7 +2,9 @@
 package secretsource
 
 import (
-	_ "go.k6.io/k6/internal/secretsource/file" // import them for init
-	_ "go.k6.io/k6/internal/secretsource/mock" // import them for init
-	_ "go.k6.io/k6/internal/secretsource/url"  // import them for init
+	_ "go.k6.io/k6/internal/secretsource/awssecretsmanager" // import them for init
+	_ "go.k6.io/k6/internal/secretsource/file"              // import them for init
+	_ "go.k6.io/k6/internal/secretsource/mock"              // import them for init
+	_ "go.k6.io/k6/internal/secretsource/url"               // import them for init
+	_ "go.k6.io/k6/internal/secretsource/vault"             // import them for init
 )
@@ -0,0 +1,200 @@
+// Package vault implements a secret source that reads secrets from the KV secrets engine of HashiCorp Vault.
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"go.k6.io/k6/lib/fsext"
+	"go.k6.io/k6/secretsource"
+)
+
+const maxResponseSize = 64 * 1024
+
+var errFailedToGetSecret = errors.New("failed to get secret")
+
+//nolint:gochecknoinits // This is how k6 secret source registration works.
+func init() {
+	secretsource.RegisterExtension("vault", func(params secretsource.Params) (secretsource.Source, error) {
+		vs, err := newVaultSecrets(params.ConfigArgument, params.Environment, params.FS)
+		if err != nil {
+			return nil, fmt.Errorf("missing or invalid config: %w", err)
+		}
+		return vs, nil
+	})
+}
+
+type vaultSecrets struct {
+	address    string
+	token      string
+	namespace  string
+	mount      string
+	path       string
+	kvVersion  int
+	httpClient *http.Client
+}
+
+// newVaultSecrets parses the configuration, which is a comma separated list of
+// key=value pairs. The token is intentionally not configurable inline, since
+// the command line is visible in the process listing. It is read from the
+// VAULT_TOKEN environment variable or from the file set with tokenFile.
+func newVaultSecrets(arg string, env map[string]string, fs fsext.Fs) (*vaultSecrets, error) {
+	vs := &vaultSecrets{
+		address:   env["VAULT_ADDR"],
+		token:     env["VAULT_TOKEN"],
+		namespace: env["VAULT_NAMESPACE"],
+		mount:     "secret",
+		kvVersion: 2,
+	}
+	timeout := 30 * time.Second
+
+	if arg != "" {
+		for _, kv := range strings.Split(arg, ",") {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid config format %q, expected key=value", kv)
+			}
+			switch k {
+			case "address":
+				vs.address = v
+			case "namespace":
+				vs.namespace = v
+			case "mount":
+				vs.mount = strings.Trim(v, "/")
+			case "path":
+				vs.path = strings.Trim(v, "/")
+			case "kvVersion":
+				switch v {
+				case "1":
+					vs.kvVersion = 1
+				case "2":
+					vs.kvVersion = 2
+				default:
+					return nil, fmt.Errorf("kvVersion must be 1 or 2, got %q", v)
+				}
+			case "tokenFile":
+				token, err := fsext.ReadFile(fs, v)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read tokenFile: %w", err)
+				}
+				vs.token = strings.TrimSpace(string(token))
+			case "timeout":
+				d, err := time.ParseDuration(v)
+				if err != nil {
+					return nil, fmt.Errorf("invalid timeout: %w", err)
+				}
+				if d <= 0 {
+					return nil, errors.New("timeout must be greater than 0")
+				}
+				timeout = d
+			default:
+				return nil, fmt.Errorf("unknown configuration key for vault secret source %q", k)
+			}
+		}
+	}
+
+	if vs.address == "" {
+		return nil, errors.New("address is required, either as an option or with the VAULT_ADDR environment variable")
+	}
+	u, err := url.Parse(vs.address)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("address %q is not an absolute URL", vs.address)
+	}
+	vs.address = strings.TrimSuffix(vs.address, "/")
+	if vs.token == "" {
+		return nil, errors.New("a token is required, either with the VAULT_TOKEN environment variable or tokenFile")
+	}
+	if vs.mount == "" {
+		return nil, errors.New("mount can't be empty")
+	}
+
+	vs.httpClient = &http.Client{Timeout: timeout}
+	return vs, nil
+}
+
+func (vs *vaultSecrets) Description() string {
+	return fmt.Sprintf("Vault KV v%d secret source at %s", vs.kvVersion, vs.address)
+}
+
+// Get returns the given field of a secret. The key is either "path#field" or,
+// if a path was configured, just the field of the secret at that path.
+func (vs *vaultSecrets) Get(key string) (string, error) {
+	path, field, err := vs.splitKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, vs.secretURL(path), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", vs.token)
+	if vs.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", vs.namespace)
+	}
+
+	resp, err := vs.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errFailedToGetSecret, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w %q: status code %d", errFailedToGetSecret, path, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(data) > maxResponseSize {
+		return "", fmt.Errorf("secret response exceeds maximum size of %d bytes", maxResponseSize)
+	}
+
+	dataPath := "data"
+	if vs.kvVersion == 2 {
+		dataPath = "data.data"
+	}
+	result := gjson.GetBytes(data, dataPath+"."+gjson.Escape(field))
+	if !result.Exists() {
+		return "", fmt.Errorf("field %q not found in secret %q", field, path)
+	}
+	if result.Type != gjson.String {
+		return "", fmt.Errorf("field %q of secret %q is not a string (got %s)", field, path, result.Type)
+	}
+	return result.String(), nil
+}
+
+func (vs *vaultSecrets) splitKey(key string) (path, field string, err error) {
+	path, field, ok := strings.Cut(key, "#")
+	if !ok {
+		path, field = vs.path, key
+	}
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "", "", fmt.Errorf("key %q should be in the path#field format when no path is configured", key)
+	}
+	if field == "" {
+		return "", "", fmt.Errorf("key %q has an empty field", key)
+	}
+	return path, field, nil
+}
+
+func (vs *vaultSecrets) secretURL(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	if vs.kvVersion == 2 {
+		return vs.address + "/v1/" + vs.mount + "/data/" + strings.Join(segments, "/")
+	}
+	return vs.address + "/v1/" + vs.mount + "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,152 @@
+package vault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/lib/fsext"
+)
+
+func TestNewVaultSecrets(t *testing.T) {
+	t.Parallel()
+
+	fs := fsext.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/token", []byte("file-token\n"), 0o600))
+
+	testCases := map[string]struct {
+		arg           string
+		env           map[string]string
+		expected      vaultSecrets
+		expectedError string
+	}{
+		"env": {
+			env: map[string]string{"VAULT_ADDR": "https://vault:8200/", "VAULT_TOKEN": "t", "VAULT_NAMESPACE": "ns"},
+			expected: vaultSecrets{
+				address: "https://vault:8200", token: "t", namespace: "ns", mount: "secret", kvVersion: 2,
+			},
+		},
+		"inline": {
+			arg: "address=http://localhost:8200,mount=/kv/,path=app/db,kvVersion=1,tokenFile=/token",
+			expected: vaultSecrets{
+				address: "http://localhost:8200", token: "file-token", mount: "kv", path: "app/db", kvVersion: 1,
+			},
+		},
+		"no address": {
+			env:           map[string]string{"VAULT_TOKEN": "t"},
+			expectedError: "address is required",
+		},
+		"relative address": {
+			arg:           "address=vault:8200",
+			env:           map[string]string{"VAULT_TOKEN": "t"},
+			expectedError: "is not an absolute URL",
+		},
+		"no token": {
+			arg:           "address=http://localhost:8200",
+			expectedError: "a token is required",
+		},
+		"inline token": {
+			arg:           "address=http://localhost:8200,token=t",
+			expectedError: `unknown configuration key for vault secret source "token"`,
+		},
+		"bad kv version": {
+			arg:           "kvVersion=3",
+			expectedError: "kvVersion must be 1 or 2",
+		},
+		"bad timeout": {
+			arg:           "timeout=-1s",
+			expectedError: "timeout must be greater than 0",
+		},
+		"not key value": {
+			arg:           "address",
+			expectedError: "expected key=value",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			vs, err := newVaultSecrets(tc.arg, tc.env, fs)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			vs.httpClient = nil
+			assert.Equal(t, tc.expected, *vs)
+		})
+	}
+}
+
+func TestVaultSecretsGet(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/app/db":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"hunter2","port":5432},"metadata":{"version":3}}}`))
+		case "/v1/kv/app/db":
+			_, _ = w.Write([]byte(`{"data":{"password":"hunter1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	env := map[string]string{"VAULT_ADDR": srv.URL, "VAULT_TOKEN": "s.token"}
+	fs := fsext.NewMemMapFs()
+
+	t.Run("kv v2", func(t *testing.T) {
+		t.Parallel()
+		vs, err := newVaultSecrets("path=app/db", env, fs)
+		require.NoError(t, err)
+
+		v, err := vs.Get("password")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", v)
+
+		v, err = vs.Get("app/db#password")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", v)
+
+		_, err = vs.Get("port")
+		require.ErrorContains(t, err, `field "port" of secret "app/db" is not a string`)
+
+		_, err = vs.Get("user")
+		require.ErrorContains(t, err, `field "user" not found in secret "app/db"`)
+
+		_, err = vs.Get("app/other#password")
+		require.ErrorIs(t, err, errFailedToGetSecret)
+		require.ErrorContains(t, err, "status code 404")
+	})
+
+	t.Run("kv v1", func(t *testing.T) {
+		t.Parallel()
+		vs, err := newVaultSecrets("mount=kv,kvVersion=1", env, fs)
+		require.NoError(t, err)
+
+		v, err := vs.Get("app/db#password")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter1", v)
+
+		_, err = vs.Get("password")
+		require.ErrorContains(t, err, "should be in the path#field format")
+	})
+
+	t.Run("wrong token", func(t *testing.T) {
+		t.Parallel()
+		vs, err := newVaultSecrets("path=app/db", map[string]string{"VAULT_ADDR": srv.URL, "VAULT_TOKEN": "bad"}, fs)
+		require.NoError(t, err)
+
+		_, err = vs.Get("password")
+		require.ErrorContains(t, err, "status code 403")
+	})
+}
@@ -122,3 +122,19 @@ func TestProgressBarLeft(t *testing.T) {
 		})
 	}
 }
+
+func TestProgressBarProgress(t *testing.T) {
+	t.Parallel()
+
+	progress, status := New().Progress()
+	assert.Equal(t, 0.0, progress)
+	assert.Equal(t, Status(0), status)
+
+	pbar := New(
+		WithStatus(Stopping),
+		WithProgress(func() (float64, []string) { return 2, []string{"right"} }),
+	)
+	progress, status = pbar.Progress()
+	assert.Equal(t, 1.0, progress)
+	assert.Equal(t, Stopping, status)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math/rand" // nosemgrep: math-random-used // used to seed the Marh.random of the JS VM that is pseudo random by specification
+	randv2 "math/rand/v2"
 
 	"github.com/grafana/sobek"
 )
@@ -20,3 +21,10 @@ func NewRandSource() sobek.RandSource {
 	}
 	return rand.New(rand.NewSource(seed)).Float64 //nolint:gosec
 }
+
+// NewSeededRandSource returns a RandSource that always generates the same
+// sequence of values for the same seed and stream.
+// The returned RandSource is NOT safe for concurrent use.
+func NewSeededRandSource(seed int64, stream uint64) sobek.RandSource {
+	return randv2.New(randv2.NewPCG(uint64(seed), stream)).Float64 //nolint:gosec
+}
@@ -260,3 +260,62 @@ func TestAsyncRequestErrors(t *testing.T) {
 		assert.Contains(t, promiseRejected.ToString(), expErr)
 	})
 }
+
+func TestAsyncRequestAbortSignal(t *testing.T) {
+	t.Parallel()
+	t.Run("in flight", func(t *testing.T) {
+		t.Parallel()
+		ts := newTestCase(t)
+
+		sr := ts.tb.Replacer.Replace
+		start := time.Now()
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			const controller = new AbortController();
+			setTimeout(() => controller.abort(), 50);
+			const res = await http.asyncRequest("GET", "HTTPBIN_URL/delay/10", null,
+				{ signal: controller.signal, throw: false });
+			if (res.error_code !== 1051) { throw new Error("wrong error code: " + res.error_code); }
+			if (res.error !== "request canceled") { throw new Error("wrong error: " + res.error); }
+		`)))
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+	t.Run("already aborted", func(t *testing.T) {
+		t.Parallel()
+		ts := newTestCase(t)
+
+		sr := ts.tb.Replacer.Replace
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			try {
+				await http.asyncRequest("GET", "HTTPBIN_URL/get", null, { signal: AbortSignal.abort() });
+			} catch (e) {
+				globalThis.rejected = e;
+			}
+		`)))
+		require.NoError(t, err)
+		assert.Contains(t, ts.runtime.VU.Runtime().Get("rejected").String(), "request canceled")
+	})
+	t.Run("not aborted", func(t *testing.T) {
+		t.Parallel()
+		ts := newTestCase(t)
+
+		sr := ts.tb.Replacer.Replace
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			const controller = new AbortController();
+			const res = await http.asyncRequest("GET", "HTTPBIN_URL/get", null, { signal: controller.signal });
+			if (res.status !== 200) { throw new Error("wrong status: " + res.status); }
+			controller.abort();
+		`)))
+		require.NoError(t, err)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		ts := newTestCase(t)
+
+		sr := ts.tb.Replacer.Replace
+		_, err := ts.runtime.RunOnEventLoop(wrapInAsyncLambda(sr(`
+			await http.asyncRequest("GET", "HTTPBIN_URL/get", null, { signal: {} });
+		`)))
+		require.ErrorContains(t, err, "invalid signal param: [object Object] isn't an AbortSignal")
+	})
+}
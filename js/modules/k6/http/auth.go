@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/lib/netext/httpext"
+)
+
+// registerAuth registers a function as the handler of the requests of the VU
+// made with name as their auth param. The function is called with an object of
+// the prepared request, with its method, url, headers and body, and the headers
+// it sets on it are sent with the request. Unlike the handlers registered by
+// extensions, it isn't called again with the redirects.
+func (mi *ModuleInstance) registerAuth(name string, handler sobek.Value) error {
+	if name == "" {
+		return errors.New("http.registerAuth() requires the name of the auth scheme")
+	}
+	fn, ok := sobek.AssertFunction(handler)
+	if !ok {
+		return fmt.Errorf("the handler of the auth scheme %q must be a function", name)
+	}
+	if _, ok := mi.authSchemes[name]; ok || httpext.IsAuthSchemeRegistered(name) {
+		return fmt.Errorf("the auth scheme %q is already registered", name)
+	}
+	if mi.authSchemes == nil {
+		mi.authSchemes = make(map[string]sobek.Callable)
+	}
+	mi.authSchemes[name] = fn
+	return nil
+}
+
+// authenticate calls the handler of the auth scheme of the request, if it was
+// registered with http.registerAuth(), and applies the headers it set.
+func (mi *ModuleInstance) authenticate(preq *httpext.ParsedHTTPRequest) error {
+	handler, ok := mi.authSchemes[preq.Auth]
+	if !ok {
+		return nil
+	}
+	rt := mi.vu.Runtime()
+
+	headers := rt.NewObject()
+	for key, values := range preq.Req.Header {
+		var value interface{} = values
+		if len(values) == 1 {
+			value = values[0]
+		}
+		if err := headers.Set(key, value); err != nil {
+			return err
+		}
+	}
+	var body string
+	if preq.Body != nil {
+		body = preq.Body.String()
+	}
+	req := rt.NewObject()
+	for name, value := range map[string]interface{}{
+		"method":  preq.Req.Method,
+		"url":     preq.Req.URL.String(),
+		"headers": headers,
+		"body":    body,
+	} {
+		if err := req.Set(name, value); err != nil {
+			return err
+		}
+	}
+
+	if _, err := handler(sobek.Undefined(), req); err != nil {
+		return err
+	}
+
+	header := make(http.Header)
+	for _, key := range headers.Keys() {
+		value := headers.Get(key)
+		if common.IsNullish(value) {
+			continue
+		}
+		if obj, isObject := value.(*sobek.Object); isObject && obj.ClassName() == "Array" {
+			var values []string
+			if err := rt.ExportTo(obj, &values); err != nil {
+				return err
+			}
+			for _, v := range values {
+				header.Add(key, v)
+			}
+			continue
+		}
+		header.Set(key, value.String())
+	}
+	preq.Req.Header = header
+	return nil
+}
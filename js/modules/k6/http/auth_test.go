@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/lib/netext/httpext"
+)
+
+func TestRegisterAuth(t *testing.T) {
+	t.Parallel()
+	ts := newTestCase(t)
+	tb := ts.tb
+	rt := ts.runtime.VU.Runtime()
+
+	_, err := rt.RunString(tb.Replacer.Replace(`
+		var calls = [];
+		http.registerAuth("hmac", (req) => {
+			calls.push(req.method + " " + req.url + " " + req.body);
+			req.headers["X-Signature"] = "sig-" + req.body.length;
+			req.headers["X-Multi"] = ["a", "b"];
+			delete req.headers["X-Remove"];
+		});
+
+		var res = http.post("HTTPBIN_URL/post", "data", {
+			auth: "hmac",
+			headers: { "X-Keep": "yes", "X-Remove": "no" },
+		});
+		var headers = res.json().headers;
+		if (headers["X-Signature"][0] !== "sig-4" || headers["X-Keep"][0] !== "yes") {
+			throw new Error("the headers weren't set: " + res.body);
+		}
+		if (headers["X-Multi"].join() !== "a,b" || headers["X-Remove"] !== undefined) {
+			throw new Error("the headers weren't changed: " + res.body);
+		}
+
+		res = http.get("HTTPBIN_URL/headers");
+		if (res.json().headers["X-Signature"] !== undefined) {
+			throw new Error("the auth scheme was used without the auth param");
+		}
+		if (calls.join() !== "POST HTTPBIN_URL/post data") {
+			throw new Error("unexpected calls " + calls.join());
+		}
+	`))
+	require.NoError(t, err)
+}
+
+func TestRegisterAuthScheme(t *testing.T) {
+	t.Parallel()
+
+	httpext.RegisterAuthScheme("test-go-scheme", func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		req.Header.Set("X-Go-Auth", req.Method+" "+req.URL.Path)
+		return next.RoundTrip(req)
+	})
+	assert.True(t, httpext.IsAuthSchemeRegistered("test-go-scheme"))
+	assert.Panics(t, func() {
+		httpext.RegisterAuthScheme("test-go-scheme", func(*http.Request, http.RoundTripper) (*http.Response, error) {
+			return nil, nil //nolint:nilnil
+		})
+	})
+
+	ts := newTestCase(t)
+	_, err := ts.runtime.VU.Runtime().RunString(ts.tb.Replacer.Replace(`
+		var res = http.get("HTTPBIN_URL/redirect-to?url=/headers", { auth: "test-go-scheme" });
+		if (res.json().headers["X-Go-Auth"][0] !== "GET /headers") {
+			throw new Error("the handler wasn't called with the redirect: " + res.body);
+		}
+		http.registerAuth("test-go-scheme", () => {});
+	`))
+	require.ErrorContains(t, err, `the auth scheme "test-go-scheme" is already registered`)
+}
+
+func TestRegisterAuthErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`http.registerAuth("", () => {})`:                                              "http.registerAuth() requires the name of the auth scheme",
+		`http.registerAuth("custom", 1)`:                                               `the handler of the auth scheme "custom" must be a function`,
+		`http.registerAuth("digest", () => {})`:                                        `the auth scheme "digest" is already registered`,
+		`http.registerAuth("custom", () => {}); http.registerAuth("custom", () => {})`: `the auth scheme "custom" is already registered`,
+		`http.registerAuth("custom", () => { throw new Error("no") }); http.get("HTTPBIN_URL/get", { auth: "custom" })`: "no",
+	}
+	for script, expected := range tests {
+		t.Run(script, func(t *testing.T) {
+			t.Parallel()
+			ts := newTestCase(t)
+			_, err := ts.runtime.VU.Runtime().RunString(ts.tb.Replacer.Replace(script))
+			require.ErrorContains(t, err, expected)
+		})
+	}
+}
@@ -24,6 +24,11 @@ type ModuleInstance struct {
 	rootModule    *RootModule
 	defaultClient *Client
 	exports       *sobek.Object
+
+	// interceptors are the ones registered with http.use().
+	interceptors []*interceptor
+	// authSchemes are the ones registered with http.registerAuth().
+	authSchemes map[string]sobek.Callable
 }
 
 var (
@@ -89,6 +94,8 @@ func (r *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
 	mustExport("asyncRequest", mi.defaultClient.asyncRequest)
 	mustExport("batch", mi.defaultClient.Batch)
 	mustExport("setResponseCallback", mi.defaultClient.SetResponseCallback)
+	mustExport("use", mi.use)
+	mustExport("registerAuth", mi.registerAuth)
 
 	mustExport("expectedStatuses", mi.expectedStatuses) // TODO: refactor?
 
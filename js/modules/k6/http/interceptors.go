@@ -0,0 +1,228 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/js/common"
+	"go.k6.io/k6/lib/netext/httpext"
+)
+
+// interceptor is registered with http.use() to run hooks around the requests
+// of a VU.
+type interceptor struct {
+	// request is called with each request before it's made, it can change it
+	// or skip it by returning a response.
+	request sobek.Callable
+	// response is called with each response and its request.
+	response sobek.Callable
+}
+
+// use registers an interceptor for the requests of the VU, either a function
+// that is the request hook or an object with request and response hooks. The
+// ones registered in the init context apply to all the requests of the VU. It
+// returns a function that removes the interceptor.
+func (mi *ModuleInstance) use(v sobek.Value) (func(), error) {
+	rt := mi.vu.Runtime()
+	if common.IsNullish(v) {
+		return nil, errors.New("http.use() requires a function or an object with request and response hooks")
+	}
+	i := &interceptor{}
+	if fn, ok := sobek.AssertFunction(v); ok {
+		i.request = fn
+	} else {
+		obj := v.ToObject(rt)
+		for name, dst := range map[string]*sobek.Callable{"request": &i.request, "response": &i.response} {
+			hook := obj.Get(name)
+			if common.IsNullish(hook) {
+				continue
+			}
+			fn, ok := sobek.AssertFunction(hook)
+			if !ok {
+				return nil, fmt.Errorf("the %s hook of the interceptor must be a function", name)
+			}
+			*dst = fn
+		}
+		if i.request == nil && i.response == nil {
+			return nil, errors.New("the interceptor has neither a request nor a response hook")
+		}
+	}
+
+	mi.interceptors = append(mi.interceptors, i)
+	return func() {
+		mi.interceptors = slices.DeleteFunc(mi.interceptors, func(other *interceptor) bool { return other == i })
+	}, nil
+}
+
+// requestArgs are the arguments of a request, that the interceptors can
+// change.
+type requestArgs struct {
+	method string
+	url    sobek.Value
+	body   sobek.Value
+	params sobek.Value
+}
+
+// interception is the state of the interceptors for a request.
+type interception struct {
+	// interceptors are the ones registered when the request was made.
+	interceptors []*interceptor
+	// request is the object passed to the hooks.
+	request *sobek.Object
+	// response is the one returned by a request hook, the request isn't made
+	// if it's set.
+	response sobek.Value
+}
+
+// interceptRequest calls the request hooks of the interceptors, in the order
+// they were registered, with an object of the request that they can change.
+// It returns nil if there are no interceptors.
+func (c *Client) interceptRequest(args *requestArgs) (*interception, error) {
+	mi := c.moduleInstance
+	if len(mi.interceptors) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+	rt := mi.vu.Runtime()
+	ic := &interception{interceptors: slices.Clone(mi.interceptors), request: rt.NewObject()}
+
+	params, err := copyParams(rt, args.params)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range map[string]sobek.Value{
+		"method": rt.ToValue(args.method),
+		"url":    args.url,
+		"body":   args.body,
+		"params": params,
+	} {
+		if value == nil {
+			value = sobek.Undefined()
+		}
+		if err := ic.request.Set(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, i := range ic.interceptors {
+		if i.request == nil {
+			continue
+		}
+		res, err := i.request(sobek.Undefined(), ic.request)
+		if err != nil {
+			return nil, err
+		}
+		if !common.IsNullish(res) {
+			ic.response = res
+			break
+		}
+	}
+
+	args.method = strings.ToUpper(ic.request.Get("method").String())
+	args.url = ic.request.Get("url")
+	args.body = ic.request.Get("body")
+	args.params = ic.request.Get("params")
+	return ic, nil
+}
+
+// interceptResponse calls the response hooks of the interceptors, in the
+// reverse order of their registration, with the response.
+func (ic *interception) interceptResponse(res *Response) error {
+	if ic == nil {
+		return nil
+	}
+	rt := res.client.moduleInstance.vu.Runtime()
+	resValue := rt.ToValue(res)
+	for _, i := range slices.Backward(ic.interceptors) {
+		if i.response == nil {
+			continue
+		}
+		if _, err := i.response(sobek.Undefined(), resValue, ic.request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyParams copies the params of a request and their headers, so the
+// interceptors can change them without changing the object of the script,
+// which might be reused for other requests.
+func copyParams(rt *sobek.Runtime, params sobek.Value) (*sobek.Object, error) {
+	result := rt.NewObject()
+	headers := rt.NewObject()
+	if !common.IsNullish(params) {
+		obj := params.ToObject(rt)
+		for _, key := range obj.Keys() {
+			if err := result.Set(key, obj.Get(key)); err != nil {
+				return nil, err
+			}
+		}
+		if h := obj.Get("headers"); !common.IsNullish(h) {
+			hobj := h.ToObject(rt)
+			for _, key := range hobj.Keys() {
+				if err := headers.Set(key, hobj.Get(key)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if err := result.Set("headers", headers); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// responseFromInterceptor returns the response of a request that was skipped
+// by an interceptor, from the object it returned, like
+// { status: 200, headers: { ... }, body: "..." }.
+func responseFromInterceptor(
+	rt *sobek.Runtime, req *httpext.ParsedHTTPRequest, v sobek.Value,
+) (*httpext.Response, error) {
+	obj := v.ToObject(rt)
+	resp := httpext.NewResponse()
+	resp.URL = req.Req.URL.String()
+	resp.Request = &httpext.Request{
+		Method:  req.Req.Method,
+		URL:     req.Req.URL.String(),
+		Headers: req.Req.Header,
+	}
+	if req.Body != nil {
+		resp.Request.Body = req.Body.String()
+	}
+
+	resp.Status = http.StatusOK
+	if status := obj.Get("status"); !common.IsNullish(status) {
+		resp.Status = int(status.ToInteger())
+		if resp.Status < 100 || resp.Status > 999 {
+			return nil, fmt.Errorf("invalid status %s of the response of the interceptor", status)
+		}
+	}
+	resp.StatusText = fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status))
+	if headers := obj.Get("headers"); !common.IsNullish(headers) {
+		hobj := headers.ToObject(rt)
+		for _, key := range hobj.Keys() {
+			resp.Headers[http.CanonicalHeaderKey(key)] = hobj.Get(key).String()
+		}
+	}
+
+	var body []byte
+	if v := obj.Get("body"); !common.IsNullish(v) {
+		var err error
+		if body, err = common.ToBytes(v.Export()); err != nil {
+			return nil, fmt.Errorf("invalid body of the response of the interceptor: %w", err)
+		}
+	}
+	switch req.ResponseType {
+	case httpext.ResponseTypeNone:
+		resp.Body = nil
+	case httpext.ResponseTypeText:
+		resp.Body = string(body)
+	default:
+		resp.Body = body
+	}
+	return resp, nil
+}
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/metrics"
+)
+
+func TestInterceptors(t *testing.T) {
+	t.Parallel()
+	ts := newTestCase(t)
+	tb := ts.tb
+	rt := ts.runtime.VU.Runtime()
+
+	_, err := rt.RunString(tb.Replacer.Replace(`
+		var requests = [], responses = [];
+		var removeTrace = http.use((req) => {
+			req.params.headers["X-Trace"] = "trace-" + requests.length;
+			requests.push(req.method + " " + req.url);
+		});
+		var removeLog = http.use({
+			response(res, req) {
+				responses.push(res.status + " " + req.params.headers["X-Trace"]);
+			},
+		});
+
+		var params = { headers: { "X-Test": "yes" } };
+		var res = http.get("HTTPBIN_URL/headers", params);
+		if (res.json().headers["X-Trace"][0] !== "trace-0" || res.json().headers["X-Test"][0] !== "yes") {
+			throw new Error("the header wasn't added: " + res.body);
+		}
+		if (params.headers["X-Trace"] !== undefined) {
+			throw new Error("the params of the script were changed");
+		}
+
+		res = http.post("HTTPBIN_URL/post", "data");
+		if (res.json().headers["X-Trace"][0] !== "trace-1" || res.request.body !== "data") {
+			throw new Error("the header wasn't added: " + res.body);
+		}
+
+		removeTrace();
+		res = http.get("HTTPBIN_URL/headers");
+		if (res.json().headers["X-Trace"] !== undefined) {
+			throw new Error("the removed interceptor was called");
+		}
+		removeLog();
+
+		if (requests.join() !== "GET HTTPBIN_URL/headers,POST HTTPBIN_URL/post") {
+			throw new Error("unexpected requests " + requests.join());
+		}
+		if (responses.join() !== "200 trace-0,200 trace-1,200 undefined") {
+			throw new Error("unexpected responses " + responses.join());
+		}
+	`))
+	require.NoError(t, err)
+	assert.Empty(t, ts.instance.interceptors)
+}
+
+func TestInterceptorsChangeRequest(t *testing.T) {
+	t.Parallel()
+	ts := newTestCase(t)
+	tb := ts.tb
+	rt := ts.runtime.VU.Runtime()
+
+	_, err := rt.RunString(tb.Replacer.Replace(`
+		http.use((req) => {
+			req.method = "put";
+			req.url = "HTTPBIN_URL/put";
+			req.body = "changed";
+			req.params.tags = { name: "changed" };
+		});
+		var res = http.post("HTTPBIN_URL/post", "data");
+		if (res.status !== 200 || res.request.body !== "changed" || res.json().method !== "PUT") {
+			throw new Error("the request wasn't changed: " + res.status + " " + res.body);
+		}
+	`))
+	require.NoError(t, err)
+
+	found := false
+	for _, sample := range metrics.GetBufferedSamples(ts.samples) {
+		for _, s := range sample.GetSamples() {
+			if name, _ := s.Tags.Get("name"); name == "changed" {
+				method, _ := s.Tags.Get("method")
+				assert.Equal(t, "PUT", method)
+				found = true
+			}
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestInterceptorsSkipRequest(t *testing.T) {
+	t.Parallel()
+	ts := newTestCase(t)
+	tb := ts.tb
+	rt := ts.runtime.VU.Runtime()
+
+	_, err := rt.RunString(tb.Replacer.Replace(`
+		var seen = [];
+		http.use({
+			request(req) {
+				if (req.url.indexOf("/cached") >= 0) {
+					return { status: 201, headers: { "content-type": "application/json" }, body: '{"cached":true}' };
+				}
+			},
+			response(res) {
+				seen.push(res.status);
+			},
+		});
+		var res = http.get("HTTPBIN_URL/cached");
+		if (res.status !== 201 || res.json().cached !== true || res.headers["Content-Type"] !== "application/json") {
+			throw new Error("unexpected response " + res.status + " " + res.body);
+		}
+		if (res.request.method !== "GET" || res.url !== "HTTPBIN_URL/cached") {
+			throw new Error("unexpected request " + res.request.method + " " + res.url);
+		}
+
+		var responses = http.batch([
+			["GET", "HTTPBIN_URL/cached"],
+			["GET", "HTTPBIN_URL/get"],
+		]);
+		if (responses[0].status !== 201 || responses[1].status !== 200) {
+			throw new Error("unexpected batch responses " + responses[0].status + " " + responses[1].status);
+		}
+		if (seen.join() !== "201,201,200") {
+			throw new Error("unexpected responses " + seen.join());
+		}
+	`))
+	require.NoError(t, err)
+
+	// only the request to /get was made
+	urls := map[string]bool{}
+	for _, sample := range metrics.GetBufferedSamples(ts.samples) {
+		for _, s := range sample.GetSamples() {
+			if u, ok := s.Tags.Get("url"); ok {
+				urls[u] = true
+			}
+		}
+	}
+	assert.Equal(t, map[string]bool{tb.Replacer.Replace("HTTPBIN_URL/get"): true}, urls)
+}
+
+func TestInterceptorsAsyncRequest(t *testing.T) {
+	t.Parallel()
+	ts := newTestCase(t)
+	tb := ts.tb
+
+	_, err := ts.runtime.RunOnEventLoop(tb.Replacer.Replace(`
+		var responses = [];
+		http.use({
+			request(req) {
+				req.params.headers["X-Trace"] = "async";
+				if (req.url.indexOf("/cached") >= 0) {
+					return { body: "cached" };
+				}
+			},
+			response(res) {
+				responses.push(res.status);
+			},
+		});
+		(async () => {
+			var res = await http.asyncRequest("GET", "HTTPBIN_URL/headers");
+			if (res.json().headers["X-Trace"][0] !== "async") {
+				throw new Error("the header wasn't added: " + res.body);
+			}
+			res = await http.asyncRequest("GET", "HTTPBIN_URL/cached");
+			if (res.status !== 200 || res.body !== "cached") {
+				throw new Error("unexpected response " + res.status + " " + res.body);
+			}
+			if (responses.join() !== "200,200") {
+				throw new Error("unexpected responses " + responses.join());
+			}
+		})()
+	`))
+	require.NoError(t, err)
+}
+
+func TestInterceptorsErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`http.use()`:                "http.use() requires a function or an object",
+		`http.use({})`:              "has neither a request nor a response hook",
+		`http.use({ response: 1 })`: "the response hook of the interceptor must be a function",
+		`http.use(() => { throw new Error("no") }); http.get("HTTPBIN_URL/get")`:          "no",
+		`http.use(() => { throw new Error("no") }); http.batch(["HTTPBIN_URL/get"])`:      "no",
+		`http.use({ response() { throw new Error("no") } }); http.get("HTTPBIN_URL/get")`: "no",
+		`http.use(() => ({ status: 1 })); http.get("HTTPBIN_URL/get")`:                    "invalid status 1",
+	}
+	for script, expected := range tests {
+		t.Run(script, func(t *testing.T) {
+			t.Parallel()
+			ts := newTestCase(t)
+			_, err := ts.runtime.VU.Runtime().RunString(ts.tb.Replacer.Replace(script))
+			require.ErrorContains(t, err, expected)
+		})
+	}
+}
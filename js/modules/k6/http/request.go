@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"mime/multipart"
@@ -15,6 +16,7 @@ import (
 	"github.com/grafana/sobek"
 	"gopkg.in/guregu/null.v3"
 
+	"go.k6.io/k6/internal/js/tc55/abort"
 	"go.k6.io/k6/js/common"
 	"go.k6.io/k6/lib/netext/httpext"
 	"go.k6.io/k6/lib/types"
@@ -41,29 +43,51 @@ func (c *Client) Request(method string, url sobek.Value, args ...sobek.Value) (*
 	if state == nil {
 		return nil, ErrHTTPForbiddenInInitContext
 	}
-	body, params := splitRequestArgs(args)
+	reqArgs := splitRequestArgs(method, url, args)
+	ic, err := c.interceptRequest(reqArgs)
+	if err != nil {
+		return nil, err
+	}
 
-	req, err := c.parseRequest(method, url, body, params)
+	req, err := c.parseRequest(reqArgs.method, reqArgs.url, exportBody(reqArgs.body), reqArgs.params)
 	if err != nil {
 		return c.handleParseRequestError(err)
 	}
 
-	resp, err := httpext.MakeRequest(c.moduleInstance.vu.Context(), state, req)
+	var resp *httpext.Response
+	if ic != nil && ic.response != nil {
+		resp, err = responseFromInterceptor(c.moduleInstance.vu.Runtime(), req, ic.response)
+	} else {
+		resp, err = httpext.MakeRequest(c.moduleInstance.vu.Context(), state, req)
+	}
 	if err != nil {
 		return nil, err
 	}
 	c.processResponse(resp, req.ResponseType)
-	return c.responseFromHTTPext(resp), nil
+	res := c.responseFromHTTPext(resp)
+	if err := ic.interceptResponse(res); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
-func splitRequestArgs(args []sobek.Value) (body interface{}, params sobek.Value) {
+func splitRequestArgs(method string, url sobek.Value, args []sobek.Value) *requestArgs {
+	reqArgs := &requestArgs{method: method, url: url}
 	if len(args) > 0 {
-		body = args[0].Export()
+		reqArgs.body = args[0]
 	}
 	if len(args) > 1 {
-		params = args[1]
+		reqArgs.params = args[1]
+	}
+	return reqArgs
+}
+
+// exportBody returns the body of a request as it's parsed.
+func exportBody(body sobek.Value) interface{} {
+	if body == nil {
+		return nil
 	}
-	return body, params
+	return body.Export()
 }
 
 func (c *Client) handleParseRequestError(err error) (*Response, error) {
@@ -90,10 +114,31 @@ func (c *Client) asyncRequest(method string, url sobek.Value, args ...sobek.Valu
 		return nil, ErrHTTPForbiddenInInitContext
 	}
 
-	body, params := splitRequestArgs(args)
 	rt := c.moduleInstance.vu.Runtime()
-	req, err := c.parseRequest(method, url, body, params)
+	reqArgs := splitRequestArgs(method, url, args)
+	ic, err := c.interceptRequest(reqArgs)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.parseRequest(reqArgs.method, reqArgs.url, exportBody(reqArgs.body), reqArgs.params)
+	var signal *abort.Signal
+	if err == nil {
+		signal, err = requestSignal(rt, reqArgs.params)
+	}
 	p, resolve, reject := rt.NewPromise()
+	if err == nil && ic != nil && ic.response != nil {
+		// the request was skipped by an interceptor
+		var resp *httpext.Response
+		if resp, err = responseFromInterceptor(rt, req, ic.response); err != nil {
+			return nil, err
+		}
+		c.processResponse(resp, req.ResponseType)
+		res := c.responseFromHTTPext(resp)
+		if err = ic.interceptResponse(res); err != nil {
+			return p, reject(err)
+		}
+		return p, resolve(res)
+	}
 	if err != nil {
 		var resp *Response
 		if resp, err = c.handleParseRequestError(err); err != nil {
@@ -104,22 +149,53 @@ func (c *Client) asyncRequest(method string, url sobek.Value, args ...sobek.Valu
 		return p, err
 	}
 
+	// The request is canceled when the signal is aborted, and the subscription
+	// is removed once it's finished, as the signal may outlive it.
+	ctx, cancel := context.WithCancelCause(c.moduleInstance.vu.Context())
+	unsubscribe := func() {}
+	if signal != nil {
+		unsubscribe = signal.Subscribe(func() { cancel(httpext.ErrRequestCanceled) })
+	}
+
 	callback := c.moduleInstance.vu.RegisterCallback()
 
 	go func() {
-		resp, err := httpext.MakeRequest(c.moduleInstance.vu.Context(), state, req)
+		resp, err := httpext.MakeRequest(ctx, state, req)
 		callback(func() error {
+			unsubscribe()
+			cancel(nil)
 			if err != nil {
 				return reject(err)
 			}
 			c.processResponse(resp, req.ResponseType)
-			return resolve(c.responseFromHTTPext(resp))
+			res := c.responseFromHTTPext(resp)
+			if err := ic.interceptResponse(res); err != nil {
+				return reject(err)
+			}
+			return resolve(res)
 		})
 	}()
 
 	return p, nil
 }
 
+// requestSignal returns the AbortSignal from the signal param, or nil if it
+// isn't set.
+func requestSignal(rt *sobek.Runtime, params sobek.Value) (*abort.Signal, error) {
+	if common.IsNullish(params) {
+		return nil, nil //nolint:nilnil
+	}
+	v := params.ToObject(rt).Get("signal")
+	if common.IsNullish(v) {
+		return nil, nil //nolint:nilnil
+	}
+	signal, err := abort.FromValue(rt, v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signal param: %w", err)
+	}
+	return signal, nil
+}
+
 // processResponse stores the body as an ArrayBuffer if indicated by
 // respType. This is done here instead of in httpext.readResponseBody to avoid
 // a reverse dependency on js/common or sobek.
@@ -152,6 +228,22 @@ func (c *Client) parseRequest(
 	if urlJSValue, ok := reqURL.(sobek.Value); ok {
 		reqURL = urlJSValue.Export()
 	}
+	defaults := state.RequestDefaults
+	if defaults != nil {
+		// the relative URLs are resolved against the base URL of the scenario
+		switch tu := reqURL.(type) {
+		case string:
+			reqURL = defaults.ResolveURL(tu)
+		case httpext.URL:
+			if tu.GetURL() != nil && !tu.GetURL().IsAbs() {
+				resolved, err := httpext.NewURL(defaults.ResolveURL(tu.URL), defaults.ResolveURL(tu.Name))
+				if err != nil {
+					return nil, err
+				}
+				reqURL = resolved
+			}
+		}
+	}
 	u, err := httpext.ToURL(reqURL)
 	if err != nil {
 		return nil, err
@@ -171,6 +263,19 @@ func (c *Client) parseRequest(
 		ResponseCallback: c.responseCallback,
 		TagsAndMeta:      c.moduleInstance.vu.State().Tags.GetCurrentValues(),
 	}
+	timeoutSet := false
+	if defaults != nil {
+		if defaults.Timeout.Valid {
+			result.Timeout = defaults.Timeout.TimeDuration()
+			timeoutSet = true
+		}
+		for key, value := range defaults.Headers {
+			if strings.ToLower(key) == "host" {
+				result.Req.Host = value
+			}
+			result.Req.Header.Set(key, value)
+		}
+	}
 
 	if state.Options.DiscardResponseBodies.Bool {
 		result.ResponseType = httpext.ResponseTypeNone
@@ -375,6 +480,22 @@ func (c *Client) parseRequest(
 					return nil, fmt.Errorf("invalid timeout value: %w", err)
 				}
 				result.Timeout = t
+				timeoutSet = true
+			case "timeouts":
+				timeouts, err := parseRequestTimeouts(rt, params.Get(k))
+				if err != nil {
+					return nil, err
+				}
+				result.Timeouts = timeouts
+			case "sla":
+				sla, err := types.GetDurationValue(params.Get(k).Export())
+				if err != nil {
+					return nil, fmt.Errorf("invalid sla value: %w", err)
+				}
+				if sla <= 0 {
+					return nil, errors.New("invalid sla value: it must be greater than 0")
+				}
+				result.SLA = sla
 			case "throw":
 				result.Throw = params.Get(k).ToBoolean()
 			case "responseType":
@@ -383,6 +504,18 @@ func (c *Client) parseRequest(
 					return nil, err
 				}
 				result.ResponseType = responseType
+			case "resolveTo":
+				resolveTo := new(types.Host)
+				if err := resolveTo.UnmarshalText([]byte(params.Get(k).String())); err != nil {
+					return nil, fmt.Errorf("invalid resolveTo value, it must be an IP with an optional port: %w", err)
+				}
+				result.ResolveTo = resolveTo
+			case "virtualHost":
+				vhost := params.Get(k).String()
+				if vhost == "" || strings.ContainsAny(vhost, "/:@ ") {
+					return nil, fmt.Errorf("invalid virtualHost value %q, it must be a host name without a port", vhost)
+				}
+				result.VirtualHost = vhost
 			case "responseCallback":
 				v := params.Get(k).Export()
 				if v == nil {
@@ -399,68 +532,145 @@ func (c *Client) parseRequest(
 	if result.ActiveJar != nil {
 		httpext.SetRequestCookies(result.Req, result.ActiveJar, result.Cookies)
 	}
+	if err := c.moduleInstance.authenticate(result); err != nil {
+		return nil, err
+	}
+	if result.Timeouts != nil && !timeoutSet {
+		// the phases have their own timeouts, so the default overall one,
+		// which would cut off long bodies, doesn't apply
+		result.Timeout = 0
+	}
+	if result.SLA > 0 && !httpext.HasSLAName(result) {
+		return nil, errors.New("the sla param requires a name tag or a URL made with http.url, " +
+			"so that there isn't a check for every URL")
+	}
 
 	return result, nil
 }
 
+// parseRequestTimeouts parses the timeouts param, like
+// { connect: "2s", headers: "5s", body: "30s" }.
+func parseRequestTimeouts(rt *sobek.Runtime, v sobek.Value) (*httpext.RequestTimeouts, error) {
+	if common.IsNullish(v) {
+		return nil, nil //nolint:nilnil
+	}
+	obj := v.ToObject(rt)
+	timeouts := &httpext.RequestTimeouts{}
+	for _, key := range obj.Keys() {
+		var dst *time.Duration
+		switch key {
+		case httpext.PhaseConnect:
+			dst = &timeouts.Connect
+		case httpext.PhaseTLSHandshake:
+			dst = &timeouts.TLSHandshake
+		case httpext.PhaseHeaders:
+			dst = &timeouts.Headers
+		case httpext.PhaseBody:
+			dst = &timeouts.Body
+		default:
+			return nil, fmt.Errorf("invalid timeouts value: unknown phase %q, the phases are %s, %s, %s and %s",
+				key, httpext.PhaseConnect, httpext.PhaseTLSHandshake, httpext.PhaseHeaders, httpext.PhaseBody)
+		}
+		t, err := types.GetDurationValue(obj.Get(key).Export())
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeouts value of %s: %w", key, err)
+		}
+		if t <= 0 {
+			return nil, fmt.Errorf("invalid timeouts value of %s: it must be greater than 0", key)
+		}
+		*dst = t
+	}
+	return timeouts, nil
+}
+
+// batchInterception is the interception of a request of a batch, with the
+// response that is passed to the response hooks.
+type batchInterception struct {
+	ic  *interception
+	res *Response
+}
+
+// prepareBatchRequest parses a request of a batch, and returns the response of
+// the request, which is filled in once it's made. The request is nil if an
+// interceptor skipped it.
+func (c *Client) prepareBatchRequest(key interface{}, val interface{}) (
+	*httpext.BatchParsedHTTPRequest, *Response, *batchInterception, error,
+) {
+	resp := httpext.NewResponse()
+	parsedReq, ic, err := c.parseBatchRequest(key, val)
+	if err == nil && ic != nil && ic.response != nil {
+		var skipped *httpext.Response
+		if skipped, err = responseFromInterceptor(c.moduleInstance.vu.Runtime(), parsedReq, ic.response); err == nil {
+			c.processResponse(skipped, parsedReq.ResponseType)
+			res := c.responseFromHTTPext(skipped)
+			return nil, res, &batchInterception{ic: ic, res: res}, nil
+		}
+	}
+	if err != nil {
+		resp.Error = err.Error()
+		var k6e httpext.K6Error
+		if errors.As(err, &k6e) {
+			resp.ErrorCode = int(k6e.Code)
+		}
+		return nil, c.responseFromHTTPext(resp), nil, err
+	}
+
+	batchReq := &httpext.BatchParsedHTTPRequest{ParsedHTTPRequest: parsedReq, Response: resp}
+	res := c.responseFromHTTPext(resp)
+	if ic == nil {
+		return batchReq, res, nil, nil
+	}
+	return batchReq, res, &batchInterception{ic: ic, res: res}, nil
+}
+
 func (c *Client) prepareBatchArray(requests []interface{}) (
-	[]httpext.BatchParsedHTTPRequest, []*Response, error,
+	[]httpext.BatchParsedHTTPRequest, []*Response, []*batchInterception, error,
 ) {
 	reqCount := len(requests)
-	batchReqs := make([]httpext.BatchParsedHTTPRequest, reqCount)
+	batchReqs := make([]httpext.BatchParsedHTTPRequest, 0, reqCount)
 	results := make([]*Response, reqCount)
+	var interceptions []*batchInterception
 
 	for i, req := range requests {
-		resp := httpext.NewResponse()
-		parsedReq, err := c.parseBatchRequest(i, req)
+		batchReq, res, bi, err := c.prepareBatchRequest(i, req)
+		results[i] = res
 		if err != nil {
-			resp.Error = err.Error()
-			var k6e httpext.K6Error
-			if errors.As(err, &k6e) {
-				resp.ErrorCode = int(k6e.Code)
-			}
-			results[i] = c.responseFromHTTPext(resp)
-			return batchReqs, results, err
+			return batchReqs, results, nil, err
+		}
+		if batchReq != nil {
+			batchReqs = append(batchReqs, *batchReq)
 		}
-		batchReqs[i] = httpext.BatchParsedHTTPRequest{
-			ParsedHTTPRequest: parsedReq,
-			Response:          resp,
+		if bi != nil {
+			interceptions = append(interceptions, bi)
 		}
-		results[i] = c.responseFromHTTPext(resp)
 	}
 
-	return batchReqs, results, nil
+	return batchReqs, results, interceptions, nil
 }
 
 func (c *Client) prepareBatchObject(requests map[string]interface{}) (
-	[]httpext.BatchParsedHTTPRequest, map[string]*Response, error,
+	[]httpext.BatchParsedHTTPRequest, map[string]*Response, []*batchInterception, error,
 ) {
 	reqCount := len(requests)
-	batchReqs := make([]httpext.BatchParsedHTTPRequest, reqCount)
+	batchReqs := make([]httpext.BatchParsedHTTPRequest, 0, reqCount)
 	results := make(map[string]*Response, reqCount)
+	var interceptions []*batchInterception
 
-	i := 0
 	for key, req := range requests {
-		resp := httpext.NewResponse()
-		parsedReq, err := c.parseBatchRequest(key, req)
+		batchReq, res, bi, err := c.prepareBatchRequest(key, req)
+		results[key] = res
 		if err != nil {
-			resp.Error = err.Error()
-			var k6e httpext.K6Error
-			if errors.As(err, &k6e) {
-				resp.ErrorCode = int(k6e.Code)
-			}
-			results[key] = c.responseFromHTTPext(resp)
-			return batchReqs, results, err
+			return batchReqs, results, nil, err
+		}
+		if batchReq != nil {
+			batchReqs = append(batchReqs, *batchReq)
 		}
-		batchReqs[i] = httpext.BatchParsedHTTPRequest{
-			ParsedHTTPRequest: parsedReq,
-			Response:          resp,
+		if bi != nil {
+			interceptions = append(interceptions, bi)
 		}
-		results[key] = c.responseFromHTTPext(resp)
-		i++
 	}
 
-	return batchReqs, results, nil
+	return batchReqs, results, interceptions, nil
 }
 
 // Batch makes multiple simultaneous HTTP requests. The provideds reqsV should be an array of request
@@ -477,22 +687,25 @@ func (c *Client) Batch(reqsV ...sobek.Value) (interface{}, error) {
 		return nil, fmt.Errorf("http.batch() accepts only an array or an object of requests")
 	}
 	var (
-		err       error
-		batchReqs []httpext.BatchParsedHTTPRequest
-		results   interface{} // either []*Response or map[string]*Response
+		err           error
+		batchReqs     []httpext.BatchParsedHTTPRequest
+		results       interface{} // either []*Response or map[string]*Response
+		interceptions []*batchInterception
 	)
 
 	switch v := reqsV[0].Export().(type) {
 	case []interface{}:
-		batchReqs, results, err = c.prepareBatchArray(v)
+		batchReqs, results, interceptions, err = c.prepareBatchArray(v)
 	case map[string]interface{}:
-		batchReqs, results, err = c.prepareBatchObject(v)
+		batchReqs, results, interceptions, err = c.prepareBatchObject(v)
 	default:
 		return nil, fmt.Errorf("invalid http.batch() argument type %T", v)
 	}
 
 	if err != nil {
-		if state.Options.Throw.Bool {
+		// the exceptions of the interceptors are thrown like for the other requests
+		var exception *sobek.Exception
+		if state.Options.Throw.Bool || errors.As(err, &exception) {
 			return nil, err
 		}
 		state.Logger.WithField("error", err).Warn("A batch request failed")
@@ -515,10 +728,17 @@ func (c *Client) Batch(reqsV ...sobek.Value) (interface{}, error) {
 			c.processResponse(req.Response, req.ResponseType)
 		}
 	}
+	for _, bi := range interceptions {
+		if e := bi.ic.interceptResponse(bi.res); e != nil {
+			return nil, e
+		}
+	}
 	return results, err
 }
 
-func (c *Client) parseBatchRequest(key interface{}, val interface{}) (*httpext.ParsedHTTPRequest, error) {
+func (c *Client) parseBatchRequest(
+	key interface{}, val interface{},
+) (*httpext.ParsedHTTPRequest, *interception, error) {
 	var (
 		method       = http.MethodGet
 		ok           bool
@@ -532,11 +752,11 @@ func (c *Client) parseBatchRequest(key interface{}, val interface{}) (*httpext.P
 		// Handling of ["GET", "http://example.com/"]
 		dataLen := len(data)
 		if dataLen < 2 {
-			return nil, fmt.Errorf("invalid batch request '%#v'", data)
+			return nil, nil, fmt.Errorf("invalid batch request '%#v'", data)
 		}
 		method, ok = data[0].(string)
 		if !ok {
-			return nil, fmt.Errorf("invalid method type '%#v'", data[0])
+			return nil, nil, fmt.Errorf("invalid method type '%#v'", data[0])
 		}
 		reqURL = data[1]
 		if dataLen > 2 {
@@ -549,7 +769,7 @@ func (c *Client) parseBatchRequest(key interface{}, val interface{}) (*httpext.P
 	case map[string]interface{}:
 		// Handling of {method: "GET", url: "https://test.k6.io"}
 		if _, ok := data["url"]; !ok {
-			return nil, fmt.Errorf("batch request %v doesn't have a url key", key)
+			return nil, nil, fmt.Errorf("batch request %v doesn't have a url key", key)
 		}
 
 		reqURL = data["url"]
@@ -557,7 +777,7 @@ func (c *Client) parseBatchRequest(key interface{}, val interface{}) (*httpext.P
 
 		if newMethod, ok := data["method"]; ok {
 			if method, ok = newMethod.(string); !ok {
-				return nil, fmt.Errorf("invalid method type '%#v'", newMethod)
+				return nil, nil, fmt.Errorf("invalid method type '%#v'", newMethod)
 			}
 			method = strings.ToUpper(method)
 			if method == http.MethodGet || method == http.MethodHead {
@@ -572,7 +792,17 @@ func (c *Client) parseBatchRequest(key interface{}, val interface{}) (*httpext.P
 		reqURL = val
 	}
 
-	return c.parseRequest(method, reqURL, body, params)
+	if len(c.moduleInstance.interceptors) == 0 {
+		req, err := c.parseRequest(method, reqURL, body, params)
+		return req, nil, err
+	}
+	reqArgs := &requestArgs{method: method, url: rt.ToValue(reqURL), body: rt.ToValue(body), params: params}
+	ic, err := c.interceptRequest(reqArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := c.parseRequest(reqArgs.method, reqArgs.url, exportBody(reqArgs.body), reqArgs.params)
+	return req, ic, err
 }
 
 func requestContainsFile(data map[string]interface{}) bool {
@@ -5,6 +5,7 @@ import (
 	"compress/gzip"
 	"compress/zlib"
 	"context"
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -34,6 +35,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
 	"gopkg.in/guregu/null.v3"
 
 	"go.k6.io/k6/internal/lib/testutils"
@@ -263,9 +265,59 @@ func TestRequest(t *testing.T) {
 			if (res.status != 302) { throw new Error("wrong status: " + res.status) }
 			if (res.url != "HTTPBIN_URL/redirect/1") { throw new Error("incorrect URL: " + res.url) }
 			if (res.headers["Location"] != "/get") { throw new Error("incorrect Location header: " + res.headers["Location"]) }
+			if (res.redirect_chain.length != 0) { throw new Error("unexpected redirect chain: " + JSON.stringify(res.redirect_chain)) }
 			`))
 			assert.NoError(t, err)
 		})
+		t.Run("redirect chain", func(t *testing.T) {
+			_, err := rt.RunString(sr(`
+			var res = http.get("HTTPBIN_URL/redirect/2");
+			var chain = res.redirect_chain;
+			if (chain.length != 3) { throw new Error("wrong redirect chain length: " + chain.length) }
+			var expected = [
+				["HTTPBIN_URL/redirect/2", 302],
+				["HTTPBIN_URL/relative-redirect/1", 302],
+				["HTTPBIN_URL/get", 200],
+			];
+			for (var i = 0; i < expected.length; i++) {
+				if (chain[i].method != "GET") { throw new Error("wrong method of hop " + i + ": " + chain[i].method) }
+				if (chain[i].url != expected[i][0]) { throw new Error("wrong url of hop " + i + ": " + chain[i].url) }
+				if (chain[i].status != expected[i][1]) { throw new Error("wrong status of hop " + i + ": " + chain[i].status) }
+				if (!(chain[i].timings.duration > 0)) { throw new Error("missing duration of hop " + i) }
+			}
+			if (chain[2].timings.duration != res.timings.duration) { throw new Error("last hop timings differ from the response ones") }
+			`))
+			assert.NoError(t, err)
+		})
+		t.Run("redirect_hop tag", func(t *testing.T) {
+			oldOpts := state.Options
+			defer func() { state.Options = oldOpts }()
+			systemTags := *oldOpts.SystemTags
+			systemTags.Add(metrics.TagRedirectHop)
+			state.Options.SystemTags = &systemTags
+			metrics.GetBufferedSamples(samples)
+
+			_, err := rt.RunString(sr(`http.get("HTTPBIN_URL/redirect/2");`))
+			require.NoError(t, err)
+
+			hops := map[string]string{}
+			for _, container := range metrics.GetBufferedSamples(samples) {
+				for _, sample := range container.GetSamples() {
+					if sample.Metric.Name != metrics.HTTPReqsName {
+						continue
+					}
+					url, _ := sample.Tags.Get(metrics.TagURL.String())
+					hop, ok := sample.Tags.Get(metrics.TagRedirectHop.String())
+					require.True(t, ok)
+					hops[url] = hop
+				}
+			}
+			assert.Equal(t, map[string]string{
+				sr("HTTPBIN_URL/redirect/2"):          "0",
+				sr("HTTPBIN_URL/relative-redirect/1"): "1",
+				sr("HTTPBIN_URL/get"):                 "2",
+			}, hops)
+		})
 
 		t.Run("post body", func(t *testing.T) {
 			tb.Mux.HandleFunc("/post-redirect", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -325,6 +377,107 @@ func TestRequest(t *testing.T) {
 			assert.Nil(t, ts.hook.LastEntry())
 		})
 	})
+	t.Run("ResolveTo", func(t *testing.T) {
+		t.Run("override", func(t *testing.T) {
+			_, err := rt.RunString(sr(`
+			var res = http.get("http://unresolvable.invalid:HTTPBIN_PORT/get", { resolveTo: "HTTPBIN_IP" });
+			if (res.status != 200) { throw new Error("wrong status: " + res.status) }
+			if (res.resolution.source != "resolveTo") { throw new Error("wrong source: " + res.resolution.source) }
+			if (res.resolution.host != "unresolvable.invalid") { throw new Error("wrong host: " + res.resolution.host) }
+			if (res.resolution.address != "HTTPBIN_IP:HTTPBIN_PORT") {
+				throw new Error("wrong address: " + res.resolution.address)
+			}
+			`))
+			assert.NoError(t, err)
+		})
+		t.Run("port", func(t *testing.T) {
+			_, err := rt.RunString(sr(`
+			var res = http.get("http://unresolvable.invalid/get", { resolveTo: "HTTPBIN_IP:HTTPBIN_PORT" });
+			if (res.status != 200) { throw new Error("wrong status: " + res.status) }
+			`))
+			assert.NoError(t, err)
+		})
+		t.Run("hosts", func(t *testing.T) {
+			tb.HTTPTransport.CloseIdleConnections()
+			_, err := rt.RunString(sr(`
+			var res = http.get("HTTPBIN_URL/get");
+			if (res.resolution.source != "hosts") { throw new Error("wrong source: " + res.resolution.source) }
+			res = http.get("HTTPBIN_URL/get");
+			if (res.resolution.source !== "") { throw new Error("the connection wasn't reused") }
+			`))
+			assert.NoError(t, err)
+		})
+		t.Run("invalid", func(t *testing.T) {
+			_, err := rt.RunString(sr(`http.get("HTTPBIN_URL/get", { resolveTo: "httpbin.local" });`))
+			require.ErrorContains(t, err, "invalid resolveTo value, it must be an IP with an optional port")
+		})
+	})
+	t.Run("VirtualHost", func(t *testing.T) {
+		t.Run("hosts", func(t *testing.T) {
+			metrics.GetBufferedSamples(samples)
+			_, err := rt.RunString(sr(`
+			var res = http.get("http://unresolvable.invalid:HTTPBIN_PORT/headers", { virtualHost: "HTTPBIN_DOMAIN" });
+			if (res.status != 200) { throw new Error("wrong status: " + res.status) }
+			if (res.json().headers["Host"] != "HTTPBIN_DOMAIN") { throw new Error("wrong Host: " + res.body) }
+			if (res.resolution.source != "virtualHost") { throw new Error("wrong source: " + res.resolution.source) }
+			`))
+			require.NoError(t, err)
+			bufSamples := metrics.GetBufferedSamples(samples)
+			require.NotEmpty(t, bufSamples)
+			for _, sampleC := range bufSamples {
+				for _, sample := range sampleC.GetSamples() {
+					_, ok := sample.Tags.Get(metrics.TagVirtualHost.String())
+					assert.False(t, ok)
+				}
+			}
+		})
+		t.Run("virtual_host tag", func(t *testing.T) {
+			oldOpts := state.Options
+			defer func() { state.Options = oldOpts }()
+			systemTags := *oldOpts.SystemTags
+			systemTags.Add(metrics.TagVirtualHost)
+			state.Options.SystemTags = &systemTags
+			metrics.GetBufferedSamples(samples)
+
+			_, err := rt.RunString(sr(`http.get("http://unresolvable.invalid:HTTPBIN_PORT/get", { virtualHost: "HTTPBIN_DOMAIN" });`))
+			require.NoError(t, err)
+			bufSamples := metrics.GetBufferedSamples(samples)
+			require.NotEmpty(t, bufSamples)
+			for _, sampleC := range bufSamples {
+				for _, sample := range sampleC.GetSamples() {
+					vhost, ok := sample.Tags.Get(metrics.TagVirtualHost.String())
+					assert.True(t, ok)
+					assert.Equal(t, "httpbin.local", vhost)
+				}
+			}
+		})
+		t.Run("reuse", func(t *testing.T) {
+			state.IsolatedTransports = lib.NewIsolatedTransports()
+			defer func() {
+				state.IsolatedTransports.CloseIdleConnections()
+				state.IsolatedTransports = nil
+			}()
+			_, err := rt.RunString(sr(`
+			var res = http.get("http://unresolvable.invalid:HTTPBIN_PORT/get", { virtualHost: "HTTPBIN_DOMAIN" });
+			if (res.resolution.source != "virtualHost") { throw new Error("wrong source: " + res.resolution.source) }
+			res = http.get("http://unresolvable.invalid:HTTPBIN_PORT/get", { virtualHost: "HTTPBIN_DOMAIN" });
+			if (res.resolution.source !== "") { throw new Error("the connection wasn't reused") }
+			`))
+			require.NoError(t, err)
+		})
+		t.Run("tls", func(t *testing.T) {
+			_, err := rt.RunString(sr(`
+			var res = http.get("HTTPSBIN_IP_URL/headers", { virtualHost: "HTTPSBIN_DOMAIN" });
+			if (res.status != 200) { throw new Error("wrong status: " + res.status) }
+			if (res.json().headers["Host"] != "HTTPSBIN_DOMAIN") { throw new Error("wrong Host: " + res.body) }
+			`))
+			require.NoError(t, err)
+		})
+		t.Run("invalid", func(t *testing.T) {
+			_, err := rt.RunString(sr(`http.get("HTTPBIN_URL/get", { virtualHost: "HTTPBIN_DOMAIN:80" });`))
+			require.ErrorContains(t, err, "it must be a host name without a port")
+		})
+	})
 	t.Run("UserAgent", func(t *testing.T) {
 		_, err := rt.RunString(sr(`
 			var res = http.get("HTTPBIN_URL/headers");
@@ -1947,6 +2100,161 @@ func TestRedirectMetricTags(t *testing.T) {
 	checkTags(<-samples, expGETtags)
 }
 
+func TestRequestSLA(t *testing.T) {
+	t.Parallel()
+	ts := newTestCase(t)
+	tb := ts.tb
+	samples := ts.samples
+	rt := ts.runtime.VU.Runtime()
+
+	tb.Mux.HandleFunc("/slow", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	sr := tb.Replacer.Replace
+	_, err := rt.RunString(sr(`
+		http.get(http.url` + "`HTTPBIN_URL/get?id=${1}`" + `, { sla: "10s" });
+		http.get("HTTPBIN_URL/slow", { sla: 50, tags: { name: "slow" } });
+		http.get("HTTPBIN_URL/get");
+	`))
+	require.NoError(t, err)
+
+	checks := map[string]float64{}
+	for _, sc := range metrics.GetBufferedSamples(samples) {
+		for _, s := range sc.GetSamples() {
+			if s.Metric.Name != metrics.ChecksName {
+				continue
+			}
+			check, _ := s.Tags.Get("check")
+			name, _ := s.Tags.Get("name")
+			assert.Contains(t, check, name)
+			checks[check] = s.Value
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		sr("HTTPBIN_URL/get?id=${} responded within 10s"): 1,
+		"slow responded within 50ms":                      0,
+	}, checks)
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := rt.RunString(sr(`http.get("HTTPBIN_URL/get", { sla: "0s" });`))
+		require.ErrorContains(t, err, "invalid sla value: it must be greater than 0")
+		_, err = rt.RunString(sr(`http.get("HTTPBIN_URL/get", { sla: "fast" });`))
+		require.ErrorContains(t, err, "invalid sla value")
+		_, err = rt.RunString(sr(`http.get("HTTPBIN_URL/get", { sla: "10s" });`))
+		require.ErrorContains(t, err, "the sla param requires a name tag or a URL made with http.url")
+	})
+}
+
+func TestRequestTimeouts(t *testing.T) {
+	t.Parallel()
+	ts := newTestCase(t)
+	tb := ts.tb
+	rt := ts.runtime.VU.Runtime()
+
+	tb.Mux.HandleFunc("/slow-headers", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	tb.Mux.HandleFunc("/slow-body", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(300 * time.Millisecond)
+		_, _ = w.Write([]byte("done"))
+	}))
+
+	_, err := rt.RunString(tb.Replacer.Replace(`
+		let res = http.get("HTTPBIN_URL/slow-headers", { throw: false, timeouts: { headers: "100ms" } });
+		if (res.error_code !== 1050 || res.error.indexOf("the headers phase took longer than 100ms") < 0) {
+			throw new Error("the headers timeout wasn't applied: " + res.error_code + " " + res.error);
+		}
+
+		res = http.get("HTTPBIN_URL/slow-body", { throw: false, timeouts: { headers: "1s", body: "100ms" } });
+		if (res.error_code !== 1050 || res.error.indexOf("the body phase took longer than 100ms") < 0) {
+			throw new Error("the body timeout wasn't applied: " + res.error_code + " " + res.error);
+		}
+
+		res = http.get("HTTPBIN_URL/slow-body", { timeouts: { connect: "1s", headers: "1s", body: "1s" } });
+		if (res.status !== 200 || res.body !== "done") {
+			throw new Error("the request failed within its timeouts: " + res.error);
+		}
+
+		res = http.get("HTTPBIN_URL/slow-body", { throw: false, timeout: "100ms", timeouts: { body: "1s" } });
+		if (res.error_code !== 1050) {
+			throw new Error("the overall timeout wasn't applied: " + res.error_code);
+		}
+	`))
+	require.NoError(t, err)
+
+	_, err = rt.RunString(tb.Replacer.Replace(`http.get("HTTPBIN_URL/get", { timeouts: { dns: "1s" } });`))
+	require.ErrorContains(t, err, `invalid timeouts value: unknown phase "dns"`)
+
+	_, err = rt.RunString(tb.Replacer.Replace(`http.get("HTTPBIN_URL/get", { timeouts: { body: 0 } });`))
+	require.ErrorContains(t, err, "invalid timeouts value of body: it must be greater than 0")
+}
+
+func TestRequestDefaults(t *testing.T) {
+	t.Parallel()
+	ts := newTestCase(t)
+	tb := ts.tb
+	rt := ts.runtime.VU.Runtime()
+
+	tb.Mux.HandleFunc("/tenant/slow", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	sr := tb.Replacer.Replace
+	ts.runtime.VU.State().RequestDefaults = &lib.RequestDefaults{
+		BaseURL: null.StringFrom(sr("HTTPBIN_URL")),
+		Headers: map[string]string{"X-Tenant": "a", "X-Other": "default"},
+		Timeout: types.NullDurationFrom(50 * time.Millisecond),
+	}
+
+	_, err := rt.RunString(sr(`
+		let res = http.get("/headers", { headers: { "X-Other": "param" } });
+		if (res.url !== "HTTPBIN_URL/headers") {
+			throw new Error("unexpected URL " + res.url);
+		}
+		const headers = res.json().headers;
+		if (headers["X-Tenant"][0] !== "a" || headers["X-Other"][0] !== "param") {
+			throw new Error("unexpected headers " + JSON.stringify(headers));
+		}
+
+		res = http.get(http.url` + "`/anything/${1}`" + `);
+		if (res.url !== "HTTPBIN_URL/anything/1") {
+			throw new Error("unexpected URL " + res.url);
+		}
+
+		res = http.get("HTTPBIN_IP_URL/get");
+		if (res.status !== 200) {
+			throw new Error("the absolute URL failed with " + res.status);
+		}
+
+		res = http.get("/tenant/slow", { throw: false });
+		if (res.error_code !== 1050) {
+			throw new Error("the default timeout wasn't applied: " + res.error_code);
+		}
+		res = http.get("/tenant/slow", { timeout: "5s" });
+		if (res.status !== 200) {
+			throw new Error("the timeout param didn't override the default one: " + res.status);
+		}
+	`))
+	require.NoError(t, err)
+
+	var names []string
+	for _, sc := range metrics.GetBufferedSamples(ts.samples) {
+		for _, s := range sc.GetSamples() {
+			if s.Metric.Name == metrics.HTTPReqsName {
+				name, _ := s.Tags.Get("name")
+				names = append(names, name)
+			}
+		}
+	}
+	assert.Contains(t, names, sr("HTTPBIN_URL/anything/${}"))
+}
+
 func BenchmarkHandlingOfResponseBodies(b *testing.B) {
 	ts := newTestCase(b)
 	tb := ts.tb
@@ -2184,6 +2492,71 @@ func TestRequestAndBatchTLS(t *testing.T) {
 			assertRequestMetricsEmitted(t, metrics.GetBufferedSamples(samples), "GET", realURL, 200, "")
 		})
 	}
+	t.Run("peer_certificates", func(t *testing.T) {
+		t.Parallel()
+		ts := newTestCase(t)
+		samples := ts.samples
+		rt := ts.runtime.VU.Runtime()
+		state := ts.runtime.VU.State()
+		certPem, key := GenerateTLSCertificate(t, "certs.localhost", time.Now(), 10*24*time.Hour)
+		s, client := GetTestServerWithCertificate(t, certPem, key)
+
+		// staple an OCSP response, signed by the certificate itself
+		cert := s.TLS.Certificates[0]
+		signer, ok := cert.PrivateKey.(crypto.Signer)
+		require.True(t, ok)
+		staple, err := ocsp.CreateResponse(cert.Leaf, cert.Leaf, ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: cert.Leaf.SerialNumber,
+			ThisUpdate:   time.Now(),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}, signer)
+		require.NoError(t, err)
+		s.TLS.Certificates[0].OCSPStaple = staple
+
+		go func() {
+			_ = s.Config.Serve(s.Listener)
+		}()
+		t.Cleanup(func() {
+			require.NoError(t, s.Config.Close())
+		})
+		host, port, err := net.SplitHostPort(s.Listener.Addr().String())
+		require.NoError(t, err)
+		remote, err := types.NewHost(net.ParseIP(host), port)
+		require.NoError(t, err)
+		hosts, err := types.NewHosts(map[string]types.Host{"certs.localhost": *remote})
+		require.NoError(t, err)
+		state.Dialer = &netext.Dialer{Hosts: hosts}
+		state.Transport = client.Transport
+		state.TLSConfig = s.TLS
+		client.Transport.(*http.Transport).DialContext = state.Dialer.DialContext
+		_, err = rt.RunString(`
+			var res = http.get("https://certs.localhost/");
+			if (res.tls.version != res.tls_version) { throw new Error("wrong TLS version: " + res.tls.version); }
+			if (!res.tls.ocsp.stapled) { throw new Error("the OCSP response wasn't stapled"); }
+			if (res.tls.ocsp.status != http.OCSP_STATUS_GOOD) { throw new Error("wrong OCSP status: " + res.tls.ocsp.status); }
+			var certs = res.tls.peer_certificates;
+			if (certs.length != 1) { throw new Error("wrong number of certificates: " + certs.length); }
+			if (certs[0].subject != "O=Acme Co") { throw new Error("wrong subject: " + certs[0].subject); }
+			if (certs[0].dns_names[0] != "certs.localhost") { throw new Error("wrong DNS names: " + certs[0].dns_names); }
+			if (certs[0].fingerprint.length != 64) { throw new Error("wrong fingerprint: " + certs[0].fingerprint); }
+			if (certs[0].days_to_expiry <= 9 || certs[0].days_to_expiry > 10) {
+				throw new Error("wrong days to expiry: " + certs[0].days_to_expiry);
+			}
+		`)
+		require.NoError(t, err)
+
+		var days []float64
+		for _, container := range metrics.GetBufferedSamples(samples) {
+			for _, sample := range container.GetSamples() {
+				if sample.Metric.Name == metrics.HTTPReqTLSCertDaysToExpiryName {
+					days = append(days, sample.Value)
+				}
+			}
+		}
+		require.Len(t, days, 1)
+		assert.InDelta(t, 10, days[0], 0.01)
+	})
 	t.Run("ocsp_stapled_good", func(t *testing.T) {
 		t.Parallel()
 		t.Skip("this started failing on GHA") // see https://github.com/grafana/k6/issues/1275
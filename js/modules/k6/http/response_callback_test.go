@@ -1,9 +1,11 @@
 package http
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -542,3 +544,64 @@ func deleteSystemTag(state *lib.State, tag string) {
 	}
 	state.Options.SystemTags = metrics.ToSystemTagSet(tagsList)
 }
+
+func TestExpectedResponsesOption(t *testing.T) {
+	t.Parallel()
+	ts := newTestCase(t)
+	tb := ts.tb
+	samples := ts.samples
+	rt := ts.runtime.VU.Runtime()
+	state := ts.runtime.VU.State()
+	sr := tb.Replacer.Replace
+
+	var rules lib.ExpectedResponseRules
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"method": "DELETE", "url": "/status/404$", "statuses": [404]},
+		{"url": "/status/4\\d\\d$", "statuses": [{"min": 200, "max": 299}, 418]}
+	]`), &rules))
+	state.Options.ExpectedResponses = rules
+
+	request := func(method, url string) []metrics.Sample {
+		_, err := rt.RunString(sr(fmt.Sprintf(`http.request(%q, %q);`, method, url)))
+		require.NoError(t, err)
+
+		var failedSamples []metrics.Sample
+		for _, container := range metrics.GetBufferedSamples(samples) {
+			for _, sample := range container.GetSamples() {
+				if sample.Metric.Name == metrics.HTTPReqFailedName {
+					failedSamples = append(failedSamples, sample)
+				}
+			}
+		}
+		return failedSamples
+	}
+
+	cases := []struct {
+		method, url string
+		failed      float64
+	}{
+		{method: "DELETE", url: "HTTPBIN_URL/status/404", failed: 0},
+		{method: "GET", url: "HTTPBIN_URL/status/404", failed: 1},
+		{method: "GET", url: "HTTPBIN_URL/status/418", failed: 0},
+		{method: "GET", url: "HTTPBIN_URL/status/200", failed: 0},
+		{method: "GET", url: "HTTPBIN_URL/status/500", failed: 1},
+	}
+	for _, tc := range cases {
+		failedSamples := request(tc.method, tc.url)
+		require.Len(t, failedSamples, 1)
+		assert.Equal(t, tc.failed, failedSamples[0].Value, "%s %s", tc.method, tc.url)
+		expected, _ := failedSamples[0].Tags.Get(metrics.TagExpectedResponse.String())
+		assert.Equal(t, strconv.FormatBool(tc.failed == 0), expected, "%s %s", tc.method, tc.url)
+	}
+
+	// the rules still classify the responses without a response callback
+	_, err := rt.RunString(`http.setResponseCallback(null);`)
+	require.NoError(t, err)
+	failedSamples := request("GET", "HTTPBIN_URL/status/418")
+	require.Len(t, failedSamples, 1)
+	assert.Equal(t, 0.0, failedSamples[0].Value)
+	failedSamples = request("GET", "HTTPBIN_URL/status/404")
+	require.Len(t, failedSamples, 1)
+	assert.Equal(t, 1.0, failedSamples[0].Value)
+	assert.Empty(t, request("GET", "HTTPBIN_URL/status/500"))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.k6.io/k6/internal/js/compiler"
 	"go.k6.io/k6/internal/js/eventloop"
+	"go.k6.io/k6/internal/js/tc55/abort"
 	"go.k6.io/k6/internal/js/tc55/timers"
 
 	"go.k6.io/k6/internal/js/modules/k6/webcrypto"
@@ -42,9 +43,11 @@ func NewRuntime(t testing.TB) *Runtime {
 	vu.RuntimeField.SetFieldNameMapper(common.FieldNameMapper{})
 	vu.InitEnvField = &common.InitEnvironment{
 		TestPreInitState: &lib.TestPreInitState{
-			Logger:   testutils.NewLogger(t),
-			Registry: metrics.NewRegistry(),
-			Usage:    usage.New(),
+			Logger:      testutils.NewLogger(t),
+			Registry:    metrics.NewRegistry(),
+			Usage:       usage.New(),
+			RunMetadata: lib.NewRunMetadata(nil),
+			ConnStats:   lib.NewConnStats(),
 		},
 		CWD: new(url.URL),
 	}
@@ -58,6 +61,7 @@ func NewRuntime(t testing.TB) *Runtime {
 		BuiltinMetrics: metrics.RegisterBuiltinMetrics(vu.InitEnvField.Registry),
 	}
 	require.NoError(t, timers.SetupGlobally(vu))
+	require.NoError(t, abort.SetupGlobally(vu))
 	require.NoError(t, webcrypto.SetupGlobally(vu))
 	// let's cancel again in case it has changed
 	t.Cleanup(func() { result.CancelContext() })
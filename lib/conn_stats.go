@@ -0,0 +1,135 @@
+package lib
+
+import (
+	"sync"
+	"time"
+
+	"go.k6.io/k6/metrics"
+)
+
+// ConnStats keeps track of the connections opened by the VUs of a test run, so
+// that the numbers of active and idle connections per host, of ephemeral ports
+// in use and of opened and closed connections can be emitted as metrics. It's
+// safe for concurrent use and all of its methods are no-ops on a nil ConnStats.
+type ConnStats struct {
+	mu             sync.Mutex
+	hosts          map[string]*hostConns
+	opened, closed int64
+}
+
+type hostConns struct {
+	active, idle int64
+}
+
+// NewConnStats returns a new ConnStats without any connections.
+func NewConnStats() *ConnStats {
+	return &ConnStats{hosts: make(map[string]*hostConns)}
+}
+
+func (cs *ConnStats) host(host string) *hostConns {
+	hc, ok := cs.hosts[host]
+	if !ok {
+		hc = &hostConns{}
+		cs.hosts[host] = hc
+	}
+	return hc
+}
+
+// Open adds an active connection to the host.
+func (cs *ConnStats) Open(host string) {
+	if cs == nil {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.host(host).active++
+	cs.opened++
+}
+
+// Close removes a connection to the host, that was idle or active.
+func (cs *ConnStats) Close(host string, idle bool) {
+	if cs == nil {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	hc := cs.host(host)
+	if idle {
+		hc.idle--
+	} else {
+		hc.active--
+	}
+	cs.closed++
+}
+
+// SetIdle moves a connection to the host from active to idle, or the other way
+// around if idle is false.
+func (cs *ConnStats) SetIdle(host string, idle bool) {
+	if cs == nil {
+		return
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	hc := cs.host(host)
+	if idle {
+		hc.active--
+		hc.idle++
+	} else {
+		hc.idle--
+		hc.active++
+	}
+}
+
+// Samples returns the samples of the connection metrics at the provided time.
+// The opened and closed connections are the ones since the previous call, and
+// every open connection is counted as an ephemeral port in use. The active and
+// idle connections are per host if the remote_host system tag is enabled.
+func (cs *ConnStats) Samples(
+	t time.Time, tags *metrics.TagSet, systemTags *metrics.SystemTagSet, builtinMetrics *metrics.BuiltinMetrics,
+) []metrics.Sample {
+	if cs == nil {
+		return nil
+	}
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	sample := func(m *metrics.Metric, tags *metrics.TagSet, value int64) metrics.Sample {
+		return metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: m, Tags: tags},
+			Time:       t,
+			Value:      float64(value),
+		}
+	}
+
+	perHost := systemTags.Has(metrics.TagRemoteHost)
+	samples := make([]metrics.Sample, 0, 2*len(cs.hosts)+3)
+	var active, idle int64
+	for host, hc := range cs.hosts {
+		if perHost {
+			hostTags := tags.With(metrics.TagRemoteHost.String(), host)
+			samples = append(samples,
+				sample(builtinMetrics.NetConnsActive, hostTags, hc.active),
+				sample(builtinMetrics.NetConnsIdle, hostTags, hc.idle),
+			)
+		}
+		active += hc.active
+		idle += hc.idle
+		// the hosts without connections are reported once with zeros
+		if hc.active+hc.idle == 0 {
+			delete(cs.hosts, host)
+		}
+	}
+	if !perHost {
+		samples = append(samples,
+			sample(builtinMetrics.NetConnsActive, tags, active),
+			sample(builtinMetrics.NetConnsIdle, tags, idle),
+		)
+	}
+	samples = append(samples,
+		sample(builtinMetrics.NetEphemeralPorts, tags, active+idle),
+		sample(builtinMetrics.NetConnsOpened, tags, cs.opened),
+		sample(builtinMetrics.NetConnsClosed, tags, cs.closed),
+	)
+	cs.opened, cs.closed = 0, 0
+	return samples
+}
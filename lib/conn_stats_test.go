@@ -0,0 +1,79 @@
+package lib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/metrics"
+)
+
+func TestConnStats(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	builtinMetrics := metrics.RegisterBuiltinMetrics(registry)
+	tags := registry.RootTagSet().With("run", "1")
+
+	values := func(samples []metrics.Sample) map[string]float64 {
+		v := make(map[string]float64, len(samples))
+		for _, s := range samples {
+			name := s.Metric.Name
+			if host, ok := s.Tags.Get("remote_host"); ok {
+				name += "{" + host + "}"
+			}
+			v[name] = s.Value
+		}
+		return v
+	}
+
+	cs := NewConnStats()
+	cs.Open("a:80")
+	cs.Open("a:80")
+	cs.Open("b:443")
+	cs.SetIdle("a:80", true)
+	cs.Close("b:443", false)
+
+	samples := cs.Samples(time.Now(), tags, &metrics.DefaultSystemTagSet, builtinMetrics)
+	for _, s := range samples {
+		run, ok := s.Tags.Get("run")
+		require.True(t, ok)
+		assert.Equal(t, "1", run)
+	}
+	assert.Equal(t, map[string]float64{
+		"net_conns_active{a:80}":  1,
+		"net_conns_idle{a:80}":    1,
+		"net_conns_active{b:443}": 0,
+		"net_conns_idle{b:443}":   0,
+		"net_ephemeral_ports":     2,
+		"net_conns_opened":        3,
+		"net_conns_closed":        1,
+	}, values(samples))
+
+	cs.SetIdle("a:80", false)
+	assert.Equal(t, map[string]float64{
+		"net_conns_active{a:80}": 2,
+		"net_conns_idle{a:80}":   0,
+		"net_ephemeral_ports":    2,
+		"net_conns_opened":       0,
+		"net_conns_closed":       0,
+	}, values(cs.Samples(time.Now(), tags, &metrics.DefaultSystemTagSet, builtinMetrics)))
+
+	cs.Open("b:443")
+	systemTags := metrics.DefaultSystemTagSet &^ metrics.SystemTagSet(metrics.TagRemoteHost)
+	assert.Equal(t, map[string]float64{
+		"net_conns_active":    3,
+		"net_conns_idle":      0,
+		"net_ephemeral_ports": 3,
+		"net_conns_opened":    1,
+		"net_conns_closed":    0,
+	}, values(cs.Samples(time.Now(), tags, &systemTags, builtinMetrics)))
+
+	var disabled *ConnStats
+	disabled.Open("a:80")
+	disabled.SetIdle("a:80", true)
+	disabled.Close("a:80", true)
+	assert.Nil(t, disabled.Samples(time.Now(), tags, &metrics.DefaultSystemTagSet, builtinMetrics))
+}
@@ -0,0 +1,54 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"gopkg.in/guregu/null.v3"
+)
+
+// ErrorBudget declares how many failures a test run tolerates before it's
+// marked as failed, separately from the thresholds:
+//   - failedIterations: how many iterations can fail with an error or time out.
+//   - checks: the percentage, from 0 to 100, of the failed values of every
+//     check, by its name, that's tolerated.
+//
+// The budget is evaluated continuously during the test run. When it's
+// exhausted, the test is aborted if abortOnExhausted is enabled, otherwise
+// it's marked as failed when it finishes.
+type ErrorBudget struct {
+	FailedIterations null.Int           `json:"failedIterations"`
+	Checks           map[string]float64 `json:"checks,omitempty"`
+	AbortOnExhausted null.Bool          `json:"abortOnExhausted"`
+}
+
+// Validate checks that the budget has something to tolerate and that its
+// limits are valid.
+func (b ErrorBudget) Validate() error {
+	if !b.FailedIterations.Valid && len(b.Checks) == 0 {
+		return errors.New("the errorBudget requires failedIterations or checks")
+	}
+	if b.FailedIterations.Int64 < 0 {
+		return errors.New("the errorBudget's failedIterations can't be negative")
+	}
+	for name, percent := range b.Checks {
+		if name == "" {
+			return errors.New("the errorBudget's checks can't have an empty name")
+		}
+		if percent < 0 || percent > 100 {
+			return fmt.Errorf("the errorBudget of the check %q should be a percentage between 0 and 100", name)
+		}
+	}
+	return nil
+}
+
+// CheckNames returns the sorted names of the checks of the budget.
+func (b ErrorBudget) CheckNames() []string {
+	names := make([]string, 0, len(b.Checks))
+	for name := range b.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
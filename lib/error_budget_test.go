@@ -0,0 +1,37 @@
+package lib
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorBudgetValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`{"failedIterations":10}`:                        "",
+		`{"checks":{"status is 200":5}}`:                 "",
+		`{"failedIterations":0,"abortOnExhausted":true}`: "",
+		`{"abortOnExhausted":true}`:                      "the errorBudget requires failedIterations or checks",
+		`{"failedIterations":-1}`:                        "the errorBudget's failedIterations can't be negative",
+		`{"checks":{"":5}}`:                              "the errorBudget's checks can't have an empty name",
+		`{"checks":{"status is 200":101}}`:               `the errorBudget of the check "status is 200" should be a percentage`,
+		`{"failedIterations":1,"checks":{"is ok":-0.5}}`: `the errorBudget of the check "is ok" should be a percentage`,
+	}
+	for data, want := range tests {
+		t.Run(data, func(t *testing.T) {
+			t.Parallel()
+
+			var b ErrorBudget
+			require.NoError(t, json.Unmarshal([]byte(data), &b))
+			err := b.Validate()
+			if want == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
@@ -142,6 +142,12 @@ type ExecutionState struct {
 	// simplification of the used atomic arithmetic operations.
 	activeVUs *int64
 
+	// The number of the VUs that are currently activated for every scenario,
+	// by its name, as *int64 values. Unlike activeVUs, the VUs of the
+	// arrival-rate executors are counted while they wait for their next
+	// iteration too.
+	scenarioActiveVUs sync.Map
+
 	// The total number of full (i.e uninterrupted) iterations that have been
 	// completed so far.
 	fullIterationsCount *uint64
@@ -153,6 +159,10 @@ type ExecutionState struct {
 	// API, etc.
 	interruptedIterationsCount *uint64
 
+	// A counter of the iterations that failed with an error or timed out,
+	// they are also counted as full or interrupted ones.
+	failedIterationsCount *uint64
+
 	// A machine-readable indicator in which the current state of the test
 	// execution is currently stored. Useful for the REST API and external
 	// observability of the k6 test run progress.
@@ -225,6 +235,7 @@ func NewExecutionState(
 		activeVUs:                  new(int64),
 		fullIterationsCount:        new(uint64),
 		interruptedIterationsCount: new(uint64),
+		failedIterationsCount:      new(uint64),
 		startTime:                  new(int64),
 		endTime:                    new(int64),
 		currentPauseTime:           new(int64),
@@ -278,6 +289,27 @@ func (es *ExecutionState) ModCurrentlyActiveVUsCount(mod int64) int64 {
 	return atomic.AddInt64(es.activeVUs, mod)
 }
 
+// GetScenarioActiveVUsCount returns the number of VUs that are currently
+// activated for the given scenario.
+//
+// IMPORTANT: for UI/information purposes only, don't use for synchronization.
+func (es *ExecutionState) GetScenarioActiveVUsCount(scenario string) int64 {
+	count, ok := es.scenarioActiveVUs.Load(scenario)
+	if !ok {
+		return 0
+	}
+	return atomic.LoadInt64(count.(*int64)) //nolint:forcetypeassert
+}
+
+// ModScenarioActiveVUsCount changes the number of currently activated VUs of
+// the given scenario.
+//
+// IMPORTANT: for UI/information purposes only, don't use for synchronization.
+func (es *ExecutionState) ModScenarioActiveVUsCount(scenario string, mod int64) int64 {
+	count, _ := es.scenarioActiveVUs.LoadOrStore(scenario, new(int64))
+	return atomic.AddInt64(count.(*int64), mod) //nolint:forcetypeassert
+}
+
 // GetFullIterationCount returns the total of full (i.e uninterrupted) iterations
 // that have been completed so far.
 //
@@ -310,6 +342,18 @@ func (es *ExecutionState) AddInterruptedIterations(count uint64) uint64 {
 	return atomic.AddUint64(es.interruptedIterationsCount, count)
 }
 
+// GetFailedIterationCount returns the total of the iterations that failed with
+// an error or timed out so far.
+func (es *ExecutionState) GetFailedIterationCount() uint64 {
+	return atomic.LoadUint64(es.failedIterationsCount)
+}
+
+// AddFailedIterations increments the number of failed iterations by the
+// provided amount.
+func (es *ExecutionState) AddFailedIterations(count uint64) uint64 {
+	return atomic.AddUint64(es.failedIterationsCount, count)
+}
+
 // SetExecutionStatus changes the current execution status to the supplied value
 // and returns the current value.
 func (es *ExecutionState) SetExecutionStatus(newStatus ExecutionStatus) (oldStatus ExecutionStatus) {
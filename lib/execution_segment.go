@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -194,6 +195,55 @@ func (es *ExecutionSegment) Split(numParts int64) ([]*ExecutionSegment, error) {
 	return results, nil
 }
 
+// NewInstanceExecutionSegment returns the execution segment of the instance
+// with the given 1-based index, when the whole test is evenly split between
+// totalInstances instances. It also returns the full execution segment sequence
+// that all of the instances should use.
+func NewInstanceExecutionSegment(index, totalInstances int64) (
+	*ExecutionSegment, ExecutionSegmentSequence, error,
+) {
+	if totalInstances < 1 {
+		return nil, nil, fmt.Errorf("the total number of instances must be at least 1, %d received", totalInstances)
+	}
+	if index < 1 || index > totalInstances {
+		return nil, nil, fmt.Errorf(
+			"the instance index must be between 1 and %d, %d received", totalInstances, index,
+		)
+	}
+	var full *ExecutionSegment
+	segments, err := full.Split(totalInstances)
+	if err != nil {
+		return nil, nil, err
+	}
+	sequence, err := NewExecutionSegmentSequence(segments...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return segments[index-1], sequence, nil
+}
+
+// NewInstanceExecutionSegmentFromString parses values in the `index/total`
+// format, e.g. `3/10` for the third out of ten instances, and returns the
+// corresponding execution segment and execution segment sequence. See
+// NewInstanceExecutionSegment() for more details.
+func NewInstanceExecutionSegmentFromString(instance string) (
+	*ExecutionSegment, ExecutionSegmentSequence, error,
+) {
+	indexStr, totalStr, ok := strings.Cut(instance, "/")
+	if !ok {
+		return nil, nil, fmt.Errorf("the instance '%s' should be in the 'index/total' format, e.g. '3/10'", instance)
+	}
+	index, err := strconv.ParseInt(strings.TrimSpace(indexStr), 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid instance index '%s': %w", indexStr, err)
+	}
+	total, err := strconv.ParseInt(strings.TrimSpace(totalStr), 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid total number of instances '%s': %w", totalStr, err)
+	}
+	return NewInstanceExecutionSegment(index, total)
+}
+
 // Equal returns true only if the two execution segments have the same from and
 // to values.
 func (es *ExecutionSegment) Equal(other *ExecutionSegment) bool {
@@ -175,6 +175,28 @@ func TestExecutionSegmentSplit(t *testing.T) {
 	assert.Equal(t, "7/16:1/2", segments[3].String())
 }
 
+func TestNewInstanceExecutionSegment(t *testing.T) {
+	t.Parallel()
+
+	segment, sequence, err := NewInstanceExecutionSegmentFromString("3/10")
+	require.NoError(t, err)
+	assert.Equal(t, "1/5:3/10", segment.String())
+	assert.Equal(t, "0,1/10,1/5,3/10,2/5,1/2,3/5,7/10,4/5,9/10,1", sequence.String())
+	pos, err := sequence.FindSegmentPosition(segment)
+	require.NoError(t, err)
+	assert.Equal(t, 2, pos)
+
+	segment, sequence, err = NewInstanceExecutionSegment(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "0:1", segment.String())
+	assert.Equal(t, "0,1", sequence.String())
+
+	for _, instance := range []string{"", "3", "a/10", "3/b", "0/10", "11/10", "1/0", "-1/3"} {
+		_, _, err = NewInstanceExecutionSegmentFromString(instance)
+		assert.Error(t, err, instance)
+	}
+}
+
 func TestExecutionSegmentFailures(t *testing.T) {
 	t.Parallel()
 	es := new(ExecutionSegment)
@@ -0,0 +1,104 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.k6.io/k6/metrics"
+)
+
+// arrivalRateVUsEmitInterval is how often the VU allocation of the
+// arrival-rate executors is emitted.
+const arrivalRateVUsEmitInterval = time.Second
+
+// Reasons for dropping the iterations of the arrival-rate executors, in the
+// reason tag of the dropped_iterations metric.
+const (
+	droppedNoFreeVU = "no_free_vu" // all VUs were busy, but more could be initialized
+	droppedMaxVUs   = "max_vus"    // all VUs were busy and maxVUs were already initialized
+)
+
+// arrivalRateVUs keeps track of the VUs of an arrival-rate executor and emits
+// how many are allocated, busy and pending initialization, so that
+// preAllocatedVUs and maxVUs can be tuned.
+type arrivalRateVUs struct {
+	pool           *activeVUPool
+	builtinMetrics *metrics.BuiltinMetrics
+	tags           *metrics.TagSet
+
+	allocated atomic.Uint64 // activated VUs
+	pending   atomic.Int64  // unplanned VUs requested for iterations without a free VU
+}
+
+func newArrivalRateVUs(pool *activeVUPool, builtinMetrics *metrics.BuiltinMetrics, tags *metrics.TagSet) *arrivalRateVUs {
+	return &arrivalRateVUs{pool: pool, builtinMetrics: builtinMetrics, tags: tags}
+}
+
+// dropIteration emits a dropped iteration, with the reason why it was dropped.
+func (v *arrivalRateVUs) dropIteration(ctx context.Context, out chan<- metrics.SampleContainer, maxVUsReached bool) {
+	reason := droppedNoFreeVU
+	if maxVUsReached {
+		reason = droppedMaxVUs
+	}
+	metrics.PushIfNotDone(ctx, out, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: v.builtinMetrics.DroppedIterations,
+			Tags:   v.tags.With("reason", reason),
+		},
+		Time:  time.Now(),
+		Value: 1,
+	})
+}
+
+func (v *arrivalRateVUs) emit(ctx context.Context, out chan<- metrics.SampleContainer) {
+	t := time.Now()
+	samples := metrics.ConnectedSamples{Tags: v.tags, Time: t}
+	for _, gauge := range []struct {
+		metric *metrics.Metric
+		value  float64
+	}{
+		{v.builtinMetrics.ArrivalRateVUsAllocated, float64(v.allocated.Load())},
+		{v.builtinMetrics.ArrivalRateVUsBusy, float64(v.pool.Running())},
+		{v.builtinMetrics.ArrivalRateVUsPending, float64(v.pending.Load())},
+	} {
+		samples.Samples = append(samples.Samples, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: gauge.metric, Tags: v.tags},
+			Time:       t,
+			Value:      gauge.value,
+		})
+	}
+	metrics.PushIfNotDone(ctx, out, samples)
+}
+
+// startEmitting emits the VUs every arrivalRateVUsEmitInterval, until the
+// returned function is called, which emits them one final time.
+func (v *arrivalRateVUs) startEmitting(ctx context.Context, out chan<- metrics.SampleContainer) func() {
+	v.emit(ctx, out)
+
+	done := make(chan struct{})
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(arrivalRateVUsEmitInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				v.emit(ctx, out)
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+		v.emit(ctx, out)
+	}
+}
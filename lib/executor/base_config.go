@@ -23,6 +23,11 @@ var scenarioNameWhitelist = regexp.MustCompile(`^[0-9a-zA-Z_-]+$`)
 
 const scenarioNameErr = "the scenario name should contain only numbers, latin letters, underscores, and dashes"
 
+// errPacingArrivalRate is the validation error of the arrival-rate executors
+// with the pacing option, since they start the iterations at their own rate.
+var errPacingArrivalRate = errors.New("the pacing can't be used with the arrival-rate executors, " +
+	"which start the iterations at their configured rate")
+
 // BaseConfig contains the common config fields for all executors
 type BaseConfig struct {
 	Name         string               `json:"-"` // set via the JS object key
@@ -34,6 +39,42 @@ type BaseConfig struct {
 	Tags         map[string]string    `json:"tags"`
 	Options      *lib.ScenarioOptions `json:"options,omitempty"`
 
+	// IterationTimeout overrides the global iterationTimeout option for this scenario.
+	IterationTimeout types.NullDuration `json:"iterationTimeout"`
+
+	// The first WarmupIterations iterations of the scenario and any iterations
+	// started during the first WarmupDuration of it are tagged with phase:warmup,
+	// which overrides any phase tag of the scenario during them.
+	WarmupIterations null.Int           `json:"warmupIterations"`
+	WarmupDuration   types.NullDuration `json:"warmupDuration"`
+
+	// Weight is the scenario's share of the maxConcurrentRequests, relative to
+	// the weights of the other scenarios. It's 1 by default.
+	Weight null.Int `json:"weight"`
+
+	// TLSSessionTickets enables the resumption of TLS sessions with session
+	// tickets for the new connections made by the scenario. When it's disabled,
+	// which is the default, every new connection does a full handshake. There
+	// is no control for 0-RTT, since Go's TLS client doesn't support early data.
+	TLSSessionTickets null.Bool `json:"tlsSessionTickets"`
+
+	// DialFamily overrides the global dialFamily option for this scenario, so
+	// the same endpoints can be tested over IPv4 and IPv6 in one test.
+	DialFamily null.String `json:"dialFamily"`
+
+	// Pacing is the think time that the VUs wait for after each iteration. It
+	// can't be used with the arrival-rate executors.
+	Pacing *lib.Pacing `json:"pacing"`
+
+	// RequestDefaults are the base URL, headers and timeout of the HTTP
+	// requests made by the scenario, unless the requests override them.
+	RequestDefaults *lib.RequestDefaults `json:"requestDefaults"`
+
+	// Mix are the exported functions that the iterations are picked from,
+	// with their weights, instead of the single exec one. With the randomSeed
+	// option, every VU picks the same sequence of functions in every run.
+	Mix lib.Mix `json:"mix"`
+
 	// TODO: future extensions like distribution, others?
 }
 
@@ -69,6 +110,41 @@ func (bc BaseConfig) Validate() (result []error) {
 	if bc.GracefulStop.Duration < 0 {
 		result = append(result, errors.New("the gracefulStop timeout can't be negative"))
 	}
+	if bc.IterationTimeout.Duration < 0 {
+		result = append(result, errors.New("the iterationTimeout can't be negative"))
+	}
+	if bc.WarmupIterations.Int64 < 0 {
+		result = append(result, errors.New("the warmupIterations can't be negative"))
+	}
+	if bc.WarmupDuration.Duration < 0 {
+		result = append(result, errors.New("the warmupDuration can't be negative"))
+	}
+	if bc.Weight.Valid && bc.Weight.Int64 < 1 {
+		result = append(result, errors.New("the weight should be at least 1"))
+	}
+	if bc.DialFamily.Valid {
+		if err := types.ValidateDialFamily(bc.DialFamily.String); err != nil {
+			result = append(result, err)
+		}
+	}
+	if bc.Pacing != nil {
+		if err := bc.Pacing.Validate(); err != nil {
+			result = append(result, err)
+		}
+	}
+	if bc.Mix != nil {
+		if bc.Exec.Valid {
+			result = append(result, errors.New("the exec and mix options can't be used together"))
+		}
+		if err := bc.Mix.Validate(); err != nil {
+			result = append(result, err)
+		}
+	}
+	if bc.RequestDefaults != nil {
+		if err := bc.RequestDefaults.Validate(); err != nil {
+			result = append(result, err)
+		}
+	}
 	return result
 }
 
@@ -98,6 +174,20 @@ func (bc BaseConfig) GetGracefulStop() time.Duration {
 	return bc.GracefulStop.TimeDuration()
 }
 
+// GetIterationTimeout returns the scenario-specific iteration timeout, if any.
+// When it isn't valid, the global iterationTimeout option is used instead.
+func (bc BaseConfig) GetIterationTimeout() types.NullDuration {
+	return bc.IterationTimeout
+}
+
+// GetWeight returns the scenario's weight for sharing the maxConcurrentRequests.
+func (bc BaseConfig) GetWeight() int64 {
+	if !bc.Weight.Valid {
+		return 1
+	}
+	return bc.Weight.Int64
+}
+
 // GetEnv returns any specific environment key=value pairs that
 // are configured for the executor.
 func (bc BaseConfig) GetEnv() map[string]string {
@@ -113,6 +203,11 @@ func (bc BaseConfig) GetExec() string {
 	return exec
 }
 
+// GetMix returns the weighted functions of the scenario's iterations, if any.
+func (bc BaseConfig) GetMix() lib.Mix {
+	return bc.Mix
+}
+
 // GetScenarioOptions returns the options specific to a scenario.
 func (bc BaseConfig) GetScenarioOptions() *lib.ScenarioOptions {
 	return bc.Options
@@ -140,6 +235,30 @@ func (bc BaseConfig) getBaseInfo(facts ...string) string {
 	if bc.GracefulStop.Duration > 0 {
 		facts = append(facts, fmt.Sprintf("gracefulStop: %s", bc.GracefulStop.Duration))
 	}
+	if bc.IterationTimeout.Duration > 0 {
+		facts = append(facts, fmt.Sprintf("iterationTimeout: %s", bc.IterationTimeout.Duration))
+	}
+	if bc.WarmupIterations.Int64 > 0 {
+		facts = append(facts, fmt.Sprintf("warmupIterations: %d", bc.WarmupIterations.Int64))
+	}
+	if bc.WarmupDuration.Duration > 0 {
+		facts = append(facts, fmt.Sprintf("warmupDuration: %s", bc.WarmupDuration.Duration))
+	}
+	if bc.TLSSessionTickets.Bool {
+		facts = append(facts, "tlsSessionTickets: true")
+	}
+	if bc.DialFamily.Valid {
+		facts = append(facts, fmt.Sprintf("dialFamily: %s", bc.DialFamily.String))
+	}
+	if bc.Pacing != nil {
+		facts = append(facts, fmt.Sprintf("pacing: %s", bc.Pacing.Model))
+	}
+	if bc.Mix != nil {
+		facts = append(facts, fmt.Sprintf("mix: %v", bc.Mix.Names()))
+	}
+	if bc.RequestDefaults != nil && bc.RequestDefaults.BaseURL.Valid {
+		facts = append(facts, fmt.Sprintf("baseURL: %s", bc.RequestDefaults.BaseURL.String))
+	}
 	if len(facts) == 0 {
 		return ""
 	}
@@ -6,7 +6,6 @@ import (
 	"math"
 	"math/big"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -90,6 +89,9 @@ func (carc ConstantArrivalRateConfig) GetDescription(et *lib.ExecutionTuple) str
 // Validate makes sure all options are configured and valid
 func (carc *ConstantArrivalRateConfig) Validate() []error {
 	errors := carc.BaseConfig.Validate()
+	if carc.Pacing != nil {
+		errors = append(errors, errPacingArrivalRate)
+	}
 	if !carc.Rate.Valid {
 		errors = append(errors, fmt.Errorf("the iteration rate isn't specified"))
 	} else if carc.Rate.Int64 <= 0 {
@@ -219,6 +221,7 @@ func (car ConstantArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 	}()
 
 	vusPool := newActiveVUPool(car.executionState)
+	vus := newArrivalRateVUs(vusPool, car.executionState.Test.BuiltinMetrics, car.getMetricTags(nil))
 	defer func() {
 		// Make sure all VUs aren't executing iterations anymore, for the cancel()
 		// below to deactivate them.
@@ -228,14 +231,13 @@ func (car ConstantArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 		cancel()
 		activeVUsWg.Wait()
 	}()
-	activeVUsCount := uint64(0)
 
 	vusFmt := pb.GetFixedLengthIntFormat(maxVUs)
 	progIters := fmt.Sprintf(
 		pb.GetFixedLengthFloatFormat(arrivalRatePerSec, 2)+" iters/s", arrivalRatePerSec)
 	progressFn := func() (float64, []string) {
 		spent := time.Since(startTime)
-		currActiveVUs := atomic.LoadUint64(&activeVUsCount)
+		currActiveVUs := vus.allocated.Load()
 		progVUs := fmt.Sprintf(vusFmt+"/"+vusFmt+" VUs",
 			vusPool.Running(), currActiveVUs)
 
@@ -280,7 +282,7 @@ func (car ConstantArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 			maxDurationCtx, car.config.BaseConfig, returnVU,
 			car.nextIterationCounters,
 		))
-		atomic.AddUint64(&activeVUsCount, 1)
+		vus.allocated.Add(1)
 		vusPool.AddVU(maxDurationCtx, activeVU, runIterationBasic)
 		return activeVU
 	}
@@ -292,7 +294,9 @@ func (car ConstantArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 		defer close(returnedVUs)
 		for range makeUnplannedVUCh {
 			car.logger.Debug("Starting initialization of an unplanned VU...")
+			vus.pending.Add(1)
 			initVU, err := car.executionState.GetUnplannedVU(maxDurationCtx, car.logger)
+			vus.pending.Add(-1)
 			if err != nil {
 				// TODO figure out how to return it to the Run goroutine
 				car.logger.WithError(err).Error("Error while allocating unplanned VU")
@@ -321,9 +325,9 @@ func (car ConstantArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 			int64(car.config.TimeUnit.TimeDuration()),
 		)).TimeDuration()
 
-	droppedIterationMetric := car.executionState.Test.BuiltinMetrics.DroppedIterations
 	shownWarning := false
-	metricTags := car.getMetricTags(nil)
+	stopEmittingVUs := vus.startEmitting(parentCtx, out)
+	defer stopEmittingVUs()
 	for li, gi := 0, start; ; li, gi = li+1, gi+offsets[li%len(offsets)] {
 		t := notScaledTickerPeriod*time.Duration(gi) - time.Since(startTime)
 		timer.Reset(t)
@@ -335,15 +339,7 @@ func (car ConstantArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 
 			// Since there aren't any free VUs available, consider this iteration
 			// dropped - we aren't going to try to recover it, but
-
-			metrics.PushIfNotDone(parentCtx, out, metrics.Sample{
-				TimeSeries: metrics.TimeSeries{
-					Metric: droppedIterationMetric,
-					Tags:   metricTags,
-				},
-				Time:  time.Now(),
-				Value: 1,
-			})
+			vus.dropIteration(parentCtx, out, remainingUnplannedVUs == 0)
 
 			// We'll try to start allocating another VU in the background,
 			// non-blockingly, if we have remainingUnplannedVUs...
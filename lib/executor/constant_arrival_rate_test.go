@@ -313,6 +313,54 @@ func TestConstantArrivalRateDroppedIterations(t *testing.T) {
 	assert.Equal(t, float64(5), sumMetricValues(engineOut, metrics.DroppedIterationsName))
 }
 
+func TestConstantArrivalRateVUsDiagnostics(t *testing.T) {
+	t.Parallel()
+	var count int64
+
+	config := &ConstantArrivalRateConfig{
+		BaseConfig:      BaseConfig{GracefulStop: types.NullDurationFrom(0 * time.Second)},
+		TimeUnit:        types.NullDurationFrom(time.Second),
+		Rate:            null.IntFrom(10),
+		Duration:        types.NullDurationFrom(950 * time.Millisecond),
+		PreAllocatedVUs: null.IntFrom(1),
+		MaxVUs:          null.IntFrom(2),
+	}
+
+	runner := simpleRunner(func(ctx context.Context, _ *lib.State) error {
+		atomic.AddInt64(&count, 1)
+		<-ctx.Done()
+		return nil
+	})
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	engineOut := make(chan metrics.SampleContainer, 1000)
+	require.NoError(t, test.executor.Run(test.ctx, engineOut))
+
+	dropped := make(map[string]float64)
+	gauges := make(map[string]float64)
+	for _, sc := range metrics.GetBufferedSamples(engineOut) {
+		for _, s := range sc.GetSamples() {
+			switch s.Metric.Name {
+			case metrics.DroppedIterationsName:
+				reason, _ := s.Tags.Get("reason")
+				dropped[reason] += s.Value
+			case metrics.ArrivalRateVUsAllocatedName, metrics.ArrivalRateVUsBusyName, metrics.ArrivalRateVUsPendingName:
+				gauges[s.Metric.Name] = s.Value
+			}
+		}
+	}
+
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, float64(1), dropped[droppedNoFreeVU])
+	assert.Equal(t, float64(7), dropped[droppedMaxVUs])
+	// the VUs may or may not have returned from their iterations by the time
+	// that the final values are emitted
+	require.Contains(t, gauges, metrics.ArrivalRateVUsBusyName)
+	assert.Equal(t, float64(2), gauges[metrics.ArrivalRateVUsAllocatedName])
+	assert.Equal(t, float64(0), gauges[metrics.ArrivalRateVUsPendingName])
+}
+
 func TestConstantArrivalRateGlobalIters(t *testing.T) {
 	t.Parallel()
 
@@ -107,6 +107,7 @@ func (clvc ConstantVUsConfig) NewExecutor(es *lib.ExecutionState, logger *logrus
 	return ConstantVUs{
 		BaseExecutor: NewBaseExecutor(clvc, es, logger),
 		config:       clvc,
+		target:       newVUsTarget(clvc.GetVUs(es.ExecutionTuple)),
 	}, nil
 }
 
@@ -115,10 +116,25 @@ func (clvc ConstantVUsConfig) NewExecutor(es *lib.ExecutionState, logger *logrus
 type ConstantVUs struct {
 	*BaseExecutor
 	config ConstantVUsConfig
+	target *vusTarget
 }
 
-// Make sure we implement the lib.Executor interface.
-var _ lib.Executor = &ConstantVUs{}
+// Make sure we implement the lib.Executor and lib.VUsTargetExecutor interfaces.
+var (
+	_ lib.Executor          = &ConstantVUs{}
+	_ lib.VUsTargetExecutor = &ConstantVUs{}
+)
+
+// GetVUsTarget returns the number of VUs that are running and the planned VUs.
+func (clv ConstantVUs) GetVUsTarget() (target, maxVUs int64) {
+	return clv.target.get()
+}
+
+// SetVUsTarget changes the number of VUs that are running, the stopped ones
+// finish their current iterations first.
+func (clv ConstantVUs) SetVUsTarget(target int64) error {
+	return clv.target.set(target)
+}
 
 // Run constantly loops through as many iterations as possible on a fixed number
 // of VUs for the specified duration.
@@ -141,7 +157,8 @@ func (clv ConstantVUs) Run(parentCtx context.Context, _ chan<- metrics.SampleCon
 
 	progressFn := func() (float64, []string) {
 		spent := time.Since(startTime)
-		right := []string{fmt.Sprintf("%d VUs", numVUs)}
+		target, _ := clv.target.get()
+		right := []string{fmt.Sprintf("%d VUs", target)}
 		if spent > duration {
 			right = append(right, duration.String())
 			return 1, right
@@ -167,39 +184,54 @@ func (clv ConstantVUs) Run(parentCtx context.Context, _ chan<- metrics.SampleCon
 	activeVUs := &sync.WaitGroup{}
 	defer activeVUs.Wait()
 
-	regDurationDone := regDurationCtx.Done()
 	runIteration := getIterationRunner(clv.executionState, clv.logger)
-
+	getVU := func() (lib.InitializedVU, error) {
+		initVU, err := clv.executionState.GetPlannedVU(clv.logger, true)
+		if err != nil {
+			clv.logger.WithError(err).Error("Cannot get a VU from the buffer")
+			cancel()
+			return initVU, err
+		}
+		activeVUs.Add(1)
+		return initVU, nil
+	}
 	returnVU := func(u lib.InitializedVU) {
 		clv.executionState.ReturnVU(u, true)
 		activeVUs.Done()
 	}
 
-	handleVU := func(initVU lib.InitializedVU) {
-		ctx, cancel := context.WithCancel(maxDurationCtx)
-		defer cancel()
-
-		activeVU := initVU.Activate(
-			getVUActivationParams(ctx, clv.config.BaseConfig, returnVU, clv.nextIterationCounters))
+	vuHandles := make([]*vuHandle, numVUs)
+	for i := range vuHandles {
+		vuHandles[i] = newStoppedVUHandle(
+			maxDurationCtx, getVU, returnVU, clv.nextIterationCounters,
+			&clv.config.BaseConfig, clv.logger.WithField("vuNum", i))
+		go vuHandles[i].runLoopsIfPossible(runIteration)
+	}
 
-		for {
-			select {
-			case <-regDurationDone:
-				return // don't make more iterations
-			default: // continue looping
+	// The VUs are started and stopped as the target changes, until the end of
+	// the regular duration, when all of them are gracefully stopped.
+	var running int64
+	stopTarget, err := clv.target.run(func(target int64) error {
+		for ; running < target; running++ {
+			if err := vuHandles[running].start(); err != nil {
+				return err
 			}
-			runIteration(maxDurationCtx, activeVU)
 		}
+		for ; running > target; running-- {
+			vuHandles[running-1].gracefulStop()
+		}
+		return nil
+	})
+	if err != nil {
+		stopTarget()
+		cancel()
+		return err
 	}
 
-	for i := int64(0); i < numVUs; i++ {
-		initVU, err := clv.executionState.GetPlannedVU(clv.logger, true)
-		if err != nil {
-			cancel()
-			return err
-		}
-		activeVUs.Add(1)
-		go handleVU(initVU)
+	<-regDurationCtx.Done()
+	stopTarget()
+	for _, vh := range vuHandles {
+		vh.gracefulStop()
 	}
 
 	return nil
@@ -52,3 +52,144 @@ func TestConstantVUsRun(t *testing.T) {
 	})
 	assert.Equal(t, uint64(50), totalIters)
 }
+
+func TestConstantVUsActiveVUs(t *testing.T) {
+	t.Parallel()
+
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, getTestConstantVUsConfig())
+	defer test.cancel()
+
+	errCh := make(chan error)
+	go func() { errCh <- test.executor.Run(test.ctx, nil) }()
+
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, int64(10), test.state.GetCurrentlyActiveVUsCount())
+
+	require.NoError(t, <-errCh)
+	assert.Equal(t, int64(0), test.state.GetCurrentlyActiveVUsCount())
+}
+
+func TestConstantVUsGracefulStopWaits(t *testing.T) {
+	t.Parallel()
+
+	config := ConstantVUsConfig{
+		BaseConfig: BaseConfig{GracefulStop: types.NullDurationFrom(time.Second)},
+		VUs:        null.IntFrom(1),
+		Duration:   types.NullDurationFrom(1 * time.Second),
+	}
+
+	var (
+		started = make(chan struct{}) // the iteration started
+		stopped = make(chan struct{}) // the iteration stopped
+		stop    = make(chan struct{}) // the iteration should stop
+	)
+
+	runner := simpleRunner(func(ctx context.Context, _ *lib.State) error {
+		close(started) // panics if another iteration starts after the duration
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			t.Fatal("The iterations should've ended before the context")
+		case <-stop:
+		}
+		return nil
+	})
+
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	errCh := make(chan error)
+	go func() { errCh <- test.executor.Run(test.ctx, nil) }()
+
+	<-started
+	// 500 milliseconds more then the duration and 500 less then the gracefulStop
+	time.Sleep(time.Millisecond * 1500)
+	close(stop)
+	<-stopped
+
+	require.NoError(t, <-errCh)
+}
+
+func TestConstantVUsGracefulStopStops(t *testing.T) {
+	t.Parallel()
+
+	config := ConstantVUsConfig{
+		BaseConfig: BaseConfig{GracefulStop: types.NullDurationFrom(time.Second)},
+		VUs:        null.IntFrom(1),
+		Duration:   types.NullDurationFrom(1 * time.Second),
+	}
+
+	var (
+		started = make(chan struct{}) // the iteration started
+		stopped = make(chan struct{}) // the iteration stopped
+		stop    = make(chan struct{}) // the iteration should stop
+	)
+
+	runner := simpleRunner(func(ctx context.Context, _ *lib.State) error {
+		close(started) // panics if another iteration starts after the duration
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+		case <-stop:
+			t.Fatal("The iterations shouldn't have ended before the context")
+		}
+		return nil
+	})
+
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	errCh := make(chan error)
+	go func() { errCh <- test.executor.Run(test.ctx, nil) }()
+
+	<-started
+	// 500 milliseconds more then the gracefulStop + duration
+	time.Sleep(time.Millisecond * 2500)
+	close(stop)
+	<-stopped
+
+	require.NoError(t, <-errCh)
+}
+
+func TestConstantVUsTarget(t *testing.T) {
+	t.Parallel()
+
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, getTestConstantVUsConfig())
+	defer test.cancel()
+
+	clv, ok := test.executor.(lib.VUsTargetExecutor)
+	require.True(t, ok)
+	require.ErrorContains(t, clv.SetVUsTarget(11), "the VUs target should be between 0 and the 10 planned VUs, got 11")
+	require.NoError(t, clv.SetVUsTarget(4))
+
+	errCh := make(chan error)
+	go func() { errCh <- test.executor.Run(test.ctx, nil) }()
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int64(4), test.state.GetCurrentlyActiveVUsCount())
+
+	require.NoError(t, clv.SetVUsTarget(7))
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int64(7), test.state.GetCurrentlyActiveVUsCount())
+
+	// the stopped VUs finish their iterations first
+	require.NoError(t, clv.SetVUsTarget(2))
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int64(2), test.state.GetCurrentlyActiveVUsCount())
+
+	require.NoError(t, <-errCh)
+	assert.Equal(t, int64(0), test.state.GetCurrentlyActiveVUsCount())
+	target, maxVUs := clv.GetVUsTarget()
+	assert.Equal(t, int64(2), target)
+	assert.Equal(t, int64(10), maxVUs)
+}
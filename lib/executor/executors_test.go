@@ -105,6 +105,83 @@ var configMapTestCases = []configMapTestCase{
 	{`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "startTime": "-10s"}}`, exp{validationError: true}},
 	{`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "exec": ""}}`, exp{validationError: true}},
 	{`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "gracefulStop": "-2s"}}`, exp{validationError: true}},
+	{`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "iterationTimeout": "-2s"}}`, exp{validationError: true}},
+	{
+		`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "iterationTimeout": "5s"}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			assert.Empty(t, cm["aname"].Validate())
+			assert.Equal(t, types.NullDurationFrom(5*time.Second), cm["aname"].(ConstantVUsConfig).GetIterationTimeout())
+
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "10 looping VUs for 10s (gracefulStop: 30s, iterationTimeout: 5s)", cm["aname"].GetDescription(et))
+		}},
+	},
+	{`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "weight": 0}}`, exp{validationError: true}},
+	{
+		`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "weight": 3}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			assert.Empty(t, cm["aname"].Validate())
+			assert.Equal(t, int64(3), cm["aname"].(ConstantVUsConfig).GetWeight())
+			assert.Equal(t, int64(1), NewConstantVUsConfig("default").GetWeight())
+		}},
+	},
+	{`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "pacing": {"model": "lognormal", "mean": "5s"}}}`, exp{validationError: true}},
+	{
+		`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "pacing": {"model": "lognormal", "mean": "5s", "sigma": 0.5}}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			assert.Empty(t, cm["aname"].Validate())
+			pacing := cm["aname"].(ConstantVUsConfig).Pacing
+			require.NotNil(t, pacing)
+			assert.Equal(t, lib.PacingLognormal, pacing.Model)
+			assert.Equal(t, types.NullDurationFrom(5*time.Second), pacing.Mean)
+
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "10 looping VUs for 10s (gracefulStop: 30s, pacing: lognormal)", cm["aname"].GetDescription(et))
+		}},
+	},
+	{`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "mix": {"browse": -1}}}`, exp{validationError: true}},
+	{`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "exec": "browse", "mix": {"browse": 1}}}`, exp{validationError: true}},
+	{
+		`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "mix": {"browse": 70, "search": 20, "checkout": 10}}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			assert.Empty(t, cm["aname"].Validate())
+			config := cm["aname"].(ConstantVUsConfig)
+			assert.Equal(t, lib.Mix{"browse": 70, "search": 20, "checkout": 10}, config.GetMix())
+
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "10 looping VUs for 10s (gracefulStop: 30s, mix: [browse checkout search])", cm["aname"].GetDescription(et))
+		}},
+	},
+	{`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "requestDefaults": {"baseURL": "example.com"}}}`, exp{validationError: true}},
+	{
+		`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "env": {"TENANT": "a"}, "requestDefaults": {"baseURL": "https://a.example.com", "headers": {"X-Tenant": "a"}, "timeout": "5s"}}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			assert.Empty(t, cm["aname"].Validate())
+			defaults := cm["aname"].(ConstantVUsConfig).RequestDefaults
+			require.NotNil(t, defaults)
+			assert.Equal(t, null.StringFrom("https://a.example.com"), defaults.BaseURL)
+			assert.Equal(t, map[string]string{"X-Tenant": "a"}, defaults.Headers)
+			assert.Equal(t, types.NullDurationFrom(5*time.Second), defaults.Timeout)
+
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "10 looping VUs for 10s (gracefulStop: 30s, baseURL: https://a.example.com)",
+				cm["aname"].GetDescription(et))
+		}},
+	},
+	{`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "dialFamily": "ipv5"}}`, exp{validationError: true}},
+	{
+		`{"aname": {"executor": "constant-vus", "vus": 10, "duration": "10s", "dialFamily": "ipv6"}}`,
+		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
+			assert.Empty(t, cm["aname"].Validate())
+			et, err := lib.NewExecutionTuple(nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, "10 looping VUs for 10s (gracefulStop: 30s, dialFamily: ipv6)", cm["aname"].GetDescription(et))
+		}},
+	},
 	// ramping-vus
 	{
 		`{"varloops": {"executor": "ramping-vus", "startVUs": 20, "gracefulStop": "15s", "gracefulRampDown": "10s",
@@ -343,6 +420,7 @@ var configMapTestCases = []configMapTestCase{
 	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "maxVUs": 30}}`, exp{}},
 	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "maxVUs": 30, "timeUnit": "-1s"}}`, exp{validationError: true}},
 	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "maxVUs": 30, "timeUnit": "0s"}}`, exp{validationError: true}},
+	{`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20, "pacing": {"model": "constant", "mean": "1s"}}}`, exp{validationError: true}},
 	{
 		`{"carrival": {"executor": "constant-arrival-rate", "rate": 10, "duration": "10m", "preAllocatedVUs": 20}}`,
 		exp{custom: func(t *testing.T, cm lib.ScenarioConfigs) {
@@ -408,6 +486,7 @@ var configMapTestCases = []configMapTestCase{
 	{`{"varrival": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 20, "maxVUs": 50, "stages": [{"duration": "5m", "target": 10}], "timeUnit": "-1s"}}`, exp{validationError: true}},
 	{`{"varrival": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 20, "maxVUs": 50, "stages": [{"duration": "5m", "target": 10}], "timeUnit": "0s"}}`, exp{validationError: true}},
 	{`{"varrival": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 30, "maxVUs": 20, "stages": [{"duration": "5m", "target": 10}]}}`, exp{validationError: true}},
+	{`{"varrival": {"executor": "ramping-arrival-rate", "preAllocatedVUs": 20, "stages": [{"duration": "5m", "target": 10}], "pacing": {"model": "constant", "mean": "1s"}}}`, exp{validationError: true}},
 	// TODO: more tests of mixed executors and execution plans
 
 	// scenario options
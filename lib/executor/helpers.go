@@ -96,6 +96,14 @@ func getIterationRunner(
 					return false
 				}
 
+				if errors.Is(err, lib.ErrIterationTimeout) {
+					logger.Warn(err.Error())
+					executionState.AddInterruptedIterations(1)
+					return false
+				}
+
+				executionState.AddFailedIterations(1)
+
 				var exception errext.Exception
 				if errors.As(err, &exception) {
 					// TODO don't count this as a full iteration?
@@ -113,6 +121,28 @@ func getIterationRunner(
 	}
 }
 
+// getVUTeardownRunner returns a closure that runs the vuTeardown() function of
+// a VU that is gracefully stopped, if the VU supports it. Its errors are
+// logged like the ones of the iterations.
+func getVUTeardownRunner(logger *logrus.Entry) func(context.Context, lib.ActiveVU) {
+	return func(ctx context.Context, vu lib.ActiveVU) {
+		tvu, ok := vu.(lib.ActiveVUWithTeardown)
+		if !ok {
+			return
+		}
+		err := tvu.RunVUTeardown()
+		if err == nil || ctx.Err() != nil || handleInterrupt(ctx, err) {
+			return
+		}
+		var exception errext.Exception
+		if errors.As(err, &exception) {
+			logger.WithField("source", "stacktrace").Error(exception.StackTrace())
+		} else {
+			logger.Error(err.Error())
+		}
+	}
+}
+
 // getDurationContexts is used to create sub-contexts that can restrict an
 // executor to only run for its allotted time.
 //
@@ -225,12 +255,34 @@ func getVUActivationParams(
 	ctx context.Context, conf BaseConfig, deactivateCallback func(lib.InitializedVU),
 	nextIterationCounters func() (uint64, uint64),
 ) *lib.VUActivationParams {
+	// The VUs are always activated with the returned params right away, so
+	// this is where the active VUs of every scenario are counted.
+	if es := lib.GetExecutionState(ctx); es != nil {
+		es.ModScenarioActiveVUsCount(conf.Name, +1)
+		returnVU := deactivateCallback
+		deactivateCallback = func(vu lib.InitializedVU) {
+			es.ModScenarioActiveVUsCount(conf.Name, -1)
+			if returnVU != nil {
+				returnVU(vu)
+			}
+		}
+	}
+
 	return &lib.VUActivationParams{
 		RunContext:               ctx,
 		Scenario:                 conf.Name,
 		Exec:                     conf.GetExec(),
 		Env:                      conf.GetEnv(),
 		Tags:                     conf.GetTags(),
+		IterationTimeout:         conf.GetIterationTimeout(),
+		WarmupIterations:         conf.WarmupIterations.Int64,
+		WarmupDuration:           conf.WarmupDuration.TimeDuration(),
+		Weight:                   conf.GetWeight(),
+		TLSSessionTickets:        conf.TLSSessionTickets.Bool,
+		DialFamily:               conf.DialFamily.String,
+		Pacing:                   conf.Pacing,
+		Mix:                      conf.Mix,
+		RequestDefaults:          conf.RequestDefaults,
 		DeactivateCallback:       deactivateCallback,
 		GetNextIterationCounters: nextIterationCounters,
 	}
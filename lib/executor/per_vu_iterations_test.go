@@ -2,6 +2,8 @@ package executor
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -52,6 +54,32 @@ func TestPerVUIterationsRun(t *testing.T) {
 	assert.Equal(t, uint64(1000), totalIters)
 }
 
+// Test that the timed out iterations are counted as interrupted but not as failed.
+func TestPerVUIterationsRunTimedOutIterations(t *testing.T) {
+	t.Parallel()
+	var iters atomic.Uint64
+
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error {
+		if iters.Add(1)%2 == 0 {
+			return fmt.Errorf("%w after 1s", lib.ErrIterationTimeout)
+		}
+		return errors.New("failed")
+	})
+
+	config := getTestPerVUIterationsConfig()
+	config.VUs, config.Iterations = null.IntFrom(1), null.IntFrom(10)
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	engineOut := make(chan metrics.SampleContainer, 1000)
+	require.NoError(t, test.executor.Run(test.ctx, engineOut))
+
+	assert.Equal(t, uint64(10), iters.Load())
+	assert.Equal(t, uint64(5), test.state.GetFullIterationCount())
+	assert.Equal(t, uint64(5), test.state.GetPartialIterationCount())
+	assert.Equal(t, uint64(5), test.state.GetFailedIterationCount())
+}
+
 // Test that when one VU "slows down", others will *not* pick up the workload.
 // This is the reverse behavior of the SharedIterations executor.
 func TestPerVUIterationsRunVariableVU(t *testing.T) {
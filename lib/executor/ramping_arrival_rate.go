@@ -86,6 +86,9 @@ func (varc RampingArrivalRateConfig) GetDescription(et *lib.ExecutionTuple) stri
 // Validate makes sure all options are configured and valid
 func (varc *RampingArrivalRateConfig) Validate() []error {
 	errors := varc.BaseConfig.Validate()
+	if varc.Pacing != nil {
+		errors = append(errors, errPacingArrivalRate)
+	}
 
 	if varc.StartRate.Int64 < 0 {
 		errors = append(errors, fmt.Errorf("the startRate value can't be negative"))
@@ -329,6 +332,7 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 	startTime, maxDurationCtx, regDurationCtx, cancel := getDurationContexts(parentCtx, duration, gracefulStop)
 
 	vusPool := newActiveVUPool(varr.executionState)
+	vus := newArrivalRateVUs(vusPool, varr.executionState.Test.BuiltinMetrics, varr.getMetricTags(nil))
 
 	defer func() {
 		// Make sure all VUs aren't executing iterations anymore, for the cancel()
@@ -341,13 +345,12 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 		<-waitOnProgressChannel
 	}()
 
-	activeVUsCount := uint64(0)
 	tickerPeriod := int64(startTickerPeriod.Duration)
 	vusFmt := pb.GetFixedLengthIntFormat(maxVUs)
 	itersFmt := pb.GetFixedLengthFloatFormat(maxArrivalRatePerSec, 2) + " iters/s"
 
 	progressFn := func() (float64, []string) {
-		currActiveVUs := atomic.LoadUint64(&activeVUsCount)
+		currActiveVUs := vus.allocated.Load()
 		currentTickerPeriod := atomic.LoadInt64(&tickerPeriod)
 		progVUs := fmt.Sprintf(vusFmt+"/"+vusFmt+" VUs",
 			vusPool.Running(), currActiveVUs)
@@ -401,7 +404,7 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 			getVUActivationParams(
 				maxDurationCtx, varr.config.BaseConfig, returnVU,
 				varr.nextIterationCounters))
-		atomic.AddUint64(&activeVUsCount, 1)
+		vus.allocated.Add(1)
 
 		vusPool.AddVU(maxDurationCtx, activeVU, runIterationBasic)
 		return activeVU
@@ -415,7 +418,9 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 
 		for range makeUnplannedVUCh {
 			varr.logger.Debug("Starting initialization of an unplanned VU...")
+			vus.pending.Add(1)
 			initVU, err := varr.executionState.GetUnplannedVU(maxDurationCtx, varr.logger)
+			vus.pending.Add(-1)
 			if err != nil {
 				// TODO figure out how to return it to the Run goroutine
 				varr.logger.WithError(err).Error("Error while allocating unplanned VU")
@@ -441,7 +446,8 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 	ch := make(chan time.Duration, 10) // buffer 10 iteration times ahead
 	var prevTime time.Duration
 	shownWarning := false
-	metricTags := varr.getMetricTags(nil)
+	stopEmittingVUs := vus.startEmitting(parentCtx, out)
+	defer stopEmittingVUs()
 	go varr.config.cal(varr.et, ch)
 	for nextTime := range ch {
 		select {
@@ -467,14 +473,7 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 
 		// Since there aren't any free VUs available, consider this iteration
 		// dropped - we aren't going to try to recover it, but
-		metrics.PushIfNotDone(parentCtx, out, metrics.Sample{
-			TimeSeries: metrics.TimeSeries{
-				Metric: varr.executionState.Test.BuiltinMetrics.DroppedIterations,
-				Tags:   metricTags,
-			},
-			Time:  time.Now(),
-			Value: 1,
-		})
+		vus.dropIteration(parentCtx, out, remainingUnplannedVUs == 0)
 
 		// We'll try to start allocating another VU in the background,
 		// non-blockingly, if we have remainingUnplannedVUs...
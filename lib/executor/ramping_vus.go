@@ -48,6 +48,10 @@ type RampingVUsConfig struct {
 	StartVUs         null.Int           `json:"startVUs"`
 	Stages           []Stage            `json:"stages"`
 	GracefulRampDown types.NullDuration `json:"gracefulRampDown"`
+	// DrainVUs makes the VUs stopped by a ramp-down run the vuTeardown()
+	// function of the script after their last iteration, within the
+	// gracefulRampDown period.
+	DrainVUs null.Bool `json:"drainVUs"`
 }
 
 // NewRampingVUsConfig returns a RampingVUsConfig with its default values
@@ -459,6 +463,7 @@ func (vlvc RampingVUsConfig) NewExecutor(es *lib.ExecutionState, logger *logrus.
 	return &RampingVUs{
 		BaseExecutor: NewBaseExecutor(vlvc, es, logger),
 		config:       vlvc,
+		target:       newVUsTarget(int64(lib.GetMaxPlannedVUs(vlvc.getRawExecutionSteps(es.ExecutionTuple, true)))), //nolint:gosec
 	}, nil
 }
 
@@ -473,12 +478,28 @@ func (vlvc RampingVUsConfig) HasWork(et *lib.ExecutionTuple) bool {
 type RampingVUs struct {
 	*BaseExecutor
 	config RampingVUsConfig
+	target *vusTarget
 
 	rawSteps, gracefulSteps []lib.ExecutionStep
 }
 
-// Make sure we implement the lib.Executor interface.
-var _ lib.Executor = &RampingVUs{}
+// Make sure we implement the lib.Executor and lib.VUsTargetExecutor interfaces.
+var (
+	_ lib.Executor          = &RampingVUs{}
+	_ lib.VUsTargetExecutor = &RampingVUs{}
+)
+
+// GetVUsTarget returns the most VUs that the stages can run and the most VUs
+// that are planned for them.
+func (vlv *RampingVUs) GetVUsTarget() (target, maxVUs int64) {
+	return vlv.target.get()
+}
+
+// SetVUsTarget caps the VUs of the stages, so fewer VUs than the stages'
+// targets are running, the stopped ones finish their current iterations first.
+func (vlv *RampingVUs) SetVUsTarget(target int64) error {
+	return vlv.target.set(target)
+}
 
 // Init initializes the rampingVUs executor by precalculating the raw
 // and graceful steps.
@@ -552,11 +573,13 @@ func (vlv *RampingVUs) Run(ctx context.Context, _ chan<- metrics.SampleContainer
 		handleNewMaxAllowedVUs = runState.maxAllowedVUsHandlerStrategy()
 		handleNewScheduledVUs  = runState.scheduledVUsHandlerStrategy()
 	)
+	stopTarget, _ := vlv.target.run(runState.handleNewVUsTarget)
 	handledGracefulSteps := runState.iterateSteps(
 		ctx,
 		handleNewMaxAllowedVUs,
 		handleNewScheduledVUs,
 	)
+	stopTarget()
 	go runState.runRemainingGracefulSteps(
 		ctx,
 		handleNewMaxAllowedVUs,
@@ -576,6 +599,11 @@ type rampingVUsRunState struct {
 	started        time.Time
 	wg             sync.WaitGroup
 
+	// the VUs that the stages are scheduling, the VUs target that caps them
+	// and the number of VU handles that were started for them
+	mu                         sync.Mutex
+	scheduled, target, running uint64
+
 	runIteration func(context.Context, lib.ActiveVU) bool // a helper closure function that runs a single iteration
 }
 
@@ -618,6 +646,9 @@ func (rs *rampingVUsRunState) runLoopsIfPossible(ctx context.Context, cancel fun
 		rs.vuHandles[i] = newStoppedVUHandle(
 			ctx, getVU, returnVU, rs.executor.nextIterationCounters,
 			&rs.executor.config.BaseConfig, rs.executor.logger.WithField("vuNum", i))
+		if rs.executor.config.DrainVUs.Bool {
+			rs.vuHandles[i].drain = getVUTeardownRunner(rs.executor.logger)
+		}
 		go rs.vuHandles[i].runLoopsIfPossible(rs.runIteration) //nolint:contextcheck
 	}
 }
@@ -683,15 +714,31 @@ func (rs *rampingVUsRunState) maxAllowedVUsHandlerStrategy() func(lib.ExecutionS
 }
 
 func (rs *rampingVUsRunState) scheduledVUsHandlerStrategy() func(lib.ExecutionStep) {
-	var cur uint64 // current number of planned raw VUs
 	return func(raw lib.ExecutionStep) {
-		pv := raw.PlannedVUs
-		for ; cur < pv; cur++ {
-			_ = rs.vuHandles[cur].start() // TODO: handle the error
-		}
-		for ; pv < cur; cur-- {
-			rs.vuHandles[cur-1].gracefulStop()
-		}
+		rs.mu.Lock()
+		defer rs.mu.Unlock()
+		rs.scheduled = raw.PlannedVUs
+		rs.startOrStopVUs()
+	}
+}
+
+func (rs *rampingVUsRunState) handleNewVUsTarget(target int64) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.target = uint64(target) //nolint:gosec
+	rs.startOrStopVUs()
+	return nil
+}
+
+// startOrStopVUs runs as many VUs as the stages are scheduling, but no more
+// than the VUs target. It has to be called with the mutex locked.
+func (rs *rampingVUsRunState) startOrStopVUs() {
+	pv := min(rs.scheduled, rs.target)
+	for ; rs.running < pv; rs.running++ {
+		_ = rs.vuHandles[rs.running].start() // TODO: handle the error
+	}
+	for ; pv < rs.running; rs.running-- {
+		rs.vuHandles[rs.running-1].gracefulStop()
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 	"math/rand"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -14,8 +15,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/guregu/null.v3"
 
+	"go.k6.io/k6/internal/lib/testutils/minirunner"
 	"go.k6.io/k6/lib"
 	"go.k6.io/k6/lib/types"
+	"go.k6.io/k6/metrics"
 )
 
 func TestRampingVUsConfigValidation(t *testing.T) {
@@ -145,6 +148,57 @@ func TestRampingVUsRun(t *testing.T) {
 	assert.Equal(t, int64(29), atomic.LoadInt64(&iterCount))
 }
 
+func TestRampingVUsTarget(t *testing.T) {
+	t.Parallel()
+
+	config := RampingVUsConfig{
+		BaseConfig:       BaseConfig{GracefulStop: types.NullDurationFrom(0)},
+		GracefulRampDown: types.NullDurationFrom(0),
+		StartVUs:         null.IntFrom(2),
+		Stages: []Stage{
+			{
+				Duration: types.NullDurationFrom(0),
+				Target:   null.IntFrom(6),
+			},
+			{
+				Duration: types.NullDurationFrom(1 * time.Second),
+				Target:   null.IntFrom(6),
+			},
+		},
+	}
+
+	runner := simpleRunner(func(_ context.Context, _ *lib.State) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+
+	test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+	defer test.cancel()
+
+	vlv, ok := test.executor.(lib.VUsTargetExecutor)
+	require.True(t, ok)
+	require.ErrorContains(t, vlv.SetVUsTarget(7), "the VUs target should be between 0 and the 6 planned VUs, got 7")
+	require.NoError(t, vlv.SetVUsTarget(3))
+
+	errCh := make(chan error)
+	go func() { errCh <- test.executor.Run(test.ctx, nil) }()
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int64(3), test.state.GetCurrentlyActiveVUsCount())
+
+	// the stopped VUs finish their iterations first
+	require.NoError(t, vlv.SetVUsTarget(1))
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int64(1), test.state.GetCurrentlyActiveVUsCount())
+
+	// the target caps the stages, it doesn't add VUs to them
+	require.NoError(t, vlv.SetVUsTarget(6))
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int64(6), test.state.GetCurrentlyActiveVUsCount())
+
+	require.NoError(t, <-errCh)
+}
+
 func TestRampingVUsGracefulStopWaits(t *testing.T) {
 	t.Parallel()
 
@@ -297,6 +351,74 @@ func TestRampingVUsGracefulRampDown(t *testing.T) {
 	}
 }
 
+func TestRampingVUsDrainVUs(t *testing.T) {
+	t.Parallel()
+
+	for _, drain := range []bool{true, false} {
+		t.Run(fmt.Sprintf("drainVUs=%v", drain), func(t *testing.T) {
+			t.Parallel()
+
+			config := RampingVUsConfig{
+				BaseConfig:       BaseConfig{GracefulStop: types.NullDurationFrom(time.Second)},
+				StartVUs:         null.IntFrom(3),
+				GracefulRampDown: types.NullDurationFrom(time.Second),
+				DrainVUs:         null.BoolFrom(drain),
+				Stages: []Stage{
+					{
+						Duration: types.NullDurationFrom(500 * time.Millisecond),
+						Target:   null.IntFrom(3),
+					},
+					{
+						Duration: types.NullDurationFrom(0),
+						Target:   null.IntFrom(1),
+					},
+					{
+						Duration: types.NullDurationFrom(500 * time.Millisecond),
+						Target:   null.IntFrom(1),
+					},
+				},
+			}
+
+			var (
+				mu         sync.Mutex
+				tornDown   = make(map[uint64]int)
+				afterDrain bool
+			)
+			runner := &minirunner.MiniRunner{
+				Fn: func(_ context.Context, state *lib.State, _ chan<- metrics.SampleContainer) error {
+					time.Sleep(50 * time.Millisecond)
+					mu.Lock()
+					defer mu.Unlock()
+					if tornDown[state.VUID] > 0 {
+						afterDrain = true
+					}
+					return nil
+				},
+				VUTeardownFn: func(_ context.Context, state *lib.State, _ chan<- metrics.SampleContainer) error {
+					mu.Lock()
+					defer mu.Unlock()
+					tornDown[state.VUID]++
+					return nil
+				},
+			}
+
+			test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+			defer test.cancel()
+			require.NoError(t, test.executor.Run(test.ctx, nil))
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.False(t, afterDrain, "a drained VU must not run more iterations")
+			if !drain {
+				assert.Empty(t, tornDown)
+				return
+			}
+			// the 2 VUs stopped by the ramp-down and the last one at the end
+			assert.Equal(t, map[uint64]int{1: 1, 2: 1, 3: 1}, tornDown)
+		})
+	}
+}
+
 // This test aims to check whether the ramping VU executor interrupts
 // hanging/remaining VUs after the graceful rampdown period finishes.
 //
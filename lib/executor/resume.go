@@ -0,0 +1,157 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
+)
+
+// ResumeConfig returns the config for the part of a scenario that remains
+// after the test has already run for the given time and the scenario has
+// completed the given number of iterations. The returned bool is false if
+// nothing of the scenario remains.
+//
+// Scenarios that haven't started yet are just shifted earlier. The time based
+// executors continue from the same point of their schedule, while the
+// iteration based ones only run the iterations that haven't been completed.
+// For per-vu-iterations, the completed iterations are assumed to have been
+// evenly distributed between the VUs. For shared-iterations, the VUs are
+// reduced to the remaining iterations, if there are fewer of them. The
+// remaining durations are extended to the minimum duration of the executors.
+func ResumeConfig(
+	config lib.ExecutorConfig, elapsed time.Duration, iterations uint64,
+) (lib.ExecutorConfig, bool, error) {
+	resumed, ok, err := resumeConfig(config, elapsed, iterations)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	if errs := resumed.Validate(); len(errs) > 0 {
+		return nil, false, fmt.Errorf("the resumed scenario %q is invalid: %w", config.GetName(), errors.Join(errs...))
+	}
+	return resumed, true, nil
+}
+
+func resumeConfig(
+	config lib.ExecutorConfig, elapsed time.Duration, iterations uint64,
+) (lib.ExecutorConfig, bool, error) {
+	startTime := config.GetStartTime()
+	if elapsed <= startTime {
+		return withStartTime(config, startTime-elapsed)
+	}
+	offset := elapsed - startTime
+	zero := types.NewNullDuration(0, true)
+
+	switch c := config.(type) {
+	case ConstantVUsConfig:
+		c.StartTime = zero
+		ok := remainingDuration(&c.Duration, offset)
+		return c, ok, nil
+	case *ConstantArrivalRateConfig:
+		cc := *c
+		cc.StartTime = zero
+		ok := remainingDuration(&cc.Duration, offset)
+		return &cc, ok, nil
+	case ExternallyControlledConfig:
+		c.StartTime = zero
+		if c.Duration.Duration == 0 { // it runs until it's stopped
+			return c, true, nil
+		}
+		ok := remainingDuration(&c.Duration, offset)
+		return c, ok, nil
+	case RampingVUsConfig:
+		c.StartTime = zero
+		start, stages := remainingStages(c.StartVUs.Int64, c.Stages, offset)
+		c.StartVUs, c.Stages = null.IntFrom(start), stages
+		return c, len(stages) > 0, nil
+	case *RampingArrivalRateConfig:
+		cc := *c
+		cc.StartTime = zero
+		start, stages := remainingStages(cc.StartRate.Int64, cc.Stages, offset)
+		cc.StartRate, cc.Stages = null.IntFrom(start), stages
+		return &cc, len(stages) > 0, nil
+	case SharedIterationsConfig:
+		c.StartTime = zero
+		c.Iterations = null.IntFrom(c.Iterations.Int64 - int64(iterations)) //nolint:gosec
+		if c.VUs.Int64 > c.Iterations.Int64 {
+			c.VUs = null.IntFrom(c.Iterations.Int64)
+		}
+		ok := remainingDuration(&c.MaxDuration, offset)
+		return c, ok && c.Iterations.Int64 > 0, nil
+	case PerVUIterationsConfig:
+		c.StartTime = zero
+		if c.VUs.Int64 > 0 {
+			c.Iterations = null.IntFrom(c.Iterations.Int64 - int64(iterations)/c.VUs.Int64) //nolint:gosec
+		}
+		ok := remainingDuration(&c.MaxDuration, offset)
+		return c, ok && c.Iterations.Int64 > 0, nil
+	default:
+		return nil, false, fmt.Errorf("scenarios with the %s executor can't be resumed", config.GetType())
+	}
+}
+
+func withStartTime(config lib.ExecutorConfig, startTime time.Duration) (lib.ExecutorConfig, bool, error) {
+	st := types.NullDurationFrom(startTime)
+	switch c := config.(type) {
+	case ConstantVUsConfig:
+		c.StartTime = st
+		return c, true, nil
+	case *ConstantArrivalRateConfig:
+		cc := *c
+		cc.StartTime = st
+		return &cc, true, nil
+	case ExternallyControlledConfig:
+		c.StartTime = st
+		return c, true, nil
+	case RampingVUsConfig:
+		c.StartTime = st
+		return c, true, nil
+	case *RampingArrivalRateConfig:
+		cc := *c
+		cc.StartTime = st
+		return &cc, true, nil
+	case SharedIterationsConfig:
+		c.StartTime = st
+		return c, true, nil
+	case PerVUIterationsConfig:
+		c.StartTime = st
+		return c, true, nil
+	default:
+		return nil, false, fmt.Errorf("scenarios with the %s executor can't be resumed", config.GetType())
+	}
+}
+
+// remainingDuration reduces the duration by the offset, but not below the
+// minDuration, and returns whether anything of it remains.
+func remainingDuration(d *types.NullDuration, offset time.Duration) bool {
+	remaining := d.TimeDuration() - offset
+	if remaining <= 0 {
+		return false
+	}
+	*d = types.NullDurationFrom(max(remaining, minDuration))
+	return true
+}
+
+// remainingStages returns the stages that remain after the offset, with the
+// start value interpolated at the offset, if it's in the middle of a stage.
+func remainingStages(start int64, stages []Stage, offset time.Duration) (int64, []Stage) {
+	from := start
+	for i, stage := range stages {
+		d := stage.Duration.TimeDuration()
+		if offset < d {
+			progress := float64(offset) / float64(d)
+			current := from + int64(math.Round(float64(stage.Target.Int64-from)*progress))
+			remaining := make([]Stage, 0, len(stages)-i)
+			remaining = append(remaining, Stage{Duration: types.NullDurationFrom(d - offset), Target: stage.Target})
+			return current, append(remaining, stages[i+1:]...)
+		}
+		offset -= d
+		from = stage.Target.Int64
+	}
+	return from, nil
+}
@@ -0,0 +1,187 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/types"
+)
+
+func TestResumeConfig(t *testing.T) {
+	t.Parallel()
+
+	stages := func(s ...int64) []Stage {
+		result := make([]Stage, 0, len(s)/2)
+		for i := 0; i < len(s); i += 2 {
+			result = append(result, Stage{
+				Duration: types.NullDurationFrom(time.Duration(s[i]) * time.Second),
+				Target:   null.IntFrom(s[i+1]),
+			})
+		}
+		return result
+	}
+	withStart := func(c lib.ExecutorConfig, start time.Duration) lib.ExecutorConfig {
+		st := types.NullDurationFrom(start)
+		switch c := c.(type) {
+		case ConstantVUsConfig:
+			c.StartTime = st
+			return c
+		case RampingVUsConfig:
+			c.StartTime = st
+			return c
+		}
+		return c
+	}
+
+	constantVUs := NewConstantVUsConfig("constant")
+	constantVUs.Duration = types.NullDurationFrom(time.Minute)
+
+	rampingVUs := NewRampingVUsConfig("ramping")
+	rampingVUs.StartVUs = null.IntFrom(0)
+	rampingVUs.Stages = stages(10, 10, 20, 30, 10, 0)
+
+	arrivalRate := NewRampingArrivalRateConfig("arrival")
+	arrivalRate.StartRate = null.IntFrom(10)
+	arrivalRate.Stages = stages(60, 70)
+	arrivalRate.PreAllocatedVUs = null.IntFrom(10)
+
+	shared := NewSharedIterationsConfig("shared")
+	shared.VUs = null.IntFrom(10)
+	shared.Iterations = null.IntFrom(100)
+
+	perVU := NewPerVUIterationsConfig("per-vu")
+	perVU.VUs = null.IntFrom(4)
+	perVU.Iterations = null.IntFrom(10)
+
+	testCases := map[string]struct {
+		config     lib.ExecutorConfig
+		elapsed    time.Duration
+		iterations uint64
+		expected   func() lib.ExecutorConfig
+		finished   bool
+	}{
+		"not started yet": {
+			config:  withStart(constantVUs, time.Minute),
+			elapsed: 20 * time.Second,
+			expected: func() lib.ExecutorConfig {
+				return withStart(constantVUs, 40*time.Second)
+			},
+		},
+		"constant vus": {
+			config:  withStart(constantVUs, 10*time.Second),
+			elapsed: 30 * time.Second,
+			expected: func() lib.ExecutorConfig {
+				c := constantVUs
+				c.StartTime = types.NewNullDuration(0, true)
+				c.Duration = types.NullDurationFrom(40 * time.Second)
+				return c
+			},
+		},
+		"constant vus finished": {
+			config:   constantVUs,
+			elapsed:  time.Minute,
+			finished: true,
+		},
+		"ramping vus in the middle of a stage": {
+			config:  rampingVUs,
+			elapsed: 15 * time.Second,
+			expected: func() lib.ExecutorConfig {
+				c := rampingVUs
+				c.StartTime = types.NewNullDuration(0, true)
+				c.StartVUs = null.IntFrom(15)
+				c.Stages = stages(15, 30, 10, 0)
+				return c
+			},
+		},
+		"ramping vus finished": {
+			config:   rampingVUs,
+			elapsed:  40 * time.Second,
+			finished: true,
+		},
+		"ramping arrival rate": {
+			config:  arrivalRate,
+			elapsed: 30 * time.Second,
+			expected: func() lib.ExecutorConfig {
+				c := *arrivalRate
+				c.StartTime = types.NewNullDuration(0, true)
+				c.StartRate = null.IntFrom(40)
+				c.Stages = stages(30, 70)
+				c.MaxVUs = null.NewInt(10, false) // set by Validate()
+				return &c
+			},
+		},
+		"shared iterations": {
+			config:     shared,
+			elapsed:    time.Minute,
+			iterations: 60,
+			expected: func() lib.ExecutorConfig {
+				c := shared
+				c.StartTime = types.NewNullDuration(0, true)
+				c.Iterations = null.IntFrom(40)
+				c.MaxDuration = types.NullDurationFrom(9 * time.Minute)
+				return c
+			},
+		},
+		"shared iterations fewer than the vus": {
+			config:     shared,
+			elapsed:    time.Minute,
+			iterations: 99,
+			expected: func() lib.ExecutorConfig {
+				c := shared
+				c.StartTime = types.NewNullDuration(0, true)
+				c.Iterations = null.IntFrom(1)
+				c.VUs = null.IntFrom(1)
+				c.MaxDuration = types.NullDurationFrom(9 * time.Minute)
+				return c
+			},
+		},
+		"constant vus almost finished": {
+			config:  constantVUs,
+			elapsed: time.Minute - time.Millisecond,
+			expected: func() lib.ExecutorConfig {
+				c := constantVUs
+				c.StartTime = types.NewNullDuration(0, true)
+				c.Duration = types.NullDurationFrom(time.Second)
+				return c
+			},
+		},
+		"shared iterations all done": {
+			config:     shared,
+			elapsed:    time.Minute,
+			iterations: 100,
+			finished:   true,
+		},
+		"per vu iterations": {
+			config:     perVU,
+			elapsed:    time.Minute,
+			iterations: 21,
+			expected: func() lib.ExecutorConfig {
+				c := perVU
+				c.StartTime = types.NewNullDuration(0, true)
+				c.Iterations = null.IntFrom(5)
+				c.MaxDuration = types.NullDurationFrom(9 * time.Minute)
+				return c
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			resumed, ok, err := ResumeConfig(tc.config, tc.elapsed, tc.iterations)
+			require.NoError(t, err)
+			if tc.finished {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tc.expected(), resumed)
+			require.Empty(t, resumed.Validate())
+		})
+	}
+}
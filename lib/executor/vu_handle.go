@@ -74,6 +74,9 @@ type vuHandle struct {
 	returnVU              func(lib.InitializedVU)
 	nextIterationCounters func() (uint64, uint64)
 	config                *BaseConfig
+	// drain, if set, is called with the VU after its last iteration when it's
+	// gracefully stopped, before it's returned
+	drain func(context.Context, lib.ActiveVU)
 
 	initVU       lib.InitializedVU
 	activeVU     lib.ActiveVU
@@ -206,6 +209,12 @@ func (vh *vuHandle) runLoopsIfPossible(runIter func(context.Context, lib.ActiveV
 			continue
 		}
 
+		if state == toGracefulStop && vh.drain != nil && vu != nil {
+			// the VU is still active, so it can clean up before being returned,
+			// if start races with this it just continues with its iterations
+			vh.drain(ctx, vu)
+		}
+
 		// slow path - something has changed - get what and wait until we can do more iterations
 		vh.mutex.Lock()
 		select {
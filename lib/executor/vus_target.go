@@ -0,0 +1,53 @@
+package executor
+
+import (
+	"fmt"
+	"sync"
+)
+
+// vusTarget keeps the lib.VUsTargetExecutor target of an executor, which caps
+// the VUs it runs. It's shared by the copies of the executor and it's applied
+// by the run of the executor, if it's running.
+type vusTarget struct {
+	mu     sync.Mutex
+	target int64
+	maxVUs int64
+	apply  func(target int64) error
+}
+
+func newVUsTarget(maxVUs int64) *vusTarget {
+	return &vusTarget{target: maxVUs, maxVUs: maxVUs}
+}
+
+func (vt *vusTarget) get() (target, maxVUs int64) {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	return vt.target, vt.maxVUs
+}
+
+func (vt *vusTarget) set(target int64) error {
+	if target < 0 || target > vt.maxVUs {
+		return fmt.Errorf("the VUs target should be between 0 and the %d planned VUs, got %d", vt.maxVUs, target)
+	}
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	vt.target = target
+	if vt.apply != nil {
+		return vt.apply(target)
+	}
+	return nil
+}
+
+// run applies the current target with the given function and then every time
+// it's changed, until the returned function is called. The error is the one of
+// the first application.
+func (vt *vusTarget) run(apply func(target int64) error) (stop func(), err error) {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	vt.apply = apply
+	return func() {
+		vt.mu.Lock()
+		defer vt.mu.Unlock()
+		vt.apply = nil
+	}, apply(vt.target)
+}
@@ -135,6 +135,16 @@ type LiveUpdatableExecutor interface {
 	UpdateConfig(ctx context.Context, newConfig interface{}) error
 }
 
+// VUsTargetExecutor should be implemented by the executors whose number of VUs
+// can be changed in the middle of the test execution, within the VUs that were
+// planned and initialized for them before the test started. Currently, the
+// constant-vus and ramping-vus executors implement it.
+type VUsTargetExecutor interface {
+	// GetVUsTarget returns the current target and the most VUs it can be.
+	GetVUsTarget() (target, maxVUs int64)
+	SetVUsTarget(target int64) error
+}
+
 // ExecutorConfigConstructor is a simple function that returns a concrete
 // Config instance with the specified name and all default values correctly
 // initialized
@@ -0,0 +1,178 @@
+package lib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ExpectedStatus is either a single HTTP status code or an inclusive range of
+// them. In JSON, it's either an integer or an object like {"min": 200, "max": 399}.
+type ExpectedStatus struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s ExpectedStatus) MarshalJSON() ([]byte, error) {
+	if s.Min == s.Max {
+		return json.Marshal(s.Min)
+	}
+	type plain ExpectedStatus
+	return json.Marshal(plain(s))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *ExpectedStatus) UnmarshalJSON(data []byte) error {
+	var code int
+	if err := json.Unmarshal(data, &code); err == nil {
+		s.Min, s.Max = code, code
+		return nil
+	}
+
+	var status struct {
+		Min *int `json:"min"`
+		Max *int `json:"max"`
+	}
+	if err := StrictJSONUnmarshal(data, &status); err != nil || status.Min == nil || status.Max == nil {
+		return fmt.Errorf("expected status %s is neither an integer nor an object like {min:100, max:329}", data)
+	}
+	s.Min, s.Max = *status.Min, *status.Max
+	return nil
+}
+
+// ExpectedResponseFields holds the JSON fields of an ExpectedResponseRule.
+type ExpectedResponseFields struct {
+	// Method restricts the rule to requests with this HTTP method; any method
+	// is matched if it's empty.
+	Method string `json:"method,omitempty"`
+
+	// URL is a regular expression that restricts the rule to requests with
+	// matching URLs; any URL is matched if it's empty.
+	URL string `json:"url,omitempty"`
+
+	// Statuses are the response statuses considered as expected.
+	Statuses []ExpectedStatus `json:"statuses"`
+}
+
+// ExpectedResponseRule declares which response statuses are expected for the
+// requests matching its method and URL pattern. Matching rules take precedence
+// over the response callback set with http.setResponseCallback().
+type ExpectedResponseRule struct {
+	ExpectedResponseFields
+	urlRegex *regexp.Regexp
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *ExpectedResponseRule) UnmarshalJSON(data []byte) error {
+	if err := StrictJSONUnmarshal(data, &r.ExpectedResponseFields); err != nil {
+		return err
+	}
+	urlRegex, err := r.compile()
+	if err != nil {
+		return err
+	}
+	r.urlRegex = urlRegex
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r ExpectedResponseRule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.ExpectedResponseFields)
+}
+
+// compile checks the rule and returns its compiled URL pattern, which is nil
+// if the rule matches any URL.
+func (r ExpectedResponseRule) compile() (*regexp.Regexp, error) {
+	if len(r.Statuses) == 0 {
+		return nil, errors.New("expected response rules need at least one status")
+	}
+	for _, s := range r.Statuses {
+		if s.Min > s.Max {
+			return nil, fmt.Errorf("invalid expected status range {min:%d, max:%d}", s.Min, s.Max)
+		}
+	}
+	if r.URL == "" {
+		return nil, nil //nolint:nilnil
+	}
+	urlRegex, err := regexp.Compile(r.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url pattern %q of expected response rule: %w", r.URL, err)
+	}
+	return urlRegex, nil
+}
+
+// Matches returns whether the rule applies to requests with the given method and URL.
+func (r *ExpectedResponseRule) Matches(method, url string) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	if r.URL == "" {
+		return true
+	}
+	if r.urlRegex == nil {
+		// the rule wasn't created through JSON unmarshalling or compiled with
+		// the consolidated options, its validity is checked by Options.Validate()
+		matched, err := regexp.MatchString(r.URL, url)
+		return err == nil && matched
+	}
+	return r.urlRegex.MatchString(url)
+}
+
+// IsExpected returns whether the given status is one of the rule's expected statuses.
+func (r *ExpectedResponseRule) IsExpected(status int) bool {
+	for _, s := range r.Statuses {
+		if s.Min <= status && status <= s.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpectedResponseRules is an ordered list of rules, the first one matching a
+// request is used to classify its response.
+type ExpectedResponseRules []ExpectedResponseRule
+
+// Classify returns whether the status of the response to a request with the
+// given method and URL is expected, according to the first matching rule. The
+// second returned value is false when no rule matches the request.
+func (rules ExpectedResponseRules) Classify(method, url string, status int) (expected bool, matched bool) {
+	for i := range rules {
+		if rules[i].Matches(method, url) {
+			return rules[i].IsExpected(status), true
+		}
+	}
+	return false, false
+}
+
+// Validate returns an error for every invalid rule.
+func (rules ExpectedResponseRules) Validate() []error {
+	var errs []error
+	for i, rule := range rules {
+		if _, err := rule.compile(); err != nil {
+			errs = append(errs, fmt.Errorf("expectedResponses[%d]: %w", i, err))
+		}
+	}
+	return errs
+}
+
+// Compile returns a copy of the rules with their URL patterns compiled, so
+// they aren't compiled again for every request. It's used for the consolidated
+// options, whose rules may not have been unmarshalled from JSON.
+func (rules ExpectedResponseRules) Compile() (ExpectedResponseRules, error) {
+	if rules == nil {
+		return nil, nil
+	}
+	compiled := make(ExpectedResponseRules, len(rules))
+	for i, rule := range rules {
+		urlRegex, err := rule.compile()
+		if err != nil {
+			return nil, fmt.Errorf("expectedResponses[%d]: %w", i, err)
+		}
+		rule.urlRegex = urlRegex
+		compiled[i] = rule
+	}
+	return compiled, nil
+}
@@ -29,6 +29,13 @@ type OnlyCachedEnabler interface {
 	AllowOnlyCached()
 }
 
+// PathAllower allows opening a path in the cached only mode even if it wasn't
+// opened before, e.g. for the files that options refer to, which are only
+// known after the initialization
+type PathAllower interface {
+	AllowPath(path string)
+}
+
 // CacheLayerGetter provide a direct access to a cache layer
 type CacheLayerGetter interface {
 	GetCachingFs() afero.Fs
@@ -58,6 +65,13 @@ func (c *CacheOnReadFs) AllowOnlyCached() {
 	c.lock.Unlock()
 }
 
+// AllowPath allows opening the path, and caching it, in the cached only mode
+func (c *CacheOnReadFs) AllowPath(path string) {
+	c.lock.Lock()
+	c.cached[path] = true
+	c.lock.Unlock()
+}
+
 // Open opens file and track the history of opened files
 // if CacheOnReadFs is in the opened only mode it should return
 // an error if file wasn't open before
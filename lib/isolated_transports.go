@@ -0,0 +1,53 @@
+package lib
+
+import (
+	"net/http"
+	"sync"
+)
+
+// IsolatedTransports are the HTTP transports with connections of their own
+// that the requests of a VU reuse, like the ones of the virtual hosts of its
+// requests. They are kept by a key of their purpose.
+type IsolatedTransports struct {
+	mu         sync.Mutex
+	transports map[any]http.RoundTripper
+}
+
+// NewIsolatedTransports returns an empty IsolatedTransports.
+func NewIsolatedTransports() *IsolatedTransports {
+	return &IsolatedTransports{transports: make(map[any]http.RoundTripper)}
+}
+
+// Get returns the transport for the key, created with newTransport if there
+// isn't one yet.
+func (t *IsolatedTransports) Get(
+	key any, newTransport func() (http.RoundTripper, error),
+) (http.RoundTripper, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if transport, ok := t.transports[key]; ok {
+		return transport, nil
+	}
+	transport, err := newTransport()
+	if err != nil {
+		return nil, err
+	}
+	t.transports[key] = transport
+	return transport, nil
+}
+
+// CloseIdleConnections closes the idle connections of all the transports.
+func (t *IsolatedTransports) CloseIdleConnections() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, transport := range t.transports {
+		if closer, ok := transport.(interface{ CloseIdleConnections() }); ok {
+			closer.CloseIdleConnections()
+		}
+	}
+}
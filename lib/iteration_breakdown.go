@@ -0,0 +1,37 @@
+package lib
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// IterationBreakdown accumulates the time that a VU spends sleeping and
+// waiting for the network during an iteration, so that the iteration duration
+// can be split into them and the time spent running the script. It's safe for
+// concurrent use, since asynchronous requests finish outside of the event loop.
+// All of its methods are no-ops on a nil IterationBreakdown.
+type IterationBreakdown struct {
+	sleep, network atomic.Int64
+}
+
+// AddSleep adds to the time spent sleeping.
+func (b *IterationBreakdown) AddSleep(d time.Duration) {
+	if b != nil {
+		b.sleep.Add(int64(d))
+	}
+}
+
+// AddNetwork adds to the time spent waiting for the network.
+func (b *IterationBreakdown) AddNetwork(d time.Duration) {
+	if b != nil {
+		b.network.Add(int64(d))
+	}
+}
+
+// Reset returns the accumulated sleep and network time and starts over.
+func (b *IterationBreakdown) Reset() (sleep, network time.Duration) {
+	if b == nil {
+		return 0, 0
+	}
+	return time.Duration(b.sleep.Swap(0)), time.Duration(b.network.Swap(0))
+}
@@ -0,0 +1,31 @@
+package lib
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterationBreakdown(t *testing.T) {
+	t.Parallel()
+
+	b := &IterationBreakdown{}
+	b.AddSleep(time.Second)
+	b.AddNetwork(time.Millisecond)
+	b.AddNetwork(2 * time.Millisecond)
+	sleep, network := b.Reset()
+	assert.Equal(t, time.Second, sleep)
+	assert.Equal(t, 3*time.Millisecond, network)
+
+	sleep, network = b.Reset()
+	assert.Zero(t, sleep)
+	assert.Zero(t, network)
+
+	var disabled *IterationBreakdown
+	disabled.AddSleep(time.Second)
+	disabled.AddNetwork(time.Second)
+	sleep, network = disabled.Reset()
+	assert.Zero(t, sleep)
+	assert.Zero(t, network)
+}
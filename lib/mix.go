@@ -0,0 +1,88 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"gopkg.in/guregu/null.v3"
+)
+
+// Mix declares the exported functions that the iterations of a scenario are
+// picked from, each one with its weight, e.g. {browse: 70, search: 20,
+// checkout: 10}. The weights are relative to each other, they don't have to
+// add up to 100.
+type Mix map[string]float64
+
+// Validate checks that the mix has functions and that their weights are valid.
+func (m Mix) Validate() error {
+	if len(m) == 0 {
+		return errors.New("the mix requires at least one function")
+	}
+	var total float64
+	for name, weight := range m {
+		if name == "" {
+			return errors.New("the mix can't have a function without a name")
+		}
+		if weight < 0 {
+			return fmt.Errorf("the weight of the %q function of the mix can't be negative", name)
+		}
+		total += weight
+	}
+	if total <= 0 {
+		return errors.New("the mix requires a function with a positive weight")
+	}
+	return nil
+}
+
+// Names returns the sorted names of the functions of the mix.
+func (m Mix) Names() []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Mixer picks the functions of the iterations of a VU from a Mix. It isn't
+// safe for concurrent use.
+type Mixer struct {
+	names   []string
+	weights []float64 // cumulative
+	rand    *rand.Rand
+}
+
+// NewMixer returns a Mixer for the VU. With a seed, the functions are picked
+// in a reproducible order that's different for every VU.
+func (m Mix) NewMixer(seed null.Int, vuID uint64) *Mixer {
+	var src rand.Source
+	if seed.Valid {
+		src = rand.NewPCG(uint64(seed.Int64), vuID) //nolint:gosec
+	} else {
+		src = rand.NewPCG(rand.Uint64(), rand.Uint64()) //nolint:gosec
+	}
+
+	mixer := &Mixer{rand: rand.New(src)} //nolint:gosec
+	var total float64
+	for _, name := range m.Names() {
+		if m[name] <= 0 {
+			continue
+		}
+		total += m[name]
+		mixer.names = append(mixer.names, name)
+		mixer.weights = append(mixer.weights, total)
+	}
+	return mixer
+}
+
+// Next returns the name of the function of the next iteration.
+func (m *Mixer) Next() string {
+	if len(m.names) == 0 {
+		return ""
+	}
+	r := m.rand.Float64() * m.weights[len(m.weights)-1]
+	i := slices.IndexFunc(m.weights, func(w float64) bool { return r < w })
+	return m.names[i]
+}
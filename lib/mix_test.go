@@ -0,0 +1,65 @@
+package lib
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+)
+
+func TestMixValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`{"browse":70,"search":20,"checkout":10}`: "",
+		`{"browse":1,"search":0}`:                 "",
+		`{}`:                                      "the mix requires at least one function",
+		`{"":1}`:                                  "the mix can't have a function without a name",
+		`{"browse":1,"search":-1}`:                `the weight of the "search" function of the mix can't be negative`,
+		`{"browse":0,"search":0}`:                 "the mix requires a function with a positive weight",
+	}
+	for data, want := range tests {
+		t.Run(data, func(t *testing.T) {
+			t.Parallel()
+
+			var m Mix
+			require.NoError(t, json.Unmarshal([]byte(data), &m))
+			err := m.Validate()
+			if want == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestMixer(t *testing.T) {
+	t.Parallel()
+
+	mix := Mix{"browse": 70, "search": 20, "checkout": 10, "never": 0}
+	sample := func(seed null.Int, vuID uint64, n int) []string {
+		mixer := mix.NewMixer(seed, vuID)
+		names := make([]string, n)
+		for i := range names {
+			names[i] = mixer.Next()
+		}
+		return names
+	}
+
+	counts := make(map[string]int)
+	for _, name := range sample(null.Int{}, 1, 10000) {
+		counts[name]++
+	}
+	assert.NotContains(t, counts, "never")
+	assert.InDelta(t, 7000, counts["browse"], 300)
+	assert.InDelta(t, 2000, counts["search"], 300)
+	assert.InDelta(t, 1000, counts["checkout"], 300)
+
+	// with a seed the sequence is reproducible, and different for every VU
+	assert.Equal(t, sample(null.IntFrom(42), 1, 100), sample(null.IntFrom(42), 1, 100))
+	assert.NotEqual(t, sample(null.IntFrom(42), 1, 100), sample(null.IntFrom(42), 2, 100))
+	assert.NotEqual(t, sample(null.IntFrom(42), 1, 100), sample(null.IntFrom(43), 1, 100))
+}
@@ -2,9 +2,11 @@ package netext
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -25,6 +27,12 @@ type Dialer struct {
 
 	BytesRead    int64
 	BytesWritten int64
+
+	// ConnStats tracks the connections for the connection metrics, if set.
+	ConnStats *lib.ConnStats
+
+	// family is the address family that the connections are restricted to.
+	family atomic.Value
 }
 
 // NewDialer constructs a new Dialer with the given DNS resolver.
@@ -55,18 +63,61 @@ func (b BlockedHostError) Error() string {
 	return fmt.Sprintf("hostname (%s) is in a blocked pattern (%s)", b.hostname, b.match)
 }
 
+// DialFamilyError is returned when the address of a host isn't of the address
+// family that the connections are restricted to.
+type DialFamilyError struct {
+	host    string
+	address net.IP
+	family  string
+}
+
+func (e DialFamilyError) Error() string {
+	if e.address == nil {
+		return fmt.Sprintf("the hosts option has no %s address for %s", e.family, e.host)
+	}
+	return fmt.Sprintf("the address %s of %s isn't an %s address, as required by the dialFamily option",
+		e.address, e.host, e.family)
+}
+
+// SetFamily restricts the connections to the address family, ipv4 or ipv6,
+// any or an empty family remove the restriction.
+func (d *Dialer) SetFamily(family string) {
+	d.family.Store(family)
+}
+
+// Family returns the address family that the connections are restricted to,
+// or an empty string if they aren't.
+func (d *Dialer) Family() string {
+	family, _ := d.family.Load().(string)
+	if family == types.DialFamilyAny {
+		return ""
+	}
+	return family
+}
+
 // DialContext wraps the net.Dialer.DialContext and handles the k6 specifics
 func (d *Dialer) DialContext(ctx context.Context, proto, addr string) (net.Conn, error) {
-	dialAddr, err := d.getDialAddr(addr)
+	start := time.Now()
+	dialAddr, source, err := d.resolveDialAddr(ctx, addr)
 	if err != nil {
 		return nil, err
 	}
+	if trace := resolutionTraceFrom(ctx); trace != nil {
+		host, _, _ := net.SplitHostPort(addr)
+		trace.record(host, dialAddr, source, time.Since(start))
+	}
 	conn, err := d.Dialer.DialContext(ctx, proto, dialAddr.String())
 	if err != nil {
 		return nil, err
 	}
-	conn = &Conn{conn, &d.BytesRead, &d.BytesWritten}
-	return conn, err
+	c := &Conn{Conn: conn, BytesRead: &d.BytesRead, BytesWritten: &d.BytesWritten}
+	if d.ConnStats != nil {
+		// the connections are tracked per host, regardless of the port
+		host, _, _ := net.SplitHostPort(addr)
+		c.stats, c.host = d.ConnStats, host
+		d.ConnStats.Open(host)
+	}
+	return c, nil
 }
 
 // ResolveAddr looks up the IP address for the given host and optionally port.
@@ -117,78 +168,151 @@ func (d *Dialer) IOSamples(
 }
 
 func (d *Dialer) getDialAddr(addr string) (*types.Host, error) {
-	remote, err := d.findRemote(addr)
+	remote, _, err := d.resolveDialAddr(context.Background(), addr)
+	return remote, err
+}
+
+// resolveDialAddr returns the address to dial for addr, and where it came from.
+func (d *Dialer) resolveDialAddr(ctx context.Context, addr string) (*types.Host, string, error) {
+	remote, source, err := d.findRemote(ctx, addr)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	for _, ipnet := range d.Blacklist {
 		if ipnet.Contains(remote.IP) {
-			return nil, BlackListedIPError{ip: remote.IP, net: ipnet}
+			return nil, "", BlackListedIPError{ip: remote.IP, net: ipnet}
 		}
 	}
 
-	return remote, nil
+	return remote, source, nil
 }
 
-func (d *Dialer) findRemote(addr string) (*types.Host, error) {
+func (d *Dialer) findRemote(ctx context.Context, addr string) (*types.Host, string, error) {
 	host, port, err := net.SplitHostPort(addr)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	ip := net.ParseIP(host)
 	if d.BlockedHostnames != nil && ip == nil {
 		if match, blocked := d.BlockedHostnames.Contains(host); blocked {
-			return nil, BlockedHostError{hostname: host, match: match}
+			return nil, "", BlockedHostError{hostname: host, match: match}
+		}
+	}
+
+	family := d.Family()
+	if remote := resolveToFor(ctx, host); remote != nil {
+		if !types.InDialFamily(remote.IP, family) {
+			return nil, "", DialFamilyError{host: host, address: remote.IP, family: family}
+		}
+		if remote.Port == 0 {
+			if remote.Port, err = strconv.Atoi(port); err != nil {
+				return nil, "", err
+			}
+		}
+		return remote, ResolutionSourceResolveTo, nil
+	}
+
+	if vhost := virtualHostFor(ctx, host); vhost != "" {
+		if d.BlockedHostnames != nil {
+			if match, blocked := d.BlockedHostnames.Contains(vhost); blocked {
+				return nil, "", BlockedHostError{hostname: vhost, match: match}
+			}
+		}
+		if d.Hosts != nil {
+			remote, e := d.getConfiguredHost(net.JoinHostPort(vhost, port), vhost, port, family)
+			if e != nil || remote != nil {
+				return remote, ResolutionSourceVirtualHost, e
+			}
 		}
 	}
 
 	if d.Hosts != nil {
-		remote, e := d.getConfiguredHost(addr, host, port)
+		remote, e := d.getConfiguredHost(addr, host, port, family)
 		if e != nil || remote != nil {
-			return remote, e
+			return remote, ResolutionSourceHosts, e
 		}
 	}
 
 	if ip != nil {
-		return types.NewHost(ip, port)
+		if !types.InDialFamily(ip, family) {
+			return nil, "", DialFamilyError{host: host, address: ip, family: family}
+		}
+		remote, err := types.NewHost(ip, port)
+		return remote, ResolutionSourceIP, err
 	}
 
-	ip, err = d.Resolver.LookupIP(host)
+	ip, err = d.lookupIP(host, family)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	if ip == nil {
-		return nil, fmt.Errorf("lookup %s: no such host", host)
+		if family != "" {
+			return nil, "", fmt.Errorf("lookup %s: no %s address", host, family)
+		}
+		return nil, "", fmt.Errorf("lookup %s: no such host", host)
 	}
 
-	return types.NewHost(ip, port)
+	remote, err := types.NewHost(ip, port)
+	return remote, ResolutionSourceDNS, err
 }
 
-func (d *Dialer) getConfiguredHost(addr, host, port string) (*types.Host, error) {
-	if remote := d.Hosts.Match(addr); remote != nil {
-		return remote, nil
+// lookupIP resolves host to an address of the family, if the connections are
+// restricted to one.
+func (d *Dialer) lookupIP(host, family string) (net.IP, error) {
+	if family == "" {
+		return d.Resolver.LookupIP(host)
 	}
+	if r, ok := d.Resolver.(FamilyResolver); ok {
+		return r.LookupIPFamily(host, family)
+	}
+	ip, err := d.Resolver.LookupIP(host)
+	if err != nil || ip == nil {
+		return ip, err
+	}
+	if !types.InDialFamily(ip, family) {
+		return nil, DialFamilyError{host: host, address: ip, family: family}
+	}
+	return ip, nil
+}
 
-	if remote := d.Hosts.Match(host); remote != nil {
-		if remote.Port != 0 || port == "" {
-			return remote, nil
-		}
-
-		newPort, err := strconv.Atoi(port)
-		if err != nil {
-			return nil, err
-		}
+func (d *Dialer) getConfiguredHost(addr, host, port, family string) (*types.Host, error) {
+	if remote, err := d.matchHost(addr, family); remote != nil || err != nil {
+		return remote, err
+	}
 
-		newRemote := *remote
-		newRemote.Port = newPort
+	remote, err := d.matchHost(host, family)
+	if remote == nil || err != nil {
+		return nil, err
+	}
+	if remote.Port != 0 || port == "" {
+		return remote, nil
+	}
 
-		return &newRemote, nil
+	newPort, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, err
 	}
+	remote.Port = newPort
+
+	return remote, nil
+}
 
-	return nil, nil //nolint:nilnil
+// matchHost returns a copy of the first address of the hosts entry matching s
+// that is of the address family, or an error if the entry has none of them.
+func (d *Dialer) matchHost(s, family string) (*types.Host, error) {
+	addresses := d.Hosts.MatchAll(s)
+	if addresses == nil {
+		return nil, nil //nolint:nilnil
+	}
+	for _, address := range addresses {
+		if types.InDialFamily(address.IP, family) {
+			return &address, nil
+		}
+	}
+	return nil, DialFamilyError{host: s, family: family}
 }
 
 // Conn wraps net.Conn and keeps track of sent and received data size
@@ -196,6 +320,45 @@ type Conn struct {
 	net.Conn
 
 	BytesRead, BytesWritten *int64
+
+	// stats tracks the connection to host, if the connection metrics are enabled.
+	stats        *lib.ConnStats
+	host         string
+	mu           sync.Mutex
+	idle, closed bool
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	if c.stats != nil {
+		c.mu.Lock()
+		if !c.closed {
+			c.closed = true
+			c.stats.Close(c.host, c.idle)
+		}
+		c.mu.Unlock()
+	}
+	return c.Conn.Close()
+}
+
+// SetConnIdle marks a connection dialed by a Dialer as idle in a connection
+// pool, or as in use again, for the connection metrics. The connections are in
+// use from when they are dialed, and the TLS connections are unwrapped.
+func SetConnIdle(conn net.Conn, idle bool) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	c, ok := conn.(*Conn)
+	if !ok || c.stats == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || c.idle == idle {
+		return
+	}
+	c.idle = idle
+	c.stats.SetIdle(c.host, idle)
 }
 
 func (c *Conn) Read(b []byte) (int, error) {
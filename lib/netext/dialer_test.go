@@ -1,6 +1,7 @@
 package netext
 
 import (
+	"context"
 	"net"
 	"testing"
 
@@ -209,6 +210,139 @@ func TestDialerResolveAddr(t *testing.T) {
 	}
 }
 
+func TestDialerResolveTo(t *testing.T) {
+	t.Parallel()
+	dialer := NewDialer(net.Dialer{}, newResolver())
+	hosts, err := types.NewHosts(map[string]types.Host{"example.com": {IP: net.ParseIP("3.4.5.6")}})
+	require.NoError(t, err)
+	dialer.Hosts = hosts
+	ipNet, err := lib.ParseCIDR("8.9.10.0/24")
+	require.NoError(t, err)
+	dialer.Blacklist = []*lib.IPNet{ipNet}
+
+	ctx := WithResolveTo(context.Background(), "Example.com", types.Host{IP: net.ParseIP("5.6.7.8")})
+	testCases := []struct {
+		address, expAddress, expSource, expErr string
+	}{
+		{"example.com:443", "5.6.7.8:443", ResolutionSourceResolveTo, ""},
+		{"EXAMPLE.com:80", "5.6.7.8:80", ResolutionSourceResolveTo, ""},
+		{"example-resolver.com:80", "1.2.3.4:80", ResolutionSourceDNS, ""},
+		{"1.2.3.4:80", "1.2.3.4:80", ResolutionSourceIP, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.address, func(t *testing.T) {
+			t.Parallel()
+			addr, source, err := dialer.resolveDialAddr(ctx, tc.address)
+			require.NoError(t, err)
+			require.Equal(t, tc.expAddress, addr.String())
+			require.Equal(t, tc.expSource, source)
+		})
+	}
+
+	addr, source, err := dialer.resolveDialAddr(context.Background(), "example.com:443")
+	require.NoError(t, err)
+	require.Equal(t, "3.4.5.6:443", addr.String())
+	require.Equal(t, ResolutionSourceHosts, source)
+
+	blockedCtx := WithResolveTo(context.Background(), "example.com", types.Host{IP: net.ParseIP("8.9.10.11"), Port: 8443})
+	_, _, err = dialer.resolveDialAddr(blockedCtx, "example.com:443")
+	require.EqualError(t, err, "IP (8.9.10.11) is in a blacklisted range (8.9.10.0/24)")
+}
+
+func TestDialerVirtualHost(t *testing.T) {
+	t.Parallel()
+	dialer := NewDialer(net.Dialer{}, newResolver())
+	hosts, err := types.NewHosts(map[string]types.Host{
+		"*.example.com":        {IP: net.ParseIP("3.4.5.6")},
+		"tenant-b.example.com": {IP: net.ParseIP("5.6.7.8"), Port: 8443},
+	})
+	require.NoError(t, err)
+	dialer.Hosts = hosts
+	blocked, err := types.NewHostnameTrie([]string{"*.blocked.com"})
+	require.NoError(t, err)
+	dialer.BlockedHostnames = blocked
+
+	testCases := []struct {
+		host, vhost, address, expAddress, expSource string
+	}{
+		{"ingress.com", "tenant-a.example.com", "ingress.com:443", "3.4.5.6:443", ResolutionSourceVirtualHost},
+		{"INGRESS.com", "tenant-b.example.com", "ingress.com:443", "5.6.7.8:8443", ResolutionSourceVirtualHost},
+		{"1.2.3.4", "tenant-a.example.com", "1.2.3.4:80", "3.4.5.6:80", ResolutionSourceVirtualHost},
+		{"example-resolver.com", "tenant.other.com", "example-resolver.com:80", "1.2.3.4:80", ResolutionSourceDNS},
+		{"ingress.com", "tenant-a.example.com", "example-resolver.com:80", "1.2.3.4:80", ResolutionSourceDNS},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.vhost+"@"+tc.address, func(t *testing.T) {
+			t.Parallel()
+			ctx := WithVirtualHost(context.Background(), tc.host, tc.vhost)
+			addr, source, err := dialer.resolveDialAddr(ctx, tc.address)
+			require.NoError(t, err)
+			require.Equal(t, tc.expAddress, addr.String())
+			require.Equal(t, tc.expSource, source)
+		})
+	}
+
+	ctx := WithVirtualHost(context.Background(), "ingress.com", "tenant.blocked.com")
+	_, _, err = dialer.resolveDialAddr(ctx, "ingress.com:443")
+	require.EqualError(t, err, "hostname (tenant.blocked.com) is in a blocked pattern (*.blocked.com)")
+}
+
+func TestDialerFamily(t *testing.T) {
+	t.Parallel()
+
+	hosts, err := types.NewHostsWithAddresses(map[string][]types.Host{
+		"dual.example.com": {{IP: net.ParseIP("3.4.5.6")}, {IP: net.ParseIP("2001:db8::68"), Port: 8443}},
+		"v4.example.com":   {{IP: net.ParseIP("3.4.5.6")}},
+	})
+	require.NoError(t, err)
+	resolver := NewResolver(mockresolver.New(map[string][]net.IP{
+		"dual-resolver.com": {net.ParseIP("1.2.3.4"), net.ParseIP("2001:db8::1")},
+	}).LookupIPAll, 0, types.DNSfirst, types.DNSpreferIPv4)
+
+	testCases := []struct {
+		family, address, expAddress, expErr string
+	}{
+		{"any", "dual.example.com:80", "3.4.5.6:80", ""},
+		{"ipv4", "dual.example.com:80", "3.4.5.6:80", ""},
+		{"ipv6", "dual.example.com:80", "[2001:db8::68]:8443", ""},
+		{"ipv6", "v4.example.com:80", "", "the hosts option has no ipv6 address for v4.example.com"},
+		{"", "dual-resolver.com:80", "1.2.3.4:80", ""},
+		{"ipv6", "dual-resolver.com:80", "[2001:db8::1]:80", ""},
+		{"ipv4", "[2001:db8::1]:80", "", "the address 2001:db8::1 of 2001:db8::1 isn't an ipv4 address, " +
+			"as required by the dialFamily option"},
+		{"ipv6", "1.2.3.4:80", "", "the address 1.2.3.4 of 1.2.3.4 isn't an ipv6 address, " +
+			"as required by the dialFamily option"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.family+" "+tc.address, func(t *testing.T) {
+			t.Parallel()
+			dialer := NewDialer(net.Dialer{}, resolver)
+			dialer.Hosts = hosts
+			dialer.SetFamily(tc.family)
+
+			addr, err := dialer.getDialAddr(tc.address)
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.expAddress, addr.String())
+			}
+		})
+	}
+
+	t.Run("no address of the family", func(t *testing.T) {
+		t.Parallel()
+		dialer := NewDialer(net.Dialer{}, NewResolver(mockresolver.New(map[string][]net.IP{
+			"v4-resolver.com": {net.ParseIP("1.2.3.4")},
+		}).LookupIPAll, 0, types.DNSfirst, types.DNSany))
+		dialer.SetFamily("ipv6")
+
+		_, err := dialer.getDialAddr("v4-resolver.com:80")
+		require.EqualError(t, err, "lookup v4-resolver.com: no ipv6 address")
+	})
+}
+
 func newResolver() *mockresolver.MockResolver {
 	return mockresolver.New(
 		map[string][]net.IP{
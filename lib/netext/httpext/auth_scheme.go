@@ -0,0 +1,70 @@
+package httpext
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// AuthHandler authenticates a request with a custom auth scheme. It's called by
+// the transport with the prepared request, which it can change, e.g. to add
+// a signature header, and it sends the request with next, so it can also handle
+// challenges like a 401 response before returning the final response. It's
+// called again with each redirect.
+type AuthHandler func(req *http.Request, next http.RoundTripper) (*http.Response, error)
+
+// builtinAuthSchemes are the values of the auth param handled by k6 itself.
+var builtinAuthSchemes = []string{"basic", "digest", "ntlm"} //nolint:gochecknoglobals
+
+//nolint:gochecknoglobals
+var authSchemes = struct {
+	sync.RWMutex
+	handlers map[string]AuthHandler
+}{handlers: make(map[string]AuthHandler)}
+
+// RegisterAuthScheme registers the handler of the requests made with name as
+// their auth param. It's meant to be called in the init() of extensions, like
+// modules.Register(), and it panics if the name is empty, is one of the
+// built-in schemes or is already registered.
+func RegisterAuthScheme(name string, handler AuthHandler) {
+	if IsAuthSchemeRegistered(name) {
+		panic(fmt.Errorf("the auth scheme %q is already registered", name))
+	}
+	if name == "" || handler == nil {
+		panic(fmt.Errorf("the auth scheme %q requires a name and a handler", name))
+	}
+
+	authSchemes.Lock()
+	defer authSchemes.Unlock()
+	authSchemes.handlers[name] = handler
+}
+
+// IsAuthSchemeRegistered returns whether the auth scheme is a built-in one or
+// one registered with RegisterAuthScheme().
+func IsAuthSchemeRegistered(name string) bool {
+	if slices.Contains(builtinAuthSchemes, name) {
+		return true
+	}
+	authSchemes.RLock()
+	defer authSchemes.RUnlock()
+	_, ok := authSchemes.handlers[name]
+	return ok
+}
+
+func getAuthHandler(name string) (AuthHandler, bool) {
+	authSchemes.RLock()
+	defer authSchemes.RUnlock()
+	handler, ok := authSchemes.handlers[name]
+	return handler, ok
+}
+
+type authSchemeTransport struct {
+	originalTransport http.RoundTripper
+	handler           AuthHandler
+}
+
+// RoundTrip authenticates the request with the handler of the auth scheme.
+func (t authSchemeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.handler(req, t.originalTransport)
+}
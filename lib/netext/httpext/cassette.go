@@ -0,0 +1,289 @@
+package httpext
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxCassetteResponses is how many responses are recorded for the same
+// request, so the cassettes of long tests don't grow without bounds. They are
+// replayed in turns.
+const maxCassetteResponses = 100
+
+// Cassette is a recording of HTTP responses that can be replayed instead of
+// sending the requests. The requests are matched by their method, URL and
+// body. The responses for the same request are replayed in the order they
+// were recorded, starting again from the first one after the last one.
+type Cassette struct {
+	Interactions []*CassetteInteraction `json:"interactions"`
+
+	mu       sync.Mutex
+	requests map[string]*cassetteResponses
+}
+
+// CassetteInteraction is a request and its recorded response.
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is a recorded request.
+type CassetteRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	CassetteBody
+}
+
+// CassetteResponse is a recorded response.
+type CassetteResponse struct {
+	Status  int         `json:"status"`
+	Proto   string      `json:"proto"`
+	Headers http.Header `json:"headers"`
+	CassetteBody
+}
+
+// CassetteBody is a recorded body. It's kept as text if it's valid UTF-8 and
+// in base64 otherwise.
+type CassetteBody struct {
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"bodyBase64,omitempty"`
+}
+
+func newCassetteBody(data []byte) CassetteBody {
+	if utf8.Valid(data) {
+		return CassetteBody{Body: string(data)}
+	}
+	return CassetteBody{BodyBase64: base64.StdEncoding.EncodeToString(data)}
+}
+
+func (b CassetteBody) bytes() ([]byte, error) {
+	if b.BodyBase64 != "" {
+		return base64.StdEncoding.DecodeString(b.BodyBase64)
+	}
+	return []byte(b.Body), nil
+}
+
+type cassetteResponses struct {
+	interactions []*CassetteInteraction
+	next         int
+}
+
+// NewCassette returns an empty cassette.
+func NewCassette() *Cassette {
+	return &Cassette{requests: make(map[string]*cassetteResponses)}
+}
+
+// LoadCassette returns the cassette from its JSON representation.
+func LoadCassette(data []byte) (*Cassette, error) {
+	c := NewCassette()
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid cassette: %w", err)
+	}
+	for _, i := range c.Interactions {
+		body, err := i.Request.bytes()
+		if err != nil {
+			return nil, fmt.Errorf("invalid body of the request to %s in the cassette: %w", i.Request.URL, err)
+		}
+		key := cassetteKey(i.Request.Method, i.Request.URL, body)
+		if c.requests[key] == nil {
+			c.requests[key] = &cassetteResponses{}
+		}
+		c.requests[key].interactions = append(c.requests[key].interactions, i)
+	}
+	return c, nil
+}
+
+// Marshal returns the JSON representation of the cassette.
+func (c *Cassette) Marshal() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return json.MarshalIndent(struct {
+		Interactions []*CassetteInteraction `json:"interactions"`
+	}{c.Interactions}, "", "  ")
+}
+
+func cassetteKey(method, url string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return method + " " + url + " " + hex.EncodeToString(sum[:])
+}
+
+// readRequestBody returns the body of the request, leaving it readable.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// RecordingTransport returns a transport that sends the requests with the
+// transport and records the responses to the cassette.
+func (c *Cassette) RecordingTransport(transport http.RoundTripper) http.RoundTripper {
+	return cassetteRecordingTransport{cassette: c, transport: transport}
+}
+
+// ReplayingTransport returns a transport that replays the responses from the
+// cassette, without sending the requests.
+func (c *Cassette) ReplayingTransport() http.RoundTripper {
+	return cassetteReplayingTransport{cassette: c}
+}
+
+type cassetteRecordingTransport struct {
+	cassette  *Cassette
+	transport http.RoundTripper
+}
+
+var _ transportWrapper = cassetteRecordingTransport{}
+
+func (t cassetteRecordingTransport) Unwrap() http.RoundTripper {
+	return t.transport
+}
+
+func (t cassetteRecordingTransport) Wrap(transport http.RoundTripper) http.RoundTripper {
+	return t.cassette.RecordingTransport(transport)
+}
+
+func (t cassetteRecordingTransport) CloseIdleConnections() {
+	closeIdleConnections(t.transport)
+}
+
+// RoundTrip sends the request and records the response as its body is read,
+// if there aren't already too many recorded responses for the same request.
+func (t cassetteRecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	url := req.URL.String()
+	key := cassetteKey(req.Method, url, reqBody)
+	t.cassette.mu.Lock()
+	responses := t.cassette.requests[key]
+	if responses == nil {
+		responses = &cassetteResponses{}
+		t.cassette.requests[key] = responses
+	}
+	full := len(responses.interactions) >= maxCassetteResponses
+	t.cassette.mu.Unlock()
+	if full {
+		return res, nil
+	}
+
+	status, proto, headers := res.StatusCode, res.Proto, res.Header.Clone()
+	res.Body = &cassetteRecordingBody{ReadCloser: res.Body, record: func(resBody []byte) {
+		interaction := &CassetteInteraction{
+			Request: CassetteRequest{Method: req.Method, URL: url, CassetteBody: newCassetteBody(reqBody)},
+			Response: CassetteResponse{
+				Status:       status,
+				Proto:        proto,
+				Headers:      headers,
+				CassetteBody: newCassetteBody(resBody),
+			},
+		}
+		t.cassette.mu.Lock()
+		if len(responses.interactions) < maxCassetteResponses {
+			responses.interactions = append(responses.interactions, interaction)
+			t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+		}
+		t.cassette.mu.Unlock()
+	}}
+
+	return res, nil
+}
+
+// cassetteRecordingBody keeps a copy of a response body as it's read, and
+// records it when it's read to the end. The responses whose bodies aren't
+// read to the end aren't recorded.
+type cassetteRecordingBody struct {
+	io.ReadCloser
+	data     bytes.Buffer
+	record   func([]byte)
+	recorded bool
+}
+
+func (b *cassetteRecordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.data.Write(p[:n])
+	if errors.Is(err, io.EOF) && !b.recorded {
+		b.recorded = true
+		b.record(b.data.Bytes())
+	}
+	return n, err
+}
+
+type cassetteReplayingTransport struct {
+	cassette *Cassette
+}
+
+var _ transportWrapper = cassetteReplayingTransport{}
+
+// Unwrap returns nil, since the requests aren't sent.
+func (t cassetteReplayingTransport) Unwrap() http.RoundTripper {
+	return nil
+}
+
+func (t cassetteReplayingTransport) Wrap(http.RoundTripper) http.RoundTripper {
+	return t
+}
+
+// RoundTrip returns the next recorded response for the request.
+func (t cassetteReplayingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := cassetteKey(req.Method, req.URL.String(), reqBody)
+	t.cassette.mu.Lock()
+	responses := t.cassette.requests[key]
+	var interaction *CassetteInteraction
+	if responses != nil && len(responses.interactions) > 0 {
+		interaction = responses.interactions[responses.next]
+		responses.next = (responses.next + 1) % len(responses.interactions)
+	}
+	t.cassette.mu.Unlock()
+	if interaction == nil {
+		return nil, fmt.Errorf("the cassette has no recorded response for %s %s", req.Method, req.URL)
+	}
+
+	body, err := interaction.Response.bytes()
+	if err != nil {
+		return nil, fmt.Errorf("invalid body of the response to %s %s in the cassette: %w", req.Method, req.URL, err)
+	}
+	res := &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+		StatusCode:    interaction.Response.Status,
+		Proto:         interaction.Response.Proto,
+		Header:        interaction.Response.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+	if res.Header == nil {
+		res.Header = make(http.Header)
+	}
+	var ok bool
+	if res.ProtoMajor, res.ProtoMinor, ok = http.ParseHTTPVersion(res.Proto); !ok {
+		res.Proto, res.ProtoMajor, res.ProtoMinor = "HTTP/1.1", 1, 1
+	}
+	return res, nil
+}
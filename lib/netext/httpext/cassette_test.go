@@ -0,0 +1,151 @@
+package httpext
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassette(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Request", r.Method+" "+string(body))
+		if r.URL.Path == "/binary" {
+			_, _ = w.Write([]byte{0xff, 0x00, 0xfe})
+			return
+		}
+		_, _ = fmt.Fprintf(w, "response %d", requests.Add(1))
+	}))
+	defer srv.Close()
+
+	roundTrip := func(t *testing.T, transport http.RoundTripper, method, path, body string) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body)) //nolint:noctx
+		require.NoError(t, err)
+		res, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		data, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, string(data)
+	}
+
+	recording := NewCassette()
+	recorder := recording.RecordingTransport(http.DefaultTransport)
+	_, body := roundTrip(t, recorder, http.MethodGet, "/", "")
+	assert.Equal(t, "response 1", body)
+	_, body = roundTrip(t, recorder, http.MethodGet, "/", "")
+	assert.Equal(t, "response 2", body)
+	_, body = roundTrip(t, recorder, http.MethodPost, "/", "data")
+	assert.Equal(t, "response 3", body)
+	_, body = roundTrip(t, recorder, http.MethodGet, "/binary", "")
+	assert.Equal(t, "\xff\x00\xfe", body)
+
+	data, err := recording.Marshal()
+	require.NoError(t, err)
+	cassette, err := LoadCassette(data)
+	require.NoError(t, err)
+	replayer := cassette.ReplayingTransport()
+
+	// the responses to the same request are replayed in turns
+	for _, want := range []string{"response 1", "response 2", "response 1"} {
+		res, body := roundTrip(t, replayer, http.MethodGet, "/", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "GET", res.Header.Get("X-Request"))
+		assert.Equal(t, want, body)
+	}
+	_, body = roundTrip(t, replayer, http.MethodPost, "/", "data")
+	assert.Equal(t, "response 3", body)
+	_, body = roundTrip(t, replayer, http.MethodGet, "/binary", "")
+	assert.Equal(t, "\xff\x00\xfe", body)
+	assert.Equal(t, int64(3), requests.Load())
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/", strings.NewReader("other")) //nolint:noctx
+	require.NoError(t, err)
+	_, err = replayer.RoundTrip(req) //nolint:bodyclose
+	require.ErrorContains(t, err, "the cassette has no recorded response for POST "+srv.URL+"/")
+}
+
+func TestCassetteRecordingLimit(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	cassette := NewCassette()
+	recorder := cassette.RecordingTransport(http.DefaultTransport)
+	for range maxCassetteResponses + 10 {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil) //nolint:noctx
+		require.NoError(t, err)
+		res, err := recorder.RoundTrip(req)
+		require.NoError(t, err)
+		_, err = io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+	}
+	assert.Len(t, cassette.Interactions, maxCassetteResponses)
+}
+
+func TestCassetteRecordingUnreadBody(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	cassette := NewCassette()
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil) //nolint:noctx
+	require.NoError(t, err)
+	res, err := cassette.RecordingTransport(http.DefaultTransport).RoundTrip(req)
+	require.NoError(t, err)
+	assert.Empty(t, cassette.Interactions, "the response must be recorded after its body is read")
+	require.NoError(t, res.Body.Close())
+	assert.Empty(t, cassette.Interactions)
+}
+
+func TestCassetteIsolatedTransport(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	vuTransport := &http.Transport{}
+	cassette := NewCassette()
+	isolated, err := isolateTransport(cassette.RecordingTransport(vuTransport), false, nil)
+	require.NoError(t, err)
+	recorder, ok := isolated.(cassetteRecordingTransport)
+	require.True(t, ok, "the isolated transport must record the responses too")
+	inner, ok := recorder.Unwrap().(*http.Transport)
+	require.True(t, ok)
+	assert.NotSame(t, vuTransport, inner)
+	assert.True(t, inner.DisableKeepAlives)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil) //nolint:noctx
+	require.NoError(t, err)
+	res, err := isolated.RoundTrip(req)
+	require.NoError(t, err)
+	_, err = io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	require.Len(t, cassette.Interactions, 1)
+	assert.Equal(t, "ok", cassette.Interactions[0].Response.Body)
+
+	replayer := cassette.ReplayingTransport()
+	isolated, err = isolateTransport(replayer, false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, replayer, isolated)
+}
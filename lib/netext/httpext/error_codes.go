@@ -1,6 +1,7 @@
 package httpext
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -28,6 +29,7 @@ const (
 	defaultNetNonTCPErrorCode errCode = 1010
 	invalidURLErrorCode       errCode = 1020
 	requestTimeoutErrorCode   errCode = 1050
+	requestCanceledErrorCode  errCode = 1051
 	// DNS errors
 	defaultDNSErrorCode      errCode = 1100
 	dnsNoSuchHostErrorCode   errCode = 1101
@@ -82,6 +84,7 @@ const (
 	x509HostnameErrorCodeMsg    = "x509: certificate doesn't match hostname"
 	x509UnknownAuthority        = "x509: unknown authority"
 	requestTimeoutErrorCodeMsg  = "request timeout"
+	requestCanceledErrorCodeMsg = "request canceled"
 	invalidURLErrorCodeMsg      = "invalid URL"
 )
 
@@ -201,6 +204,28 @@ func errorCodeForError(err error) (errCode, string) {
 	}
 }
 
+// ErrRequestCanceled is the cause with which the context of a request is
+// canceled by the script, e.g. with an AbortSignal, so the request's error is
+// classified as canceled.
+var ErrRequestCanceled = errors.New(requestCanceledErrorCodeMsg)
+
+// wrapCanceledError returns a K6Error for err if the request was canceled
+// with ErrRequestCanceled, or because one of its phases timed out.
+func wrapCanceledError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	cause := context.Cause(ctx)
+	if errors.Is(cause, ErrRequestCanceled) {
+		return NewK6Error(requestCanceledErrorCode, requestCanceledErrorCodeMsg, err)
+	}
+	var phaseErr *PhaseTimeoutError
+	if errors.As(cause, &phaseErr) {
+		return NewK6Error(requestTimeoutErrorCode, requestTimeoutErrorCodeMsg+": "+phaseErr.Error(), phaseErr)
+	}
+	return err
+}
+
 // K6Error is a helper struct that enhances Go errors with custom k6-specific
 // error-codes and more user-readable error messages.
 type K6Error struct {
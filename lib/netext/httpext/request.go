@@ -3,6 +3,7 @@ package httpext
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -15,9 +16,12 @@ import (
 
 	"github.com/Azure/go-ntlmssp"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http2"
 	"gopkg.in/guregu/null.v3"
 
 	"go.k6.io/k6/lib"
+	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
 
@@ -38,10 +42,16 @@ type Request struct {
 
 // ParsedHTTPRequest is a representation of a request after it has been parsed from a user script.
 type ParsedHTTPRequest struct {
-	URL              *URL
-	Body             *bytes.Buffer
-	Req              *http.Request
-	Timeout          time.Duration
+	URL     *URL
+	Body    *bytes.Buffer
+	Req     *http.Request
+	Timeout time.Duration
+	// Timeouts are the timeouts of the phases of the request, if they are
+	// set, the overall Timeout only applies if it isn't zero.
+	Timeouts *RequestTimeouts
+	// SLA is the maximum duration of the request, if it's set a check of
+	// whether the request met it is emitted, named after the request's name.
+	SLA              time.Duration
 	Auth             string
 	Throw            bool
 	ResponseType     ResponseType
@@ -51,6 +61,13 @@ type ParsedHTTPRequest struct {
 	ActiveJar        *cookiejar.Jar
 	Cookies          map[string]*HTTPRequestCookie
 	TagsAndMeta      metrics.TagsAndMeta
+	// ResolveTo is the address to connect to instead of resolving the host of
+	// the URL, the hosts of the redirects are resolved as usual.
+	ResolveTo *types.Host
+	// VirtualHost is sent as the Host header and the TLS server name of the
+	// requests to the host of the URL, which connect to the address of its
+	// hosts option entry, if it has one.
+	VirtualHost string
 }
 
 // ncloser matches non-compliant io.Closer implementations (e.g. zstd.Decoder).
@@ -95,7 +112,14 @@ func updateK6Response(k6Response *Response, finishedReq *finishedRequest) {
 		k6Response.RemoteIP = remoteHost
 		k6Response.RemotePort = remotePort
 	}
-	k6Response.Timings = ResponseTimings{
+	k6Response.Timings = trailTimings(trail)
+	if resolution := finishedReq.resolution.Resolution(); resolution != nil {
+		k6Response.Resolution = *resolution
+	}
+}
+
+func trailTimings(trail *Trail) ResponseTimings {
+	return ResponseTimings{
 		Duration:       metrics.D(trail.Duration),
 		Blocked:        metrics.D(trail.Blocked),
 		Connecting:     metrics.D(trail.Connecting),
@@ -184,6 +208,32 @@ func MakeRequest(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest)
 	tracerTransport := newTransport(ctx, state, &preq.TagsAndMeta, preq.ResponseCallback)
 	var transport http.RoundTripper = tracerTransport
 
+	if preq.ResolveTo != nil {
+		resolveToTransport, err := isolateTransport(state.Transport, false, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer closeIdleConnections(resolveToTransport)
+		tracerTransport.roundTripper = resolveToTransport
+		ctx = netext.WithResolveTo(ctx, preq.Req.URL.Hostname(), *preq.ResolveTo)
+	}
+
+	if preq.VirtualHost != "" {
+		vhostTransport, err := newVirtualHostTransport(
+			state, tracerTransport.roundTripper, preq.Req.URL.Host, preq.VirtualHost)
+		if err != nil {
+			return nil, err
+		}
+		if state.IsolatedTransports == nil {
+			defer vhostTransport.CloseIdleConnections()
+		}
+		tracerTransport.roundTripper = vhostTransport
+		ctx = netext.WithVirtualHost(ctx, preq.Req.URL.Hostname(), preq.VirtualHost)
+		if state.Options.SystemTags.Has(metrics.TagVirtualHost) {
+			preq.TagsAndMeta.SetSystemTagOrMeta(metrics.TagVirtualHost, preq.VirtualHost)
+		}
+	}
+
 	if state.Options.HTTPDebug.String != "" {
 		// Combine tags with common log fields
 		combinedLogFields := map[string]interface{}{"source": "http-debug", "vu": state.VUID, "iter": state.Iteration}
@@ -227,6 +277,10 @@ func MakeRequest(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest)
 			}
 		}
 		transport = ntlmssp.Negotiator{RoundTripper: transport}
+	default:
+		if handler, ok := getAuthHandler(preq.Auth); ok {
+			transport = authSchemeTransport{originalTransport: transport, handler: handler}
+		}
 	}
 
 	resp := &Response{
@@ -234,6 +288,9 @@ func MakeRequest(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest)
 		Request: respReq,
 		Headers: make(map[string]string),
 		Cookies: make(map[string][]*HTTPCookie),
+		TLS:     ResponseTLS{PeerCertificates: []netext.Certificate{}},
+
+		RedirectChain: []RedirectHop{},
 	}
 	client := http.Client{
 		Transport: transport,
@@ -265,7 +322,16 @@ func MakeRequest(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest)
 		},
 	}
 
-	reqCtx, cancelFunc := context.WithTimeout(ctx, preq.Timeout)
+	// The time spent waiting for the limiter isn't part of the request's timeout.
+	if state.RequestLimiter != nil {
+		release, err := state.RequestLimiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	reqCtx, phases, cancelFunc := requestContext(ctx, preq)
 	defer cancelFunc()
 	mreq := preq.Req.WithContext(reqCtx)
 	res, resErr := client.Do(mreq)
@@ -280,16 +346,26 @@ func MakeRequest(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest)
 	}
 
 	if resErr == nil {
+		phases.start(PhaseBody)
 		resp.Body, resErr = readResponseBody(state, preq.ResponseType, res, resErr)
+		phases.stop(PhaseBody)
 		if resErr != nil && errors.Is(resErr, context.DeadlineExceeded) {
 			// TODO This can be more specific that the timeout happened in the middle of the reading of the body
 			resErr = NewK6Error(requestTimeoutErrorCode, requestTimeoutErrorCodeMsg, resErr)
+		} else {
+			resErr = wrapCanceledError(reqCtx, resErr)
 		}
 	}
 	finishedReq := tracerTransport.processLastSavedRequest(wrapDecompressionError(resErr))
 	if finishedReq != nil {
 		updateK6Response(resp, finishedReq)
 	}
+	if preq.SLA > 0 {
+		emitSLACheck(ctx, state, preq, finishedReq)
+	}
+	if hops := tracerTransport.hops; len(hops) > 1 {
+		resp.RedirectChain = hops
+	}
 
 	if resErr == nil {
 		if preq.ActiveJar != nil {
@@ -360,3 +436,124 @@ func SetRequestCookies(req *http.Request, jar *cookiejar.Jar, reqCookies map[str
 		}
 	}
 }
+
+// transportWrapper is implemented by the VU's transports that wrap another
+// transport, like the ones of the HTTP cassettes, so the requests that need
+// connections of their own can wrap a copy of the wrapped transport in the
+// same way.
+type transportWrapper interface {
+	http.RoundTripper
+	// Unwrap returns the wrapped transport, or nil if the requests aren't sent.
+	Unwrap() http.RoundTripper
+	// Wrap returns a transport that wraps the given one in the same way.
+	Wrap(http.RoundTripper) http.RoundTripper
+}
+
+// isolateTransport returns a transport for the requests that have to connect
+// to other addresses than the VU's requests to the same hosts. It's a copy of
+// the VU's transport, changed by configure, and wrapped like the VU's
+// transport. The VU's transport is returned as it is if it doesn't send the
+// requests.
+func isolateTransport(
+	vuTransport http.RoundTripper, keepAlive bool, configure func(*http.Transport),
+) (http.RoundTripper, error) {
+	wrapper, isWrapper := vuTransport.(transportWrapper)
+	if isWrapper {
+		if vuTransport = wrapper.Unwrap(); vuTransport == nil {
+			return wrapper, nil
+		}
+	}
+	isolated, err := newIsolatedTransport(vuTransport, keepAlive)
+	if err != nil {
+		return nil, err
+	}
+	if configure != nil {
+		configure(isolated)
+	}
+	if isWrapper {
+		return wrapper.Wrap(isolated), nil
+	}
+	return isolated, nil
+}
+
+func closeIdleConnections(transport http.RoundTripper) {
+	if t, ok := transport.(interface{ CloseIdleConnections() }); ok {
+		t.CloseIdleConnections()
+	}
+}
+
+// newIsolatedTransport returns a copy of the VU's transport that doesn't share
+// any connections with it, and keeps them alive only if keepAlive is set.
+func newIsolatedTransport(vuTransport http.RoundTripper, keepAlive bool) (*http.Transport, error) {
+	t, ok := vuTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("connecting to another address isn't supported by the VU's transport")
+	}
+	isolated := t.Clone()
+	isolated.DisableKeepAlives = isolated.DisableKeepAlives || !keepAlive
+	if _, h2 := t.TLSNextProto["h2"]; h2 {
+		// the copied HTTP/2 upgrade would add the connections to the VU's pool
+		isolated.TLSNextProto = nil
+		if err := http2.ConfigureTransport(isolated); err != nil {
+			return nil, err
+		}
+	}
+	return isolated, nil
+}
+
+// virtualHostTransport sends the requests to the host of the URL with the
+// virtual host as their Host header and TLS server name, through connections
+// of their own. The requests to the other hosts, after a redirect, are sent
+// with the next transport.
+type virtualHostTransport struct {
+	host, virtualHost string
+	isolated          http.RoundTripper
+	next              http.RoundTripper
+}
+
+// virtualHostKey is the key of the isolated transports of the VU for the
+// requests to a host with a virtual host.
+type virtualHostKey struct {
+	host, virtualHost string
+}
+
+// newVirtualHostTransport returns the transport for the requests to the host
+// with the virtual host. Its connections are reused by the next requests of
+// the VU with the same host and virtual host, if the VU keeps the isolated
+// transports.
+func newVirtualHostTransport(
+	state *lib.State, next http.RoundTripper, host, virtualHost string,
+) (*virtualHostTransport, error) {
+	newTransport := func() (http.RoundTripper, error) {
+		return isolateTransport(state.Transport, state.IsolatedTransports != nil, func(t *http.Transport) {
+			if t.TLSClientConfig == nil {
+				t.TLSClientConfig = &tls.Config{} //nolint:gosec
+			}
+			t.TLSClientConfig.ServerName = virtualHost
+		})
+	}
+	var isolated http.RoundTripper
+	var err error
+	if state.IsolatedTransports != nil {
+		isolated, err = state.IsolatedTransports.Get(virtualHostKey{host: host, virtualHost: virtualHost}, newTransport)
+	} else {
+		isolated, err = newTransport()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &virtualHostTransport{host: host, virtualHost: virtualHost, isolated: isolated, next: next}, nil
+}
+
+func (t *virtualHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.EqualFold(req.URL.Host, t.host) {
+		return t.next.RoundTrip(req)
+	}
+	vhostReq := req.Clone(req.Context())
+	vhostReq.Host = t.virtualHost
+	return t.isolated.RoundTrip(vhostReq)
+}
+
+func (t *virtualHostTransport) CloseIdleConnections() {
+	closeIdleConnections(t.isolated)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -289,6 +290,58 @@ func TestMakeRequestTimeoutInTheMiddle(t *testing.T) {
 	}
 }
 
+func TestMakeRequestRequestLimiter(t *testing.T) {
+	t.Parallel()
+	var active, maxActive int64
+	var mu sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		active++
+		maxActive = max(maxActive, active)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	samples := make(chan metrics.SampleContainer, 100)
+	logger := logrus.New()
+	logger.Out = io.Discard
+	registry := metrics.NewRegistry()
+	limiter := lib.NewRequestLimiter(2)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			state := &lib.State{
+				Options:        lib.Options{SystemTags: &metrics.DefaultSystemTagSet},
+				RequestLimiter: limiter.Scenario("default", 1),
+				Transport:      ts.Client().Transport,
+				Samples:        samples,
+				Logger:         logger,
+				BufferPool:     lib.NewBufferPool(),
+				BuiltinMetrics: metrics.RegisterBuiltinMetrics(registry),
+				Tags:           lib.NewVUStateTags(registry.RootTagSet()),
+			}
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, ts.URL, nil)
+			preq := &ParsedHTTPRequest{
+				Req:         req,
+				URL:         &URL{u: req.URL, URL: ts.URL, Name: ts.URL},
+				Timeout:     time.Second,
+				TagsAndMeta: state.Tags.GetCurrentValues(),
+			}
+			_, err := MakeRequest(context.Background(), state, preq)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(2), maxActive)
+}
+
 func BenchmarkWrapDecompressionError(b *testing.B) {
 	err := errors.New("error")
 	b.ResetTimer()
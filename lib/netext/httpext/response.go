@@ -43,6 +43,14 @@ type ResponseTimings struct {
 	Receiving      float64 `json:"receiving"`
 }
 
+// RedirectHop holds the details of a single round trip in a redirect chain.
+type RedirectHop struct {
+	Method  string          `json:"method"`
+	URL     string          `json:"url"`
+	Status  int             `json:"status"`
+	Timings ResponseTimings `json:"timings"`
+}
+
 // HTTPCookie is a representation of an http cookies used in the Response object
 type HTTPCookie struct {
 	Name, Value, Domain, Path string
@@ -66,9 +74,26 @@ type Response struct {
 	TLSVersion     string                   `json:"tls_version"`
 	TLSCipherSuite string                   `json:"tls_cipher_suite"`
 	OCSP           netext.OCSP              `json:"ocsp"`
+	TLS            ResponseTLS              `json:"tls"`
+	Resolution     netext.Resolution        `json:"resolution"`
 	Error          string                   `json:"error"`
 	ErrorCode      int                      `json:"error_code"`
 	Request        *Request                 `json:"request"`
+
+	// RedirectChain contains every round trip made for the request, in order,
+	// when any redirects were followed, and is empty otherwise. The last hop is
+	// the response itself.
+	RedirectChain []RedirectHop `json:"redirect_chain"`
+}
+
+// ResponseTLS holds the TLS details of a response, its version is empty for
+// the responses that weren't received over TLS.
+type ResponseTLS struct {
+	Version          string               `json:"version"`
+	CipherSuite      string               `json:"cipher_suite"`
+	Resumed          bool                 `json:"resumed"`
+	OCSP             netext.OCSP          `json:"ocsp"`
+	PeerCertificates []netext.Certificate `json:"peer_certificates"`
 }
 
 // NewResponse returns an empty Response instance.
@@ -77,6 +102,9 @@ func NewResponse() *Response {
 		Headers: make(map[string]string),
 		Cookies: make(map[string][]*HTTPCookie),
 		Body:    []byte{},
+		TLS:     ResponseTLS{PeerCertificates: []netext.Certificate{}},
+
+		RedirectChain: []RedirectHop{},
 	}
 }
 
@@ -85,4 +113,11 @@ func (res *Response) setTLSInfo(tlsState *tls.ConnectionState) {
 	res.TLSVersion = tlsInfo.Version
 	res.TLSCipherSuite = tlsInfo.CipherSuite
 	res.OCSP = oscp
+	res.TLS = ResponseTLS{
+		Version:          tlsInfo.Version,
+		CipherSuite:      tlsInfo.CipherSuite,
+		Resumed:          tlsInfo.Resumed,
+		OCSP:             oscp,
+		PeerCertificates: tlsInfo.PeerCertificates,
+	}
 }
@@ -0,0 +1,62 @@
+package httpext
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.k6.io/k6/lib"
+	"go.k6.io/k6/metrics"
+)
+
+// slaCheckName returns the name of the check of a request's SLA, which
+// includes the name of the request, so that the checks are aggregated per URL
+// name.
+func slaCheckName(name string, sla time.Duration) string {
+	return fmt.Sprintf("%s responded within %s", name, sla)
+}
+
+// slaName returns the name of the request for its SLA check, which is its name
+// tag or the name of the URL made with http.url.
+func slaName(preq *ParsedHTTPRequest) (string, bool) {
+	if name, ok := preq.TagsAndMeta.Tags.Get(metrics.TagName.String()); ok {
+		return name, true
+	}
+	if preq.URL.Name != "" && preq.URL.Name != preq.URL.Clean() {
+		return preq.URL.Name, true
+	}
+	return "", false
+}
+
+// HasSLAName returns whether the request has a name for its SLA check. The
+// requests without one can't have an SLA, since the checks would be per URL.
+func HasSLAName(preq *ParsedHTTPRequest) bool {
+	_, ok := slaName(preq)
+	return ok
+}
+
+// emitSLACheck emits the check of whether the request met its SLA, i.e. it
+// didn't fail and its final response took at most the SLA.
+func emitSLACheck(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest, finishedReq *finishedRequest) {
+	name, _ := slaName(preq)
+
+	tags := preq.TagsAndMeta.Tags
+	if state.Options.SystemTags.Has(metrics.TagName) {
+		tags = tags.With(metrics.TagName.String(), name)
+	}
+	if state.Options.SystemTags.Has(metrics.TagCheck) {
+		tags = tags.With(metrics.TagCheck.String(), slaCheckName(name, preq.SLA))
+	}
+
+	t, met := time.Now(), false
+	if finishedReq != nil {
+		t = finishedReq.trail.EndTime
+		met = finishedReq.err == nil && finishedReq.trail.Duration <= preq.SLA
+	}
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{Metric: state.BuiltinMetrics.Checks, Tags: tags},
+		Time:       t,
+		Metadata:   preq.TagsAndMeta.Metadata,
+		Value:      metrics.B(met),
+	})
+}
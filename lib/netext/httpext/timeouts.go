@@ -0,0 +1,147 @@
+package httpext
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// The phases of a request that can have their own timeouts.
+const (
+	PhaseConnect      = "connect"
+	PhaseTLSHandshake = "tlsHandshake"
+	PhaseHeaders      = "headers"
+	PhaseBody         = "body"
+)
+
+// RequestTimeouts are the timeouts of the phases of a request, a zero timeout
+// doesn't limit its phase. The connect and TLS handshake timeouts apply to
+// each new connection, the headers timeout is the time from sending the
+// request to receiving the headers of the response, for every redirect, and
+// the body timeout is the time it takes to read the response body.
+type RequestTimeouts struct {
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	Headers      time.Duration
+	Body         time.Duration
+}
+
+// PhaseTimeoutError is the cause with which the context of a request is
+// canceled when one of its phases takes longer than its timeout.
+type PhaseTimeoutError struct {
+	Phase   string
+	Timeout time.Duration
+}
+
+func (e *PhaseTimeoutError) Error() string {
+	return fmt.Sprintf("the %s phase took longer than %s", e.Phase, e.Timeout)
+}
+
+// requestContext returns the context of a request, which is canceled when the
+// request takes longer than its timeout or, if it has timeouts for its phases,
+// when one of them takes longer than its own. Without an overall timeout, only
+// the timeouts of the phases apply.
+func requestContext(ctx context.Context, preq *ParsedHTTPRequest) (context.Context, *phaseTimer, func()) {
+	if preq.Timeouts == nil {
+		reqCtx, cancel := context.WithTimeout(ctx, preq.Timeout)
+		return reqCtx, nil, cancel
+	}
+
+	var (
+		reqCtx        context.Context
+		cancelOverall context.CancelFunc
+	)
+	if preq.Timeout > 0 {
+		reqCtx, cancelOverall = context.WithTimeout(ctx, preq.Timeout)
+	} else {
+		reqCtx, cancelOverall = context.WithCancel(ctx)
+	}
+	reqCtx, cancel := context.WithCancelCause(reqCtx)
+	timer := &phaseTimer{timeouts: *preq.Timeouts, cancel: cancel, timers: make(map[string]*time.Timer)}
+	// nosemgrep: dynamic-httptrace-clienttrace // the hooks only start and stop the timers
+	reqCtx = httptrace.WithClientTrace(reqCtx, &httptrace.ClientTrace{
+		ConnectStart:         func(string, string) { timer.start(PhaseConnect) },
+		ConnectDone:          func(string, string, error) { timer.stop(PhaseConnect) },
+		TLSHandshakeStart:    func() { timer.start(PhaseTLSHandshake) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { timer.stop(PhaseTLSHandshake) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { timer.start(PhaseHeaders) },
+		GotFirstResponseByte: func() { timer.stop(PhaseHeaders) },
+	})
+	return reqCtx, timer, func() {
+		timer.stopAll()
+		cancel(nil)
+		cancelOverall()
+	}
+}
+
+// phaseTimer cancels the context of a request when one of its phases takes
+// longer than its timeout. It's safe for concurrent use, since the hooks of
+// the connections are called by the goroutines that dial them.
+type phaseTimer struct {
+	timeouts RequestTimeouts
+	cancel   context.CancelCauseFunc
+
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+}
+
+func (pt *phaseTimer) timeout(phase string) time.Duration {
+	switch phase {
+	case PhaseConnect:
+		return pt.timeouts.Connect
+	case PhaseTLSHandshake:
+		return pt.timeouts.TLSHandshake
+	case PhaseHeaders:
+		return pt.timeouts.Headers
+	case PhaseBody:
+		return pt.timeouts.Body
+	default:
+		return 0
+	}
+}
+
+// start starts the timer of the phase, if it has a timeout. It's a no-op on a
+// nil phaseTimer, so it can be used for the requests without phase timeouts.
+func (pt *phaseTimer) start(phase string) {
+	if pt == nil {
+		return
+	}
+	timeout := pt.timeout(phase)
+	if timeout <= 0 {
+		return
+	}
+
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if t, ok := pt.timers[phase]; ok {
+		t.Stop()
+	}
+	pt.timers[phase] = time.AfterFunc(timeout, func() {
+		pt.cancel(&PhaseTimeoutError{Phase: phase, Timeout: timeout})
+	})
+}
+
+// stop stops the timer of the phase, if it was started.
+func (pt *phaseTimer) stop(phase string) {
+	if pt == nil {
+		return
+	}
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if t, ok := pt.timers[phase]; ok {
+		t.Stop()
+		delete(pt.timers, phase)
+	}
+}
+
+func (pt *phaseTimer) stopAll() {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	for phase, t := range pt.timers {
+		t.Stop()
+		delete(pt.timers, phase)
+	}
+}
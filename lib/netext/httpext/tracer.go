@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"go.k6.io/k6/lib/netext"
 	"go.k6.io/k6/metrics"
 	"gopkg.in/guregu/null.v3"
 )
@@ -156,6 +157,7 @@ type Tracer struct {
 
 	connReused     bool
 	connRemoteAddr net.Addr
+	conn           net.Conn
 }
 
 // Trace returns a premade ClientTrace that calls all of the Tracer's hooks.
@@ -167,6 +169,7 @@ func (t *Tracer) Trace() *httptrace.ClientTrace {
 		TLSHandshakeStart:    t.TLSHandshakeStart,
 		TLSHandshakeDone:     t.TLSHandshakeDone,
 		GotConn:              t.GotConn,
+		PutIdleConn:          t.PutIdleConn,
 		WroteRequest:         t.WroteRequest,
 		GotFirstResponseByte: t.GotFirstResponseByte,
 	}
@@ -261,6 +264,8 @@ func (t *Tracer) GotConn(info httptrace.GotConnInfo) {
 	t.gotConn = now
 	t.connReused = info.Reused
 	t.connRemoteAddr = info.Conn.RemoteAddr()
+	t.conn = info.Conn
+	netext.SetConnIdle(info.Conn, false)
 
 	// The Go stdlib's http module can start connecting to a remote server, only
 	// to abandon that connection even before it was fully established and reuse
@@ -293,6 +298,15 @@ func (t *Tracer) GotConn(info httptrace.GotConnInfo) {
 	}
 }
 
+// PutIdleConn is called when the connection is returned to
+// the idle pool. It isn't called for HTTP/2 connections, so
+// they are always in use for the connection metrics.
+func (t *Tracer) PutIdleConn(err error) {
+	if err == nil && t.conn != nil {
+		netext.SetConnIdle(t.conn, true)
+	}
+}
+
 // WroteRequest is called with the result of writing the
 // request and any body. It may be called multiple times
 // in the case of retried requests.
@@ -12,6 +12,7 @@ import (
 
 	"go.k6.io/k6/lib"
 	"go.k6.io/k6/lib/netext"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
 
@@ -23,18 +24,27 @@ type transport struct {
 	tagsAndMeta      *metrics.TagsAndMeta
 	responseCallback func(int) bool
 
+	// roundTripper makes the requests, it's the state's Transport unless the
+	// request needs connections of its own.
+	roundTripper http.RoundTripper
+
 	lastRequest     *unfinishedRequest
 	lastRequestLock *sync.Mutex
+
+	// hops holds the details of every round trip done for the request so far,
+	// e.g. because of redirects.
+	hops []RedirectHop
 }
 
 // unfinishedRequest stores the request and the raw result returned from the
 // underlying http.RoundTripper, but before its body has been read
 type unfinishedRequest struct {
-	ctx      context.Context
-	tracer   *Tracer
-	request  *http.Request
-	response *http.Response
-	err      error
+	ctx        context.Context
+	tracer     *Tracer
+	resolution *netext.ResolutionTrace
+	request    *http.Request
+	response   *http.Response
+	err        error
 }
 
 // finishedRequest is produced once the request has been finalized; it is
@@ -66,6 +76,7 @@ func newTransport(
 		state:            state,
 		tagsAndMeta:      tagsAndMeta,
 		responseCallback: responseCallback,
+		roundTripper:     state.Transport,
 		lastRequestLock:  new(sync.Mutex),
 	}
 }
@@ -101,6 +112,7 @@ func (t *transport) measureAndEmitMetrics(unfReq *unfinishedRequest) *finishedRe
 	}
 
 	tagsAndMeta.SetSystemTagOrMetaIfEnabled(enabledTags, metrics.TagMethod, unfReq.request.Method)
+	tagsAndMeta.SetSystemTagOrMetaIfEnabled(enabledTags, metrics.TagRedirectHop, strconv.Itoa(len(t.hops)))
 
 	if unfReq.err != nil {
 		result.errorCode, result.errorMsg = errorCodeForError(unfReq.err)
@@ -119,21 +131,30 @@ func (t *transport) measureAndEmitMetrics(unfReq *unfinishedRequest) *finishedRe
 			tlsInfo, oscp := netext.ParseTLSConnState(unfReq.response.TLS)
 			tagsAndMeta.SetSystemTagOrMetaIfEnabled(enabledTags, metrics.TagTLSVersion, tlsInfo.Version)
 			tagsAndMeta.SetSystemTagOrMetaIfEnabled(enabledTags, metrics.TagOCSPStatus, oscp.Status)
+			tagsAndMeta.SetSystemTagOrMetaIfEnabled(enabledTags, metrics.TagTLSResumed, strconv.FormatBool(tlsInfo.Resumed))
 			result.tlsInfo = tlsInfo
 		}
 	}
-	if enabledTags.Has(metrics.TagIP) && trail.ConnRemoteAddr != nil {
+	if trail.ConnRemoteAddr != nil {
 		if ip, _, err := net.SplitHostPort(trail.ConnRemoteAddr.String()); err == nil {
-			tagsAndMeta.SetSystemTagOrMeta(metrics.TagIP, ip)
+			tagsAndMeta.SetSystemTagOrMetaIfEnabled(enabledTags, metrics.TagIP, ip)
+			if parsed := net.ParseIP(ip); parsed != nil {
+				tagsAndMeta.SetSystemTagOrMetaIfEnabled(enabledTags, metrics.TagIPFamily, types.IPFamily(parsed))
+			}
 		}
 	}
+	var statusCode int
+	if unfReq.err == nil {
+		statusCode = unfReq.response.StatusCode
+	}
+	// The expectedResponses rules classify the responses even when there's no response callback
+	expected, classified := t.state.Options.ExpectedResponses.Classify(
+		unfReq.request.Method, unfReq.request.URL.String(), statusCode)
+	if !classified && t.responseCallback != nil {
+		expected, classified = t.responseCallback(statusCode), true
+	}
 	var failed float64
-	if t.responseCallback != nil {
-		var statusCode int
-		if unfReq.err == nil {
-			statusCode = unfReq.response.StatusCode
-		}
-		expected := t.responseCallback(statusCode)
+	if classified {
 		if !expected {
 			failed = 1
 		}
@@ -141,8 +162,19 @@ func (t *transport) measureAndEmitMetrics(unfReq *unfinishedRequest) *finishedRe
 		tagsAndMeta.SetSystemTagOrMetaIfEnabled(enabledTags, metrics.TagExpectedResponse, strconv.FormatBool(expected))
 	}
 
+	hop := RedirectHop{
+		Method:  unfReq.request.Method,
+		URL:     unfReq.request.URL.String(),
+		Timings: trailTimings(trail),
+	}
+	if unfReq.err == nil {
+		hop.Status = unfReq.response.StatusCode
+	}
+	t.hops = append(t.hops, hop)
+	t.state.IterationBreakdown.AddNetwork(trail.Blocked + trail.Duration)
+
 	trail.SaveSamples(t.state.BuiltinMetrics, &tagsAndMeta)
-	if t.responseCallback != nil {
+	if classified {
 		trail.Failed.Valid = true
 		if failed == 1 {
 			trail.Failed.Bool = true
@@ -159,6 +191,19 @@ func (t *transport) measureAndEmitMetrics(unfReq *unfinishedRequest) *finishedRe
 			},
 		)
 	}
+	if days, ok := result.tlsInfo.DaysToExpiry(); ok {
+		trail.Samples = append(trail.Samples,
+			metrics.Sample{
+				TimeSeries: metrics.TimeSeries{
+					Metric: t.state.BuiltinMetrics.HTTPReqTLSCertDaysToExpiry,
+					Tags:   tagsAndMeta.Tags,
+				},
+				Time:     trail.EndTime,
+				Metadata: tagsAndMeta.Metadata,
+				Value:    days,
+			},
+		)
+	}
 	metrics.PushIfNotDone(t.ctx, t.state.Samples, trail)
 	return result
 }
@@ -201,9 +246,11 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	ctx := req.Context()
 	tracer := &Tracer{}
+	resolution := &netext.ResolutionTrace{}
 	// nosemgrep: dynamic-httptrace-clienttrace // this is a false possitive
-	reqWithTracer := req.WithContext(httptrace.WithClientTrace(ctx, tracer.Trace()))
-	resp, err := t.state.Transport.RoundTrip(reqWithTracer)
+	reqWithTracer := req.WithContext(httptrace.WithClientTrace(
+		netext.WithResolutionTrace(ctx, resolution), tracer.Trace()))
+	resp, err := t.roundTripper.RoundTrip(reqWithTracer)
 
 	var netError net.Error
 	if errors.As(err, &netError) && netError.Timeout() {
@@ -213,14 +260,17 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		} else {
 			err = NewK6Error(requestTimeoutErrorCode, requestTimeoutErrorCodeMsg, netError)
 		}
+	} else {
+		err = wrapCanceledError(ctx, err)
 	}
 
 	t.saveCurrentRequest(&unfinishedRequest{
-		ctx:      ctx,
-		tracer:   tracer,
-		request:  req,
-		response: resp,
-		err:      err,
+		ctx:        ctx,
+		tracer:     tracer,
+		resolution: resolution,
+		request:    req,
+		response:   resp,
+		err:        err,
 	})
 
 	return resp, err
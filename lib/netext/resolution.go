@@ -0,0 +1,117 @@
+package netext
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.k6.io/k6/lib/types"
+)
+
+// The sources of the addresses that the dialer connects to.
+const (
+	ResolutionSourceDNS       = "dns"
+	ResolutionSourceHosts     = "hosts"
+	ResolutionSourceIP        = "ip"
+	ResolutionSourceResolveTo = "resolveTo"
+	// ResolutionSourceVirtualHost is the hosts option entry of the virtual
+	// host of the request, instead of the entry of its host.
+	ResolutionSourceVirtualHost = "virtualHost"
+)
+
+// Resolution describes how the dialer resolved the address of a connection. The
+// responses of the requests that reused a connection have an empty one.
+type Resolution struct {
+	// Host is the host that was resolved.
+	Host string `json:"host"`
+	// Address is the address that the dialer connected to.
+	Address string `json:"address"`
+	// Source is where the address came from: the DNS, the hosts option, the
+	// host itself if it's an IP, a resolveTo override or the hosts option
+	// entry of a virtual host.
+	Source string `json:"source"`
+	// Duration is how long the resolution took, in milliseconds.
+	Duration float64 `json:"duration"`
+}
+
+// ResolutionTrace records the resolution of the connection dialed for a
+// request, if any connection was dialed. It's safe for concurrent use.
+type ResolutionTrace struct {
+	resolution atomic.Pointer[Resolution]
+}
+
+// Resolution returns the recorded resolution, or nil if the request reused a
+// connection.
+func (t *ResolutionTrace) Resolution() *Resolution {
+	return t.resolution.Load()
+}
+
+func (t *ResolutionTrace) record(host string, remote *types.Host, source string, duration time.Duration) {
+	t.resolution.Store(&Resolution{
+		Host:     host,
+		Address:  remote.String(),
+		Source:   source,
+		Duration: float64(duration) / float64(time.Millisecond),
+	})
+}
+
+type resolutionCtxKey int
+
+const (
+	ctxKeyResolveTo resolutionCtxKey = iota
+	ctxKeyResolutionTrace
+	ctxKeyVirtualHost
+)
+
+type resolveTo struct {
+	host string
+	to   types.Host
+}
+
+// WithResolveTo returns a context with which the dialer connects to the address
+// instead of resolving the host. The port of the dialed address is kept if the
+// address doesn't have one. The other hosts are resolved as usual.
+func WithResolveTo(ctx context.Context, host string, to types.Host) context.Context {
+	return context.WithValue(ctx, ctxKeyResolveTo, &resolveTo{host: host, to: to})
+}
+
+type virtualHost struct {
+	host, virtualHost string
+}
+
+// WithVirtualHost returns a context with which the dialer connects to the
+// address of the hosts option entry of the virtual host, including the
+// wildcard ones, instead of resolving the host. The host is resolved as usual
+// if the virtual host doesn't have an entry.
+func WithVirtualHost(ctx context.Context, host, vhost string) context.Context {
+	return context.WithValue(ctx, ctxKeyVirtualHost, &virtualHost{host: host, virtualHost: vhost})
+}
+
+// WithResolutionTrace returns a context with which the dialer records its
+// resolutions in the trace.
+func WithResolutionTrace(ctx context.Context, trace *ResolutionTrace) context.Context {
+	return context.WithValue(ctx, ctxKeyResolutionTrace, trace)
+}
+
+func resolveToFor(ctx context.Context, host string) *types.Host {
+	override, ok := ctx.Value(ctxKeyResolveTo).(*resolveTo)
+	if !ok || !strings.EqualFold(override.host, host) {
+		return nil
+	}
+	to := override.to
+	return &to
+}
+
+func virtualHostFor(ctx context.Context, host string) string {
+	override, ok := ctx.Value(ctxKeyVirtualHost).(*virtualHost)
+	if !ok || !strings.EqualFold(override.host, host) {
+		return ""
+	}
+	return override.virtualHost
+}
+
+func resolutionTraceFrom(ctx context.Context) *ResolutionTrace {
+	trace, _ := ctx.Value(ctxKeyResolutionTrace).(*ResolutionTrace)
+	return trace
+}
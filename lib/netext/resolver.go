@@ -17,6 +17,13 @@ type Resolver interface {
 	LookupIP(host string) (net.IP, error)
 }
 
+// FamilyResolver is a Resolver that can also return only addresses of one
+// address family, for the dialFamily option.
+type FamilyResolver interface {
+	Resolver
+	LookupIPFamily(host, family string) (net.IP, error)
+}
+
 type resolver struct {
 	resolve     MultiResolver
 	selectIndex types.DNSSelect
@@ -67,12 +74,19 @@ func NewResolver(
 // LookupIP returns a single IP resolved for host, selected according to the
 // configured select and policy options.
 func (r *resolver) LookupIP(host string) (net.IP, error) {
+	return r.LookupIPFamily(host, types.DialFamilyAny)
+}
+
+// LookupIPFamily returns a single IP of the address family resolved for host,
+// selected according to the configured select and policy options. The policy
+// is applied to the addresses of the family, so it can't exclude them.
+func (r *resolver) LookupIPFamily(host, family string) (net.IP, error) {
 	ips, err := r.resolve(host)
 	if err != nil {
 		return nil, err
 	}
 
-	ips = r.applyPolicy(ips)
+	ips = r.applyPolicy(filterFamily(ips, family))
 	return r.selectOne(host, ips), nil
 }
 
@@ -81,6 +95,12 @@ func (r *resolver) LookupIP(host string) (net.IP, error) {
 // refreshed if the last lookup time exceeds the configured TTL (not the TTL
 // returned in the DNS record).
 func (r *cacheResolver) LookupIP(host string) (net.IP, error) {
+	return r.LookupIPFamily(host, types.DialFamilyAny)
+}
+
+// LookupIPFamily is like LookupIP, but it returns only an IP of the address
+// family. The cache is shared by all the families.
+func (r *cacheResolver) LookupIPFamily(host, family string) (net.IP, error) {
 	r.cm.Lock()
 
 	var ips []net.IP
@@ -94,14 +114,13 @@ func (r *cacheResolver) LookupIP(host string) (net.IP, error) {
 		if err != nil {
 			return nil, err
 		}
-		ips = r.applyPolicy(ips)
 		r.cm.Lock()
 		r.cache[host] = cacheRecord{ips: ips, lastLookup: time.Now()}
 	}
 
 	r.cm.Unlock()
 
-	return r.selectOne(host, ips), nil
+	return r.selectOne(host, r.applyPolicy(filterFamily(ips, family))), nil
 }
 
 func (r *resolver) selectOne(host string, ips []net.IP) net.IP {
@@ -168,3 +187,16 @@ func groupByVersion(ips []net.IP) (ip4 []net.IP, ip6 []net.IP) {
 
 	return ip4, ip6
 }
+
+func filterFamily(ips []net.IP, family string) []net.IP {
+	if family == "" || family == types.DialFamilyAny {
+		return ips
+	}
+	filtered := make([]net.IP, 0, len(ips))
+	for _, ip := range ips {
+		if types.InDialFamily(ip, family) {
+			filtered = append(filtered, ip)
+		}
+	}
+	return filtered
+}
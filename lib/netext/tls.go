@@ -2,7 +2,12 @@
 package netext
 
 import (
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"math"
+	"time"
 
 	"golang.org/x/crypto/ocsp"
 
@@ -35,6 +40,36 @@ const (
 type TLSInfo struct {
 	Version     string
 	CipherSuite string
+	Resumed     bool
+
+	// PeerCertificates is the certificate chain presented by the peer,
+	// starting with its own certificate.
+	PeerCertificates []Certificate
+}
+
+// Certificate keeps the details of an X.509 certificate
+type Certificate struct {
+	Subject      string   `json:"subject"`
+	Issuer       string   `json:"issuer"`
+	SerialNumber string   `json:"serial_number"`
+	DNSNames     []string `json:"dns_names"`
+	NotBefore    int64    `json:"not_before"`
+	NotAfter     int64    `json:"not_after"`
+	DaysToExpiry float64  `json:"days_to_expiry"`
+	Fingerprint  string   `json:"fingerprint"`
+}
+
+// DaysToExpiry returns the days until the first of the certificates of the
+// chain expires, and false if there are no certificates.
+func (i TLSInfo) DaysToExpiry() (float64, bool) {
+	if len(i.PeerCertificates) == 0 {
+		return 0, false
+	}
+	days := math.Inf(1)
+	for _, cert := range i.PeerCertificates {
+		days = min(days, cert.DaysToExpiry)
+	}
+	return days, true
 }
 
 // OCSP keeps Online Certificate Status Protocol (OCSP) details
@@ -45,6 +80,9 @@ type OCSP struct {
 	RevokedAt        int64  `json:"revoked_at"`
 	RevocationReason string `json:"revocation_reason"`
 	Status           string `json:"status"`
+	// Stapled is true if the server stapled an OCSP response that could be
+	// parsed.
+	Stapled bool `json:"stapled"`
 }
 
 // ParseTLSConnState parses tls.ConnectionState and returns TLS and OCSP details
@@ -62,9 +100,16 @@ func ParseTLSConnState(tlsState *tls.ConnectionState) (TLSInfo, OCSP) {
 	}
 
 	tlsInfo.CipherSuite = lib.SupportedTLSCipherSuitesToString[tlsState.CipherSuite]
+	tlsInfo.Resumed = tlsState.DidResume
+	now := time.Now()
+	tlsInfo.PeerCertificates = make([]Certificate, 0, len(tlsState.PeerCertificates))
+	for _, cert := range tlsState.PeerCertificates {
+		tlsInfo.PeerCertificates = append(tlsInfo.PeerCertificates, newCertificate(cert, now))
+	}
 	ocspStapledRes := OCSP{Status: OCSP_STATUS_UNKNOWN}
 
 	if ocspRes, err := ocsp.ParseResponse(tlsState.OCSPResponse, nil); err == nil {
+		ocspStapledRes.Stapled = true
 		switch ocspRes.Status {
 		case ocsp.Good:
 			ocspStapledRes.Status = OCSP_STATUS_GOOD
@@ -105,3 +150,17 @@ func ParseTLSConnState(tlsState *tls.ConnectionState) (TLSInfo, OCSP) {
 
 	return tlsInfo, ocspStapledRes
 }
+
+func newCertificate(cert *x509.Certificate, now time.Time) Certificate {
+	fingerprint := sha256.Sum256(cert.Raw)
+	return Certificate{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.Text(16),
+		DNSNames:     append([]string{}, cert.DNSNames...),
+		NotBefore:    cert.NotBefore.Unix(),
+		NotAfter:     cert.NotAfter.Unix(),
+		DaysToExpiry: cert.NotAfter.Sub(now).Hours() / 24,
+		Fingerprint:  hex.EncodeToString(fingerprint[:]),
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"strings"
 
 	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
@@ -282,11 +283,19 @@ type Options struct {
 	// Throw warnings (eg. failed HTTP requests) as errors instead of simply logging them.
 	Throw null.Bool `json:"throw" envconfig:"K6_THROW"`
 
+	// Per method and URL pattern rules for which HTTP response statuses are expected;
+	// they take precedence over the response callback for the requests they match,
+	// and they still apply if the callback is removed with setResponseCallback(null).
+	ExpectedResponses ExpectedResponseRules `json:"expectedResponses" ignored:"true"`
+
 	// Define thresholds; these take the form of 'metric=["snippet1", "snippet2"]'.
 	// To create a threshold on a derived metric based on tag queries ("submetrics"), create a
 	// metric on a nonexistent metric named 'real_metric{tagA:valueA,tagB:valueB}'.
 	Thresholds map[string]metrics.Thresholds `json:"thresholds" envconfig:"K6_THRESHOLDS"`
 
+	// The failed iterations and checks that are tolerated before the test run is marked as failed.
+	ErrorBudget *ErrorBudget `json:"errorBudget" ignored:"true"`
+
 	// Blacklist IP ranges that tests may not contact. Mainly useful in hosted setups.
 	BlacklistIPs []*IPNet `json:"blacklistIPs" envconfig:"K6_BLACKLIST_IPS"`
 
@@ -296,6 +305,10 @@ type Options struct {
 	// Hosts overrides dns entries for given hosts
 	Hosts types.NullHosts `json:"hosts" envconfig:"K6_HOSTS"`
 
+	// DialFamily restricts the connections to IPv4 or IPv6 addresses, the DNS
+	// and the hosts entries are resolved to an address of that family.
+	DialFamily null.String `json:"dialFamily" envconfig:"K6_DIAL_FAMILY"`
+
 	// Disable keep-alive connections
 	NoConnectionReuse null.Bool `json:"noConnectionReuse" envconfig:"K6_NO_CONNECTION_REUSE"`
 
@@ -304,10 +317,37 @@ type Options struct {
 	// errors about running out of file handles or sockets, or being unable to bind addresses.
 	NoVUConnectionReuse null.Bool `json:"noVUConnectionReuse" envconfig:"K6_NO_VU_CONNECTION_REUSE"`
 
+	// MaxConcurrentRequests limits how many HTTP requests all the VUs can make concurrently. When
+	// the scenarios contend for them, they are shared in proportion to the scenarios' weights.
+	// Only the k6/http requests are limited, not the WebSocket or gRPC connections.
+	MaxConcurrentRequests null.Int `json:"maxConcurrentRequests" envconfig:"K6_MAX_CONCURRENT_REQUESTS"`
+
 	// MinIterationDuration can be used to force VUs to pause between iterations if a specific
 	// iteration is shorter than the specified value.
 	MinIterationDuration types.NullDuration `json:"minIterationDuration" envconfig:"K6_MIN_ITERATION_DURATION"`
 
+	// IterationTimeout interrupts any iteration that runs for longer than the specified value,
+	// after which the VU continues with its next iteration. It can be overridden per scenario.
+	IterationTimeout types.NullDuration `json:"iterationTimeout" envconfig:"K6_ITERATION_TIMEOUT"`
+
+	// VUMemoryLimit is the maximum estimated size, in bytes, of the JS values that a VU
+	// can retain between iterations in its globals and in the exports of its main module.
+	// It isn't a limit of the heap of the VU: the values that are only held by module-level
+	// variables, closures or imported modules aren't counted, so leaks in them aren't
+	// detected. VUMemoryLimitAction is what happens to the VUs that exceed it: "warn" only
+	// logs a warning, "restart" also reinitializes the VU.
+	VUMemoryLimit       null.Int    `json:"vuMemoryLimit" envconfig:"K6_VU_MEMORY_LIMIT"`
+	VUMemoryLimitAction null.String `json:"vuMemoryLimitAction" envconfig:"K6_VU_MEMORY_LIMIT_ACTION"`
+
+	// IterationBreakdown emits metrics that split the duration of every iteration into the
+	// time spent running the script, sleeping and waiting for the network.
+	IterationBreakdown null.Bool `json:"iterationBreakdown" envconfig:"K6_ITERATION_BREAKDOWN"`
+
+	// ConnectionMetrics emits metrics of the active and idle connections per host, tagged
+	// with the remote_host system tag, the ephemeral ports in use and the opened and
+	// closed connections every second.
+	ConnectionMetrics null.Bool `json:"connectionMetrics" envconfig:"K6_CONNECTION_METRICS"`
+
 	// Cloud is the configuration for the k6 Cloud, formerly known as ext.loadimpact.
 	Cloud json.RawMessage `json:"cloud,omitempty"`
 
@@ -321,6 +361,21 @@ type Options struct {
 	// Summary time unit for summary metrics (response times) in CLI output
 	SummaryTimeUnit null.String `json:"summaryTimeUnit" envconfig:"K6_SUMMARY_TIME_UNIT"`
 
+	// Tags (e.g. "scenario" or "group") by which the key metrics in the end-of-test summary
+	// are automatically broken down, without having to define thresholds for their sub-metrics.
+	SummaryBreakdown []string `json:"summaryBreakdown" envconfig:"K6_SUMMARY_BREAKDOWN"`
+
+	// Metrics (e.g. "http_req_duration" or "http_reqs{status:500}") whose values are also
+	// shown in the end-of-test summary as a series of time buckets, to show the shape of the run.
+	SummaryTimeSeries []string `json:"summaryTimeSeries" envconfig:"K6_SUMMARY_TIME_SERIES"`
+
+	// The length of the time buckets of SummaryTimeSeries, 10s if unset.
+	SummaryTimeSeriesInterval types.NullDuration `json:"summaryTimeSeriesInterval" envconfig:"K6_SUMMARY_TIME_SERIES_INTERVAL"` //nolint:lll
+
+	// For how long the exact values of trend metrics are kept for the thresholds and
+	// the end-of-test summary, older values are downsampled. Everything is kept if unset.
+	TrendExactWindow types.NullDuration `json:"trendExactWindow" envconfig:"K6_TREND_EXACT_WINDOW"`
+
 	// Which system tags to include with metrics ("method", "vu" etc.)
 	// Use pointer for identifying whether user provide any tag or not.
 	SystemTags *metrics.SystemTagSet `json:"systemTags" envconfig:"K6_SYSTEM_TAGS"`
@@ -328,15 +383,37 @@ type Options struct {
 	// Tags are key-value pairs to be applied to all samples for the run.
 	RunTags map[string]string `json:"tags" envconfig:"K6_TAGS"`
 
+	// RunMetadata are key-value pairs that describe the run as a whole, e.g. the git SHA or
+	// the environment under test. Unlike the tags, they aren't applied to the samples, they
+	// are given to the outputs and to handleSummary().
+	RunMetadata map[string]string `json:"runMetadata" envconfig:"K6_RUN_METADATA"`
+
 	// Buffer size of the channel for metric samples; 0 means unbuffered
 	MetricSamplesBufferSize null.Int `json:"metricSamplesBufferSize" envconfig:"K6_METRIC_SAMPLES_BUFFER_SIZE"`
 
+	// How many metric samples every output can have buffered; 0 means unlimited
+	MetricSamplesBufferLimit null.Int `json:"metricSamplesBufferLimit" envconfig:"K6_METRIC_SAMPLES_BUFFER_LIMIT"`
+
+	// What to do when an output has reached the buffer limit: block, drop or aggregate
+	MetricSamplesBufferPolicy null.String `json:"metricSamplesBufferPolicy" envconfig:"K6_METRIC_SAMPLES_BUFFER_POLICY"`
+
 	// Do not reset cookies after a VU iteration
 	NoCookiesReset null.Bool `json:"noCookiesReset" envconfig:"K6_NO_COOKIES_RESET"`
 
 	// Discard Http Responses Body
 	DiscardResponseBodies null.Bool `json:"discardResponseBodies" envconfig:"K6_DISCARD_RESPONSE_BODIES"`
 
+	// HTTPRecord is a cassette file to which the HTTP responses are recorded, HTTPReplay is
+	// a cassette file from which they are replayed instead of sending the requests. The
+	// paths are relative to the script.
+	HTTPRecord null.String `json:"httpRecord" envconfig:"K6_HTTP_RECORD"`
+	HTTPReplay null.String `json:"httpReplay" envconfig:"K6_HTTP_REPLAY"`
+
+	// RandomSeed seeds Math.random(), the k6/random streams and the picks of the scenarios'
+	// mixes, so that the random values of a run can be replayed exactly by running it again
+	// with the same seed.
+	RandomSeed null.Int `json:"randomSeed" envconfig:"K6_RANDOM_SEED"`
+
 	// Redirect console logging to a file
 	ConsoleOutput null.String `json:"-" envconfig:"K6_CONSOLE_OUTPUT"`
 
@@ -448,9 +525,15 @@ func (o Options) Apply(opts Options) Options {
 	if opts.Throw.Valid {
 		o.Throw = opts.Throw
 	}
+	if opts.ExpectedResponses != nil {
+		o.ExpectedResponses = opts.ExpectedResponses
+	}
 	if opts.Thresholds != nil {
 		o.Thresholds = opts.Thresholds
 	}
+	if opts.ErrorBudget != nil {
+		o.ErrorBudget = opts.ErrorBudget
+	}
 	if opts.BlacklistIPs != nil {
 		o.BlacklistIPs = opts.BlacklistIPs
 	}
@@ -460,15 +543,36 @@ func (o Options) Apply(opts Options) Options {
 	if opts.Hosts.Valid {
 		o.Hosts = opts.Hosts
 	}
+	if opts.DialFamily.Valid {
+		o.DialFamily = opts.DialFamily
+	}
 	if opts.NoConnectionReuse.Valid {
 		o.NoConnectionReuse = opts.NoConnectionReuse
 	}
 	if opts.NoVUConnectionReuse.Valid {
 		o.NoVUConnectionReuse = opts.NoVUConnectionReuse
 	}
+	if opts.MaxConcurrentRequests.Valid {
+		o.MaxConcurrentRequests = opts.MaxConcurrentRequests
+	}
 	if opts.MinIterationDuration.Valid {
 		o.MinIterationDuration = opts.MinIterationDuration
 	}
+	if opts.IterationTimeout.Valid {
+		o.IterationTimeout = opts.IterationTimeout
+	}
+	if opts.VUMemoryLimit.Valid {
+		o.VUMemoryLimit = opts.VUMemoryLimit
+	}
+	if opts.VUMemoryLimitAction.Valid {
+		o.VUMemoryLimitAction = opts.VUMemoryLimitAction
+	}
+	if opts.IterationBreakdown.Valid {
+		o.IterationBreakdown = opts.IterationBreakdown
+	}
+	if opts.ConnectionMetrics.Valid {
+		o.ConnectionMetrics = opts.ConnectionMetrics
+	}
 	if opts.NoCookiesReset.Valid {
 		o.NoCookiesReset = opts.NoCookiesReset
 	}
@@ -484,18 +588,48 @@ func (o Options) Apply(opts Options) Options {
 	if opts.SummaryTimeUnit.Valid {
 		o.SummaryTimeUnit = opts.SummaryTimeUnit
 	}
+	if opts.SummaryBreakdown != nil {
+		o.SummaryBreakdown = opts.SummaryBreakdown
+	}
+	if opts.SummaryTimeSeries != nil {
+		o.SummaryTimeSeries = opts.SummaryTimeSeries
+	}
+	if opts.SummaryTimeSeriesInterval.Valid {
+		o.SummaryTimeSeriesInterval = opts.SummaryTimeSeriesInterval
+	}
+	if opts.TrendExactWindow.Valid {
+		o.TrendExactWindow = opts.TrendExactWindow
+	}
 	if opts.SystemTags != nil {
 		o.SystemTags = opts.SystemTags
 	}
 	if len(opts.RunTags) > 0 {
 		o.RunTags = opts.RunTags
 	}
+	if len(opts.RunMetadata) > 0 {
+		o.RunMetadata = opts.RunMetadata
+	}
 	if opts.MetricSamplesBufferSize.Valid {
 		o.MetricSamplesBufferSize = opts.MetricSamplesBufferSize
 	}
+	if opts.MetricSamplesBufferLimit.Valid {
+		o.MetricSamplesBufferLimit = opts.MetricSamplesBufferLimit
+	}
+	if opts.MetricSamplesBufferPolicy.Valid {
+		o.MetricSamplesBufferPolicy = opts.MetricSamplesBufferPolicy
+	}
 	if opts.DiscardResponseBodies.Valid {
 		o.DiscardResponseBodies = opts.DiscardResponseBodies
 	}
+	if opts.HTTPRecord.Valid {
+		o.HTTPRecord = opts.HTTPRecord
+	}
+	if opts.HTTPReplay.Valid {
+		o.HTTPReplay = opts.HTTPReplay
+	}
+	if opts.RandomSeed.Valid {
+		o.RandomSeed = opts.RandomSeed
+	}
 	if opts.ConsoleOutput.Valid {
 		o.ConsoleOutput = opts.ConsoleOutput
 	}
@@ -535,11 +669,75 @@ func (o Options) Validate() []error {
 		}
 	}
 	validationErrors = append(validationErrors, o.Scenarios.Validate()...)
+	validationErrors = append(validationErrors, o.ExpectedResponses.Validate()...)
+	if o.ErrorBudget != nil {
+		if err := o.ErrorBudget.Validate(); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
 
 	// Duration
 	if o.SetupTimeout.Valid && o.SetupTimeout.Duration <= 0 {
 		validationErrors = append(validationErrors, errors.New("setupTimeout must be positive"))
 	}
+	for _, tag := range o.SummaryBreakdown {
+		if strings.TrimSpace(tag) == "" {
+			validationErrors = append(validationErrors, errors.New("summaryBreakdown can't contain empty tag names"))
+		}
+	}
+	for _, name := range o.SummaryTimeSeries {
+		if strings.TrimSpace(name) == "" {
+			validationErrors = append(validationErrors, errors.New("summaryTimeSeries can't contain empty metric names"))
+		}
+	}
+	if o.SummaryTimeSeriesInterval.Valid && o.SummaryTimeSeriesInterval.Duration <= 0 {
+		validationErrors = append(validationErrors, errors.New("summaryTimeSeriesInterval must be positive"))
+	}
+	if o.TrendExactWindow.Valid && o.TrendExactWindow.Duration < 0 {
+		validationErrors = append(validationErrors, errors.New("trendExactWindow can't be negative"))
+	}
+	if o.IterationTimeout.Valid && o.IterationTimeout.Duration < 0 {
+		validationErrors = append(validationErrors, errors.New("iterationTimeout can't be negative"))
+	}
+	for key := range o.RunMetadata {
+		if strings.TrimSpace(key) == "" {
+			validationErrors = append(validationErrors, errors.New("runMetadata can't contain empty keys"))
+		}
+	}
+	if o.MaxConcurrentRequests.Valid && o.MaxConcurrentRequests.Int64 < 0 {
+		validationErrors = append(validationErrors, errors.New("maxConcurrentRequests can't be negative"))
+	}
+	if o.VUMemoryLimit.Valid && o.VUMemoryLimit.Int64 < 0 {
+		validationErrors = append(validationErrors, errors.New("vuMemoryLimit can't be negative"))
+	}
+	if o.VUMemoryLimitAction.Valid {
+		switch o.VUMemoryLimitAction.String {
+		case "warn", "restart":
+		default:
+			validationErrors = append(validationErrors, fmt.Errorf(
+				"vuMemoryLimitAction should be either warn or restart but was %q", o.VUMemoryLimitAction.String))
+		}
+	}
+	if o.DialFamily.Valid {
+		if err := types.ValidateDialFamily(o.DialFamily.String); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
+	if o.MetricSamplesBufferLimit.Valid && o.MetricSamplesBufferLimit.Int64 < 0 {
+		validationErrors = append(validationErrors, errors.New("metricSamplesBufferLimit can't be negative"))
+	}
+	if o.MetricSamplesBufferPolicy.Valid {
+		switch o.MetricSamplesBufferPolicy.String {
+		case "block", "drop", "aggregate":
+		default:
+			validationErrors = append(validationErrors, fmt.Errorf(
+				"metricSamplesBufferPolicy should be one of block, drop or aggregate but was %q",
+				o.MetricSamplesBufferPolicy.String))
+		}
+	}
+	if o.HTTPRecord.String != "" && o.HTTPReplay.String != "" {
+		validationErrors = append(validationErrors, errors.New("httpRecord and httpReplay can't be used together"))
+	}
 	return validationErrors
 }
 
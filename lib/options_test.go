@@ -477,6 +477,60 @@ func TestOptions(t *testing.T) {
 		assert.True(t, opts.Throw.Valid)
 		assert.Equal(t, true, opts.Throw.Bool)
 	})
+	t.Run("ExpectedResponses", func(t *testing.T) {
+		t.Parallel()
+		jsonStr := `{"expectedResponses":[` +
+			`{"method":"DELETE","url":"/cache/.*","statuses":[404,{"min":200,"max":299}]},` +
+			`{"statuses":[{"min":200,"max":399}]}]}`
+		var opts Options
+		require.NoError(t, json.Unmarshal([]byte(jsonStr), &opts))
+		opts = Options{}.Apply(opts)
+		require.Len(t, opts.ExpectedResponses, 2)
+		assert.Empty(t, opts.Validate())
+
+		expected, matched := opts.ExpectedResponses.Classify("DELETE", "https://example.com/cache/123", 404)
+		assert.True(t, matched)
+		assert.True(t, expected)
+		expected, matched = opts.ExpectedResponses.Classify("DELETE", "https://example.com/cache/123", 500)
+		assert.True(t, matched)
+		assert.False(t, expected)
+		expected, matched = opts.ExpectedResponses.Classify("GET", "https://example.com/cache/123", 404)
+		assert.True(t, matched)
+		assert.False(t, expected)
+		_, matched = Options{}.ExpectedResponses.Classify("GET", "https://example.com/", 200)
+		assert.False(t, matched)
+
+		optsData, err := json.Marshal(opts)
+		require.NoError(t, err)
+		assert.Contains(t, string(optsData), jsonStr[1:len(jsonStr)-1])
+
+		for _, invalid := range []string{
+			`{"expectedResponses":[{"url":"/cache/.*"}]}`,
+			`{"expectedResponses":[{"url":"(","statuses":[200]}]}`,
+			`{"expectedResponses":[{"statuses":[{"min":200}]}]}`,
+			`{"expectedResponses":[{"statuses":[{"min":300,"max":200}]}]}`,
+			`{"expectedResponses":[{"statuses":[200],"foo":"bar"}]}`,
+		} {
+			assert.Error(t, json.Unmarshal([]byte(invalid), &Options{}), invalid)
+		}
+
+		rules := ExpectedResponseRules{{ExpectedResponseFields: ExpectedResponseFields{
+			URL: "/cache/.*", Statuses: []ExpectedStatus{{Min: 404, Max: 404}},
+		}}}
+		assert.Empty(t, Options{ExpectedResponses: rules}.Validate())
+		assert.Nil(t, rules[0].urlRegex)
+		compiled, err := rules.Compile()
+		require.NoError(t, err)
+		assert.Nil(t, rules[0].urlRegex)
+		require.NotNil(t, compiled[0].urlRegex)
+		assert.True(t, compiled[0].Matches("GET", "https://example.com/cache/123"))
+		assert.False(t, compiled[0].Matches("GET", "https://example.com/other/123"))
+
+		_, err = ExpectedResponseRules{{ExpectedResponseFields: ExpectedResponseFields{
+			URL: "(", Statuses: []ExpectedStatus{{Min: 404, Max: 404}},
+		}}}.Compile()
+		assert.ErrorContains(t, err, "expectedResponses[0]: invalid url pattern")
+	})
 
 	t.Run("Thresholds", func(t *testing.T) {
 		t.Parallel()
@@ -544,6 +598,32 @@ func TestOptions(t *testing.T) {
 		opts := Options{}.Apply(Options{SummaryTrendStats: stats})
 		assert.Equal(t, stats, opts.SummaryTrendStats)
 	})
+	t.Run("SummaryTimeSeries", func(t *testing.T) {
+		t.Parallel()
+		names := []string{"http_req_duration", "http_reqs{status:500}"}
+		opts := Options{}.Apply(Options{
+			SummaryTimeSeries:         names,
+			SummaryTimeSeriesInterval: types.NullDurationFrom(time.Minute),
+		})
+		assert.Equal(t, names, opts.SummaryTimeSeries)
+		assert.Equal(t, types.NullDurationFrom(time.Minute), opts.SummaryTimeSeriesInterval)
+		assert.Empty(t, opts.Validate())
+
+		opts = opts.Apply(Options{
+			SummaryTimeSeries:         []string{" "},
+			SummaryTimeSeriesInterval: types.NullDurationFrom(0),
+		})
+		assert.Len(t, opts.Validate(), 2)
+	})
+	t.Run("DialFamily", func(t *testing.T) {
+		t.Parallel()
+		opts := Options{}.Apply(Options{DialFamily: null.StringFrom("ipv6")})
+		assert.Equal(t, null.StringFrom("ipv6"), opts.DialFamily)
+		assert.Empty(t, opts.Validate())
+
+		opts = opts.Apply(Options{DialFamily: null.StringFrom("ipv5")})
+		assert.Len(t, opts.Validate(), 1)
+	})
 	t.Run("RunTags", func(t *testing.T) {
 		t.Parallel()
 		tags := map[string]string{"myTag": "hello"}
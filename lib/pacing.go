@@ -0,0 +1,126 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib/types"
+)
+
+// The statistical models of the think time between iterations.
+const (
+	PacingConstant    = "constant"
+	PacingUniform     = "uniform"
+	PacingNormal      = "normal"
+	PacingLognormal   = "lognormal"
+	PacingExponential = "exponential"
+)
+
+// Pacing declares the think time that the VUs of a scenario wait for between
+// their iterations, drawn from a statistical model:
+//   - constant: always the mean.
+//   - uniform: uniformly distributed between min and max.
+//   - normal: normally distributed around the mean, with a standard deviation
+//     of sigma times the mean.
+//   - lognormal: log-normally distributed with the mean, where sigma is the
+//     standard deviation of the underlying normal distribution.
+//   - exponential: exponentially distributed with the mean.
+//
+// The min and max, if set, bound the think times of all the models. With a
+// seed, every VU waits for the same sequence of think times in every run.
+type Pacing struct {
+	Model string             `json:"model"`
+	Mean  types.NullDuration `json:"mean"`
+	Sigma null.Float         `json:"sigma"`
+	Min   types.NullDuration `json:"min"`
+	Max   types.NullDuration `json:"max"`
+	Seed  null.Int           `json:"seed"`
+}
+
+// Validate checks that the pacing is complete for its model.
+func (p Pacing) Validate() error {
+	switch p.Model {
+	case PacingConstant, PacingExponential:
+		if !p.Mean.Valid || p.Mean.Duration <= 0 {
+			return fmt.Errorf("the %s pacing requires a positive mean", p.Model)
+		}
+	case PacingNormal, PacingLognormal:
+		if !p.Mean.Valid || p.Mean.Duration <= 0 {
+			return fmt.Errorf("the %s pacing requires a positive mean", p.Model)
+		}
+		if !p.Sigma.Valid || p.Sigma.Float64 < 0 {
+			return fmt.Errorf("the %s pacing requires a sigma that isn't negative", p.Model)
+		}
+	case PacingUniform:
+		if !p.Min.Valid || !p.Max.Valid {
+			return errors.New("the uniform pacing requires a min and a max")
+		}
+	case "":
+		return errors.New("the pacing requires a model")
+	default:
+		return fmt.Errorf("unknown pacing model %q, it must be one of %s, %s, %s, %s or %s", p.Model,
+			PacingConstant, PacingUniform, PacingNormal, PacingLognormal, PacingExponential)
+	}
+	if p.Min.Duration < 0 || p.Max.Duration < 0 {
+		return errors.New("the pacing's min and max can't be negative")
+	}
+	if p.Min.Valid && p.Max.Valid && p.Min.Duration > p.Max.Duration {
+		return errors.New("the pacing's min can't be greater than its max")
+	}
+	return nil
+}
+
+// Pacer draws the think times of a VU from a Pacing. It isn't safe for
+// concurrent use.
+type Pacer struct {
+	pacing Pacing
+	rand   *rand.Rand
+}
+
+// NewPacer returns a Pacer for the VU. If the pacing has a seed, the think
+// times are reproducible and different for every VU.
+func (p Pacing) NewPacer(vuID uint64) *Pacer {
+	var src rand.Source
+	if p.Seed.Valid {
+		src = rand.NewPCG(uint64(p.Seed.Int64), vuID) //nolint:gosec
+	} else {
+		src = rand.NewPCG(rand.Uint64(), rand.Uint64()) //nolint:gosec
+	}
+	return &Pacer{pacing: p, rand: rand.New(src)} //nolint:gosec
+}
+
+// Next returns the next think time.
+func (p *Pacer) Next() time.Duration {
+	mean := float64(p.pacing.Mean.Duration)
+	sigma := p.pacing.Sigma.Float64
+
+	var d float64
+	switch p.pacing.Model {
+	case PacingConstant:
+		d = mean
+	case PacingUniform:
+		low, high := float64(p.pacing.Min.Duration), float64(p.pacing.Max.Duration)
+		d = low + p.rand.Float64()*(high-low)
+	case PacingNormal:
+		d = mean + p.rand.NormFloat64()*sigma*mean
+	case PacingLognormal:
+		// mu is chosen so that the mean of the distribution is the mean
+		mu := math.Log(mean) - sigma*sigma/2
+		d = math.Exp(mu + p.rand.NormFloat64()*sigma)
+	case PacingExponential:
+		d = p.rand.ExpFloat64() * mean
+	}
+
+	if p.pacing.Min.Valid {
+		d = max(d, float64(p.pacing.Min.Duration))
+	}
+	if p.pacing.Max.Valid {
+		d = min(d, float64(p.pacing.Max.Duration))
+	}
+	return time.Duration(max(d, 0))
+}
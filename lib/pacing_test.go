@@ -0,0 +1,91 @@
+package lib
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacingValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`{"model":"constant","mean":"1s"}`:               "",
+		`{"model":"uniform","min":"1s","max":"2s"}`:      "",
+		`{"model":"normal","mean":"5s","sigma":0.2}`:     "",
+		`{"model":"lognormal","mean":"5s","sigma":0.5}`:  "",
+		`{"model":"exponential","mean":"5s","max":"1m"}`: "",
+		`{"mean":"1s"}`:                                          "the pacing requires a model",
+		`{"model":"pareto","mean":"1s"}`:                         `unknown pacing model "pareto"`,
+		`{"model":"constant"}`:                                   "the constant pacing requires a positive mean",
+		`{"model":"lognormal","mean":"5s"}`:                      "the lognormal pacing requires a sigma",
+		`{"model":"uniform","min":"1s"}`:                         "the uniform pacing requires a min and a max",
+		`{"model":"uniform","min":"2s","max":"1s"}`:              "the pacing's min can't be greater than its max",
+		`{"model":"exponential","mean":"5s","min":"-1s"}`:        "the pacing's min and max can't be negative",
+		`{"model":"normal","mean":"5s","sigma":-1}`:              "the normal pacing requires a sigma that isn't negative",
+		`{"model":"constant","mean":"1s","min":"2s","max":"1s"}`: "the pacing's min can't be greater than its max",
+	}
+	for data, want := range tests {
+		t.Run(data, func(t *testing.T) {
+			t.Parallel()
+
+			var p Pacing
+			require.NoError(t, json.Unmarshal([]byte(data), &p))
+			err := p.Validate()
+			if want == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestPacer(t *testing.T) {
+	t.Parallel()
+
+	sample := func(data string, vuID uint64, n int) []time.Duration {
+		var p Pacing
+		require.NoError(t, json.Unmarshal([]byte(data), &p))
+		require.NoError(t, p.Validate())
+		pacer := p.NewPacer(vuID)
+		durations := make([]time.Duration, n)
+		for i := range durations {
+			durations[i] = pacer.Next()
+		}
+		return durations
+	}
+	mean := func(durations []time.Duration) time.Duration {
+		var sum time.Duration
+		for _, d := range durations {
+			sum += d
+		}
+		return sum / time.Duration(len(durations))
+	}
+
+	assert.Equal(t, []time.Duration{time.Second, time.Second}, sample(`{"model":"constant","mean":"1s"}`, 1, 2))
+
+	for _, d := range sample(`{"model":"uniform","min":"1s","max":"2s"}`, 1, 1000) {
+		assert.True(t, d >= time.Second && d <= 2*time.Second, d)
+	}
+	for _, d := range sample(`{"model":"normal","mean":"5s","sigma":1,"min":"1s","max":"9s"}`, 1, 1000) {
+		assert.True(t, d >= time.Second && d <= 9*time.Second, d)
+	}
+
+	for _, model := range []string{
+		`{"model":"normal","mean":"5s","sigma":0.2}`,
+		`{"model":"lognormal","mean":"5s","sigma":0.5}`,
+		`{"model":"exponential","mean":"5s"}`,
+	} {
+		assert.InDelta(t, 5*time.Second, mean(sample(model, 1, 20000)), float64(200*time.Millisecond), model)
+	}
+
+	seeded := `{"model":"lognormal","mean":"5s","sigma":0.5,"seed":42}`
+	assert.Equal(t, sample(seeded, 1, 10), sample(seeded, 1, 10))
+	assert.NotEqual(t, sample(seeded, 1, 10), sample(seeded, 2, 10))
+	unseeded := `{"model":"lognormal","mean":"5s","sigma":0.5}`
+	assert.NotEqual(t, sample(unseeded, 1, 10), sample(unseeded, 1, 10))
+}
@@ -0,0 +1,57 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/lib/types"
+)
+
+// RequestDefaults are the defaults of the HTTP requests made by the VUs of a
+// scenario, the params of each request take precedence over them.
+type RequestDefaults struct {
+	// BaseURL is prepended to the relative URLs of the requests, like /users,
+	// so the same code can target a different host in each scenario.
+	BaseURL null.String        `json:"baseURL"`
+	Headers map[string]string  `json:"headers"`
+	Timeout types.NullDuration `json:"timeout"`
+}
+
+// Validate checks that the base URL is an absolute HTTP URL and that the
+// timeout is positive.
+func (d RequestDefaults) Validate() error {
+	if d.BaseURL.Valid {
+		u, err := url.Parse(d.BaseURL.String)
+		if err != nil {
+			return fmt.Errorf("invalid requestDefaults baseURL: %w", err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("the requestDefaults baseURL %q has to be an absolute http or https URL", d.BaseURL.String)
+		}
+	}
+	if d.Timeout.Valid && d.Timeout.Duration <= 0 {
+		return errors.New("the requestDefaults timeout must be positive")
+	}
+	return nil
+}
+
+// ResolveURL prepends the base URL to the relative URL, the path of the base
+// URL is kept, so the base URL https://example.com/api and the URL /users
+// result in https://example.com/api/users. Absolute URLs are returned as they
+// are.
+func (d RequestDefaults) ResolveURL(rawURL string) string {
+	if !d.BaseURL.Valid {
+		return rawURL
+	}
+	if u, err := url.Parse(rawURL); err != nil || u.Scheme != "" || u.Host != "" {
+		return rawURL
+	}
+	if rawURL == "" || strings.HasPrefix(rawURL, "?") || strings.HasPrefix(rawURL, "#") {
+		return d.BaseURL.String + rawURL
+	}
+	return strings.TrimSuffix(d.BaseURL.String, "/") + "/" + strings.TrimPrefix(rawURL, "/")
+}
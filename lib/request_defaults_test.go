@@ -0,0 +1,56 @@
+package lib
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+)
+
+func TestRequestDefaultsValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`{}`:                                    "",
+		`{"baseURL":"https://example.com/api"}`: "",
+		`{"headers":{"X-Tenant":"a"},"timeout":"5s"}`: "",
+		`{"baseURL":"example.com"}`:                   `the requestDefaults baseURL "example.com" has to be an absolute`,
+		`{"baseURL":"ftp://example.com"}`:             `the requestDefaults baseURL "ftp://example.com" has to be an absolute`,
+		`{"baseURL":"http://[::1"}`:                   "invalid requestDefaults baseURL",
+		`{"timeout":"0s"}`:                            "the requestDefaults timeout must be positive",
+	}
+	for data, want := range tests {
+		t.Run(data, func(t *testing.T) {
+			t.Parallel()
+
+			var d RequestDefaults
+			require.NoError(t, json.Unmarshal([]byte(data), &d))
+			err := d.Validate()
+			if want == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestRequestDefaultsResolveURL(t *testing.T) {
+	t.Parallel()
+
+	d := RequestDefaults{BaseURL: null.StringFrom("https://example.com/api/")}
+	tests := map[string]string{
+		"/users":                  "https://example.com/api/users",
+		"users?page=2":            "https://example.com/api/users?page=2",
+		"":                        "https://example.com/api/",
+		"?page=2":                 "https://example.com/api/?page=2",
+		"http://other.test/users": "http://other.test/users",
+		"//other.test/users":      "//other.test/users",
+	}
+	for rawURL, want := range tests {
+		assert.Equal(t, want, d.ResolveURL(rawURL), rawURL)
+	}
+	assert.Equal(t, "/users", RequestDefaults{}.ResolveURL("/users"))
+}
@@ -0,0 +1,127 @@
+package lib
+
+import (
+	"context"
+	"sync"
+)
+
+// RequestLimiter limits how many requests all the scenarios can make
+// concurrently, so a single scenario can't exhaust the sockets and the file
+// descriptors of the load generator. When the scenarios contend for the
+// requests, they are shared between them in proportion to their weights.
+// A scenario can use the requests that the others don't need.
+//
+// Only the requests of the k6/http module are limited. The connections of the
+// other protocols, like WebSockets and gRPC, aren't, since they are long-lived
+// and limiting them at the dialer would make them starve the HTTP requests.
+type RequestLimiter struct {
+	mu        sync.Mutex
+	limit     int64
+	active    int64
+	scenarios map[string]*ScenarioRequestLimiter
+}
+
+// ScenarioRequestLimiter is the share of a scenario of a RequestLimiter.
+type ScenarioRequestLimiter struct {
+	limiter *RequestLimiter
+	name    string
+	weight  int64
+
+	// active and waiting are guarded by the mutex of the limiter.
+	active  int64
+	waiting []chan struct{}
+}
+
+// NewRequestLimiter returns a RequestLimiter for the number of concurrent
+// requests.
+func NewRequestLimiter(limit int64) *RequestLimiter {
+	return &RequestLimiter{limit: limit, scenarios: make(map[string]*ScenarioRequestLimiter)}
+}
+
+// Scenario returns the share of the scenario, with its weight. The weights
+// lower than 1 are treated as 1.
+func (l *RequestLimiter) Scenario(name string, weight int64) *ScenarioRequestLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, ok := l.scenarios[name]
+	if !ok {
+		s = &ScenarioRequestLimiter{limiter: l, name: name}
+		l.scenarios[name] = s
+	}
+	s.weight = max(weight, 1)
+	return s
+}
+
+// Acquire waits until the scenario can make a request, or until the context is
+// done. The returned function must be called when the request is finished.
+func (s *ScenarioRequestLimiter) Acquire(ctx context.Context) (release func(), err error) {
+	l := s.limiter
+	l.mu.Lock()
+	if l.active < l.limit && len(s.waiting) == 0 {
+		l.active++
+		s.active++
+		l.mu.Unlock()
+		return s.release, nil
+	}
+	ch := make(chan struct{})
+	s.waiting = append(s.waiting, ch)
+	l.mu.Unlock()
+
+	select {
+	case <-ch:
+		return s.release, nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, w := range s.waiting {
+		if w == ch {
+			s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+			return nil, ctx.Err()
+		}
+	}
+	// it was granted in the meantime, so it's passed on
+	l.active--
+	s.active--
+	l.grant()
+	return nil, ctx.Err()
+}
+
+func (s *ScenarioRequestLimiter) release() {
+	l := s.limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	s.active--
+	l.grant()
+}
+
+// grant lets the waiting requests proceed while there is room, starting with
+// the ones of the scenarios that use the smallest share of their weight.
+// It must be called with the mutex locked.
+func (l *RequestLimiter) grant() {
+	for l.active < l.limit {
+		var next *ScenarioRequestLimiter
+		for _, s := range l.scenarios {
+			if len(s.waiting) == 0 {
+				continue
+			}
+			// s.active/s.weight < next.active/next.weight, without the divisions
+			if next == nil || s.active*next.weight < next.active*s.weight ||
+				(s.active*next.weight == next.active*s.weight && s.name < next.name) {
+				next = s
+			}
+		}
+		if next == nil {
+			return
+		}
+		ch := next.waiting[0]
+		next.waiting = next.waiting[1:]
+		l.active++
+		next.active++
+		close(ch)
+	}
+}
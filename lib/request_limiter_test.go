@@ -0,0 +1,114 @@
+package lib
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLimiterLimit(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRequestLimiter(3)
+	scenario := limiter.Scenario("default", 1)
+	var active, maxActive atomic.Int64
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := scenario.Acquire(context.Background())
+			assert.NoError(t, err)
+			n := active.Add(1)
+			for {
+				m := maxActive.Load()
+				if n <= m || maxActive.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			active.Add(-1)
+			release()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(3), maxActive.Load())
+	assert.Equal(t, int64(0), limiter.active)
+}
+
+func TestRequestLimiterWeights(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRequestLimiter(3)
+	heavy := limiter.Scenario("heavy", 2)
+	light := limiter.Scenario("light", 1)
+
+	var releases []func()
+	for range 3 {
+		release, err := heavy.Acquire(context.Background())
+		require.NoError(t, err)
+		releases = append(releases, release)
+	}
+
+	var wg sync.WaitGroup
+	granted := make(chan func(), 8)
+	for _, s := range []*ScenarioRequestLimiter{heavy, light} {
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				release, err := s.Acquire(context.Background())
+				assert.NoError(t, err)
+				granted <- release
+			}()
+		}
+	}
+	waiting := func() int {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return len(heavy.waiting) + len(light.waiting)
+	}
+	require.Eventually(t, func() bool { return waiting() == 8 }, time.Second, time.Millisecond)
+
+	// the light scenario gets its share, even though the heavy one used all of them
+	for _, release := range releases {
+		release()
+	}
+	limiter.mu.Lock()
+	assert.Equal(t, int64(2), heavy.active)
+	assert.Equal(t, int64(1), light.active)
+	limiter.mu.Unlock()
+
+	// the rest are granted as the requests finish
+	for range 8 {
+		(<-granted)()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(0), limiter.active)
+}
+
+func TestRequestLimiterCanceled(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRequestLimiter(1)
+	scenario := limiter.Scenario("default", 1)
+	release, err := scenario.Acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = scenario.Acquire(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, scenario.waiting)
+
+	release()
+	release, err = scenario.Acquire(context.Background())
+	require.NoError(t, err)
+	release()
+	assert.Equal(t, int64(0), limiter.active)
+}
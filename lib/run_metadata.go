@@ -0,0 +1,68 @@
+package lib
+
+import (
+	"maps"
+	"sync"
+)
+
+// RunMetadata holds the key-value metadata of the test run, like the git SHA,
+// the environment or the build ID under test, so that the results can be
+// correlated with them. It's set with the runMetadata option and from the
+// script, and it's safe for concurrent use.
+type RunMetadata struct {
+	mu      sync.RWMutex
+	values  map[string]string
+	version uint64
+}
+
+// NewRunMetadata returns a new RunMetadata with the initial values.
+func NewRunMetadata(values map[string]string) *RunMetadata {
+	m := &RunMetadata{values: make(map[string]string, len(values))}
+	maps.Copy(m.values, values)
+	return m
+}
+
+// Set sets the value of the key.
+func (m *RunMetadata) Set(key, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current, ok := m.values[key]; ok && current == value {
+		return
+	}
+	m.values[key] = value
+	m.version++
+}
+
+// Get returns the value of the key, and whether it's set.
+func (m *RunMetadata) Get(key string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Delete deletes the key.
+func (m *RunMetadata) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+	delete(m.values, key)
+	m.version++
+}
+
+// Map returns a copy of the metadata.
+func (m *RunMetadata) Map() map[string]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return maps.Clone(m.values)
+}
+
+// Version returns a number that changes every time the metadata changes, so
+// that the outputs can tell whether they have to emit it again.
+func (m *RunMetadata) Version() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.version
+}
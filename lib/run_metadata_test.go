@@ -0,0 +1,37 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunMetadata(t *testing.T) {
+	t.Parallel()
+
+	initial := map[string]string{"git_sha": "abc123"}
+	m := NewRunMetadata(initial)
+	initial["git_sha"] = "changed"
+	assert.Equal(t, map[string]string{"git_sha": "abc123"}, m.Map())
+
+	version := m.Version()
+	m.Set("git_sha", "abc123")
+	m.Delete("missing")
+	assert.Equal(t, version, m.Version())
+
+	m.Set("environment", "staging")
+	assert.Greater(t, m.Version(), version)
+	value, ok := m.Get("environment")
+	assert.True(t, ok)
+	assert.Equal(t, "staging", value)
+
+	version = m.Version()
+	m.Delete("environment")
+	assert.Greater(t, m.Version(), version)
+	_, ok = m.Get("environment")
+	assert.False(t, ok)
+
+	values := m.Map()
+	values["other"] = "value"
+	assert.Equal(t, map[string]string{"git_sha": "abc123"}, m.Map())
+}
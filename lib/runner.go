@@ -2,12 +2,19 @@ package lib
 
 import (
 	"context"
+	"errors"
 	"io"
+	"time"
 
 	"go.k6.io/k6/internal/lib/summary"
+	"go.k6.io/k6/lib/types"
 	"go.k6.io/k6/metrics"
 )
 
+// ErrIterationTimeout is returned by ActiveVU.RunOnce() when the iteration was
+// interrupted because it exceeded the configured iterationTimeout.
+var ErrIterationTimeout = errors.New("iteration timed out")
+
 // ActiveVU represents an actively running virtual user.
 type ActiveVU interface {
 	// RunOnce runs the configured exported function in the VU once.
@@ -16,6 +23,16 @@ type ActiveVU interface {
 	RunOnce() error
 }
 
+// ActiveVUWithTeardown is implemented by the active VUs that can clean up,
+// e.g. log out their sessions, before they are stopped by a scale-down.
+type ActiveVUWithTeardown interface {
+	ActiveVU
+	// RunVUTeardown runs the vuTeardown() function of the script in the VU,
+	// if it's exported. Like RunOnce(), it's interrupted by canceling the
+	// context given to InitializedVU.Activate()
+	RunVUTeardown() error
+}
+
 // InitializedVU represents a virtual user ready for work. It needs to be
 // activated (i.e. given a context) before it can actually be used. Activation
 // also requires a callback function, which will be called when the supplied
@@ -36,6 +53,15 @@ type VUActivationParams struct {
 	Env, Tags                map[string]string
 	Exec, Scenario           string
 	GetNextIterationCounters func() (uint64, uint64)
+	IterationTimeout         types.NullDuration
+	WarmupIterations         int64
+	WarmupDuration           time.Duration
+	Weight                   int64
+	TLSSessionTickets        bool
+	DialFamily               string
+	Pacing                   *Pacing
+	Mix                      Mix
+	RequestDefaults          *RequestDefaults
 }
 
 // A Runner is a factory for VUs. It should precompute as much as possible upon
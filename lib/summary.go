@@ -3,6 +3,7 @@ package lib
 import (
 	"time"
 
+	"go.k6.io/k6/errext"
 	"go.k6.io/k6/metrics"
 )
 
@@ -13,4 +14,8 @@ type LegacySummary struct {
 	TestRunDuration time.Duration // TODO: use lib.ExecutionState-based interface instead?
 	NoColor         bool          // TODO: drop this when noColor is part of the (runtime) options
 	UIState         UIState
+	RunMetadata     map[string]string
+	// Abort has the details of the test.abort() call that stopped the test
+	// run, if any.
+	Abort *errext.InterruptError
 }
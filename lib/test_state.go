@@ -30,6 +30,12 @@ type TestPreInitState struct {
 
 	// FIXME (@oleiade): is this the way?
 	TestStatus *TestStatus
+
+	// RunMetadata is the metadata of the test run, shared by the VUs and the outputs.
+	RunMetadata *RunMetadata
+
+	// ConnStats tracks the connections of the VUs for the connection metrics.
+	ConnStats *ConnStats
 }
 
 // TestStatus holds the test execution status and is used to support marking a test as failed
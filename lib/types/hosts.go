@@ -31,27 +31,39 @@ func NewNullHosts(source map[string]Host) (NullHosts, error) {
 	}, nil
 }
 
-// MarshalJSON converts NullHosts to valid JSON
+// MarshalJSON converts NullHosts to valid JSON, the hosts with several
+// addresses are converted to arrays of them.
 func (n NullHosts) MarshalJSON() ([]byte, error) {
 	if !n.Valid {
 		return []byte(nullJSON), nil
 	}
 
-	jsonMap := make(map[string]string)
+	jsonMap := make(map[string]any)
 	for k, v := range n.Trie.source {
-		var right string
-		if v.Port != 0 {
-			right = v.String()
-		} else {
-			right = v.IP.String()
+		addresses, ok := n.Trie.addresses[k]
+		if !ok {
+			jsonMap[k] = hostJSON(v)
+			continue
 		}
-		jsonMap[k] = right
+		values := make([]string, len(addresses))
+		for i, address := range addresses {
+			values[i] = hostJSON(address)
+		}
+		jsonMap[k] = values
 	}
 
 	return json.Marshal(jsonMap)
 }
 
-// UnmarshalJSON converts JSON to NullHosts
+func hostJSON(h Host) string {
+	if h.Port != 0 {
+		return h.String()
+	}
+	return h.IP.String()
+}
+
+// UnmarshalJSON converts JSON to NullHosts, a host can be mapped to an
+// address or to an array of them, which can mix IPv4 and IPv6 addresses.
 func (n *NullHosts) UnmarshalJSON(data []byte) error {
 	if bytes.Equal(data, []byte(nullJSON)) {
 		n.Trie = nil
@@ -59,27 +71,34 @@ func (n *NullHosts) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	jsonSource := make(map[string]string)
+	jsonSource := make(map[string]json.RawMessage)
 	if err := json.Unmarshal(data, &jsonSource); err != nil {
 		return err
 	}
 
-	source := make(map[string]Host)
-	for k, v := range jsonSource {
-		ip, port, err := net.SplitHostPort(v)
-		if err == nil {
-			pInt, err := strconv.Atoi(port)
+	source := make(map[string][]Host)
+	for k, raw := range jsonSource {
+		var values []string
+		if err := json.Unmarshal(raw, &values); err != nil {
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return fmt.Errorf("the hosts value of %q has to be an address or an array of them", k)
+			}
+			values = []string{value}
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("the hosts value of %q can't be an empty array", k)
+		}
+		for _, v := range values {
+			host, err := parseHostsValue(v)
 			if err != nil {
 				return err
 			}
-
-			source[k] = Host{IP: net.ParseIP(ip), Port: pInt}
-		} else {
-			source[k] = Host{IP: net.ParseIP(v)}
+			source[k] = append(source[k], host)
 		}
 	}
 
-	hosts, err := NewHosts(source)
+	hosts, err := NewHostsWithAddresses(source)
 	if err != nil {
 		return err
 	}
@@ -88,10 +107,25 @@ func (n *NullHosts) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func parseHostsValue(v string) (Host, error) {
+	ip, port, err := net.SplitHostPort(v)
+	if err != nil {
+		return Host{IP: net.ParseIP(v)}, nil
+	}
+	pInt, err := strconv.Atoi(port)
+	if err != nil {
+		return Host{}, err
+	}
+	return Host{IP: net.ParseIP(ip), Port: pInt}, nil
+}
+
 // Hosts is wrapper around trieNode to integrate with net.TCPAddr
 type Hosts struct {
 	n      *trieNode
 	source map[string]Host
+	// addresses are all the addresses of the hosts that have several, the
+	// first of which is in source.
+	addresses map[string][]Host
 }
 
 // NewHosts returns new Hosts from given addresses.
@@ -113,6 +147,33 @@ func NewHosts(source map[string]Host) (*Hosts, error) {
 	return h, nil
 }
 
+// NewHostsWithAddresses returns new Hosts from the given addresses, a host can
+// have several, of different address families, so the dialer can pick the
+// first one of the family it's restricted to.
+func NewHostsWithAddresses(source map[string][]Host) (*Hosts, error) {
+	first := make(map[string]Host, len(source))
+	var addresses map[string][]Host
+	for k, v := range source {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("no addresses for the host pattern '%s'", k)
+		}
+		first[k] = v[0]
+		if len(v) > 1 {
+			if addresses == nil {
+				addresses = make(map[string][]Host)
+			}
+			addresses[strings.ToLower(k)] = v
+		}
+	}
+
+	h, err := NewHosts(first)
+	if err != nil {
+		return nil, err
+	}
+	h.addresses = addresses
+	return h, nil
+}
+
 func toLowerKeys(source map[string]Host) map[string]Host {
 	result := make(map[string]Host, len(source))
 	for k, v := range source {
@@ -163,3 +224,18 @@ func (t *Hosts) Match(s string) *Host {
 
 	return &address
 }
+
+// MatchAll returns all the addresses of the host matching s, or nil if none
+// matches. The addresses are returned in the order they were configured.
+func (t *Hosts) MatchAll(s string) []Host {
+	s = strings.ToLower(s)
+	match, ok := t.n.contains(s)
+	if !ok {
+		return nil
+	}
+
+	if addresses, ok := t.addresses[match]; ok {
+		return addresses
+	}
+	return []Host{t.source[match]}
+}
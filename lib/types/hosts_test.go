@@ -245,3 +245,28 @@ func TestHostsJSON(t *testing.T) {
 		}
 	})
 }
+
+func TestHostsMultipleAddresses(t *testing.T) {
+	t.Parallel()
+
+	var hosts NullHosts
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"*.example.com": ["1.2.3.4", "[aa::bb]:8443"],
+		"single.com": "5.6.7.8"
+	}`), &hosts))
+
+	assert.Equal(t, []Host{
+		{IP: net.ParseIP("1.2.3.4")},
+		{IP: net.ParseIP("aa::bb"), Port: 8443},
+	}, hosts.Trie.MatchAll("api.example.com"))
+	assert.Equal(t, &Host{IP: net.ParseIP("1.2.3.4")}, hosts.Trie.Match("api.example.com"))
+	assert.Equal(t, []Host{{IP: net.ParseIP("5.6.7.8")}}, hosts.Trie.MatchAll("single.com"))
+	assert.Nil(t, hosts.Trie.MatchAll("other.com"))
+
+	m, err := json.Marshal(hosts)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"*.example.com": ["1.2.3.4", "[aa::bb]:8443"], "single.com": "5.6.7.8"}`, string(m))
+
+	err = json.Unmarshal([]byte(`{"example.com": []}`), &hosts)
+	assert.ErrorContains(t, err, `the hosts value of "example.com" can't be an empty array`)
+}
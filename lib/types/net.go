@@ -1,6 +1,7 @@
 package types
 
 import (
+	"fmt"
 	"net"
 	"strconv"
 )
@@ -86,3 +87,40 @@ func splitHostPort(text []byte) (net.IP, string, error) {
 
 	return ip, port, nil
 }
+
+// The address families that the connections can be restricted to, with the
+// dialFamily option.
+const (
+	DialFamilyAny  = "any"
+	DialFamilyIPv4 = "ipv4"
+	DialFamilyIPv6 = "ipv6"
+)
+
+// ValidateDialFamily returns an error if family isn't one of the address
+// families of the dialFamily option.
+func ValidateDialFamily(family string) error {
+	switch family {
+	case DialFamilyAny, DialFamilyIPv4, DialFamilyIPv6:
+		return nil
+	default:
+		return fmt.Errorf("invalid dialFamily %q, it has to be one of %q, %q or %q",
+			family, DialFamilyIPv4, DialFamilyIPv6, DialFamilyAny)
+	}
+}
+
+// IPFamily returns the address family of ip, ipv4 or ipv6.
+func IPFamily(ip net.IP) string {
+	if ip.To4() != nil {
+		return DialFamilyIPv4
+	}
+	return DialFamilyIPv6
+}
+
+// InDialFamily returns whether ip belongs to the address family, an empty
+// family is the same as any.
+func InDialFamily(ip net.IP, family string) bool {
+	if family == "" || family == DialFamilyAny {
+		return true
+	}
+	return IPFamily(ip) == family
+}
@@ -62,9 +62,22 @@ type State struct {
 	CookieJar *cookiejar.Jar
 	TLSConfig *tls.Config
 
+	// IsolatedTransports are the transports with connections of their own
+	// that the requests of the VU reuse. The requests create transports of
+	// their own, that don't keep their connections alive, if it's nil.
+	IsolatedTransports *IsolatedTransports
+
+	// RequestDefaults are the defaults of the HTTP requests of the current
+	// scenario, if it has any. These will be assigned on VU activation.
+	RequestDefaults *RequestDefaults
+
 	// Rate limits.
 	RPSLimit *rate.Limiter
 
+	// RequestLimiter limits the concurrent requests of the scenario, if the
+	// maxConcurrentRequests option is set.
+	RequestLimiter *ScenarioRequestLimiter
+
 	// Sample channel, possibly buffered
 	Samples chan<- metrics.SampleContainer
 
@@ -100,6 +113,13 @@ type State struct {
 	// in a thread-safe manner. It is used to ensure that the test status is only
 	// marked as failed once, even if multiple VUs or goroutines, try to mark it at the same time.
 	TestStatus *TestStatus
+
+	// RunMetadata is the metadata of the test run, which the script can add to.
+	RunMetadata *RunMetadata
+
+	// IterationBreakdown accumulates the sleep and network time of the current
+	// iteration. It's nil unless the iterationBreakdown option is enabled.
+	IterationBreakdown *IterationBreakdown
 }
 
 // GetAddrResolver returns the AddrResolver implementation or nil if not available.
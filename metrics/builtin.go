@@ -2,11 +2,22 @@ package metrics
 
 //nolint:revive
 const (
-	VUsName               = "vus"
-	VUsMaxName            = "vus_max"
-	IterationsName        = "iterations"
-	IterationDurationName = "iteration_duration"
-	DroppedIterationsName = "dropped_iterations"
+	VUsName                = "vus"
+	VUsMaxName             = "vus_max"
+	IterationsName         = "iterations"
+	IterationDurationName  = "iteration_duration"
+	DroppedIterationsName  = "dropped_iterations"
+	IterationsTimedOutName = "iterations_timed_out"
+	MetricsDroppedName     = "metrics_dropped"
+	VUMemoryBytesName      = "vu_memory_bytes"
+
+	ArrivalRateVUsAllocatedName = "arrival_rate_vus_allocated"
+	ArrivalRateVUsBusyName      = "arrival_rate_vus_busy"
+	ArrivalRateVUsPendingName   = "arrival_rate_vus_pending"
+
+	IterationDurationScriptName  = "iteration_duration_script"
+	IterationDurationSleepName   = "iteration_duration_sleep"
+	IterationDurationNetworkName = "iteration_duration_network"
 
 	ChecksName        = "checks"
 	GroupDurationName = "group_duration"
@@ -21,26 +32,48 @@ const (
 	HTTPReqWaitingName        = "http_req_waiting"
 	HTTPReqReceivingName      = "http_req_receiving"
 
+	HTTPReqTLSCertDaysToExpiryName = "http_req_tls_cert_days_to_expiry"
+
 	WSSessionsName         = "ws_sessions"
 	WSMessagesSentName     = "ws_msgs_sent"
 	WSMessagesReceivedName = "ws_msgs_received"
 	WSPingName             = "ws_ping"
 	WSSessionDurationName  = "ws_session_duration"
 	WSConnectingName       = "ws_connecting"
+	WSPongTimeoutsName     = "ws_pong_timeouts"
 
 	GRPCReqDurationName = "grpc_req_duration"
 
 	DataSentName     = "data_sent"
 	DataReceivedName = "data_received"
+
+	NetConnsActiveName    = "net_conns_active"
+	NetConnsIdleName      = "net_conns_idle"
+	NetEphemeralPortsName = "net_ephemeral_ports"
+	NetConnsOpenedName    = "net_conns_opened"
+	NetConnsClosedName    = "net_conns_closed"
 )
 
 // BuiltinMetrics represent all the builtin metrics of k6
 type BuiltinMetrics struct {
-	VUs               *Metric
-	VUsMax            *Metric
-	Iterations        *Metric
-	IterationDuration *Metric
-	DroppedIterations *Metric
+	VUs                *Metric
+	VUsMax             *Metric
+	Iterations         *Metric
+	IterationDuration  *Metric
+	DroppedIterations  *Metric
+	IterationsTimedOut *Metric
+	MetricsDropped     *Metric
+	VUMemoryBytes      *Metric
+
+	// VU allocation of the arrival-rate executors.
+	ArrivalRateVUsAllocated *Metric
+	ArrivalRateVUsBusy      *Metric
+	ArrivalRateVUsPending   *Metric
+
+	// Iteration breakdown, emitted only with the iterationBreakdown option.
+	IterationDurationScript  *Metric
+	IterationDurationSleep   *Metric
+	IterationDurationNetwork *Metric
 
 	// Runner-emitted.
 	Checks        *Metric
@@ -57,6 +90,10 @@ type BuiltinMetrics struct {
 	HTTPReqWaiting        *Metric
 	HTTPReqReceiving      *Metric
 
+	// HTTPReqTLSCertDaysToExpiry is the days until the first certificate of
+	// the chain of the server expires.
+	HTTPReqTLSCertDaysToExpiry *Metric
+
 	// Websocket-related
 	WSSessions         *Metric
 	WSMessagesSent     *Metric
@@ -64,6 +101,7 @@ type BuiltinMetrics struct {
 	WSPing             *Metric
 	WSSessionDuration  *Metric
 	WSConnecting       *Metric
+	WSPongTimeouts     *Metric
 
 	// gRPC-related
 	GRPCReqDuration *Metric
@@ -71,16 +109,34 @@ type BuiltinMetrics struct {
 	// Network-related; used for future protocols as well.
 	DataSent     *Metric
 	DataReceived *Metric
+
+	// Connections, emitted only with the connectionMetrics option.
+	NetConnsActive    *Metric
+	NetConnsIdle      *Metric
+	NetEphemeralPorts *Metric
+	NetConnsOpened    *Metric
+	NetConnsClosed    *Metric
 }
 
 // RegisterBuiltinMetrics register and returns the builtin metrics in the provided registry
 func RegisterBuiltinMetrics(registry *Registry) *BuiltinMetrics {
 	return &BuiltinMetrics{
-		VUs:               registry.MustNewMetric(VUsName, Gauge),
-		VUsMax:            registry.MustNewMetric(VUsMaxName, Gauge),
-		Iterations:        registry.MustNewMetric(IterationsName, Counter),
-		IterationDuration: registry.MustNewMetric(IterationDurationName, Trend, Time),
-		DroppedIterations: registry.MustNewMetric(DroppedIterationsName, Counter),
+		VUs:                registry.MustNewMetric(VUsName, Gauge),
+		VUsMax:             registry.MustNewMetric(VUsMaxName, Gauge),
+		Iterations:         registry.MustNewMetric(IterationsName, Counter),
+		IterationDuration:  registry.MustNewMetric(IterationDurationName, Trend, Time),
+		DroppedIterations:  registry.MustNewMetric(DroppedIterationsName, Counter),
+		IterationsTimedOut: registry.MustNewMetric(IterationsTimedOutName, Counter),
+		MetricsDropped:     registry.MustNewMetric(MetricsDroppedName, Counter),
+		VUMemoryBytes:      registry.MustNewMetric(VUMemoryBytesName, Gauge, Data),
+
+		ArrivalRateVUsAllocated: registry.MustNewMetric(ArrivalRateVUsAllocatedName, Gauge),
+		ArrivalRateVUsBusy:      registry.MustNewMetric(ArrivalRateVUsBusyName, Gauge),
+		ArrivalRateVUsPending:   registry.MustNewMetric(ArrivalRateVUsPendingName, Gauge),
+
+		IterationDurationScript:  registry.MustNewMetric(IterationDurationScriptName, Trend, Time),
+		IterationDurationSleep:   registry.MustNewMetric(IterationDurationSleepName, Trend, Time),
+		IterationDurationNetwork: registry.MustNewMetric(IterationDurationNetworkName, Trend, Time),
 
 		Checks:        registry.MustNewMetric(ChecksName, Rate),
 		GroupDuration: registry.MustNewMetric(GroupDurationName, Trend, Time),
@@ -95,16 +151,25 @@ func RegisterBuiltinMetrics(registry *Registry) *BuiltinMetrics {
 		HTTPReqWaiting:        registry.MustNewMetric(HTTPReqWaitingName, Trend, Time),
 		HTTPReqReceiving:      registry.MustNewMetric(HTTPReqReceivingName, Trend, Time),
 
+		HTTPReqTLSCertDaysToExpiry: registry.MustNewMetric(HTTPReqTLSCertDaysToExpiryName, Gauge),
+
 		WSSessions:         registry.MustNewMetric(WSSessionsName, Counter),
 		WSMessagesSent:     registry.MustNewMetric(WSMessagesSentName, Counter),
 		WSMessagesReceived: registry.MustNewMetric(WSMessagesReceivedName, Counter),
 		WSPing:             registry.MustNewMetric(WSPingName, Trend, Time),
 		WSSessionDuration:  registry.MustNewMetric(WSSessionDurationName, Trend, Time),
 		WSConnecting:       registry.MustNewMetric(WSConnectingName, Trend, Time),
+		WSPongTimeouts:     registry.MustNewMetric(WSPongTimeoutsName, Counter),
 
 		GRPCReqDuration: registry.MustNewMetric(GRPCReqDurationName, Trend, Time),
 
 		DataSent:     registry.MustNewMetric(DataSentName, Counter, Data),
 		DataReceived: registry.MustNewMetric(DataReceivedName, Counter, Data),
+
+		NetConnsActive:    registry.MustNewMetric(NetConnsActiveName, Gauge),
+		NetConnsIdle:      registry.MustNewMetric(NetConnsIdleName, Gauge),
+		NetEphemeralPorts: registry.MustNewMetric(NetEphemeralPortsName, Gauge),
+		NetConnsOpened:    registry.MustNewMetric(NetConnsOpenedName, Counter),
+		NetConnsClosed:    registry.MustNewMetric(NetConnsClosedName, Counter),
 	}
 }
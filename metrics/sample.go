@@ -184,3 +184,20 @@ func parsePercentile(stat string) (float64, error) {
 
 	return percentile, nil
 }
+
+// These are the name and value of the tag that is added to all samples emitted
+// by warm-up iterations. Such samples are still sent to the outputs, but they
+// are excluded from the thresholds and the end-of-test summary.
+const (
+	PhaseTagName      = "phase"
+	WarmupPhaseTagVal = "warmup"
+)
+
+// IsWarmup returns whether the sample was emitted by a warm-up iteration.
+func (s Sample) IsWarmup() bool {
+	if s.Tags == nil {
+		return false
+	}
+	phase, ok := s.Tags.Get(PhaseTagName)
+	return ok && phase == WarmupPhaseTagVal
+}
@@ -117,6 +117,28 @@ type TrendSink struct {
 	count    uint64
 	min, max float64
 	sum      float64
+
+	// When the exact values are kept only for a window of time, the older
+	// values are moved from recent to the sketch and values is only a sorted
+	// copy of recent.
+	window time.Duration
+	recent []timedValue
+	sketch *trendSketch
+}
+
+type timedValue struct {
+	time  time.Time
+	value float64
+}
+
+// SetExactWindow makes the sink keep the exact values only of the samples
+// that are within the given duration from the latest one. The older values
+// are moved to a sketch, so the memory usage of the sink doesn't grow with
+// the duration of the test, while the percentiles stay accurate within 1%.
+// It should be called before any samples are added to the sink.
+func (t *TrendSink) SetExactWindow(window time.Duration) {
+	t.window = window
+	t.sketch = newTrendSketch()
 }
 
 // IsEmpty indicates whether the TrendSink is empty.
@@ -135,14 +157,29 @@ func (t *TrendSink) Add(s Sample) {
 		}
 	}
 
-	t.values = append(t.values, s.Value)
 	t.sorted = false
 	t.count++
 	t.sum += s.Value
+
+	if t.sketch == nil {
+		t.values = append(t.values, s.Value)
+		return
+	}
+	t.recent = append(t.recent, timedValue{time: s.Time, value: s.Value})
+	cutoff := s.Time.Add(-t.window)
+	expired := 0
+	for expired < len(t.recent) && t.recent[expired].time.Before(cutoff) {
+		t.sketch.add(t.recent[expired].value)
+		expired++
+	}
+	t.recent = t.recent[expired:]
 }
 
 // P calculates the given percentile from sink values.
 func (t *TrendSink) P(pct float64) float64 {
+	if t.sketch != nil {
+		return t.windowP(pct)
+	}
 	switch t.count {
 	case 0:
 		return 0
@@ -165,6 +202,51 @@ func (t *TrendSink) P(pct float64) float64 {
 	}
 }
 
+// windowP calculates the given percentile from both the exact values and the
+// sketch of the older ones, with the same interpolation as P().
+func (t *TrendSink) windowP(pct float64) float64 {
+	if t.count == 0 {
+		return 0
+	}
+	if !t.sorted {
+		t.values = t.values[:0]
+		for _, v := range t.recent {
+			t.values = append(t.values, v.value)
+		}
+		sort.Float64s(t.values)
+		t.sorted = true
+	}
+
+	buckets := t.sketch.buckets()
+	i := pct * (float64(t.count) - 1.0)
+	j := t.valueAt(buckets, uint64(math.Floor(i)))
+	k := t.valueAt(buckets, uint64(math.Ceil(i)))
+	f := i - math.Floor(i)
+	return j + (k-j)*f
+}
+
+// valueAt returns the value with the given rank, counted from 0, among the
+// sorted exact values and the sketch buckets combined.
+func (t *TrendSink) valueAt(buckets []sketchBucket, rank uint64) float64 {
+	var before uint64 // the number of values that are smaller than the current bucket
+	counted := 0      // the number of exact values that are already counted in before
+	for _, b := range buckets {
+		smaller := sort.SearchFloat64s(t.values, b.value)
+		if rank < before+uint64(smaller-counted) { //nolint:gosec
+			return t.values[counted+int(rank-before)] //nolint:gosec
+		}
+		before += uint64(smaller - counted) //nolint:gosec
+		counted = smaller
+		if rank < before+b.count {
+			// the value that represents the bucket may be outside of the range
+			// of the values that are in it
+			return math.Min(math.Max(b.value, t.min), t.max)
+		}
+		before += b.count
+	}
+	return t.values[counted+int(rank-before)] //nolint:gosec
+}
+
 // Min returns the minimum value.
 func (t *TrendSink) Min() float64 {
 	return t.min
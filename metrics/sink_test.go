@@ -236,6 +236,40 @@ func TestTrendSink(t *testing.T) {
 	})
 }
 
+func TestTrendSinkExactWindow(t *testing.T) {
+	t.Parallel()
+
+	exact := NewTrendSink()
+	windowed := NewTrendSink()
+	windowed.SetExactWindow(time.Minute)
+
+	start := time.Now()
+	for i := range 10000 {
+		s := Sample{
+			TimeSeries: TimeSeries{Metric: &Metric{}},
+			Time:       start.Add(time.Duration(i) * time.Second),
+			Value:      float64(i%1000) - 100,
+		}
+		exact.Add(s)
+		windowed.Add(s)
+	}
+
+	assert.Len(t, windowed.recent, 61)
+	assert.Equal(t, exact.Count(), windowed.Count())
+	assert.Equal(t, exact.Min(), windowed.Min())
+	assert.Equal(t, exact.Max(), windowed.Max())
+	assert.Equal(t, exact.Avg(), windowed.Avg())
+	for _, pct := range []float64{0, 0.01, 0.1, 0.25, 0.5, 0.9, 0.95, 0.99, 1} {
+		expected := exact.P(pct)
+		assert.InDelta(t, expected, windowed.P(pct), math.Abs(expected)*0.01+1e-9, "p(%g)", pct*100)
+	}
+
+	// the percentiles are still correct after more values are added
+	windowed.Add(Sample{TimeSeries: TimeSeries{Metric: &Metric{}}, Time: start.Add(time.Hour * 3), Value: 5000})
+	assert.Equal(t, 5000.0, windowed.P(1))
+	assert.Len(t, windowed.recent, 1)
+}
+
 func TestRateSink(t *testing.T) {
 	t.Parallel()
 	samples6 := []float64{1.0, 0.0, 1.0, 0.0, 0.0, 1.0}
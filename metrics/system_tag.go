@@ -38,15 +38,25 @@ const (
 	TagVU   // non-indexable
 	TagOCSPStatus
 	TagIP
+	TagRedirectHop
+	TagTLSResumed
+	TagIPFamily
+	TagVirtualHost
+
+	// System tags added later, which are enabled by default.
+	TagJourney
+	TagRemoteHost
 )
 
 // DefaultSystemTagSet includes all of the system tags emitted with metrics by default.
-// Other tags that are not enabled by default include: iter, vu, ocsp_status, ip
+// Other tags that are not enabled by default include: iter, vu, ocsp_status, ip, redirect_hop, tls_resumed,
+// ip_family, virtual_host
 //
 //nolint:gochecknoglobals
 var DefaultSystemTagSet = SystemTagSet(
 	TagProto | TagSubproto | TagStatus | TagMethod | TagURL | TagName | TagGroup |
-		TagCheck | TagError | TagErrorCode | TagTLSVersion | TagScenario | TagService | TagExpectedResponse)
+		TagCheck | TagError | TagErrorCode | TagTLSVersion | TagScenario | TagService | TagExpectedResponse |
+		TagJourney | TagRemoteHost)
 
 // NonIndexableSystemTags are high cardinality system tags (i.e. metadata).
 //
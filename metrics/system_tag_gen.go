@@ -6,27 +6,33 @@ import (
 	"fmt"
 )
 
-const _SystemTagName = "protosubprotostatusmethodurlnamegroupcheckerrorerror_codetls_versionscenarioserviceexpected_responseitervuocsp_statusip"
+const _SystemTagName = "protosubprotostatusmethodurlnamegroupcheckerrorerror_codetls_versionscenarioserviceexpected_responseitervuocsp_statusipredirect_hoptls_resumedip_familyvirtual_hostjourneyremote_host"
 
 var _SystemTagMap = map[SystemTag]string{
-	1:      _SystemTagName[0:5],
-	2:      _SystemTagName[5:13],
-	4:      _SystemTagName[13:19],
-	8:      _SystemTagName[19:25],
-	16:     _SystemTagName[25:28],
-	32:     _SystemTagName[28:32],
-	64:     _SystemTagName[32:37],
-	128:    _SystemTagName[37:42],
-	256:    _SystemTagName[42:47],
-	512:    _SystemTagName[47:57],
-	1024:   _SystemTagName[57:68],
-	2048:   _SystemTagName[68:76],
-	4096:   _SystemTagName[76:83],
-	8192:   _SystemTagName[83:100],
-	16384:  _SystemTagName[100:104],
-	32768:  _SystemTagName[104:106],
-	65536:  _SystemTagName[106:117],
-	131072: _SystemTagName[117:119],
+	1:       _SystemTagName[0:5],
+	2:       _SystemTagName[5:13],
+	4:       _SystemTagName[13:19],
+	8:       _SystemTagName[19:25],
+	16:      _SystemTagName[25:28],
+	32:      _SystemTagName[28:32],
+	64:      _SystemTagName[32:37],
+	128:     _SystemTagName[37:42],
+	256:     _SystemTagName[42:47],
+	512:     _SystemTagName[47:57],
+	1024:    _SystemTagName[57:68],
+	2048:    _SystemTagName[68:76],
+	4096:    _SystemTagName[76:83],
+	8192:    _SystemTagName[83:100],
+	16384:   _SystemTagName[100:104],
+	32768:   _SystemTagName[104:106],
+	65536:   _SystemTagName[106:117],
+	131072:  _SystemTagName[117:119],
+	262144:  _SystemTagName[119:131],
+	524288:  _SystemTagName[131:142],
+	1048576: _SystemTagName[142:151],
+	2097152: _SystemTagName[151:163],
+	4194304: _SystemTagName[163:170],
+	8388608: _SystemTagName[170:181],
 }
 
 func (i SystemTag) String() string {
@@ -36,7 +42,7 @@ func (i SystemTag) String() string {
 	return fmt.Sprintf("SystemTag(%d)", i)
 }
 
-var _SystemTagValues = []SystemTag{1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024, 2048, 4096, 8192, 16384, 32768, 65536, 131072}
+var _SystemTagValues = []SystemTag{1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024, 2048, 4096, 8192, 16384, 32768, 65536, 131072, 262144, 524288, 1048576, 2097152, 4194304, 8388608}
 
 var _SystemTagNameToValueMap = map[string]SystemTag{
 	_SystemTagName[0:5]:     1,
@@ -57,6 +63,12 @@ var _SystemTagNameToValueMap = map[string]SystemTag{
 	_SystemTagName[104:106]: 32768,
 	_SystemTagName[106:117]: 65536,
 	_SystemTagName[117:119]: 131072,
+	_SystemTagName[119:131]: 262144,
+	_SystemTagName[131:142]: 524288,
+	_SystemTagName[142:151]: 1048576,
+	_SystemTagName[151:163]: 2097152,
+	_SystemTagName[163:170]: 4194304,
+	_SystemTagName[170:181]: 8388608,
 }
 
 // SystemTagString retrieves an enum value from the enum constants string name.
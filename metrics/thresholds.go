@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,6 +27,20 @@ type Threshold struct {
 	AbortGracePeriod types.NullDuration
 	// parsed is the threshold expression parsed from the Source
 	parsed *thresholdExpression
+	// timeline has every evaluation of the threshold, in order
+	timeline []ThresholdEvaluation
+}
+
+// ThresholdEvaluation is the result of a single evaluation of a threshold.
+type ThresholdEvaluation struct {
+	// Time is when the threshold was evaluated
+	Time time.Time
+	// TestRunDuration is how long the test had been running at the evaluation
+	TestRunDuration time.Duration
+	// Value is the observed value of the threshold's aggregation
+	Value float64
+	// Ok is whether the threshold passed
+	Ok bool
 }
 
 func newThreshold(src string, abortOnFail bool, gracePeriod types.NullDuration) *Threshold {
@@ -85,6 +100,28 @@ func (t *Threshold) run(sinks map[string]float64) (bool, error) {
 	return passes, err
 }
 
+// record adds the result of an evaluation to the timeline of the threshold.
+// Evaluations without an observed value, e.g. of a rate without samples yet,
+// aren't recorded.
+func (t *Threshold) record(sinks map[string]float64, timeSpentInTest time.Duration, passes bool) {
+	value, ok := sinks[t.parsed.SinkKey()]
+	if !ok {
+		return
+	}
+	t.timeline = append(t.timeline, ThresholdEvaluation{
+		Time:            time.Now(),
+		TestRunDuration: timeSpentInTest,
+		Value:           value,
+		Ok:              passes,
+	})
+}
+
+// Timeline returns every evaluation of the threshold with its observed value,
+// in the order they happened.
+func (t *Threshold) Timeline() []ThresholdEvaluation {
+	return slices.Clone(t.timeline)
+}
+
 type thresholdConfig struct {
 	Threshold        string             `json:"threshold"`
 	AbortOnFail      bool               `json:"abortOnFail"`
@@ -149,6 +186,7 @@ func (ts *Thresholds) runAll(timeSpentInTest time.Duration) (bool, error) {
 		if err != nil {
 			return false, fmt.Errorf("threshold %d run error: %w", i, err)
 		}
+		threshold.record(ts.sinked, timeSpentInTest, b)
 
 		if !b {
 			succeeded = false
@@ -634,6 +634,35 @@ func TestThresholdsRunAll(t *testing.T) {
 	}
 }
 
+func TestThresholdsRunTimeline(t *testing.T) {
+	t.Parallel()
+
+	thresholds := NewThresholds([]string{`count<3`})
+	require.NoError(t, thresholds.Parse())
+	assert.Empty(t, thresholds.Thresholds[0].Timeline())
+
+	sink := &CounterSink{}
+	for i, value := range []float64{1, 1, 2, 0, 0} {
+		sink.Value += value
+		_, err := thresholds.Run(sink, time.Duration(i+1)*time.Second)
+		require.NoError(t, err)
+	}
+
+	timeline := thresholds.Thresholds[0].Timeline()
+	require.Len(t, timeline, 5)
+	for i, expected := range []struct {
+		value float64
+		ok    bool
+	}{{1, true}, {2, true}, {4, false}, {4, false}, {4, false}} {
+		assert.Equal(t, time.Duration(i+1)*time.Second, timeline[i].TestRunDuration)
+		assert.Equal(t, expected.value, timeline[i].Value)
+		assert.Equal(t, expected.ok, timeline[i].Ok)
+		if i > 0 {
+			assert.False(t, timeline[i-1].Time.After(timeline[i].Time))
+		}
+	}
+}
+
 func getTrendSink(values ...float64) *TrendSink {
 	sink := NewTrendSink()
 	for _, v := range values {
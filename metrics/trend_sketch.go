@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+)
+
+// trendSketchAccuracy is the maximum relative error of the values that are
+// returned from a trendSketch.
+const trendSketchAccuracy = 0.01
+
+//nolint:gochecknoglobals
+var trendSketchGamma = (1 + trendSketchAccuracy) / (1 - trendSketchAccuracy)
+
+// trendSketch is a histogram with logarithmically sized buckets, so its
+// memory usage depends on the range of the values and not on their count,
+// while every value that is returned from it is within trendSketchAccuracy of
+// the real one. Positive and negative values are kept in separate buckets.
+type trendSketch struct {
+	positive map[int32]uint64
+	negative map[int32]uint64
+	zeros    uint64
+	count    uint64
+}
+
+func newTrendSketch() *trendSketch {
+	return &trendSketch{
+		positive: make(map[int32]uint64),
+		negative: make(map[int32]uint64),
+	}
+}
+
+func sketchIndex(v float64) int32 {
+	return int32(math.Ceil(math.Log(v) / math.Log(trendSketchGamma)))
+}
+
+func sketchValue(index int32) float64 {
+	return 2 * math.Pow(trendSketchGamma, float64(index)) / (trendSketchGamma + 1)
+}
+
+func (s *trendSketch) add(v float64) {
+	switch {
+	case v > 0:
+		s.positive[sketchIndex(v)]++
+	case v < 0:
+		s.negative[sketchIndex(-v)]++
+	default:
+		s.zeros++
+	}
+	s.count++
+}
+
+// sketchBucket is a bucket of a trendSketch with the value that represents
+// all the values in it.
+type sketchBucket struct {
+	value float64
+	count uint64
+}
+
+// buckets returns all non-empty buckets, sorted by their values.
+func (s *trendSketch) buckets() []sketchBucket {
+	result := make([]sketchBucket, 0, len(s.positive)+len(s.negative)+1)
+	for i, c := range s.negative {
+		result = append(result, sketchBucket{value: -sketchValue(i), count: c})
+	}
+	if s.zeros > 0 {
+		result = append(result, sketchBucket{value: 0, count: s.zeros})
+	}
+	for i, c := range s.positive {
+		result = append(result, sketchBucket{value: sketchValue(i), count: c})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].value < result[j].value })
+	return result
+}
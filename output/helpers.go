@@ -8,14 +8,62 @@ import (
 	"go.k6.io/k6/metrics"
 )
 
+// BufferPolicy is what a [SampleBuffer] does when it has reached its limit.
+type BufferPolicy string
+
+// The possible buffer policies.
+const (
+	// BufferPolicyBlock blocks the caller of AddMetricSamples() until the
+	// buffered samples are flushed. The Manager calls it from a goroutine of
+	// the output, so the other outputs still get their samples, and this
+	// back-pressure eventually blocks the VUs.
+	BufferPolicyBlock BufferPolicy = "block"
+	// BufferPolicyDrop drops the samples that don't fit in the buffer.
+	BufferPolicyDrop BufferPolicy = "drop"
+	// BufferPolicyAggregate merges the buffered counter and gauge samples of the
+	// same time series, and drops whatever still doesn't fit in the buffer. Only
+	// the plain samples are merged, the other sample containers, like the HTTP
+	// trails, are kept as they are.
+	BufferPolicyAggregate BufferPolicy = "aggregate"
+)
+
 // SampleBuffer is a simple thread-safe buffer for metric samples. It should be
 // used by most outputs, since we generally want to flush metric samples to the
 // remote service asynchronously. We want to do it only every several seconds,
 // and we don't want to block the Engine in the meantime.
+//
+// By default the buffer is unbounded, SetBufferLimit() can be used to limit
+// how many samples it can hold if the output falls behind.
 type SampleBuffer struct {
 	sync.Mutex
 	buffer []metrics.SampleContainer
 	maxLen int
+
+	limit    int
+	policy   BufferPolicy
+	buffered int
+	dropped  uint64
+	flushed  *sync.Cond
+}
+
+// SetBufferLimit limits how many samples can be buffered and sets what
+// happens to the new ones when the limit is reached. A limit of 0 means that
+// the buffer is unbounded. It should be called before the output is started.
+func (sc *SampleBuffer) SetBufferLimit(limit int, policy BufferPolicy) {
+	sc.Lock()
+	defer sc.Unlock()
+	sc.limit, sc.policy = limit, policy
+	if sc.flushed == nil {
+		sc.flushed = sync.NewCond(&sc.Mutex)
+	}
+}
+
+// DroppedSamples returns how many samples have been dropped so far because
+// the buffer was full.
+func (sc *SampleBuffer) DroppedSamples() uint64 {
+	sc.Lock()
+	defer sc.Unlock()
+	return sc.dropped
 }
 
 // AddMetricSamples adds the given metric samples to the internal buffer.
@@ -24,8 +72,53 @@ func (sc *SampleBuffer) AddMetricSamples(samples []metrics.SampleContainer) {
 		return
 	}
 	sc.Lock()
-	sc.buffer = append(sc.buffer, samples...)
-	sc.Unlock()
+	defer sc.Unlock()
+	if sc.limit <= 0 {
+		sc.buffer = append(sc.buffer, samples...)
+		return
+	}
+
+	count := countSamples(samples)
+	switch sc.policy {
+	case BufferPolicyDrop:
+		sc.addOrDrop(samples)
+	case BufferPolicyAggregate:
+		if sc.buffered+count > sc.limit {
+			aggregated, kept := aggregateSamples(append(sc.buffer, samples...))
+			if len(aggregated) > sc.limit {
+				sc.dropped += uint64(len(aggregated) - sc.limit) //nolint:gosec
+				aggregated = aggregated[:sc.limit]
+			}
+			sc.buffer = []metrics.SampleContainer{aggregated}
+			sc.buffered = len(aggregated)
+			sc.addOrDrop(kept)
+			return
+		}
+		sc.buffer = append(sc.buffer, samples...)
+		sc.buffered += count
+	default:
+		// Always accept the samples into an empty buffer, so a batch bigger
+		// than the limit doesn't block forever.
+		for sc.buffered > 0 && sc.buffered+count > sc.limit {
+			sc.flushed.Wait()
+		}
+		sc.buffer = append(sc.buffer, samples...)
+		sc.buffered += count
+	}
+}
+
+// addOrDrop adds the sample containers that still fit in the buffer and
+// counts the samples of the rest as dropped.
+func (sc *SampleBuffer) addOrDrop(samples []metrics.SampleContainer) {
+	for _, container := range samples {
+		count := len(container.GetSamples())
+		if sc.buffered+count > sc.limit {
+			sc.dropped += uint64(count) //nolint:gosec
+			continue
+		}
+		sc.buffer = append(sc.buffer, container)
+		sc.buffered += count
+	}
 }
 
 // GetBufferedSamples returns the currently buffered metric samples and makes a
@@ -45,10 +138,58 @@ func (sc *SampleBuffer) GetBufferedSamples() []metrics.SampleContainer {
 	// Make the new buffer halfway between the previously allocated size and the
 	// maximum buffer size we've seen so far, to hopefully reduce copying a bit.
 	sc.buffer = make([]metrics.SampleContainer, 0, (bufferedLen+sc.maxLen)/2)
+	sc.buffered = 0
+	if sc.flushed != nil {
+		sc.flushed.Broadcast()
+	}
 
 	return buffered
 }
 
+func countSamples(samples []metrics.SampleContainer) int {
+	count := 0
+	for _, container := range samples {
+		count += len(container.GetSamples())
+	}
+	return count
+}
+
+// aggregateSamples merges all counter samples of the same time series, of the
+// plain sample containers, into one with their sum, and all gauge samples into
+// their last one. The samples of the other metric types can't be merged without
+// losing data, so they're kept as they are. The other sample containers, which
+// carry more than their samples, are returned separately, unchanged.
+func aggregateSamples(samples []metrics.SampleContainer) (metrics.Samples, []metrics.SampleContainer) {
+	merged := make(map[metrics.TimeSeries]int)
+	result := metrics.Samples{}
+	var kept []metrics.SampleContainer
+	for _, container := range samples {
+		switch container.(type) {
+		case metrics.Sample, metrics.Samples:
+		default:
+			kept = append(kept, container)
+			continue
+		}
+		for _, sample := range container.GetSamples() {
+			if sample.Metric.Type != metrics.Counter && sample.Metric.Type != metrics.Gauge {
+				result = append(result, sample)
+				continue
+			}
+			i, ok := merged[sample.TimeSeries]
+			if !ok {
+				merged[sample.TimeSeries] = len(result)
+				result = append(result, sample)
+				continue
+			}
+			if sample.Metric.Type == metrics.Counter {
+				sample.Value += result[i].Value
+			}
+			result[i] = sample
+		}
+	}
+	return result, kept
+}
+
 // PeriodicFlusher is a small helper for asynchronously flushing buffered metric
 // samples on regular intervals. The biggest benefit is having a Stop() method
 // that waits for one last flush before it returns.
@@ -50,6 +50,93 @@ func TestSampleBufferBasics(t *testing.T) {
 	assert.Empty(t, buffer.GetBufferedSamples())
 }
 
+func TestSampleBufferLimit(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	counter := registry.MustNewMetric("my_counter", metrics.Counter)
+	gauge := registry.MustNewMetric("my_gauge", metrics.Gauge)
+	tags := registry.RootTagSet().With("tag1", "val1")
+	sample := func(m *metrics.Metric, v float64) metrics.Sample {
+		return metrics.Sample{TimeSeries: metrics.TimeSeries{Metric: m, Tags: tags}, Time: time.Now(), Value: v}
+	}
+
+	t.Run("drop", func(t *testing.T) {
+		t.Parallel()
+		buffer := SampleBuffer{}
+		buffer.SetBufferLimit(3, BufferPolicyDrop)
+		c1, c2, c3 := sample(counter, 1), sample(counter, 2), sample(counter, 3)
+		connected := metrics.ConnectedSamples{Samples: []metrics.Sample{c1, c2}}
+
+		buffer.AddMetricSamples([]metrics.SampleContainer{c1, connected, c2, c3})
+		assert.Equal(t, uint64(2), buffer.DroppedSamples())
+		assert.Equal(t, []metrics.SampleContainer{c1, connected}, buffer.GetBufferedSamples())
+
+		buffer.AddMetricSamples([]metrics.SampleContainer{c3})
+		assert.Equal(t, []metrics.SampleContainer{c3}, buffer.GetBufferedSamples())
+		assert.Equal(t, uint64(2), buffer.DroppedSamples())
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		t.Parallel()
+		buffer := SampleBuffer{}
+		buffer.SetBufferLimit(2, BufferPolicyAggregate)
+		buffer.AddMetricSamples([]metrics.SampleContainer{sample(counter, 1), sample(gauge, 5)})
+		buffer.AddMetricSamples([]metrics.SampleContainer{sample(counter, 2), sample(gauge, 7)})
+		buffer.AddMetricSamples([]metrics.SampleContainer{sample(counter, 3)})
+
+		buffered := buffer.GetBufferedSamples()
+		require.Len(t, buffered, 1)
+		samples := buffered[0].GetSamples()
+		require.Len(t, samples, 2)
+		assert.Equal(t, counter, samples[0].Metric)
+		assert.Equal(t, 6.0, samples[0].Value)
+		assert.Equal(t, gauge, samples[1].Metric)
+		assert.Equal(t, 7.0, samples[1].Value)
+		assert.Zero(t, buffer.DroppedSamples())
+	})
+
+	t.Run("aggregate keeps the containers", func(t *testing.T) {
+		t.Parallel()
+		buffer := SampleBuffer{}
+		buffer.SetBufferLimit(3, BufferPolicyAggregate)
+		connected := metrics.ConnectedSamples{Samples: []metrics.Sample{sample(counter, 1), sample(gauge, 2)}}
+		buffer.AddMetricSamples([]metrics.SampleContainer{sample(counter, 1), connected})
+		buffer.AddMetricSamples([]metrics.SampleContainer{sample(counter, 2), connected})
+
+		buffered := buffer.GetBufferedSamples()
+		require.Len(t, buffered, 2)
+		samples := buffered[0].GetSamples()
+		require.Len(t, samples, 1)
+		assert.Equal(t, 3.0, samples[0].Value)
+		assert.Equal(t, connected, buffered[1])
+		assert.Equal(t, uint64(2), buffer.DroppedSamples(), "the second connected samples don't fit")
+	})
+
+	t.Run("block", func(t *testing.T) {
+		t.Parallel()
+		buffer := SampleBuffer{}
+		buffer.SetBufferLimit(2, BufferPolicyBlock)
+		// a batch bigger than the limit is still accepted into an empty buffer
+		buffer.AddMetricSamples([]metrics.SampleContainer{sample(counter, 1), sample(counter, 2), sample(counter, 3)})
+
+		added := make(chan struct{})
+		go func() {
+			buffer.AddMetricSamples([]metrics.SampleContainer{sample(counter, 4)})
+			close(added)
+		}()
+		select {
+		case <-added:
+			t.Fatal("AddMetricSamples() should block while the buffer is full")
+		case <-time.After(50 * time.Millisecond):
+		}
+		assert.Len(t, buffer.GetBufferedSamples(), 3)
+		<-added
+		assert.Len(t, buffer.GetBufferedSamples(), 1)
+		assert.Zero(t, buffer.DroppedSamples())
+	})
+}
+
 func TestSampleBufferConcurrently(t *testing.T) {
 	t.Parallel()
 
@@ -1,6 +1,8 @@
 package output
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -11,12 +13,20 @@ import (
 // TODO: completely get rid of this, see https://github.com/grafana/k6/issues/2430
 const sendBatchToOutputsRate = 50 * time.Millisecond
 
+// outputBatchesQueueSize is how many batches of samples can wait for an output
+// that is slow to accept them, before the manager waits for it too.
+const outputBatchesQueueSize = 10
+
 // Manager can be used to manage multiple outputs at the same time.
 type Manager struct {
 	outputs []Output
 	logger  logrus.FieldLogger
 
 	testStopCallback func(error)
+
+	droppedMetric *metrics.Metric
+	droppedTags   *metrics.TagSet
+	dropped       []uint64
 }
 
 // NewManager returns a new manager for the given outputs.
@@ -28,8 +38,20 @@ func NewManager(outputs []Output, logger logrus.FieldLogger, testStopCallback fu
 	}
 }
 
+// SetDroppedSamplesMetric makes the manager periodically emit samples of the
+// given counter metric with the number of samples that the outputs dropped
+// because their buffers were full. Every sample has the given tags and the
+// description of the output as the "output" tag.
+func (om *Manager) SetDroppedSamplesMetric(metric *metrics.Metric, tags *metrics.TagSet) {
+	om.droppedMetric, om.droppedTags = metric, tags
+	om.dropped = make([]uint64, len(om.outputs))
+}
+
 // Start spins up all configured outputs and then starts a new goroutine that
-// pipes metrics from the given samples channel to them.
+// pipes metrics from the given samples channel to them. Every output gets the
+// samples on a goroutine of its own, so an output that blocks in
+// AddMetricSamples(), like the ones with the block buffer policy, doesn't
+// delay the samples of the others.
 //
 // If some output fails to start, it stops the already started ones. This may
 // take some time, since some outputs make initial network requests to set up
@@ -40,6 +62,21 @@ func NewManager(outputs []Output, logger logrus.FieldLogger, testStopCallback fu
 // all of its buffered metrics have been sent to all outputs. The second
 // callback will call the Stop() or StopWithTestError() method of every output.
 func (om *Manager) Start(samplesChan chan metrics.SampleContainer) (wait func(), finish func(error), err error) {
+	wait, finishWithErr, err := om.StartWithStopErrors(samplesChan)
+	if err != nil {
+		return nil, nil, err
+	}
+	finish = func(testErr error) {
+		_ = finishWithErr(testErr) // the errors are already logged
+	}
+	return wait, finish, nil
+}
+
+// StartWithStopErrors is like Start, but its finish callback also returns the
+// errors of the outputs that failed to stop, e.g. to flush their last metrics.
+func (om *Manager) StartWithStopErrors(
+	samplesChan chan metrics.SampleContainer,
+) (wait func(), finish func(error) error, err error) {
 	if err := om.startOutputs(); err != nil {
 		return nil, nil, err
 	}
@@ -47,9 +84,26 @@ func (om *Manager) Start(samplesChan chan metrics.SampleContainer) (wait func(),
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
 
+	outputsWG := &sync.WaitGroup{}
+	queues := make([]chan []metrics.SampleContainer, len(om.outputs))
+	for i, out := range om.outputs {
+		queue := make(chan []metrics.SampleContainer, outputBatchesQueueSize)
+		queues[i] = queue
+		outputsWG.Add(1)
+		go func() {
+			defer outputsWG.Done()
+			for sampleContainers := range queue {
+				out.AddMetricSamples(sampleContainers)
+			}
+		}()
+	}
+
 	sendToOutputs := func(sampleContainers []metrics.SampleContainer) {
-		for _, out := range om.outputs {
-			out.AddMetricSamples(sampleContainers)
+		if len(sampleContainers) == 0 {
+			return
+		}
+		for _, queue := range queues {
+			queue <- sampleContainers
 		}
 	}
 
@@ -64,24 +118,67 @@ func (om *Manager) Start(samplesChan chan metrics.SampleContainer) (wait func(),
 			case sampleContainer, ok := <-samplesChan:
 				if !ok {
 					sendToOutputs(buffer)
+					for _, queue := range queues {
+						close(queue)
+					}
+					outputsWG.Wait()
+					// report the samples that were dropped from the last batch too
+					if dropped := om.appendDroppedSamples(nil); len(dropped) > 0 {
+						for _, out := range om.outputs {
+							out.AddMetricSamples(dropped)
+						}
+					}
 					return
 				}
 				buffer = append(buffer, sampleContainer)
 			case <-ticker.C:
-				sendToOutputs(buffer)
+				sendToOutputs(om.appendDroppedSamples(buffer))
 				buffer = make([]metrics.SampleContainer, 0, cap(buffer))
 			}
 		}
 	}()
 
 	wait = wg.Wait
-	finish = func(testErr error) {
+	finish = func(testErr error) error {
 		wait() // just in case, though it shouldn't be needed if API is used correctly
-		om.stopOutputs(testErr, len(om.outputs))
+		return om.stopOutputs(testErr, len(om.outputs))
 	}
 	return wait, finish, nil
 }
 
+// appendDroppedSamples appends a sample for every output that has dropped
+// samples since the last time it was called.
+func (om *Manager) appendDroppedSamples(buffer []metrics.SampleContainer) []metrics.SampleContainer {
+	if om.droppedMetric == nil {
+		return buffer
+	}
+	now := time.Now()
+	for i, out := range om.outputs {
+		limitOut, ok := out.(WithBufferLimit)
+		if !ok {
+			continue
+		}
+		dropped := limitOut.DroppedSamples()
+		previous := om.dropped[i]
+		if dropped <= previous {
+			continue
+		}
+		if previous == 0 {
+			om.logger.Warnf("The output %s has fallen behind and is dropping metric samples", out.Description())
+		}
+		om.dropped[i] = dropped
+		buffer = append(buffer, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{
+				Metric: om.droppedMetric,
+				Tags:   om.droppedTags.With("output", out.Description()),
+			},
+			Time:  now,
+			Value: float64(dropped - previous),
+		})
+	}
+	return buffer
+}
+
 // startOutputs spins up all configured outputs. If some output fails to start,
 // it stops the already started ones. This may take some time, since some
 // outputs make initial network requests to set up whatever remote services are
@@ -94,15 +191,16 @@ func (om *Manager) startOutputs() error {
 		}
 
 		if err := out.Start(); err != nil {
-			om.stopOutputs(err, i)
+			_ = om.stopOutputs(err, i)
 			return err
 		}
 	}
 	return nil
 }
 
-func (om *Manager) stopOutputs(testErr error, upToID int) {
+func (om *Manager) stopOutputs(testErr error, upToID int) error {
 	om.logger.Debugf("Stopping %d outputs...", upToID)
+	var errs []error
 	for i := 0; i < upToID; i++ {
 		out := om.outputs[i]
 		var err error
@@ -114,6 +212,8 @@ func (om *Manager) stopOutputs(testErr error, upToID int) {
 
 		if err != nil {
 			om.logger.WithError(err).Errorf("Stopping output %d failed", i)
+			errs = append(errs, fmt.Errorf("%s: %w", out.Description(), err))
 		}
 	}
+	return errors.Join(errs...)
 }
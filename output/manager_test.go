@@ -0,0 +1,122 @@
+package output
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/internal/lib/testutils"
+	"go.k6.io/k6/metrics"
+)
+
+type limitedOutput struct {
+	SampleBuffer
+}
+
+func (o *limitedOutput) Description() string { return "limited" }
+func (o *limitedOutput) Start() error        { return nil }
+func (o *limitedOutput) Stop() error         { return nil }
+
+func TestManagerDroppedSamples(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	builtin := metrics.RegisterBuiltinMetrics(registry)
+	counter := registry.MustNewMetric("my_counter", metrics.Counter)
+	sample := metrics.Sample{
+		TimeSeries: metrics.TimeSeries{Metric: counter, Tags: registry.RootTagSet()},
+		Time:       time.Now(),
+		Value:      1,
+	}
+
+	limited := &limitedOutput{}
+	limited.SetBufferLimit(1, BufferPolicyDrop)
+	unlimited := &limitedOutput{}
+
+	manager := NewManager([]Output{limited, unlimited}, testutils.NewLogger(t), func(error) {})
+	manager.SetDroppedSamplesMetric(builtin.MetricsDropped, registry.RootTagSet().With("run", "tag"))
+	samples := make(chan metrics.SampleContainer, 3)
+	wait, finish, err := manager.Start(samples)
+	require.NoError(t, err)
+	samples <- sample
+	samples <- sample
+	samples <- sample
+	close(samples)
+	wait()
+	finish(nil)
+
+	var dropped []metrics.Sample
+	for _, sc := range unlimited.GetBufferedSamples() {
+		for _, s := range sc.GetSamples() {
+			if s.Metric == builtin.MetricsDropped {
+				dropped = append(dropped, s)
+			}
+		}
+	}
+	require.Len(t, dropped, 1)
+	assert.Equal(t, 2.0, dropped[0].Value)
+	assert.Equal(t, map[string]string{"run": "tag", "output": "limited"}, dropped[0].Tags.Map())
+}
+
+type blockedOutput struct {
+	limitedOutput
+	release chan struct{}
+}
+
+func (o *blockedOutput) AddMetricSamples(samples []metrics.SampleContainer) {
+	<-o.release
+	o.limitedOutput.AddMetricSamples(samples)
+}
+
+func TestManagerBlockedOutput(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	counter := registry.MustNewMetric("my_counter", metrics.Counter)
+	sample := metrics.Sample{
+		TimeSeries: metrics.TimeSeries{Metric: counter, Tags: registry.RootTagSet()},
+		Time:       time.Now(),
+		Value:      1,
+	}
+
+	blocked := &blockedOutput{release: make(chan struct{})}
+	other := &limitedOutput{}
+	manager := NewManager([]Output{blocked, other}, testutils.NewLogger(t), func(error) {})
+	samples := make(chan metrics.SampleContainer, 1)
+	wait, finish, err := manager.Start(samples)
+	require.NoError(t, err)
+
+	samples <- sample
+	// the other output gets the samples while the blocked one doesn't accept them
+	require.Eventually(t, func() bool {
+		return len(other.GetBufferedSamples()) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	close(blocked.release)
+	close(samples)
+	wait()
+	finish(nil)
+	assert.Len(t, blocked.GetBufferedSamples(), 1)
+}
+
+type failingOutput struct {
+	limitedOutput
+}
+
+func (o *failingOutput) Description() string { return "failing" }
+func (o *failingOutput) Stop() error         { return errors.New("flush failed") }
+
+func TestManagerStopErrors(t *testing.T) {
+	t.Parallel()
+
+	manager := NewManager([]Output{&limitedOutput{}, &failingOutput{}}, testutils.NewLogger(t), func(error) {})
+	samples := make(chan metrics.SampleContainer)
+	wait, finish, err := manager.StartWithStopErrors(samples)
+	require.NoError(t, err)
+	close(samples)
+	wait()
+	assert.EqualError(t, finish(nil), "failing: flush failed")
+}
@@ -102,3 +102,20 @@ type WithBuiltinMetrics interface {
 	Output
 	SetBuiltinMetrics(builtinMetrics *metrics.BuiltinMetrics)
 }
+
+// WithRunMetadata is an output that can receive the metadata of the test run.
+// The script can add to it while the test is running, so the output should
+// check it again whenever it flushes.
+type WithRunMetadata interface {
+	Output
+	SetRunMetadata(runMetadata *lib.RunMetadata)
+}
+
+// WithBufferLimit is an output whose buffer of metric samples can be limited,
+// like the outputs that use [SampleBuffer]. It reports how many samples it
+// has dropped because of the limit.
+type WithBufferLimit interface {
+	Output
+	SetBufferLimit(limit int, policy BufferPolicy)
+	DroppedSamples() uint64
+}
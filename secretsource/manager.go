@@ -75,6 +75,21 @@ func (sm *Manager) Get(sourceName, key string) (string, error) {
 	return value, err
 }
 
+// Redact replaces all the secrets returned so far in the given string.
+// It is used for outputs other than logs, like the end-of-test summary.
+func (sm *Manager) Redact(s string) string {
+	if sm == nil {
+		return s
+	}
+	sm.hook.mx.RLock()
+	replacer := sm.hook.replacer
+	sm.hook.mx.RUnlock()
+	if replacer == nil {
+		return s
+	}
+	return replacer.Replace(s)
+}
+
 // UnknownSourceError is returned when a unknown source is requested
 type UnknownSourceError string
 